@@ -49,7 +49,7 @@ Page({
   async loadBanners() {
     this.setData({ loading: true });
     try {
-      const res = await api.admin.listBanners();
+      const res = await api.collectPages(api.admin.listBanners);
       if (res.code === 200) {
         const banners = (res.data || []).map((item) => ({
           ...item,
@@ -23,7 +23,7 @@ Page({
   async loadCategories() {
     this.setData({ loading: true });
     try {
-      const res = await api.admin.listCategories();
+      const res = await api.collectPages(api.admin.listCategories);
       if (res.code === 200) {
         const categories = (res.data || []).map((item) => {
           const hasCustomCover = !!item.cover_url;
@@ -71,7 +71,7 @@ Page({
 
   async loadCategories() {
     try {
-      const res = await api.content.listCategories();
+      const res = await api.collectPages(api.content.listCategories);
       if (res.code === 200) {
         const categories = res.data || [];
         let categoryIndex = this.data.categoryIndex;
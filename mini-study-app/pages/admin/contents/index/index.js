@@ -46,7 +46,7 @@ Page({
         params.status = statusOption.value;
       }
 
-      const res = await api.admin.listContents(params);
+      const res = await api.collectPages(api.admin.listContents, params);
       if (res.code === 200) {
         const list = (res.data || []).map((item) => ({
           id: item.id,
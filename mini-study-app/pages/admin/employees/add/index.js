@@ -32,7 +32,7 @@ Page({
   async loadManagers() {
     try {
       wx.showLoading({ title: "加载中..." });
-      const res = await api.user.getManagers();
+      const res = await api.collectPages(api.user.getManagers);
       if (res.code === 200) {
         // 为每个店长添加 selected 属性
         const managers = (res.data || []).map(manager => ({
@@ -38,7 +38,7 @@ Page({
       this.setData({ loading: true });
       const [userRes, managersRes] = await Promise.all([
         api.admin.getUser(this.data.userId),
-        api.user.getManagers()
+        api.collectPages(api.user.getManagers)
       ]);
 
       if (userRes.code !== 200) {
@@ -30,7 +30,7 @@ Page({
 
   async loadData() {
     try {
-      const res = await api.admin.listUsers();
+      const res = await api.collectPages(api.admin.listUsers);
       if (res.code !== 200) {
         wx.showToast({ title: res.message || "加载数据失败", icon: "none" });
         return;
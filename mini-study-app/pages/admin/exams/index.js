@@ -20,7 +20,7 @@ Page({
   async loadExams() {
     this.setData({ loading: true });
     try {
-      const res = await api.admin.listExams();
+      const res = await api.collectPages(api.admin.listExams);
       if (res.code === 200) {
         const exams = (res.data || []).map((item) => ({
           id: item.id,
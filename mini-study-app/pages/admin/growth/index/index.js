@@ -18,7 +18,17 @@ Page({
       { label: "已拒绝", value: "rejected" }
     ],
     statusFilterIndex: 0,
-    loading: false
+    loading: false,
+    page: 1,
+    hasMore: false,
+    rejectReasons: [],
+    rejectForm: {
+      visible: false,
+      postId: 0,
+      reasonIndex: 0,
+      reason: "",
+      submitting: false
+    }
   },
 
   onLoad() {
@@ -36,19 +46,32 @@ Page({
     this.loadPosts();
   },
 
-  async loadPosts() {
+  handleLoadMore() {
+    if (this.data.hasMore) {
+      this.loadPosts(true);
+    }
+  },
+
+  // 按页加载：append 为 true 时加载下一页并追加到列表
+  async loadPosts(append = false) {
     if (this.data.loading) return;
     this.setData({ loading: true });
     try {
       const statusOpt = this.data.statusFilterOptions[this.data.statusFilterIndex];
-      const params = { keyword: this.data.keyword };
+      const page = append ? this.data.page + 1 : 1;
+      const params = { keyword: this.data.keyword, page };
       if (statusOpt && statusOpt.value) {
         params.status = statusOpt.value;
       }
       const res = await api.admin.listGrowth(params);
       if (res.code === 200) {
-        const posts = (res.data || []).map((item) => this.transformPost(item));
-        this.setData({ posts });
+        const data = res.data || {};
+        const items = (data.items || []).map((item) => this.transformPost(item));
+        this.setData({
+          posts: append ? this.data.posts.concat(items) : items,
+          page,
+          hasMore: !!(data.pagination && data.pagination.has_more)
+        });
       } else {
         wx.showToast({ title: res.message || "加载失败", icon: "none" });
       }
@@ -79,10 +102,20 @@ Page({
       publisherName,
       publisherRole: item.publisher_role || "",
       avatarText,
-      createdAtText
+      createdAtText,
+      rejectReasonText: this.rejectReasonText(item)
     };
   },
 
+  // 拒绝原因展示为“原因名称：说明”，选择“其他”时只展示说明
+  rejectReasonText(item) {
+    if (item.status !== "rejected") return "";
+    const label = item.reject_reason_code === "other" ? "" : item.reject_reason_label || "";
+    const reason = item.reject_reason || "";
+    if (label && reason) return `${label}：${reason}`;
+    return label || reason;
+  },
+
   formatDateTime(isoString) {
     if (!isoString) return "";
     const d = new Date(isoString);
@@ -127,20 +160,69 @@ Page({
     }
   },
 
+  // 拒绝需要选择原因，选择“其他”时还需填写说明，先打开原因表单
   async handleReject(e) {
     const id = Number(e.currentTarget.dataset.id);
     if (!id) return;
+    if (!this.data.rejectReasons.length) {
+      try {
+        const res = await api.admin.growthRejectReasons();
+        if (res.code !== 200) {
+          wx.showToast({ title: res.message || "加载拒绝原因失败", icon: "none" });
+          return;
+        }
+        this.setData({ rejectReasons: res.data || [] });
+      } catch (err) {
+        console.error("load reject reasons error", err);
+        wx.showToast({ title: "加载拒绝原因失败", icon: "none" });
+        return;
+      }
+    }
+    this.setData({
+      rejectForm: { visible: true, postId: id, reasonIndex: 0, reason: "", submitting: false }
+    });
+  },
+
+  handleRejectReasonChange(e) {
+    this.setData({ "rejectForm.reasonIndex": Number(e.detail.value) });
+  },
+
+  handleRejectInput(e) {
+    this.setData({ "rejectForm.reason": e.detail.value || "" });
+  },
+
+  closeRejectForm() {
+    this.setData({ "rejectForm.visible": false });
+  },
+
+  async handleRejectSubmit() {
+    const { postId, reasonIndex, reason, submitting } = this.data.rejectForm;
+    if (submitting) return;
+    const option = this.data.rejectReasons[reasonIndex];
+    const text = reason.trim();
+    if (!option) {
+      wx.showToast({ title: "请选择拒绝原因", icon: "none" });
+      return;
+    }
+    if (option.code === "other" && !text) {
+      wx.showToast({ title: "选择其他原因时请填写说明", icon: "none" });
+      return;
+    }
+    this.setData({ "rejectForm.submitting": true });
     try {
-      const res = await api.admin.rejectGrowth(id);
+      const res = await api.admin.rejectGrowth(postId, { reason_code: option.code, reason: text });
       if (res.code === 200) {
         wx.showToast({ title: "已拒绝", icon: "success" });
+        this.setData({ "rejectForm.visible": false });
         this.loadPosts();
       } else {
         wx.showToast({ title: res.message || "操作失败", icon: "none" });
       }
     } catch (err) {
       console.error("reject growth error", err);
-      wx.showToast({ title: "操作失败", icon: "none" });
+      wx.showToast({ title: err.message || "操作失败", icon: "none" });
+    } finally {
+      this.setData({ "rejectForm.submitting": false });
     }
   },
 
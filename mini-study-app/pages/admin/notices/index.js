@@ -54,8 +54,7 @@ Page({
   async loadNotices() {
     this.setData({ loading: true });
     try {
-      // 所有角色共用公告列表接口
-      const res = await api.notice.list();
+      const res = await api.collectPages(api.admin.listNotices);
       if (res.code === 200) {
         const notices = (res.data || [])
           .map((item) => ({
@@ -19,7 +19,7 @@ Page({
 
   async loadExams() {
     try {
-      const res = await api.exam.listAvailable();
+      const res = await api.collectPages(api.exam.listAvailable);
       if (res.code === 200) {
         const exams = (res.data || []).map((item) => ({
           id: item.id,
@@ -51,7 +51,7 @@ Page({
 
   async loadResults() {
     try {
-      const res = await api.exam.listMyResults();
+      const res = await api.collectPages(api.exam.listMyResults);
       if (res.code === 200) {
         const results = (res.data || []).map((item) => ({
           id: item.attempt_id,
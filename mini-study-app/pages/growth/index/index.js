@@ -6,7 +6,9 @@ Page({
     user: {},
     posts: [],
     keyword: "",
-    loading: false
+    loading: false,
+    nextCursor: "",
+    hasMore: false
   },
 
   onShow() {
@@ -19,13 +21,28 @@ Page({
     this.loadPosts();
   },
 
-  async loadPosts() {
+  // 动态流按游标分页：append 为 true 时从上一页的 next_cursor 继续加载
+  async loadPosts(append = false) {
+    if (append && (this.data.loading || !this.data.hasMore)) return;
     this.setData({ loading: true });
     try {
-      const res = await api.growth.list({ keyword: this.data.keyword });
+      const params = { keyword: this.data.keyword };
+      if (append) {
+        params.cursor = this.data.nextCursor;
+      }
+      const res = await api.growth.list(params);
       if (res.code === 200) {
-        const posts = (res.data || []).map((item) => this.transformPost(item));
-        this.setData({ posts });
+        const data = res.data || {};
+        const pagination = data.pagination || {};
+        const items = (data.items || []).map((item) => this.transformPost(item));
+        const posts = append
+          ? this.data.posts.concat(items)
+          : (data.pinned || []).map((item) => this.transformPost(item)).concat(items);
+        this.setData({
+          posts,
+          nextCursor: pagination.next_cursor || "",
+          hasMore: !!pagination.has_more
+        });
       } else {
         wx.showToast({ title: res.message || "加载失败", icon: "none" });
       }
@@ -73,6 +90,10 @@ Page({
     this.loadPosts();
   },
 
+  handleLoadMore() {
+    this.loadPosts(true);
+  },
+
   handleSearchInput(e) {
     this.setData({ keyword: e.detail.value || "" });
   },
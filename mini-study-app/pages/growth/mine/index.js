@@ -19,6 +19,8 @@ Page({
     ],
     statusFilterIndex: 0,
     loading: false,
+    nextCursor: "",
+    hasMore: false,
     form: {
       content: "",
       image_paths: []
@@ -41,7 +43,13 @@ Page({
     this.loadMyPosts();
   },
 
-  async loadMyPosts() {
+  handleLoadMore() {
+    this.loadMyPosts(true);
+  },
+
+  // 动态流按游标分页：append 为 true 时从上一页的 next_cursor 继续加载
+  async loadMyPosts(append = false) {
+    if (append && (this.data.loading || !this.data.hasMore)) return;
     this.setData({ loading: true });
     try {
       const statusOpt = this.data.statusFilterOptions[this.data.statusFilterIndex];
@@ -49,10 +57,19 @@ Page({
       if (statusOpt && statusOpt.value) {
         params.status = statusOpt.value;
       }
+      if (append) {
+        params.cursor = this.data.nextCursor;
+      }
       const res = await api.growth.listMine(params);
       if (res.code === 200) {
-        const posts = (res.data || []).map((item) => this.transformPost(item));
-        this.setData({ posts });
+        const data = res.data || {};
+        const pagination = data.pagination || {};
+        const items = (data.items || []).map((item) => this.transformPost(item));
+        this.setData({
+          posts: append ? this.data.posts.concat(items) : items,
+          nextCursor: pagination.next_cursor || "",
+          hasMore: !!pagination.has_more
+        });
       } else {
         wx.showToast({ title: res.message || "加载失败", icon: "none" });
       }
@@ -85,10 +102,20 @@ Page({
       publisherRole: item.publisher_role || "",
       avatarText,
       createdAt: item.created_at,
-      createdAtText: this.formatDateTime(item.created_at)
+      createdAtText: this.formatDateTime(item.created_at),
+      rejectReasonText: this.rejectReasonText(item)
     };
   },
 
+  // 拒绝原因展示为“原因名称：说明”，选择“其他”时只展示说明
+  rejectReasonText(item) {
+    if (item.status !== "rejected") return "";
+    const label = item.reject_reason_code === "other" ? "" : item.reject_reason_label || "";
+    const reason = item.reject_reason || "";
+    if (label && reason) return `${label}：${reason}`;
+    return label || reason;
+  },
+
   formatDateTime(isoString) {
     if (!isoString) return "";
     const d = new Date(isoString);
@@ -31,7 +31,7 @@ Page({
   async loadBanners() {
     try {
       console.log("[Banner] 开始加载轮播图数据");
-      const res = await api.banner.listVisible();
+      const res = await api.collectPages(api.banner.listVisible);
       console.log("[Banner] API响应:", res);
       if (res.code === 200) {
         const banners = (res.data || []).map((item) => ({
@@ -57,7 +57,7 @@ Page({
 
   async loadCategories() {
     try {
-      const res = await api.content.listCategories();
+      const res = await api.collectPages(api.content.listCategories);
       if (res.code === 200) {
         const categories = (res.data || []).map((item) => ({
           id: item.id,
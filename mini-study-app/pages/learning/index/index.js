@@ -21,7 +21,7 @@ Page({
   async loadCategories() {
     this.setData({ loading: true });
     try {
-      const res = await api.content.listCategories();
+      const res = await api.collectPages(api.content.listCategories);
       if (res.code === 200) {
         const categories = (res.data || []).map((item) => ({
           id: item.id,
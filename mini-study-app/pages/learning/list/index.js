@@ -34,7 +34,7 @@ Page({
     if (!this.data.categoryId) return;
     this.setData({ loading: true });
     try {
-      const res = await api.content.listPublished({
+      const res = await api.collectPages(api.content.listPublished, {
         category_id: this.data.categoryId
       });
       if (res.code === 200) {
@@ -55,7 +55,7 @@ Page({
 
         // 加载当前用户的学习进度列表，并合并到课程数据中
         try {
-          const progressRes = await api.learning.listProgress();
+          const progressRes = await api.collectPages(api.learning.listProgress);
           if (progressRes.code === 200 && Array.isArray(progressRes.data)) {
             const progressMap = {};
             (progressRes.data || []).forEach((p) => {
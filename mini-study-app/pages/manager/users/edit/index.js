@@ -36,7 +36,7 @@ Page({
     try {
       const [userRes, managersRes] = await Promise.all([
         api.admin.getUser(this.data.userId),
-        api.user.getManagers()
+        api.collectPages(api.user.getManagers)
       ]);
 
       if (userRes.code !== 200 || !userRes.data) {
@@ -21,7 +21,7 @@ Page({
 
   async loadUsers() {
     try {
-      const res = await api.admin.listUsers();
+      const res = await api.collectPages(api.admin.listUsers);
       if (res.code === 200) {
         this.setData({ users: res.data || [] });
       } else {
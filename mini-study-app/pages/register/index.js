@@ -24,7 +24,7 @@ Page({
 
   async loadManagers() {
     try {
-      const res = await api.user.getManagers();
+      const res = await api.collectPages(api.user.getManagers);
       if (res.code === 200) {
         const managers = res.data || [];
         this.setData({
@@ -122,9 +122,39 @@ function handleUnauthorized() {
   });
 }
 
+// 列表接口统一返回 { items, pagination }；Mock 数据按同样结构包装成一页
+function mockPage(list = []) {
+  return {
+    code: 200,
+    message: 'success',
+    data: {
+      items: list,
+      pagination: { page: 1, page_size: list.length, total: list.length, has_more: false }
+    }
+  };
+}
+
+// 依次请求页码分页接口的所有页，返回 { code, message, data: 全部 items }
+// 仅用于需要展示完整列表的页面；每页取接口允许的最大数量 100
+async function collectPages(fetchPage, params = {}) {
+  const items = [];
+  for (let page = 1; ; page += 1) {
+    const res = await fetchPage({ ...params, page, page_size: 100 });
+    if (res.code !== 200) {
+      return res;
+    }
+    const data = res.data || {};
+    items.push(...(data.items || []));
+    if (!data.pagination || !data.pagination.has_more) {
+      return { code: res.code, message: res.message, data: items };
+    }
+  }
+}
+
 module.exports = {
   $request: request,
   buildFileUrl,
+  collectPages,
   file: {
     upload(filePath) {
       const token = getToken();
@@ -264,13 +294,14 @@ module.exports = {
       });
     },
     // 获取店长列表
-    getManagers() {
+    getManagers(params = {}) {
       if (USE_MOCK) {
-        return mockService.fetchManagers();
+        return mockService.fetchManagers().then((res) => mockPage(res.data || []));
       }
       return request({
         url: '/users/managers',
-        method: 'GET'
+        method: 'GET',
+        data: params
       });
     },
     // 员工注册
@@ -303,15 +334,16 @@ module.exports = {
   
   // 内容相关
   content: {
-    listCategories() {
+    listCategories(params = {}) {
       if (USE_MOCK) {
         const user = wx.getStorageSync('user');
         const role = user ? user.role : 'employee';
-        return mockService.fetchCourseCategories(role);
+        return mockService.fetchCourseCategories(role).then((res) => mockPage(res.data || []));
       }
       return request({
         url: '/contents/categories',
-        method: 'GET'
+        method: 'GET',
+        data: params
       });
     },
     listPublished(params = {}) {
@@ -324,7 +356,7 @@ module.exports = {
         if (params.type) {
           list = list.filter(c => c.type === params.type);
         }
-        return Promise.resolve({ code: 200, message: 'success', data: list });
+        return Promise.resolve(mockPage(list));
       }
       return request({
         url: '/contents',
@@ -345,13 +377,14 @@ module.exports = {
 
   // 学习相关
   learning: {
-    listProgress() {
+    listProgress(params = {}) {
       if (USE_MOCK) {
-        return mockService.fetchProgress();
+        return mockService.fetchProgress().then((res) => mockPage(res.data || []));
       }
       return request({
         url: '/learning',
-        method: 'GET'
+        method: 'GET',
+        data: params
       });
     },
     getProgress(contentId) {
@@ -433,47 +466,44 @@ module.exports = {
 
   // 考试相关
   exam: {
-    listAvailable() {
+    listAvailable(params = {}) {
       if (USE_MOCK) {
         // Mock 模式下返回示例考试列表
-        return Promise.resolve({ 
-          code: 200, 
-          message: 'success', 
-          data: [
-            {
-              id: 1,
-              title: '销售技巧考核',
-              description: '测试你对销售技巧的掌握程度',
-              total_score: 100,
-              pass_score: 60,
-              question_count: 10,
-              time_limit_minutes: 60,
-              status: 'published',
-              attempt_status: 'not_started',
-              last_score: null,
-              last_passed: false,
-              last_submitted_at: null
-            },
-            {
-              id: 2,
-              title: '产品知识测试',
-              description: '检验产品相关知识的掌握情况',
-              total_score: 100,
-              pass_score: 70,
-              question_count: 15,
-              time_limit_minutes: 45,
-              status: 'published',
-              attempt_status: 'attempted',
-              last_score: 65,
-              last_passed: false,
-              last_submitted_at: '2024-01-15T10:30:00Z'
-            }
-          ]
-        });
+        return Promise.resolve(mockPage([
+          {
+            id: 1,
+            title: '销售技巧考核',
+            description: '测试你对销售技巧的掌握程度',
+            total_score: 100,
+            pass_score: 60,
+            question_count: 10,
+            time_limit_minutes: 60,
+            status: 'published',
+            attempt_status: 'not_started',
+            last_score: null,
+            last_passed: false,
+            last_submitted_at: null
+          },
+          {
+            id: 2,
+            title: '产品知识测试',
+            description: '检验产品相关知识的掌握情况',
+            total_score: 100,
+            pass_score: 70,
+            question_count: 15,
+            time_limit_minutes: 45,
+            status: 'published',
+            attempt_status: 'attempted',
+            last_score: 65,
+            last_passed: false,
+            last_submitted_at: '2024-01-15T10:30:00Z'
+          }
+        ]));
       }
       return request({
         url: '/exams',
-        method: 'GET'
+        method: 'GET',
+        data: params
       });
     },
     getDetail(id) {
@@ -597,29 +627,26 @@ module.exports = {
         data
       });
     },
-    listMyResults() {
+    listMyResults(params = {}) {
       if (USE_MOCK) {
         // Mock 模式下返回考试结果列表
-        return Promise.resolve({ 
-          code: 200, 
-          message: 'success', 
-          data: [
-            {
-              attempt_id: 1,
-              exam_id: 2,
-              exam_title: '产品知识测试',
-              score: 65,
-              total_score: 100,
-              pass_score: 70,
-              pass: false,
-              submitted_at: '2024-01-15T10:30:00Z'
-            }
-          ]
-        });
+        return Promise.resolve(mockPage([
+          {
+            attempt_id: 1,
+            exam_id: 2,
+            exam_title: '产品知识测试',
+            score: 65,
+            total_score: 100,
+            pass_score: 70,
+            pass: false,
+            submitted_at: '2024-01-15T10:30:00Z'
+          }
+        ]));
       }
       return request({
         url: '/exams/my/results',
-        method: 'GET'
+        method: 'GET',
+        data: params
       });
     },
     managerOverview() {
@@ -679,7 +706,7 @@ module.exports = {
     // 成长圈公开列表（已审核通过，可搜索）
     list(params = {}) {
       if (USE_MOCK) {
-        return Promise.resolve({ code: 200, message: 'success', data: [] });
+        return Promise.resolve(mockPage([]));
       }
       return request({
         url: '/growth',
@@ -691,7 +718,7 @@ module.exports = {
     // 我的成长圈列表
     listMine(params = {}) {
       if (USE_MOCK) {
-        return Promise.resolve({ code: 200, message: 'success', data: [] });
+        return Promise.resolve(mockPage([]));
       }
       return request({
         url: '/growth/mine',
@@ -726,13 +753,14 @@ module.exports = {
 
   // 轮播图
   banner: {
-    listVisible() {
+    listVisible(params = {}) {
       if (USE_MOCK) {
-        return mockService.fetchBanners();
+        return mockService.fetchBanners().then((res) => mockPage(res.data || []));
       }
       return request({
         url: '/banners',
-        method: 'GET'
+        method: 'GET',
+        data: params
       });
     }
   },
@@ -774,7 +802,7 @@ module.exports = {
     // 查询用户列表
     listUsers(params = {}) {
       if (USE_MOCK) {
-        return mockService.fetchAllUsers();
+        return mockService.fetchAllUsers().then((res) => mockPage(res.data || []));
       }
       return request({
         url: '/admin/users',
@@ -894,7 +922,7 @@ module.exports = {
     // 成长圈管理
     listGrowth(params = {}) {
       if (USE_MOCK) {
-        return Promise.resolve({ code: 200, message: 'success', data: [] });
+        return Promise.resolve(mockPage([]));
       }
       return request({
         url: '/admin/growth',
@@ -911,13 +939,33 @@ module.exports = {
         method: 'POST'
       });
     },
-    rejectGrowth(id) {
+    // 拒绝动态：data 为 { reason_code, reason }，二者至少填写一项，选择 other 时必须填写 reason
+    rejectGrowth(id, data) {
       if (USE_MOCK) {
         return Promise.resolve({ code: 200, message: 'success', data: null });
       }
       return request({
         url: `/admin/growth/${id}/reject`,
-        method: 'POST'
+        method: 'POST',
+        data
+      });
+    },
+    // 预设的拒绝原因
+    growthRejectReasons() {
+      if (USE_MOCK) {
+        return Promise.resolve({
+          code: 200,
+          message: 'success',
+          data: [
+            { code: 'irrelevant', label: '与工作无关' },
+            { code: 'low_quality', label: '内容质量不足' },
+            { code: 'other', label: '其他' }
+          ]
+        });
+      }
+      return request({
+        url: '/admin/growth/reject-reasons',
+        method: 'GET'
       });
     },
     // 轮播管理
@@ -925,7 +973,7 @@ module.exports = {
       if (USE_MOCK) {
         // Mock 模式下返回所有轮播图
         const mock = require('../mock/mockData');
-        return Promise.resolve({ code: 200, message: 'success', data: mock.banners });
+        return Promise.resolve(mockPage(mock.banners));
       }
       return request({
         url: '/admin/banners',
@@ -985,7 +1033,7 @@ module.exports = {
     // 公告管理
     listNotices(params = {}) {
       if (USE_MOCK) {
-        return mockService.fetchNotices();
+        return mockService.fetchNotices().then((res) => mockPage(res.data || []));
       }
       return request({
         url: '/admin/notices',
@@ -1022,7 +1070,7 @@ module.exports = {
         method: 'GET'
       });
     },
-    listCategories() {
+    listCategories(params = {}) {
       if (USE_MOCK) {
         const mock = require('../mock/mockData');
         const list = (mock.courseCategories || []).map((item) => ({
@@ -1033,11 +1081,12 @@ module.exports = {
           cover_url: item.cover_url || '',
           count: item.count || 0
         }));
-        return Promise.resolve({ code: 200, message: 'success', data: list });
+        return Promise.resolve(mockPage(list));
       }
       return request({
         url: '/admin/categories',
-        method: 'GET'
+        method: 'GET',
+        data: params
       });
     },
     updateCategory(id, data) {
@@ -1073,7 +1122,7 @@ module.exports = {
         if (params.status) {
           contents = contents.filter(c => (c.status || 'published') === params.status);
         }
-        return Promise.resolve({ code: 200, message: 'success', data: contents });
+        return Promise.resolve(mockPage(contents));
       }
       return request({
         url: '/admin/contents',
@@ -1138,12 +1187,8 @@ module.exports = {
     // 考试管理
     listExams(params = {}) {
       if (USE_MOCK) {
-        // Mock 模式下返回空列表或示例数据
-        return Promise.resolve({ 
-          code: 200, 
-          message: 'success', 
-          data: [] 
-        });
+        // Mock 模式下返回空列表
+        return Promise.resolve(mockPage([]));
       }
       return request({
         url: '/admin/exams',
@@ -0,0 +1,73 @@
+// Command rollup backfills the reporting rollups behind the learning overviews, e.g. after
+// the first deployment or after fixing data directly in the database.
+//
+//	go run ./cmd/rollup -from 2025-01-01 -to 2025-12-31
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/bootstrap"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+func main() {
+	today := time.Now().Format("2006-01-02")
+	fromFlag := flag.String("from", "", "first day to roll up (YYYY-MM-DD), required")
+	toFlag := flag.String("to", today, "last day to roll up (YYYY-MM-DD)")
+	flag.Parse()
+
+	from, err := time.ParseInLocation("2006-01-02", *fromFlag, time.Local)
+	if err != nil {
+		log.Fatalf("invalid -from %q: %v", *fromFlag, err)
+	}
+	to, err := time.ParseInLocation("2006-01-02", *toFlag, time.Local)
+	if err != nil {
+		log.Fatalf("invalid -to %q: %v", *toFlag, err)
+	}
+
+	cfg, err := bootstrap.LoadConfig("./configs")
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+
+	logger, err := bootstrap.InitLogger(cfg)
+	if err != nil {
+		log.Fatalf("init logger: %v", err)
+	}
+	defer logger.Sync() //nolint:errcheck
+
+	db, err := bootstrap.InitDatabase(cfg, logger)
+	if err != nil {
+		logger.Fatal("init database", zap.Error(err))
+	}
+
+	rollupService := service.NewReportRollupService(
+		repository.NewReportRollupRepository(db),
+		repository.NewLearningRecordRepository(db),
+		repository.NewAnalyticsRepository(db),
+		repository.NewSchedulerLeaseRepository(db),
+		service.RollupOptions{
+			NightlyHour: cfg.Rollup.NightlyHour,
+			InitialDays: cfg.Rollup.InitialDays,
+			LeaseTTL:    cfg.Rollup.LeaseTTL,
+		},
+	)
+
+	start := time.Now()
+	days, err := rollupService.Backfill(from, to)
+	if err != nil {
+		logger.Fatal("backfill report rollups", zap.Int("days", days), zap.Error(err))
+	}
+	logger.Info("report rollups backfilled",
+		zap.String("from", *fromFlag),
+		zap.String("to", *toFlag),
+		zap.Int("days", days),
+		zap.Duration("took", time.Since(start)),
+	)
+}
@@ -27,6 +27,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -34,6 +35,7 @@ import (
 
 	"github.com/javapub/mini-study/mini-study-backend/internal/bootstrap"
 	"github.com/javapub/mini-study/mini-study-backend/internal/handler"
+	"github.com/javapub/mini-study/mini-study-backend/internal/moderation"
 	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
 	"github.com/javapub/mini-study/mini-study-backend/internal/service"
 )
@@ -55,11 +57,24 @@ func main() {
 		logger.Fatal("init database", zap.Error(err))
 	}
 
+	store, signer, err := bootstrap.InitStorage(cfg, logger)
+	if err != nil {
+		logger.Fatal("init storage", zap.Error(err))
+	}
+
+	searchIndex, err := bootstrap.InitSearchIndex(cfg)
+	if err != nil {
+		logger.Fatal("init search index", zap.Error(err))
+	}
+
+	mail := bootstrap.InitMailer(cfg)
+
 	validate := validator.New()
 
 	auditRepo := repository.NewAuditRepository(db)
 	userRepo := repository.NewUserRepository(db)
 	relationRepo := repository.NewManagerEmployeeRepository(db)
+	transferRepo := repository.NewEmployeeTransferRepository(db)
 	contentCategoryRepo := repository.NewContentCategoryRepository(db)
 	contentRepo := repository.NewContentRepository(db)
 	learningRecordRepo := repository.NewLearningRecordRepository(db)
@@ -69,32 +84,138 @@ func main() {
 	noticeRepo := repository.NewNoticeRepository(db)
 	pointRepo := repository.NewPointRepository(db)
 	growthPostRepo := repository.NewGrowthPostRepository(db)
-
-	auditService := service.NewAuditService(auditRepo)
+	uploadSessionRepo := repository.NewUploadSessionRepository(db)
+	mediaJobRepo := repository.NewMediaJobRepository(db)
+	contentPageRepo := repository.NewContentPageRepository(db)
+	pageViewRepo := repository.NewLearningPageViewRepository(db)
+	schedulerLeaseRepo := repository.NewSchedulerLeaseRepository(db)
+	reviewEventRepo := repository.NewReviewEventRepository(db)
+	contentRevisionRepo := repository.NewContentRevisionRepository(db)
+	checkpointRepo := repository.NewContentCheckpointRepository(db)
+	checkpointAnswerRepo := repository.NewCheckpointAnswerRepository(db)
+	contentRatingRepo := repository.NewContentRatingRepository(db)
+	contentCommentRepo := repository.NewContentCommentRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	sensitiveWordRepo := repository.NewSensitiveWordRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+	reportRollupRepo := repository.NewReportRollupRepository(db)
+	reportScheduleRepo := repository.NewReportScheduleRepository(db)
+	certificateRepo := repository.NewCertificateRepository(db)
+	certificationRepo := repository.NewCertificationRepository(db)
+
+	auditService := service.NewAuditService(auditRepo, userRepo)
+	fileService := service.NewFileService(store, signer, auditService, cfg.Upload.MaxSizeMB, cfg.Storage.SignedURLTTL)
+	uploadSessionService := service.NewUploadSessionService(uploadSessionRepo, fileService, cfg.Upload.ChunkDir, cfg.Upload.ChunkSizeMB, cfg.Upload.MaxSessionSizeMB, cfg.Upload.SessionTTL)
 	tokenService := service.NewTokenService(cfg.JWT.Secret, cfg.JWT.Issuer, cfg.JWT.TTL, cfg.JWT.RefreshTTL, userRepo)
-	pointService := service.NewPointService(pointRepo, userRepo)
-	userService := service.NewUserService(userRepo, relationRepo, auditService, pointService)
-	contentService := service.NewContentService(contentCategoryRepo, contentRepo, userRepo)
-	learningService := service.NewLearningService(learningRecordRepo, contentRepo, userRepo, pointService)
-	examService := service.NewExamService(examRepo, examAttemptRepo, userRepo, relationRepo, learningRecordRepo, contentRepo)
+	pointService := service.NewPointService(pointRepo, userRepo, auditService)
+	userService := service.NewUserService(userRepo, relationRepo, transferRepo, auditService, pointService)
+	reviewService := service.NewReviewService(reviewEventRepo, contentRepo, examRepo, userRepo, auditService)
+	searchService := service.NewSearchService(searchIndex, contentRepo, contentCategoryRepo, contentPageRepo, examRepo, growthPostRepo, userRepo)
+	mediaService := service.NewMediaService(mediaJobRepo, contentRepo, contentPageRepo, userRepo, fileService, auditService, searchService, service.MediaOptions{
+		Enabled:        cfg.Media.Enabled,
+		FFmpegPath:     cfg.Media.FFmpegPath,
+		FFprobePath:    cfg.Media.FFprobePath,
+		WorkDir:        cfg.Media.WorkDir,
+		SegmentSeconds: cfg.Media.SegmentSeconds,
+		MaxAttempts:    cfg.Media.MaxAttempts,
+		JobTimeout:     cfg.Media.JobTimeout,
+		PlaybackTTL:    cfg.Media.PlaybackTTL,
+		PlaybackURL:    "/api/v1/media/hls",
+		SofficePath:    cfg.Media.SofficePath,
+		PdftoppmPath:   cfg.Media.PdftoppmPath,
+		PdftotextPath:  cfg.Media.PdftotextPath,
+		PageWidth:      cfg.Media.PageWidth,
+	})
+	contentService := service.NewContentService(contentCategoryRepo, contentRepo, userRepo, auditService, mediaService, searchService, reviewService, contentRevisionRepo)
+	learningService := service.NewLearningService(learningRecordRepo, pageViewRepo, checkpointRepo, checkpointAnswerRepo, contentRepo, userRepo, pointService, service.LearningOptions{
+		DocCompletionPercent: cfg.Learning.DocCompletionPercent,
+		MinPageSeconds:       int64(cfg.Learning.MinPageSeconds),
+	})
+	certificateService := service.NewCertificateService(certificateRepo, examAttemptRepo, examRepo, userRepo, relationRepo, store, auditService, service.CertificateOptions{
+		SerialPrefix: cfg.Certificate.SerialPrefix,
+		VerifyURL:    cfg.Certificate.VerifyURL,
+	})
+	notificationService := service.NewNotificationService(notificationRepo)
+	certificationService := service.NewCertificationService(certificationRepo, examAttemptRepo, examRepo, userRepo, relationRepo, notificationService, schedulerLeaseRepo, auditService, cfg.Certification.LeaseTTL)
+	examService := service.NewExamService(examRepo, examAttemptRepo, userRepo, relationRepo, learningRecordRepo, contentRepo, reportRollupRepo, auditService, searchService, reviewService, certificateService, certificationService)
 	bannerService := service.NewBannerService(bannerRepo, userRepo, auditService)
 	noticeService := service.NewNoticeService(noticeRepo, userRepo, auditService)
-	growthService := service.NewGrowthService(growthPostRepo, userRepo, auditService)
+	var moderationChecker moderation.Checker
+	if cfg.Moderation.Endpoint != "" {
+		moderationChecker = moderation.NewHTTPChecker(cfg.Moderation.Endpoint, cfg.Moderation.Token, cfg.Moderation.Timeout)
+	}
+	moderationService := service.NewModerationService(sensitiveWordRepo, userRepo, auditService, moderationChecker, service.ModerationOptions{
+		Enabled:         cfg.Moderation.Enabled,
+		RefreshInterval: cfg.Moderation.RefreshInterval,
+		ImageBaseURL:    cfg.Moderation.ImageBaseURL,
+	})
+	growthService := service.NewGrowthService(growthPostRepo, userRepo, relationRepo, auditService, searchService, notificationService, moderationService, service.GrowthOptions{
+		EmployeePostEnabled: cfg.Growth.EmployeePostEnabled,
+		TrendingDays:        cfg.Growth.TrendingDays,
+	})
+	checkpointService := service.NewCheckpointService(checkpointRepo, checkpointAnswerRepo, contentRepo, userRepo, learningService, pointService, auditService)
+	feedbackService := service.NewContentFeedbackService(contentRatingRepo, contentCommentRepo, contentRepo, userRepo, learningService, auditService)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, userRepo, relationRepo)
+	scheduleService := service.NewScheduleService(schedulerLeaseRepo, contentRepo, examRepo, userRepo, auditService, searchService, reviewService, cfg.Scheduler.LeaseTTL)
+	rollupService := service.NewReportRollupService(reportRollupRepo, learningRecordRepo, analyticsRepo, schedulerLeaseRepo, service.RollupOptions{
+		NightlyHour: cfg.Rollup.NightlyHour,
+		InitialDays: cfg.Rollup.InitialDays,
+		LeaseTTL:    cfg.Rollup.LeaseTTL,
+	})
+	reportService := service.NewReportService(reportScheduleRepo, userRepo, examService, pointService, schedulerLeaseRepo, mail, cfg.Report.LeaseTTL)
 
 	userHandler := handler.NewUserHandler(userService, tokenService)
-	contentHandler := handler.NewContentHandler(contentService)
+	contentHandler := handler.NewContentHandler(contentService, fileService, mediaService, feedbackService)
 	learningHandler := handler.NewLearningHandler(learningService)
 	bannerHandler := handler.NewBannerHandler(bannerService)
 	noticeHandler := handler.NewNoticeHandler(noticeService)
 	examHandler := handler.NewExamHandler(examService)
-	uploadHandler := handler.NewUploadHandler(cfg.Upload.Dir, cfg.Upload.MaxSizeMB, auditService)
+	uploadHandler := handler.NewUploadHandler(fileService, uploadSessionService)
 	systemHandler := handler.NewSystemHandler(cfg.App.Name, cfg.App.Version)
 	pointHandler := handler.NewPointHandler(pointService)
 	growthHandler := handler.NewGrowthHandler(growthService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	mediaHandler := handler.NewMediaHandler(mediaService)
+	searchHandler := handler.NewSearchHandler(searchService)
+	scheduleHandler := handler.NewScheduleHandler(scheduleService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	checkpointHandler := handler.NewCheckpointHandler(checkpointService)
+	feedbackHandler := handler.NewContentFeedbackHandler(feedbackService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	moderationHandler := handler.NewModerationHandler(moderationService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+	reportHandler := handler.NewReportHandler(reportService)
+	certificateHandler := handler.NewCertificateHandler(certificateService)
+	certificationHandler := handler.NewCertificationHandler(certificationService)
+
+	background, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	if cfg.Audit.RetentionDays > 0 {
+		retention := time.Duration(cfg.Audit.RetentionDays) * 24 * time.Hour
+		go auditService.RunRetention(background, retention, cfg.Audit.ArchiveInterval, logger)
+	}
+	go uploadSessionService.RunCleanup(background, cfg.Upload.CleanupInterval, logger)
+	go searchService.RunRefresh(background, cfg.Search.RefreshInterval, logger)
+	if cfg.Media.Enabled {
+		go mediaService.RunWorker(background, cfg.Media.PollInterval, logger)
+	}
+	if cfg.Scheduler.Enabled {
+		go scheduleService.RunScheduler(background, cfg.Scheduler.Interval, logger)
+	}
+	if cfg.Rollup.Enabled {
+		go rollupService.RunRollups(background, cfg.Rollup.Interval, logger)
+	}
+	if cfg.Report.DeliveryEnabled && mail != nil {
+		go reportService.RunDeliveries(background, cfg.Report.Interval, logger)
+	}
+	if cfg.Certification.RemindersEnabled {
+		go certificationService.RunReminders(background, cfg.Certification.Interval, logger)
+	}
 
 	engine := gin.New()
 	bootstrap.RegisterMiddlewares(engine, cfg, logger, validate)
-	bootstrap.RegisterRoutes(engine, cfg, userHandler, contentHandler, learningHandler, bannerHandler, noticeHandler, examHandler, uploadHandler, systemHandler, pointHandler, growthHandler)
+	bootstrap.RegisterRoutes(engine, cfg, tokenService.ValidateSession, userHandler, contentHandler, learningHandler, bannerHandler, noticeHandler, examHandler, uploadHandler, systemHandler, pointHandler, growthHandler, auditHandler, mediaHandler, searchHandler, scheduleHandler, reviewHandler, checkpointHandler, feedbackHandler, notificationHandler, moderationHandler, analyticsHandler, reportHandler, certificateHandler, certificationHandler)
 
 	srv := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
@@ -114,6 +235,8 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
+	stopBackground()
+
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.RequestTimeout)
 	defer cancel()
 
@@ -10,12 +10,25 @@ import (
 
 // Config holds the global application configuration loaded via Viper.
 type Config struct {
-	App      AppConfig      `mapstructure:"app"`
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	Upload   UploadConfig   `mapstructure:"upload"`
-	Swagger  SwaggerConfig  `mapstructure:"swagger"`
+	App           AppConfig           `mapstructure:"app"`
+	Server        ServerConfig        `mapstructure:"server"`
+	Database      DatabaseConfig      `mapstructure:"database"`
+	JWT           JWTConfig           `mapstructure:"jwt"`
+	Upload        UploadConfig        `mapstructure:"upload"`
+	Storage       StorageConfig       `mapstructure:"storage"`
+	Swagger       SwaggerConfig       `mapstructure:"swagger"`
+	Audit         AuditConfig         `mapstructure:"audit"`
+	Media         MediaConfig         `mapstructure:"media"`
+	Learning      LearningConfig      `mapstructure:"learning"`
+	Search        SearchConfig        `mapstructure:"search"`
+	Scheduler     SchedulerConfig     `mapstructure:"scheduler"`
+	Moderation    ModerationConfig    `mapstructure:"moderation"`
+	Growth        GrowthConfig        `mapstructure:"growth"`
+	Rollup        RollupConfig        `mapstructure:"rollup"`
+	Mail          MailConfig          `mapstructure:"mail"`
+	Report        ReportConfig        `mapstructure:"report"`
+	Certificate   CertificateConfig   `mapstructure:"certificate"`
+	Certification CertificationConfig `mapstructure:"certification"`
 }
 
 // AppConfig describes metadata for the running service.
@@ -56,9 +69,174 @@ type JWTConfig struct {
 }
 
 // UploadConfig stores file upload limits.
+// MaxSizeMB caps single-request uploads; larger files use chunked upload sessions.
 type UploadConfig struct {
-	MaxSizeMB int    `mapstructure:"max_size_mb"`
-	Dir       string `mapstructure:"dir"`
+	MaxSizeMB          int           `mapstructure:"max_size_mb"`
+	Dir                string        `mapstructure:"dir"`
+	ChunkDir           string        `mapstructure:"chunk_dir"`
+	ChunkSizeMB        int           `mapstructure:"chunk_size_mb"`
+	MaxSessionSizeMB   int           `mapstructure:"max_session_size_mb"`
+	SessionTTLRaw      string        `mapstructure:"session_ttl"`
+	CleanupIntervalRaw string        `mapstructure:"cleanup_interval"`
+	SessionTTL         time.Duration `mapstructure:"-"`
+	CleanupInterval    time.Duration `mapstructure:"-"`
+}
+
+// StorageConfig selects the file storage backend.
+// Public uploads of the local driver are stored in upload.dir; private ones in PrivateDir.
+type StorageConfig struct {
+	Driver          string        `mapstructure:"driver"` // local | s3
+	PrivateDir      string        `mapstructure:"private_dir"`
+	PublicBaseURL   string        `mapstructure:"public_base_url"`
+	SignSecret      string        `mapstructure:"sign_secret"`
+	SignedURLTTLRaw string        `mapstructure:"signed_url_ttl"`
+	SignedURLTTL    time.Duration `mapstructure:"-"`
+	S3              S3Config      `mapstructure:"s3"`
+}
+
+// S3Config describes an S3-compatible object storage endpoint (AWS S3, MinIO, ...).
+type S3Config struct {
+	Endpoint      string `mapstructure:"endpoint"`
+	Region        string `mapstructure:"region"`
+	Bucket        string `mapstructure:"bucket"`
+	AccessKey     string `mapstructure:"access_key"`
+	SecretKey     string `mapstructure:"secret_key"`
+	UsePathStyle  bool   `mapstructure:"use_path_style"`
+	PublicBaseURL string `mapstructure:"public_base_url"`
+}
+
+// AuditConfig controls audit log retention.
+// Entries older than RetentionDays are moved to audit_log_archives; 0 disables archiving.
+type AuditConfig struct {
+	RetentionDays      int           `mapstructure:"retention_days"`
+	ArchiveIntervalRaw string        `mapstructure:"archive_interval"`
+	ArchiveInterval    time.Duration `mapstructure:"-"`
+}
+
+// MediaConfig controls the media pipeline: video probing, thumbnails and HLS packaging via
+// ffmpeg/ffprobe, and document page rendering via LibreOffice and poppler. When disabled,
+// video durations must be entered manually and documents have no page previews.
+type MediaConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	FFmpegPath      string        `mapstructure:"ffmpeg_path"`
+	FFprobePath     string        `mapstructure:"ffprobe_path"`
+	SofficePath     string        `mapstructure:"soffice_path"`
+	PdftoppmPath    string        `mapstructure:"pdftoppm_path"`
+	PdftotextPath   string        `mapstructure:"pdftotext_path"`
+	PageWidth       int           `mapstructure:"page_width"`
+	WorkDir         string        `mapstructure:"work_dir"`
+	SegmentSeconds  int           `mapstructure:"segment_seconds"`
+	MaxAttempts     int           `mapstructure:"max_attempts"`
+	PollIntervalRaw string        `mapstructure:"poll_interval"`
+	JobTimeoutRaw   string        `mapstructure:"job_timeout"`
+	PlaybackTTLRaw  string        `mapstructure:"playback_ttl"`
+	PollInterval    time.Duration `mapstructure:"-"`
+	JobTimeout      time.Duration `mapstructure:"-"`
+	PlaybackTTL     time.Duration `mapstructure:"-"`
+}
+
+// LearningConfig controls learning completion rules.
+// A document with page previews is completed once DocCompletionPercent of its pages were read,
+// a page counting as read after MinPageSeconds in total.
+type LearningConfig struct {
+	DocCompletionPercent int `mapstructure:"doc_completion_percent"`
+	MinPageSeconds       int `mapstructure:"min_page_seconds"`
+}
+
+// SearchConfig selects the full-text search backend. The embedded memory index is rebuilt
+// from the database at startup and every RefreshInterval, so documents written through other
+// instances show up within that time.
+type SearchConfig struct {
+	Driver             string        `mapstructure:"driver"` // memory
+	RefreshIntervalRaw string        `mapstructure:"refresh_interval"`
+	RefreshInterval    time.Duration `mapstructure:"-"`
+}
+
+// SchedulerConfig controls the timed publish/offline loop. Instances compete for a lease
+// row so only one of them applies due schedules at a time.
+type SchedulerConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	IntervalRaw string        `mapstructure:"interval"`
+	LeaseTTLRaw string        `mapstructure:"lease_ttl"`
+	Interval    time.Duration `mapstructure:"-"`
+	LeaseTTL    time.Duration `mapstructure:"-"`
+}
+
+// ModerationConfig controls automated moderation of growth posts. Posts are checked against the
+// sensitive word dictionary and, when Endpoint is set, an external text and image checker; clean
+// posts are approved, blatant ones rejected and the rest left for a human. When disabled, every
+// post waits for a human.
+type ModerationConfig struct {
+	Enabled            bool          `mapstructure:"enabled"`
+	Endpoint           string        `mapstructure:"endpoint"`
+	Token              string        `mapstructure:"token"`
+	ImageBaseURL       string        `mapstructure:"image_base_url"`
+	TimeoutRaw         string        `mapstructure:"timeout"`
+	RefreshIntervalRaw string        `mapstructure:"refresh_interval"`
+	Timeout            time.Duration `mapstructure:"-"`
+	RefreshInterval    time.Duration `mapstructure:"-"`
+}
+
+// GrowthConfig controls who may post to the growth circle and how trending topics are ranked.
+// Managers can always post; employees only when EmployeePostEnabled is set. Trending topics count
+// the posts approved within the last TrendingDays.
+type GrowthConfig struct {
+	EmployeePostEnabled bool `mapstructure:"employee_post_enabled"`
+	TrendingDays        int  `mapstructure:"trending_days"`
+}
+
+// RollupConfig controls the reporting rollups behind the learning overviews. Every Interval the
+// lease holder refreshes what changed since the last run; after NightlyHour it also rebuilds
+// the previous day and every user summary once a day. The first run rolls up InitialDays.
+type RollupConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	IntervalRaw string        `mapstructure:"interval"`
+	LeaseTTLRaw string        `mapstructure:"lease_ttl"`
+	NightlyHour int           `mapstructure:"nightly_hour"`
+	InitialDays int           `mapstructure:"initial_days"`
+	Interval    time.Duration `mapstructure:"-"`
+	LeaseTTL    time.Duration `mapstructure:"-"`
+}
+
+// MailConfig configures the SMTP server used to send email. Mail is disabled when Host is empty.
+// TLS connects over TLS from the start (usually port 465); otherwise STARTTLS is used when the
+// server offers it.
+type MailConfig struct {
+	Host       string        `mapstructure:"host"`
+	Port       int           `mapstructure:"port"`
+	Username   string        `mapstructure:"username"`
+	Password   string        `mapstructure:"password"`
+	From       string        `mapstructure:"from"`
+	TLS        bool          `mapstructure:"tls"`
+	TimeoutRaw string        `mapstructure:"timeout"`
+	Timeout    time.Duration `mapstructure:"-"`
+}
+
+// ReportConfig controls the scheduled report delivery loop. Instances compete for a lease row
+// so only one of them sends due reports at a time.
+type ReportConfig struct {
+	DeliveryEnabled bool          `mapstructure:"delivery_enabled"`
+	IntervalRaw     string        `mapstructure:"interval"`
+	LeaseTTLRaw     string        `mapstructure:"lease_ttl"`
+	Interval        time.Duration `mapstructure:"-"`
+	LeaseTTL        time.Duration `mapstructure:"-"`
+}
+
+// CertificateConfig controls exam certificates. SerialPrefix starts every serial number, and
+// VerifyURL, when set, is printed on certificates with the serial appended as ?serial=.
+type CertificateConfig struct {
+	SerialPrefix string `mapstructure:"serial_prefix"`
+	VerifyURL    string `mapstructure:"verify_url"`
+}
+
+// CertificationConfig controls the recertification reminder loop. Instances compete for a lease
+// row so only one of them sends reminders at a time.
+type CertificationConfig struct {
+	RemindersEnabled bool          `mapstructure:"reminders_enabled"`
+	IntervalRaw      string        `mapstructure:"interval"`
+	LeaseTTLRaw      string        `mapstructure:"lease_ttl"`
+	Interval         time.Duration `mapstructure:"-"`
+	LeaseTTL         time.Duration `mapstructure:"-"`
 }
 
 // SwaggerConfig toggles Swagger UI exposure.
@@ -132,6 +310,154 @@ func (c *Config) normalize() error {
 		return fmt.Errorf("parse jwt.refresh_ttl: %w", err)
 	}
 
+	c.Audit.ArchiveInterval, err = time.ParseDuration(defaultString(c.Audit.ArchiveIntervalRaw, "24h"))
+	if err != nil {
+		return fmt.Errorf("parse audit.archive_interval: %w", err)
+	}
+
+	c.Upload.SessionTTL, err = time.ParseDuration(defaultString(c.Upload.SessionTTLRaw, "24h"))
+	if err != nil {
+		return fmt.Errorf("parse upload.session_ttl: %w", err)
+	}
+
+	c.Upload.CleanupInterval, err = time.ParseDuration(defaultString(c.Upload.CleanupIntervalRaw, "1h"))
+	if err != nil {
+		return fmt.Errorf("parse upload.cleanup_interval: %w", err)
+	}
+	c.Upload.ChunkDir = defaultString(c.Upload.ChunkDir, "storage/chunks")
+	if c.Upload.ChunkSizeMB <= 0 {
+		c.Upload.ChunkSizeMB = 5
+	}
+	if c.Upload.MaxSessionSizeMB <= 0 {
+		c.Upload.MaxSessionSizeMB = 2048
+	}
+
+	c.Storage.SignedURLTTL, err = time.ParseDuration(defaultString(c.Storage.SignedURLTTLRaw, "15m"))
+	if err != nil {
+		return fmt.Errorf("parse storage.signed_url_ttl: %w", err)
+	}
+	c.Storage.Driver = defaultString(c.Storage.Driver, "local")
+	c.Storage.PrivateDir = defaultString(c.Storage.PrivateDir, "storage/private")
+	c.Storage.PublicBaseURL = defaultString(c.Storage.PublicBaseURL, "/uploads")
+
+	c.Media.PollInterval, err = time.ParseDuration(defaultString(c.Media.PollIntervalRaw, "10s"))
+	if err != nil {
+		return fmt.Errorf("parse media.poll_interval: %w", err)
+	}
+	c.Media.JobTimeout, err = time.ParseDuration(defaultString(c.Media.JobTimeoutRaw, "2h"))
+	if err != nil {
+		return fmt.Errorf("parse media.job_timeout: %w", err)
+	}
+	c.Media.PlaybackTTL, err = time.ParseDuration(defaultString(c.Media.PlaybackTTLRaw, "6h"))
+	if err != nil {
+		return fmt.Errorf("parse media.playback_ttl: %w", err)
+	}
+	c.Media.FFmpegPath = defaultString(c.Media.FFmpegPath, "ffmpeg")
+	c.Media.FFprobePath = defaultString(c.Media.FFprobePath, "ffprobe")
+	c.Media.SofficePath = defaultString(c.Media.SofficePath, "soffice")
+	c.Media.PdftoppmPath = defaultString(c.Media.PdftoppmPath, "pdftoppm")
+	c.Media.PdftotextPath = defaultString(c.Media.PdftotextPath, "pdftotext")
+	if c.Media.PageWidth <= 0 {
+		c.Media.PageWidth = 1080
+	}
+	c.Media.WorkDir = defaultString(c.Media.WorkDir, "storage/media-work")
+	if c.Media.SegmentSeconds <= 0 {
+		c.Media.SegmentSeconds = 6
+	}
+	if c.Media.MaxAttempts <= 0 {
+		c.Media.MaxAttempts = 3
+	}
+
+	if c.Learning.DocCompletionPercent <= 0 || c.Learning.DocCompletionPercent > 100 {
+		c.Learning.DocCompletionPercent = 80
+	}
+	if c.Learning.MinPageSeconds < 0 {
+		c.Learning.MinPageSeconds = 0
+	}
+
+	c.Search.Driver = defaultString(c.Search.Driver, "memory")
+	c.Search.RefreshInterval, err = time.ParseDuration(defaultString(c.Search.RefreshIntervalRaw, "5m"))
+	if err != nil {
+		return fmt.Errorf("parse search.refresh_interval: %w", err)
+	}
+
+	c.Scheduler.Interval, err = time.ParseDuration(defaultString(c.Scheduler.IntervalRaw, "30s"))
+	if err != nil {
+		return fmt.Errorf("parse scheduler.interval: %w", err)
+	}
+	c.Scheduler.LeaseTTL, err = time.ParseDuration(defaultString(c.Scheduler.LeaseTTLRaw, "2m"))
+	if err != nil {
+		return fmt.Errorf("parse scheduler.lease_ttl: %w", err)
+	}
+	if c.Scheduler.LeaseTTL <= c.Scheduler.Interval {
+		c.Scheduler.LeaseTTL = 2 * c.Scheduler.Interval
+	}
+
+	c.Moderation.Timeout, err = time.ParseDuration(defaultString(c.Moderation.TimeoutRaw, "5s"))
+	if err != nil {
+		return fmt.Errorf("parse moderation.timeout: %w", err)
+	}
+	c.Moderation.RefreshInterval, err = time.ParseDuration(defaultString(c.Moderation.RefreshIntervalRaw, "1m"))
+	if err != nil {
+		return fmt.Errorf("parse moderation.refresh_interval: %w", err)
+	}
+
+	if c.Growth.TrendingDays <= 0 {
+		c.Growth.TrendingDays = 7
+	}
+
+	c.Rollup.Interval, err = time.ParseDuration(defaultString(c.Rollup.IntervalRaw, "10m"))
+	if err != nil {
+		return fmt.Errorf("parse rollup.interval: %w", err)
+	}
+	c.Rollup.LeaseTTL, err = time.ParseDuration(defaultString(c.Rollup.LeaseTTLRaw, "30m"))
+	if err != nil {
+		return fmt.Errorf("parse rollup.lease_ttl: %w", err)
+	}
+	if c.Rollup.LeaseTTL <= c.Rollup.Interval {
+		c.Rollup.LeaseTTL = 2 * c.Rollup.Interval
+	}
+	if c.Rollup.NightlyHour < 0 || c.Rollup.NightlyHour > 23 {
+		return fmt.Errorf("rollup.nightly_hour must be between 0 and 23")
+	}
+	if c.Rollup.InitialDays <= 0 {
+		c.Rollup.InitialDays = 90
+	}
+
+	if c.Mail.Port == 0 {
+		c.Mail.Port = 587
+	}
+	c.Mail.Timeout, err = time.ParseDuration(defaultString(c.Mail.TimeoutRaw, "30s"))
+	if err != nil {
+		return fmt.Errorf("parse mail.timeout: %w", err)
+	}
+
+	c.Report.Interval, err = time.ParseDuration(defaultString(c.Report.IntervalRaw, "1m"))
+	if err != nil {
+		return fmt.Errorf("parse report.interval: %w", err)
+	}
+	c.Report.LeaseTTL, err = time.ParseDuration(defaultString(c.Report.LeaseTTLRaw, "10m"))
+	if err != nil {
+		return fmt.Errorf("parse report.lease_ttl: %w", err)
+	}
+	if c.Report.LeaseTTL <= c.Report.Interval {
+		c.Report.LeaseTTL = 2 * c.Report.Interval
+	}
+
+	c.Certificate.SerialPrefix = defaultString(c.Certificate.SerialPrefix, "MS")
+
+	c.Certification.Interval, err = time.ParseDuration(defaultString(c.Certification.IntervalRaw, "1h"))
+	if err != nil {
+		return fmt.Errorf("parse certification.interval: %w", err)
+	}
+	c.Certification.LeaseTTL, err = time.ParseDuration(defaultString(c.Certification.LeaseTTLRaw, "2h"))
+	if err != nil {
+		return fmt.Errorf("parse certification.lease_ttl: %w", err)
+	}
+	if c.Certification.LeaseTTL <= c.Certification.Interval {
+		c.Certification.LeaseTTL = 2 * c.Certification.Interval
+	}
+
 	if c.App.Env == "" {
 		c.App.Env = "local"
 	}
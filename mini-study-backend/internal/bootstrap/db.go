@@ -25,7 +25,8 @@ func InitDatabase(cfg *Config, logger *zap.Logger) (*gorm.DB, error) {
 		return nil, fmt.Errorf("unsupported database driver %s", cfg.Database.Driver)
 	}
 
-	gormCfg := &gorm.Config{}
+	// 将驱动的唯一键冲突等错误转换为 gorm.ErrDuplicatedKey，便于业务层识别并重试
+	gormCfg := &gorm.Config{TranslateError: true}
 	if cfg.App.Env == "dev" || cfg.App.Env == "local" {
 		gormCfg.Logger = gormlogger.Default.LogMode(gormlogger.Info)
 	}
@@ -35,13 +36,20 @@ func InitDatabase(cfg *Config, logger *zap.Logger) (*gorm.DB, error) {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
 
+	if err := migrateExamAttemptCycles(db); err != nil {
+		return nil, fmt.Errorf("migrate exam attempt cycles: %w", err)
+	}
+
 	if err := db.AutoMigrate(
 		&model.User{},
 		&model.AuditLog{},
+		&model.AuditLogArchive{},
 		&model.ManagerEmployee{},
+		&model.EmployeeTransfer{},
 		&model.ContentCategory{},
 		&model.Content{},
 		&model.LearningRecord{},
+		&model.LearningActivity{},
 		&model.Banner{},
 		&model.Notice{},
 		&model.ExamPaper{},
@@ -51,28 +59,97 @@ func InitDatabase(cfg *Config, logger *zap.Logger) (*gorm.DB, error) {
 		&model.UserPoint{},
 		&model.PointTransaction{},
 		&model.GrowthPost{},
+		&model.UploadSession{},
+		&model.MediaJob{},
+		&model.ContentPage{},
+		&model.LearningPageView{},
+		&model.SchedulerLease{},
+		&model.ReviewEvent{},
+		&model.ContentRevision{},
+		&model.ContentCheckpoint{},
+		&model.CheckpointAnswer{},
+		&model.ContentRating{},
+		&model.ContentComment{},
+		&model.GrowthLike{},
+		&model.GrowthComment{},
+		&model.GrowthShare{},
+		&model.Notification{},
+		&model.SensitiveWord{},
+		&model.GrowthModerationEvent{},
+		&model.GrowthTopic{},
+		&model.GrowthPostTopic{},
+		&model.UserDailyStat{},
+		&model.ContentDailyStat{},
+		&model.ExamDailyStat{},
+		&model.UserReportSummary{},
+		&model.UserExamSummary{},
+		&model.ReportRollupState{},
+		&model.ReportSchedule{},
+		&model.CertificateTemplate{},
+		&model.Certificate{},
+		&model.Certification{},
+		&model.UserCertification{},
 	); err != nil {
 		return nil, fmt.Errorf("auto migrate: %w", err)
 	}
 
+	// 考试记录原为每人每卷唯一，认证到期后需重新考试，改为按(用户, 试卷, 轮次)唯一，移除旧的唯一索引
+	if db.Migrator().HasIndex(&model.ExamAttempt{}, "idx_user_exam") {
+		if err := db.Migrator().DropIndex(&model.ExamAttempt{}, "idx_user_exam"); err != nil {
+			return nil, fmt.Errorf("drop exam attempt unique index: %w", err)
+		}
+	}
+
 	// 为MySQL数据库添加表注释（SQLite不支持表注释）
 	if cfg.Database.Driver == "mysql" || cfg.Database.Driver == "" {
 		tableComments := map[string]string{
-			"users":              "用户表",
-			"audit_logs":         "审计日志表",
-			"manager_employees":  "店长员工关联表",
-			"content_categories": "学习内容分类表",
-			"contents":           "学习内容表",
-			"learning_records":   "学习记录表",
-			"banners":            "轮播图表",
-			"notices":            "系统公告表",
-			"exam_papers":        "试卷表",
-			"exam_questions":     "考试题目表",
-			"exam_options":       "考试选项表",
-			"exam_attempts":      "考试记录表",
-			"user_points":        "用户积分表",
-			"point_transactions": "积分明细表",
-			"growth_posts":       "成长圈动态表",
+			"users":                      "用户表",
+			"audit_logs":                 "审计日志表",
+			"audit_log_archives":         "审计日志归档表",
+			"manager_employees":          "店长员工关联表",
+			"employee_transfers":         "员工调动记录表",
+			"content_categories":         "学习内容分类表",
+			"contents":                   "学习内容表",
+			"learning_records":           "学习记录表",
+			"banners":                    "轮播图表",
+			"notices":                    "系统公告表",
+			"exam_papers":                "试卷表",
+			"exam_questions":             "考试题目表",
+			"exam_options":               "考试选项表",
+			"exam_attempts":              "考试记录表",
+			"user_points":                "用户积分表",
+			"point_transactions":         "积分明细表",
+			"growth_posts":               "成长圈动态表",
+			"upload_sessions":            "分片上传会话表",
+			"media_jobs":                 "媒体处理任务表",
+			"content_pages":              "文档预览页表",
+			"learning_page_views":        "文档逐页阅读记录表",
+			"scheduler_leases":           "定时任务租约表",
+			"review_events":              "内容审核流转记录表",
+			"content_revisions":          "学习内容修订版本表",
+			"content_checkpoints":        "学习内容随堂测验检查点表",
+			"checkpoint_answers":         "随堂测验作答记录表",
+			"content_ratings":            "学习内容评分与评价表",
+			"content_comments":           "学习内容评论表",
+			"growth_likes":               "成长圈点赞表",
+			"growth_comments":            "成长圈评论表",
+			"growth_shares":              "成长圈分享记录表",
+			"notifications":              "站内通知表",
+			"sensitive_words":            "成长圈审核敏感词表",
+			"growth_moderation_events":   "成长圈动态审核历史表",
+			"growth_topics":              "成长圈话题表",
+			"growth_post_topics":         "成长圈动态话题关联表",
+			"report_user_daily_stats":    "用户每日学习汇总表",
+			"report_content_daily_stats": "学习内容每日汇总表",
+			"report_exam_daily_stats":    "试卷每日汇总表",
+			"report_user_summaries":      "用户学习进度汇总表",
+			"report_user_exam_summaries": "用户试卷成绩汇总表",
+			"report_rollup_states":       "报表汇总任务水位表",
+			"report_schedules":           "报表定时发送计划表",
+			"certificate_templates":      "证书模板表",
+			"certificates":               "考试证书表",
+			"certifications":             "资质认证表",
+			"user_certifications":        "用户认证持有记录表",
 		}
 
 		for tableName, comment := range tableComments {
@@ -85,3 +162,37 @@ func InitDatabase(cfg *Config, logger *zap.Logger) (*gorm.DB, error) {
 	logger.Info("database connected")
 	return db, nil
 }
+
+// migrateExamAttemptCycles adds the cycle column to an existing exam_attempts table before
+// automigration creates the unique (user, exam, cycle) index, numbering each user's earlier
+// attempts of an exam in submission order so that retakes do not collide.
+func migrateExamAttemptCycles(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&model.ExamAttempt{}) || migrator.HasColumn(&model.ExamAttempt{}, "Cycle") {
+		return nil
+	}
+	if err := migrator.AddColumn(&model.ExamAttempt{}, "Cycle"); err != nil {
+		return err
+	}
+
+	var rows []struct {
+		ID     uint
+		UserID uint
+		ExamID uint
+	}
+	if err := db.Unscoped().Model(&model.ExamAttempt{}).Select("id", "user_id", "exam_id").Order("id").Find(&rows).Error; err != nil {
+		return err
+	}
+	cycles := map[[2]uint]int{}
+	for _, row := range rows {
+		key := [2]uint{row.UserID, row.ExamID}
+		cycles[key]++
+		if cycles[key] == 1 {
+			continue
+		}
+		if err := db.Unscoped().Model(&model.ExamAttempt{}).Where("id = ?", row.ID).UpdateColumn("cycle", cycles[key]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package bootstrap
+
+import (
+	"github.com/javapub/mini-study/mini-study-backend/internal/mailer"
+)
+
+// InitMailer builds the configured mailer, or returns nil when mail is not configured.
+func InitMailer(cfg *Config) mailer.Mailer {
+	if cfg.Mail.Host == "" {
+		return nil
+	}
+	return mailer.NewSMTPMailer(mailer.SMTPConfig{
+		Host:     cfg.Mail.Host,
+		Port:     cfg.Mail.Port,
+		Username: cfg.Mail.Username,
+		Password: cfg.Mail.Password,
+		From:     cfg.Mail.From,
+		TLS:      cfg.Mail.TLS,
+		Timeout:  cfg.Mail.Timeout,
+	})
+}
@@ -9,8 +9,8 @@ import (
 )
 
 // RegisterRoutes binds all HTTP handlers to the gin engine.
-func RegisterRoutes(engine *gin.Engine, cfg *Config, userHandler *handler.UserHandler, contentHandler *handler.ContentHandler, learningHandler *handler.LearningHandler, bannerHandler *handler.BannerHandler, noticeHandler *handler.NoticeHandler, examHandler *handler.ExamHandler, uploadHandler *handler.UploadHandler, systemHandler *handler.SystemHandler, pointHandler *handler.PointHandler, growthHandler *handler.GrowthHandler) {
+func RegisterRoutes(engine *gin.Engine, cfg *Config, sessions middleware.SessionValidator, userHandler *handler.UserHandler, contentHandler *handler.ContentHandler, learningHandler *handler.LearningHandler, bannerHandler *handler.BannerHandler, noticeHandler *handler.NoticeHandler, examHandler *handler.ExamHandler, uploadHandler *handler.UploadHandler, systemHandler *handler.SystemHandler, pointHandler *handler.PointHandler, growthHandler *handler.GrowthHandler, auditHandler *handler.AuditHandler, mediaHandler *handler.MediaHandler, searchHandler *handler.SearchHandler, scheduleHandler *handler.ScheduleHandler, reviewHandler *handler.ReviewHandler, checkpointHandler *handler.CheckpointHandler, feedbackHandler *handler.ContentFeedbackHandler, notificationHandler *handler.NotificationHandler, moderationHandler *handler.ModerationHandler, analyticsHandler *handler.AnalyticsHandler, reportHandler *handler.ReportHandler, certificateHandler *handler.CertificateHandler, certificationHandler *handler.CertificationHandler) {
 	engine.Static("/uploads", cfg.Upload.Dir)
-	auth := middleware.JWT(cfg.JWT.Secret)
-	router.RegisterRoutes(engine, cfg.Swagger.Enabled, auth, userHandler, contentHandler, learningHandler, bannerHandler, noticeHandler, examHandler, uploadHandler, systemHandler, pointHandler, growthHandler)
+	auth := middleware.JWT(cfg.JWT.Secret, sessions)
+	router.RegisterRoutes(engine, cfg.Swagger.Enabled, auth, userHandler, contentHandler, learningHandler, bannerHandler, noticeHandler, examHandler, uploadHandler, systemHandler, pointHandler, growthHandler, auditHandler, mediaHandler, searchHandler, scheduleHandler, reviewHandler, checkpointHandler, feedbackHandler, notificationHandler, moderationHandler, analyticsHandler, reportHandler, certificateHandler, certificationHandler)
 }
@@ -0,0 +1,17 @@
+package bootstrap
+
+import (
+	"fmt"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/search"
+)
+
+// InitSearchIndex builds the configured search backend.
+func InitSearchIndex(cfg *Config) (search.Index, error) {
+	switch cfg.Search.Driver {
+	case "memory":
+		return search.NewMemoryIndex(), nil
+	default:
+		return nil, fmt.Errorf("unsupported search driver %s", cfg.Search.Driver)
+	}
+}
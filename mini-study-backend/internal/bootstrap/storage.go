@@ -0,0 +1,68 @@
+package bootstrap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
+)
+
+// downloadPath serves private objects of the local driver via signed links.
+const downloadPath = "/api/v1/files/download"
+
+// signSecretInfo separates the derived signing key from other uses of the JWT secret.
+const signSecretInfo = "mini-study storage url signing"
+
+// InitStorage builds the configured storage backend and the signer for local download links.
+func InitStorage(cfg *Config, logger *zap.Logger) (storage.Storage, *storage.URLSigner, error) {
+	secret := cfg.Storage.SignSecret
+	if secret == "" {
+		derived, err := DeriveSignSecret(cfg.JWT.Secret)
+		if err != nil {
+			return nil, nil, err
+		}
+		logger.Warn("storage.sign_secret is not set; deriving the download signing key from jwt.secret, configure a dedicated secret")
+		secret = derived
+	}
+	signer := storage.NewURLSigner(secret)
+
+	switch cfg.Storage.Driver {
+	case "local":
+		return storage.NewLocalStorage(cfg.Upload.Dir, cfg.Storage.PrivateDir, cfg.Storage.PublicBaseURL, downloadPath, signer), signer, nil
+	case "s3":
+		store, err := storage.NewS3Storage(storage.S3Config{
+			Endpoint:      cfg.Storage.S3.Endpoint,
+			Region:        cfg.Storage.S3.Region,
+			Bucket:        cfg.Storage.S3.Bucket,
+			AccessKey:     cfg.Storage.S3.AccessKey,
+			SecretKey:     cfg.Storage.S3.SecretKey,
+			UsePathStyle:  cfg.Storage.S3.UsePathStyle,
+			PublicBaseURL: cfg.Storage.S3.PublicBaseURL,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, signer, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported storage driver %s", cfg.Storage.Driver)
+	}
+}
+
+// DeriveSignSecret derives the download signing key from the JWT secret with HKDF-SHA256,
+// so a leaked download signature never doubles as a token signing key.
+func DeriveSignSecret(jwtSecret string) (string, error) {
+	if jwtSecret == "" {
+		return "", errors.New("storage.sign_secret and jwt.secret are both empty")
+	}
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(jwtSecret), nil, []byte(signSecretInfo)), key); err != nil {
+		return "", fmt.Errorf("derive storage sign secret: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
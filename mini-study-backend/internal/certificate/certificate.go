@@ -0,0 +1,178 @@
+// Package certificate lays out exam certificates as PDF and generates their serial numbers.
+package certificate
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/pdf"
+)
+
+// Page orientations.
+const (
+	Landscape = "landscape"
+	Portrait  = "portrait"
+)
+
+// DefaultBody is used when a template has no body text.
+const DefaultBody = "{name}（工号 {work_no}）于 {date} 参加《{exam_title}》考试，成绩 {score} 分，考核合格，特发此证。"
+
+// Placeholders lists the fields that may appear in a template body.
+var Placeholders = []string{"{name}", "{work_no}", "{exam_title}", "{score}", "{total_score}", "{date}", "{serial}"}
+
+// Design is the admin-editable part of a certificate.
+type Design struct {
+	Title       string
+	Body        string
+	Issuer      string
+	Signer      string
+	Orientation string
+	AccentColor string
+}
+
+// Data is what a single certificate certifies.
+type Data struct {
+	HolderName string
+	WorkNo     string
+	ExamTitle  string
+	Score      int
+	TotalScore int
+	PassedAt   time.Time
+	Serial     string
+	// VerifyURL is printed below the serial number when set.
+	VerifyURL string
+}
+
+// FormatDate formats a date the way it is printed on certificates.
+func FormatDate(t time.Time) string {
+	return t.Format("2006年1月2日")
+}
+
+// FillBody replaces the placeholders in body with data.
+func FillBody(body string, data Data) string {
+	if strings.TrimSpace(body) == "" {
+		body = DefaultBody
+	}
+	return strings.NewReplacer(
+		"{name}", data.HolderName,
+		"{work_no}", data.WorkNo,
+		"{exam_title}", data.ExamTitle,
+		"{score}", strconv.Itoa(data.Score),
+		"{total_score}", strconv.Itoa(data.TotalScore),
+		"{date}", FormatDate(data.PassedAt),
+		"{serial}", data.Serial,
+	).Replace(body)
+}
+
+// VerifyLink appends serial to the verification base URL, or returns "" when base is empty.
+func VerifyLink(base, serial string) string {
+	if base == "" {
+		return ""
+	}
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	return base + sep + "serial=" + url.QueryEscape(serial)
+}
+
+// Render writes the certificate as a single-page A4 PDF.
+func Render(w io.Writer, design Design, data Data) error {
+	accent, err := pdf.ParseHexColor(design.AccentColor)
+	if err != nil {
+		accent = pdf.Color{R: 0.72, G: 0.53, B: 0.04}
+	}
+	gray := pdf.Color{R: 0.35, G: 0.35, B: 0.35}
+
+	width, height := pdf.A4Height, pdf.A4Width
+	if design.Orientation == Portrait {
+		width, height = pdf.A4Width, pdf.A4Height
+	}
+	doc := pdf.New(width, height)
+	doc.Title = fmt.Sprintf("%s - %s", design.Title, data.HolderName)
+	page := doc.AddPage()
+
+	// 双线边框
+	page.Rect(18, 18, width-36, height-36, nil, &accent, 3)
+	page.Rect(28, 28, width-56, height-56, nil, &accent, 0.8)
+
+	cx := width / 2
+	margin := 90.0
+	if design.Orientation == Portrait {
+		margin = 70
+	}
+
+	title := design.Title
+	if title == "" {
+		title = "合格证书"
+	}
+	titleY := height - 120
+	page.TextCentered(cx, titleY, 38, accent, true, title)
+	page.Line(cx-90, titleY-22, cx+90, titleY-22, accent, 1.2)
+
+	// 正文居中排版，姓名单独成行突出显示
+	y := titleY - 90
+	page.TextCentered(cx, y, 26, pdf.Black, true, data.HolderName)
+	y -= 50
+	for _, line := range pdf.Wrap(FillBody(design.Body, data), 16, width-2*margin) {
+		page.TextCentered(cx, y, 16, pdf.Black, false, line)
+		y -= 30
+	}
+
+	right := width - margin
+	bottom := 70.0
+	if design.Orientation == Portrait {
+		bottom = 90
+	}
+	rows := []string{}
+	if design.Issuer != "" {
+		rows = append(rows, design.Issuer)
+	}
+	if design.Signer != "" {
+		rows = append(rows, "签发人："+design.Signer)
+	}
+	rows = append(rows, FormatDate(data.PassedAt))
+	for i, row := range rows {
+		page.TextRight(right, bottom+float64(len(rows)-1-i)*24, 14, pdf.Black, i == 0 && design.Issuer != "", row)
+	}
+
+	page.Text(margin, bottom+24, 10, gray, false, "证书编号："+data.Serial)
+	if data.VerifyURL != "" {
+		page.Text(margin, bottom, 9, gray, false, "验证地址："+data.VerifyURL)
+	}
+
+	return doc.Write(w)
+}
+
+// serialAlphabet is Crockford's base32, which leaves out I, L, O and U to avoid misreading.
+const serialAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewSerial returns a random serial number such as MS-20261019-7K3QX9PA.
+func NewSerial(prefix string, at time.Time) (string, error) {
+	var raw [8]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", fmt.Errorf("generate certificate serial: %w", err)
+	}
+	code := make([]byte, len(raw))
+	for i, b := range raw {
+		code[i] = serialAlphabet[int(b)%len(serialAlphabet)]
+	}
+	return fmt.Sprintf("%s-%s-%s", prefix, at.Format("20060102"), code), nil
+}
+
+// NormalizeSerial uppercases a serial typed by hand and maps the letters Crockford's base32
+// treats as look-alikes (O, I, L) back to digits in the random part.
+func NormalizeSerial(serial string) string {
+	serial = strings.ToUpper(strings.TrimSpace(serial))
+	idx := strings.LastIndex(serial, "-")
+	if idx < 0 {
+		return serial
+	}
+	code := strings.NewReplacer("O", "0", "I", "1", "L", "1").Replace(serial[idx+1:])
+	return serial[:idx+1] + code
+}
@@ -912,7 +912,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "覆盖式更新某个员工绑定的店长工号列表",
+                "description": "覆盖式更新某个员工绑定的店长工号列表，并记录调动历史；已禁用用户不能修改",
                 "consumes": [
                     "application/json"
                 ],
@@ -3732,7 +3732,7 @@ const docTemplate = `{
             }
         }
     }
-}
+}`
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
@@ -3744,8 +3744,6 @@ var SwaggerInfo = &swag.Spec{
 	Description:      "企业学习平台后端 API 文档",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
-	LeftDelim:        "{{",
-	RightDelim:       "}}",
 }
 
 func init() {
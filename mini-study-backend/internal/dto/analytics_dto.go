@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+// LearningAnalyticsQuery 学习数据看板查询参数，日期按服务器时区计算。
+type LearningAnalyticsQuery struct {
+	From      *time.Time `form:"from" time_format:"2006-01-02" example:"2024-06-01"`                 // 开始日期（含），默认 30 天前
+	To        *time.Time `form:"to" time_format:"2006-01-02" example:"2024-06-30"`                   // 结束日期（含），默认今天
+	Interval  string     `form:"interval" binding:"omitempty,oneof=day week month" example:"week"`   // 统计粒度：day/week/month，默认 day
+	GroupBy   string     `form:"group_by" binding:"omitempty,oneof=role manager" example:"role"`     // 分组对比：role 按角色，manager 按店长团队；为空时不分组
+	Role      string     `form:"role" binding:"omitempty,oneof=employee manager" example:"employee"` // 只统计该角色的用户
+	ManagerID uint       `form:"manager_id" example:"2"`                                             // 只统计该店长及其名下员工
+}
+
+// LearningAnalyticsPoint 一个统计周期（或整个时间范围）内的学习指标。
+type LearningAnalyticsPoint struct {
+	Period          string  `json:"period,omitempty" example:"2024-06-03"` // 周期起始日期，按周统计时为周一；汇总数据为空
+	ActiveLearners  int64   `json:"active_learners" example:"42"`          // 活跃学员数：有学习进度上报、考试提交或积分入账的去重用户数
+	Completions     int64   `json:"completions" example:"56"`              // 完成的学习内容数
+	ExamAttempts    int64   `json:"exam_attempts" example:"20"`            // 考试提交次数
+	ExamPassed      int64   `json:"exam_passed" example:"17"`              // 考试通过次数
+	ExamPassRate    float64 `json:"exam_pass_rate" example:"85"`           // 考试通过率（百分比），没有提交时为 0
+	VideoLearners   int64   `json:"video_learners" example:"30"`           // 观看视频的去重学员数：视频播放位置有推进的用户
+	AvgWatchSeconds float64 `json:"avg_watch_seconds" example:"612.5"`     // 人均观看时长（秒）：周期内视频播放位置推进的秒数按观看学员平均，回看不计
+	PointsEarned    int64   `json:"points_earned" example:"80"`            // 获得的积分
+}
+
+// LearningCohortSeries 一个分组的汇总与时间序列。
+type LearningCohortSeries struct {
+	Key    string                   `json:"key" example:"employee"` // 分组标识：all、角色代码或店长ID（0 表示未绑定店长）
+	Label  string                   `json:"label" example:"员工"`     // 分组名称
+	Total  LearningAnalyticsPoint   `json:"total"`                  // 整个时间范围的汇总
+	Series []LearningAnalyticsPoint `json:"series"`                 // 按周期的明细，周期连续且不省略空周期
+}
+
+// LearningAnalyticsResponse 学习数据看板。
+type LearningAnalyticsResponse struct {
+	From     string                 `json:"from" example:"2024-06-01"`
+	To       string                 `json:"to" example:"2024-06-30"`
+	Interval string                 `json:"interval" example:"week"`
+	GroupBy  string                 `json:"group_by,omitempty" example:"role"`
+	Periods  []string               `json:"periods"` // 各周期起始日期
+	Cohorts  []LearningCohortSeries `json:"cohorts"` // 不分组时只有 key 为 all 的一组
+}
@@ -0,0 +1,54 @@
+package dto
+
+import "time"
+
+// AdminAuditLogQuery filters audit logs for admin search and export.
+type AdminAuditLogQuery struct {
+	ActorID    uint       `form:"actor_id" binding:"omitempty,min=1" example:"1"`                                  // 操作者ID
+	Action     string     `form:"action" binding:"omitempty,max=64" example:"deactivate_user"`                     // 操作动作
+	Target     string     `form:"target" binding:"omitempty,max=128" example:"users"`                              // 操作目标
+	Result     string     `form:"result" binding:"omitempty,max=32" example:"success"`                             // 操作结果
+	RequestID  string     `form:"request_id" binding:"omitempty,max=64" example:"3f1c..."`                         // 请求ID
+	EntityType string     `form:"entity_type" binding:"omitempty,max=32" example:"content"`                        // 实体类型
+	EntityID   uint       `form:"entity_id" binding:"omitempty,min=1" example:"12"`                                // 实体ID
+	StartAt    *time.Time `form:"start_at" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-01-01T00:00:00Z"` // 开始时间（含）
+	EndAt      *time.Time `form:"end_at" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-02-01T00:00:00Z"`   // 结束时间（不含）
+	Page       int        `form:"page" binding:"omitempty,min=1" example:"1"`
+	PageSize   int        `form:"page_size" binding:"omitempty,min=1,max=100" example:"20"`
+}
+
+// AuditLogResponse 审计日志返回结构。
+type AuditLogResponse struct {
+	ID          uint               `json:"id" example:"1"`                    // 日志ID
+	ActorID     uint               `json:"actor_id" example:"1"`              // 操作者ID
+	ActorWorkNo string             `json:"actor_work_no" example:"admin"`     // 操作者工号
+	ActorName   string             `json:"actor_name" example:"系统管理员"`        // 操作者姓名
+	Action      string             `json:"action" example:"deactivate_user"`  // 操作动作
+	Target      string             `json:"target" example:"users"`            // 操作目标
+	Payload     string             `json:"payload" example:"{\"user_id\":3}"` // 操作载荷
+	Result      string             `json:"result" example:"success"`          // 操作结果
+	RequestID   string             `json:"request_id" example:"3f1c..."`      // 请求ID
+	EntityType  string             `json:"entity_type" example:"content"`     // 实体类型
+	EntityID    uint               `json:"entity_id" example:"12"`            // 实体ID
+	Changes     []AuditFieldChange `json:"changes,omitempty"`                 // 字段级变更
+	CreatedAt   time.Time          `json:"created_at"`                        // 操作时间
+}
+
+// AuditFieldChange 单个字段的变更前后值，敏感字段以 *** 代替。
+type AuditFieldChange struct {
+	Field  string      `json:"field" example:"status"`      // 字段名（JSON字段名）
+	Before interface{} `json:"before" swaggertype:"string"` // 变更前的值，新建时为 null
+	After  interface{} `json:"after" swaggertype:"string"`  // 变更后的值，删除时为 null
+}
+
+// AuditEntityHistoryQuery paginates the history of a single entity.
+type AuditEntityHistoryQuery struct {
+	Page     int `form:"page" binding:"omitempty,min=1" example:"1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100" example:"20"`
+}
+
+// AdminAuditLogListResponse 审计日志分页结果。
+type AdminAuditLogListResponse struct {
+	Items      []AuditLogResponse `json:"items"`
+	Pagination Pagination         `json:"pagination"`
+}
@@ -26,9 +26,16 @@ type AdminUpdateBannerRequest struct {
 	EndAt        *time.Time `json:"end_at" example:"2024-12-31T23:59:59Z"`                                        // 结束时间
 }
 
+// BannerQuery pages the banners visible to the current user.
+type BannerQuery struct {
+	PageQuery
+}
+
 // AdminListBannerQuery filters admin banner list.
 type AdminListBannerQuery struct {
 	Status *bool `form:"status" example:"true"` // 是否启用：true(启用) false(禁用)
+
+	PageQuery
 }
 
 // BannerResponse is returned to client.
@@ -43,3 +50,9 @@ type BannerResponse struct {
 	StartAt      *time.Time `json:"start_at" example:"2024-01-01T00:00:00Z"`                            // 开始时间
 	EndAt        *time.Time `json:"end_at" example:"2024-12-31T23:59:59Z"`                              // 结束时间
 }
+
+// BannerListResponse is a page of banners.
+type BannerListResponse struct {
+	Items      []BannerResponse `json:"items"`
+	Pagination Pagination       `json:"pagination"`
+}
@@ -0,0 +1,72 @@
+package dto
+
+import "time"
+
+// CertificateTemplateRequest creates or replaces a certificate template. exam_id binds the
+// template to one exam; 0 makes it the default for exams without their own template.
+type CertificateTemplateRequest struct {
+	Name        string `json:"name" binding:"required,max=100" example:"食品安全培训合格证"`
+	ExamID      uint   `json:"exam_id" example:"3"`
+	Title       string `json:"title" binding:"max=50" example:"合格证书"`
+	Body        string `json:"body" binding:"max=2000" example:"{name}（工号 {work_no}）于 {date} 参加《{exam_title}》考试，成绩 {score} 分，考核合格，特发此证。"`
+	Issuer      string `json:"issuer" binding:"max=100" example:"迷你学堂培训中心"`
+	Signer      string `json:"signer" binding:"max=50" example:"张三"`
+	Orientation string `json:"orientation" binding:"omitempty,oneof=landscape portrait" example:"landscape"`
+	AccentColor string `json:"accent_color" binding:"omitempty,len=7,hexcolor" example:"#B8860B"`
+	Enabled     *bool  `json:"enabled" example:"true"`
+}
+
+// CertificateTemplateResponse is a certificate template.
+type CertificateTemplateResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	ExamID      uint      `json:"exam_id"`
+	ExamTitle   string    `json:"exam_title,omitempty"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	Issuer      string    `json:"issuer"`
+	Signer      string    `json:"signer"`
+	Orientation string    `json:"orientation"`
+	AccentColor string    `json:"accent_color"`
+	Enabled     bool      `json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CertificateTemplateListResponse lists templates with the placeholders their body may use.
+type CertificateTemplateListResponse struct {
+	Items        []CertificateTemplateResponse `json:"items"`
+	Placeholders []string                      `json:"placeholders"`
+}
+
+// CertificateResponse is an issued certificate.
+type CertificateResponse struct {
+	Serial     string    `json:"serial" example:"MS-20261019-7K3QX9PA"`
+	AttemptID  uint      `json:"attempt_id"`
+	ExamID     uint      `json:"exam_id"`
+	ExamTitle  string    `json:"exam_title"`
+	HolderName string    `json:"holder_name"`
+	Score      int       `json:"score"`
+	TotalScore int       `json:"total_score"`
+	IssuedAt   time.Time `json:"issued_at"`
+	FileSize   int64     `json:"file_size"`
+	VerifyURL  string    `json:"verify_url,omitempty"`
+}
+
+// CertificateListResponse lists a user's certificates.
+type CertificateListResponse struct {
+	Items []CertificateResponse `json:"items"`
+}
+
+// CertificateVerifyResponse tells whether a serial number belongs to a genuine certificate.
+// Name and work number are partly masked because the endpoint is public.
+type CertificateVerifyResponse struct {
+	Valid      bool       `json:"valid"`
+	Serial     string     `json:"serial"`
+	HolderName string     `json:"holder_name,omitempty" example:"张*"`
+	WorkNo     string     `json:"work_no,omitempty" example:"E0***1"`
+	ExamTitle  string     `json:"exam_title,omitempty"`
+	Score      int        `json:"score,omitempty"`
+	TotalScore int        `json:"total_score,omitempty"`
+	IssuedAt   *time.Time `json:"issued_at,omitempty"`
+}
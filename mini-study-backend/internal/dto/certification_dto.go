@@ -0,0 +1,85 @@
+package dto
+
+import "time"
+
+// CertificationRequest creates or replaces a certification. Passing the linked exam grants the
+// certification for validity_months; renewal_window_days before expiry the exam reopens for
+// the holder and a reminder is sent.
+type CertificationRequest struct {
+	Name              string `json:"name" binding:"required,max=100" example:"食品安全年度认证"`
+	Description       string `json:"description" binding:"max=1000" example:"门店员工每年须重新通过食品安全考试"`
+	ExamID            uint   `json:"exam_id" binding:"required" example:"3"`
+	ValidityMonths    int    `json:"validity_months" binding:"required,min=1,max=120" example:"12"`
+	RenewalWindowDays *int   `json:"renewal_window_days" binding:"omitempty,min=0,max=365" example:"30"`
+	Enabled           *bool  `json:"enabled" example:"true"`
+}
+
+// CertificationResponse is a certification definition.
+type CertificationResponse struct {
+	ID                uint      `json:"id"`
+	Name              string    `json:"name"`
+	Description       string    `json:"description"`
+	ExamID            uint      `json:"exam_id"`
+	ExamTitle         string    `json:"exam_title,omitempty"`
+	ValidityMonths    int       `json:"validity_months"`
+	RenewalWindowDays int       `json:"renewal_window_days"`
+	Enabled           bool      `json:"enabled"`
+	HolderCount       int       `json:"holder_count"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// CertificationListResponse lists certifications.
+type CertificationListResponse struct {
+	Items []CertificationResponse `json:"items"`
+}
+
+// MyCertificationItem is a certification the user holds or is expected to hold. Status is
+// valid, expiring, expired or not_certified.
+type MyCertificationItem struct {
+	CertificationID uint       `json:"certification_id"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	ExamID          uint       `json:"exam_id"`
+	ExamTitle       string     `json:"exam_title"`
+	ValidityMonths  int        `json:"validity_months"`
+	Status          string     `json:"status" example:"expiring"`
+	PassedAt        *time.Time `json:"passed_at,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	RenewalOpensAt  *time.Time `json:"renewal_opens_at,omitempty"`
+	DaysRemaining   *int       `json:"days_remaining,omitempty"`
+	CanRetake       bool       `json:"can_retake"`
+}
+
+// MyCertificationListResponse lists the current user's certifications.
+type MyCertificationListResponse struct {
+	Items []MyCertificationItem `json:"items"`
+}
+
+// CertificationExpiringQuery filters certifications expiring within days. Expired ones are
+// always included. manager_id limits the admin view to one manager's team.
+type CertificationExpiringQuery struct {
+	Days            int  `form:"days" binding:"omitempty,min=1,max=365" example:"30"`
+	CertificationID uint `form:"certification_id" example:"1"`
+	ManagerID       uint `form:"manager_id" example:"2"`
+}
+
+// CertificationExpiringItem is a user's certification that expires soon or has expired.
+type CertificationExpiringItem struct {
+	UserID            uint      `json:"user_id"`
+	WorkNo            string    `json:"work_no"`
+	Name              string    `json:"name"`
+	CertificationID   uint      `json:"certification_id"`
+	CertificationName string    `json:"certification_name"`
+	ExamID            uint      `json:"exam_id"`
+	Status            string    `json:"status" example:"expiring"`
+	PassedAt          time.Time `json:"passed_at"`
+	ExpiresAt         time.Time `json:"expires_at"`
+	DaysRemaining     int       `json:"days_remaining"`
+}
+
+// CertificationExpiringResponse lists certifications expiring within Days, soonest first.
+type CertificationExpiringResponse struct {
+	Days  int                         `json:"days"`
+	Items []CertificationExpiringItem `json:"items"`
+}
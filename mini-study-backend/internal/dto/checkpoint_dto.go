@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+// AdminCheckpointUpsertRequest creates or replaces a quiz checkpoint of a content.
+type AdminCheckpointUpsertRequest struct {
+	Position     int64               `json:"position" binding:"min=0" example:"120"`                                  // 位置：视频为播放秒数，文档为页码（读完该页后作答），图文为前面的内容块数量
+	QuestionType string              `json:"question_type" binding:"required,oneof=single multiple" example:"single"` // 题型：single(单选) multiple(多选)
+	Stem         string              `json:"stem" binding:"required" example:"开业前最先检查哪一项？"`                           // 题干
+	Options      []ArticleQuizOption `json:"options" binding:"required,min=2,max=10"`                                 // 选项，2-10 个，is_correct 标记正确答案
+	Analysis     string              `json:"analysis" example:"消防通道须保持畅通"`                                            // 解析，答对后返回给学员
+	Points       int                 `json:"points" binding:"omitempty,min=0,max=100" example:"1"`                    // 首次作答即答对奖励的积分，0 表示不奖励
+}
+
+// CheckpointResponse describes a quiz checkpoint. Answers and analysis are only returned to
+// admins, or to learners who have passed the checkpoint.
+type CheckpointResponse struct {
+	ID           uint                `json:"id" example:"5"`                         // 检查点ID
+	ContentID    uint                `json:"content_id" example:"1"`                 // 内容ID
+	Position     int64               `json:"position" example:"120"`                 // 位置：视频秒数/文档页码/图文内容块序号
+	QuestionType string              `json:"question_type" example:"single"`         // 题型
+	Stem         string              `json:"stem" example:"开业前最先检查哪一项？"`             // 题干
+	Options      []ArticleQuizOption `json:"options"`                                // 选项
+	Analysis     string              `json:"analysis,omitempty" example:"消防通道须保持畅通"` // 解析
+	Points       int                 `json:"points" example:"1"`                     // 首次作答即答对奖励的积分
+
+	Attempts int        `json:"attempts" example:"1"`  // 当前用户的作答次数
+	Passed   bool       `json:"passed" example:"true"` // 当前用户是否已答对
+	PassedAt *time.Time `json:"passed_at,omitempty"`   // 答对时间
+}
+
+// CheckpointListQuery pages the checkpoints of a content.
+type CheckpointListQuery struct {
+	PageQuery
+}
+
+// CheckpointListResponse is a page of checkpoints.
+type CheckpointListResponse struct {
+	Items      []CheckpointResponse `json:"items"`
+	Pagination Pagination           `json:"pagination"`
+}
+
+// CheckpointAnswerRequest submits an answer to a checkpoint.
+type CheckpointAnswerRequest struct {
+	OptionIndexes []int `json:"option_indexes" binding:"required,min=1,dive,min=0" example:"0"` // 选择的选项序号（从 0 开始）
+}
+
+// CheckpointAnswerResponse is the result of answering a checkpoint.
+type CheckpointAnswerResponse struct {
+	CheckpointID   uint                      `json:"checkpoint_id" example:"5"`              // 检查点ID
+	Correct        bool                      `json:"correct" example:"true"`                 // 本次是否答对
+	Passed         bool                      `json:"passed" example:"true"`                  // 是否已通过（曾经答对）
+	Attempts       int                       `json:"attempts" example:"1"`                   // 累计作答次数
+	CorrectIndexes []int                     `json:"correct_indexes,omitempty" example:"0"`  // 正确选项序号，通过后返回
+	Analysis       string                    `json:"analysis,omitempty" example:"消防通道须保持畅通"` // 解析，通过后返回
+	PointsAwarded  int                       `json:"points_awarded,omitempty" example:"1"`   // 本次获得的积分
+	Progress       *LearningProgressResponse `json:"progress,omitempty"`                     // 通过后重新计算的学习进度（已开始学习时返回）
+}
@@ -7,9 +7,38 @@ type APIResponse[T any] struct {
 	Data    T      `json:"data"`
 }
 
+// PageQuery is the offset pagination contract of admin tables and catalogs. Sort accepts the
+// fields listed by each endpoint; rows with equal sort values keep a stable order by ID.
+type PageQuery struct {
+	Page     int    `form:"page" binding:"omitempty,min=1" example:"1"`               // 页码，从 1 开始
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100" example:"20"` // 每页数量，默认20，最大100
+	Sort     string `form:"sort" binding:"omitempty,max=32" example:"created_at"`     // 排序字段，可选值见各接口说明
+	Order    string `form:"order" binding:"omitempty,oneof=asc desc" example:"desc"`  // 排序方向：asc/desc
+}
+
+// CursorQuery is the pagination contract of feeds, which are read newest first. Pass the
+// next_cursor of the previous page to continue; rows posted in between do not shift the pages.
+type CursorQuery struct {
+	Cursor   string `form:"cursor" binding:"omitempty,max=128" example:"MTcwNDEwNjgwMDAwMDAwMDAwMDo0Mg"` // 上一页返回的 next_cursor，为空表示第一页
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100" example:"20"`                    // 每页数量，默认20，最大100
+}
+
 // Pagination describes paged list metadata.
 type Pagination struct {
 	Page     int   `json:"page" example:"1"`
 	PageSize int   `json:"page_size" example:"20"`
 	Total    int64 `json:"total" example:"100"`
+
+	HasMore    bool   `json:"has_more" example:"true"`                                        // 是否还有下一页
+	NextCursor string `json:"next_cursor,omitempty" example:"MTcwNDEwNjgwMDAwMDAwMDAwMDo0Mg"` // 游标分页的下一页游标，仅信息流返回
+}
+
+// NewPagination builds the metadata of an offset page.
+func NewPagination(page, pageSize int, total int64) Pagination {
+	return Pagination{
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+		HasMore:  int64(page*pageSize) < total,
+	}
 }
@@ -2,10 +2,56 @@ package dto
 
 import "time"
 
+// ArticleBlock is one block of an article. Which fields apply depends on Type; the others are
+// dropped when the blocks are saved.
 type ArticleBlock struct {
-	Type      string `json:"type"`
-	Text      string `json:"text,omitempty"`
-	ImagePath string `json:"image_path,omitempty"`
+	Type         string       `json:"type" example:"text"`                                // 块类型：text(段落) heading(标题) image(图片) list(列表) quote(引用) video(视频片段) attachment(附件) callout(提示框) quiz(随堂测验)
+	Text         string       `json:"text,omitempty" example:"门店开业前需完成以下检查"`              // 正文，用于 text/heading/quote/callout
+	ImagePath    string       `json:"image_path,omitempty" example:"contents/2024/a.png"` // 图片存储路径，用于 image
+	ImageURL     string       `json:"image_url,omitempty"`                                // 图片访问地址（私有存储为签名链接），仅响应返回
+	Level        int          `json:"level,omitempty" example:"2"`                        // 标题级别 1-3，用于 heading，默认 2
+	Ordered      bool         `json:"ordered,omitempty"`                                  // 是否有序列表，用于 list
+	Items        []string     `json:"items,omitempty"`                                    // 列表项，用于 list
+	Cite         string       `json:"cite,omitempty" example:"《门店运营手册》"`                  // 引用出处，用于 quote
+	Style        string       `json:"style,omitempty" example:"warning"`                  // 提示框样式 info/tip/warning/danger，用于 callout，默认 info
+	Title        string       `json:"title,omitempty" example:"注意"`                       // 提示框标题，用于 callout
+	Caption      string       `json:"caption,omitempty" example:"收银台布局示意"`                // 说明文字，用于 image/video
+	VideoPath    string       `json:"video_path,omitempty" example:"contents/2024/a.mp4"` // 视频存储路径，用于 video
+	VideoURL     string       `json:"video_url,omitempty"`                                // 视频访问地址（私有存储为签名链接），仅响应返回
+	StartSeconds int64        `json:"start_seconds,omitempty" example:"30"`               // 片段起始秒数，用于 video
+	EndSeconds   int64        `json:"end_seconds,omitempty" example:"90"`                 // 片段结束秒数，0 表示播放到结尾，用于 video
+	FilePath     string       `json:"file_path,omitempty" example:"contents/2024/a.pdf"`  // 附件存储路径，用于 attachment
+	FileURL      string       `json:"file_url,omitempty"`                                 // 附件访问地址（私有存储为签名链接），仅响应返回
+	FileName     string       `json:"file_name,omitempty" example:"检查清单.pdf"`             // 附件显示名称，用于 attachment，默认取路径中的文件名
+	Quiz         *ArticleQuiz `json:"quiz,omitempty"`                                     // 测验题，用于 quiz
+}
+
+// ArticleQuiz is a knowledge-check question embedded in an article.
+type ArticleQuiz struct {
+	Type     string              `json:"type" example:"single"`                  // 题型：single(单选) multiple(多选)
+	Stem     string              `json:"stem" example:"开业前最先检查哪一项？"`             // 题干
+	Options  []ArticleQuizOption `json:"options"`                                // 选项，2-10 个
+	Analysis string              `json:"analysis,omitempty" example:"消防通道须保持畅通"` // 解析，学员端不返回
+}
+
+// ArticleQuizOption is one option of an article quiz.
+type ArticleQuizOption struct {
+	Content   string `json:"content" example:"消防通道"`              // 选项内容
+	IsCorrect bool   `json:"is_correct,omitempty" example:"true"` // 是否正确答案，学员端不返回
+}
+
+// ArticleQuizAnswerRequest answers a quiz block of an article.
+type ArticleQuizAnswerRequest struct {
+	OptionIndexes []int `json:"option_indexes" binding:"required,min=1,dive,min=0" example:"0"` // 选择的选项序号（从 0 开始）
+}
+
+// ArticleQuizAnswerResponse is the feedback on an article quiz answer. Quiz blocks are self-checks:
+// answers are not recorded, and the answer key and analysis are always returned.
+type ArticleQuizAnswerResponse struct {
+	BlockIndex     int    `json:"block_index" example:"3"`                // 测验所在内容块序号（从 0 开始）
+	Correct        bool   `json:"correct" example:"true"`                 // 是否答对
+	CorrectIndexes []int  `json:"correct_indexes" example:"0"`            // 正确选项序号
+	Analysis       string `json:"analysis,omitempty" example:"消防通道须保持畅通"` // 解析
 }
 
 // AdminCreateContentRequest defines payload to create content.
@@ -17,7 +63,7 @@ type AdminCreateContentRequest struct {
 	CoverURL        string         `json:"cover_url" example:"https://example.com/cover.jpg"`                               // 封面图片URL
 	Summary         string         `json:"summary" example:"本视频介绍产品核心功能"`                                                // 内容摘要
 	VisibleRoles    string         `json:"visible_roles" binding:"omitempty,oneof=employee manager both" example:"both"`   // 可见角色：employee(员工) manager(店长) both(全部)
-	Status          string         `json:"status" binding:"omitempty,oneof=draft published" example:"published"`           // 状态：draft(草稿) published(已发布)
+	Status          string         `json:"status" binding:"omitempty,oneof=draft published" example:"draft"`               // 状态：新建内容均为 draft(草稿)，提交审核通过后才能发布
 	DurationSeconds int64          `json:"duration_seconds" example:"3600"`                                                 // 视频时长（秒）
 	ArticleBlocks   []ArticleBlock `json:"article_blocks,omitempty"`                                                          // 图文内容块，仅在 type=article 时使用
 }
@@ -31,7 +77,7 @@ type AdminUpdateContentRequest struct {
 	CoverURL        string         `json:"cover_url" example:"https://example.com/cover.jpg"`                             // 封面图片URL
 	Summary         string         `json:"summary" example:"本视频介绍产品核心功能"`                                                // 内容摘要
 	VisibleRoles    string         `json:"visible_roles" binding:"omitempty,oneof=employee manager both" example:"both"` // 可见角色：employee(员工) manager(店长) both(全部)
-	Status          string         `json:"status" binding:"omitempty,oneof=draft published offline" example:"published"` // 状态：draft(草稿) published(已发布) offline(下线)
+	Status          string         `json:"status" binding:"omitempty,oneof=draft in_review approved published offline" example:"published"` // 状态：draft(草稿) published(已发布，须已审核通过) offline(下线)；in_review/approved 只能通过审核接口变更
 	DurationSeconds int64          `json:"duration_seconds" example:"3600"`                                                // 视频时长（秒）
 	ArticleBlocks   []ArticleBlock `json:"article_blocks,omitempty"`                                                        // 图文内容块，仅在 type=article 时使用
 }
@@ -40,13 +86,34 @@ type AdminUpdateContentRequest struct {
 type AdminListContentRequest struct {
 	CategoryID uint   `form:"category_id" example:"1"`                                                      // 分类ID
 	Type       string `form:"type" binding:"omitempty,oneof=doc video article" example:"video"`            // 内容类型：doc(文档) 或 video(视频) 或 article(图文)
-	Status     string `form:"status" binding:"omitempty,oneof=draft published offline" example:"published"` // 状态：draft(草稿) published(已发布) offline(下线)
+	Status     string `form:"status" binding:"omitempty,oneof=draft in_review approved published offline" example:"published"` // 状态：draft(草稿) in_review(审核中) approved(已通过) published(已发布) offline(下线)
+
+	PageQuery
 }
 
 // PublishedContentQuery filters public content list.
 type PublishedContentQuery struct {
 	CategoryID uint   `form:"category_id" example:"1"`                                         // 分类ID
 	Type       string `form:"type" binding:"omitempty,oneof=doc video article" example:"video"` // 内容类型：doc(文档) 或 video(视频) 或 article(图文)
+
+	PageQuery
+}
+
+// ContentListResponse is a page of contents.
+type ContentListResponse struct {
+	Items      []ContentResponse `json:"items"`
+	Pagination Pagination        `json:"pagination"`
+}
+
+// ContentCategoryQuery pages category lists.
+type ContentCategoryQuery struct {
+	PageQuery
+}
+
+// ContentCategoryListResponse is a page of categories.
+type ContentCategoryListResponse struct {
+	Items      []ContentCategoryResponse `json:"items"`
+	Pagination Pagination                `json:"pagination"`
 }
 
 // ContentCategoryResponse represents category info.
@@ -71,6 +138,9 @@ type ContentResponse struct {
 	CategoryID      uint           `json:"category_id" example:"1"`                             // 分类ID
 	CategoryName    string         `json:"category_name" example:"产品培训"`                        // 分类名称
 	FilePath        string         `json:"file_path" example:"/uploads/video.mp4"`              // 文件存储路径
+	FileURL         string         `json:"file_url" example:"/uploads/video.mp4"`               // 文件访问URL，私有文件为带过期时间的签名链接
+	PageCount       int            `json:"page_count" example:"24"`                             // 文档预览页数，0 表示尚未生成分页预览
+	HLSURL          string         `json:"hls_url,omitempty" example:"/api/v1/media/hls/private/hls/3a7b/master.m3u8?expires=1700000000&signature=9f86d0"` // HLS 自适应码率播放地址（视频处理完成后返回）
 	CoverURL        string         `json:"cover_url" example:"https://example.com/cover.jpg"`   // 封面图片URL
 	Summary         string         `json:"summary" example:"本视频介绍产品核心功能"`                       // 内容摘要
 	Status          string         `json:"status" example:"published"`                          // 状态：draft(草稿) published(已发布) offline(下线)
@@ -78,21 +148,55 @@ type ContentResponse struct {
 	DurationSeconds int64          `json:"duration_seconds" example:"3600"`                     // 视频时长（秒）
 	PublishAt       *time.Time     `json:"publish_at,omitempty" example:"2024-01-01T00:00:00Z"` // 发布时间
 	ArticleBlocks   []ArticleBlock `json:"article_blocks,omitempty"`                             // 图文内容块，仅在 type=article 时返回
+
+	ScheduledPublishAt *time.Time `json:"scheduled_publish_at,omitempty" example:"2024-06-01T09:00:00+08:00"` // 定时发布时间
+	ScheduledOfflineAt *time.Time `json:"scheduled_offline_at,omitempty" example:"2024-06-30T18:00:00+08:00"` // 定时下线时间
+
+	RatingAverage float64 `json:"rating_average" example:"4.3"` // 平均评分（1-5 星，保留一位小数），无人评分时为 0
+	RatingCount   int64   `json:"rating_count" example:"18"`    // 评分人数
 }
 
 // LearningProgressRequest upserts learning progress.
 type LearningProgressRequest struct {
-	ContentID     uint  `json:"content_id" binding:"required" example:"1"`              // 内容ID
-	VideoPosition int64 `json:"video_position" binding:"gte=0" example:"120"`           // 视频播放位置（秒），0表示文档类型或初始状态
+	ContentID     uint  `json:"content_id" binding:"required" example:"1"`                   // 内容ID
+	VideoPosition int64 `json:"video_position" binding:"gte=0" example:"120"`                // 视频播放位置（秒），0表示文档类型或初始状态
+	Page          int   `json:"page" binding:"omitempty,min=1" example:"3"`                  // 文档当前阅读页码（文档类型上报）
+	PageSeconds   int64 `json:"page_seconds" binding:"omitempty,min=0,max=600" example:"15"` // 本次上报前在该页停留的秒数，单次最多 600
 }
 
 // LearningProgressResponse returns progress info.
 type LearningProgressResponse struct {
-	ContentID       uint   `json:"content_id" example:"1"`          // 内容ID
-	VideoPosition   int64  `json:"video_position" example:"120"`    // 视频播放位置（秒）
-	DurationSeconds int64  `json:"duration_seconds" example:"3600"` // 视频总时长（秒）
-	Progress        int    `json:"progress" example:"3"`            // 学习进度百分比（0-100）
-	Status          string `json:"status" example:"in_progress"`    // 学习状态：not_started(未开始) in_progress(进行中) completed(已完成)
+	ContentID       uint                       `json:"content_id" example:"1"`          // 内容ID
+	VideoPosition   int64                      `json:"video_position" example:"120"`    // 视频播放位置（秒）
+	DurationSeconds int64                      `json:"duration_seconds" example:"3600"` // 视频总时长（秒）
+	PageCount       int                        `json:"page_count" example:"24"`         // 文档预览总页数
+	PagesRead       int                        `json:"pages_read" example:"6"`          // 文档已读页数（停留达到最短阅读时长的页）
+	LastPage        int                        `json:"last_page" example:"7"`           // 文档最近阅读页码
+	Progress        int                        `json:"progress" example:"3"`            // 学习进度百分比（0-100）
+	Status          string                     `json:"status" example:"in_progress"`    // 学习状态：not_started(未开始) in_progress(进行中) completed(已完成)
+	PageViews       []LearningPageViewResponse `json:"page_views,omitempty"`            // 文档逐页阅读时长，仅查询单个内容进度时返回
+
+	CheckpointTotal     int  `json:"checkpoint_total,omitempty" example:"3"`      // 随堂测验检查点数量
+	CheckpointPassed    int  `json:"checkpoint_passed,omitempty" example:"1"`     // 已答对的检查点数量
+	PendingCheckpointID uint `json:"pending_checkpoint_id,omitempty" example:"5"` // 下一个待答对的检查点，答对前其后的学习不计入进度
+}
+
+// LearningProgressQuery pages through the caller's learning progress.
+type LearningProgressQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=in_progress completed" example:"in_progress"` // 学习状态过滤：in_progress(进行中) completed(已完成)
+	PageQuery
+}
+
+// LearningProgressListResponse is a page of learning progress.
+type LearningProgressListResponse struct {
+	Items      []LearningProgressResponse `json:"items"`
+	Pagination Pagination                 `json:"pagination"`
+}
+
+// LearningPageViewResponse 文档单页累计阅读时长。
+type LearningPageViewResponse struct {
+	PageNo          int   `json:"page_no" example:"3"`           // 页码
+	DurationSeconds int64 `json:"duration_seconds" example:"42"` // 累计阅读时长（秒）
 }
 
 // UserLearningStatsResponse returns learning statistics for a user.
@@ -112,3 +216,22 @@ type ContentCompletionStatsResponse struct {
 	TotalCount     int64   `json:"total_count" example:"30"`              // 已开始学习的用户总数
 	CompletionRate float64 `json:"completion_rate" example:"50.0"`        // 完成率（百分比）
 }
+
+// ContentPageQuery paginates the page previews of a document.
+type ContentPageQuery struct {
+	Page     int `form:"page" binding:"omitempty,min=1" example:"1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=50" example:"10"`
+}
+
+// ContentPageResponse 文档单页预览。
+type ContentPageResponse struct {
+	PageNo   int    `json:"page_no" example:"1"`                                                        // 页码（从1开始）
+	ImageURL string `json:"image_url" example:"/api/v1/files/download/private/doc-pages/3a7b/0001.png"` // 页面图片，带过期时间的签名链接
+	Text     string `json:"text" example:"第一章 门店服务规范"`                                                  // 页面文本
+}
+
+// ContentPageListResponse 文档分页预览结果，total 为文档总页数。
+type ContentPageListResponse struct {
+	Items      []ContentPageResponse `json:"items"`
+	Pagination Pagination            `json:"pagination"`
+}
@@ -0,0 +1,108 @@
+package dto
+
+import "time"
+
+// ContentRatingRequest creates or replaces the caller's rating of a content.
+type ContentRatingRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5" example:"4"`       // 评分：1-5 星
+	Review string `json:"review" binding:"omitempty,max=1000" example:"讲解清晰，案例实用"` // 评价文字，可为空；修改后需重新审核
+}
+
+// ContentRatingResponse describes a rating and its review text.
+type ContentRatingResponse struct {
+	ID           uint       `json:"id" example:"1"`                                       // 评分ID
+	ContentID    uint       `json:"content_id" example:"12"`                              // 内容ID
+	ContentTitle string     `json:"content_title,omitempty" example:"开业检查流程"`             // 内容标题（仅管理后台返回）
+	UserID       uint       `json:"user_id" example:"3"`                                  // 评分用户ID
+	UserName     string     `json:"user_name" example:"张三"`                               // 评分用户姓名
+	Rating       int        `json:"rating" example:"4"`                                   // 评分：1-5 星
+	Review       string     `json:"review,omitempty" example:"讲解清晰，案例实用"`                 // 评价文字
+	Status       string     `json:"status" example:"approved"`                            // 评价审核状态：pending/approved/rejected
+	CreatedAt    time.Time  `json:"created_at" example:"2024-01-01T12:00:00Z"`            // 首次评分时间
+	UpdatedAt    time.Time  `json:"updated_at" example:"2024-01-02T12:00:00Z"`            // 最近修改时间
+	ApprovedAt   *time.Time `json:"approved_at,omitempty" example:"2024-01-01T13:00:00Z"` // 审核通过时间
+}
+
+// ContentRatingSummary aggregates the ratings of a content.
+type ContentRatingSummary struct {
+	ContentID    uint          `json:"content_id" example:"12"`        // 内容ID
+	ContentTitle string        `json:"content_title" example:"开业检查流程"` // 内容标题
+	Average      float64       `json:"average" example:"4.3"`          // 平均分，保留一位小数
+	Count        int64         `json:"count" example:"18"`             // 评分人数
+	Distribution map[int]int64 `json:"distribution,omitempty"`         // 各星级人数，键为 1-5
+}
+
+// ContentReviewListResponse is the public rating view of a content.
+type ContentReviewListResponse struct {
+	Summary ContentRatingSummary    `json:"summary"`        // 评分汇总
+	Mine    *ContentRatingResponse  `json:"mine,omitempty"` // 当前用户自己的评分（含审核状态）
+	Reviews []ContentRatingResponse `json:"reviews"`        // 已审核通过的评价
+}
+
+// AdminContentRatingQuery filters ratings in the admin console.
+type AdminContentRatingQuery struct {
+	ContentID  uint   `form:"content_id" example:"12"`                                                      // 内容ID
+	Status     string `form:"status" binding:"omitempty,oneof=pending approved rejected" example:"pending"` // 评价审核状态
+	MaxRating  int    `form:"max_rating" binding:"omitempty,min=1,max=5" example:"2"`                       // 只看评分不高于该值的记录
+	WithReview bool   `form:"with_review" example:"true"`                                                   // 为 true 时只看填写了评价文字的记录
+	PageQuery
+}
+
+// ContentRatingListResponse is a page of ratings in the admin console.
+type ContentRatingListResponse struct {
+	Items      []ContentRatingResponse `json:"items"`
+	Pagination Pagination              `json:"pagination"`
+}
+
+// AdminRatedContentQuery filters the lowest-rated contents report.
+type AdminRatedContentQuery struct {
+	MinCount int64 `form:"min_count" binding:"omitempty,min=1" example:"3"` // 至少多少人评分才纳入排行，默认 1
+	PageQuery
+}
+
+// ContentRatingSummaryListResponse is a page of the rated contents report.
+type ContentRatingSummaryListResponse struct {
+	Items      []ContentRatingSummary `json:"items"`
+	Pagination Pagination             `json:"pagination"`
+}
+
+// ContentCommentRequest posts a comment or a reply.
+type ContentCommentRequest struct {
+	Body     string `json:"body" binding:"required,min=1,max=1000" example:"第三步的顺序能再讲一下吗？"` // 评论内容
+	ParentID *uint  `json:"parent_id" example:"8"`                                          // 回复的评论ID，为空表示顶层评论
+}
+
+// ContentCommentResponse describes a comment; the public list nests replies under their parent.
+type ContentCommentResponse struct {
+	ID         uint                     `json:"id" example:"9"`                                       // 评论ID
+	ContentID  uint                     `json:"content_id" example:"12"`                              // 内容ID
+	ParentID   *uint                    `json:"parent_id,omitempty" example:"8"`                      // 回复的评论ID
+	Body       string                   `json:"body" example:"第三步的顺序能再讲一下吗？"`                         // 评论内容
+	Status     string                   `json:"status" example:"approved"`                            // 状态：pending/approved/rejected
+	UserID     uint                     `json:"user_id" example:"3"`                                  // 评论用户ID
+	UserName   string                   `json:"user_name" example:"张三"`                               // 评论用户姓名
+	UserRole   string                   `json:"user_role" example:"employee"`                         // 评论用户角色
+	CreatedAt  time.Time                `json:"created_at" example:"2024-01-01T12:00:00Z"`            // 评论时间
+	ApprovedAt *time.Time               `json:"approved_at,omitempty" example:"2024-01-01T13:00:00Z"` // 审核通过时间
+	Replies    []ContentCommentResponse `json:"replies,omitempty"`                                    // 回复（仅公开列表返回）
+}
+
+// AdminContentCommentQuery filters comments in the admin console.
+type AdminContentCommentQuery struct {
+	ContentID uint   `form:"content_id" example:"12"`                                                      // 内容ID
+	Keyword   string `form:"keyword" example:"顺序"`                                                         // 搜索关键词
+	Status    string `form:"status" binding:"omitempty,oneof=pending approved rejected" example:"pending"` // 状态过滤
+	PageQuery
+}
+
+// ContentCommentQuery pages the public comments of a content, newest top-level comment first.
+type ContentCommentQuery struct {
+	CursorQuery
+}
+
+// ContentCommentListResponse is a page of comments: a cursor page of threads in the public list,
+// an offset page in the admin console.
+type ContentCommentListResponse struct {
+	Items      []ContentCommentResponse `json:"items"`
+	Pagination Pagination               `json:"pagination"`
+}
@@ -0,0 +1,68 @@
+package dto
+
+import "time"
+
+// ContentRevisionItem 修订版本列表项。
+type ContentRevisionItem struct {
+	Version       int       `json:"version" example:"3"`                           // 版本号
+	Action        string    `json:"action" example:"update"`                       // 来源：baseline/create/update/rollback
+	SourceVersion int       `json:"source_version,omitempty" example:"1"`          // 回滚来源版本号
+	AuthorID      uint      `json:"author_id" example:"1"`                         // 修改人ID
+	AuthorName    string    `json:"author_name" example:"系统管理员"`                   // 修改人姓名
+	Title         string    `json:"title" example:"新品培训"`                          // 该版本的标题
+	ChangedFields []string  `json:"changed_fields" example:"title,article_blocks"` // 相比上一版本变更的字段
+	CreatedAt     time.Time `json:"created_at"`                                    // 生成时间
+}
+
+// ContentRevisionListQuery 修订版本列表分页参数。
+type ContentRevisionListQuery struct {
+	PageQuery
+}
+
+// ContentRevisionListResponse 修订版本分页结果。
+type ContentRevisionListResponse struct {
+	Items      []ContentRevisionItem `json:"items"`
+	Pagination Pagination            `json:"pagination"`
+}
+
+// ContentRevisionDetail 修订版本的完整内容。
+type ContentRevisionDetail struct {
+	Version         int            `json:"version" example:"3"`                     // 版本号
+	Action          string         `json:"action" example:"update"`                 // 来源：baseline/create/update/rollback
+	SourceVersion   int            `json:"source_version,omitempty" example:"1"`    // 回滚来源版本号
+	AuthorID        uint           `json:"author_id" example:"1"`                   // 修改人ID
+	AuthorName      string         `json:"author_name" example:"系统管理员"`             // 修改人姓名
+	Title           string         `json:"title" example:"新品培训"`                    // 标题
+	Type            string         `json:"type" example:"article"`                  // 内容类型
+	CategoryID      uint           `json:"category_id" example:"1"`                 // 分类ID
+	VisibleRoles    string         `json:"visible_roles" example:"both"`            // 可见角色
+	FilePath        string         `json:"file_path" example:"contents/2024/a.mp4"` // 文件存储路径
+	CoverURL        string         `json:"cover_url" example:"https://example.com/cover.jpg"`
+	Summary         string         `json:"summary" example:"本视频介绍产品核心功能"`   // 摘要
+	DurationSeconds int64          `json:"duration_seconds" example:"3600"` // 视频时长（秒）
+	ArticleBlocks   []ArticleBlock `json:"article_blocks"`                  // 图文内容块
+	CreatedAt       time.Time      `json:"created_at"`                      // 生成时间
+}
+
+// ContentRevisionDiffQuery 选择比对的两个版本，默认比对最新版本与其上一版本。
+type ContentRevisionDiffQuery struct {
+	From int `form:"from" binding:"omitempty,min=1" example:"1"` // 旧版本号
+	To   int `form:"to" binding:"omitempty,min=1" example:"3"`   // 新版本号
+}
+
+// ArticleBlockDiff 图文内容块的一处差异。
+type ArticleBlockDiff struct {
+	Op        string        `json:"op" example:"changed"`   // equal(未变) added(新增) removed(删除) changed(修改)
+	FromIndex *int          `json:"from_index" example:"2"` // 在旧版本中的位置，新增时为空
+	ToIndex   *int          `json:"to_index" example:"2"`   // 在新版本中的位置，删除时为空
+	Before    *ArticleBlock `json:"before,omitempty"`       // 旧版本中的内容块
+	After     *ArticleBlock `json:"after,omitempty"`        // 新版本中的内容块
+}
+
+// ContentRevisionDiffResponse 两个版本之间的差异。
+type ContentRevisionDiffResponse struct {
+	From   int                `json:"from" example:"1"` // 旧版本号
+	To     int                `json:"to" example:"3"`   // 新版本号
+	Fields []AuditFieldChange `json:"fields"`           // 除图文内容块外的字段变更
+	Blocks []ArticleBlockDiff `json:"blocks"`           // 图文内容块逐块比对，按新版本顺序排列
+}
@@ -23,7 +23,7 @@ type AdminExamQuestionUpsert struct {
 type AdminExamUpsertRequest struct {
 	Title            string                    `json:"title" binding:"required"`
 	Description      string                    `json:"description"`
-	Status           string                    `json:"status" binding:"omitempty,oneof=draft published archived"`
+	Status           string                    `json:"status" binding:"omitempty,oneof=draft in_review approved published archived"`
 	TargetRole       string                    `json:"target_role" binding:"omitempty,oneof=employee manager all"`
 	TimeLimitMinutes int                       `json:"time_limit_minutes" binding:"omitempty,min=0"`
 	PassScore        int                       `json:"pass_score" binding:"required,min=0"`
@@ -44,6 +44,11 @@ type ExamListItem struct {
 	LastScore        int        `json:"last_score"`
 	LastPassed       bool       `json:"last_passed"`
 	LastSubmittedAt  *time.Time `json:"last_submitted_at"`
+	// CertificationStatus is set when the exam backs a certification: valid, expiring, expired
+	// or not_certified. CanRetake reports whether a submitted exam may be taken again.
+	CertificationStatus    string     `json:"certification_status,omitempty"`
+	CertificationExpiresAt *time.Time `json:"certification_expires_at,omitempty"`
+	CanRetake              bool       `json:"can_retake"`
 }
 
 // ExamDetailQuestionOption is returned to exam detail API.
@@ -111,6 +116,10 @@ type ExamSubmitResponse struct {
 	TotalCount      int                `json:"total_count"`
 	DurationSeconds int64              `json:"duration_seconds"`
 	Answers         []ExamAnswerReview `json:"answers"`
+	// CertificateSerial is set when the pass was issued a certificate.
+	CertificateSerial string `json:"certificate_serial,omitempty"`
+	// CertificationExpiresAt is set when the pass renewed a certification linked to the exam.
+	CertificationExpiresAt *time.Time `json:"certification_expires_at,omitempty"`
 }
 
 // ExamResultSummary represents a simplified attempt record.
@@ -123,6 +132,8 @@ type ExamResultSummary struct {
 	PassScore   int       `json:"pass_score"`
 	Pass        bool      `json:"pass"`
 	SubmittedAt time.Time `json:"submitted_at"`
+	// CertificateSerial is set once a certificate has been issued for the attempt.
+	CertificateSerial string `json:"certificate_serial,omitempty"`
 }
 
 // ManagerExamProgressItem summarises exam stats for manager dashboard.
@@ -160,10 +171,12 @@ type ManagerEmployeeExamRecord struct {
 	LearningProgress EmployeeLearningProgress  `json:"learning_progress"`
 }
 
-// ManagerExamOverviewResponse returns exam & learning overview for manager.
+// ManagerExamOverviewResponse returns exam & learning overview for manager. The figures come
+// from the reporting rollups as of RefreshedAt, which is null before the first rollup.
 type ManagerExamOverviewResponse struct {
 	ExamProgress []ManagerExamProgressItem   `json:"exam_progress"`
 	Employees    []ManagerEmployeeExamRecord `json:"employees"`
+	RefreshedAt  *time.Time                  `json:"refreshed_at"`
 }
 
 // AdminExamOverviewQuery controls filtering and pagination for admin exam overview.
@@ -185,9 +198,35 @@ type AdminUserExamRecord struct {
 	LearningProgress EmployeeLearningProgress  `json:"learning_progress"`
 }
 
-// AdminExamOverviewResponse returns exam & learning overview for admin.
+// AdminExamOverviewResponse returns exam & learning overview for admin, read from the
+// reporting rollups like the manager overview.
 type AdminExamOverviewResponse struct {
 	ExamProgress []ManagerExamProgressItem `json:"exam_progress"`
 	Users        []AdminUserExamRecord     `json:"users"`
 	Pagination   Pagination                `json:"pagination"`
+	RefreshedAt  *time.Time                `json:"refreshed_at"`
+}
+
+// AdminExamListQuery filters the admin exam list.
+type AdminExamListQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=draft in_review approved published archived" example:"published"`
+	PageQuery
+}
+
+// ExamListResponse is a page of exams available to the current user.
+type ExamListResponse struct {
+	Items      []ExamListItem `json:"items"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+// ExamResultListResponse is a page of the current user's exam results.
+type ExamResultListResponse struct {
+	Items      []ExamResultSummary `json:"items"`
+	Pagination Pagination          `json:"pagination"`
+}
+
+// AdminExamListResponse is a page of exams with questions for admin.
+type AdminExamListResponse struct {
+	Items      []ExamDetailResponse `json:"items"`
+	Pagination Pagination           `json:"pagination"`
 }
@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+// UploadRequest carries the multipart form fields accompanying an uploaded file.
+type UploadRequest struct {
+	Purpose string `form:"purpose" binding:"omitempty,oneof=avatar cover video growth_image document" example:"cover"` // 上传用途：avatar头像 cover封面/配图 video视频 growth_image成长动态图片 document文档；为空时按扩展名推断
+}
+
+// UploadResponse 上传结果。
+type UploadResponse struct {
+	Path        string `json:"path" example:"/uploads/cover/3a/3a7bd3e2360a3d29eea436fcfb7e44c735d117c42d1c1835420b6b9942dd4f1b.png"` // 写入业务数据的路径：公开文件为访问URL，私有文件为存储键
+	URL         string `json:"url" example:"/uploads/cover/3a/3a7bd3e2360a3d29eea436fcfb7e44c735d117c42d1c1835420b6b9942dd4f1b.png"`  // 当前可访问的URL，私有文件为带过期时间的签名链接
+	Key         string `json:"key" example:"public/cover/3a/3a7bd3e2360a3d29eea436fcfb7e44c735d117c42d1c1835420b6b9942dd4f1b.png"`    // 存储键（按内容SHA-256寻址）
+	Purpose     string `json:"purpose" example:"cover"`                                                                               // 上传用途
+	ContentType string `json:"content_type" example:"image/png"`                                                                      // 嗅探得到的MIME类型
+	Size        int64  `json:"size" example:"20480"`                                                                                  // 文件大小（字节）
+	Private     bool   `json:"private" example:"false"`                                                                               // 是否为私有文件
+}
+
+// SignedDownloadQuery carries the signature of a local signed download link.
+type SignedDownloadQuery struct {
+	Expires   int64  `form:"expires" binding:"required" example:"1700000000"`  // 过期时间（Unix秒）
+	Signature string `form:"signature" binding:"required" example:"9f86d0..."` // 签名
+}
+
+// InitUploadSessionRequest 创建分片上传会话。
+type InitUploadSessionRequest struct {
+	Filename string `json:"filename" binding:"required,max=255" example:"产品培训.mp4"`                                                                   // 原始文件名（用于校验扩展名）
+	Size     int64  `json:"size" binding:"required,min=1" example:"524288000"`                                                                        // 文件总大小（字节）
+	Purpose  string `json:"purpose" binding:"omitempty,oneof=avatar cover video growth_image document" example:"video"`                               // 上传用途，为空时按扩展名推断
+	SHA256   string `json:"sha256" binding:"omitempty,len=64,hexadecimal" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"` // 整个文件的SHA-256，可选，完成时校验
+}
+
+// UploadChunkRequest carries the form fields of a chunk upload.
+type UploadChunkRequest struct {
+	Offset   int64  `form:"offset" binding:"min=0" example:"0"`                                                                                         // 分片在文件中的起始偏移，必须等于已接收字节数
+	Checksum string `form:"checksum" binding:"omitempty,len=64,hexadecimal" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"` // 分片的SHA-256，可选
+}
+
+// UploadSessionResponse 分片上传会话状态。
+type UploadSessionResponse struct {
+	UploadID      string    `json:"upload_id" example:"5f0c6c1e-7d1b-4c1e-9a55-0d8f3b7c2e11"` // 上传会话ID
+	Filename      string    `json:"filename" example:"产品培训.mp4"`                              // 原始文件名
+	Purpose       string    `json:"purpose" example:"video"`                                  // 上传用途
+	TotalSize     int64     `json:"total_size" example:"524288000"`                           // 文件总大小（字节）
+	ReceivedBytes int64     `json:"received_bytes" example:"10485760"`                        // 已接收字节数，即下一个分片的偏移
+	ChunkSize     int64     `json:"chunk_size" example:"5242880"`                             // 单个分片的最大字节数
+	Status        string    `json:"status" example:"uploading"`                               // 状态：uploading/completed/aborted/expired
+	ExpiresAt     time.Time `json:"expires_at"`                                               // 会话过期时间，每次上传分片后顺延
+}
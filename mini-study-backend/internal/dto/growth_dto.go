@@ -2,38 +2,231 @@ package dto
 
 import "time"
 
-// CreateGrowthPostRequest 店长创建成长圈动态请求体。
+// CreateGrowthPostRequest 创建成长圈动态请求体，正文中的 #话题 会自动关联到话题。
 type CreateGrowthPostRequest struct {
-	Content    string   `json:"content" binding:"required,min=1,max=1000" example:"今天门店销售突破50万，大家辛苦了！"` // 动态文本内容
-	ImagePaths []string `json:"image_paths" binding:"omitempty,max=9,dive,required"`                                 // 图片路径数组，最多9张
+	Content    string   `json:"content" binding:"required,min=1,max=1000" example:"今天门店销售突破50万，大家辛苦了！#门店喜报"` // 动态文本内容
+	ImagePaths []string `json:"image_paths" binding:"omitempty,max=9,dive,required"`                         // 图片路径数组，最多9张
+
+	Scope        string   `json:"scope" binding:"omitempty,oneof=company team roles region" example:"company"` // 可见范围：company全公司(默认)/team本店团队/roles指定角色；用户没有区域信息，按区域发布请使用 team，传 region 会被拒绝
+	VisibleRoles []string `json:"visible_roles" binding:"omitempty,max=3,dive,oneof=employee manager admin"`   // scope 为 roles 时可见的角色
 }
 
 // GrowthListQuery 成长圈公开列表查询参数。
 type GrowthListQuery struct {
-	Keyword string `form:"keyword" example:"销售"` // 搜索关键词，按内容模糊匹配
+	Keyword  string `form:"keyword" example:"销售"`     // 搜索关键词，按内容模糊匹配
+	Topic    string `form:"topic" example:"门店喜报"`     // 话题名称，只看带该话题的动态
+	Featured bool   `form:"featured" example:"false"` // 只看精选动态
+
+	CursorQuery
 }
 
 // GrowthMyListQuery 当前用户自己的成长圈列表查询参数。
 type GrowthMyListQuery struct {
-	Keyword string `form:"keyword" example:"培训"`                                                        // 搜索关键词
+	Keyword string `form:"keyword" example:"培训"`                                                         // 搜索关键词
 	Status  string `form:"status" binding:"omitempty,oneof=pending approved rejected" example:"pending"` // 状态过滤
+
+	CursorQuery
 }
 
 // AdminGrowthListQuery 管理员成长圈列表查询参数。
 type AdminGrowthListQuery struct {
-	Keyword string `form:"keyword" example:"奖励"`                                                        // 搜索关键词
+	Keyword string `form:"keyword" example:"奖励"`                                                         // 搜索关键词
 	Status  string `form:"status" binding:"omitempty,oneof=pending approved rejected" example:"pending"` // 状态过滤
+
+	Moderation string `form:"moderation" binding:"omitempty,oneof=pass review block" example:"review"` // 自动审核结论过滤
+
+	PageQuery
 }
 
 // GrowthPostResponse 成长圈动态返回结构。
 type GrowthPostResponse struct {
-	ID            uint       `json:"id" example:"1"`                         // 动态ID
-	Content       string     `json:"content" example:"今天门店销售突破50万，大家辛苦了！"` // 文本内容
-	ImagePaths    []string   `json:"image_paths,omitempty"`                   // 图片路径数组（相对路径），前端使用 buildFileUrl 转全路径
-	Status        string     `json:"status" example:"approved"`            // 状态：pending/approved/rejected
-	PublisherID   uint       `json:"publisher_id" example:"3"`             // 发布者用户ID
-	PublisherName string     `json:"publisher_name" example:"张店长"`       // 发布者姓名
-	PublisherRole string     `json:"publisher_role" example:"manager"`     // 发布者角色：employee/manager/admin
-	CreatedAt     time.Time  `json:"created_at" example:"2024-01-01T12:00:00Z"` // 创建时间
-	ApprovedAt    *time.Time `json:"approved_at,omitempty" example:"2024-01-01T13:00:00Z"`   // 审核通过时间
+	ID            uint       `json:"id" example:"1"`                                       // 动态ID
+	Content       string     `json:"content" example:"今天门店销售突破50万，大家辛苦了！"`                 // 文本内容
+	ImagePaths    []string   `json:"image_paths,omitempty"`                                // 图片路径数组（相对路径），前端使用 buildFileUrl 转全路径
+	Status        string     `json:"status" example:"approved"`                            // 状态：pending/approved/rejected
+	PublisherID   uint       `json:"publisher_id" example:"3"`                             // 发布者用户ID
+	PublisherName string     `json:"publisher_name" example:"张店长"`                         // 发布者姓名
+	PublisherRole string     `json:"publisher_role" example:"manager"`                     // 发布者角色：employee/manager/admin
+	CreatedAt     time.Time  `json:"created_at" example:"2024-01-01T12:00:00Z"`            // 创建时间
+	ApprovedAt    *time.Time `json:"approved_at,omitempty" example:"2024-01-01T13:00:00Z"` // 审核通过时间
+
+	LikeCount    int64 `json:"like_count" example:"12"`   // 点赞数
+	CommentCount int64 `json:"comment_count" example:"3"` // 已公开的评论数
+	ShareCount   int64 `json:"share_count" example:"5"`   // 分享人数
+	Liked        bool  `json:"liked" example:"true"`      // 当前用户是否已点赞
+
+	RejectReasonCode  string     `json:"reject_reason_code,omitempty" example:"irrelevant"`    // 最近一次拒绝原因代码
+	RejectReasonLabel string     `json:"reject_reason_label,omitempty" example:"与工作无关"`        // 拒绝原因代码的中文名称
+	RejectReason      string     `json:"reject_reason,omitempty" example:"请补充门店活动的具体数据"`       // 拒绝原因说明
+	RejectedAt        *time.Time `json:"rejected_at,omitempty" example:"2024-01-01T13:00:00Z"` // 最近一次拒绝时间
+	ResubmitCount     int        `json:"resubmit_count" example:"1"`                           // 重新提交次数
+
+	Scope        string   `json:"scope" example:"company"` // 可见范围：company/team/roles
+	VisibleRoles []string `json:"visible_roles,omitempty"` // scope 为 roles 时可见的角色
+	Topics       []string `json:"topics,omitempty"`        // 话题名称
+	Pinned       bool     `json:"pinned" example:"false"`  // 是否置顶
+	Featured     bool     `json:"featured" example:"true"` // 是否精选
+
+	Moderation *ModerationResultResponse `json:"moderation,omitempty"` // 自动审核结果，仅管理员列表返回
+}
+
+// GrowthPostListResponse 成长圈动态分页结果；信息流接口通过 pagination.next_cursor 翻页。
+// 公开信息流的第一页在 pinned 中返回置顶动态，置顶动态不再出现在 items 中。
+type GrowthPostListResponse struct {
+	Pinned     []GrowthPostResponse `json:"pinned,omitempty"`
+	Items      []GrowthPostResponse `json:"items"`
+	Pagination Pagination           `json:"pagination"`
+}
+
+// GrowthLikeResponse 点赞或取消点赞后的状态。
+type GrowthLikeResponse struct {
+	PostID    uint  `json:"post_id" example:"1"`     // 动态ID
+	Liked     bool  `json:"liked" example:"true"`    // 当前用户是否已点赞
+	LikeCount int64 `json:"like_count" example:"12"` // 点赞数
+}
+
+// GrowthLikerResponse 点赞用户信息。
+type GrowthLikerResponse struct {
+	UserID  uint      `json:"user_id" example:"3"`                     // 点赞用户ID
+	Name    string    `json:"name" example:"张三"`                       // 点赞用户姓名
+	Role    string    `json:"role" example:"employee"`                 // 点赞用户角色
+	LikedAt time.Time `json:"liked_at" example:"2024-01-01T12:00:00Z"` // 点赞时间
+}
+
+// GrowthLikerListResponse 点赞用户分页结果，通过 pagination.next_cursor 翻页。
+type GrowthLikerListResponse struct {
+	Items      []GrowthLikerResponse `json:"items"`
+	Pagination Pagination            `json:"pagination"`
+}
+
+// GrowthCommentRequest 发表成长圈评论或回复。
+type GrowthCommentRequest struct {
+	Body     string `json:"body" binding:"required,min=1,max=500" example:"恭喜！经验能分享一下吗？"` // 评论内容
+	ParentID *uint  `json:"parent_id" example:"8"`                                        // 回复的评论ID，为空表示顶层评论
+}
+
+// GrowthCommentResponse 成长圈评论；公开列表把回复嵌套在被回复的评论下。
+type GrowthCommentResponse struct {
+	ID         uint                    `json:"id" example:"9"`                                       // 评论ID
+	PostID     uint                    `json:"post_id" example:"1"`                                  // 动态ID
+	ParentID   *uint                   `json:"parent_id,omitempty" example:"8"`                      // 回复的评论ID
+	Body       string                  `json:"body" example:"恭喜！经验能分享一下吗？"`                          // 评论内容
+	Status     string                  `json:"status" example:"approved"`                            // 状态：pending/approved/rejected
+	UserID     uint                    `json:"user_id" example:"3"`                                  // 评论用户ID
+	UserName   string                  `json:"user_name" example:"张三"`                               // 评论用户姓名
+	UserRole   string                  `json:"user_role" example:"employee"`                         // 评论用户角色
+	CreatedAt  time.Time               `json:"created_at" example:"2024-01-01T12:00:00Z"`            // 评论时间
+	ApprovedAt *time.Time              `json:"approved_at,omitempty" example:"2024-01-01T13:00:00Z"` // 审核通过时间
+	Replies    []GrowthCommentResponse `json:"replies,omitempty"`                                    // 回复（仅公开列表返回）
+}
+
+// AdminGrowthCommentQuery 管理员成长圈评论列表查询参数。
+type AdminGrowthCommentQuery struct {
+	PostID  uint   `form:"post_id" example:"1"`                                                          // 动态ID
+	Keyword string `form:"keyword" example:"经验"`                                                         // 搜索关键词
+	Status  string `form:"status" binding:"omitempty,oneof=pending approved rejected" example:"pending"` // 状态过滤
+	PageQuery
+}
+
+// GrowthCommentListResponse 成长圈评论分页结果：公开列表按顶层评论游标分页，管理后台按页码分页。
+type GrowthCommentListResponse struct {
+	Items      []GrowthCommentResponse `json:"items"`
+	Pagination Pagination              `json:"pagination"`
+}
+
+// GrowthShareRequest 记录一次分享。
+type GrowthShareRequest struct {
+	Channel string `json:"channel" binding:"required,oneof=wechat_friend wechat_moments link" example:"wechat_friend"` // 分享渠道：wechat_friend(微信好友) wechat_moments(朋友圈) link(复制链接)
+}
+
+// GrowthShareResponse 分享后的分享人数。
+type GrowthShareResponse struct {
+	PostID     uint  `json:"post_id" example:"1"`     // 动态ID
+	ShareCount int64 `json:"share_count" example:"6"` // 分享人数，同一用户多次分享只计一次
+}
+
+// ResubmitGrowthPostRequest 修改被拒绝的动态并重新提交审核。
+type ResubmitGrowthPostRequest struct {
+	Content    string   `json:"content" binding:"required,min=1,max=1000" example:"今天门店销售突破50万，感谢团队的努力！"` // 修改后的文本内容
+	ImagePaths []string `json:"image_paths" binding:"omitempty,max=9,dive,required"`                      // 修改后的图片路径数组，最多9张
+
+	Scope        string   `json:"scope" binding:"omitempty,oneof=company team roles region" example:"team"`  // 可见范围，为空时保持不变；region 不受支持，请使用 team
+	VisibleRoles []string `json:"visible_roles" binding:"omitempty,max=3,dive,oneof=employee manager admin"` // scope 为 roles 时可见的角色
+}
+
+// GrowthRejectRequest 管理员拒绝动态的请求体，原因代码和原因说明至少填写一项，选择 other 时必须填写说明。
+type GrowthRejectRequest struct {
+	ReasonCode string `json:"reason_code" binding:"omitempty,oneof=ad sensitive privacy irrelevant low_quality duplicate other" example:"irrelevant"` // 预设原因代码
+	Reason     string `json:"reason" binding:"omitempty,max=500" example:"请补充门店活动的具体数据"`                                                              // 原因说明
+}
+
+// GrowthRejectReasonResponse 预设的拒绝原因。
+type GrowthRejectReasonResponse struct {
+	Code  string `json:"code" example:"irrelevant"` // 原因代码
+	Label string `json:"label" example:"与工作无关"`     // 中文名称
+}
+
+// GrowthModerationEventResponse 一条动态审核历史记录。
+type GrowthModerationEventResponse struct {
+	ID          uint      `json:"id" example:"1"`                             // 记录ID
+	Action      string    `json:"action" example:"reject"`                    // 动作：submit/resubmit/auto_approve/auto_reject/auto_review/approve/reject
+	FromStatus  string    `json:"from_status" example:"pending"`              // 变更前状态，提交时为空
+	ToStatus    string    `json:"to_status" example:"rejected"`               // 变更后状态
+	ActorID     uint      `json:"actor_id" example:"1"`                       // 操作人ID，0 表示自动审核
+	ActorName   string    `json:"actor_name" example:"系统管理员"`                 // 操作人姓名
+	ReasonCode  string    `json:"reason_code,omitempty" example:"irrelevant"` // 拒绝原因代码
+	ReasonLabel string    `json:"reason_label,omitempty" example:"与工作无关"`     // 拒绝原因代码的中文名称
+	Reason      string    `json:"reason,omitempty" example:"请补充门店活动的具体数据"`    // 原因说明或自动审核说明
+	Content     string    `json:"content,omitempty" example:"今天门店销售突破50万！"`   // 提交或重新提交时的内容快照
+	ImagePaths  []string  `json:"image_paths,omitempty"`                      // 提交或重新提交时的图片快照
+	CreatedAt   time.Time `json:"created_at" example:"2024-01-01T12:00:00Z"`  // 操作时间
+}
+
+// GrowthModerationHistoryResponse 动态的当前状态与完整审核历史。
+type GrowthModerationHistoryResponse struct {
+	PostID uint                            `json:"post_id" example:"1"`      // 动态ID
+	Status string                          `json:"status" example:"pending"` // 当前状态
+	Events []GrowthModerationEventResponse `json:"events"`                   // 审核历史，按时间先后排列
+}
+
+// GrowthRejectionReportQuery 拒绝原因统计查询参数，日期按服务器时区计算。
+type GrowthRejectionReportQuery struct {
+	From     *time.Time `form:"from" time_format:"2006-01-02" example:"2024-06-01"`               // 开始日期（含），默认 30 天前
+	To       *time.Time `form:"to" time_format:"2006-01-02" example:"2024-06-30"`                 // 结束日期（含），默认今天
+	Interval string     `form:"interval" binding:"omitempty,oneof=day week month" example:"week"` // 统计粒度：day/week/month，默认 day
+}
+
+// GrowthRejectionReasonCount 某个拒绝原因的次数。
+type GrowthRejectionReasonCount struct {
+	Code  string `json:"code" example:"irrelevant"` // 原因代码，auto_moderation 表示自动审核拒绝
+	Label string `json:"label" example:"与工作无关"`     // 中文名称
+	Count int64  `json:"count" example:"3"`         // 次数
+}
+
+// GrowthRejectionPeriod 一个统计周期内的拒绝次数。
+type GrowthRejectionPeriod struct {
+	Period  string                       `json:"period" example:"2024-06-03"` // 周期起始日期，按周统计时为周一
+	Total   int64                        `json:"total" example:"5"`           // 拒绝总次数
+	Reasons []GrowthRejectionReasonCount `json:"reasons"`                     // 按原因拆分，次数多的在前
+}
+
+// GrowthRejectionReportResponse 拒绝原因统计，包含整体分布和每个周期的明细，周期连续且不省略空周期。
+type GrowthRejectionReportResponse struct {
+	From     string                       `json:"from" example:"2024-06-01"`
+	To       string                       `json:"to" example:"2024-06-30"`
+	Interval string                       `json:"interval" example:"week"`
+	Total    int64                        `json:"total" example:"12"` // 拒绝总次数
+	Reasons  []GrowthRejectionReasonCount `json:"reasons"`            // 整体原因分布，次数多的在前
+	Periods  []GrowthRejectionPeriod      `json:"periods"`
+}
+
+// GrowthTrendingQuery 热门话题查询参数。
+type GrowthTrendingQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=50" example:"10"` // 返回数量，默认 10
+}
+
+// GrowthTopicResponse 话题及其动态数。
+type GrowthTopicResponse struct {
+	Name      string `json:"name" example:"门店喜报"`    // 话题名称
+	PostCount int64  `json:"post_count" example:"8"` // 统计周期内通过审核的动态数
 }
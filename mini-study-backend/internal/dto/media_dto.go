@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// AdminMediaJobQuery filters video processing jobs.
+type AdminMediaJobQuery struct {
+	ContentID uint   `form:"content_id" binding:"omitempty,min=1" example:"12"`                                           // 内容ID
+	Status    string `form:"status" binding:"omitempty,oneof=pending running succeeded failed canceled" example:"failed"` // 状态
+	Page      int    `form:"page" binding:"omitempty,min=1" example:"1"`
+	PageSize  int    `form:"page_size" binding:"omitempty,min=1,max=100" example:"20"`
+}
+
+// MediaJobResponse 视频处理任务状态。
+type MediaJobResponse struct {
+	ID              uint       `json:"id" example:"1"`                                         // 任务ID
+	ContentID       uint       `json:"content_id" example:"12"`                                // 内容ID
+	Kind            string     `json:"kind" example:"video"`                                   // 任务类型：video视频/document文档
+	SourceKey       string     `json:"source_key" example:"private/video/3a/3a7bd3e2.mp4"`     // 源文件存储键
+	Status          string     `json:"status" example:"succeeded"`                             // 状态：pending/running/succeeded/failed/canceled
+	Attempts        int        `json:"attempts" example:"1"`                                   // 已尝试次数
+	NextRunAt       time.Time  `json:"next_run_at"`                                            // 下次可执行时间（失败重试退避）
+	Error           string     `json:"error" example:""`                                       // 最近一次失败原因
+	DurationSeconds int64      `json:"duration_seconds" example:"3600"`                        // 探测到的时长（秒）
+	Width           int        `json:"width" example:"1920"`                                   // 源视频宽度
+	Height          int        `json:"height" example:"1080"`                                  // 源视频高度
+	ThumbnailURL    string     `json:"thumbnail_url" example:"/uploads/cover/9f/9f86d081.jpg"` // 生成的缩略图
+	HLSKey          string     `json:"hls_key" example:"private/hls/3a7bd3e2/master.m3u8"`     // HLS 主播放列表存储键
+	PageCount       int        `json:"page_count" example:"24"`                                // 文档转换页数
+	StartedAt       *time.Time `json:"started_at"`                                             // 最近一次开始时间
+	FinishedAt      *time.Time `json:"finished_at"`                                            // 完成时间
+	CreatedAt       time.Time  `json:"created_at"`                                             // 创建时间
+}
+
+// AdminMediaJobListResponse 视频处理任务分页结果。
+type AdminMediaJobListResponse struct {
+	Items      []MediaJobResponse `json:"items"`
+	Pagination Pagination         `json:"pagination"`
+}
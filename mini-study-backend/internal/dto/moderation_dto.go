@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+// SensitiveWordQuery 敏感词列表查询参数。
+type SensitiveWordQuery struct {
+	Keyword  string `form:"keyword" example:"微信"`                                          // 按敏感词模糊匹配
+	Category string `form:"category" example:"ad"`                                         // 分类过滤
+	Level    string `form:"level" binding:"omitempty,oneof=block review" example:"review"` // 处置级别过滤
+	PageQuery
+}
+
+// SensitiveWordRequest 新增或修改敏感词请求体。
+type SensitiveWordRequest struct {
+	Word     string `json:"word" binding:"required,max=64" example:"加微信"`                 // 敏感词，匹配时忽略大小写、全半角和标点空格
+	Category string `json:"category" binding:"omitempty,max=32" example:"ad"`             // 分类，如 ad/fraud/gambling/abuse/privacy
+	Level    string `json:"level" binding:"required,oneof=block review" example:"review"` // 处置级别：block 直接拒绝，review 转人工复核
+}
+
+// SensitiveWordResponse 敏感词。
+type SensitiveWordResponse struct {
+	ID        uint      `json:"id" example:"1"`                            // 敏感词ID
+	Word      string    `json:"word" example:"加微信"`                        // 敏感词
+	Category  string    `json:"category" example:"ad"`                     // 分类
+	Level     string    `json:"level" example:"review"`                    // 处置级别
+	CreatedAt time.Time `json:"created_at" example:"2024-01-01T12:00:00Z"` // 创建时间
+	UpdatedAt time.Time `json:"updated_at" example:"2024-01-01T12:00:00Z"` // 更新时间
+}
+
+// SensitiveWordListResponse 敏感词分页结果。
+type SensitiveWordListResponse struct {
+	Items      []SensitiveWordResponse `json:"items"`
+	Pagination Pagination              `json:"pagination"`
+}
+
+// ModerationCheckRequest 试运行自动审核的请求体，不会保存任何数据。
+type ModerationCheckRequest struct {
+	Content    string   `json:"content" binding:"required,max=1000" example:"想了解详情请加微信"` // 待检测文本
+	ImagePaths []string `json:"image_paths" binding:"omitempty,max=9,dive,required"`     // 待检测图片路径
+}
+
+// ModerationMatchResponse 一处敏感词命中。
+type ModerationMatchResponse struct {
+	Word     string `json:"word" example:"加微信"`     // 词典中的敏感词
+	Text     string `json:"text" example:"加 微信"`    // 原文中命中的片段，可能夹杂用于规避检测的符号
+	Category string `json:"category" example:"ad"`  // 分类
+	Level    string `json:"level" example:"review"` // 处置级别
+}
+
+// ModerationResultResponse 自动审核结果。
+type ModerationResultResponse struct {
+	Verdict   string                    `json:"verdict" example:"review"`                   // 结论：pass 自动通过/review 转人工/block 自动拒绝
+	Matches   []ModerationMatchResponse `json:"matches"`                                    // 命中的敏感词
+	Labels    []string                  `json:"labels,omitempty"`                           // 外部审核服务返回的标签
+	Note      string                    `json:"note,omitempty" example:"命中敏感词：加微信"`         // 审核说明
+	Highlight string                    `json:"highlight" example:"想了解详情请<mark>加微信</mark>"` // HTML 转义后的文本，命中片段以 <mark> 标记
+}
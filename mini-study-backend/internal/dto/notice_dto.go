@@ -22,6 +22,12 @@ type AdminUpdateNoticeRequest struct {
 
 type AdminListNoticeQuery struct {
 	Status *bool `form:"status"`
+	PageQuery
+}
+
+type NoticeListResponse struct {
+	Items      []NoticeResponse `json:"items"`
+	Pagination Pagination       `json:"pagination"`
 }
 
 type NoticeResponse struct {
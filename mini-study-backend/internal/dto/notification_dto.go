@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+// NotificationListQuery 站内通知列表查询参数。
+type NotificationListQuery struct {
+	UnreadOnly bool `form:"unread_only" example:"true"` // 为 true 时只返回未读通知
+	CursorQuery
+}
+
+// NotificationResponse 站内通知。
+type NotificationResponse struct {
+	ID         uint       `json:"id" example:"1"`                                   // 通知ID
+	Type       string     `json:"type" example:"growth_like"`                       // 类型：growth_like/growth_comment/growth_reply/growth_share/growth_approved/growth_rejected/growth_featured
+	ActorID    uint       `json:"actor_id" example:"3"`                             // 触发通知的用户ID
+	ActorName  string     `json:"actor_name" example:"张三"`                          // 触发通知的用户姓名
+	EntityType string     `json:"entity_type" example:"growth_posts"`               // 关联实体类型
+	EntityID   uint       `json:"entity_id" example:"1"`                            // 关联实体ID
+	Summary    string     `json:"summary" example:"张三 赞了你的动态"`                      // 通知摘要
+	Read       bool       `json:"read" example:"false"`                             // 是否已读
+	ReadAt     *time.Time `json:"read_at,omitempty" example:"2024-01-01T13:00:00Z"` // 已读时间
+	CreatedAt  time.Time  `json:"created_at" example:"2024-01-01T12:00:00Z"`        // 通知时间
+}
+
+// NotificationListResponse 站内通知分页结果，通过 pagination.next_cursor 翻页。
+type NotificationListResponse struct {
+	Items       []NotificationResponse `json:"items"`
+	UnreadCount int64                  `json:"unread_count" example:"4"` // 未读通知总数
+	Pagination  Pagination             `json:"pagination"`
+}
+
+// NotificationReadAllResponse 全部已读的结果。
+type NotificationReadAllResponse struct {
+	Updated int64 `json:"updated" example:"4"` // 本次标记为已读的通知数
+}
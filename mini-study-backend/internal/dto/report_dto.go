@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+// ReportDefinition describes a report that can be exported or delivered by email.
+type ReportDefinition struct {
+	Key         string   `json:"key" example:"team_learning"`
+	Name        string   `json:"name" example:"团队学习汇总"`
+	Description string   `json:"description"`
+	Columns     []string `json:"columns"`
+}
+
+// ReportExportQuery selects the format and, for admins, the team of a report. Without
+// manager_id an admin exports every user; managers always export their own team.
+type ReportExportQuery struct {
+	Format    string `form:"format" binding:"omitempty,oneof=xlsx csv" example:"xlsx"`
+	ManagerID uint   `form:"manager_id" binding:"omitempty,min=1" example:"2"`
+}
+
+// ReportScheduleRequest creates or replaces a report delivery schedule. Weekday applies to
+// weekly schedules (1 = Monday, 7 = Sunday) and month_day to monthly ones.
+type ReportScheduleRequest struct {
+	Report    string `json:"report" binding:"required,oneof=team_learning exam_results points_ranking" example:"team_learning"`
+	Format    string `json:"format" binding:"omitempty,oneof=xlsx csv" example:"xlsx"`
+	Recipient string `json:"recipient" binding:"required,email,max=255" example:"manager@example.com"`
+	Frequency string `json:"frequency" binding:"required,oneof=daily weekly monthly" example:"weekly"`
+	Weekday   int    `json:"weekday" binding:"omitempty,min=1,max=7" example:"1"`
+	MonthDay  int    `json:"month_day" binding:"omitempty,min=1,max=28" example:"1"`
+	Hour      int    `json:"hour" binding:"min=0,max=23" example:"8"`
+	ManagerID uint   `json:"manager_id" binding:"omitempty,min=1" example:"2"`
+	Enabled   *bool  `json:"enabled" example:"true"`
+}
+
+// ReportScheduleResponse is a delivery schedule with the result of its last run.
+type ReportScheduleResponse struct {
+	ID             uint       `json:"id"`
+	OwnerID        uint       `json:"owner_id"`
+	Report         string     `json:"report"`
+	ReportName     string     `json:"report_name"`
+	Format         string     `json:"format"`
+	Recipient      string     `json:"recipient"`
+	Frequency      string     `json:"frequency"`
+	Weekday        int        `json:"weekday"`
+	MonthDay       int        `json:"month_day"`
+	Hour           int        `json:"hour"`
+	ManagerID      uint       `json:"manager_id"`
+	ManagerName    string     `json:"manager_name,omitempty"`
+	Enabled        bool       `json:"enabled"`
+	NextRunAt      *time.Time `json:"next_run_at"`
+	LastRunAt      *time.Time `json:"last_run_at"`
+	LastStatus     string     `json:"last_status"`
+	LastError      string     `json:"last_error,omitempty"`
+	FailedAttempts int        `json:"failed_attempts"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// ReportScheduleListResponse lists delivery schedules.
+type ReportScheduleListResponse struct {
+	Items []ReportScheduleResponse `json:"items"`
+}
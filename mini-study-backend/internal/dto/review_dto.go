@@ -0,0 +1,81 @@
+package dto
+
+import "time"
+
+// ReviewSubmitRequest 提交审核，可同时指定审核人。
+type ReviewSubmitRequest struct {
+	ReviewerID uint   `json:"reviewer_id" example:"1"`                                // 指定审核人ID，不能是提交人自己；不填沿用已指定的审核人，均未指定时除提交人外任意管理员可审核
+	Comment    string `json:"comment" binding:"omitempty,max=1000" example:"请审核新品培训"` // 提交说明
+}
+
+// ReviewAssignRequest 指派审核人。
+type ReviewAssignRequest struct {
+	ReviewerID uint `json:"reviewer_id" binding:"required" example:"1"` // 审核人ID，须为管理员
+}
+
+// ReviewApproveRequest 审核通过。
+type ReviewApproveRequest struct {
+	Comment string `json:"comment" binding:"omitempty,max=1000" example:"内容准确"` // 审核意见
+}
+
+// ReviewRejectRequest 驳回审核，退回草稿。
+type ReviewRejectRequest struct {
+	Reason string `json:"reason" binding:"required,min=1,max=500" example:"第二节数据需要更新"` // 驳回原因
+}
+
+// ReviewCommentRequest 审核评论。
+type ReviewCommentRequest struct {
+	Comment string `json:"comment" binding:"required,min=1,max=1000" example:"封面图片分辨率偏低"` // 评论内容
+}
+
+// ReviewQueueQuery 审核列表筛选条件。
+type ReviewQueueQuery struct {
+	EntityType string `form:"entity_type" binding:"omitempty,oneof=content exam" example:"content"`          // 实体类型，为空返回全部
+	Status     string `form:"status" binding:"omitempty,oneof=draft in_review approved" example:"in_review"` // 审核状态，默认 in_review
+	ReviewerID uint   `form:"reviewer_id" example:"1"`                                                       // 指定审核人ID
+	Mine       bool   `form:"mine" example:"true"`                                                           // 只看指派给我的
+
+	PageQuery
+}
+
+// ReviewItemResponse 实体的审核状态。
+type ReviewItemResponse struct {
+	EntityType    string     `json:"entity_type" example:"content"`     // 实体类型：content/exam
+	EntityID      uint       `json:"entity_id" example:"12"`            // 实体ID
+	Title         string     `json:"title" example:"新品培训"`              // 标题
+	Status        string     `json:"status" example:"in_review"`        // 当前状态
+	ReviewerID    uint       `json:"reviewer_id" example:"1"`           // 指定审核人ID，0 表示任意管理员
+	ReviewerName  string     `json:"reviewer_name" example:"系统管理员"`     // 指定审核人姓名
+	SubmittedBy   uint       `json:"submitted_by" example:"1"`          // 提交人ID
+	SubmitterName string     `json:"submitter_name" example:"系统管理员"`    // 提交人姓名
+	SubmittedAt   *time.Time `json:"submitted_at"`                      // 提交时间
+	ReviewedBy    uint       `json:"reviewed_by" example:"1"`           // 最近审核人ID
+	ReviewedAt    *time.Time `json:"reviewed_at"`                       // 最近审核时间
+	RejectReason  string     `json:"reject_reason" example:"第二节数据需要更新"` // 最近一次驳回原因
+	UpdatedAt     time.Time  `json:"updated_at"`                        // 更新时间
+}
+
+// ReviewEventResponse 一条审核流转记录。
+type ReviewEventResponse struct {
+	ID         uint      `json:"id" example:"1"`                  // 记录ID
+	Action     string    `json:"action" example:"reject"`         // 动作：submit/withdraw/assign/approve/reject/comment/publish/offline/revert
+	FromStatus string    `json:"from_status" example:"in_review"` // 变更前状态
+	ToStatus   string    `json:"to_status" example:"draft"`       // 变更后状态
+	ActorID    uint      `json:"actor_id" example:"1"`            // 操作人ID，0 表示定时任务
+	ActorName  string    `json:"actor_name" example:"系统管理员"`      // 操作人姓名
+	ReviewerID uint      `json:"reviewer_id" example:"1"`         // 操作时的指定审核人ID
+	Comment    string    `json:"comment" example:"第二节数据需要更新"`     // 评论或原因
+	CreatedAt  time.Time `json:"created_at"`                      // 操作时间
+}
+
+// ReviewQueueResponse 审核列表分页结果。
+type ReviewQueueResponse struct {
+	Items      []ReviewItemResponse `json:"items"`
+	Pagination Pagination           `json:"pagination"`
+}
+
+// ReviewDetailResponse 审核状态与完整流转记录。
+type ReviewDetailResponse struct {
+	ReviewItemResponse
+	History []ReviewEventResponse `json:"history"` // 流转记录，按时间先后排序
+}
@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+// AdminScheduleRequest 设置定时发布与定时下线。两个时间每次一并提交，为空表示取消对应计划。
+type AdminScheduleRequest struct {
+	PublishAt *time.Time `json:"publish_at" example:"2024-06-01T09:00:00+08:00"` // 定时发布时间
+	OfflineAt *time.Time `json:"offline_at" example:"2024-06-30T18:00:00+08:00"` // 定时下线（考试为归档）时间
+}
+
+// ScheduleStateResponse 实体当前的状态与发布计划。
+type ScheduleStateResponse struct {
+	EntityType         string     `json:"entity_type" example:"content"`                            // 实体类型：content/exam
+	EntityID           uint       `json:"entity_id" example:"12"`                                   // 实体ID
+	Title              string     `json:"title" example:"新品培训"`                                     // 标题
+	Status             string     `json:"status" example:"draft"`                                   // 当前状态
+	ScheduledPublishAt *time.Time `json:"scheduled_publish_at" example:"2024-06-01T09:00:00+08:00"` // 定时发布时间
+	ScheduledOfflineAt *time.Time `json:"scheduled_offline_at" example:"2024-06-30T18:00:00+08:00"` // 定时下线时间
+}
+
+// AdminScheduleCalendarQuery 发布日历查询范围，日期按服务器时区计算。
+type AdminScheduleCalendarQuery struct {
+	From       *time.Time `form:"from" time_format:"2006-01-02" example:"2024-06-01"`                   // 开始日期（含），默认今天
+	To         *time.Time `form:"to" time_format:"2006-01-02" example:"2024-06-30"`                     // 结束日期（含），默认开始日期后 30 天
+	EntityType string     `form:"entity_type" binding:"omitempty,oneof=content exam" example:"content"` // 实体类型
+}
+
+// ScheduleEvent 一次计划中的状态变更。
+type ScheduleEvent struct {
+	At         time.Time `json:"at"`                            // 计划执行时间
+	Action     string    `json:"action" example:"publish"`      // 动作：publish发布/offline下线（考试为归档）
+	EntityType string    `json:"entity_type" example:"content"` // 实体类型：content/exam
+	EntityID   uint      `json:"entity_id" example:"12"`        // 实体ID
+	Title      string    `json:"title" example:"新品培训"`          // 标题
+	Status     string    `json:"status" example:"draft"`        // 当前状态
+}
+
+// ScheduleCalendarDay 某一天的计划变更。
+type ScheduleCalendarDay struct {
+	Date   string          `json:"date" example:"2024-06-01"`
+	Events []ScheduleEvent `json:"events"`
+}
+
+// AdminScheduleCalendarResponse 按日期分组的发布日历，只包含有计划的日期。
+type AdminScheduleCalendarResponse struct {
+	From string                `json:"from" example:"2024-06-01"`
+	To   string                `json:"to" example:"2024-06-30"`
+	Days []ScheduleCalendarDay `json:"days"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+// SearchQuery 全站搜索参数，kind/type/category_id/role 为可选的分面筛选。
+type SearchQuery struct {
+	Q          string `form:"q" binding:"required,max=100" example:"门店陈列"`                          // 搜索关键词
+	Kind       string `form:"kind" binding:"omitempty,oneof=content exam growth" example:"content"` // 结果类别：content学习内容/exam考试/growth成长圈
+	Type       string `form:"type" binding:"omitempty,oneof=doc video article" example:"video"`     // 内容类型，仅对学习内容生效
+	CategoryID uint   `form:"category_id" example:"1"`                                              // 内容分类ID
+	Role       string `form:"role" binding:"omitempty,oneof=employee manager" example:"employee"`   // 仅返回该角色可见的结果
+	Page       int    `form:"page" binding:"omitempty,min=1" example:"1"`
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=50" example:"20"`
+}
+
+// SearchHit 单条搜索结果，title_highlight 与 snippet 为已转义的 HTML，命中词以 <em> 标记。
+type SearchHit struct {
+	Kind           string    `json:"kind" example:"content"`                    // 结果类别
+	ID             uint      `json:"id" example:"12"`                           // 对应实体ID
+	Title          string    `json:"title" example:"门店陈列规范"`                    // 标题（成长圈为发布者姓名）
+	TitleHighlight string    `json:"title_highlight" example:"<em>门店陈列</em>规范"` // 高亮标题
+	Snippet        string    `json:"snippet" example:"…新品上市时的<em>门店陈列</em>要求…"` // 高亮正文摘要
+	Type           string    `json:"type,omitempty" example:"doc"`              // 内容类型
+	CategoryID     uint      `json:"category_id,omitempty" example:"1"`         // 内容分类ID
+	CategoryName   string    `json:"category_name,omitempty" example:"员工学习"`    // 内容分类名称
+	Score          float64   `json:"score" example:"3.82"`                      // 相关度得分
+	CreatedAt      time.Time `json:"created_at"`                                // 创建时间
+}
+
+// SearchFacet 分面统计项。
+type SearchFacet struct {
+	Value string `json:"value" example:"video"`        // 筛选值
+	Label string `json:"label,omitempty" example:"视频"` // 展示名称
+	Count int    `json:"count" example:"3"`            // 命中数量
+}
+
+// SearchResponse 搜索结果，facets 按 kind/type/category_id/role 统计全部命中结果。
+type SearchResponse struct {
+	Items      []SearchHit              `json:"items"`
+	Facets     map[string][]SearchFacet `json:"facets"`
+	Pagination Pagination               `json:"pagination"`
+}
+
+// SearchReindexResponse 重建索引结果。
+type SearchReindexResponse struct {
+	Documents  int   `json:"documents" example:"128"`  // 已索引文档数
+	DurationMS int64 `json:"duration_ms" example:"42"` // 耗时（毫秒）
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // RegisterRequest represents the registration payload.
 // manager_ids 中放的是店长的 work_no，而不是数值 ID。
 type RegisterRequest struct {
@@ -26,6 +28,17 @@ type UserResponse struct {
 	Status bool   `json:"status" example:"true"`       // 状态：true(启用) false(禁用)
 }
 
+// ManagerListQuery pages the active managers offered at registration.
+type ManagerListQuery struct {
+	PageQuery
+}
+
+// ManagerListResponse is a page of active managers.
+type ManagerListResponse struct {
+	Items      []UserResponse `json:"items"`
+	Pagination Pagination     `json:"pagination"`
+}
+
 // TokenResponse contains JWT pair.
 type TokenResponse struct {
 	AccessToken  string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`  // 访问令牌
@@ -70,6 +83,13 @@ type AdminUpdateEmployeeManagersRequest struct {
 type AdminListUsersQuery struct {
 	Role    string `form:"role" binding:"omitempty,oneof=employee manager admin" example:"employee"` // 角色过滤
 	Keyword string `form:"keyword" binding:"omitempty,max=100" example:"张三"`                         // 关键词（工号/姓名/手机号）
+	PageQuery
+}
+
+// AdminUserListResponse is a page of users in the admin panel.
+type AdminUserListResponse struct {
+	Items      []AdminUserResponse `json:"items"`
+	Pagination Pagination          `json:"pagination"`
 }
 
 // ManagerBrief 提供店长的简要信息。
@@ -83,12 +103,36 @@ type ManagerBrief struct {
 // AdminUserResponse 返回给管理后台的用户信息。
 type AdminUserResponse struct {
 	UserResponse
-	ManagerIDs []uint         `json:"manager_ids"` // 绑定的店长ID列表
-	Managers   []ManagerBrief `json:"managers"`    // 店长详情
-	Points     int64          `json:"points"`      // 积分总数
+	ManagerIDs   []uint         `json:"manager_ids"`             // 绑定的店长ID列表
+	Managers     []ManagerBrief `json:"managers"`                // 店长详情
+	Points       int64          `json:"points"`                  // 积分总数
+	OffboardedAt *time.Time     `json:"offboarded_at,omitempty"` // 离职时间，非空表示已离职并匿名化
 }
 
 // AdminUpdateUserRoleRequest updates user role.
 type AdminUpdateUserRoleRequest struct {
 	Role string `json:"role" binding:"required,oneof=employee manager admin" example:"manager"` // 目标角色
 }
+
+// AdminUserLifecycleRequest carries the reason for deactivating, reactivating or offboarding a user.
+type AdminUserLifecycleRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=255" example:"长期休假"` // 操作原因
+}
+
+// AdminTransferEmployeeRequest moves an employee to other managers.
+// manager_ids 中放的是店长的 work_no，而不是数值 ID。
+type AdminTransferEmployeeRequest struct {
+	ManagerWorkNos []string `json:"manager_ids" binding:"required,min=1,dive,required" example:"M002"` // 调入店长工号列表
+	Reason         string   `json:"reason" binding:"omitempty,max=255" example:"门店调整"`                 // 调动原因
+}
+
+// EmployeeTransferResponse 员工调动记录。
+type EmployeeTransferResponse struct {
+	ID           uint           `json:"id" example:"1"`           // 记录ID
+	EmployeeID   uint           `json:"employee_id" example:"10"` // 员工ID
+	FromManagers []ManagerBrief `json:"from_managers"`            // 调动前店长
+	ToManagers   []ManagerBrief `json:"to_managers"`              // 调动后店长
+	OperatorID   uint           `json:"operator_id" example:"1"`  // 操作人ID
+	Reason       string         `json:"reason" example:"门店调整"`    // 调动原因
+	CreatedAt    time.Time      `json:"created_at"`               // 调动时间
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// AnalyticsHandler 处理学习数据看板接口。
+type AnalyticsHandler struct {
+	analytics *service.AnalyticsService
+}
+
+// NewAnalyticsHandler 创建学习数据看板处理器。
+func NewAnalyticsHandler(analytics *service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{analytics: analytics}
+}
+
+// AdminLearningAnalytics godoc
+// @Summary 学习数据看板
+// @Description 按日、周（周一开始）或月返回活跃学员、内容完成数、考试通过率、平均观看时长与积分的连续时间序列，可按角色或店长团队分组对比；管理员账号不计入
+// @Tags 管理后台-数据看板
+// @Security Bearer
+// @Produce json
+// @Param from query string false "开始日期（含），格式 2006-01-02，默认 30 天前"
+// @Param to query string false "结束日期（含），格式 2006-01-02，默认今天"
+// @Param interval query string false "统计粒度 day/week/month，默认 day"
+// @Param group_by query string false "分组对比 role/manager，为空不分组"
+// @Param role query string false "只统计该角色 employee/manager"
+// @Param manager_id query int false "只统计该店长及其名下员工"
+// @Success 200 {object} utils.Response{data=dto.LearningAnalyticsResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/analytics/learning [get]
+func (h *AnalyticsHandler) AdminLearningAnalytics(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var query dto.LearningAnalyticsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.analytics.AdminLearningAnalytics(adminID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// AuditHandler exposes audit log query endpoints for admins.
+type AuditHandler struct {
+	audit *service.AuditService
+}
+
+// NewAuditHandler builds an AuditHandler.
+func NewAuditHandler(audit *service.AuditService) *AuditHandler {
+	return &AuditHandler{audit: audit}
+}
+
+// AdminListAuditLogs godoc
+// @Summary 管理员查询审计日志
+// @Description 按操作者、动作、目标、结果、请求ID与时间范围筛选审计日志，按时间倒序分页返回
+// @Tags 管理后台-审计
+// @Security Bearer
+// @Produce json
+// @Param actor_id query int false "操作者ID"
+// @Param action query string false "操作动作"
+// @Param target query string false "操作目标"
+// @Param result query string false "操作结果"
+// @Param request_id query string false "请求ID"
+// @Param entity_type query string false "实体类型(content/exam/banner/notice/user/user_point/content_checkpoint/certificate_template/certification)"
+// @Param entity_id query int false "实体ID"
+// @Param start_at query string false "开始时间(RFC3339)"
+// @Param end_at query string false "结束时间(RFC3339，不含)"
+// @Param page query int false "页码，从1开始"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Success 200 {object} utils.Response{data=dto.AdminAuditLogListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/audit-logs [get]
+func (h *AuditHandler) AdminListAuditLogs(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var query dto.AdminAuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	logs, err := h.audit.AdminSearch(adminID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(logs).JSON(c)
+}
+
+// AdminExportAuditLogs godoc
+// @Summary 管理员导出审计日志
+// @Description 按与查询接口相同的条件和顺序（时间倒序）导出 CSV 文件（单次最多 100000 条）
+// @Tags 管理后台-审计
+// @Security Bearer
+// @Produce text/csv
+// @Param actor_id query int false "操作者ID"
+// @Param action query string false "操作动作"
+// @Param target query string false "操作目标"
+// @Param result query string false "操作结果"
+// @Param request_id query string false "请求ID"
+// @Param entity_type query string false "实体类型(content/exam/banner/notice/user/user_point/content_checkpoint)"
+// @Param entity_id query int false "实体ID"
+// @Param start_at query string false "开始时间(RFC3339)"
+// @Param end_at query string false "结束时间(RFC3339，不含)"
+// @Success 200 {file} file
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/audit-logs/export [get]
+func (h *AuditHandler) AdminExportAuditLogs(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var query dto.AdminAuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	filename := fmt.Sprintf("audit-logs-%s.csv", time.Now().Format("20060102150405"))
+	w := utils.NewAttachmentWriter(c, filename, "text/csv; charset=utf-8")
+	if err := h.audit.AdminExportCSV(adminID, query, w); err != nil {
+		if !w.Started() {
+			utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+			return
+		}
+		_ = c.Error(err)
+	}
+}
+
+// AdminEntityHistory godoc
+// @Summary 管理员查看实体变更历史
+// @Description 返回单个实体（内容、试卷、轮播图、公告、用户、积分、随堂测验、证书模板、认证）的审计记录及字段级变更，按时间倒序分页；未知的实体类型返回 400
+// @Tags 管理后台-审计
+// @Security Bearer
+// @Produce json
+// @Param entity_type path string true "实体类型(content/exam/banner/notice/user/user_point/content_checkpoint)"
+// @Param entity_id path int true "实体ID"
+// @Param page query int false "页码，从1开始"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Success 200 {object} utils.Response{data=dto.AdminAuditLogListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/audit-logs/entities/{entity_type}/{entity_id} [get]
+func (h *AuditHandler) AdminEntityHistory(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	entityID, err := strconv.ParseUint(c.Param("entity_id"), 10, 64)
+	if err != nil || entityID == 0 {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的实体ID").JSON(c)
+		return
+	}
+
+	var query dto.AuditEntityHistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	logs, err := h.audit.AdminEntityHistory(adminID, c.Param("entity_type"), uint(entityID), query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(logs).JSON(c)
+}
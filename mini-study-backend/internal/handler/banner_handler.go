@@ -25,11 +25,15 @@ func NewBannerHandler(service *service.BannerService) *BannerHandler {
 
 // ListVisibleBanners godoc
 // @Summary 查询可见轮播图
-// @Description 返回当前登录用户基于角色可见的轮播图列表
+// @Description 分页返回当前登录用户基于角色可见的轮播图列表
 // @Tags 轮播
 // @Security Bearer
 // @Produce json
-// @Success 200 {object} utils.Response{data=[]dto.BannerResponse}
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 sort_order/start_at/created_at/id，默认 sort_order"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.BannerListResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Router /api/v1/banners [get]
@@ -40,22 +44,32 @@ func (h *BannerHandler) ListVisibleBanners(c *gin.Context) {
 		return
 	}
 
-	banners, err := h.service.ListVisible(userID)
+	var query dto.BannerQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	banners, page, err := h.service.ListVisible(userID, query)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
 	}
-	utils.NewSuccessResponse(h.toResponses(banners)).JSON(c)
+	utils.NewSuccessResponse(dto.BannerListResponse{Items: h.toResponses(banners), Pagination: page}).JSON(c)
 }
 
 // AdminListBanners godoc
 // @Summary 管理员查询轮播图列表
-// @Description 管理员可按状态筛选轮播图
+// @Description 管理员可按状态筛选轮播图，分页返回，默认按排序序号升序
 // @Tags 管理后台-轮播
 // @Security Bearer
 // @Produce json
 // @Param status query bool false "是否启用 true/false"
-// @Success 200 {object} utils.Response{data=[]dto.BannerResponse}
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 sort_order/start_at/created_at/id，默认 sort_order"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.BannerListResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Router /api/v1/admin/banners [get]
@@ -72,12 +86,12 @@ func (h *BannerHandler) AdminListBanners(c *gin.Context) {
 		return
 	}
 
-	banners, err := h.service.AdminListBanners(adminID, query.Status)
+	banners, page, err := h.service.AdminListBanners(adminID, query)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
 	}
-	utils.NewSuccessResponse(h.toResponses(banners)).JSON(c)
+	utils.NewSuccessResponse(dto.BannerListResponse{Items: h.toResponses(banners), Pagination: page}).JSON(c)
 }
 
 // AdminCreateBanner godoc
@@ -105,7 +119,7 @@ func (h *BannerHandler) AdminCreateBanner(c *gin.Context) {
 		return
 	}
 
-	banner, err := h.service.AdminCreateBanner(adminID, req)
+	banner, err := h.service.AdminCreateBanner(c.Request.Context(), adminID, req)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -146,7 +160,7 @@ func (h *BannerHandler) AdminUpdateBanner(c *gin.Context) {
 		return
 	}
 
-	banner, err := h.service.AdminUpdateBanner(adminID, uint(bannerID), req)
+	banner, err := h.service.AdminUpdateBanner(c.Request.Context(), adminID, uint(bannerID), req)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// CertificateHandler 处理考试证书的下载、验证与模板管理接口。
+type CertificateHandler struct {
+	certificates *service.CertificateService
+}
+
+// NewCertificateHandler 创建证书处理器。
+func NewCertificateHandler(certificates *service.CertificateService) *CertificateHandler {
+	return &CertificateHandler{certificates: certificates}
+}
+
+// ListMine godoc
+// @Summary 我的证书
+// @Description 返回当前用户已获得的考试证书
+// @Tags 证书
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=dto.CertificateListResponse}
+// @Failure 401 {object} utils.Response
+// @Router /api/v1/certificates/my [get]
+func (h *CertificateHandler) ListMine(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	resp, err := h.certificates.ListMine(userID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// Download godoc
+// @Summary 下载考试证书
+// @Description 下载某次通过的考试的 PDF 证书，尚未生成时按当前模板生成；持证人本人、其店长与管理员可下载
+// @Tags 证书
+// @Security Bearer
+// @Produce application/pdf
+// @Param attempt_id path int true "考试记录ID"
+// @Success 200 {file} file
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/certificates/attempts/{attempt_id}/download [get]
+func (h *CertificateHandler) Download(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	attemptID, err := parseIDParam(c.Param("attempt_id"))
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的考试记录ID").JSON(c)
+		return
+	}
+
+	body, info, issued, err := h.certificates.Open(c.Request.Context(), userID, attemptID)
+	if err != nil {
+		utils.NewErrorResponse(certificateAccessStatus(err), err.Error()).JSON(c)
+		return
+	}
+	defer body.Close()
+
+	c.Header("Cache-Control", "private, max-age=0")
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "certificate-"+issued.Serial+".pdf"))
+	c.Header("Content-Length", strconv.FormatInt(info.Size, 10))
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, body)
+}
+
+// certificateAccessStatus maps Open errors to HTTP statuses.
+func certificateAccessStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrCertificateAttemptNotFound), errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrCertificateForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Verify godoc
+// @Summary 验证证书编号
+// @Description 公开接口，确认证书编号是否为系统颁发的真实证书；持证人姓名与工号部分隐藏
+// @Tags 证书
+// @Produce json
+// @Param serial query string true "证书编号"
+// @Success 200 {object} utils.Response{data=dto.CertificateVerifyResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/certificates/verify [get]
+func (h *CertificateHandler) Verify(c *gin.Context) {
+	resp, err := h.certificates.Verify(c.Query("serial"))
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminListTemplates godoc
+// @Summary 证书模板列表
+// @Description 返回全部证书模板及正文可用的占位符
+// @Tags 证书
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=dto.CertificateTemplateListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/certificate-templates [get]
+func (h *CertificateHandler) AdminListTemplates(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	resp, err := h.certificates.ListTemplates(adminID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminCreateTemplate godoc
+// @Summary 创建证书模板
+// @Description exam_id 绑定试卷，为 0 时作为默认模板；每张试卷与默认模板各只能有一个启用的模板
+// @Tags 证书
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.CertificateTemplateRequest true "证书模板"
+// @Success 200 {object} utils.Response{data=dto.CertificateTemplateResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/certificate-templates [post]
+func (h *CertificateHandler) AdminCreateTemplate(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var req dto.CertificateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.certificates.CreateTemplate(c.Request.Context(), adminID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminUpdateTemplate godoc
+// @Summary 修改证书模板
+// @Description 只影响之后颁发的证书，已颁发的证书保持原样
+// @Tags 证书
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "模板ID"
+// @Param request body dto.CertificateTemplateRequest true "证书模板"
+// @Success 200 {object} utils.Response{data=dto.CertificateTemplateResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/certificate-templates/{id} [put]
+func (h *CertificateHandler) AdminUpdateTemplate(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	templateID, err := parseIDParam(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的模板ID").JSON(c)
+		return
+	}
+
+	var req dto.CertificateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.certificates.UpdateTemplate(c.Request.Context(), adminID, templateID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminDeleteTemplate godoc
+// @Summary 删除证书模板
+// @Tags 证书
+// @Security Bearer
+// @Produce json
+// @Param id path int true "模板ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/certificate-templates/{id} [delete]
+func (h *CertificateHandler) AdminDeleteTemplate(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	templateID, err := parseIDParam(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的模板ID").JSON(c)
+		return
+	}
+
+	if err := h.certificates.DeleteTemplate(c.Request.Context(), adminID, templateID); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(nil).JSON(c)
+}
+
+// AdminPreviewTemplate godoc
+// @Summary 预览证书模板
+// @Description 以示例数据生成 PDF 预览
+// @Tags 证书
+// @Security Bearer
+// @Produce application/pdf
+// @Param id path int true "模板ID"
+// @Success 200 {file} file
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/certificate-templates/{id}/preview [get]
+func (h *CertificateHandler) AdminPreviewTemplate(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	templateID, err := parseIDParam(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的模板ID").JSON(c)
+		return
+	}
+
+	w := utils.NewAttachmentWriter(c, fmt.Sprintf("certificate-template-%d.pdf", templateID), "application/pdf")
+	if err := h.certificates.PreviewTemplate(adminID, templateID, w); err != nil {
+		if !w.Started() {
+			utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+			return
+		}
+		_ = c.Error(err)
+	}
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// CertificationHandler 处理资质认证、到期状态与重新认证相关接口。
+type CertificationHandler struct {
+	certifications *service.CertificationService
+}
+
+// NewCertificationHandler 创建认证处理器。
+func NewCertificationHandler(certifications *service.CertificationService) *CertificationHandler {
+	return &CertificationHandler{certifications: certifications}
+}
+
+// ListMine godoc
+// @Summary 我的认证
+// @Description 返回当前用户需要取得或已取得的认证及其状态（valid有效/expiring即将到期/expired已过期/not_certified未认证），需处理的排在前面
+// @Tags 认证
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=dto.MyCertificationListResponse}
+// @Failure 401 {object} utils.Response
+// @Router /api/v1/certifications/my [get]
+func (h *CertificationHandler) ListMine(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	resp, err := h.certifications.ListMine(userID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// ManagerExpiring godoc
+// @Summary 团队即将到期的认证
+// @Description 列出店长名下员工在 days 天内到期或已过期的认证，按到期时间排序
+// @Tags 店长
+// @Security Bearer
+// @Produce json
+// @Param days query int false "天数，默认30"
+// @Param certification_id query int false "认证ID"
+// @Success 200 {object} utils.Response{data=dto.CertificationExpiringResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/manager/certifications/expiring [get]
+func (h *CertificationHandler) ManagerExpiring(c *gin.Context) {
+	managerID := middleware.GetUserID(c)
+	if managerID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	var query dto.CertificationExpiringQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	resp, err := h.certifications.TeamExpiring(managerID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminList godoc
+// @Summary 认证列表
+// @Description 返回全部认证及当前持有人数
+// @Tags 认证
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=dto.CertificationListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/certifications [get]
+func (h *CertificationHandler) AdminList(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	resp, err := h.certifications.List(adminID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminCreate godoc
+// @Summary 创建认证
+// @Description 绑定一张试卷，通过即取得认证，有效期从通过之日起计算；已通过该试卷的用户自动取得认证。每张试卷只能关联一个启用的认证
+// @Tags 认证
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.CertificationRequest true "认证"
+// @Success 200 {object} utils.Response{data=dto.CertificationResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/certifications [post]
+func (h *CertificationHandler) AdminCreate(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var req dto.CertificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.certifications.Create(c.Request.Context(), adminID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminUpdate godoc
+// @Summary 修改认证
+// @Description 按新的有效期重新计算到期时间；更换试卷时按新试卷的考试记录重新认定持有人
+// @Tags 认证
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "认证ID"
+// @Param request body dto.CertificationRequest true "认证"
+// @Success 200 {object} utils.Response{data=dto.CertificationResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/certifications/{id} [put]
+func (h *CertificationHandler) AdminUpdate(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	certificationID, err := parseIDParam(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的认证ID").JSON(c)
+		return
+	}
+
+	var req dto.CertificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.certifications.Update(c.Request.Context(), adminID, certificationID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminDelete godoc
+// @Summary 删除认证
+// @Description 考试记录保留
+// @Tags 认证
+// @Security Bearer
+// @Produce json
+// @Param id path int true "认证ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/certifications/{id} [delete]
+func (h *CertificationHandler) AdminDelete(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	certificationID, err := parseIDParam(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的认证ID").JSON(c)
+		return
+	}
+
+	if err := h.certifications.Delete(c.Request.Context(), adminID, certificationID); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(nil).JSON(c)
+}
+
+// AdminExpiring godoc
+// @Summary 即将到期的认证
+// @Description 列出全部用户在 days 天内到期或已过期的认证，可按认证或店长团队筛选
+// @Tags 认证
+// @Security Bearer
+// @Produce json
+// @Param days query int false "天数，默认30"
+// @Param certification_id query int false "认证ID"
+// @Param manager_id query int false "店长ID"
+// @Success 200 {object} utils.Response{data=dto.CertificationExpiringResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/certifications/expiring [get]
+func (h *CertificationHandler) AdminExpiring(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	var query dto.CertificationExpiringQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	resp, err := h.certifications.AdminExpiring(adminID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// CheckpointHandler exposes the quiz checkpoints embedded in contents.
+type CheckpointHandler struct {
+	checkpoints *service.CheckpointService
+}
+
+// NewCheckpointHandler builds a CheckpointHandler.
+func NewCheckpointHandler(checkpoints *service.CheckpointService) *CheckpointHandler {
+	return &CheckpointHandler{checkpoints: checkpoints}
+}
+
+// ListCheckpoints godoc
+// @Summary 查询内容的随堂测验
+// @Description 分页返回内容的随堂测验检查点及当前用户的作答情况，默认按位置顺序；答对后才返回正确答案与解析
+// @Tags 内容
+// @Security Bearer
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 position/created_at/id，默认 position"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.CheckpointListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/contents/{id}/checkpoints [get]
+func (h *CheckpointHandler) ListCheckpoints(c *gin.Context) {
+	userID, contentID, ok := checkpointContent(c)
+	if !ok {
+		return
+	}
+
+	var query dto.CheckpointListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	items, page, err := h.checkpoints.List(userID, contentID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(dto.CheckpointListResponse{Items: items, Pagination: page}).JSON(c)
+}
+
+// AnswerCheckpoint godoc
+// @Summary 回答随堂测验
+// @Description 提交检查点的答案，答错可重试；答对后其后的学习才计入进度，首次作答即答对时发放该检查点的积分
+// @Tags 内容
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param checkpoint_id path int true "检查点ID"
+// @Param request body dto.CheckpointAnswerRequest true "所选选项"
+// @Success 200 {object} utils.Response{data=dto.CheckpointAnswerResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/contents/{id}/checkpoints/{checkpoint_id}/answer [post]
+func (h *CheckpointHandler) AnswerCheckpoint(c *gin.Context) {
+	userID, contentID, ok := checkpointContent(c)
+	if !ok {
+		return
+	}
+	checkpointID, ok := checkpointIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CheckpointAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.checkpoints.Answer(c.Request.Context(), userID, contentID, checkpointID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminListCheckpoints godoc
+// @Summary 管理员查看内容的随堂测验
+// @Description 分页返回内容的随堂测验检查点，包含正确答案与解析，默认按位置顺序
+// @Tags 管理后台-内容
+// @Security Bearer
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 position/created_at/id，默认 position"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.CheckpointListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/contents/{id}/checkpoints [get]
+func (h *CheckpointHandler) AdminListCheckpoints(c *gin.Context) {
+	adminID, contentID, ok := checkpointContent(c)
+	if !ok {
+		return
+	}
+
+	var query dto.CheckpointListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	items, page, err := h.checkpoints.AdminList(adminID, contentID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(dto.CheckpointListResponse{Items: items, Pagination: page}).JSON(c)
+}
+
+// AdminCreateCheckpoint godoc
+// @Summary 管理员添加随堂测验
+// @Description 在视频的指定秒数、文档的指定页之后或图文的指定内容块之后添加检查点，学员须答对后才能继续学习
+// @Tags 管理后台-内容
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param request body dto.AdminCheckpointUpsertRequest true "检查点"
+// @Success 200 {object} utils.Response{data=dto.CheckpointResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/contents/{id}/checkpoints [post]
+func (h *CheckpointHandler) AdminCreateCheckpoint(c *gin.Context) {
+	adminID, contentID, ok := checkpointContent(c)
+	if !ok {
+		return
+	}
+
+	var req dto.AdminCheckpointUpsertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.checkpoints.AdminCreate(c.Request.Context(), adminID, contentID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminUpdateCheckpoint godoc
+// @Summary 管理员修改随堂测验
+// @Description 整体替换检查点的位置、题目与积分，已答对的学员保持通过
+// @Tags 管理后台-内容
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param checkpoint_id path int true "检查点ID"
+// @Param request body dto.AdminCheckpointUpsertRequest true "检查点"
+// @Success 200 {object} utils.Response{data=dto.CheckpointResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/contents/{id}/checkpoints/{checkpoint_id} [put]
+func (h *CheckpointHandler) AdminUpdateCheckpoint(c *gin.Context) {
+	adminID, contentID, ok := checkpointContent(c)
+	if !ok {
+		return
+	}
+	checkpointID, ok := checkpointIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.AdminCheckpointUpsertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.checkpoints.AdminUpdate(c.Request.Context(), adminID, contentID, checkpointID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminDeleteCheckpoint godoc
+// @Summary 管理员删除随堂测验
+// @Tags 管理后台-内容
+// @Security Bearer
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param checkpoint_id path int true "检查点ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/contents/{id}/checkpoints/{checkpoint_id} [delete]
+func (h *CheckpointHandler) AdminDeleteCheckpoint(c *gin.Context) {
+	adminID, contentID, ok := checkpointContent(c)
+	if !ok {
+		return
+	}
+	checkpointID, ok := checkpointIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.checkpoints.AdminDelete(c.Request.Context(), adminID, contentID, checkpointID); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(nil).JSON(c)
+}
+
+// checkpointContent reads the caller and the content ID from the path, writing the error
+// response when either is missing.
+func checkpointContent(c *gin.Context) (uint, uint, bool) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return 0, 0, false
+	}
+
+	contentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "非法的内容ID").JSON(c)
+		return 0, 0, false
+	}
+	return userID, uint(contentID), true
+}
+
+func checkpointIDParam(c *gin.Context) (uint, bool) {
+	checkpointID, err := strconv.ParseUint(c.Param("checkpoint_id"), 10, 64)
+	if err != nil || checkpointID == 0 {
+		utils.NewErrorResponse(http.StatusBadRequest, "非法的检查点ID").JSON(c)
+		return 0, false
+	}
+	return uint(checkpointID), true
+}
@@ -0,0 +1,393 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// ContentFeedbackHandler exposes ratings, reviews and comments on contents.
+type ContentFeedbackHandler struct {
+	feedback *service.ContentFeedbackService
+}
+
+// NewContentFeedbackHandler builds a ContentFeedbackHandler.
+func NewContentFeedbackHandler(feedback *service.ContentFeedbackService) *ContentFeedbackHandler {
+	return &ContentFeedbackHandler{feedback: feedback}
+}
+
+// RateContent godoc
+// @Summary 评分并评价内容
+// @Description 为内容打 1-5 星并可附评价文字，重复提交会覆盖自己之前的评分；评分立即计入平均分，评价文字修改后需重新审核
+// @Tags 内容
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param request body dto.ContentRatingRequest true "评分"
+// @Success 200 {object} utils.Response{data=dto.ContentRatingResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/contents/{id}/rating [put]
+func (h *ContentFeedbackHandler) RateContent(c *gin.Context) {
+	userID, contentID, ok := checkpointContent(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ContentRatingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.feedback.Rate(c.Request.Context(), userID, contentID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// ListReviews godoc
+// @Summary 查询内容评价
+// @Description 返回内容的平均分、各星级人数、我的评分以及已审核通过的评价
+// @Tags 内容
+// @Security Bearer
+// @Produce json
+// @Param id path int true "内容ID"
+// @Success 200 {object} utils.Response{data=dto.ContentReviewListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/contents/{id}/reviews [get]
+func (h *ContentFeedbackHandler) ListReviews(c *gin.Context) {
+	userID, contentID, ok := checkpointContent(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.feedback.ListReviews(userID, contentID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// ListComments godoc
+// @Summary 查询内容评论
+// @Description 按游标分页返回已审核通过的顶层评论，最新的在前，回复按时间顺序嵌套在 replies 中
+// @Tags 内容
+// @Security Bearer
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param cursor query string false "上一页返回的 next_cursor"
+// @Param page_size query int false "每页顶层评论数量，默认20，最大100"
+// @Success 200 {object} utils.Response{data=dto.ContentCommentListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/contents/{id}/comments [get]
+func (h *ContentFeedbackHandler) ListComments(c *gin.Context) {
+	userID, contentID, ok := checkpointContent(c)
+	if !ok {
+		return
+	}
+
+	var query dto.ContentCommentQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.feedback.ListComments(userID, contentID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// CreateComment godoc
+// @Summary 发表内容评论
+// @Description 发表评论或回复已公开的评论，审核通过后对其他人可见
+// @Tags 内容
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param request body dto.ContentCommentRequest true "评论"
+// @Success 200 {object} utils.Response{data=dto.ContentCommentResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/contents/{id}/comments [post]
+func (h *ContentFeedbackHandler) CreateComment(c *gin.Context) {
+	userID, contentID, ok := checkpointContent(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ContentCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.feedback.CreateComment(c.Request.Context(), userID, contentID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// DeleteComment godoc
+// @Summary 删除内容评论
+// @Description 作者可删除自己的评论，管理员可删除任意评论，其下的回复随之隐藏
+// @Tags 内容
+// @Security Bearer
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param comment_id path int true "评论ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/contents/{id}/comments/{comment_id} [delete]
+func (h *ContentFeedbackHandler) DeleteComment(c *gin.Context) {
+	userID, contentID, ok := checkpointContent(c)
+	if !ok {
+		return
+	}
+	commentID, ok := feedbackIDParam(c, "comment_id", "非法的评论ID")
+	if !ok {
+		return
+	}
+
+	if err := h.feedback.DeleteComment(c.Request.Context(), userID, contentID, commentID); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(nil).JSON(c)
+}
+
+// AdminLowestRated godoc
+// @Summary 管理员查看低分内容
+// @Description 分页列出已有评分的内容，默认按平均分从低到高
+// @Tags 管理后台-内容评价
+// @Security Bearer
+// @Produce json
+// @Param min_count query int false "至少多少人评分才纳入，默认 1"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 average/count/id，默认 average"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.ContentRatingSummaryListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/feedback/contents [get]
+func (h *ContentFeedbackHandler) AdminLowestRated(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var query dto.AdminRatedContentQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	items, err := h.feedback.AdminLowestRated(adminID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(items).JSON(c)
+}
+
+// AdminListRatings godoc
+// @Summary 管理员查询评分与评价
+// @Description 可按内容、审核状态、最高星级筛选，分页返回，默认评分最低的在前
+// @Tags 管理后台-内容评价
+// @Security Bearer
+// @Produce json
+// @Param content_id query int false "内容ID"
+// @Param status query string false "审核状态 pending/approved/rejected"
+// @Param max_rating query int false "只看评分不高于该值的记录"
+// @Param with_review query bool false "只看填写了评价文字的记录"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 rating/created_at/updated_at/id，默认 rating"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.ContentRatingListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/feedback/ratings [get]
+func (h *ContentFeedbackHandler) AdminListRatings(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var query dto.AdminContentRatingQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	items, err := h.feedback.AdminListRatings(adminID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(items).JSON(c)
+}
+
+// AdminApproveRating godoc
+// @Summary 管理员审核通过评价
+// @Tags 管理后台-内容评价
+// @Security Bearer
+// @Produce json
+// @Param id path int true "评分ID"
+// @Success 200 {object} utils.Response{data=dto.ContentRatingResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/feedback/ratings/{id}/approve [post]
+func (h *ContentFeedbackHandler) AdminApproveRating(c *gin.Context) {
+	h.moderateRating(c, true)
+}
+
+// AdminRejectRating godoc
+// @Summary 管理员拒绝评价
+// @Description 拒绝后评价文字不再公开，星级评分仍计入平均分
+// @Tags 管理后台-内容评价
+// @Security Bearer
+// @Produce json
+// @Param id path int true "评分ID"
+// @Success 200 {object} utils.Response{data=dto.ContentRatingResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/feedback/ratings/{id}/reject [post]
+func (h *ContentFeedbackHandler) AdminRejectRating(c *gin.Context) {
+	h.moderateRating(c, false)
+}
+
+// AdminListComments godoc
+// @Summary 管理员查询内容评论
+// @Description 可按内容、状态和关键词筛选评论，分页返回，默认最新的在前
+// @Tags 管理后台-内容评价
+// @Security Bearer
+// @Produce json
+// @Param content_id query int false "内容ID"
+// @Param status query string false "状态过滤 pending/approved/rejected"
+// @Param keyword query string false "搜索关键词"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 created_at/status/id，默认 created_at"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.ContentCommentListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/feedback/comments [get]
+func (h *ContentFeedbackHandler) AdminListComments(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var query dto.AdminContentCommentQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	items, err := h.feedback.AdminListComments(adminID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(items).JSON(c)
+}
+
+// AdminApproveComment godoc
+// @Summary 管理员审核通过评论
+// @Tags 管理后台-内容评价
+// @Security Bearer
+// @Produce json
+// @Param id path int true "评论ID"
+// @Success 200 {object} utils.Response{data=dto.ContentCommentResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/feedback/comments/{id}/approve [post]
+func (h *ContentFeedbackHandler) AdminApproveComment(c *gin.Context) {
+	h.moderateComment(c, true)
+}
+
+// AdminRejectComment godoc
+// @Summary 管理员拒绝评论
+// @Tags 管理后台-内容评价
+// @Security Bearer
+// @Produce json
+// @Param id path int true "评论ID"
+// @Success 200 {object} utils.Response{data=dto.ContentCommentResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/feedback/comments/{id}/reject [post]
+func (h *ContentFeedbackHandler) AdminRejectComment(c *gin.Context) {
+	h.moderateComment(c, false)
+}
+
+func (h *ContentFeedbackHandler) moderateRating(c *gin.Context, approve bool) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	ratingID, ok := feedbackIDParam(c, "id", "非法的评分ID")
+	if !ok {
+		return
+	}
+
+	resp, err := h.feedback.AdminModerateRating(c.Request.Context(), adminID, ratingID, approve)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+func (h *ContentFeedbackHandler) moderateComment(c *gin.Context, approve bool) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	commentID, ok := feedbackIDParam(c, "id", "非法的评论ID")
+	if !ok {
+		return
+	}
+
+	resp, err := h.feedback.AdminModerateComment(c.Request.Context(), adminID, commentID, approve)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+func feedbackIDParam(c *gin.Context, name, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		utils.NewErrorResponse(http.StatusBadRequest, message).JSON(c)
+		return 0, false
+	}
+	return uint(id), true
+}
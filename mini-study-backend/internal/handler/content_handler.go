@@ -1,37 +1,52 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
+	"path"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 
 	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
 	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
 	"github.com/javapub/mini-study/mini-study-backend/internal/model"
 	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
 	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
 )
 
 // ContentHandler handles content related endpoints.
 type ContentHandler struct {
 	service *service.ContentService
+	files   *service.FileService
+	media   *service.MediaService
+	ratings *service.ContentFeedbackService
 }
 
 // NewContentHandler creates a content handler.
-func NewContentHandler(contentService *service.ContentService) *ContentHandler {
-	return &ContentHandler{service: contentService}
+func NewContentHandler(contentService *service.ContentService, fileService *service.FileService, mediaService *service.MediaService, feedbackService *service.ContentFeedbackService) *ContentHandler {
+	return &ContentHandler{service: contentService, files: fileService, media: mediaService, ratings: feedbackService}
 }
 
 // ListCategories godoc
 // @Summary 查询可见内容分类
-// @Description 返回当前登录用户基于角色可访问的内容分类列表
+// @Description 分页返回当前登录用户基于角色可访问的内容分类，默认按排序序号升序
 // @Tags 内容
 // @Security Bearer
 // @Produce json
-// @Success 200 {object} utils.Response{data=[]dto.ContentCategoryResponse}
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 sort_order/name/created_at/id，默认 sort_order"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.ContentCategoryListResponse}
 // @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
 // @Router /api/v1/contents/categories [get]
 func (h *ContentHandler) ListCategories(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -40,30 +55,34 @@ func (h *ContentHandler) ListCategories(c *gin.Context) {
 		return
 	}
 
-	categories, counts, err := h.service.ListCategoriesWithCount(userID)
-	if err != nil {
+	var query dto.ContentCategoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
 	}
 
-	resp := make([]dto.ContentCategoryResponse, 0, len(categories))
-	for i, item := range categories {
-		count := int64(0)
-		if i < len(counts) {
-			count = counts[i]
-		}
-		resp = append(resp, dto.ContentCategoryResponse{
-			ID:        item.ID,
-			Name:      item.Name,
-			RoleScope: item.RoleScope,
-			SortOrder: item.SortOrder,
-			CoverURL:  item.CoverURL,
-			Count:     count,
-		})
+	categories, counts, page, err := h.service.ListCategoriesWithCount(userID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
 	}
-	utils.NewSuccessResponse(resp).JSON(c)
+	utils.NewSuccessResponse(dto.ContentCategoryListResponse{Items: toCategoryResponses(categories, counts), Pagination: page}).JSON(c)
 }
 
+// AdminListCategories godoc
+// @Summary 管理员查询内容分类
+// @Description 分页返回启用中的内容分类及各分类已发布的内容数量，默认按排序序号升序
+// @Tags 管理后台-内容
+// @Security Bearer
+// @Produce json
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 sort_order/name/created_at/id，默认 sort_order"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.ContentCategoryListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/categories [get]
 func (h *ContentHandler) AdminListCategories(c *gin.Context) {
 	adminID := middleware.GetUserID(c)
 	if adminID == 0 {
@@ -71,12 +90,21 @@ func (h *ContentHandler) AdminListCategories(c *gin.Context) {
 		return
 	}
 
-	categories, counts, err := h.service.AdminListCategories(adminID)
+	var query dto.ContentCategoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	categories, counts, page, err := h.service.AdminListCategories(adminID, query)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
 	}
+	utils.NewSuccessResponse(dto.ContentCategoryListResponse{Items: toCategoryResponses(categories, counts), Pagination: page}).JSON(c)
+}
 
+func toCategoryResponses(categories []model.ContentCategory, counts []int64) []dto.ContentCategoryResponse {
 	resp := make([]dto.ContentCategoryResponse, 0, len(categories))
 	for i, item := range categories {
 		count := int64(0)
@@ -92,18 +120,22 @@ func (h *ContentHandler) AdminListCategories(c *gin.Context) {
 			Count:     count,
 		})
 	}
-	utils.NewSuccessResponse(resp).JSON(c)
+	return resp
 }
 
 // ListPublishedContents godoc
 // @Summary 查询已发布内容
-// @Description 根据分类与类型筛选当前用户可访问的已发布内容
+// @Description 根据分类与类型筛选当前用户可访问的已发布内容，附带平均评分与评分人数
 // @Tags 内容
 // @Security Bearer
 // @Produce json
 // @Param category_id query int false "分类ID"
 // @Param type query string false "内容类型(doc/video/article)"
-// @Success 200 {object} utils.Response{data=[]dto.ContentResponse}
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 publish_at/created_at/updated_at/title/id，默认 publish_at"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.ContentListResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Router /api/v1/contents [get]
@@ -120,18 +152,26 @@ func (h *ContentHandler) ListPublishedContents(c *gin.Context) {
 		return
 	}
 
-	contents, err := h.service.ListPublished(userID, query.CategoryID, query.Type)
+	contents, page, err := h.service.ListPublished(userID, query)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
 	}
 
-	utils.NewSuccessResponse(h.toContentResponses(contents)).JSON(c)
+	resp := h.toContentResponses(c.Request.Context(), contents)
+	for i := range resp {
+		resp[i].ArticleBlocks = service.LearnerArticleBlocks(resp[i].ArticleBlocks)
+	}
+	if err := h.ratings.ApplyRatings(resp); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(dto.ContentListResponse{Items: resp, Pagination: page}).JSON(c)
 }
 
 // GetContentDetail godoc
 // @Summary 查询内容详情
-// @Description 获取指定已发布内容的详细信息
+// @Description 获取指定已发布内容的详细信息（含平均评分与评分人数），图文中随堂测验的答案与解析不返回
 // @Tags 内容
 // @Security Bearer
 // @Produce json
@@ -160,19 +200,213 @@ func (h *ContentHandler) GetContentDetail(c *gin.Context) {
 		return
 	}
 
-	utils.NewSuccessResponse(h.toContentResponse(content)).JSON(c)
+	resp := h.toContentResponse(c.Request.Context(), content)
+	// 随堂测验的答案与解析只对管理员可见
+	resp.ArticleBlocks = service.LearnerArticleBlocks(resp.ArticleBlocks)
+	items := []dto.ContentResponse{resp}
+	if err := h.ratings.ApplyRatings(items); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(items[0]).JSON(c)
+}
+
+// AnswerArticleQuiz godoc
+// @Summary 回答图文中的随堂测验
+// @Description 提交图文内容中测验块的答案并返回是否答对、正确选项与解析。测验块仅用于自测，不记录作答、不计分；需要计分或解锁后续学习的题目使用检查点
+// @Tags 内容
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param index path int true "测验所在内容块序号（从 0 开始）"
+// @Param request body dto.ArticleQuizAnswerRequest true "所选选项"
+// @Success 200 {object} utils.Response{data=dto.ArticleQuizAnswerResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/contents/{id}/blocks/{index}/answer [post]
+func (h *ContentHandler) AnswerArticleQuiz(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	contentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "非法的内容ID").JSON(c)
+		return
+	}
+	blockIndex, err := strconv.Atoi(c.Param("index"))
+	if err != nil || blockIndex < 0 {
+		utils.NewErrorResponse(http.StatusBadRequest, "非法的内容块序号").JSON(c)
+		return
+	}
+
+	var req dto.ArticleQuizAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.service.AnswerArticleQuiz(userID, uint(contentID), blockIndex, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// GetContentFile godoc
+// @Summary 读取内容文件
+// @Description 校验当前用户可查看该内容后返回其文档/视频文件（内容不存在或未发布返回 404，无权查看返回 403）。支持 Range 分段请求（视频拖动）与 ETag/If-None-Match 协商缓存；对象存储驱动下重定向到预签名地址。每次访问以水印编号记录在访问日志中，响应头 X-Watermark-ID 可用于叠加显示
+// @Tags 内容
+// @Security Bearer
+// @Produce octet-stream
+// @Param id path int true "内容ID"
+// @Param download query bool false "为 true 时以附件形式下载"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Success 302 {string} string
+// @Success 304 {string} string
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/v1/contents/{id}/file [get]
+func (h *ContentHandler) GetContentFile(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	contentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "非法的内容ID").JSON(c)
+		return
+	}
+
+	content, err := h.service.GetPublishedDetail(userID, uint(contentID))
+	if err != nil {
+		utils.NewErrorResponse(contentAccessStatus(err), err.Error()).JSON(c)
+		return
+	}
+
+	file, err := h.files.OpenContentFile(c.Request.Context(), userID, content)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			utils.NewErrorResponse(http.StatusNotFound, "内容文件不存在").JSON(c)
+			return
+		}
+		utils.NewErrorResponse(http.StatusInternalServerError, err.Error()).JSON(c)
+		return
+	}
+	middleware.AddLogFields(c,
+		zap.Uint("user_id", userID),
+		zap.Uint("content_id", content.ID),
+		zap.String("file_key", file.Key),
+		zap.String("range", c.GetHeader("Range")),
+		zap.String("watermark_id", file.Watermark),
+	)
+	c.Header("X-Watermark-ID", file.Watermark)
+
+	if file.Redirect != "" {
+		c.Redirect(http.StatusFound, file.Redirect)
+		return
+	}
+	defer file.Body.Close()
+
+	c.Header("Cache-Control", "private, no-cache")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Type", file.Info.ContentType)
+	if file.Info.ETag != "" {
+		c.Header("ETag", file.Info.ETag)
+	}
+	if download, _ := strconv.ParseBool(c.Query("download")); download {
+		filename := content.Title + path.Ext(file.Key)
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+	if seeker, ok := file.Body.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, "", file.Info.ModTime, seeker)
+		return
+	}
+	utils.NewErrorResponse(http.StatusInternalServerError, "文件无法按范围读取").JSON(c)
+}
+
+// contentAccessStatus maps GetPublishedDetail errors to HTTP statuses; unpublished contents
+// are reported as missing so learners cannot probe drafts.
+func contentAccessStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrContentNotFound), errors.Is(err, service.ErrContentNotPublished):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrContentForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ListContentPages godoc
+// @Summary 文档分页预览
+// @Description 返回文档转换后的逐页图片（签名链接）与文本，供小程序逐页阅读；阅读时通过学习进度接口上报页码与停留时长。预览尚未生成时 items 为空
+// @Tags 内容
+// @Security Bearer
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param page query int false "页码，从1开始"
+// @Param page_size query int false "每页数量，默认10，最大50"
+// @Success 200 {object} utils.Response{data=dto.ContentPageListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/contents/{id}/pages [get]
+func (h *ContentHandler) ListContentPages(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	contentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "非法的内容ID").JSON(c)
+		return
+	}
+
+	var query dto.ContentPageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	content, err := h.service.GetPublishedDetail(userID, uint(contentID))
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	pages, err := h.media.ListPages(c.Request.Context(), content, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(pages).JSON(c)
 }
 
 // AdminListContents godoc
 // @Summary 管理员查询内容列表
-// @Description 管理员可按分类、类型与状态筛选内容
+// @Description 管理员可按分类、类型与状态筛选内容，分页返回，默认按ID倒序
 // @Tags 管理后台-内容
 // @Security Bearer
 // @Produce json
 // @Param category_id query int false "分类ID"
 // @Param type query string false "内容类型(doc/video/article)"
-// @Param status query string false "内容状态(draft/published/offline)"
-// @Success 200 {object} utils.Response{data=[]dto.ContentResponse}
+// @Param status query string false "内容状态(draft/in_review/approved/published/offline)"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 id/title/publish_at/created_at/updated_at，默认 id"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.ContentListResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Router /api/v1/admin/contents [get]
@@ -189,12 +423,12 @@ func (h *ContentHandler) AdminListContents(c *gin.Context) {
 		return
 	}
 
-	contents, err := h.service.AdminListContents(adminID, query)
+	contents, page, err := h.service.AdminListContents(adminID, query)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
 	}
-	utils.NewSuccessResponse(h.toContentResponses(contents)).JSON(c)
+	utils.NewSuccessResponse(dto.ContentListResponse{Items: h.toContentResponses(c.Request.Context(), contents), Pagination: page}).JSON(c)
 }
 
 // AdminCreateContent godoc
@@ -222,12 +456,12 @@ func (h *ContentHandler) AdminCreateContent(c *gin.Context) {
 		return
 	}
 
-	content, err := h.service.AdminCreateContent(adminID, req)
+	content, err := h.service.AdminCreateContent(c.Request.Context(), adminID, req)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
 	}
-	utils.NewSuccessResponse(h.toContentResponse(content)).JSON(c)
+	utils.NewSuccessResponse(h.toContentResponse(c.Request.Context(), content)).JSON(c)
 }
 
 func (h *ContentHandler) AdminUpdateCategory(c *gin.Context) {
@@ -300,23 +534,177 @@ func (h *ContentHandler) AdminUpdateContent(c *gin.Context) {
 		return
 	}
 
-	content, err := h.service.AdminUpdateContent(adminID, uint(contentID), req)
+	content, err := h.service.AdminUpdateContent(c.Request.Context(), adminID, uint(contentID), req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(h.toContentResponse(c.Request.Context(), content)).JSON(c)
+}
+
+// AdminListRevisions godoc
+// @Summary 管理员查看内容修订历史
+// @Description 分页列出内容的修订版本，默认最新的在前，含修改人、时间及相比上一版本变更的字段
+// @Tags 管理后台-内容
+// @Security Bearer
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 id/version/created_at，默认 version"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.ContentRevisionListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/contents/{id}/revisions [get]
+func (h *ContentHandler) AdminListRevisions(c *gin.Context) {
+	adminID, contentID, ok := h.adminContentID(c)
+	if !ok {
+		return
+	}
+
+	var query dto.ContentRevisionListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	items, page, err := h.service.AdminListRevisions(adminID, contentID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(dto.ContentRevisionListResponse{Items: items, Pagination: page}).JSON(c)
+}
+
+// AdminGetRevision godoc
+// @Summary 管理员查看内容修订版本
+// @Description 返回指定版本的完整内容，包括图文内容块
+// @Tags 管理后台-内容
+// @Security Bearer
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param version path int true "版本号"
+// @Success 200 {object} utils.Response{data=dto.ContentRevisionDetail}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/contents/{id}/revisions/{version} [get]
+func (h *ContentHandler) AdminGetRevision(c *gin.Context) {
+	adminID, contentID, ok := h.adminContentID(c)
+	if !ok {
+		return
+	}
+	version, ok := revisionVersion(c)
+	if !ok {
+		return
+	}
+
+	detail, err := h.service.AdminGetRevision(adminID, contentID, version)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(detail).JSON(c)
+}
+
+// AdminDiffRevisions godoc
+// @Summary 管理员比对内容修订版本
+// @Description 比对两个版本的字段与图文内容块，内容块逐块标记为未变、新增、删除或修改；默认比对最新版本与其上一版本
+// @Tags 管理后台-内容
+// @Security Bearer
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param from query int false "旧版本号，默认为新版本的上一版本"
+// @Param to query int false "新版本号，默认为最新版本"
+// @Success 200 {object} utils.Response{data=dto.ContentRevisionDiffResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/contents/{id}/revisions/diff [get]
+func (h *ContentHandler) AdminDiffRevisions(c *gin.Context) {
+	adminID, contentID, ok := h.adminContentID(c)
+	if !ok {
+		return
+	}
+
+	var query dto.ContentRevisionDiffQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	diff, err := h.service.AdminDiffRevisions(adminID, contentID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(diff).JSON(c)
+}
+
+// AdminRollbackContent godoc
+// @Summary 管理员回滚内容到指定版本
+// @Description 将内容的标题、文件、正文等字段恢复为指定版本，回滚按普通修改处理并生成新版本；已审核通过的内容回滚后需重新审核
+// @Tags 管理后台-内容
+// @Security Bearer
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param version path int true "版本号"
+// @Success 200 {object} utils.Response{data=dto.ContentResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/contents/{id}/revisions/{version}/rollback [post]
+func (h *ContentHandler) AdminRollbackContent(c *gin.Context) {
+	adminID, contentID, ok := h.adminContentID(c)
+	if !ok {
+		return
+	}
+	version, ok := revisionVersion(c)
+	if !ok {
+		return
+	}
+
+	content, err := h.service.AdminRollbackContent(c.Request.Context(), adminID, contentID, version)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
 	}
-	utils.NewSuccessResponse(h.toContentResponse(content)).JSON(c)
+	utils.NewSuccessResponse(h.toContentResponse(c.Request.Context(), content)).JSON(c)
 }
 
-func (h *ContentHandler) toContentResponses(contents []model.Content) []dto.ContentResponse {
+// adminContentID reads the caller and the content ID from the path, writing the error response
+// when either is missing.
+func (h *ContentHandler) adminContentID(c *gin.Context) (uint, uint, bool) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return 0, 0, false
+	}
+
+	contentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "非法的内容ID").JSON(c)
+		return 0, 0, false
+	}
+	return adminID, uint(contentID), true
+}
+
+func revisionVersion(c *gin.Context) (int, bool) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		utils.NewErrorResponse(http.StatusBadRequest, "非法的版本号").JSON(c)
+		return 0, false
+	}
+	return version, true
+}
+
+func (h *ContentHandler) toContentResponses(ctx context.Context, contents []model.Content) []dto.ContentResponse {
 	resp := make([]dto.ContentResponse, 0, len(contents))
 	for idx := range contents {
-		resp = append(resp, h.toContentResponse(&contents[idx]))
+		resp = append(resp, h.toContentResponse(ctx, &contents[idx]))
 	}
 	return resp
 }
 
-func (h *ContentHandler) toContentResponse(content *model.Content) dto.ContentResponse {
+func (h *ContentHandler) toContentResponse(ctx context.Context, content *model.Content) dto.ContentResponse {
 	categoryName := ""
 	if content.Category.ID != 0 {
 		categoryName = content.Category.Name
@@ -325,6 +713,13 @@ func (h *ContentHandler) toContentResponse(content *model.Content) dto.ContentRe
 	if content.BodyBlocksJSON != "" {
 		_ = json.Unmarshal([]byte(content.BodyBlocksJSON), &articleBlocks)
 	}
+	// 内容块中的图片、视频与附件同内容文件一样解析为可访问的地址
+	for i := range articleBlocks {
+		block := &articleBlocks[i]
+		block.ImageURL = h.files.ResolveURL(ctx, block.ImagePath)
+		block.VideoURL = h.files.ResolveURL(ctx, block.VideoPath)
+		block.FileURL = h.files.ResolveURL(ctx, block.FilePath)
+	}
 	return dto.ContentResponse{
 		ID:              content.ID,
 		Title:           content.Title,
@@ -332,6 +727,9 @@ func (h *ContentHandler) toContentResponse(content *model.Content) dto.ContentRe
 		CategoryID:      content.CategoryID,
 		CategoryName:    categoryName,
 		FilePath:        content.FilePath,
+		FileURL:         h.files.ResolveURL(ctx, content.FilePath),
+		HLSURL:          h.media.PlaybackURL(content),
+		PageCount:       content.PageCount,
 		CoverURL:        content.CoverURL,
 		Summary:         content.Summary,
 		Status:          content.Status,
@@ -339,5 +737,8 @@ func (h *ContentHandler) toContentResponse(content *model.Content) dto.ContentRe
 		DurationSeconds: content.DurationSeconds,
 		PublishAt:       content.PublishAt,
 		ArticleBlocks:   articleBlocks,
+
+		ScheduledPublishAt: content.ScheduledPublishAt,
+		ScheduledOfflineAt: content.ScheduledOfflineAt,
 	}
 }
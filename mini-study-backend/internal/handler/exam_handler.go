@@ -27,7 +27,11 @@ func NewExamHandler(svc *service.ExamService) *ExamHandler {
 // @Tags 考试
 // @Security Bearer
 // @Produce json
-// @Success 200 {object} utils.Response{data=[]dto.ExamListItem}
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 id/title/created_at/updated_at/pass_score，默认 id"
+// @Param order query string false "排序方向 asc/desc，默认 desc"
+// @Success 200 {object} utils.Response{data=dto.ExamListResponse}
 // @Router /api/v1/exams [get]
 func (h *ExamHandler) ListAvailable(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -35,7 +39,13 @@ func (h *ExamHandler) ListAvailable(c *gin.Context) {
 		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
 		return
 	}
-	resp, err := h.service.ListAvailableExams(userID)
+	var query dto.PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.service.ListAvailableExams(userID, query)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -101,7 +111,7 @@ func (h *ExamHandler) SubmitExam(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.SubmitExam(userID, examID, req)
+	resp, err := h.service.SubmitExam(c.Request.Context(), userID, examID, req)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -114,7 +124,11 @@ func (h *ExamHandler) SubmitExam(c *gin.Context) {
 // @Tags 考试
 // @Security Bearer
 // @Produce json
-// @Success 200 {object} utils.Response{data=[]dto.ExamResultSummary}
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 created_at/score/id，默认 created_at"
+// @Param order query string false "排序方向 asc/desc，默认 desc"
+// @Success 200 {object} utils.Response{data=dto.ExamResultListResponse}
 // @Router /api/v1/exams/my/results [get]
 func (h *ExamHandler) ListMyResults(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -123,7 +137,13 @@ func (h *ExamHandler) ListMyResults(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.ListMyResults(userID)
+	var query dto.PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.service.ListMyResults(userID, query)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -153,7 +173,7 @@ func (h *ExamHandler) AdminCreateExam(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.AdminCreateExam(adminID, req)
+	resp, err := h.service.AdminCreateExam(c.Request.Context(), adminID, req)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -166,7 +186,12 @@ func (h *ExamHandler) AdminCreateExam(c *gin.Context) {
 // @Tags 管理端/考试
 // @Security Bearer
 // @Produce json
-// @Success 200 {object} utils.Response{data=[]dto.ExamDetailResponse}
+// @Param status query string false "状态(draft/in_review/approved/published/archived)"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 id/title/created_at/updated_at/pass_score，默认 id"
+// @Param order query string false "排序方向 asc/desc，默认 desc"
+// @Success 200 {object} utils.Response{data=dto.AdminExamListResponse}
 // @Router /api/v1/admin/exams [get]
 func (h *ExamHandler) AdminListExams(c *gin.Context) {
 	adminID := middleware.GetUserID(c)
@@ -175,7 +200,13 @@ func (h *ExamHandler) AdminListExams(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.AdminListExams(adminID)
+	var query dto.AdminExamListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.service.AdminListExams(adminID, query)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -241,7 +272,7 @@ func (h *ExamHandler) AdminUpdateExam(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.AdminUpdateExam(adminID, examID, req)
+	resp, err := h.service.AdminUpdateExam(c.Request.Context(), adminID, examID, req)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 
@@ -24,17 +25,22 @@ func NewGrowthHandler(svc *service.GrowthService) *GrowthHandler {
 
 // ListPublicPosts godoc
 // @Summary 查询成长圈动态
-// @Description 返回所有已审核通过的成长圈动态列表，可按关键词搜索
+// @Description 按游标分页返回当前用户可见的已审核通过的成长圈动态，最新的在前，可按关键词、话题或精选筛选；不带关键词和精选筛选时第一页在 pinned 中返回置顶动态
 // @Tags 成长圈
 // @Security Bearer
 // @Produce json
 // @Param keyword query string false "搜索关键词"
-// @Success 200 {object} utils.Response{data=[]dto.GrowthPostResponse}
+// @Param topic query string false "话题名称"
+// @Param featured query bool false "只看精选动态"
+// @Param cursor query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Success 200 {object} utils.Response{data=dto.GrowthPostListResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Router /api/v1/growth [get]
 func (h *GrowthHandler) ListPublicPosts(c *gin.Context) {
-	if middleware.GetUserID(c) == 0 {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
 		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
 		return
 	}
@@ -45,7 +51,7 @@ func (h *GrowthHandler) ListPublicPosts(c *gin.Context) {
 		return
 	}
 
-	posts, err := h.service.ListPublic(query.Keyword)
+	posts, err := h.service.ListPublic(userID, query)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -55,13 +61,15 @@ func (h *GrowthHandler) ListPublicPosts(c *gin.Context) {
 
 // ListMyPosts godoc
 // @Summary 查询我的成长圈动态
-// @Description 返回当前登录用户发布的成长圈动态列表，可按状态和关键词筛选
+// @Description 按游标分页返回当前登录用户发布的成长圈动态，可按状态和关键词筛选
 // @Tags 成长圈
 // @Security Bearer
 // @Produce json
 // @Param status query string false "状态过滤 pending/approved/rejected"
 // @Param keyword query string false "搜索关键词"
-// @Success 200 {object} utils.Response{data=[]dto.GrowthPostResponse}
+// @Param cursor query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Success 200 {object} utils.Response{data=dto.GrowthPostListResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Router /api/v1/growth/mine [get]
@@ -87,8 +95,8 @@ func (h *GrowthHandler) ListMyPosts(c *gin.Context) {
 }
 
 // CreatePost godoc
-// @Summary 店长发布成长圈动态
-// @Description 仅店长可以发布成长圈动态，支持文本+多图
+// @Summary 发布成长圈动态
+// @Description 店长可以发布成长圈动态，配置开启后员工也可发布；支持文本+多图，可限定全公司、本店团队或指定角色可见，正文中的 #话题 自动关联
 // @Tags 成长圈
 // @Security Bearer
 // @Accept json
@@ -111,7 +119,45 @@ func (h *GrowthHandler) CreatePost(c *gin.Context) {
 		return
 	}
 
-	post, err := h.service.CreatePost(userID, req)
+	post, err := h.service.CreatePost(c.Request.Context(), userID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(post).JSON(c)
+}
+
+// ResubmitPost godoc
+// @Summary 修改并重新提交被拒绝的动态
+// @Description 店长修改自己被拒绝的动态，提交后回到待审核状态并重新经过自动审核
+// @Tags 成长圈
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "动态ID"
+// @Param body body dto.ResubmitGrowthPostRequest true "修改后的动态"
+// @Success 200 {object} utils.Response{data=dto.GrowthPostResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/growth/{id} [put]
+func (h *GrowthHandler) ResubmitPost(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	postID, ok := feedbackIDParam(c, "id", "非法的动态ID")
+	if !ok {
+		return
+	}
+
+	var req dto.ResubmitGrowthPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	post, err := h.service.Resubmit(c.Request.Context(), userID, postID, req)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -119,6 +165,36 @@ func (h *GrowthHandler) CreatePost(c *gin.Context) {
 	utils.NewSuccessResponse(post).JSON(c)
 }
 
+// PostHistory godoc
+// @Summary 查询动态审核历史
+// @Description 返回动态的提交、重新提交、自动审核与人工审核记录，提交类记录附带当时的内容快照；发布者本人和管理员可查看
+// @Tags 成长圈
+// @Security Bearer
+// @Produce json
+// @Param id path int true "动态ID"
+// @Success 200 {object} utils.Response{data=dto.GrowthModerationHistoryResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/growth/{id}/history [get]
+func (h *GrowthHandler) PostHistory(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	postID, ok := feedbackIDParam(c, "id", "非法的动态ID")
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.History(userID, postID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
 // DeletePost godoc
 // @Summary 删除成长圈动态
 // @Description 店长可删除自己未通过审核的动态，管理员可删除任意动态
@@ -144,7 +220,7 @@ func (h *GrowthHandler) DeletePost(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(userID, uint(postID)); err != nil {
+	if err := h.service.Delete(c.Request.Context(), userID, uint(postID)); err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
 	}
@@ -153,13 +229,18 @@ func (h *GrowthHandler) DeletePost(c *gin.Context) {
 
 // AdminListPosts godoc
 // @Summary 管理员查询成长圈动态列表
-// @Description 管理员可按状态和关键词筛选成长圈动态
+// @Description 管理员可按状态、自动审核结论和关键词筛选成长圈动态，分页返回，默认按发布时间倒序；经过自动审核的动态附带命中的敏感词和高亮文本
 // @Tags 管理后台-成长圈
 // @Security Bearer
 // @Produce json
 // @Param status query string false "状态过滤 pending/approved/rejected"
+// @Param moderation query string false "自动审核结论过滤 pass/review/block"
 // @Param keyword query string false "搜索关键词"
-// @Success 200 {object} utils.Response{data=[]dto.GrowthPostResponse}
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 created_at/status/id，默认 created_at"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.GrowthPostListResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Router /api/v1/admin/growth [get]
@@ -209,7 +290,7 @@ func (h *GrowthHandler) AdminApprovePost(c *gin.Context) {
 		return
 	}
 
-	post, err := h.service.Approve(adminID, uint(postID))
+	post, err := h.service.Approve(c.Request.Context(), adminID, uint(postID))
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -219,11 +300,13 @@ func (h *GrowthHandler) AdminApprovePost(c *gin.Context) {
 
 // AdminRejectPost godoc
 // @Summary 管理员拒绝成长圈动态
-// @Description 将指定成长圈动态状态设置为 rejected
+// @Description 将指定成长圈动态状态设置为 rejected，须选择预设原因代码或填写原因说明，原因会通知发布者
 // @Tags 管理后台-成长圈
 // @Security Bearer
+// @Accept json
 // @Produce json
 // @Param id path int true "动态ID"
+// @Param body body dto.GrowthRejectRequest true "拒绝原因"
 // @Success 200 {object} utils.Response{data=dto.GrowthPostResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 400 {object} utils.Response
@@ -242,7 +325,506 @@ func (h *GrowthHandler) AdminRejectPost(c *gin.Context) {
 		return
 	}
 
-	post, err := h.service.Reject(adminID, uint(postID))
+	var req dto.GrowthRejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	post, err := h.service.Reject(c.Request.Context(), adminID, uint(postID), req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(post).JSON(c)
+}
+
+// LikePost godoc
+// @Summary 点赞成长圈动态
+// @Description 点赞已审核通过的动态，重复点赞不重复计数；首次点赞时通知作者
+// @Tags 成长圈
+// @Security Bearer
+// @Produce json
+// @Param id path int true "动态ID"
+// @Success 200 {object} utils.Response{data=dto.GrowthLikeResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/growth/{id}/like [post]
+func (h *GrowthHandler) LikePost(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	postID, ok := feedbackIDParam(c, "id", "非法的动态ID")
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.Like(userID, postID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// UnlikePost godoc
+// @Summary 取消点赞成长圈动态
+// @Tags 成长圈
+// @Security Bearer
+// @Produce json
+// @Param id path int true "动态ID"
+// @Success 200 {object} utils.Response{data=dto.GrowthLikeResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/growth/{id}/like [delete]
+func (h *GrowthHandler) UnlikePost(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	postID, ok := feedbackIDParam(c, "id", "非法的动态ID")
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.Unlike(userID, postID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// ListLikers godoc
+// @Summary 查询点赞用户
+// @Description 按游标分页返回动态的点赞用户，最近点赞的在前
+// @Tags 成长圈
+// @Security Bearer
+// @Produce json
+// @Param id path int true "动态ID"
+// @Param cursor query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Success 200 {object} utils.Response{data=dto.GrowthLikerListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/growth/{id}/likes [get]
+func (h *GrowthHandler) ListLikers(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	postID, ok := feedbackIDParam(c, "id", "非法的动态ID")
+	if !ok {
+		return
+	}
+	var query dto.CursorQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.service.ListLikers(userID, postID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// SharePost godoc
+// @Summary 记录成长圈动态分享
+// @Description 客户端完成分享后上报渠道。每个用户首次分享时分享人数加一并通知作者，重复上报不重复计数
+// @Tags 成长圈
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "动态ID"
+// @Param body body dto.GrowthShareRequest true "分享渠道"
+// @Success 200 {object} utils.Response{data=dto.GrowthShareResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/growth/{id}/share [post]
+func (h *GrowthHandler) SharePost(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	postID, ok := feedbackIDParam(c, "id", "非法的动态ID")
+	if !ok {
+		return
+	}
+	var req dto.GrowthShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.service.Share(userID, postID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// ListComments godoc
+// @Summary 查询成长圈评论
+// @Description 按游标分页返回动态下已审核通过的顶层评论，最新的在前；回复嵌套在 replies 中
+// @Tags 成长圈
+// @Security Bearer
+// @Produce json
+// @Param id path int true "动态ID"
+// @Param cursor query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Success 200 {object} utils.Response{data=dto.GrowthCommentListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/growth/{id}/comments [get]
+func (h *GrowthHandler) ListComments(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	postID, ok := feedbackIDParam(c, "id", "非法的动态ID")
+	if !ok {
+		return
+	}
+	var query dto.CursorQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.service.ListComments(userID, postID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// CreateComment godoc
+// @Summary 发表成长圈评论
+// @Description 评论或回复已审核通过的动态，审核通过后公开并通知动态作者
+// @Tags 成长圈
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "动态ID"
+// @Param body body dto.GrowthCommentRequest true "评论内容"
+// @Success 200 {object} utils.Response{data=dto.GrowthCommentResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/growth/{id}/comments [post]
+func (h *GrowthHandler) CreateComment(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	postID, ok := feedbackIDParam(c, "id", "非法的动态ID")
+	if !ok {
+		return
+	}
+	var req dto.GrowthCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.service.CreateComment(c.Request.Context(), userID, postID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// DeleteComment godoc
+// @Summary 删除成长圈评论
+// @Description 作者可删除自己的评论，管理员可删除任意评论
+// @Tags 成长圈
+// @Security Bearer
+// @Produce json
+// @Param id path int true "动态ID"
+// @Param comment_id path int true "评论ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/growth/{id}/comments/{comment_id} [delete]
+func (h *GrowthHandler) DeleteComment(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	postID, ok := feedbackIDParam(c, "id", "非法的动态ID")
+	if !ok {
+		return
+	}
+	commentID, ok := feedbackIDParam(c, "comment_id", "非法的评论ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteComment(c.Request.Context(), userID, postID, commentID); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(nil).JSON(c)
+}
+
+// AdminListComments godoc
+// @Summary 管理员查询成长圈评论
+// @Description 可按动态、状态和关键词筛选评论，分页返回，默认最新的在前
+// @Tags 管理后台-成长圈
+// @Security Bearer
+// @Produce json
+// @Param post_id query int false "动态ID"
+// @Param status query string false "状态过滤 pending/approved/rejected"
+// @Param keyword query string false "搜索关键词"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 created_at/status/id，默认 created_at"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.GrowthCommentListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/growth/comments [get]
+func (h *GrowthHandler) AdminListComments(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	var query dto.AdminGrowthCommentQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.service.AdminListComments(adminID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminApproveComment godoc
+// @Summary 管理员审核通过成长圈评论
+// @Description 通过后评论公开，并通知动态作者与被回复的评论作者
+// @Tags 管理后台-成长圈
+// @Security Bearer
+// @Produce json
+// @Param id path int true "评论ID"
+// @Success 200 {object} utils.Response{data=dto.GrowthCommentResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/growth/comments/{id}/approve [post]
+func (h *GrowthHandler) AdminApproveComment(c *gin.Context) {
+	h.moderateComment(c, true)
+}
+
+// AdminRejectComment godoc
+// @Summary 管理员拒绝成长圈评论
+// @Description 拒绝后评论及其下的回复不再公开
+// @Tags 管理后台-成长圈
+// @Security Bearer
+// @Produce json
+// @Param id path int true "评论ID"
+// @Success 200 {object} utils.Response{data=dto.GrowthCommentResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/growth/comments/{id}/reject [post]
+func (h *GrowthHandler) AdminRejectComment(c *gin.Context) {
+	h.moderateComment(c, false)
+}
+
+func (h *GrowthHandler) moderateComment(c *gin.Context, approve bool) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	commentID, ok := feedbackIDParam(c, "id", "非法的评论ID")
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.AdminModerateComment(c.Request.Context(), adminID, commentID, approve)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminRejectReasons godoc
+// @Summary 查询预设的拒绝原因
+// @Tags 管理后台-成长圈
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]dto.GrowthRejectReasonResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/growth/reject-reasons [get]
+func (h *GrowthHandler) AdminRejectReasons(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	reasons, err := h.service.RejectReasons(adminID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(reasons).JSON(c)
+}
+
+// AdminRejectionReport godoc
+// @Summary 成长圈拒绝原因统计
+// @Description 统计时间范围内人工和自动审核拒绝的原因分布，并按日、周（周一开始）或月给出连续的趋势数据
+// @Tags 管理后台-成长圈
+// @Security Bearer
+// @Produce json
+// @Param from query string false "开始日期（含），格式 2006-01-02，默认 30 天前"
+// @Param to query string false "结束日期（含），格式 2006-01-02，默认今天"
+// @Param interval query string false "统计粒度 day/week/month，默认 day"
+// @Success 200 {object} utils.Response{data=dto.GrowthRejectionReportResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/growth/rejections/report [get]
+func (h *GrowthHandler) AdminRejectionReport(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var query dto.GrowthRejectionReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	report, err := h.service.AdminRejectionReport(adminID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(report).JSON(c)
+}
+
+// TrendingTopics godoc
+// @Summary 热门话题
+// @Description 按最近一段时间内通过审核的可见动态数排序返回热门话题，天数由 growth.trending_days 配置
+// @Tags 成长圈
+// @Security Bearer
+// @Produce json
+// @Param limit query int false "返回数量，默认10，最大50"
+// @Success 200 {object} utils.Response{data=[]dto.GrowthTopicResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/growth/topics/trending [get]
+func (h *GrowthHandler) TrendingTopics(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	var query dto.GrowthTrendingQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	topics, err := h.service.TrendingTopics(userID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(topics).JSON(c)
+}
+
+// AdminPinPost godoc
+// @Summary 置顶成长圈动态
+// @Description 置顶已通过审核的动态，最多同时置顶 5 条
+// @Tags 管理后台-成长圈
+// @Security Bearer
+// @Produce json
+// @Param id path int true "动态ID"
+// @Success 200 {object} utils.Response{data=dto.GrowthPostResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/growth/{id}/pin [post]
+func (h *GrowthHandler) AdminPinPost(c *gin.Context) {
+	h.curatePost(c, h.service.SetPinned, true)
+}
+
+// AdminUnpinPost godoc
+// @Summary 取消置顶成长圈动态
+// @Tags 管理后台-成长圈
+// @Security Bearer
+// @Produce json
+// @Param id path int true "动态ID"
+// @Success 200 {object} utils.Response{data=dto.GrowthPostResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/growth/{id}/pin [delete]
+func (h *GrowthHandler) AdminUnpinPost(c *gin.Context) {
+	h.curatePost(c, h.service.SetPinned, false)
+}
+
+// AdminFeaturePost godoc
+// @Summary 设为精选动态
+// @Description 将已通过审核的动态设为精选并通知发布者
+// @Tags 管理后台-成长圈
+// @Security Bearer
+// @Produce json
+// @Param id path int true "动态ID"
+// @Success 200 {object} utils.Response{data=dto.GrowthPostResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/growth/{id}/feature [post]
+func (h *GrowthHandler) AdminFeaturePost(c *gin.Context) {
+	h.curatePost(c, h.service.SetFeatured, true)
+}
+
+// AdminUnfeaturePost godoc
+// @Summary 取消精选动态
+// @Tags 管理后台-成长圈
+// @Security Bearer
+// @Produce json
+// @Param id path int true "动态ID"
+// @Success 200 {object} utils.Response{data=dto.GrowthPostResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/growth/{id}/feature [delete]
+func (h *GrowthHandler) AdminUnfeaturePost(c *gin.Context) {
+	h.curatePost(c, h.service.SetFeatured, false)
+}
+
+func (h *GrowthHandler) curatePost(c *gin.Context, set func(context.Context, uint, uint, bool) (*dto.GrowthPostResponse, error), on bool) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	postID, ok := feedbackIDParam(c, "id", "非法的动态ID")
+	if !ok {
+		return
+	}
+
+	post, err := set(c.Request.Context(), adminID, postID, on)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -24,7 +24,7 @@ func NewLearningHandler(service *service.LearningService) *LearningHandler {
 
 // UpdateProgress godoc
 // @Summary 记录学习进度
-// @Description 登录用户上报视频播放位置，系统会累计已学进度
+// @Description 登录用户上报视频播放位置，或文档当前页码与该页停留秒数，系统会累计已学进度。已生成分页预览的文档按已读页数占比判断完成
 // @Tags 学习
 // @Security Bearer
 // @Accept json
@@ -47,7 +47,7 @@ func (h *LearningHandler) UpdateProgress(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.UpdateProgress(userID, req)
+	resp, err := h.service.UpdateProgress(c.Request.Context(), userID, req)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -90,11 +90,16 @@ func (h *LearningHandler) GetProgress(c *gin.Context) {
 
 // ListProgress godoc
 // @Summary 查询学习进度列表
-// @Description 返回当前用户所有已记录的学习进度
+// @Description 分页返回当前用户已记录的学习进度，默认最近学习的在前
 // @Tags 学习
 // @Security Bearer
 // @Produce json
-// @Success 200 {object} utils.Response{data=[]dto.LearningProgressResponse}
+// @Param status query string false "学习状态 in_progress/completed"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 updated_at/created_at/progress/id，默认 updated_at"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.LearningProgressListResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Router /api/v1/learning [get]
@@ -105,7 +110,13 @@ func (h *LearningHandler) ListProgress(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.ListProgress(userID)
+	var query dto.LearningProgressQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.service.ListProgress(userID, query)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
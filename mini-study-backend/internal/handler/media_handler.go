@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// MediaHandler serves HLS playback and exposes media processing jobs to admins.
+type MediaHandler struct {
+	media *service.MediaService
+}
+
+// NewMediaHandler builds a MediaHandler.
+func NewMediaHandler(media *service.MediaService) *MediaHandler {
+	return &MediaHandler{media: media}
+}
+
+// ServeHLS godoc
+// @Summary HLS 播放
+// @Description 通过内容详情返回的 hls_url 播放视频。播放列表中的子列表与切片地址会自动带上同一签名；切片支持 Range 请求，对象存储驱动下重定向到预签名地址
+// @Tags 媒体
+// @Produce application/vnd.apple.mpegurl
+// @Param path path string true "HLS 文件存储键"
+// @Param expires query int true "过期时间（Unix秒）"
+// @Param signature query string true "签名"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Success 302 {string} string
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/media/hls/{path} [get]
+func (h *MediaHandler) ServeHLS(c *gin.Context) {
+	var query dto.SignedDownloadQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusForbidden, "缺少签名参数").JSON(c)
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("path"), "/")
+	object, err := h.media.OpenHLS(c.Request.Context(), key, query)
+	if err != nil {
+		status := http.StatusForbidden
+		if errors.Is(err, storage.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		utils.NewErrorResponse(status, err.Error()).JSON(c)
+		return
+	}
+	if object.Redirect != "" {
+		c.Redirect(http.StatusFound, object.Redirect)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=0")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Type", object.ContentType)
+	if object.Playlist != nil {
+		c.Data(http.StatusOK, object.ContentType, object.Playlist)
+		return
+	}
+	defer object.Body.Close()
+	if object.ETag != "" {
+		c.Header("ETag", object.ETag)
+	}
+	if seeker, ok := object.Body.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, "", object.ModTime, seeker)
+		return
+	}
+	utils.NewErrorResponse(http.StatusInternalServerError, "切片无法按范围读取").JSON(c)
+}
+
+// AdminListMediaJobs godoc
+// @Summary 管理员查询视频处理任务
+// @Description 按内容与状态筛选媒体处理任务（视频：探测时长、生成缩略图、HLS 打包；文档：转换并渲染分页预览），按创建时间倒序分页返回
+// @Tags 管理后台-媒体
+// @Security Bearer
+// @Produce json
+// @Param content_id query int false "内容ID"
+// @Param status query string false "状态(pending/running/succeeded/failed/canceled)"
+// @Param page query int false "页码，从1开始"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Success 200 {object} utils.Response{data=dto.AdminMediaJobListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/media-jobs [get]
+func (h *MediaHandler) AdminListMediaJobs(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var query dto.AdminMediaJobQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	jobs, err := h.media.AdminListJobs(adminID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(jobs).JSON(c)
+}
+
+// AdminReprocessContent godoc
+// @Summary 管理员重新处理视频或文档
+// @Description 为视频或文档内容重新创建处理任务，常用于处理失败后重试；尚未执行的旧任务会被取消
+// @Tags 管理后台-媒体
+// @Security Bearer
+// @Produce json
+// @Param id path int true "内容ID"
+// @Success 200 {object} utils.Response{data=dto.MediaJobResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/contents/{id}/media-jobs [post]
+func (h *MediaHandler) AdminReprocessContent(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	contentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "非法的内容ID").JSON(c)
+		return
+	}
+
+	job, err := h.media.AdminReprocess(c.Request.Context(), adminID, uint(contentID))
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(job).JSON(c)
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// ModerationHandler 处理自动审核词典管理接口。
+type ModerationHandler struct {
+	moderation *service.ModerationService
+}
+
+// NewModerationHandler 创建自动审核处理器。
+func NewModerationHandler(moderation *service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{moderation: moderation}
+}
+
+// AdminListWords godoc
+// @Summary 管理员查询敏感词
+// @Tags 管理后台-自动审核
+// @Security Bearer
+// @Produce json
+// @Param keyword query string false "按敏感词模糊匹配"
+// @Param category query string false "分类过滤"
+// @Param level query string false "处置级别过滤 block/review"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 id/word/category/level/created_at，默认 id"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.SensitiveWordListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/moderation/words [get]
+func (h *ModerationHandler) AdminListWords(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var query dto.SensitiveWordQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.moderation.ListWords(adminID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminCreateWord godoc
+// @Summary 管理员新增敏感词
+// @Description 新增后立即对新发布的成长圈动态生效
+// @Tags 管理后台-自动审核
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.SensitiveWordRequest true "敏感词"
+// @Success 200 {object} utils.Response{data=dto.SensitiveWordResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/moderation/words [post]
+func (h *ModerationHandler) AdminCreateWord(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var req dto.SensitiveWordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.moderation.CreateWord(c.Request.Context(), adminID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminUpdateWord godoc
+// @Summary 管理员修改敏感词
+// @Tags 管理后台-自动审核
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "敏感词ID"
+// @Param request body dto.SensitiveWordRequest true "敏感词"
+// @Success 200 {object} utils.Response{data=dto.SensitiveWordResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/moderation/words/{id} [put]
+func (h *ModerationHandler) AdminUpdateWord(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	id, ok := feedbackIDParam(c, "id", "非法的敏感词ID")
+	if !ok {
+		return
+	}
+
+	var req dto.SensitiveWordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.moderation.UpdateWord(c.Request.Context(), adminID, id, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminDeleteWord godoc
+// @Summary 管理员删除敏感词
+// @Tags 管理后台-自动审核
+// @Security Bearer
+// @Produce json
+// @Param id path int true "敏感词ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/moderation/words/{id} [delete]
+func (h *ModerationHandler) AdminDeleteWord(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	id, ok := feedbackIDParam(c, "id", "非法的敏感词ID")
+	if !ok {
+		return
+	}
+
+	if err := h.moderation.DeleteWord(c.Request.Context(), adminID, id); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(nil).JSON(c)
+}
+
+// AdminCheck godoc
+// @Summary 管理员试运行自动审核
+// @Description 用当前词典和外部审核服务检测一段文本及图片，返回结论和高亮结果，不保存任何数据
+// @Tags 管理后台-自动审核
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.ModerationCheckRequest true "待检测内容"
+// @Success 200 {object} utils.Response{data=dto.ModerationResultResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/moderation/check [post]
+func (h *ModerationHandler) AdminCheck(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var req dto.ModerationCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.moderation.Check(c.Request.Context(), adminID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
@@ -54,12 +54,16 @@ func (h *NoticeHandler) GetLatestNotice(c *gin.Context) {
 
 // AdminListNotices godoc
 // @Summary 管理员查询公告列表
-// @Description 管理员可按状态筛选公告
+// @Description 管理员可按状态筛选公告，分页返回，默认按生效时间倒序
 // @Tags 管理后台-公告
 // @Security Bearer
 // @Produce json
 // @Param status query bool false "是否启用 true/false"
-// @Success 200 {object} utils.Response{data=[]dto.NoticeResponse}
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 start_at/end_at/created_at/title/id，默认 start_at"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.NoticeListResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Router /api/v1/admin/notices [get]
@@ -76,12 +80,12 @@ func (h *NoticeHandler) AdminListNotices(c *gin.Context) {
 		return
 	}
 
-	notices, err := h.service.AdminListNotices(adminID, query.Status)
+	notices, page, err := h.service.AdminListNotices(adminID, query)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
 	}
-	utils.NewSuccessResponse(h.toResponses(notices)).JSON(c)
+	utils.NewSuccessResponse(dto.NoticeListResponse{Items: h.toResponses(notices), Pagination: page}).JSON(c)
 }
 
 // AdminCreateNotice godoc
@@ -109,7 +113,7 @@ func (h *NoticeHandler) AdminCreateNotice(c *gin.Context) {
 		return
 	}
 
-	notice, err := h.service.AdminCreateNotice(adminID, req)
+	notice, err := h.service.AdminCreateNotice(c.Request.Context(), adminID, req)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -150,7 +154,7 @@ func (h *NoticeHandler) AdminUpdateNotice(c *gin.Context) {
 		return
 	}
 
-	notice, err := h.service.AdminUpdateNotice(adminID, uint(noticeID), req)
+	notice, err := h.service.AdminUpdateNotice(c.Request.Context(), adminID, uint(noticeID), req)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// NotificationHandler 处理站内通知接口。
+type NotificationHandler struct {
+	notifications *service.NotificationService
+}
+
+// NewNotificationHandler 创建站内通知处理器。
+func NewNotificationHandler(notifications *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notifications: notifications}
+}
+
+// ListNotifications godoc
+// @Summary 查询我的通知
+// @Description 按游标分页返回当前用户的站内通知，最新的在前，并返回未读总数
+// @Tags 通知
+// @Security Bearer
+// @Produce json
+// @Param unread_only query bool false "只看未读"
+// @Param cursor query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Success 200 {object} utils.Response{data=dto.NotificationListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	var query dto.NotificationListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.notifications.List(userID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// MarkRead godoc
+// @Summary 标记通知已读
+// @Tags 通知
+// @Security Bearer
+// @Produce json
+// @Param id path int true "通知ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	id, ok := feedbackIDParam(c, "id", "非法的通知ID")
+	if !ok {
+		return
+	}
+
+	if err := h.notifications.MarkRead(userID, id); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(nil).JSON(c)
+}
+
+// MarkAllRead godoc
+// @Summary 全部通知标记已读
+// @Tags 通知
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=dto.NotificationReadAllResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	resp, err := h.notifications.MarkAllRead(userID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
@@ -101,7 +101,7 @@ func (h *PointHandler) AdminListAllPoints(c *gin.Context) {
 	if result == nil {
 		result = &dto.AdminListPointsResponse{
 			Items:      []dto.UserPointListItem{},
-			Pagination: dto.Pagination{Page: query.Page, PageSize: query.PageSize, Total: 0},
+			Pagination: dto.NewPagination(query.Page, query.PageSize, 0),
 		}
 	}
 
@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
+	"github.com/javapub/mini-study/mini-study-backend/internal/report"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// ReportHandler 处理报表导出与定时发送接口。
+type ReportHandler struct {
+	reports *service.ReportService
+}
+
+// NewReportHandler 创建报表处理器。
+func NewReportHandler(reports *service.ReportService) *ReportHandler {
+	return &ReportHandler{reports: reports}
+}
+
+// Definitions godoc
+// @Summary 报表列表
+// @Description 返回可导出和定时发送的报表及其列
+// @Tags 报表
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]dto.ReportDefinition}
+// @Failure 401 {object} utils.Response
+// @Router /api/v1/reports/definitions [get]
+func (h *ReportHandler) Definitions(c *gin.Context) {
+	if middleware.GetUserID(c) == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(h.reports.Definitions()).JSON(c)
+}
+
+// Export godoc
+// @Summary 导出报表
+// @Description 店长导出本店团队的报表，管理员可按店长筛选，未筛选时为全部用户；数据与学习看板一致
+// @Tags 报表
+// @Security Bearer
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce text/csv
+// @Param report path string true "报表类型(team_learning/exam_results/points_ranking)"
+// @Param format query string false "文件格式 xlsx/csv，默认 xlsx"
+// @Param manager_id query int false "店长ID，仅管理员可用"
+// @Success 200 {file} file
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/reports/{report}/export [get]
+func (h *ReportHandler) Export(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var query dto.ReportExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	if query.Format == "" {
+		query.Format = report.FormatXLSX
+	}
+
+	kind := c.Param("report")
+	filename, err := h.reports.ExportFilename(kind, query.Format, time.Now())
+	if err != nil {
+		utils.NewErrorResponse(http.StatusNotFound, err.Error()).JSON(c)
+		return
+	}
+	w := utils.NewAttachmentWriter(c, filename, report.ContentType(query.Format))
+	if err := h.reports.Export(userID, kind, query, w); err != nil {
+		if !w.Started() {
+			utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+			return
+		}
+		_ = c.Error(err)
+	}
+}
+
+// ListSchedules godoc
+// @Summary 报表发送计划列表
+// @Description 店长查看自己的计划，管理员查看全部计划
+// @Tags 报表
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=dto.ReportScheduleListResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/reports/schedules [get]
+func (h *ReportHandler) ListSchedules(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	resp, err := h.reports.ListSchedules(userID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// CreateSchedule godoc
+// @Summary 创建报表发送计划
+// @Description 按每天、每周或每月的固定时刻生成报表并以邮件附件发送给收件人；需配置邮件服务
+// @Tags 报表
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.ReportScheduleRequest true "发送计划"
+// @Success 200 {object} utils.Response{data=dto.ReportScheduleResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/reports/schedules [post]
+func (h *ReportHandler) CreateSchedule(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var req dto.ReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.reports.CreateSchedule(userID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// UpdateSchedule godoc
+// @Summary 修改报表发送计划
+// @Description 修改后按新的频率重新计算下次发送时间
+// @Tags 报表
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "计划ID"
+// @Param request body dto.ReportScheduleRequest true "发送计划"
+// @Success 200 {object} utils.Response{data=dto.ReportScheduleResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/reports/schedules/{id} [put]
+func (h *ReportHandler) UpdateSchedule(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的计划ID").JSON(c)
+		return
+	}
+
+	var req dto.ReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.reports.UpdateSchedule(userID, uint(id), req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// DeleteSchedule godoc
+// @Summary 删除报表发送计划
+// @Tags 报表
+// @Security Bearer
+// @Produce json
+// @Param id path int true "计划ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/reports/schedules/{id} [delete]
+func (h *ReportHandler) DeleteSchedule(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的计划ID").JSON(c)
+		return
+	}
+
+	if err := h.reports.DeleteSchedule(userID, uint(id)); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(nil).JSON(c)
+}
+
+// SendSchedule godoc
+// @Summary 立即发送报表
+// @Description 立即按计划生成并发送一次报表，用于确认收件地址，不影响下次发送时间
+// @Tags 报表
+// @Security Bearer
+// @Produce json
+// @Param id path int true "计划ID"
+// @Success 200 {object} utils.Response{data=dto.ReportScheduleResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/reports/schedules/{id}/send [post]
+func (h *ReportHandler) SendSchedule(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的计划ID").JSON(c)
+		return
+	}
+
+	resp, err := h.reports.SendNow(c.Request.Context(), userID, uint(id))
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// ReviewHandler exposes the editorial workflow of contents and exams.
+type ReviewHandler struct {
+	reviews *service.ReviewService
+}
+
+// NewReviewHandler builds a ReviewHandler.
+func NewReviewHandler(reviews *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviews: reviews}
+}
+
+// AdminQueue godoc
+// @Summary 管理员查看审核列表
+// @Description 按审核状态分页列出内容与考试，默认返回审核中的条目，先提交的在前
+// @Tags 管理后台-审核
+// @Security Bearer
+// @Produce json
+// @Param entity_type query string false "实体类型(content/exam)"
+// @Param status query string false "审核状态(draft/in_review/approved)，默认 in_review"
+// @Param reviewer_id query int false "指定审核人ID"
+// @Param mine query bool false "只看指派给我的"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 submitted_at/updated_at/title/id，默认 submitted_at"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.ReviewQueueResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/reviews [get]
+func (h *ReviewHandler) AdminQueue(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var query dto.ReviewQueueQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	items, page, err := h.reviews.Queue(adminID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(dto.ReviewQueueResponse{Items: items, Pagination: page}).JSON(c)
+}
+
+// AdminDetail godoc
+// @Summary 管理员查看审核详情
+// @Description 返回内容或考试的审核状态、指定审核人、驳回原因及完整流转记录（提交、审核、评论、发布、下线等）
+// @Tags 管理后台-审核
+// @Security Bearer
+// @Produce json
+// @Param entity_type path string true "实体类型(content/exam)"
+// @Param entity_id path int true "实体ID"
+// @Success 200 {object} utils.Response{data=dto.ReviewDetailResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/reviews/{entity_type}/{entity_id} [get]
+func (h *ReviewHandler) AdminDetail(c *gin.Context) {
+	adminID, entityType, entityID, ok := h.entity(c)
+	if !ok {
+		return
+	}
+
+	detail, err := h.reviews.Detail(adminID, entityType, entityID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(detail).JSON(c)
+}
+
+// AdminSubmit godoc
+// @Summary 提交审核
+// @Description 将草稿提交审核，可同时指定审核人；指定后只有该审核人可以通过或驳回
+// @Tags 管理后台-审核
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param entity_type path string true "实体类型(content/exam)"
+// @Param entity_id path int true "实体ID"
+// @Param request body dto.ReviewSubmitRequest false "提交信息"
+// @Success 200 {object} utils.Response{data=dto.ReviewDetailResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/reviews/{entity_type}/{entity_id}/submit [post]
+func (h *ReviewHandler) AdminSubmit(c *gin.Context) {
+	adminID, entityType, entityID, ok := h.entity(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReviewSubmitRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+			return
+		}
+	}
+
+	detail, err := h.reviews.Submit(c.Request.Context(), adminID, entityType, entityID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(detail).JSON(c)
+}
+
+// AdminWithdraw godoc
+// @Summary 撤回审核
+// @Description 提交人撤回审核中的条目，退回草稿
+// @Tags 管理后台-审核
+// @Security Bearer
+// @Produce json
+// @Param entity_type path string true "实体类型(content/exam)"
+// @Param entity_id path int true "实体ID"
+// @Success 200 {object} utils.Response{data=dto.ReviewDetailResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/reviews/{entity_type}/{entity_id}/withdraw [post]
+func (h *ReviewHandler) AdminWithdraw(c *gin.Context) {
+	adminID, entityType, entityID, ok := h.entity(c)
+	if !ok {
+		return
+	}
+
+	detail, err := h.reviews.Withdraw(c.Request.Context(), adminID, entityType, entityID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(detail).JSON(c)
+}
+
+// AdminAssignReviewer godoc
+// @Summary 指派审核人
+// @Description 为草稿或审核中的条目指派审核人，审核人须为已启用的管理员
+// @Tags 管理后台-审核
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param entity_type path string true "实体类型(content/exam)"
+// @Param entity_id path int true "实体ID"
+// @Param request body dto.ReviewAssignRequest true "审核人"
+// @Success 200 {object} utils.Response{data=dto.ReviewDetailResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/reviews/{entity_type}/{entity_id}/reviewer [put]
+func (h *ReviewHandler) AdminAssignReviewer(c *gin.Context) {
+	adminID, entityType, entityID, ok := h.entity(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReviewAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	detail, err := h.reviews.AssignReviewer(c.Request.Context(), adminID, entityType, entityID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(detail).JSON(c)
+}
+
+// AdminApprove godoc
+// @Summary 审核通过
+// @Description 审核通过后条目可以发布或定时发布
+// @Tags 管理后台-审核
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param entity_type path string true "实体类型(content/exam)"
+// @Param entity_id path int true "实体ID"
+// @Param request body dto.ReviewApproveRequest false "审核意见"
+// @Success 200 {object} utils.Response{data=dto.ReviewDetailResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/reviews/{entity_type}/{entity_id}/approve [post]
+func (h *ReviewHandler) AdminApprove(c *gin.Context) {
+	adminID, entityType, entityID, ok := h.entity(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReviewApproveRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+			return
+		}
+	}
+
+	detail, err := h.reviews.Approve(c.Request.Context(), adminID, entityType, entityID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(detail).JSON(c)
+}
+
+// AdminReject godoc
+// @Summary 驳回审核
+// @Description 驳回审核中的条目并退回草稿，须填写驳回原因
+// @Tags 管理后台-审核
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param entity_type path string true "实体类型(content/exam)"
+// @Param entity_id path int true "实体ID"
+// @Param request body dto.ReviewRejectRequest true "驳回原因"
+// @Success 200 {object} utils.Response{data=dto.ReviewDetailResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/reviews/{entity_type}/{entity_id}/reject [post]
+func (h *ReviewHandler) AdminReject(c *gin.Context) {
+	adminID, entityType, entityID, ok := h.entity(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReviewRejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	detail, err := h.reviews.Reject(c.Request.Context(), adminID, entityType, entityID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(detail).JSON(c)
+}
+
+// AdminComment godoc
+// @Summary 添加审核评论
+// @Description 在任意状态下为条目添加审核评论，记入流转记录
+// @Tags 管理后台-审核
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param entity_type path string true "实体类型(content/exam)"
+// @Param entity_id path int true "实体ID"
+// @Param request body dto.ReviewCommentRequest true "评论"
+// @Success 200 {object} utils.Response{data=dto.ReviewDetailResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/reviews/{entity_type}/{entity_id}/comments [post]
+func (h *ReviewHandler) AdminComment(c *gin.Context) {
+	adminID, entityType, entityID, ok := h.entity(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReviewCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	detail, err := h.reviews.Comment(c.Request.Context(), adminID, entityType, entityID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(detail).JSON(c)
+}
+
+// entity reads the caller and the reviewed entity from the path, writing the error response
+// when either is missing.
+func (h *ReviewHandler) entity(c *gin.Context) (uint, string, uint, bool) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return 0, "", 0, false
+	}
+
+	entityID, err := strconv.ParseUint(c.Param("entity_id"), 10, 64)
+	if err != nil || entityID == 0 {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的实体ID").JSON(c)
+		return 0, "", 0, false
+	}
+	return adminID, c.Param("entity_type"), uint(entityID), true
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// ScheduleHandler exposes timed publishing of contents and exams to admins.
+type ScheduleHandler struct {
+	schedules *service.ScheduleService
+}
+
+// NewScheduleHandler builds a ScheduleHandler.
+func NewScheduleHandler(schedules *service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{schedules: schedules}
+}
+
+// AdminScheduleContent godoc
+// @Summary 管理员设置内容定时发布/下线
+// @Description 一并提交定时发布与定时下线时间，为空表示取消对应计划。只有审核通过的内容可以定时发布；已发布内容只能设置定时下线；未发布内容需先有定时发布才能设置定时下线，且下线时间须晚于发布时间
+// @Tags 管理后台-内容
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param request body dto.AdminScheduleRequest true "发布计划"
+// @Success 200 {object} utils.Response{data=dto.ScheduleStateResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/contents/{id}/schedule [put]
+func (h *ScheduleHandler) AdminScheduleContent(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	contentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "非法的内容ID").JSON(c)
+		return
+	}
+
+	var req dto.AdminScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	state, err := h.schedules.AdminScheduleContent(c.Request.Context(), adminID, uint(contentID), req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(state).JSON(c)
+}
+
+// AdminScheduleExam godoc
+// @Summary 管理员设置考试定时发布/归档
+// @Description 规则同内容定时发布，定时下线对考试表示归档
+// @Tags 管理后台-考试
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "考试ID"
+// @Param request body dto.AdminScheduleRequest true "发布计划"
+// @Success 200 {object} utils.Response{data=dto.ScheduleStateResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/exams/{id}/schedule [put]
+func (h *ScheduleHandler) AdminScheduleExam(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	examID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "非法的考试ID").JSON(c)
+		return
+	}
+
+	var req dto.AdminScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	state, err := h.schedules.AdminScheduleExam(c.Request.Context(), adminID, uint(examID), req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(state).JSON(c)
+}
+
+// AdminCalendar godoc
+// @Summary 管理员查看发布日历
+// @Description 按日期分组返回指定范围内计划中的内容/考试发布与下线，日期按服务器时区计算，范围不超过 92 天
+// @Tags 管理后台-内容
+// @Security Bearer
+// @Produce json
+// @Param from query string false "开始日期(YYYY-MM-DD)，默认今天"
+// @Param to query string false "结束日期(YYYY-MM-DD)，默认开始日期后 30 天"
+// @Param entity_type query string false "实体类型(content/exam)"
+// @Success 200 {object} utils.Response{data=dto.AdminScheduleCalendarResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/schedule [get]
+func (h *ScheduleHandler) AdminCalendar(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var query dto.AdminScheduleCalendarQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	calendar, err := h.schedules.AdminCalendar(adminID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(calendar).JSON(c)
+}
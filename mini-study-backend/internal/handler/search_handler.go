@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// SearchHandler 处理全站搜索接口。
+type SearchHandler struct {
+	service *service.SearchService
+}
+
+// NewSearchHandler 创建搜索处理器。
+func NewSearchHandler(svc *service.SearchService) *SearchHandler {
+	return &SearchHandler{service: svc}
+}
+
+// Search godoc
+// @Summary 全站搜索
+// @Description 在学习内容（标题、摘要、图文正文、文档页面文字）、考试（标题、描述、题干）与成长圈动态中检索，按相关度排序并返回高亮摘要与分面统计；仅返回当前用户角色可见的结果。中文按单字与双字切分，关键词中的每个词都需命中
+// @Tags 搜索
+// @Security Bearer
+// @Produce json
+// @Param q query string true "搜索关键词"
+// @Param kind query string false "结果类别(content/exam/growth)"
+// @Param type query string false "内容类型(doc/video/article)"
+// @Param category_id query int false "内容分类ID"
+// @Param role query string false "仅返回该角色可见的结果(employee/manager)"
+// @Param page query int false "页码，从1开始"
+// @Param page_size query int false "每页数量，默认20，最大50"
+// @Success 200 {object} utils.Response{data=dto.SearchResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var query dto.SearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	result, err := h.service.Search(c.Request.Context(), userID, query)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(result).JSON(c)
+}
+
+// AdminReindex godoc
+// @Summary 管理员重建搜索索引
+// @Description 从数据库重新加载全部已发布内容、考试与已通过的成长圈动态并替换索引；重建期间搜索仍使用旧索引
+// @Tags 管理后台-搜索
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=dto.SearchReindexResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/search/reindex [post]
+func (h *SearchHandler) AdminReindex(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	result, err := h.service.AdminReindex(c.Request.Context(), adminID)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(result).JSON(c)
+}
@@ -1,38 +1,44 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/middleware"
 	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
 	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
 )
 
 // UploadHandler handles file upload operations.
 type UploadHandler struct {
-	audit     *service.AuditService
-	uploadDir string
-	maxSize   int64
+	files    *service.FileService
+	sessions *service.UploadSessionService
 }
 
 // NewUploadHandler creates a handler.
-func NewUploadHandler(uploadDir string, maxSizeMB int, audit *service.AuditService) *UploadHandler {
-	return &UploadHandler{audit: audit, uploadDir: uploadDir, maxSize: int64(maxSizeMB) * 1024 * 1024}
+func NewUploadHandler(files *service.FileService, sessions *service.UploadSessionService) *UploadHandler {
+	return &UploadHandler{files: files, sessions: sessions}
 }
 
 // Upload godoc
 // @Summary 上传文件
-// @Description 登录用户上传文件后返回可用于内容配置的存储路径
+// @Description 登录用户按用途上传文件。服务端嗅探文件内容校验扩展名白名单，按内容 SHA-256 生成存储键；视频与文档为私有文件，返回带过期时间的签名链接
 // @Tags 文件
 // @Security Bearer
 // @Accept multipart/form-data
 // @Produce json
 // @Param file formData file true "待上传文件"
-// @Success 200 {object} utils.Response{data=map[string]string}
+// @Param purpose formData string false "上传用途(avatar/cover/video/growth_image/document)，为空时按扩展名推断"
+// @Success 200 {object} utils.Response{data=dto.UploadResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 400 {object} utils.Response
-// @Failure 500 {object} utils.Response
 // @Router /api/v1/files/upload [post]
 func (h *UploadHandler) Upload(c *gin.Context) {
 	file, err := c.FormFile("file")
@@ -41,17 +47,225 @@ func (h *UploadHandler) Upload(c *gin.Context) {
 		return
 	}
 
-	if file.Size > h.maxSize {
-		utils.NewErrorResponse(http.StatusBadRequest, "file too large").JSON(c)
+	var req dto.UploadRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
 	}
 
-	path, err := utils.SaveUploadedFile(file, h.uploadDir)
+	resp, err := h.files.Upload(c.Request.Context(), middleware.GetUserID(c), req.Purpose, file)
 	if err != nil {
-		utils.NewErrorResponse(http.StatusInternalServerError, err.Error()).JSON(c)
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// Download godoc
+// @Summary 通过签名链接下载私有文件
+// @Description 本地存储驱动下的私有文件（视频、文档）只能通过上传或内容详情返回的签名链接访问，链接过期或签名不符返回 403
+// @Tags 文件
+// @Produce octet-stream
+// @Param key path string true "存储键"
+// @Param expires query int true "过期时间（Unix秒）"
+// @Param signature query string true "签名"
+// @Success 200 {file} file
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/v1/files/download/{key} [get]
+func (h *UploadHandler) Download(c *gin.Context) {
+	var query dto.SignedDownloadQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusForbidden, "缺少签名参数").JSON(c)
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	body, info, err := h.files.OpenSigned(c.Request.Context(), key, query)
+	if err != nil {
+		status := http.StatusForbidden
+		if errors.Is(err, storage.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		utils.NewErrorResponse(status, err.Error()).JSON(c)
+		return
+	}
+	defer body.Close()
+
+	c.Header("Cache-Control", "private, max-age=0")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Type", info.ContentType)
+	if info.ETag != "" {
+		c.Header("ETag", info.ETag)
+	}
+	if seeker, ok := body.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, "", info.ModTime, seeker)
+		return
+	}
+	c.Header("Content-Length", strconv.FormatInt(info.Size, 10))
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, body)
+}
+
+// InitUploadSession godoc
+// @Summary 创建分片上传会话
+// @Description 大文件（如培训视频）先创建会话，再按 offset 顺序上传分片，最后调用完成接口合并。会话在最后一次上传分片后 session_ttl 内未完成会被清理
+// @Tags 文件
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param body body dto.InitUploadSessionRequest true "文件信息"
+// @Success 200 {object} utils.Response{data=dto.UploadSessionResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/files/uploads [post]
+func (h *UploadHandler) InitUploadSession(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	var req dto.InitUploadSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.sessions.Init(userID, req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// GetUploadSession godoc
+// @Summary 查询分片上传会话
+// @Description 网络中断后查询已接收字节数 received_bytes，从该偏移继续上传
+// @Tags 文件
+// @Security Bearer
+// @Produce json
+// @Param upload_id path string true "上传会话ID"
+// @Success 200 {object} utils.Response{data=dto.UploadSessionResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/files/uploads/{upload_id} [get]
+func (h *UploadHandler) GetUploadSession(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	resp, err := h.sessions.Get(userID, c.Param("upload_id"))
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// UploadChunk godoc
+// @Summary 上传分片
+// @Description 以 multipart 表单上传一个分片，offset 必须等于当前 received_bytes，否则返回 409；会话正被其他请求处理时也返回 409；可附带分片 SHA-256 校验
+// @Tags 文件
+// @Security Bearer
+// @Accept multipart/form-data
+// @Produce json
+// @Param upload_id path string true "上传会话ID"
+// @Param file formData file true "分片数据"
+// @Param offset formData int true "分片起始偏移"
+// @Param checksum formData string false "分片SHA-256（十六进制）"
+// @Success 200 {object} utils.Response{data=dto.UploadSessionResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/v1/files/uploads/{upload_id}/chunks [put]
+func (h *UploadHandler) UploadChunk(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
 		return
 	}
 
-	_ = h.audit.Record(0, "upload", "files", file.Filename, "success")
-	utils.NewSuccessResponse(gin.H{"path": path}).JSON(c)
+	chunk, err := c.FormFile("file")
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, "file is required").JSON(c)
+		return
+	}
+	var req dto.UploadChunkRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp, err := h.sessions.UploadChunk(userID, c.Param("upload_id"), req, chunk)
+	if err != nil {
+		utils.NewErrorResponse(uploadSessionStatus(err), err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// CompleteUploadSession godoc
+// @Summary 完成分片上传
+// @Description 所有分片上传完成后合并文件，按用途校验文件内容并写入存储，返回与普通上传相同的结果；可重复调用
+// @Tags 文件
+// @Security Bearer
+// @Produce json
+// @Param upload_id path string true "上传会话ID"
+// @Success 200 {object} utils.Response{data=dto.UploadResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/v1/files/uploads/{upload_id}/complete [post]
+func (h *UploadHandler) CompleteUploadSession(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	resp, err := h.sessions.Complete(c.Request.Context(), userID, c.Param("upload_id"))
+	if err != nil {
+		utils.NewErrorResponse(uploadSessionStatus(err), err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AbortUploadSession godoc
+// @Summary 取消分片上传
+// @Description 取消上传会话并删除已接收的分片
+// @Tags 文件
+// @Security Bearer
+// @Produce json
+// @Param upload_id path string true "上传会话ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/v1/files/uploads/{upload_id} [delete]
+func (h *UploadHandler) AbortUploadSession(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	if err := h.sessions.Abort(userID, c.Param("upload_id")); err != nil {
+		utils.NewErrorResponse(uploadSessionStatus(err), err.Error()).JSON(c)
+		return
+	}
+	utils.NewSuccessResponse(nil).JSON(c)
+}
+
+// uploadSessionStatus maps upload session errors to HTTP statuses: an offset mismatch or a
+// session busy with another request is a conflict the client resolves by retrying.
+func uploadSessionStatus(err error) int {
+	if errors.Is(err, service.ErrUploadOffsetMismatch) || errors.Is(err, service.ErrUploadSessionBusy) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
 }
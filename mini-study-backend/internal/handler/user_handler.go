@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 
@@ -41,7 +42,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 		return
 	}
 
-	user, err := h.users.Register(req)
+	user, err := h.users.Register(c.Request.Context(), req)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -75,7 +76,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, err := h.users.Login(req)
+	user, err := h.users.Login(c.Request.Context(), req)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusUnauthorized, err.Error()).JSON(c)
 		return
@@ -92,19 +93,30 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 // ListManagers godoc
 // @Summary 查询店长列表
-// @Description 返回所有启用状态的店长供员工注册或绑定使用
+// @Description 分页返回启用状态的店长供员工注册或绑定使用
 // @Tags 用户
 // @Produce json
-// @Success 200 {object} utils.Response{data=[]dto.UserResponse}
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 id/work_no/name/role/created_at，默认 id"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.ManagerListResponse}
+// @Failure 400 {object} utils.Response
 // @Router /api/v1/users/managers [get]
 func (h *UserHandler) ListManagers(c *gin.Context) {
-	users, err := h.users.ListManagers()
+	var query dto.ManagerListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	users, page, err := h.users.ListManagers(query)
 	if err != nil {
-		utils.NewErrorResponse(http.StatusInternalServerError, err.Error()).JSON(c)
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
 	}
 
-	var resp []dto.UserResponse
+	resp := make([]dto.UserResponse, 0, len(users))
 	for _, u := range users {
 		resp = append(resp, dto.UserResponse{
 			ID:     u.ID,
@@ -116,7 +128,7 @@ func (h *UserHandler) ListManagers(c *gin.Context) {
 		})
 	}
 
-	utils.NewSuccessResponse(resp).JSON(c)
+	utils.NewSuccessResponse(dto.ManagerListResponse{Items: resp, Pagination: page}).JSON(c)
 }
 
 // RefreshToken godoc
@@ -213,13 +225,17 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 
 // AdminListUsers godoc
 // @Summary 管理员查询用户列表
-// @Description 管理员可根据角色、关键词筛选用户，并查看其店长绑定
+// @Description 管理员可根据角色、关键词筛选用户，并查看其店长绑定；分页返回，默认按ID倒序
 // @Tags 管理后台-用户
 // @Security Bearer
 // @Produce json
 // @Param role query string false "角色 employee/manager/admin"
 // @Param keyword query string false "关键词（工号/姓名/手机号）"
-// @Success 200 {object} utils.Response{data=[]dto.AdminUserResponse}
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param sort query string false "排序字段 id/work_no/name/role/created_at，默认 id"
+// @Param order query string false "排序方向 asc/desc"
+// @Success 200 {object} utils.Response{data=dto.AdminUserListResponse}
 // @Failure 400 {object} utils.Response
 // @Router /api/v1/admin/users [get]
 func (h *UserHandler) AdminListUsers(c *gin.Context) {
@@ -309,7 +325,7 @@ func (h *UserHandler) AdminUpdateUserRole(c *gin.Context) {
 		return
 	}
 
-	user, err := h.users.AdminUpdateUserRole(adminID, uint(targetID), model.Role(req.Role))
+	user, err := h.users.AdminUpdateUserRole(c.Request.Context(), adminID, uint(targetID), model.Role(req.Role))
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -391,7 +407,7 @@ func (h *UserHandler) AdminCreateEmployee(c *gin.Context) {
 		return
 	}
 
-	user, err := h.users.CreateEmployee(adminID, req)
+	user, err := h.users.CreateEmployee(c.Request.Context(), adminID, req)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -432,7 +448,7 @@ func (h *UserHandler) AdminPromoteToManager(c *gin.Context) {
 		return
 	}
 
-	user, err := h.users.PromoteToManager(adminID, uint(targetID))
+	user, err := h.users.PromoteToManager(c.Request.Context(), adminID, uint(targetID))
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
@@ -451,7 +467,7 @@ func (h *UserHandler) AdminPromoteToManager(c *gin.Context) {
 
 // AdminUpdateEmployeeManagers godoc
 // @Summary 管理员维护员工店长绑定
-// @Description 覆盖式更新某个员工绑定的店长工号列表
+// @Description 覆盖式更新某个员工绑定的店长工号列表，并记录调动历史；已禁用用户不能修改
 // @Tags 管理后台-用户
 // @Security Bearer
 // @Accept json
@@ -481,7 +497,169 @@ func (h *UserHandler) AdminUpdateEmployeeManagers(c *gin.Context) {
 		return
 	}
 
-	user, err := h.users.UpdateEmployeeManagers(adminID, uint(targetID), req.ManagerWorkNos)
+	user, err := h.users.UpdateEmployeeManagers(c.Request.Context(), adminID, uint(targetID), req.ManagerWorkNos)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	resp := dto.UserResponse{
+		ID:     user.ID,
+		WorkNo: user.WorkNo,
+		Phone:  user.Phone,
+		Name:   user.Name,
+		Role:   user.Role,
+		Status: user.Status,
+	}
+	utils.NewSuccessResponse(resp).JSON(c)
+}
+
+// AdminDeactivateUser godoc
+// @Summary 管理员禁用用户
+// @Description 禁用指定用户，立即吊销其已签发的令牌并解除店长-员工绑定
+// @Tags 管理后台-用户
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param body body dto.AdminUserLifecycleRequest false "操作原因"
+// @Success 200 {object} utils.Response{data=dto.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/users/{id}/deactivate [post]
+func (h *UserHandler) AdminDeactivateUser(c *gin.Context) {
+	h.handleLifecycle(c, h.users.DeactivateUser)
+}
+
+// AdminReactivateUser godoc
+// @Summary 管理员重新启用用户
+// @Description 重新启用被禁用的用户，已离职用户不可启用
+// @Tags 管理后台-用户
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param body body dto.AdminUserLifecycleRequest false "操作原因"
+// @Success 200 {object} utils.Response{data=dto.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/users/{id}/reactivate [post]
+func (h *UserHandler) AdminReactivateUser(c *gin.Context) {
+	h.handleLifecycle(c, h.users.ReactivateUser)
+}
+
+// AdminOffboardUser godoc
+// @Summary 管理员办理离职
+// @Description 永久禁用用户并匿名化姓名、手机号与工号，学习、考试与积分记录保留用于统计
+// @Tags 管理后台-用户
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param body body dto.AdminUserLifecycleRequest false "离职原因"
+// @Success 200 {object} utils.Response{data=dto.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/users/{id}/offboard [post]
+func (h *UserHandler) AdminOffboardUser(c *gin.Context) {
+	h.handleLifecycle(c, h.users.OffboardUser)
+}
+
+// AdminTransferEmployee godoc
+// @Summary 管理员调动员工
+// @Description 将员工调动到新的店长名下，并记录调动历史
+// @Tags 管理后台-用户
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "员工ID"
+// @Param body body dto.AdminTransferEmployeeRequest true "调入店长工号与原因"
+// @Success 200 {object} utils.Response{data=dto.EmployeeTransferResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/users/{id}/transfer [post]
+func (h *UserHandler) AdminTransferEmployee(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	idStr := c.Param("id")
+	targetID, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil || targetID == 0 {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的用户ID").JSON(c)
+		return
+	}
+
+	var req dto.AdminTransferEmployeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	transfer, err := h.users.TransferEmployee(c.Request.Context(), adminID, uint(targetID), req)
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	utils.NewSuccessResponse(transfer).JSON(c)
+}
+
+// AdminListEmployeeTransfers godoc
+// @Summary 管理员查询员工调动历史
+// @Description 返回指定员工的店长调动记录，按时间倒序
+// @Tags 管理后台-用户
+// @Security Bearer
+// @Produce json
+// @Param id path int true "员工ID"
+// @Success 200 {object} utils.Response{data=[]dto.EmployeeTransferResponse}
+// @Failure 400 {object} utils.Response
+// @Router /api/v1/admin/users/{id}/transfers [get]
+func (h *UserHandler) AdminListEmployeeTransfers(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	idStr := c.Param("id")
+	targetID, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil || targetID == 0 {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的用户ID").JSON(c)
+		return
+	}
+
+	transfers, err := h.users.ListEmployeeTransfers(adminID, uint(targetID))
+	if err != nil {
+		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+		return
+	}
+
+	utils.NewSuccessResponse(transfers).JSON(c)
+}
+
+// handleLifecycle binds the shared lifecycle payload and applies an account status change.
+func (h *UserHandler) handleLifecycle(c *gin.Context, apply func(ctx context.Context, adminID, targetID uint, reason string) (*model.User, error)) {
+	adminID := middleware.GetUserID(c)
+	if adminID == 0 {
+		utils.NewErrorResponse(http.StatusUnauthorized, "未登录").JSON(c)
+		return
+	}
+
+	idStr := c.Param("id")
+	targetID, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil || targetID == 0 {
+		utils.NewErrorResponse(http.StatusBadRequest, "无效的用户ID").JSON(c)
+		return
+	}
+
+	var req dto.AdminUserLifecycleRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
+			return
+		}
+	}
+
+	user, err := apply(c.Request.Context(), adminID, uint(targetID), req.Reason)
 	if err != nil {
 		utils.NewErrorResponse(http.StatusBadRequest, err.Error()).JSON(c)
 		return
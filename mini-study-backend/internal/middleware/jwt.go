@@ -11,8 +11,12 @@ import (
 
 const userIDKey = "userID"
 
+// SessionValidator checks that the token owner may still access the API,
+// e.g. the account is enabled and the token has not been revoked.
+type SessionValidator func(userID, tokenVersion uint) error
+
 // JWT protects routes using bearer tokens.
-func JWT(secret string) gin.HandlerFunc {
+func JWT(secret string, validate SessionValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		header := c.GetHeader("Authorization")
 		token := strings.TrimPrefix(header, "Bearer ")
@@ -29,7 +33,16 @@ func JWT(secret string) gin.HandlerFunc {
 			return
 		}
 
+		if validate != nil {
+			if err := validate(claims.UserID, claims.TokenVersion); err != nil {
+				utils.NewErrorResponse(http.StatusUnauthorized, err.Error()).JSON(c)
+				c.Abort()
+				return
+			}
+		}
+
 		c.Set(userIDKey, claims.UserID)
+		c.Request = c.Request.WithContext(utils.WithUserID(c.Request.Context(), claims.UserID))
 		c.Next()
 	}
 }
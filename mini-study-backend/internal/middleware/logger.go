@@ -7,6 +7,8 @@ import (
 	"go.uber.org/zap"
 )
 
+const logFieldsKey = "log_fields"
+
 // RequestLogger writes structured access logs using zap.
 func RequestLogger(logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -14,13 +16,25 @@ func RequestLogger(logger *zap.Logger) gin.HandlerFunc {
 		c.Next()
 		latency := time.Since(start)
 
-		logger.Info("request completed",
+		fields := []zap.Field{
 			zap.String("method", c.Request.Method),
 			zap.String("path", c.Request.URL.Path),
 			zap.Int("status", c.Writer.Status()),
 			zap.Duration("latency", latency),
 			zap.String("client_ip", c.ClientIP()),
 			zap.String("request_id", GetRequestID(c)),
-		)
+		}
+		if extra, ok := c.Get(logFieldsKey); ok {
+			fields = append(fields, extra.([]zap.Field)...)
+		}
+		logger.Info("request completed", fields...)
+	}
+}
+
+// AddLogFields attaches extra fields to the access log entry of the current request.
+func AddLogFields(c *gin.Context, fields ...zap.Field) {
+	if existing, ok := c.Get(logFieldsKey); ok {
+		fields = append(existing.([]zap.Field), fields...)
 	}
+	c.Set(logFieldsKey, fields)
 }
@@ -3,18 +3,24 @@ package middleware
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
 )
 
 const requestIDKey = "X-Request-ID"
 
+// maxRequestIDLength bounds client supplied ids; longer values are replaced by a generated one.
+const maxRequestIDLength = 64
+
 // RequestID ensures every request has a unique identifier for tracing.
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		rid := c.GetHeader(requestIDKey)
-		if rid == "" {
+		if rid == "" || len(rid) > maxRequestIDLength {
 			rid = uuid.NewString()
 		}
 		c.Set(requestIDKey, rid)
+		c.Request = c.Request.WithContext(utils.WithRequestID(c.Request.Context(), rid))
 		c.Writer.Header().Set(requestIDKey, rid)
 		c.Next()
 	}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // TableName 指定表名
 func (AuditLog) TableName() string {
 	return "audit_logs"
@@ -8,9 +10,35 @@ func (AuditLog) TableName() string {
 // AuditLog stores audit info for critical operations.
 type AuditLog struct {
 	Base
-	ActorID uint   `gorm:"comment:操作者ID" json:"actor_id"`
-	Action  string `gorm:"size:64;comment:操作动作" json:"action"`
-	Target  string `gorm:"size:128;comment:操作目标" json:"target"`
-	Payload string `gorm:"type:text;comment:操作载荷(JSON格式)" json:"payload"`
-	Result  string `gorm:"size:32;comment:操作结果(success成功/failed失败)" json:"result"`
+	ActorID    uint   `gorm:"index;comment:操作者ID" json:"actor_id"`
+	Action     string `gorm:"size:64;index;comment:操作动作" json:"action"`
+	Target     string `gorm:"size:128;comment:操作目标" json:"target"`
+	Payload    string `gorm:"type:text;comment:操作载荷(JSON格式)" json:"payload"`
+	Result     string `gorm:"size:32;comment:操作结果(success成功/failed失败)" json:"result"`
+	RequestID  string `gorm:"size:64;index;comment:请求ID(X-Request-ID)" json:"request_id"`
+	EntityType string `gorm:"size:32;index:idx_audit_entity,priority:1;comment:实体类型(content/exam/banner/notice/user/user_point/content_checkpoint)" json:"entity_type"`
+	EntityID   uint   `gorm:"index:idx_audit_entity,priority:2;comment:实体ID" json:"entity_id"`
+	Changes    string `gorm:"type:text;comment:字段级变更前后值(JSON数组)" json:"-"`
+}
+
+// TableName 指定表名
+func (AuditLogArchive) TableName() string {
+	return "audit_log_archives"
+}
+
+// AuditLogArchive keeps audit entries moved out of audit_logs by the retention job.
+// ID mirrors the original audit_logs.id so archived rows stay traceable.
+type AuditLogArchive struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement:false;comment:原审计日志ID" json:"id"`
+	ActorID    uint      `gorm:"index;comment:操作者ID" json:"actor_id"`
+	Action     string    `gorm:"size:64;comment:操作动作" json:"action"`
+	Target     string    `gorm:"size:128;comment:操作目标" json:"target"`
+	Payload    string    `gorm:"type:text;comment:操作载荷(JSON格式)" json:"payload"`
+	Result     string    `gorm:"size:32;comment:操作结果(success成功/failed失败)" json:"result"`
+	RequestID  string    `gorm:"size:64;comment:请求ID(X-Request-ID)" json:"request_id"`
+	EntityType string    `gorm:"size:32;index:idx_audit_archive_entity,priority:1;comment:实体类型" json:"entity_type"`
+	EntityID   uint      `gorm:"index:idx_audit_archive_entity,priority:2;comment:实体ID" json:"entity_id"`
+	Changes    string    `gorm:"type:text;comment:字段级变更前后值(JSON数组)" json:"-"`
+	CreatedAt  time.Time `gorm:"index;comment:原创建时间" json:"created_at"`
+	ArchivedAt time.Time `gorm:"comment:归档时间" json:"archived_at"`
 }
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// TableName 指定表名
+func (CertificateTemplate) TableName() string {
+	return "certificate_templates"
+}
+
+// CertificateTemplate 证书模板：管理员设计证书的标题、正文与落款。
+// 绑定试卷的模板只用于该试卷，ExamID 为 0 的启用模板是未绑定模板的试卷的默认模板。
+type CertificateTemplate struct {
+	Base
+	Name        string `gorm:"size:100;not null;comment:模板名称" json:"name"`
+	ExamID      uint   `gorm:"index;default:0;comment:适用试卷ID(0表示默认模板)" json:"exam_id"`
+	Title       string `gorm:"size:50;comment:证书标题" json:"title"`
+	Body        string `gorm:"type:text;comment:证书正文(支持{name}/{work_no}/{exam_title}/{score}/{total_score}/{date}/{serial}占位符)" json:"body"`
+	Issuer      string `gorm:"size:100;comment:颁发单位" json:"issuer"`
+	Signer      string `gorm:"size:50;comment:签发人" json:"signer"`
+	Orientation string `gorm:"size:16;default:'landscape';comment:版式(landscape横版/portrait竖版)" json:"orientation"`
+	AccentColor string `gorm:"size:7;default:'#B8860B';comment:主题色" json:"accent_color"`
+	Enabled     bool   `gorm:"default:true;comment:是否启用" json:"enabled"`
+	CreatorID   uint   `gorm:"comment:创建者ID" json:"creator_id"`
+}
+
+// TableName 指定表名
+func (Certificate) TableName() string {
+	return "certificates"
+}
+
+// Certificate 考试证书：每次通过的考试记录对应一张证书，保存颁发时的姓名、成绩等快照与 PDF 文件。
+type Certificate struct {
+	Base
+	Serial     string    `gorm:"size:32;uniqueIndex;not null;comment:证书编号" json:"serial"`
+	AttemptID  uint      `gorm:"uniqueIndex;not null;comment:考试记录ID" json:"attempt_id"`
+	UserID     uint      `gorm:"index;not null;comment:持证人ID" json:"user_id"`
+	ExamID     uint      `gorm:"index;not null;comment:试卷ID" json:"exam_id"`
+	TemplateID uint      `gorm:"comment:证书模板ID" json:"template_id"`
+	HolderName string    `gorm:"size:100;comment:持证人姓名" json:"holder_name"`
+	WorkNo     string    `gorm:"size:50;comment:持证人工号" json:"work_no"`
+	ExamTitle  string    `gorm:"size:200;comment:试卷标题" json:"exam_title"`
+	Score      int       `gorm:"comment:得分" json:"score"`
+	TotalScore int       `gorm:"comment:总分" json:"total_score"`
+	IssuedAt   time.Time `gorm:"comment:颁发时间(考试通过时间)" json:"issued_at"`
+	FileKey    string    `gorm:"size:255;comment:PDF存储键" json:"-"`
+	FileSize   int64     `gorm:"comment:PDF文件大小(字节)" json:"file_size"`
+}
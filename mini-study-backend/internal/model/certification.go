@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// TableName 指定表名
+func (Certification) TableName() string {
+	return "certifications"
+}
+
+// Certification 资质认证：绑定一张试卷，通过考试即取得认证，有效期从通过之日起计算。
+// 到期前 RenewalWindowDays 天起开放重新考试并提醒持证人，过期后须重新考试。
+type Certification struct {
+	Base
+	Name              string `gorm:"size:100;not null;comment:认证名称" json:"name"`
+	Description       string `gorm:"type:text;comment:认证说明" json:"description"`
+	ExamID            uint   `gorm:"index;not null;comment:关联试卷ID" json:"exam_id"`
+	ValidityMonths    int    `gorm:"not null;comment:有效期(月)" json:"validity_months"`
+	RenewalWindowDays int    `gorm:"default:30;comment:到期前多少天开放重新考试并提醒" json:"renewal_window_days"`
+	Enabled           bool   `gorm:"default:true;comment:是否启用" json:"enabled"`
+	CreatorID         uint   `gorm:"comment:创建者ID" json:"creator_id"`
+}
+
+// TableName 指定表名
+func (UserCertification) TableName() string {
+	return "user_certifications"
+}
+
+// UserCertification 用户持有的认证，记录最近一次通过关联试卷的时间与到期时间。
+// 重新考试通过后更新通过与到期时间，并清空提醒记录。
+type UserCertification struct {
+	Base
+	CertificationID   uint       `gorm:"uniqueIndex:idx_user_certification,priority:1;comment:认证ID" json:"certification_id"`
+	UserID            uint       `gorm:"uniqueIndex:idx_user_certification,priority:2;index;comment:用户ID" json:"user_id"`
+	AttemptID         uint       `gorm:"comment:最近一次通过的考试记录ID" json:"attempt_id"`
+	PassedAt          time.Time  `gorm:"comment:通过时间" json:"passed_at"`
+	ExpiresAt         time.Time  `gorm:"index;comment:到期时间" json:"expires_at"`
+	RenewalNotifiedAt *time.Time `gorm:"comment:即将到期提醒时间" json:"renewal_notified_at,omitempty"`
+	ExpiredNotifiedAt *time.Time `gorm:"comment:已过期提醒时间" json:"expired_notified_at,omitempty"`
+}
@@ -34,10 +34,17 @@ type Content struct {
 	CoverURL        string          `gorm:"size:512;comment:封面图片URL" json:"cover_url"`
 	Summary         string          `gorm:"type:text;comment:摘要" json:"summary"`
 	BodyBlocksJSON  string          `gorm:"type:longtext;comment:图文内容结构(JSON)" json:"-"`
-	Status          string          `gorm:"size:16;comment:状态(draft草稿/published已发布)" json:"status"`
+	Status          string          `gorm:"size:16;comment:状态(draft草稿/in_review审核中/approved已通过/published已发布/offline已下线)" json:"status"`
 	PublishAt       *time.Time      `gorm:"comment:发布时间" json:"publish_at"`
 	CreatorID       uint            `gorm:"comment:创建者ID" json:"creator_id"`
 	DurationSeconds int64           `gorm:"comment:时长(秒)" json:"duration_seconds"`
+	HLSPath         string          `gorm:"size:512;comment:HLS主播放列表存储键" json:"hls_path"`
+	PageCount       int             `gorm:"default:0;comment:文档预览页数" json:"page_count"`
+
+	ScheduledPublishAt *time.Time `gorm:"index;comment:定时发布时间" json:"scheduled_publish_at"`
+	ScheduledOfflineAt *time.Time `gorm:"index;comment:定时下线时间" json:"scheduled_offline_at"`
+
+	ReviewState
 }
 
 // TableName 指定表名
@@ -52,6 +59,24 @@ type LearningRecord struct {
 	ContentID     uint       `gorm:"uniqueIndex:idx_user_content;comment:内容ID" json:"content_id"`
 	Progress      int        `gorm:"default:0;comment:学习进度(百分比)" json:"progress"`
 	VideoPosition int64      `gorm:"default:0;comment:视频观看位置(秒)" json:"video_position"`
+	PagesRead     int        `gorm:"default:0;comment:文档已读页数" json:"pages_read"`
+	LastPage      int        `gorm:"default:0;comment:文档最近阅读页码" json:"last_page"`
 	Status        string     `gorm:"size:16;comment:状态(learning学习中/completed已完成)" json:"status"`
 	CompletedAt   *time.Time `gorm:"comment:完成时间" json:"completed_at"`
 }
+
+// TableName 指定表名
+func (LearningActivity) TableName() string {
+	return "learning_daily_activities"
+}
+
+// LearningActivity 记录用户某天学习某个内容的情况。学习记录只保留最后一次更新，
+// 按天留存的活动用于统计历史上每个周期的活跃学员与观看时长。
+type LearningActivity struct {
+	Base
+	UserID       uint      `gorm:"uniqueIndex:idx_learning_activity,priority:1;comment:用户ID" json:"user_id"`
+	ContentID    uint      `gorm:"uniqueIndex:idx_learning_activity,priority:2;comment:内容ID" json:"content_id"`
+	ActivityDate time.Time `gorm:"type:date;uniqueIndex:idx_learning_activity,priority:3;index;comment:学习日期" json:"activity_date"`
+	Updates      int64     `gorm:"default:0;comment:当天进度上报次数" json:"updates"`
+	WatchSeconds int64     `gorm:"default:0;comment:当天视频播放位置推进的秒数" json:"watch_seconds"`
+}
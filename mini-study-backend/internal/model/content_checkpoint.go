@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// TableName 指定表名
+func (ContentCheckpoint) TableName() string {
+	return "content_checkpoints"
+}
+
+// ContentCheckpoint 学习内容中的随堂测验检查点，学员答对后才能继续学习。Position 的含义随内容类型而定：
+// 视频为播放秒数，文档为页码（读完该页后作答），图文为前面的内容块数量。
+type ContentCheckpoint struct {
+	Base
+	ContentID    uint   `gorm:"index;comment:内容ID" json:"content_id"`
+	Position     int64  `gorm:"default:0;comment:位置(视频秒数/文档页码/图文内容块序号)" json:"position"`
+	QuestionType string `gorm:"size:16;comment:题型(single单选/multiple多选)" json:"question_type"`
+	Stem         string `gorm:"type:text;comment:题干" json:"stem"`
+	OptionsJSON  string `gorm:"type:text;comment:选项(JSON)" json:"-"`
+	Analysis     string `gorm:"type:text;comment:解析" json:"analysis"`
+	Points       int    `gorm:"default:0;comment:首次作答即答对奖励的积分" json:"points"`
+	CreatorID    uint   `gorm:"comment:创建者ID" json:"creator_id"`
+}
+
+// TableName 指定表名
+func (CheckpointAnswer) TableName() string {
+	return "checkpoint_answers"
+}
+
+// CheckpointAnswer 记录用户对检查点的作答情况，每个用户每个检查点一条。
+type CheckpointAnswer struct {
+	Base
+	UserID          uint       `gorm:"uniqueIndex:idx_user_checkpoint;comment:用户ID" json:"user_id"`
+	CheckpointID    uint       `gorm:"uniqueIndex:idx_user_checkpoint;comment:检查点ID" json:"checkpoint_id"`
+	ContentID       uint       `gorm:"index;comment:内容ID" json:"content_id"`
+	Attempts        int        `gorm:"default:0;comment:作答次数" json:"attempts"`
+	SelectedOptions string     `gorm:"size:255;comment:最近一次选择的选项序号(逗号分隔)" json:"selected_options"`
+	Passed          bool       `gorm:"default:false;comment:是否已答对" json:"passed"`
+	PassedAt        *time.Time `gorm:"comment:答对时间" json:"passed_at"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// TableName 指定表名
+func (ContentRating) TableName() string {
+	return "content_ratings"
+}
+
+// ContentRating 学员对学习内容的评分与评价，每个用户每个内容一条。评分立即计入平均分，
+// 评价文字需经管理员审核通过后才公开展示。
+type ContentRating struct {
+	Base
+	ContentID  uint       `gorm:"uniqueIndex:idx_content_user_rating;comment:内容ID" json:"content_id"`
+	UserID     uint       `gorm:"uniqueIndex:idx_content_user_rating;index;comment:评分用户ID" json:"user_id"`
+	User       User       `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Rating     int        `gorm:"not null;comment:评分(1-5星)" json:"rating"`
+	Review     string     `gorm:"type:text;comment:评价文字" json:"review"`
+	Status     string     `gorm:"size:16;default:'pending';comment:评价审核状态(pending待审核/approved已通过/rejected已拒绝)" json:"status"`
+	ApprovedAt *time.Time `gorm:"comment:审核通过时间" json:"approved_at,omitempty"`
+}
+
+// TableName 指定表名
+func (ContentComment) TableName() string {
+	return "content_comments"
+}
+
+// ContentComment 学习内容下的评论，ParentID 指向被回复的评论以形成楼中楼，审核通过后才公开展示。
+type ContentComment struct {
+	Base
+	ContentID  uint       `gorm:"index;comment:内容ID" json:"content_id"`
+	ParentID   *uint      `gorm:"index;comment:回复的评论ID，为空表示顶层评论" json:"parent_id,omitempty"`
+	UserID     uint       `gorm:"index;comment:评论用户ID" json:"user_id"`
+	User       User       `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Body       string     `gorm:"type:text;not null;comment:评论内容" json:"body"`
+	Status     string     `gorm:"size:16;default:'pending';comment:状态(pending待审核/approved已通过/rejected已拒绝)" json:"status"`
+	ApprovedAt *time.Time `gorm:"comment:审核通过时间" json:"approved_at,omitempty"`
+}
@@ -0,0 +1,29 @@
+package model
+
+// TableName 指定表名
+func (ContentPage) TableName() string {
+	return "content_pages"
+}
+
+// ContentPage 文档内容转换后的单页预览：页面图片与提取的文本。
+type ContentPage struct {
+	Base
+	ContentID uint   `gorm:"uniqueIndex:idx_content_page;comment:内容ID" json:"content_id"`
+	PageNo    int    `gorm:"uniqueIndex:idx_content_page;comment:页码(从1开始)" json:"page_no"`
+	ImageKey  string `gorm:"size:512;comment:页面图片存储键" json:"image_key"`
+	Text      string `gorm:"type:text;comment:页面文本" json:"text"`
+}
+
+// TableName 指定表名
+func (LearningPageView) TableName() string {
+	return "learning_page_views"
+}
+
+// LearningPageView 记录用户在文档某一页上的累计阅读时长。
+type LearningPageView struct {
+	Base
+	UserID          uint  `gorm:"uniqueIndex:idx_user_content_page;comment:用户ID" json:"user_id"`
+	ContentID       uint  `gorm:"uniqueIndex:idx_user_content_page;comment:内容ID" json:"content_id"`
+	PageNo          int   `gorm:"uniqueIndex:idx_user_content_page;comment:页码" json:"page_no"`
+	DurationSeconds int64 `gorm:"default:0;comment:累计阅读时长(秒)" json:"duration_seconds"`
+}
@@ -0,0 +1,34 @@
+package model
+
+// Content revision sources.
+const (
+	RevisionActionBaseline = "baseline"
+	RevisionActionCreate   = "create"
+	RevisionActionUpdate   = "update"
+	RevisionActionRollback = "rollback"
+)
+
+// TableName 指定表名
+func (ContentRevision) TableName() string {
+	return "content_revisions"
+}
+
+// ContentRevision 内容的不可变修订版本。创建内容及每次修改标题、正文、文件等字段时生成一条，用于比对与回滚；
+// 回滚本身也生成新版本，历史版本从不修改。
+type ContentRevision struct {
+	Base
+	ContentID       uint   `gorm:"uniqueIndex:idx_content_version;comment:内容ID" json:"content_id"`
+	Version         int    `gorm:"uniqueIndex:idx_content_version;comment:版本号(从1递增)" json:"version"`
+	Action          string `gorm:"size:16;comment:来源(baseline历史基线/create创建/update修改/rollback回滚)" json:"action"`
+	SourceVersion   int    `gorm:"default:0;comment:回滚来源版本号" json:"source_version"`
+	AuthorID        uint   `gorm:"comment:修改人ID" json:"author_id"`
+	Title           string `gorm:"size:255;comment:标题" json:"title"`
+	Type            string `gorm:"size:16;comment:内容类型" json:"type"`
+	CategoryID      uint   `gorm:"comment:分类ID" json:"category_id"`
+	VisibleRoles    string `gorm:"size:16;comment:可见角色" json:"visible_roles"`
+	FilePath        string `gorm:"size:512;comment:文件路径" json:"file_path"`
+	CoverURL        string `gorm:"size:512;comment:封面图片URL" json:"cover_url"`
+	Summary         string `gorm:"type:text;comment:摘要" json:"summary"`
+	BodyBlocksJSON  string `gorm:"type:longtext;comment:图文内容结构(JSON)" json:"-"`
+	DurationSeconds int64  `gorm:"comment:时长(秒)" json:"duration_seconds"`
+}
@@ -0,0 +1,16 @@
+package model
+
+// TableName 指定表名
+func (EmployeeTransfer) TableName() string {
+	return "employee_transfers"
+}
+
+// EmployeeTransfer 记录员工在店长之间的调动历史。
+type EmployeeTransfer struct {
+	Base
+	EmployeeID     uint   `gorm:"not null;index;comment:员工ID" json:"employee_id"`
+	FromManagerIDs string `gorm:"type:text;comment:调动前店长ID列表(JSON)" json:"-"`
+	ToManagerIDs   string `gorm:"type:text;comment:调动后店长ID列表(JSON)" json:"-"`
+	OperatorID     uint   `gorm:"comment:操作人ID" json:"operator_id"`
+	Reason         string `gorm:"size:255;comment:调动原因" json:"reason"`
+}
@@ -12,7 +12,7 @@ type ExamPaper struct {
 	Base
 	Title            string         `gorm:"size:200;not null;comment:试卷标题" json:"title"`
 	Description      string         `gorm:"type:text;comment:试卷描述" json:"description"`
-	Status           string         `gorm:"size:20;default:'draft';comment:状态(draft草稿/published已发布)" json:"status"`
+	Status           string         `gorm:"size:20;default:'draft';comment:状态(draft草稿/in_review审核中/approved已通过/published已发布/archived已归档)" json:"status"`
 	TargetRole       Role           `gorm:"size:16;default:'employee';comment:目标角色(employee员工/manager店长)" json:"target_role"`
 	TimeLimitMinutes int            `gorm:"default:0;comment:时间限制(分钟)" json:"time_limit_minutes"`
 	PassScore        int            `gorm:"default:0;comment:及格分数" json:"pass_score"`
@@ -20,6 +20,11 @@ type ExamPaper struct {
 	CreatorID        uint           `gorm:"comment:创建者ID" json:"creator_id"`
 	Questions        []ExamQuestion `json:"questions" gorm:"foreignKey:ExamID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 
+	ScheduledPublishAt *time.Time `gorm:"index;comment:定时发布时间" json:"scheduled_publish_at"`
+	ScheduledOfflineAt *time.Time `gorm:"index;comment:定时归档时间" json:"scheduled_offline_at"`
+
+	ReviewState
+
 	QuestionCount int `gorm:"-" json:"question_count"`
 }
 
@@ -59,11 +64,14 @@ func (ExamAttempt) TableName() string {
 	return "exam_attempts"
 }
 
-// ExamAttempt records a user's submission for an exam.
+// ExamAttempt records a user's submission for an exam. A user normally submits an exam once;
+// exams backing a certification may be retaken in its renewal window, each retake starting the
+// next cycle. A user has at most one attempt per exam and cycle.
 type ExamAttempt struct {
 	Base
-	ExamID          uint       `json:"exam_id" gorm:"uniqueIndex:idx_user_exam;comment:试卷ID"`
-	UserID          uint       `json:"user_id" gorm:"uniqueIndex:idx_user_exam;comment:用户ID"`
+	ExamID          uint       `json:"exam_id" gorm:"index:idx_exam_attempt_user_exam;uniqueIndex:idx_exam_attempt_cycle,priority:2;comment:试卷ID"`
+	UserID          uint       `json:"user_id" gorm:"index:idx_exam_attempt_user_exam;uniqueIndex:idx_exam_attempt_cycle,priority:1;comment:用户ID"`
+	Cycle           int        `json:"cycle" gorm:"uniqueIndex:idx_exam_attempt_cycle,priority:3;not null;default:1;comment:考试轮次(认证重考时递增)"`
 	Status          string     `gorm:"size:16;default:'submitted';comment:状态(submitted已提交/grading评分中/completed已完成)" json:"status"`
 	Score           int        `gorm:"comment:得分" json:"score"`
 	CorrectCount    int        `gorm:"comment:正确题数" json:"correct_count"`
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// TableName 指定表名
+func (GrowthLike) TableName() string {
+	return "growth_likes"
+}
+
+// GrowthLike 用户对成长圈动态的点赞，每个用户每条动态一条，取消点赞时物理删除。
+type GrowthLike struct {
+	Base
+	PostID uint `gorm:"uniqueIndex:idx_growth_post_user_like;comment:动态ID" json:"post_id"`
+	UserID uint `gorm:"uniqueIndex:idx_growth_post_user_like;index;comment:点赞用户ID" json:"user_id"`
+	User   User `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+}
+
+// TableName 指定表名
+func (GrowthComment) TableName() string {
+	return "growth_comments"
+}
+
+// GrowthComment 成长圈动态下的评论，ParentID 指向被回复的评论，与动态一样需审核通过后才公开展示。
+type GrowthComment struct {
+	Base
+	PostID     uint       `gorm:"index;comment:动态ID" json:"post_id"`
+	ParentID   *uint      `gorm:"index;comment:回复的评论ID，为空表示顶层评论" json:"parent_id,omitempty"`
+	UserID     uint       `gorm:"index;comment:评论用户ID" json:"user_id"`
+	User       User       `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Body       string     `gorm:"type:text;not null;comment:评论内容" json:"body"`
+	Status     string     `gorm:"size:16;default:'pending';comment:状态(pending待审核/approved已通过/rejected已拒绝)" json:"status"`
+	ApprovedAt *time.Time `gorm:"comment:审核通过时间" json:"approved_at,omitempty"`
+}
+
+// TableName 指定表名
+func (GrowthShare) TableName() string {
+	return "growth_shares"
+}
+
+// GrowthShare 记录用户对成长圈动态的分享，每人每条动态只记一次，Channel 为首次分享的渠道。
+type GrowthShare struct {
+	Base
+	PostID  uint   `gorm:"uniqueIndex:idx_growth_post_user_share;comment:动态ID" json:"post_id"`
+	UserID  uint   `gorm:"uniqueIndex:idx_growth_post_user_share;index;comment:分享用户ID" json:"user_id"`
+	Channel string `gorm:"size:32;comment:首次分享的渠道(wechat_friend微信好友/wechat_moments朋友圈/link复制链接)" json:"channel"`
+}
@@ -0,0 +1,32 @@
+package model
+
+// Growth moderation event actions.
+const (
+	GrowthActionSubmit      = "submit"
+	GrowthActionResubmit    = "resubmit"
+	GrowthActionAutoApprove = "auto_approve"
+	GrowthActionAutoReject  = "auto_reject"
+	GrowthActionAutoReview  = "auto_review"
+	GrowthActionApprove     = "approve"
+	GrowthActionReject      = "reject"
+)
+
+// TableName 指定表名
+func (GrowthModerationEvent) TableName() string {
+	return "growth_moderation_events"
+}
+
+// GrowthModerationEvent 成长圈动态的审核历史：提交、重新提交、自动审核结论以及人工通过或拒绝。
+// 提交类记录保存当时的动态内容快照，便于对比修改前后的差异。
+type GrowthModerationEvent struct {
+	Base
+	PostID     uint   `gorm:"index;comment:动态ID" json:"post_id"`
+	Action     string `gorm:"size:16;index:idx_growth_moderation_action;comment:动作(submit提交/resubmit重新提交/auto_approve自动通过/auto_reject自动拒绝/auto_review转人工/approve通过/reject拒绝)" json:"action"`
+	FromStatus string `gorm:"size:16;comment:变更前状态" json:"from_status"`
+	ToStatus   string `gorm:"size:16;comment:变更后状态" json:"to_status"`
+	ActorID    uint   `gorm:"default:0;comment:操作人ID(0表示自动审核)" json:"actor_id"`
+	ReasonCode string `gorm:"size:32;index;comment:拒绝原因代码" json:"reason_code"`
+	Reason     string `gorm:"size:500;comment:原因或自动审核说明" json:"reason"`
+	Content    string `gorm:"type:text;comment:提交时的动态内容快照" json:"content"`
+	ImagePaths string `gorm:"type:text;comment:提交时的图片路径快照(JSON)" json:"-"`
+}
@@ -16,4 +16,24 @@ type GrowthPost struct {
 	ImagePaths string `gorm:"type:text;comment:图片路径数组(JSON)" json:"-"`
 	Status     string `gorm:"size:16;default:'pending';comment:状态(pending待审核/approved已通过/rejected已拒绝)" json:"status"`
 	ApprovedAt *time.Time `gorm:"comment:审核通过时间" json:"approved_at,omitempty"`
+
+	LikeCount    int64 `gorm:"default:0;comment:点赞数" json:"like_count"`
+	CommentCount int64 `gorm:"default:0;comment:已公开评论数" json:"comment_count"`
+	ShareCount   int64 `gorm:"default:0;comment:分享人数，同一用户多次分享只计一次" json:"share_count"`
+
+	ModerationVerdict string `gorm:"size:16;index;comment:自动审核结论(pass/review/block)，为空表示未经自动审核" json:"moderation_verdict"`
+	ModerationMatches string `gorm:"type:text;comment:命中的敏感词(JSON)" json:"-"`
+	ModerationLabels  string `gorm:"size:255;comment:外部审核服务返回的标签，逗号分隔" json:"moderation_labels"`
+	ModerationNote    string `gorm:"size:255;comment:自动审核说明" json:"moderation_note"`
+
+	RejectReasonCode string     `gorm:"size:32;comment:最近一次拒绝原因代码" json:"reject_reason_code"`
+	RejectReason     string     `gorm:"size:500;comment:最近一次拒绝原因说明" json:"reject_reason"`
+	RejectedAt       *time.Time `gorm:"comment:最近一次拒绝时间" json:"rejected_at,omitempty"`
+	ResubmitCount    int        `gorm:"default:0;comment:重新提交次数" json:"resubmit_count"`
+
+	Scope        string     `gorm:"size:16;default:'company';index;comment:可见范围(company全公司/team本店团队/roles指定角色)" json:"scope"`
+	VisibleRoles string     `gorm:"size:64;comment:scope为roles时可见的角色，前后带逗号便于匹配，如,employee,manager," json:"-"`
+	Topics       string     `gorm:"size:255;comment:话题名称，逗号分隔" json:"-"`
+	PinnedAt     *time.Time `gorm:"index;comment:置顶时间，为空表示未置顶" json:"pinned_at,omitempty"`
+	FeaturedAt   *time.Time `gorm:"index;comment:设为精选的时间，为空表示非精选" json:"featured_at,omitempty"`
 }
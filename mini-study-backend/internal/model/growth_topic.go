@@ -0,0 +1,32 @@
+package model
+
+// Growth post visibility scopes.
+const (
+	GrowthScopeCompany = "company"
+	GrowthScopeTeam    = "team"
+	GrowthScopeRoles   = "roles"
+)
+
+// TableName 指定表名
+func (GrowthTopic) TableName() string {
+	return "growth_topics"
+}
+
+// GrowthTopic 成长圈话题，由动态正文中的 #话题 自动创建。
+type GrowthTopic struct {
+	Base
+	Name string `gorm:"size:32;not null;uniqueIndex;comment:话题名称(英文字母统一小写)" json:"name"`
+}
+
+// TableName 指定表名
+func (GrowthPostTopic) TableName() string {
+	return "growth_post_topics"
+}
+
+// GrowthPostTopic 动态与话题的关联，动态重新提交时整体替换，删除时物理删除。
+type GrowthPostTopic struct {
+	Base
+	PostID  uint        `gorm:"uniqueIndex:idx_growth_post_topic;comment:动态ID" json:"post_id"`
+	TopicID uint        `gorm:"uniqueIndex:idx_growth_post_topic;index;comment:话题ID" json:"topic_id"`
+	Topic   GrowthTopic `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+}
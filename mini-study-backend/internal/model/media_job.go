@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Media job kinds: videos are probed and packaged as HLS, documents are rendered to page images.
+const (
+	MediaJobVideo    = "video"
+	MediaJobDocument = "document"
+)
+
+// Media job statuses.
+const (
+	MediaJobPending   = "pending"
+	MediaJobRunning   = "running"
+	MediaJobSucceeded = "succeeded"
+	MediaJobFailed    = "failed"
+	MediaJobCanceled  = "canceled"
+)
+
+// TableName 指定表名
+func (MediaJob) TableName() string {
+	return "media_jobs"
+}
+
+// MediaJob 媒体处理任务：视频探测时长与分辨率、生成封面缩略图并打包 HLS 多码率流；文档转换为逐页图片与文本。
+type MediaJob struct {
+	Base
+	ContentID       uint       `gorm:"index;comment:内容ID" json:"content_id"`
+	Kind            string     `gorm:"size:16;default:video;comment:任务类型(video视频/document文档)" json:"kind"`
+	SourceKey       string     `gorm:"size:512;comment:源文件存储键" json:"source_key"`
+	Status          string     `gorm:"size:16;index:idx_media_job_claim,priority:1;comment:状态(pending待处理/running处理中/succeeded成功/failed失败/canceled已取消)" json:"status"`
+	Attempts        int        `gorm:"default:0;comment:已尝试次数" json:"attempts"`
+	NextRunAt       time.Time  `gorm:"index:idx_media_job_claim,priority:2;comment:下次可执行时间" json:"next_run_at"`
+	LeaseUntil      *time.Time `gorm:"comment:执行租约到期时间" json:"lease_until"`
+	Error           string     `gorm:"type:text;comment:最近一次失败原因" json:"error"`
+	DurationSeconds int64      `gorm:"default:0;comment:探测到的时长(秒)" json:"duration_seconds"`
+	Width           int        `gorm:"default:0;comment:视频宽度" json:"width"`
+	Height          int        `gorm:"default:0;comment:视频高度" json:"height"`
+	ThumbnailURL    string     `gorm:"size:512;comment:缩略图URL" json:"thumbnail_url"`
+	HLSKey          string     `gorm:"size:512;comment:HLS主播放列表存储键" json:"hls_key"`
+	PageCount       int        `gorm:"default:0;comment:文档转换页数" json:"page_count"`
+	StartedAt       *time.Time `gorm:"comment:最近一次开始时间" json:"started_at"`
+	FinishedAt      *time.Time `gorm:"comment:完成时间" json:"finished_at"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// TableName 指定表名
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// Notification 站内通知，例如成长圈动态被点赞、评论或分享时通知作者，或提醒认证即将到期。
+type Notification struct {
+	Base
+	UserID     uint       `gorm:"index:idx_notification_user_read;comment:接收用户ID" json:"user_id"`
+	ActorID    uint       `gorm:"comment:触发通知的用户ID" json:"actor_id"`
+	Actor      User       `json:"-" gorm:"foreignKey:ActorID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Type       string     `gorm:"size:32;comment:通知类型(growth_like/growth_comment/growth_reply/growth_share/growth_approved/growth_rejected/growth_featured/certification_expiring/certification_expired)" json:"type"`
+	EntityType string     `gorm:"size:32;comment:关联实体类型" json:"entity_type"`
+	EntityID   uint       `gorm:"comment:关联实体ID" json:"entity_id"`
+	Summary    string     `gorm:"size:255;comment:通知摘要" json:"summary"`
+	ReadAt     *time.Time `gorm:"index:idx_notification_user_read;comment:已读时间" json:"read_at,omitempty"`
+}
@@ -0,0 +1,96 @@
+package model
+
+import "time"
+
+// TableName 指定表名
+func (UserDailyStat) TableName() string {
+	return "report_user_daily_stats"
+}
+
+// UserDailyStat 按天汇总的用户学习与考试数据，由后台汇总任务按整天重算。
+type UserDailyStat struct {
+	Base
+	StatDate        time.Time `gorm:"type:date;uniqueIndex:idx_report_user_day,priority:1;comment:统计日期" json:"stat_date"`
+	UserID          uint      `gorm:"uniqueIndex:idx_report_user_day,priority:2;index;comment:用户ID" json:"user_id"`
+	LearningUpdates int64     `gorm:"default:0;comment:当天有进度更新的内容数" json:"learning_updates"`
+	Completions     int64     `gorm:"default:0;comment:当天完成的内容数" json:"completions"`
+	ExamAttempts    int64     `gorm:"default:0;comment:当天提交的考试数" json:"exam_attempts"`
+	ExamPassed      int64     `gorm:"default:0;comment:当天通过的考试数" json:"exam_passed"`
+	ExamScoreSum    int64     `gorm:"default:0;comment:当天考试得分合计" json:"exam_score_sum"`
+	PointsEarned    int64     `gorm:"default:0;comment:当天获得的积分" json:"points_earned"`
+}
+
+// TableName 指定表名
+func (ContentDailyStat) TableName() string {
+	return "report_content_daily_stats"
+}
+
+// ContentDailyStat 按天汇总的学习内容数据。
+type ContentDailyStat struct {
+	Base
+	StatDate    time.Time `gorm:"type:date;uniqueIndex:idx_report_content_day,priority:1;comment:统计日期" json:"stat_date"`
+	ContentID   uint      `gorm:"uniqueIndex:idx_report_content_day,priority:2;index;comment:内容ID" json:"content_id"`
+	Learners    int64     `gorm:"default:0;comment:当天有进度更新的人数" json:"learners"`
+	Completions int64     `gorm:"default:0;comment:当天完成的人数" json:"completions"`
+}
+
+// TableName 指定表名
+func (ExamDailyStat) TableName() string {
+	return "report_exam_daily_stats"
+}
+
+// ExamDailyStat 按天汇总的试卷数据。
+type ExamDailyStat struct {
+	Base
+	StatDate time.Time `gorm:"type:date;uniqueIndex:idx_report_exam_day,priority:1;comment:统计日期" json:"stat_date"`
+	ExamID   uint      `gorm:"uniqueIndex:idx_report_exam_day,priority:2;index;comment:试卷ID" json:"exam_id"`
+	Attempts int64     `gorm:"default:0;comment:当天提交次数" json:"attempts"`
+	Passed   int64     `gorm:"default:0;comment:当天通过次数" json:"passed"`
+	ScoreSum int64     `gorm:"default:0;comment:当天得分合计" json:"score_sum"`
+}
+
+// TableName 指定表名
+func (UserReportSummary) TableName() string {
+	return "report_user_summaries"
+}
+
+// UserReportSummary 用户当前的学习完成数与最近一次考试，供学习看板直接读取。
+type UserReportSummary struct {
+	Base
+	UserID            uint       `gorm:"uniqueIndex;comment:用户ID" json:"user_id"`
+	Completed         int64      `gorm:"default:0;comment:已完成内容数" json:"completed"`
+	Learning          int64      `gorm:"default:0;comment:已开始学习的内容数" json:"learning"`
+	LatestExamID      uint       `gorm:"default:0;comment:最近一次考试的试卷ID(0表示未参加)" json:"latest_exam_id"`
+	LatestExamTitle   string     `gorm:"size:255;comment:最近一次考试的试卷标题" json:"latest_exam_title"`
+	LatestScore       int        `gorm:"default:0;comment:最近一次考试得分" json:"latest_score"`
+	LatestPass        bool       `gorm:"default:false;comment:最近一次考试是否通过" json:"latest_pass"`
+	LatestSubmittedAt *time.Time `gorm:"comment:最近一次考试提交时间" json:"latest_submitted_at"`
+}
+
+// TableName 指定表名
+func (UserExamSummary) TableName() string {
+	return "report_user_exam_summaries"
+}
+
+// UserExamSummary 用户在每份试卷上的成绩汇总，便于按任意人群统计试卷通过率。
+type UserExamSummary struct {
+	Base
+	UserID    uint   `gorm:"uniqueIndex:idx_report_user_exam,priority:1;comment:用户ID" json:"user_id"`
+	ExamID    uint   `gorm:"uniqueIndex:idx_report_user_exam,priority:2;index;comment:试卷ID" json:"exam_id"`
+	ExamTitle string `gorm:"size:255;comment:试卷标题" json:"exam_title"`
+	Attempts  int64  `gorm:"default:0;comment:提交次数" json:"attempts"`
+	Passed    int64  `gorm:"default:0;comment:通过次数" json:"passed"`
+	ScoreSum  int64  `gorm:"default:0;comment:得分合计" json:"score_sum"`
+}
+
+// TableName 指定表名
+func (ReportRollupState) TableName() string {
+	return "report_rollup_states"
+}
+
+// ReportRollupState 汇总任务的水位线，记录各任务已汇总到的时间点。
+type ReportRollupState struct {
+	Base
+	Name           string    `gorm:"size:64;uniqueIndex;comment:任务名称" json:"name"`
+	RefreshedUntil time.Time `gorm:"comment:已汇总到的时间" json:"refreshed_until"`
+}
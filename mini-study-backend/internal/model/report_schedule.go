@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// TableName 指定表名
+func (ReportSchedule) TableName() string {
+	return "report_schedules"
+}
+
+// ReportSchedule 报表定时发送计划：按频率生成报表文件并以邮件附件发送给收件人。
+// 店长只能发送本店团队的报表；管理员可指定店长团队，未指定时为全部用户。
+type ReportSchedule struct {
+	Base
+	OwnerID        uint       `gorm:"index;comment:创建人ID" json:"owner_id"`
+	ManagerID      uint       `gorm:"default:0;comment:报表范围(店长ID，0表示全部用户)" json:"manager_id"`
+	Report         string     `gorm:"size:32;comment:报表类型(team_learning团队学习汇总/exam_results考试成绩/points_ranking积分排行)" json:"report"`
+	Format         string     `gorm:"size:8;comment:文件格式(xlsx/csv)" json:"format"`
+	Recipient      string     `gorm:"size:255;comment:收件邮箱" json:"recipient"`
+	Frequency      string     `gorm:"size:16;comment:发送频率(daily每天/weekly每周/monthly每月)" json:"frequency"`
+	Weekday        int        `gorm:"default:1;comment:每周发送日(1-7，周一为1)" json:"weekday"`
+	MonthDay       int        `gorm:"default:1;comment:每月发送日(1-28)" json:"month_day"`
+	Hour           int        `gorm:"default:8;comment:发送时刻(0-23时)" json:"hour"`
+	Enabled        bool       `gorm:"default:true;comment:是否启用" json:"enabled"`
+	NextRunAt      *time.Time `gorm:"index;comment:下次发送时间" json:"next_run_at"`
+	LastRunAt      *time.Time `gorm:"comment:最近发送时间" json:"last_run_at"`
+	LastStatus     string     `gorm:"size:16;comment:最近发送结果(sent成功/failed失败)" json:"last_status"`
+	LastError      string     `gorm:"size:500;comment:最近发送失败原因" json:"last_error"`
+	FailedAttempts int        `gorm:"default:0;comment:连续定时发送失败次数，失败后按退避间隔重试" json:"failed_attempts"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// Editorial statuses shared by contents and exams. Retired entities are "offline" for contents
+// and "archived" for exams.
+const (
+	EditorialDraft     = "draft"
+	EditorialInReview  = "in_review"
+	EditorialApproved  = "approved"
+	EditorialPublished = "published"
+	EditorialOffline   = "offline"
+	EditorialArchived  = "archived"
+)
+
+// Review event actions.
+const (
+	ReviewActionSubmit   = "submit"
+	ReviewActionWithdraw = "withdraw"
+	ReviewActionAssign   = "assign"
+	ReviewActionApprove  = "approve"
+	ReviewActionReject   = "reject"
+	ReviewActionComment  = "comment"
+	ReviewActionPublish  = "publish"
+	ReviewActionOffline  = "offline"
+	ReviewActionRevert   = "revert"
+)
+
+// ReviewState 审核状态，嵌入内容与试卷：提交人、指定审核人与最近一次审核结果。
+type ReviewState struct {
+	ReviewerID   uint       `gorm:"default:0;index;comment:指定审核人ID(0表示任意管理员)" json:"reviewer_id"`
+	SubmittedBy  uint       `gorm:"default:0;comment:提交审核人ID" json:"submitted_by"`
+	SubmittedAt  *time.Time `gorm:"comment:提交审核时间" json:"submitted_at"`
+	ReviewedBy   uint       `gorm:"default:0;comment:审核人ID" json:"reviewed_by"`
+	ReviewedAt   *time.Time `gorm:"comment:审核时间" json:"reviewed_at"`
+	RejectReason string     `gorm:"size:500;comment:驳回原因" json:"reject_reason"`
+}
+
+// TableName 指定表名
+func (ReviewEvent) TableName() string {
+	return "review_events"
+}
+
+// ReviewEvent 审核流转记录：状态变更（提交、审批、驳回、发布、下线等）与审核评论。
+type ReviewEvent struct {
+	Base
+	EntityType string `gorm:"size:16;index:idx_review_entity;comment:实体类型(content内容/exam考试)" json:"entity_type"`
+	EntityID   uint   `gorm:"index:idx_review_entity;comment:实体ID" json:"entity_id"`
+	Action     string `gorm:"size:16;comment:动作(submit提交/withdraw撤回/assign指派/approve通过/reject驳回/comment评论/publish发布/offline下线/revert退回草稿)" json:"action"`
+	FromStatus string `gorm:"size:16;comment:变更前状态" json:"from_status"`
+	ToStatus   string `gorm:"size:16;comment:变更后状态" json:"to_status"`
+	ActorID    uint   `gorm:"default:0;comment:操作人ID(0表示系统)" json:"actor_id"`
+	ReviewerID uint   `gorm:"default:0;comment:指定审核人ID" json:"reviewer_id"`
+	Comment    string `gorm:"type:text;comment:评论或原因" json:"comment"`
+}
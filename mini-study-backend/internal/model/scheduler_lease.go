@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// TableName 指定表名
+func (SchedulerLease) TableName() string {
+	return "scheduler_leases"
+}
+
+// SchedulerLease 后台定时任务租约，多实例部署时同一时刻只有持有者执行该任务。
+type SchedulerLease struct {
+	Base
+	Name       string    `gorm:"size:64;uniqueIndex;comment:任务名称" json:"name"`
+	Holder     string    `gorm:"size:128;comment:当前持有者(实例标识)" json:"holder"`
+	LeaseUntil time.Time `gorm:"comment:租约到期时间" json:"lease_until"`
+}
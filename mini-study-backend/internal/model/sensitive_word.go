@@ -0,0 +1,14 @@
+package model
+
+// TableName 指定表名
+func (SensitiveWord) TableName() string {
+	return "sensitive_words"
+}
+
+// SensitiveWord 成长圈自动审核使用的敏感词，命中 block 级别的动态直接拒绝，命中 review 级别的转人工复核。
+type SensitiveWord struct {
+	Base
+	Word     string `gorm:"size:64;not null;uniqueIndex;comment:敏感词" json:"word"`
+	Category string `gorm:"size:32;index;comment:分类(ad广告/fraud诈骗/gambling赌博/abuse辱骂/privacy隐私等)" json:"category"`
+	Level    string `gorm:"size:16;not null;default:'review';comment:处置级别(block直接拒绝/review转人工)" json:"level"`
+}
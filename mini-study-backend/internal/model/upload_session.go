@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Upload session statuses.
+const (
+	UploadSessionUploading = "uploading"
+	UploadSessionCompleted = "completed"
+	UploadSessionAborted   = "aborted"
+	UploadSessionExpired   = "expired"
+)
+
+// TableName 指定表名
+func (UploadSession) TableName() string {
+	return "upload_sessions"
+}
+
+// UploadSession 分片上传会话，记录已接收的字节偏移以支持断点续传。
+// 写分片、完成、取消与过期清理前先认领会话（ClaimToken/ClaimedUntil），多实例下同一时刻只有一个请求处理该会话。
+type UploadSession struct {
+	Base
+	UploadID      string     `gorm:"size:36;uniqueIndex;comment:上传会话ID(UUID)" json:"upload_id"`
+	UserID        uint       `gorm:"index;comment:上传者ID" json:"user_id"`
+	Purpose       string     `gorm:"size:32;comment:上传用途" json:"purpose"`
+	Filename      string     `gorm:"size:255;comment:原始文件名" json:"filename"`
+	TotalSize     int64      `gorm:"comment:文件总大小(字节)" json:"total_size"`
+	ReceivedBytes int64      `gorm:"default:0;comment:已接收字节数(下一个分片的偏移)" json:"received_bytes"`
+	SHA256        string     `gorm:"size:64;comment:客户端声明的文件SHA-256(可选)" json:"sha256"`
+	Status        string     `gorm:"size:16;index:idx_upload_session_status,priority:1;comment:状态(uploading上传中/completed已完成/aborted已取消/expired已过期)" json:"status"`
+	ExpiresAt     time.Time  `gorm:"index:idx_upload_session_status,priority:2;comment:过期时间" json:"expires_at"`
+	Key           string     `gorm:"size:255;comment:完成后的存储键" json:"key"`
+	CompletedAt   *time.Time `gorm:"comment:完成时间" json:"completed_at"`
+	ClaimToken    string     `gorm:"size:36;comment:当前处理请求的认领标识" json:"-"`
+	ClaimedUntil  *time.Time `gorm:"comment:认领到期时间" json:"-"`
+}
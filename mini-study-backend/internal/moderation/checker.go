@@ -0,0 +1,105 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Verdicts, from mildest to strictest.
+const (
+	VerdictPass   = "pass"   // publish without review
+	VerdictReview = "review" // queue for a human
+	VerdictBlock  = "block"  // reject
+)
+
+// Stricter returns the stricter of two verdicts.
+func Stricter(a, b string) string {
+	if rank(b) > rank(a) {
+		return b
+	}
+	return a
+}
+
+func rank(verdict string) int {
+	switch verdict {
+	case VerdictBlock:
+		return 2
+	case VerdictReview:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Input is the content submitted for checking. Image URLs must be reachable by the checker.
+type Input struct {
+	Text      string   `json:"text"`
+	ImageURLs []string `json:"image_urls,omitempty"`
+}
+
+// Result is the decision of a checker. Labels name what was found, e.g. "porn" or "ad".
+type Result struct {
+	Verdict string   `json:"verdict"`
+	Labels  []string `json:"labels,omitempty"`
+	Reason  string   `json:"reason,omitempty"`
+}
+
+// Checker is implemented by external text and image moderation services.
+type Checker interface {
+	Check(ctx context.Context, input Input) (*Result, error)
+}
+
+// HTTPChecker posts the input as JSON to an endpoint answering with a Result. It adapts any
+// vendor through a small gateway, keeping vendor SDKs out of the application.
+type HTTPChecker struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+// NewHTTPChecker creates a checker for endpoint. A non-empty token is sent as a bearer token.
+func NewHTTPChecker(endpoint, token string, timeout time.Duration) *HTTPChecker {
+	return &HTTPChecker{endpoint: endpoint, token: token, client: &http.Client{Timeout: timeout}}
+}
+
+// Check implements Checker.
+func (c *HTTPChecker) Check(ctx context.Context, input Input) (*Result, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("moderation request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("moderation request: status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var result Result
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode moderation result: %w", err)
+	}
+	switch result.Verdict {
+	case VerdictPass, VerdictReview, VerdictBlock:
+		return &result, nil
+	default:
+		return nil, fmt.Errorf("unknown moderation verdict %q", result.Verdict)
+	}
+}
@@ -0,0 +1,35 @@
+package moderation
+
+// Word categories of the built-in dictionary.
+const (
+	CategoryAd       = "ad"       // 广告引流
+	CategoryFraud    = "fraud"    // 诈骗、违法交易
+	CategoryGambling = "gambling" // 赌博
+	CategoryAbuse    = "abuse"    // 辱骂
+	CategoryPrivacy  = "privacy"  // 泄露客户或内部信息
+)
+
+// DefaultWords is the built-in dictionary seeded into an empty word table. It is a starting
+// point; administrators maintain the actual list.
+var DefaultWords = []Word{
+	{Text: "加微信", Category: CategoryAd, Level: LevelReview},
+	{Text: "加v", Category: CategoryAd, Level: LevelReview},
+	{Text: "私聊领取", Category: CategoryAd, Level: LevelReview},
+	{Text: "扫码进群", Category: CategoryAd, Level: LevelReview},
+	{Text: "代购", Category: CategoryAd, Level: LevelReview},
+	{Text: "兼职刷单", Category: CategoryFraud, Level: LevelBlock},
+	{Text: "刷单返利", Category: CategoryFraud, Level: LevelBlock},
+	{Text: "代开发票", Category: CategoryFraud, Level: LevelBlock},
+	{Text: "办证", Category: CategoryFraud, Level: LevelReview},
+	{Text: "套现", Category: CategoryFraud, Level: LevelReview},
+	{Text: "网赌", Category: CategoryGambling, Level: LevelBlock},
+	{Text: "博彩", Category: CategoryGambling, Level: LevelBlock},
+	{Text: "六合彩", Category: CategoryGambling, Level: LevelBlock},
+	{Text: "赌博", Category: CategoryGambling, Level: LevelReview},
+	{Text: "傻逼", Category: CategoryAbuse, Level: LevelBlock},
+	{Text: "去死", Category: CategoryAbuse, Level: LevelReview},
+	{Text: "垃圾公司", Category: CategoryAbuse, Level: LevelReview},
+	{Text: "身份证号", Category: CategoryPrivacy, Level: LevelReview},
+	{Text: "客户电话", Category: CategoryPrivacy, Level: LevelReview},
+	{Text: "内部价格", Category: CategoryPrivacy, Level: LevelReview},
+}
@@ -0,0 +1,32 @@
+package moderation
+
+import (
+	"html"
+	"strings"
+)
+
+// Highlight escapes text for HTML and wraps the spans of matches in <mark> tags. Overlapping
+// matches are merged into one mark.
+func Highlight(text string, matches []Match) string {
+	var b strings.Builder
+	pos := 0
+	for i := 0; i < len(matches); i++ {
+		start, end := matches[i].Start, matches[i].End
+		if end <= pos {
+			continue
+		}
+		start = max(start, pos)
+		// matches are ordered by start, so the following ones can only extend this span
+		for i+1 < len(matches) && matches[i+1].Start < end {
+			i++
+			end = max(end, matches[i].End)
+		}
+		b.WriteString(html.EscapeString(text[pos:start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[start:end]))
+		b.WriteString("</mark>")
+		pos = end
+	}
+	b.WriteString(html.EscapeString(text[pos:]))
+	return b.String()
+}
@@ -0,0 +1,185 @@
+package moderation
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Levels of dictionary words.
+const (
+	LevelBlock  = "block"  // the text is rejected outright
+	LevelReview = "review" // the text is queued for a human
+)
+
+// Word is one dictionary entry.
+type Word struct {
+	Text     string
+	Category string
+	Level    string
+}
+
+// Match is an occurrence of a dictionary word. Start and End are the byte span in the original
+// text; the span may contain noise characters inserted to dodge the dictionary, e.g. "加 微-信".
+type Match struct {
+	Word     string
+	Category string
+	Level    string
+	Start    int
+	End      int
+}
+
+// Matcher finds dictionary words in text with an Aho-Corasick automaton, so a text is scanned
+// once whatever the size of the dictionary. Matching ignores case, full-width forms and
+// characters other than letters and digits. A Matcher is immutable and safe for concurrent use.
+type Matcher struct {
+	nodes   []node
+	words   []Word
+	lengths []int // normalized rune count of each word
+}
+
+type node struct {
+	next   map[rune]int
+	fail   int
+	output []int // indexes into words ending at this node, including via fail links
+}
+
+// NewMatcher builds a matcher for words. Words that normalize to the empty string are ignored;
+// when the same word appears twice the later entry wins.
+func NewMatcher(words []Word) *Matcher {
+	m := &Matcher{nodes: []node{{next: map[rune]int{}}}}
+	index := make(map[string]int)
+	for _, w := range words {
+		key := Normalize(w.Text)
+		if key == "" {
+			continue
+		}
+		if i, ok := index[key]; ok {
+			m.words[i] = w
+			continue
+		}
+		index[key] = len(m.words)
+		m.words = append(m.words, w)
+		m.lengths = append(m.lengths, utf8.RuneCountInString(key))
+		m.insert(key, len(m.words)-1)
+	}
+	m.link()
+	return m
+}
+
+// Len returns the number of distinct words.
+func (m *Matcher) Len() int {
+	return len(m.words)
+}
+
+func (m *Matcher) insert(key string, word int) {
+	cur := 0
+	for _, r := range key {
+		nxt, ok := m.nodes[cur].next[r]
+		if !ok {
+			nxt = len(m.nodes)
+			m.nodes = append(m.nodes, node{next: map[rune]int{}})
+			m.nodes[cur].next[r] = nxt
+		}
+		cur = nxt
+	}
+	m.nodes[cur].output = append(m.nodes[cur].output, word)
+}
+
+// link computes the failure links breadth first and merges the outputs along them.
+func (m *Matcher) link() {
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[cur].next {
+			fail := m.nodes[cur].fail
+			for fail > 0 {
+				if _, ok := m.nodes[fail].next[r]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if target, ok := m.nodes[fail].next[r]; ok && target != child {
+				m.nodes[child].fail = target
+			}
+			m.nodes[child].output = append(m.nodes[child].output, m.nodes[m.nodes[child].fail].output...)
+			queue = append(queue, child)
+		}
+	}
+}
+
+// Find returns the occurrences of dictionary words in text, ordered by position. Overlapping
+// occurrences are all reported.
+func (m *Matcher) Find(text string) []Match {
+	if m == nil || len(m.words) == 0 {
+		return nil
+	}
+	// starts holds the byte offset of every significant rune read so far; a word of n runes
+	// ending at the current rune started n runes back.
+	var starts []int
+	var matches []Match
+	cur := 0
+	for offset, orig := range text {
+		r, ok := fold(orig)
+		if !ok {
+			continue
+		}
+		starts = append(starts, offset)
+		for cur > 0 {
+			if _, ok := m.nodes[cur].next[r]; ok {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+		if nxt, ok := m.nodes[cur].next[r]; ok {
+			cur = nxt
+		}
+		end := offset + utf8.RuneLen(orig)
+		for _, i := range m.nodes[cur].output {
+			w := m.words[i]
+			matches = append(matches, Match{
+				Word:     w.Text,
+				Category: w.Category,
+				Level:    w.Level,
+				Start:    starts[len(starts)-m.lengths[i]],
+				End:      end,
+			})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End > matches[j].End
+	})
+	return matches
+}
+
+// Normalize returns the form of text the matcher compares: lowercase, half-width, with
+// everything but letters and digits removed.
+func Normalize(text string) string {
+	var b strings.Builder
+	for _, orig := range text {
+		if r, ok := fold(orig); ok {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// fold maps a rune to its comparison form and reports whether it is significant.
+func fold(r rune) (rune, bool) {
+	// full-width ASCII, common in Chinese input, folds to its half-width form
+	if r >= 0xFF01 && r <= 0xFF5E {
+		r -= 0xFEE0
+	}
+	if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+		return 0, false
+	}
+	return unicode.ToLower(r), true
+}
@@ -0,0 +1,335 @@
+// Package pdf writes simple vector PDF documents: text, lines and rectangles on fixed-size pages.
+//
+// Text is set in STSong-Light, one of the standard Adobe Chinese fonts that PDF readers supply
+// themselves, so documents stay small and need no font files. Characters outside the Basic
+// Multilingual Plane are replaced with "?".
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Page sizes in points (1/72 inch).
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Color is an RGB color with components between 0 and 1.
+type Color struct {
+	R, G, B float64
+}
+
+// Black is the default text and line color.
+var Black = Color{}
+
+// ParseHexColor parses "#RRGGBB" (the "#" is optional).
+func ParseHexColor(s string) (Color, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return Color{}, fmt.Errorf("invalid color %q", s)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q", s)
+	}
+	return Color{
+		R: float64(v>>16&0xff) / 255,
+		G: float64(v>>8&0xff) / 255,
+		B: float64(v&0xff) / 255,
+	}, nil
+}
+
+// Document is a PDF under construction. All pages share the document's size.
+type Document struct {
+	Width, Height float64
+	Title         string
+	CreatedAt     time.Time
+
+	pages []*Page
+}
+
+// New creates a document with pages of the given size in points.
+func New(width, height float64) *Document {
+	return &Document{Width: width, Height: height, CreatedAt: time.Now()}
+}
+
+// AddPage appends an empty page and returns it.
+func (d *Document) AddPage() *Page {
+	page := &Page{doc: d}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Page collects the drawing operators of one page. Coordinates are in points with the origin
+// at the bottom-left corner, as in PDF itself.
+type Page struct {
+	doc *Document
+	buf bytes.Buffer
+}
+
+// Width returns the page width.
+func (p *Page) Width() float64 { return p.doc.Width }
+
+// Height returns the page height.
+func (p *Page) Height() float64 { return p.doc.Height }
+
+// Rect fills and/or strokes a rectangle whose bottom-left corner is (x, y).
+func (p *Page) Rect(x, y, w, h float64, fill *Color, stroke *Color, lineWidth float64) {
+	if fill == nil && stroke == nil {
+		return
+	}
+	p.buf.WriteString("q ")
+	if fill != nil {
+		fmt.Fprintf(&p.buf, "%s %s %s rg ", num(fill.R), num(fill.G), num(fill.B))
+	}
+	if stroke != nil {
+		fmt.Fprintf(&p.buf, "%s %s %s RG %s w ", num(stroke.R), num(stroke.G), num(stroke.B), num(lineWidth))
+	}
+	fmt.Fprintf(&p.buf, "%s %s %s %s re ", num(x), num(y), num(w), num(h))
+	switch {
+	case fill != nil && stroke != nil:
+		p.buf.WriteString("B")
+	case fill != nil:
+		p.buf.WriteString("f")
+	default:
+		p.buf.WriteString("S")
+	}
+	p.buf.WriteString(" Q\n")
+}
+
+// Line strokes a straight line.
+func (p *Page) Line(x1, y1, x2, y2 float64, color Color, lineWidth float64) {
+	fmt.Fprintf(&p.buf, "q %s %s %s RG %s w %s %s m %s %s l S Q\n",
+		num(color.R), num(color.G), num(color.B), num(lineWidth), num(x1), num(y1), num(x2), num(y2))
+}
+
+// Text draws s with its baseline starting at (x, y). Bold text is emulated by stroking the glyph
+// outlines, as the standard Chinese fonts have no bold face.
+func (p *Page) Text(x, y, size float64, color Color, bold bool, s string) {
+	if s == "" {
+		return
+	}
+	c := fmt.Sprintf("%s %s %s", num(color.R), num(color.G), num(color.B))
+	p.buf.WriteString("q BT ")
+	if bold {
+		fmt.Fprintf(&p.buf, "%s rg %s RG 2 Tr %s w ", c, c, num(size/30))
+	} else {
+		fmt.Fprintf(&p.buf, "%s rg ", c)
+	}
+	fmt.Fprintf(&p.buf, "/F1 %s Tf %s %s Td <%s> Tj ET Q\n", num(size), num(x), num(y), EncodeText(s))
+}
+
+// TextCentered draws s centered horizontally around cx.
+func (p *Page) TextCentered(cx, y, size float64, color Color, bold bool, s string) {
+	p.Text(cx-TextWidth(s, size)/2, y, size, color, bold, s)
+}
+
+// TextRight draws s so that it ends at x.
+func (p *Page) TextRight(x, y, size float64, color Color, bold bool, s string) {
+	p.Text(x-TextWidth(s, size), y, size, color, bold, s)
+}
+
+// TextWidth returns the width of s in points at the given font size: printable ASCII uses the
+// font's proportional Latin widths and everything else is full-width.
+func TextWidth(s string, size float64) float64 {
+	units := 0
+	for _, r := range s {
+		units += runeWidth(r)
+	}
+	return float64(units) * size / 1000
+}
+
+// Wrap breaks s into lines no wider than maxWidth. Lines break between any two Chinese
+// characters, and between words for Latin text; explicit newlines are kept.
+func Wrap(s string, size, maxWidth float64) []string {
+	limit := int(maxWidth * 1000 / size)
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		runes := []rune(paragraph)
+		if len(runes) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		for len(runes) > 0 {
+			width, end, lastSpace := 0, 0, -1
+			for end < len(runes) {
+				w := runeWidth(runes[end])
+				if width+w > limit && end > 0 {
+					break
+				}
+				if runes[end] == ' ' {
+					lastSpace = end
+				}
+				width += w
+				end++
+			}
+			if end < len(runes) && isWordRune(runes[end]) && end > 0 && isWordRune(runes[end-1]) && lastSpace > 0 {
+				end = lastSpace + 1
+			}
+			lines = append(lines, strings.TrimRight(string(runes[:end]), " "))
+			runes = runes[end:]
+			for len(runes) > 0 && runes[0] == ' ' {
+				runes = runes[1:]
+			}
+		}
+	}
+	return lines
+}
+
+// EncodeText returns s as the hex-encoded UCS-2 string expected by the font's UniGB-UCS2-H encoding.
+func EncodeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r > 0xffff || utf16.IsSurrogate(r) || r < 0x20 {
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
+
+// Write renders the document.
+func (d *Document) Write(w io.Writer) error {
+	out := &pdfWriter{w: w}
+	out.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	// Object numbers: 1 catalog, 2 page tree, 3-5 font, 6 info, then a page and its content per page.
+	const firstPage = 7
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+i*2)
+	}
+
+	out.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	out.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(d.pages), num(d.Width), num(d.Height)))
+	out.object(3, "<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [4 0 R] >>")
+	out.object(4, "<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> "+
+		"/FontDescriptor 5 0 R /DW 1000 /W [1 ["+latinWidthList()+"]] >>")
+	out.object(5, "<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] "+
+		"/ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>")
+	out.object(6, fmt.Sprintf("<< /Title <FEFF%s> /Producer (mini-study) /CreationDate (%s) >>",
+		utf16Hex(d.Title), pdfDate(d.CreatedAt)))
+
+	for i, page := range d.pages {
+		id := firstPage + i*2
+		out.object(id, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", id+1))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		_, _ = zw.Write(page.buf.Bytes())
+		_ = zw.Close()
+		out.stream(id+1, "/Filter /FlateDecode", compressed.Bytes())
+	}
+
+	xref := out.n
+	count := len(out.offsets) + 1
+	out.printf("xref\n0 %d\n0000000000 65535 f \n", count)
+	for _, offset := range out.offsets {
+		out.printf("%010d 00000 n \n", offset)
+	}
+	out.printf("trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", count, xref)
+	return out.err
+}
+
+// pdfWriter tracks byte offsets of objects for the cross-reference table.
+type pdfWriter struct {
+	w       io.Writer
+	n       int64
+	offsets []int64
+	err     error
+}
+
+func (p *pdfWriter) printf(format string, args ...interface{}) {
+	p.write([]byte(fmt.Sprintf(format, args...)))
+}
+
+func (p *pdfWriter) write(b []byte) {
+	if p.err != nil {
+		return
+	}
+	n, err := p.w.Write(b)
+	p.n += int64(n)
+	p.err = err
+}
+
+// object writes object id, which must be the next number in sequence.
+func (p *pdfWriter) object(id int, body string) {
+	p.offsets = append(p.offsets, p.n)
+	p.printf("%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+func (p *pdfWriter) stream(id int, dict string, data []byte) {
+	p.offsets = append(p.offsets, p.n)
+	p.printf("%d 0 obj\n<< %s /Length %d >>\nstream\n", id, dict, len(data))
+	p.write(data)
+	p.printf("\nendstream\nendobj\n")
+}
+
+// latinWidths are the advance widths of printable ASCII (U+0020-U+007E) in STSong-Light, which
+// UniGB-UCS2-H maps to the proportional Latin glyphs CID 1-95.
+var latinWidths = [95]int{
+	207, 270, 342, 467, 462, 797, 710, 239, 374, 374, 423, 605, 238, 375, 238, 334,
+	462, 462, 462, 462, 462, 462, 462, 462, 462, 462, 238, 238, 605, 605, 605, 344,
+	748, 684, 560, 695, 739, 563, 511, 729, 793, 318, 312, 666, 526, 896, 758, 772,
+	544, 772, 628, 465, 607, 753, 711, 972, 647, 620, 607, 374, 333, 374, 606, 500,
+	239, 417, 503, 427, 529, 415, 264, 444, 518, 241, 230, 495, 228, 793, 527, 524,
+	524, 504, 338, 336, 277, 517, 450, 652, 466, 452, 407, 370, 258, 370, 605,
+}
+
+func latinWidthList() string {
+	widths := make([]string, len(latinWidths))
+	for i, w := range latinWidths {
+		widths[i] = strconv.Itoa(w)
+	}
+	return strings.Join(widths, " ")
+}
+
+func runeWidth(r rune) int {
+	if r >= 0x20 && r < 0x7f {
+		return latinWidths[r-0x20]
+	}
+	return 1000
+}
+
+func isWordRune(r rune) bool {
+	return r > ' ' && r < 0x7f
+}
+
+// num formats a coordinate with at most two decimals.
+func num(v float64) string {
+	return strconv.FormatFloat(float64(int64(v*100+copysign(0.5, v)))/100, 'f', -1, 64)
+}
+
+func copysign(v, sign float64) float64 {
+	if sign < 0 {
+		return -v
+	}
+	return v
+}
+
+func utf16Hex(s string) string {
+	var b strings.Builder
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	return b.String()
+}
+
+func pdfDate(t time.Time) string {
+	_, offset := t.Zone()
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("D:%s%c%02d'%02d'", t.Format("20060102150405"), sign, offset/3600, offset/60%60)
+}
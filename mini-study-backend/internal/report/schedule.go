@@ -0,0 +1,52 @@
+package report
+
+import "time"
+
+// Delivery frequencies.
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+)
+
+// Schedule describes when a report is delivered, in the location of the times passed to
+// NextRun. Weekday runs from 1 (Monday) to 7 (Sunday) and only applies to weekly schedules;
+// MonthDay runs from 1 to 28 so every month has it and only applies to monthly schedules.
+type Schedule struct {
+	Frequency string
+	Weekday   int
+	MonthDay  int
+	Hour      int
+}
+
+// NextRun returns the first delivery time strictly after after.
+func NextRun(s Schedule, after time.Time) time.Time {
+	loc := after.Location()
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, loc)
+	at := func(d time.Time) time.Time {
+		return time.Date(d.Year(), d.Month(), d.Day(), s.Hour, 0, 0, 0, loc)
+	}
+
+	switch s.Frequency {
+	case FrequencyWeekly:
+		// time.Weekday 以周日为 0，这里换算为周一为 1、周日为 7
+		current := (int(day.Weekday())+6)%7 + 1
+		next := at(day.AddDate(0, 0, (s.Weekday-current+7)%7))
+		if !next.After(after) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next
+	case FrequencyMonthly:
+		next := at(time.Date(day.Year(), day.Month(), s.MonthDay, 0, 0, 0, 0, loc))
+		if !next.After(after) {
+			next = at(time.Date(day.Year(), day.Month()+1, s.MonthDay, 0, 0, 0, 0, loc))
+		}
+		return next
+	default:
+		next := at(day)
+		if !next.After(after) {
+			next = at(day.AddDate(0, 0, 1))
+		}
+		return next
+	}
+}
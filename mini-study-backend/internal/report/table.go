@@ -0,0 +1,115 @@
+// Package report renders tabular reports as CSV or XLSX files and computes report delivery
+// times. It has no dependency on the rest of the application so exports stay easy to test.
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export formats.
+const (
+	FormatXLSX = "xlsx"
+	FormatCSV  = "csv"
+)
+
+// Table is a single sheet of a report. Cells may be strings, integers, floats, bools, times or
+// nil; numbers stay numeric in XLSX so they can be summed and sorted in Excel.
+type Table struct {
+	Title   string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// AddRow appends a row of cells.
+func (t *Table) AddRow(cells ...interface{}) {
+	t.Rows = append(t.Rows, cells)
+}
+
+// Write renders the table in the given format.
+func Write(w io.Writer, format string, table *Table) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, table)
+	case FormatXLSX:
+		return WriteXLSX(w, table)
+	default:
+		return fmt.Errorf("unsupported report format %s", format)
+	}
+}
+
+// ContentType returns the MIME type of a format.
+func ContentType(format string) string {
+	if format == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+// WriteCSV renders the table as CSV, UTF-8 with a BOM so Excel detects the encoding.
+func WriteCSV(w io.Writer, table *Table) error {
+	if _, err := io.WriteString(w, "\xEF\xBB\xBF"); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(table.Columns); err != nil {
+		return err
+	}
+	record := make([]string, 0, len(table.Columns))
+	for _, row := range table.Rows {
+		record = record[:0]
+		for _, cell := range row {
+			record = append(record, FormatCell(cell))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// FormatCell renders a cell as text. Text that a spreadsheet would read as a formula is
+// prefixed with an apostrophe so names and titles typed by users cannot run formulas.
+func FormatCell(cell interface{}) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeFormula(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "是"
+		}
+		return "否"
+	case time.Time:
+		return v.Format("2006-01-02 15:04")
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format("2006-01-02 15:04")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// escapeFormula prefixes text starting with a formula trigger with an apostrophe.
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
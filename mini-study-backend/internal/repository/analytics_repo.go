@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// AnalyticsRepository reads the learning activity behind the admin analytics dashboard.
+// Totals are grouped per user and period in the database; periods are given as boundaries so
+// the bucketing needs no database-specific date functions.
+type AnalyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepository creates an AnalyticsRepository.
+func NewAnalyticsRepository(db *gorm.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// LearningEvent is a user action at a point in time with the point change as Value.
+type LearningEvent struct {
+	UserID uint
+	At     time.Time
+	Value  int64
+}
+
+// PeriodTotal is what one user did in one period: the number of Events and a summed Amount
+// whose meaning depends on the method.
+type PeriodTotal struct {
+	UserID uint  `gorm:"column:user_id"`
+	Period int   `gorm:"column:period_no"`
+	Events int64 `gorm:"column:events"`
+	Amount int64 `gorm:"column:amount"`
+}
+
+// Activity returns, per user and period, the days spent on each content as Events and the
+// seconds the video position advanced as Amount.
+func (r *AnalyticsRepository) Activity(bounds []time.Time) ([]PeriodTotal, error) {
+	return r.periodTotals(r.db.Model(&model.LearningActivity{}), "activity_date", "SUM(watch_seconds)", bounds, "sum learning activity")
+}
+
+// Completions returns, per user and period, the contents completed.
+func (r *AnalyticsRepository) Completions(bounds []time.Time) ([]PeriodTotal, error) {
+	return r.periodTotals(r.db.Model(&model.LearningRecord{}), "completed_at", "0", bounds, "sum learning completions")
+}
+
+// ExamResults returns, per user and period, the exam attempts submitted with the passed ones
+// as Amount.
+func (r *AnalyticsRepository) ExamResults(bounds []time.Time) ([]PeriodTotal, error) {
+	return r.periodTotals(r.db.Model(&model.ExamAttempt{}), "submitted_at", "SUM(CASE WHEN pass THEN 1 ELSE 0 END)", bounds, "sum exam results")
+}
+
+// PointTotals returns, per user and period, the positive point transactions with the points
+// as Amount.
+func (r *AnalyticsRepository) PointTotals(bounds []time.Time) ([]PeriodTotal, error) {
+	return r.periodTotals(r.db.Model(&model.PointTransaction{}).Where("`change` > 0"), "created_at", "SUM(`change`)", bounds, "sum points earned")
+}
+
+// periodTotals counts the rows of query whose column falls in [bounds[0], bounds[len-1]),
+// grouped by user and by the period i of [bounds[i], bounds[i+1]) containing it.
+func (r *AnalyticsRepository) periodTotals(query *gorm.DB, column, amount string, bounds []time.Time, action string) ([]PeriodTotal, error) {
+	if len(bounds) < 2 {
+		return nil, nil
+	}
+	last := len(bounds) - 1
+	var period strings.Builder
+	args := make([]interface{}, 0, last)
+	period.WriteString("CASE")
+	for i := 1; i < last; i++ {
+		fmt.Fprintf(&period, " WHEN %s < ? THEN %d", column, i-1)
+		args = append(args, bounds[i])
+	}
+	fmt.Fprintf(&period, " ELSE %d END", last-1)
+
+	var totals []PeriodTotal
+	if err := query.
+		Select(fmt.Sprintf("user_id, %s AS period_no, COUNT(*) AS events, %s AS amount", period.String(), amount), args...).
+		Where(column+" >= ? AND "+column+" < ?", bounds[0], bounds[last]).
+		Group("user_id, period_no").
+		Scan(&totals).Error; err != nil {
+		return nil, errors.Wrap(err, action)
+	}
+	return totals, nil
+}
+
+// PointsEarned returns the positive point transactions in the range with the points as Value.
+func (r *AnalyticsRepository) PointsEarned(from, to time.Time) ([]LearningEvent, error) {
+	var events []LearningEvent
+	if err := r.db.Model(&model.PointTransaction{}).
+		Select("user_id, created_at AS at, `change` AS value").
+		Where("`change` > 0").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Scan(&events).Error; err != nil {
+		return nil, errors.Wrap(err, "list points earned")
+	}
+	return events, nil
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 
@@ -17,6 +19,24 @@ func NewAuditRepository(db *gorm.DB) *AuditRepository {
 	return &AuditRepository{db: db}
 }
 
+// WithTx 返回在事务 tx 中执行的仓库。
+func (r *AuditRepository) WithTx(tx *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: tx}
+}
+
+// AuditLogFilter 审计日志查询条件，零值字段表示不过滤。
+type AuditLogFilter struct {
+	ActorID    uint
+	Action     string
+	Target     string
+	Result     string
+	RequestID  string
+	EntityType string
+	EntityID   uint
+	StartAt    *time.Time
+	EndAt      *time.Time
+}
+
 // Create 写入一条审计日志。
 func (r *AuditRepository) Create(entry *model.AuditLog) error {
 	if err := r.db.Create(entry).Error; err != nil {
@@ -24,3 +44,145 @@ func (r *AuditRepository) Create(entry *model.AuditLog) error {
 	}
 	return nil
 }
+
+// Count 统计符合条件的审计日志数量。
+func (r *AuditRepository) Count(filter AuditLogFilter) (int64, error) {
+	var total int64
+	if err := r.applyFilter(r.db.Model(&model.AuditLog{}), filter).Count(&total).Error; err != nil {
+		return 0, errors.Wrap(err, "count audit logs")
+	}
+	return total, nil
+}
+
+// Search 分页查询审计日志，按时间倒序。
+func (r *AuditRepository) Search(filter AuditLogFilter, page, pageSize int) ([]model.AuditLog, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	total, err := r.Count(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []model.AuditLog{}, 0, nil
+	}
+
+	var items []model.AuditLog
+	if err := r.applyFilter(r.db.Model(&model.AuditLog{}), filter).
+		Order("id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&items).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "search audit logs")
+	}
+	return items, total, nil
+}
+
+// Iterate 按 ID 倒序分批遍历符合条件的审计日志，用于导出，顺序与 Search 一致。
+func (r *AuditRepository) Iterate(filter AuditLogFilter, batchSize int, fn func([]model.AuditLog) error) error {
+	var lastID uint
+	for {
+		query := r.applyFilter(r.db.Model(&model.AuditLog{}), filter)
+		if lastID > 0 {
+			query = query.Where("id < ?", lastID)
+		}
+		var batch []model.AuditLog
+		if err := query.Order("id DESC").Limit(batchSize).Find(&batch).Error; err != nil {
+			return errors.Wrap(err, "iterate audit logs")
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+}
+
+// ArchiveBefore 将创建时间早于 cutoff 的日志移动到归档表，单批最多 batchSize 条，返回本批移动数量。
+func (r *AuditRepository) ArchiveBefore(cutoff time.Time, batchSize int) (int64, error) {
+	var moved int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var logs []model.AuditLog
+		if err := tx.Unscoped().
+			Where("created_at < ?", cutoff).
+			Order("id ASC").
+			Limit(batchSize).
+			Find(&logs).Error; err != nil {
+			return errors.Wrap(err, "load expired audit logs")
+		}
+		if len(logs) == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		archives := make([]model.AuditLogArchive, 0, len(logs))
+		ids := make([]uint, 0, len(logs))
+		for _, entry := range logs {
+			archives = append(archives, model.AuditLogArchive{
+				ID:         entry.ID,
+				ActorID:    entry.ActorID,
+				Action:     entry.Action,
+				Target:     entry.Target,
+				Payload:    entry.Payload,
+				Result:     entry.Result,
+				RequestID:  entry.RequestID,
+				EntityType: entry.EntityType,
+				EntityID:   entry.EntityID,
+				Changes:    entry.Changes,
+				CreatedAt:  entry.CreatedAt,
+				ArchivedAt: now,
+			})
+			ids = append(ids, entry.ID)
+		}
+
+		if err := tx.Create(&archives).Error; err != nil {
+			return errors.Wrap(err, "create audit log archives")
+		}
+		if err := tx.Unscoped().Where("id IN ?", ids).Delete(&model.AuditLog{}).Error; err != nil {
+			return errors.Wrap(err, "purge archived audit logs")
+		}
+		moved = int64(len(logs))
+		return nil
+	})
+	return moved, err
+}
+
+func (r *AuditRepository) applyFilter(query *gorm.DB, filter AuditLogFilter) *gorm.DB {
+	if filter.ActorID > 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Target != "" {
+		query = query.Where("target = ?", filter.Target)
+	}
+	if filter.Result != "" {
+		query = query.Where("result = ?", filter.Result)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID > 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.StartAt != nil {
+		query = query.Where("created_at >= ?", *filter.StartAt)
+	}
+	if filter.EndAt != nil {
+		query = query.Where("created_at < ?", *filter.EndAt)
+	}
+	return query
+}
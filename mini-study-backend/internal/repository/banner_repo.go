@@ -44,34 +44,40 @@ func (r *BannerRepository) FindByID(id uint) (*model.Banner, error) {
 	return &banner, nil
 }
 
-// ListAdmin returns all banners optionally filtered by status.
-func (r *BannerRepository) ListAdmin(status *bool) ([]model.Banner, error) {
-	query := r.db.Order("sort_order asc, id desc")
+// BannerSortFields lists the fields banner lists can be sorted by.
+var BannerSortFields = SortFields{
+	"id":         "id",
+	"sort_order": "sort_order",
+	"start_at":   "start_at",
+	"created_at": "created_at",
+}
+
+// SearchAdmin returns a page of banners optionally filtered by status.
+func (r *BannerRepository) SearchAdmin(status *bool, page PageRequest) ([]model.Banner, int64, error) {
+	query := r.db.Model(&model.Banner{})
 	if status != nil {
 		query = query.Where("status = ?", *status)
 	}
-	var banners []model.Banner
-	if err := query.Find(&banners).Error; err != nil {
-		return nil, errors.Wrap(err, "list banners")
+	banners, total, err := findPage[model.Banner](query, page)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search banners")
 	}
-	return banners, nil
+	return banners, total, nil
 }
 
-// ListVisible returns banners visible to role now.
-func (r *BannerRepository) ListVisible(role string, now time.Time) ([]model.Banner, error) {
-	query := r.db.
+// SearchVisible returns a page of banners visible to role now.
+func (r *BannerRepository) SearchVisible(role string, now time.Time, page PageRequest) ([]model.Banner, int64, error) {
+	query := r.db.Model(&model.Banner{}).
 		Where("status = ?", true).
 		Where("start_at IS NULL OR start_at <= ?", now).
-		Where("end_at IS NULL OR end_at >= ?", now).
-		Order("sort_order asc, id desc")
+		Where("end_at IS NULL OR end_at >= ?", now)
 	if role != "" && role != "both" {
 		query = query.Where("visible_roles = ? OR visible_roles = ?", role, "both")
 	}
-
-	var banners []model.Banner
-	if err := query.Find(&banners).Error; err != nil {
-		return nil, errors.Wrap(err, "list visible banners")
+	banners, total, err := findPage[model.Banner](query, page)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search visible banners")
 	}
-	return banners, nil
+	return banners, total, nil
 }
 
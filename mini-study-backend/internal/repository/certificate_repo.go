@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// CertificateRepository persists certificate templates and issued certificates.
+type CertificateRepository struct {
+	db *gorm.DB
+}
+
+// NewCertificateRepository creates a CertificateRepository.
+func NewCertificateRepository(db *gorm.DB) *CertificateRepository {
+	return &CertificateRepository{db: db}
+}
+
+// CreateTemplate inserts a template.
+func (r *CertificateRepository) CreateTemplate(template *model.CertificateTemplate) error {
+	if err := r.db.Create(template).Error; err != nil {
+		return errors.Wrap(err, "create certificate template")
+	}
+	return nil
+}
+
+// UpdateTemplate saves every field of a template.
+func (r *CertificateRepository) UpdateTemplate(template *model.CertificateTemplate) error {
+	if err := r.db.Save(template).Error; err != nil {
+		return errors.Wrap(err, "update certificate template")
+	}
+	return nil
+}
+
+// DeleteTemplate soft-deletes a template. Issued certificates keep their PDF.
+func (r *CertificateRepository) DeleteTemplate(id uint) error {
+	if err := r.db.Delete(&model.CertificateTemplate{}, id).Error; err != nil {
+		return errors.Wrap(err, "delete certificate template")
+	}
+	return nil
+}
+
+// FindTemplate loads a template.
+func (r *CertificateRepository) FindTemplate(id uint) (*model.CertificateTemplate, error) {
+	var template model.CertificateTemplate
+	if err := r.db.First(&template, id).Error; err != nil {
+		return nil, errors.Wrap(err, "find certificate template")
+	}
+	return &template, nil
+}
+
+// ListTemplates returns every template, default templates first.
+func (r *CertificateRepository) ListTemplates() ([]model.CertificateTemplate, error) {
+	var templates []model.CertificateTemplate
+	if err := r.db.Order("exam_id asc, id asc").Find(&templates).Error; err != nil {
+		return nil, errors.Wrap(err, "list certificate templates")
+	}
+	return templates, nil
+}
+
+// FindTemplateForExam returns the enabled template bound to examID, falling back to the enabled
+// default template. It returns gorm.ErrRecordNotFound when neither exists.
+func (r *CertificateRepository) FindTemplateForExam(examID uint) (*model.CertificateTemplate, error) {
+	var template model.CertificateTemplate
+	if err := r.db.Where("enabled = ? AND exam_id IN ?", true, []uint{examID, 0}).
+		Order("exam_id desc, id desc").First(&template).Error; err != nil {
+		return nil, errors.Wrap(err, "find certificate template for exam")
+	}
+	return &template, nil
+}
+
+// EnabledTemplateExists reports whether another enabled template is bound to examID
+// (0 for the default template).
+func (r *CertificateRepository) EnabledTemplateExists(examID, excludeID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&model.CertificateTemplate{}).
+		Where("enabled = ? AND exam_id = ? AND id <> ?", true, examID, excludeID).
+		Count(&count).Error; err != nil {
+		return false, errors.Wrap(err, "count certificate templates")
+	}
+	return count > 0, nil
+}
+
+// Create inserts an issued certificate.
+func (r *CertificateRepository) Create(certificate *model.Certificate) error {
+	if err := r.db.Create(certificate).Error; err != nil {
+		return errors.Wrap(err, "create certificate")
+	}
+	return nil
+}
+
+// FindByAttempt returns the certificate issued for an exam attempt.
+func (r *CertificateRepository) FindByAttempt(attemptID uint) (*model.Certificate, error) {
+	var certificate model.Certificate
+	if err := r.db.Where("attempt_id = ?", attemptID).First(&certificate).Error; err != nil {
+		return nil, errors.Wrap(err, "find certificate by attempt")
+	}
+	return &certificate, nil
+}
+
+// FindBySerial returns the certificate with the given serial number.
+func (r *CertificateRepository) FindBySerial(serial string) (*model.Certificate, error) {
+	var certificate model.Certificate
+	if err := r.db.Where("serial = ?", serial).First(&certificate).Error; err != nil {
+		return nil, errors.Wrap(err, "find certificate by serial")
+	}
+	return &certificate, nil
+}
+
+// ListByUser returns a user's certificates, newest first.
+func (r *CertificateRepository) ListByUser(userID uint) ([]model.Certificate, error) {
+	var certificates []model.Certificate
+	if err := r.db.Where("user_id = ?", userID).Order("issued_at desc, id desc").Find(&certificates).Error; err != nil {
+		return nil, errors.Wrap(err, "list certificates by user")
+	}
+	return certificates, nil
+}
+
+// SerialsByAttempts maps attempt IDs to the serial of their certificate.
+func (r *CertificateRepository) SerialsByAttempts(attemptIDs []uint) (map[uint]string, error) {
+	result := make(map[uint]string, len(attemptIDs))
+	if len(attemptIDs) == 0 {
+		return result, nil
+	}
+	var certificates []model.Certificate
+	if err := r.db.Select("attempt_id", "serial").Where("attempt_id IN ?", attemptIDs).Find(&certificates).Error; err != nil {
+		return nil, errors.Wrap(err, "list certificate serials")
+	}
+	for _, certificate := range certificates {
+		result[certificate.AttemptID] = certificate.Serial
+	}
+	return result, nil
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// CertificationRepository persists certifications and the certifications users hold.
+type CertificationRepository struct {
+	db *gorm.DB
+}
+
+// NewCertificationRepository creates a CertificationRepository.
+func NewCertificationRepository(db *gorm.DB) *CertificationRepository {
+	return &CertificationRepository{db: db}
+}
+
+// Create inserts a certification.
+func (r *CertificationRepository) Create(certification *model.Certification) error {
+	if err := r.db.Create(certification).Error; err != nil {
+		return errors.Wrap(err, "create certification")
+	}
+	return nil
+}
+
+// Update saves every field of a certification.
+func (r *CertificationRepository) Update(certification *model.Certification) error {
+	if err := r.db.Save(certification).Error; err != nil {
+		return errors.Wrap(err, "update certification")
+	}
+	return nil
+}
+
+// Delete soft-deletes a certification and removes what users hold of it.
+func (r *CertificationRepository) Delete(id uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("certification_id = ?", id).Delete(&model.UserCertification{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Certification{}, id).Error
+	})
+	if err != nil {
+		return errors.Wrap(err, "delete certification")
+	}
+	return nil
+}
+
+// FindByID loads a certification.
+func (r *CertificationRepository) FindByID(id uint) (*model.Certification, error) {
+	var certification model.Certification
+	if err := r.db.First(&certification, id).Error; err != nil {
+		return nil, errors.Wrap(err, "find certification")
+	}
+	return &certification, nil
+}
+
+// List returns every certification, newest first.
+func (r *CertificationRepository) List() ([]model.Certification, error) {
+	var certifications []model.Certification
+	if err := r.db.Order("id desc").Find(&certifications).Error; err != nil {
+		return nil, errors.Wrap(err, "list certifications")
+	}
+	return certifications, nil
+}
+
+// ListEnabled returns the enabled certifications.
+func (r *CertificationRepository) ListEnabled() ([]model.Certification, error) {
+	var certifications []model.Certification
+	if err := r.db.Where("enabled = ?", true).Order("id asc").Find(&certifications).Error; err != nil {
+		return nil, errors.Wrap(err, "list enabled certifications")
+	}
+	return certifications, nil
+}
+
+// FindEnabledByExam returns the enabled certification linked to an exam.
+func (r *CertificationRepository) FindEnabledByExam(examID uint) (*model.Certification, error) {
+	var certification model.Certification
+	if err := r.db.Where("enabled = ? AND exam_id = ?", true, examID).First(&certification).Error; err != nil {
+		return nil, errors.Wrap(err, "find certification by exam")
+	}
+	return &certification, nil
+}
+
+// EnabledExists reports whether another enabled certification is linked to examID.
+func (r *CertificationRepository) EnabledExists(examID, excludeID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&model.Certification{}).
+		Where("enabled = ? AND exam_id = ? AND id <> ?", true, examID, excludeID).
+		Count(&count).Error; err != nil {
+		return false, errors.Wrap(err, "count certifications")
+	}
+	return count > 0, nil
+}
+
+// FindUserCertification returns what a user holds of a certification.
+func (r *CertificationRepository) FindUserCertification(certificationID, userID uint) (*model.UserCertification, error) {
+	var held model.UserCertification
+	if err := r.db.Where("certification_id = ? AND user_id = ?", certificationID, userID).First(&held).Error; err != nil {
+		return nil, errors.Wrap(err, "find user certification")
+	}
+	return &held, nil
+}
+
+// SaveUserCertification records a pass, replacing the previous one and its reminder times.
+func (r *CertificationRepository) SaveUserCertification(held *model.UserCertification) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "certification_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"attempt_id", "passed_at", "expires_at", "renewal_notified_at", "expired_notified_at", "updated_at", "deleted_at"}),
+	}).Create(held).Error
+	if err != nil {
+		return errors.Wrap(err, "save user certification")
+	}
+	return nil
+}
+
+// DeleteUserCertifications removes everything users hold of a certification.
+func (r *CertificationRepository) DeleteUserCertifications(certificationID uint) error {
+	if err := r.db.Unscoped().Where("certification_id = ?", certificationID).Delete(&model.UserCertification{}).Error; err != nil {
+		return errors.Wrap(err, "delete user certifications")
+	}
+	return nil
+}
+
+// ListByCertification returns every holder of a certification.
+func (r *CertificationRepository) ListByCertification(certificationID uint) ([]model.UserCertification, error) {
+	var held []model.UserCertification
+	if err := r.db.Where("certification_id = ?", certificationID).Find(&held).Error; err != nil {
+		return nil, errors.Wrap(err, "list user certifications")
+	}
+	return held, nil
+}
+
+// ListByUser returns the certifications a user holds.
+func (r *CertificationRepository) ListByUser(userID uint) ([]model.UserCertification, error) {
+	var held []model.UserCertification
+	if err := r.db.Where("user_id = ?", userID).Find(&held).Error; err != nil {
+		return nil, errors.Wrap(err, "list user certifications by user")
+	}
+	return held, nil
+}
+
+// ListExpiringBefore returns held certifications expiring before the given time, including
+// those already expired, soonest first. Nil certificationIDs or userIDs means no filter.
+func (r *CertificationRepository) ListExpiringBefore(certificationIDs, userIDs []uint, before time.Time) ([]model.UserCertification, error) {
+	query := r.db.Where("expires_at < ?", before)
+	if certificationIDs != nil {
+		query = query.Where("certification_id IN ?", certificationIDs)
+	}
+	if userIDs != nil {
+		query = query.Where("user_id IN ?", userIDs)
+	}
+	var held []model.UserCertification
+	if err := query.Order("expires_at asc, id asc").Find(&held).Error; err != nil {
+		return nil, errors.Wrap(err, "list expiring user certifications")
+	}
+	return held, nil
+}
+
+// ListRenewalDue returns holders of a certification that expires in (now, until] and have not
+// been reminded yet.
+func (r *CertificationRepository) ListRenewalDue(certificationID uint, now, until time.Time, limit int) ([]model.UserCertification, error) {
+	var held []model.UserCertification
+	if err := r.db.
+		Where("certification_id = ? AND expires_at > ? AND expires_at <= ? AND renewal_notified_at IS NULL", certificationID, now, until).
+		Order("expires_at asc").Limit(limit).
+		Find(&held).Error; err != nil {
+		return nil, errors.Wrap(err, "list renewal reminders")
+	}
+	return held, nil
+}
+
+// ListExpiredDue returns holders of a certification that expired by now and have not been told yet.
+func (r *CertificationRepository) ListExpiredDue(certificationID uint, now time.Time, limit int) ([]model.UserCertification, error) {
+	var held []model.UserCertification
+	if err := r.db.
+		Where("certification_id = ? AND expires_at <= ? AND expired_notified_at IS NULL", certificationID, now).
+		Order("expires_at asc").Limit(limit).
+		Find(&held).Error; err != nil {
+		return nil, errors.Wrap(err, "list expired reminders")
+	}
+	return held, nil
+}
+
+// MarkNotified sets a reminder time column if it is still empty. It reports false when the
+// reminder was already recorded, so each reminder is sent once.
+func (r *CertificationRepository) MarkNotified(id uint, column string, at time.Time) (bool, error) {
+	res := r.db.Model(&model.UserCertification{}).
+		Where("id = ?", id).
+		Where(clause.Expr{SQL: "? IS NULL", Vars: []interface{}{clause.Column{Name: column}}}).
+		UpdateColumn(column, at)
+	if res.Error != nil {
+		return false, errors.Wrap(res.Error, "mark certification reminder")
+	}
+	return res.RowsAffected > 0, nil
+}
@@ -30,6 +30,27 @@ func (r *ContentCategoryRepository) ListByRole(role string) ([]model.ContentCate
 	return categories, nil
 }
 
+// ContentCategorySortFields 分类列表可排序字段。
+var ContentCategorySortFields = SortFields{
+	"id":         "id",
+	"sort_order": "sort_order",
+	"name":       "name",
+	"created_at": "created_at",
+}
+
+// SearchByRole 根据角色分页查询可用分类，role 为空时查询全部。
+func (r *ContentCategoryRepository) SearchByRole(role string, page PageRequest) ([]model.ContentCategory, int64, error) {
+	query := r.db.Model(&model.ContentCategory{}).Where("status = ?", true)
+	if role != "" && role != "both" {
+		query = query.Where("role_scope = ? OR role_scope = ?", role, "both")
+	}
+	categories, total, err := findPage[model.ContentCategory](query, page)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search categories")
+	}
+	return categories, total, nil
+}
+
 // FindByID 通过 ID 获取分类。
 func (r *ContentCategoryRepository) FindByID(id uint) (*model.ContentCategory, error) {
 	var category model.ContentCategory
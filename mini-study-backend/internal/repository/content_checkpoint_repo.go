@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// ContentCheckpointRepository 随堂测验检查点仓储。
+type ContentCheckpointRepository struct {
+	db *gorm.DB
+}
+
+// NewContentCheckpointRepository 创建检查点仓库实例。
+func NewContentCheckpointRepository(db *gorm.DB) *ContentCheckpointRepository {
+	return &ContentCheckpointRepository{db: db}
+}
+
+// Create 新增检查点。
+func (r *ContentCheckpointRepository) Create(checkpoint *model.ContentCheckpoint) error {
+	if err := r.db.Create(checkpoint).Error; err != nil {
+		return errors.Wrap(err, "create content checkpoint")
+	}
+	return nil
+}
+
+// Update 保存检查点。
+func (r *ContentCheckpointRepository) Update(checkpoint *model.ContentCheckpoint) error {
+	if err := r.db.Save(checkpoint).Error; err != nil {
+		return errors.Wrap(err, "update content checkpoint")
+	}
+	return nil
+}
+
+// Delete 删除检查点，作答记录保留。
+func (r *ContentCheckpointRepository) Delete(id uint) error {
+	if err := r.db.Delete(&model.ContentCheckpoint{}, id).Error; err != nil {
+		return errors.Wrap(err, "delete content checkpoint")
+	}
+	return nil
+}
+
+// FindByID 查询检查点。
+func (r *ContentCheckpointRepository) FindByID(id uint) (*model.ContentCheckpoint, error) {
+	var checkpoint model.ContentCheckpoint
+	if err := r.db.First(&checkpoint, id).Error; err != nil {
+		return nil, errors.Wrap(err, "find content checkpoint")
+	}
+	return &checkpoint, nil
+}
+
+// ListByContent 按位置顺序列出内容的检查点。
+func (r *ContentCheckpointRepository) ListByContent(contentID uint) ([]model.ContentCheckpoint, error) {
+	var checkpoints []model.ContentCheckpoint
+	if err := r.db.Where("content_id = ?", contentID).Order("position asc, id asc").Find(&checkpoints).Error; err != nil {
+		return nil, errors.Wrap(err, "list content checkpoints")
+	}
+	return checkpoints, nil
+}
+
+// ContentCheckpointSortFields 检查点列表可排序字段。
+var ContentCheckpointSortFields = SortFields{
+	"id":         "id",
+	"position":   "position",
+	"created_at": "created_at",
+}
+
+// SearchByContent 分页查询内容的检查点。
+func (r *ContentCheckpointRepository) SearchByContent(contentID uint, page PageRequest) ([]model.ContentCheckpoint, int64, error) {
+	query := r.db.Model(&model.ContentCheckpoint{}).Where("content_id = ?", contentID)
+	checkpoints, total, err := findPage[model.ContentCheckpoint](query, page)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search content checkpoints")
+	}
+	return checkpoints, total, nil
+}
+
+// CheckpointAnswerRepository 随堂测验作答记录仓储。
+type CheckpointAnswerRepository struct {
+	db *gorm.DB
+}
+
+// NewCheckpointAnswerRepository 创建作答记录仓库实例。
+func NewCheckpointAnswerRepository(db *gorm.DB) *CheckpointAnswerRepository {
+	return &CheckpointAnswerRepository{db: db}
+}
+
+// ListByUserAndContent 查询用户在某个内容下的全部作答记录。
+func (r *CheckpointAnswerRepository) ListByUserAndContent(userID, contentID uint) ([]model.CheckpointAnswer, error) {
+	var answers []model.CheckpointAnswer
+	if err := r.db.Where("user_id = ? AND content_id = ?", userID, contentID).Find(&answers).Error; err != nil {
+		return nil, errors.Wrap(err, "list checkpoint answers")
+	}
+	return answers, nil
+}
+
+// RecordAttempt 累加一次作答并保存最近的选择，记录不存在时创建；答对时记录通过时间，已通过的记录
+// 不会被改回未通过。返回更新后的记录，以及本次作答是否使其首次通过：通过状态以条件更新写入，
+// 并发作答时只有一次会返回 true。
+func (r *CheckpointAnswerRepository) RecordAttempt(userID, contentID, checkpointID uint, selected string, passed bool) (*model.CheckpointAnswer, bool, error) {
+	answer := &model.CheckpointAnswer{UserID: userID, CheckpointID: checkpointID, ContentID: contentID}
+	newlyPassed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND checkpoint_id = ?", userID, checkpointID).
+			FirstOrCreate(answer).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.CheckpointAnswer{}).Where("id = ?", answer.ID).UpdateColumns(map[string]interface{}{
+			"attempts":         gorm.Expr("attempts + 1"),
+			"selected_options": selected,
+		}).Error; err != nil {
+			return err
+		}
+		if passed {
+			res := tx.Model(&model.CheckpointAnswer{}).Where("id = ? AND passed = ?", answer.ID, false).
+				UpdateColumns(map[string]interface{}{"passed": true, "passed_at": time.Now()})
+			if res.Error != nil {
+				return res.Error
+			}
+			newlyPassed = res.RowsAffected == 1
+		}
+		return tx.First(answer, answer.ID).Error
+	})
+	if err != nil {
+		return nil, false, errors.Wrap(err, "record checkpoint attempt")
+	}
+	return answer, newlyPassed, nil
+}
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// ContentRatingRepository 内容评分与评价仓储。
+type ContentRatingRepository struct {
+	db *gorm.DB
+}
+
+// NewContentRatingRepository 创建评分仓库实例。
+func NewContentRatingRepository(db *gorm.DB) *ContentRatingRepository {
+	return &ContentRatingRepository{db: db}
+}
+
+// ContentRatingFilter 管理员查询评价的过滤条件。
+type ContentRatingFilter struct {
+	ContentID uint
+	Status    string
+	MaxRating int
+	// WithReview 为 true 时只返回填写了评价文字的记录
+	WithReview bool
+}
+
+// ContentRatingAggregate 单个内容的评分汇总。
+type ContentRatingAggregate struct {
+	ContentID uint
+	Title     string
+	Average   float64
+	Count     int64
+}
+
+// FindByID 查询评分记录。
+func (r *ContentRatingRepository) FindByID(id uint) (*model.ContentRating, error) {
+	var rating model.ContentRating
+	if err := r.db.Preload("User").First(&rating, id).Error; err != nil {
+		return nil, errors.Wrap(err, "find content rating")
+	}
+	return &rating, nil
+}
+
+// FindByUserAndContent 查询用户对内容的评分，不存在时返回 gorm.ErrRecordNotFound。
+func (r *ContentRatingRepository) FindByUserAndContent(userID, contentID uint) (*model.ContentRating, error) {
+	var rating model.ContentRating
+	if err := r.db.Preload("User").Where("user_id = ? AND content_id = ?", userID, contentID).First(&rating).Error; err != nil {
+		return nil, errors.Wrap(err, "find user content rating")
+	}
+	return &rating, nil
+}
+
+// Save 新增或更新用户对内容的评分，(content_id, user_id) 唯一。
+func (r *ContentRatingRepository) Save(rating *model.ContentRating) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		existing := &model.ContentRating{}
+		if err := tx.Where("content_id = ? AND user_id = ?", rating.ContentID, rating.UserID).
+			Attrs(model.ContentRating{Rating: rating.Rating, Status: rating.Status}).
+			FirstOrCreate(existing).Error; err != nil {
+			return err
+		}
+		rating.ID = existing.ID
+		rating.CreatedAt = existing.CreatedAt
+		return tx.Omit("User").Save(rating).Error
+	})
+	if err != nil {
+		return errors.Wrap(err, "save content rating")
+	}
+	return nil
+}
+
+// Update 保存评分记录。
+func (r *ContentRatingRepository) Update(rating *model.ContentRating) error {
+	if err := r.db.Omit("User").Save(rating).Error; err != nil {
+		return errors.Wrap(err, "update content rating")
+	}
+	return nil
+}
+
+// ListApprovedReviews 查询内容下已审核通过且填写了评价文字的记录，最新的在前。
+func (r *ContentRatingRepository) ListApprovedReviews(contentID uint) ([]model.ContentRating, error) {
+	var ratings []model.ContentRating
+	if err := r.db.Preload("User").
+		Where("content_id = ? AND status = ? AND review <> ''", contentID, "approved").
+		Order("created_at desc, id desc").
+		Find(&ratings).Error; err != nil {
+		return nil, errors.Wrap(err, "list approved content reviews")
+	}
+	return ratings, nil
+}
+
+// ContentRatingSortFields 评分列表可排序字段。
+var ContentRatingSortFields = SortFields{
+	"id":         "id",
+	"rating":     "rating",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// RatedContentSortFields 内容评分排行可排序字段。
+var RatedContentSortFields = SortFields{
+	"id":      "content_id",
+	"average": "average",
+	"count":   "count",
+}
+
+// AdminSearch 管理员按条件分页查询评分。
+func (r *ContentRatingRepository) AdminSearch(filter ContentRatingFilter, page PageRequest) ([]model.ContentRating, int64, error) {
+	query := r.db.Model(&model.ContentRating{})
+	if filter.ContentID > 0 {
+		query = query.Where("content_id = ?", filter.ContentID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.MaxRating > 0 {
+		query = query.Where("rating <= ?", filter.MaxRating)
+	}
+	if filter.WithReview {
+		query = query.Where("review <> ''")
+	}
+
+	ratings, total, err := findPage[model.ContentRating](query, page, preload("User"))
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "admin search content ratings")
+	}
+	return ratings, total, nil
+}
+
+// AggregateByContents 汇总指定内容的平均分与评分人数。
+func (r *ContentRatingRepository) AggregateByContents(contentIDs []uint) (map[uint]ContentRatingAggregate, error) {
+	if len(contentIDs) == 0 {
+		return map[uint]ContentRatingAggregate{}, nil
+	}
+
+	var rows []ContentRatingAggregate
+	if err := r.db.Table("content_ratings").
+		Select("content_id, AVG(rating) AS average, COUNT(*) AS count").
+		Where("content_id IN ? AND deleted_at IS NULL", contentIDs).
+		Group("content_id").
+		Scan(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, "aggregate content ratings")
+	}
+
+	result := make(map[uint]ContentRatingAggregate, len(rows))
+	for _, row := range rows {
+		result[row.ContentID] = row
+	}
+	return result, nil
+}
+
+// Distribution 统计内容各星级的评分人数。
+func (r *ContentRatingRepository) Distribution(contentID uint) (map[int]int64, error) {
+	var rows []struct {
+		Rating int
+		Count  int64
+	}
+	if err := r.db.Model(&model.ContentRating{}).
+		Select("rating, COUNT(*) AS count").
+		Where("content_id = ?", contentID).
+		Group("rating").
+		Scan(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, "content rating distribution")
+	}
+
+	result := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		result[row.Rating] = row.Count
+	}
+	return result, nil
+}
+
+// SearchRated 分页汇总评分人数不少于 minCount 的内容的平均分与评分人数。
+func (r *ContentRatingRepository) SearchRated(minCount int64, page PageRequest) ([]ContentRatingAggregate, int64, error) {
+	grouped := r.db.Table("content_ratings").
+		Select("content_ratings.content_id, contents.title, AVG(content_ratings.rating) AS average, COUNT(*) AS count").
+		Joins("JOIN contents ON contents.id = content_ratings.content_id AND contents.deleted_at IS NULL").
+		Where("content_ratings.deleted_at IS NULL").
+		Group("content_ratings.content_id, contents.title").
+		Having("COUNT(*) >= ?", minCount)
+
+	var total int64
+	if err := r.db.Table("(?) AS rated", grouped).Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "count rated contents")
+	}
+	rows := []ContentRatingAggregate{}
+	if total == 0 {
+		return rows, 0, nil
+	}
+	if err := r.db.Table("(?) AS rated", grouped).
+		Order(page.Order).
+		Offset((page.Page - 1) * page.PageSize).
+		Limit(page.PageSize).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "search rated contents")
+	}
+	return rows, total, nil
+}
+
+// ContentCommentRepository 内容评论仓储。
+type ContentCommentRepository struct {
+	db *gorm.DB
+}
+
+// NewContentCommentRepository 创建评论仓库实例。
+func NewContentCommentRepository(db *gorm.DB) *ContentCommentRepository {
+	return &ContentCommentRepository{db: db}
+}
+
+// Create 新增评论。
+func (r *ContentCommentRepository) Create(comment *model.ContentComment) error {
+	if err := r.db.Omit("User").Create(comment).Error; err != nil {
+		return errors.Wrap(err, "create content comment")
+	}
+	return nil
+}
+
+// Update 保存评论。
+func (r *ContentCommentRepository) Update(comment *model.ContentComment) error {
+	if err := r.db.Omit("User").Save(comment).Error; err != nil {
+		return errors.Wrap(err, "update content comment")
+	}
+	return nil
+}
+
+// Delete 软删除评论。
+func (r *ContentCommentRepository) Delete(comment *model.ContentComment) error {
+	if err := r.db.Delete(comment).Error; err != nil {
+		return errors.Wrap(err, "delete content comment")
+	}
+	return nil
+}
+
+// FindByID 查询评论。
+func (r *ContentCommentRepository) FindByID(id uint) (*model.ContentComment, error) {
+	var comment model.ContentComment
+	if err := r.db.Preload("User").First(&comment, id).Error; err != nil {
+		return nil, errors.Wrap(err, "find content comment")
+	}
+	return &comment, nil
+}
+
+// FeedApprovedComments 按游标分页查询内容下已审核通过的顶层评论，最新的在前。
+func (r *ContentCommentRepository) FeedApprovedComments(contentID uint, cursor CursorRequest) ([]model.ContentComment, int64, bool, error) {
+	query := r.db.Model(&model.ContentComment{}).
+		Where("content_id = ? AND parent_id IS NULL AND status = ?", contentID, "approved")
+	comments, total, more, err := findFeed[model.ContentComment](query, "content_comments", cursor, preload("User"))
+	if err != nil {
+		return nil, 0, false, errors.Wrap(err, "feed approved content comments")
+	}
+	return comments, total, more, nil
+}
+
+// ListApprovedReplies 按时间顺序查询指定评论下已审核通过的直接回复。
+func (r *ContentCommentRepository) ListApprovedReplies(parentIDs []uint) ([]model.ContentComment, error) {
+	comments := []model.ContentComment{}
+	if len(parentIDs) == 0 {
+		return comments, nil
+	}
+	if err := r.db.Preload("User").
+		Where("parent_id IN ? AND status = ?", parentIDs, "approved").
+		Order("created_at asc, id asc").
+		Find(&comments).Error; err != nil {
+		return nil, errors.Wrap(err, "list approved content comment replies")
+	}
+	return comments, nil
+}
+
+// ContentCommentSortFields 评论列表可排序字段。
+var ContentCommentSortFields = SortFields{
+	"id":         "id",
+	"created_at": "created_at",
+	"status":     "status",
+}
+
+// AdminSearch 管理员按内容、状态与关键词分页查询评论。
+func (r *ContentCommentRepository) AdminSearch(contentID uint, keyword, status string, page PageRequest) ([]model.ContentComment, int64, error) {
+	query := r.db.Model(&model.ContentComment{})
+	if contentID > 0 {
+		query = query.Where("content_id = ?", contentID)
+	}
+	if keyword != "" {
+		query = query.Where("body LIKE ?", "%"+keyword+"%")
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	comments, total, err := findPage[model.ContentComment](query, page, preload("User"))
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "admin search content comments")
+	}
+	return comments, total, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// ContentPageRepository 文档预览页仓储。
+type ContentPageRepository struct {
+	db *gorm.DB
+}
+
+// NewContentPageRepository 创建文档预览页仓库实例。
+func NewContentPageRepository(db *gorm.DB) *ContentPageRepository {
+	return &ContentPageRepository{db: db}
+}
+
+// ReplaceForContent 在事务中替换内容的全部预览页，并同步内容的页数。
+func (r *ContentPageRepository) ReplaceForContent(contentID uint, pages []model.ContentPage, contentFields map[string]interface{}) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("content_id = ?", contentID).Delete(&model.ContentPage{}).Error; err != nil {
+			return err
+		}
+		if len(pages) > 0 {
+			if err := tx.CreateInBatches(pages, 100).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.Content{}).Where("id = ?", contentID).Updates(contentFields).Error
+	})
+	if err != nil {
+		return errors.Wrap(err, "replace content pages")
+	}
+	return nil
+}
+
+// ListByContent 分页查询内容的预览页，按页码升序。
+func (r *ContentPageRepository) ListByContent(contentID uint, maxPage, page, pageSize int) ([]model.ContentPage, error) {
+	var pages []model.ContentPage
+	if err := r.db.Where("content_id = ? AND page_no <= ?", contentID, maxPage).
+		Order("page_no ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&pages).Error; err != nil {
+		return nil, errors.Wrap(err, "list content pages")
+	}
+	return pages, nil
+}
+
+// LearningPageViewRepository 文档逐页阅读记录仓储。
+type LearningPageViewRepository struct {
+	db *gorm.DB
+}
+
+// NewLearningPageViewRepository 创建逐页阅读记录仓库实例。
+func NewLearningPageViewRepository(db *gorm.DB) *LearningPageViewRepository {
+	return &LearningPageViewRepository{db: db}
+}
+
+// AddDuration 累加用户在某页的阅读时长，记录不存在时创建。
+func (r *LearningPageViewRepository) AddDuration(userID, contentID uint, pageNo int, seconds int64) error {
+	view := &model.LearningPageView{UserID: userID, ContentID: contentID, PageNo: pageNo}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND content_id = ? AND page_no = ?", userID, contentID, pageNo).
+			FirstOrCreate(view).Error; err != nil {
+			return err
+		}
+		if seconds <= 0 {
+			return nil
+		}
+		return tx.Model(&model.LearningPageView{}).
+			Where("id = ?", view.ID).
+			UpdateColumn("duration_seconds", gorm.Expr("duration_seconds + ?", seconds)).Error
+	})
+	if err != nil {
+		return errors.Wrap(err, "add page view duration")
+	}
+	return nil
+}
+
+// CountRead 统计阅读时长达到 minSeconds 的页数（只计入当前页数范围内的页）。
+func (r *LearningPageViewRepository) CountRead(userID, contentID uint, maxPage int, minSeconds int64) (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.LearningPageView{}).
+		Where("user_id = ? AND content_id = ? AND page_no <= ? AND duration_seconds >= ?", userID, contentID, maxPage, minSeconds).
+		Count(&count).Error; err != nil {
+		return 0, errors.Wrap(err, "count read pages")
+	}
+	return count, nil
+}
+
+// ListByUserAndContent 列出用户在某文档的逐页阅读记录。
+func (r *LearningPageViewRepository) ListByUserAndContent(userID, contentID uint) ([]model.LearningPageView, error) {
+	var views []model.LearningPageView
+	if err := r.db.Where("user_id = ? AND content_id = ?", userID, contentID).
+		Order("page_no ASC").
+		Find(&views).Error; err != nil {
+		return nil, errors.Wrap(err, "list page views")
+	}
+	return views, nil
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 
@@ -17,6 +19,17 @@ func NewContentRepository(db *gorm.DB) *ContentRepository {
 	return &ContentRepository{db: db}
 }
 
+// WithTx 返回在事务 tx 中执行的仓库。
+func (r *ContentRepository) WithTx(tx *gorm.DB) *ContentRepository {
+	return &ContentRepository{db: tx}
+}
+
+// Transaction 在同一个数据库事务中执行 fn，fn 返回错误时回滚。
+// 各仓库通过 WithTx(tx) 加入该事务。
+func (r *ContentRepository) Transaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
 // Create 新增内容。
 func (r *ContentRepository) Create(content *model.Content) error {
 	if err := r.db.Create(content).Error; err != nil {
@@ -42,9 +55,30 @@ func (r *ContentRepository) FindByID(id uint) (*model.Content, error) {
 	return &content, nil
 }
 
-// ListAdmin 管理端内容列表，支持分类/类型/状态筛选。
-func (r *ContentRepository) ListAdmin(categoryID uint, contentType, status string) ([]model.Content, error) {
-	query := r.db.Preload("Category").Order("id desc")
+// FindByIDs 按 ID 批量查询内容。
+func (r *ContentRepository) FindByIDs(ids []uint) ([]model.Content, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var contents []model.Content
+	if err := r.db.Where("id IN ?", ids).Find(&contents).Error; err != nil {
+		return nil, errors.Wrap(err, "find contents by ids")
+	}
+	return contents, nil
+}
+
+// ContentSortFields 内容列表可排序字段。
+var ContentSortFields = SortFields{
+	"id":         "id",
+	"title":      "title",
+	"publish_at": "publish_at",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// SearchAdmin 管理端分页查询内容，支持分类/类型/状态筛选。
+func (r *ContentRepository) SearchAdmin(categoryID uint, contentType, status string, page PageRequest) ([]model.Content, int64, error) {
+	query := r.db.Model(&model.Content{})
 	if categoryID > 0 {
 		query = query.Where("category_id = ?", categoryID)
 	}
@@ -55,14 +89,34 @@ func (r *ContentRepository) ListAdmin(categoryID uint, contentType, status strin
 		query = query.Where("status = ?", status)
 	}
 
-	var contents []model.Content
-	if err := query.Find(&contents).Error; err != nil {
-		return nil, errors.Wrap(err, "list admin contents")
+	contents, total, err := findPage[model.Content](query, page, preload("Category"))
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search admin contents")
 	}
-	return contents, nil
+	return contents, total, nil
 }
 
-// ListPublishedByRole 根据角色、分类、类型获取已发布内容。
+// SearchPublished 按角色、分类、类型分页查询已发布内容。
+func (r *ContentRepository) SearchPublished(role string, categoryID uint, contentType string, page PageRequest) ([]model.Content, int64, error) {
+	query := r.db.Model(&model.Content{}).Where("status = ?", "published")
+	if role != "" && role != "both" {
+		query = query.Where("visible_roles = ? OR visible_roles = ?", role, "both")
+	}
+	if categoryID > 0 {
+		query = query.Where("category_id = ?", categoryID)
+	}
+	if contentType != "" {
+		query = query.Where("type = ?", contentType)
+	}
+
+	contents, total, err := findPage[model.Content](query, page, preload("Category"))
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search published contents")
+	}
+	return contents, total, nil
+}
+
+// ListPublishedByRole 根据角色、分类、类型获取全部已发布内容，用于重建搜索索引等批量场景。
 func (r *ContentRepository) ListPublishedByRole(role string, categoryID uint, contentType string) ([]model.Content, error) {
 	query := r.db.Preload("Category").
 		Where("status = ?", "published").
@@ -116,3 +170,50 @@ func (r *ContentRepository) CountPublishedByCategoryAndRole(categoryID uint, rol
 	}
 	return count, nil
 }
+
+// UpdateFields 按字段更新内容，避免整行保存覆盖并发修改。
+func (r *ContentRepository) UpdateFields(id uint, fields map[string]interface{}) error {
+	if err := r.db.Model(&model.Content{}).Where("id = ?", id).Updates(fields).Error; err != nil {
+		return errors.Wrap(err, "update content fields")
+	}
+	return nil
+}
+
+// ListDueSchedules 查询定时发布或定时下线时间已到的内容。
+func (r *ContentRepository) ListDueSchedules(now time.Time, limit int) ([]model.Content, error) {
+	var contents []model.Content
+	if err := r.db.Where("scheduled_publish_at <= ? OR scheduled_offline_at <= ?", now, now).
+		Order("id asc").Limit(limit).Find(&contents).Error; err != nil {
+		return nil, errors.Wrap(err, "list due content schedules")
+	}
+	return contents, nil
+}
+
+// ApplySchedule 仅在 column 指定的定时时间仍已到期时更新内容；计划已被其他实例执行或被修改时返回 false。
+func (r *ContentRepository) ApplySchedule(id uint, column string, now time.Time, fields map[string]interface{}) (bool, error) {
+	res := r.db.Model(&model.Content{}).Where("id = ?", id).Where(column+" <= ?", now).Updates(fields)
+	if res.Error != nil {
+		return false, errors.Wrap(res.Error, "apply content schedule")
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// ListScheduledBetween 查询计划在 [from, to) 内发布或下线的内容。
+func (r *ContentRepository) ListScheduledBetween(from, to time.Time) ([]model.Content, error) {
+	var contents []model.Content
+	if err := r.db.Where("(scheduled_publish_at >= ? AND scheduled_publish_at < ?) OR (scheduled_offline_at >= ? AND scheduled_offline_at < ?)", from, to, from, to).
+		Order("id asc").Find(&contents).Error; err != nil {
+		return nil, errors.Wrap(err, "list scheduled contents")
+	}
+	return contents, nil
+}
+
+// UpdateIfStatus 仅当内容仍处于 status 状态时更新字段，用于审核流转的并发保护。
+func (r *ContentRepository) UpdateIfStatus(id uint, status string, fields map[string]interface{}) (bool, error) {
+	res := r.db.Model(&model.Content{}).Where("id = ? AND status = ?", id, status).Updates(fields)
+	if res.Error != nil {
+		return false, errors.Wrap(res.Error, "update content review state")
+	}
+	return res.RowsAffected == 1, nil
+}
+
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// ContentRevisionRepository 内容修订版本仓储，版本只新增不修改。
+type ContentRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewContentRevisionRepository 创建内容修订版本仓库实例。
+func NewContentRevisionRepository(db *gorm.DB) *ContentRevisionRepository {
+	return &ContentRevisionRepository{db: db}
+}
+
+// WithTx 返回在事务 tx 中执行的仓库。
+func (r *ContentRevisionRepository) WithTx(tx *gorm.DB) *ContentRevisionRepository {
+	return &ContentRevisionRepository{db: tx}
+}
+
+// Create 新增修订版本，(content_id, version) 唯一。
+func (r *ContentRevisionRepository) Create(revision *model.ContentRevision) error {
+	if err := r.db.Create(revision).Error; err != nil {
+		return errors.Wrap(err, "create content revision")
+	}
+	return nil
+}
+
+// LatestVersion 返回内容的最新版本号，没有版本时返回 0。
+func (r *ContentRevisionRepository) LatestVersion(contentID uint) (int, error) {
+	var version int
+	if err := r.db.Model(&model.ContentRevision{}).Where("content_id = ?", contentID).
+		Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, errors.Wrap(err, "find latest content revision")
+	}
+	return version, nil
+}
+
+// ContentRevisionSortFields 修订版本列表可排序字段。
+var ContentRevisionSortFields = SortFields{
+	"id":         "id",
+	"version":    "version",
+	"created_at": "created_at",
+}
+
+// SearchByContent 分页查询内容的修订版本。
+func (r *ContentRevisionRepository) SearchByContent(contentID uint, page PageRequest) ([]model.ContentRevision, int64, error) {
+	query := r.db.Model(&model.ContentRevision{}).Where("content_id = ?", contentID)
+	revisions, total, err := findPage[model.ContentRevision](query, page)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search content revisions")
+	}
+	return revisions, total, nil
+}
+
+// FindByVersions 查询内容的多个指定版本。
+func (r *ContentRevisionRepository) FindByVersions(contentID uint, versions []int) ([]model.ContentRevision, error) {
+	revisions := []model.ContentRevision{}
+	if len(versions) == 0 {
+		return revisions, nil
+	}
+	if err := r.db.Where("content_id = ? AND version IN ?", contentID, versions).Find(&revisions).Error; err != nil {
+		return nil, errors.Wrap(err, "find content revisions")
+	}
+	return revisions, nil
+}
+
+// FindByVersion 查询内容的指定版本。
+func (r *ContentRevisionRepository) FindByVersion(contentID uint, version int) (*model.ContentRevision, error) {
+	var revision model.ContentRevision
+	if err := r.db.Where("content_id = ? AND version = ?", contentID, version).First(&revision).Error; err != nil {
+		return nil, errors.Wrap(err, "find content revision")
+	}
+	return &revision, nil
+}
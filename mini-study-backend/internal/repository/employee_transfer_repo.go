@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// EmployeeTransferRepository 员工调动记录仓储。
+type EmployeeTransferRepository struct {
+	db *gorm.DB
+}
+
+// NewEmployeeTransferRepository 创建调动记录仓库实例。
+func NewEmployeeTransferRepository(db *gorm.DB) *EmployeeTransferRepository {
+	return &EmployeeTransferRepository{db: db}
+}
+
+// WithTx 返回在事务 tx 中执行的仓库。
+func (r *EmployeeTransferRepository) WithTx(tx *gorm.DB) *EmployeeTransferRepository {
+	return &EmployeeTransferRepository{db: tx}
+}
+
+// Create 写入一条调动记录。
+func (r *EmployeeTransferRepository) Create(transfer *model.EmployeeTransfer) error {
+	if err := r.db.Create(transfer).Error; err != nil {
+		return errors.Wrap(err, "create employee transfer")
+	}
+	return nil
+}
+
+// ListByEmployee 按时间倒序返回员工的调动历史。
+func (r *EmployeeTransferRepository) ListByEmployee(employeeID uint) ([]model.EmployeeTransfer, error) {
+	var transfers []model.EmployeeTransfer
+	if err := r.db.Where("employee_id = ?", employeeID).
+		Order("id DESC").
+		Find(&transfers).Error; err != nil {
+		return nil, errors.Wrap(err, "list employee transfers")
+	}
+	return transfers, nil
+}
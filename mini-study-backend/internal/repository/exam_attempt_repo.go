@@ -38,6 +38,23 @@ func (r *ExamAttemptRepository) FindLatestByUserAndExam(userID, examID uint) (*m
 	return &attempt, nil
 }
 
+// ExamAttemptSortFields lists the fields exam result lists can be sorted by.
+var ExamAttemptSortFields = SortFields{
+	"id":         "id",
+	"score":      "score",
+	"created_at": "created_at",
+}
+
+// SearchByUser returns a page of a user's attempts.
+func (r *ExamAttemptRepository) SearchByUser(userID uint, page PageRequest) ([]model.ExamAttempt, int64, error) {
+	query := r.db.Model(&model.ExamAttempt{}).Where("user_id = ?", userID)
+	attempts, total, err := findPage[model.ExamAttempt](query, page, preload("Exam"))
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search attempts by user")
+	}
+	return attempts, total, nil
+}
+
 // ListByUser returns all attempts for a user ordered by submission time desc.
 func (r *ExamAttemptRepository) ListByUser(userID uint) ([]model.ExamAttempt, error) {
 	var attempts []model.ExamAttempt
@@ -51,52 +68,33 @@ func (r *ExamAttemptRepository) ListByUser(userID uint) ([]model.ExamAttempt, er
 	return attempts, nil
 }
 
-// ListLatestByUsers returns latest attempt per user.
-func (r *ExamAttemptRepository) ListLatestByUsers(userIDs []uint) (map[uint]model.ExamAttempt, error) {
-	if len(userIDs) == 0 {
-		return map[uint]model.ExamAttempt{}, nil
+// FindByID loads an attempt with its exam.
+func (r *ExamAttemptRepository) FindByID(id uint) (*model.ExamAttempt, error) {
+	var attempt model.ExamAttempt
+	if err := r.db.Preload("Exam").First(&attempt, id).Error; err != nil {
+		return nil, errors.Wrap(err, "find exam attempt")
 	}
+	return &attempt, nil
+}
 
+// ListLatestPassedByExam returns each user's latest passed attempt of an exam.
+func (r *ExamAttemptRepository) ListLatestPassedByExam(examID uint) ([]model.ExamAttempt, error) {
 	var attempts []model.ExamAttempt
 	if err := r.db.
-		Where("user_id IN ?", userIDs).
-		Order("created_at DESC").
-		Preload("Exam").
+		Where("exam_id = ? AND pass = ?", examID, true).
+		Order("created_at DESC, id DESC").
 		Find(&attempts).Error; err != nil {
-		return nil, errors.Wrap(err, "list attempts by user ids")
+		return nil, errors.Wrap(err, "list passed attempts by exam")
 	}
 
-	result := make(map[uint]model.ExamAttempt, len(userIDs))
+	latest := make([]model.ExamAttempt, 0, len(attempts))
+	seen := make(map[uint]struct{}, len(attempts))
 	for _, attempt := range attempts {
-		if _, exists := result[attempt.UserID]; exists {
+		if _, exists := seen[attempt.UserID]; exists {
 			continue
 		}
-		result[attempt.UserID] = attempt
-	}
-	return result, nil
-}
-
-// ExamAggregateRow stores aggregated stats.
-type ExamAggregateRow struct {
-	ExamID       uint
-	AttemptCount int64
-	PassCount    int64
-	AvgScore     float64
-}
-
-// AggregateByExamForUsers aggregates attempt stats for given users.
-func (r *ExamAttemptRepository) AggregateByExamForUsers(userIDs []uint) ([]ExamAggregateRow, error) {
-	if len(userIDs) == 0 {
-		return []ExamAggregateRow{}, nil
-	}
-
-	var rows []ExamAggregateRow
-	if err := r.db.Table("exam_attempts").
-		Select("exam_id, COUNT(*) as attempt_count, SUM(CASE WHEN pass = 1 THEN 1 ELSE 0 END) as pass_count, AVG(score) as avg_score").
-		Where("user_id IN ?", userIDs).
-		Group("exam_id").
-		Scan(&rows).Error; err != nil {
-		return nil, errors.Wrap(err, "aggregate attempts by exam")
+		seen[attempt.UserID] = struct{}{}
+		latest = append(latest, attempt)
 	}
-	return rows, nil
+	return latest, nil
 }
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 
@@ -36,6 +38,9 @@ func (r *ExamRepository) UpdateExam(exam *model.ExamPaper) error {
 			"time_limit_minutes": exam.TimeLimitMinutes,
 			"pass_score":         exam.PassScore,
 			"total_score":        exam.TotalScore,
+
+			"scheduled_publish_at": exam.ScheduledPublishAt,
+			"scheduled_offline_at": exam.ScheduledOfflineAt,
 		}).Error; err != nil {
 		return errors.Wrap(err, "update exam")
 	}
@@ -121,27 +126,56 @@ func (r *ExamRepository) FindPublishedWithQuestions(id uint) (*model.ExamPaper,
 	return &exam, nil
 }
 
-// ListPublishedByRole lists published exams that match role scope.
-func (r *ExamRepository) ListPublishedByRole(role model.Role) ([]model.ExamPaper, error) {
-	var exams []model.ExamPaper
-	query := r.db.
+// ExamSortFields lists the fields exam lists can be sorted by.
+var ExamSortFields = SortFields{
+	"id":         "id",
+	"title":      "title",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"pass_score": "pass_score",
+}
+
+// SearchPublishedByRole returns a page of published exams that match role scope.
+func (r *ExamRepository) SearchPublishedByRole(role model.Role, page PageRequest) ([]model.ExamPaper, int64, error) {
+	query := r.db.Model(&model.ExamPaper{}).
 		Where("status = ?", "published").
-		Order("id DESC")
+		Where("target_role = ? OR target_role = ?", role, "all")
 
-	query = query.Where("target_role = ? OR target_role = ?", role, "all")
+	exams, total, err := findPage[model.ExamPaper](query, page, preloadQuestionIDs)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search published exams")
+	}
+	countQuestions(exams)
+	return exams, total, nil
+}
 
-	if err := query.Preload("Questions", func(db *gorm.DB) *gorm.DB {
-		return db.Select("id, exam_id").Order("exam_questions.id ASC")
-	}).Find(&exams).Error; err != nil {
-		return nil, errors.Wrap(err, "list published exams")
+// SearchAdmin returns a page of exams for admin, optionally filtered by status.
+func (r *ExamRepository) SearchAdmin(status string, page PageRequest) ([]model.ExamPaper, int64, error) {
+	query := r.db.Model(&model.ExamPaper{})
+	if status != "" {
+		query = query.Where("status = ?", status)
 	}
 
+	exams, total, err := findPage[model.ExamPaper](query, page, preloadQuestionIDs)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search admin exams")
+	}
+	countQuestions(exams)
+	return exams, total, nil
+}
+
+// preloadQuestionIDs loads just the question IDs, enough to count them.
+func preloadQuestionIDs(db *gorm.DB) *gorm.DB {
+	return db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id, exam_id").Order("exam_questions.id ASC")
+	})
+}
+
+func countQuestions(exams []model.ExamPaper) {
 	for idx := range exams {
 		exams[idx].QuestionCount = len(exams[idx].Questions)
 		exams[idx].Questions = nil
 	}
-
-	return exams, nil
 }
 
 // CountQuestions returns how many questions belong to an exam.
@@ -153,18 +187,13 @@ func (r *ExamRepository) CountQuestions(examID uint) (int64, error) {
 	return count, nil
 }
 
-// ListAll loads all exams for admin with question count.
+// ListAll loads all exams with question count, for batch jobs such as rebuilding the search index.
 func (r *ExamRepository) ListAll() ([]model.ExamPaper, error) {
 	var exams []model.ExamPaper
-	if err := r.db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
-		return db.Select("id, exam_id").Order("exam_questions.id ASC")
-	}).Find(&exams).Error; err != nil {
+	if err := r.db.Scopes(preloadQuestionIDs).Find(&exams).Error; err != nil {
 		return nil, errors.Wrap(err, "list all exams")
 	}
-	for idx := range exams {
-		exams[idx].QuestionCount = len(exams[idx].Questions)
-		exams[idx].Questions = nil
-	}
+	countQuestions(exams)
 	return exams, nil
 }
 
@@ -179,3 +208,50 @@ func (r *ExamRepository) FindByIDs(ids []uint) ([]model.ExamPaper, error) {
 	}
 	return exams, nil
 }
+
+// UpdateFields updates the given exam columns only.
+func (r *ExamRepository) UpdateFields(id uint, fields map[string]interface{}) error {
+	if err := r.db.Model(&model.ExamPaper{}).Where("id = ?", id).Updates(fields).Error; err != nil {
+		return errors.Wrap(err, "update exam fields")
+	}
+	return nil
+}
+
+// ListDueSchedules lists exams whose scheduled publish or archive time has passed.
+func (r *ExamRepository) ListDueSchedules(now time.Time, limit int) ([]model.ExamPaper, error) {
+	var exams []model.ExamPaper
+	if err := r.db.Where("scheduled_publish_at <= ? OR scheduled_offline_at <= ?", now, now).
+		Order("id ASC").Limit(limit).Find(&exams).Error; err != nil {
+		return nil, errors.Wrap(err, "list due exam schedules")
+	}
+	return exams, nil
+}
+
+// ApplySchedule updates an exam only while the schedule in column is still due. It returns
+// false when another replica already applied it or the schedule was changed meanwhile.
+func (r *ExamRepository) ApplySchedule(id uint, column string, now time.Time, fields map[string]interface{}) (bool, error) {
+	res := r.db.Model(&model.ExamPaper{}).Where("id = ?", id).Where(column+" <= ?", now).Updates(fields)
+	if res.Error != nil {
+		return false, errors.Wrap(res.Error, "apply exam schedule")
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// ListScheduledBetween lists exams scheduled to be published or archived within [from, to).
+func (r *ExamRepository) ListScheduledBetween(from, to time.Time) ([]model.ExamPaper, error) {
+	var exams []model.ExamPaper
+	if err := r.db.Where("(scheduled_publish_at >= ? AND scheduled_publish_at < ?) OR (scheduled_offline_at >= ? AND scheduled_offline_at < ?)", from, to, from, to).
+		Order("id ASC").Find(&exams).Error; err != nil {
+		return nil, errors.Wrap(err, "list scheduled exams")
+	}
+	return exams, nil
+}
+
+// UpdateIfStatus 仅当试卷仍处于 status 状态时更新字段，用于审核流转的并发保护。
+func (r *ExamRepository) UpdateIfStatus(id uint, status string, fields map[string]interface{}) (bool, error) {
+	res := r.db.Model(&model.ExamPaper{}).Where("id = ? AND status = ?", id, status).Updates(fields)
+	if res.Error != nil {
+		return false, errors.Wrap(res.Error, "update exam review state")
+	}
+	return res.RowsAffected == 1, nil
+}
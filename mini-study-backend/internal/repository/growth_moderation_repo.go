@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// CreateWithEvents inserts a post together with its topic links and the first entries of its
+// moderation history.
+func (r *GrowthPostRepository) CreateWithEvents(post *model.GrowthPost, events ...model.GrowthModerationEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+		if err := syncGrowthTopics(tx, post); err != nil {
+			return err
+		}
+		return createGrowthEvents(tx, post.ID, events)
+	})
+	if err != nil {
+		return errors.Wrap(err, "create growth post")
+	}
+	return nil
+}
+
+// UpdateWithEvents saves a post, like Update, and appends to its moderation history. The topic
+// links are rebuilt from post.Topics since an edited post may tag different topics.
+func (r *GrowthPostRepository) UpdateWithEvents(post *model.GrowthPost, events ...model.GrowthModerationEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("like_count", "comment_count", "share_count").Save(post).Error; err != nil {
+			return err
+		}
+		if err := syncGrowthTopics(tx, post); err != nil {
+			return err
+		}
+		return createGrowthEvents(tx, post.ID, events)
+	})
+	if err != nil {
+		return errors.Wrap(err, "update growth post")
+	}
+	return nil
+}
+
+func createGrowthEvents(tx *gorm.DB, postID uint, events []model.GrowthModerationEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	for i := range events {
+		events[i].PostID = postID
+	}
+	return tx.Create(&events).Error
+}
+
+// ListEvents returns the moderation history of a post, oldest first.
+func (r *GrowthPostRepository) ListEvents(postID uint) ([]model.GrowthModerationEvent, error) {
+	var events []model.GrowthModerationEvent
+	if err := r.db.Where("post_id = ?", postID).Order("id asc").Find(&events).Error; err != nil {
+		return nil, errors.Wrap(err, "list growth moderation events")
+	}
+	return events, nil
+}
+
+// ListRejections returns the reason code and time of every manual and automatic rejection
+// within [from, to).
+func (r *GrowthPostRepository) ListRejections(from, to time.Time) ([]model.GrowthModerationEvent, error) {
+	var events []model.GrowthModerationEvent
+	if err := r.db.Select("id, reason_code, created_at").
+		Where("action IN ?", []string{model.GrowthActionReject, model.GrowthActionAutoReject}).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Order("created_at asc").
+		Find(&events).Error; err != nil {
+		return nil, errors.Wrap(err, "list growth rejections")
+	}
+	return events, nil
+}
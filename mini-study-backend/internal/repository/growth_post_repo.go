@@ -3,6 +3,7 @@ package repository
 import (
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/javapub/mini-study/mini-study-backend/internal/model"
 )
@@ -25,9 +26,10 @@ func (r *GrowthPostRepository) Create(post *model.GrowthPost) error {
 	return nil
 }
 
-// Update updates a growth post.
+// Update updates a growth post. The like, comment and share counters are maintained by the
+// interaction methods below and are never overwritten here.
 func (r *GrowthPostRepository) Update(post *model.GrowthPost) error {
-	if err := r.db.Save(post).Error; err != nil {
+	if err := r.db.Omit("like_count", "comment_count", "share_count").Save(post).Error; err != nil {
 		return errors.Wrap(err, "update growth post")
 	}
 	return nil
@@ -50,7 +52,20 @@ func (r *GrowthPostRepository) FindByID(id uint) (*model.GrowthPost, error) {
 	return &post, nil
 }
 
-// ListPublic returns approved posts visible to everyone, optionally filtered by keyword.
+// FindByIDs returns growth posts by ID list.
+func (r *GrowthPostRepository) FindByIDs(ids []uint) ([]model.GrowthPost, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var posts []model.GrowthPost
+	if err := r.db.Where("id IN ?", ids).Find(&posts).Error; err != nil {
+		return nil, errors.Wrap(err, "find growth posts by ids")
+	}
+	return posts, nil
+}
+
+// ListPublic returns all approved posts, optionally filtered by keyword, for batch jobs such as
+// rebuilding the search index.
 func (r *GrowthPostRepository) ListPublic(keyword string) ([]model.GrowthPost, error) {
 	query := r.db.Preload("Creator").
 		Where("status = ?", "approved").
@@ -67,40 +82,252 @@ func (r *GrowthPostRepository) ListPublic(keyword string) ([]model.GrowthPost, e
 	return posts, nil
 }
 
-// ListByCreator returns posts created by a specific user.
-func (r *GrowthPostRepository) ListByCreator(creatorID uint, keyword, status string) ([]model.GrowthPost, error) {
-	query := r.db.Preload("Creator").
-		Where("creator_id = ?", creatorID).
-		Order("created_at desc, id desc")
+// GrowthPostSortFields lists the fields admin growth post lists can be sorted by.
+var GrowthPostSortFields = SortFields{
+	"id":         "id",
+	"created_at": "created_at",
+	"status":     "status",
+}
+
+// FeedByCreator reads the posts of a specific user, newest first.
+func (r *GrowthPostRepository) FeedByCreator(creatorID uint, keyword, status string, cursor CursorRequest) ([]model.GrowthPost, int64, bool, error) {
+	query := r.db.Model(&model.GrowthPost{}).Where("creator_id = ?", creatorID)
 	if keyword != "" {
-		like := "%" + keyword + "%"
-		query = query.Where("content LIKE ?", like)
+		query = query.Where("content LIKE ?", "%"+keyword+"%")
 	}
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
 
-	var posts []model.GrowthPost
-	if err := query.Find(&posts).Error; err != nil {
-		return nil, errors.Wrap(err, "list my growth posts")
+	posts, total, more, err := findFeed[model.GrowthPost](query, "growth_posts", cursor, preload("Creator"))
+	if err != nil {
+		return nil, 0, false, errors.Wrap(err, "feed my growth posts")
 	}
-	return posts, nil
+	return posts, total, more, nil
 }
 
-// AdminList returns posts for admin with optional status and keyword filters.
-func (r *GrowthPostRepository) AdminList(keyword, status string) ([]model.GrowthPost, error) {
-	query := r.db.Preload("Creator").Order("created_at desc, id desc")
+// SearchAdmin returns a page of posts for admin with optional status, moderation verdict and
+// keyword filters.
+func (r *GrowthPostRepository) SearchAdmin(keyword, status, verdict string, page PageRequest) ([]model.GrowthPost, int64, error) {
+	query := r.db.Model(&model.GrowthPost{})
 	if keyword != "" {
-		like := "%" + keyword + "%"
-		query = query.Where("content LIKE ?", like)
+		query = query.Where("content LIKE ?", "%"+keyword+"%")
 	}
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
+	if verdict != "" {
+		query = query.Where("moderation_verdict = ?", verdict)
+	}
 
-	var posts []model.GrowthPost
-	if err := query.Find(&posts).Error; err != nil {
-		return nil, errors.Wrap(err, "admin list growth posts")
+	posts, total, err := findPage[model.GrowthPost](query, page, preload("Creator"))
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search admin growth posts")
 	}
-	return posts, nil
+	return posts, total, nil
+}
+
+// Like records a like of a post by a user. It reports false when the user had already liked the
+// post, in which case nothing changes.
+func (r *GrowthPostRepository) Like(postID, userID uint) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Omit("User").
+			Create(&model.GrowthLike{PostID: postID, UserID: userID})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+		return tx.Model(&model.GrowthPost{}).Where("id = ?", postID).
+			UpdateColumn("like_count", gorm.Expr("like_count + 1")).Error
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "like growth post")
+	}
+	return created, nil
+}
+
+// Unlike removes a user's like of a post. It reports false when there was no like to remove.
+func (r *GrowthPostRepository) Unlike(postID, userID uint) (bool, error) {
+	removed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Unscoped().Where("post_id = ? AND user_id = ?", postID, userID).Delete(&model.GrowthLike{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		removed = true
+		return tx.Model(&model.GrowthPost{}).Where("id = ? AND like_count > 0", postID).
+			UpdateColumn("like_count", gorm.Expr("like_count - 1")).Error
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "unlike growth post")
+	}
+	return removed, nil
+}
+
+// LikedPostIDs reports which of the given posts the user has liked.
+func (r *GrowthPostRepository) LikedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error) {
+	liked := make(map[uint]bool, len(postIDs))
+	if len(postIDs) == 0 {
+		return liked, nil
+	}
+	var ids []uint
+	if err := r.db.Model(&model.GrowthLike{}).
+		Where("user_id = ? AND post_id IN ?", userID, postIDs).
+		Pluck("post_id", &ids).Error; err != nil {
+		return nil, errors.Wrap(err, "list liked growth posts")
+	}
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, nil
+}
+
+// FeedLikers reads the likes of a post, most recent first.
+func (r *GrowthPostRepository) FeedLikers(postID uint, cursor CursorRequest) ([]model.GrowthLike, int64, bool, error) {
+	query := r.db.Model(&model.GrowthLike{}).Where("post_id = ?", postID)
+	likes, total, more, err := findFeed[model.GrowthLike](query, "growth_likes", cursor, preload("User"))
+	if err != nil {
+		return nil, 0, false, errors.Wrap(err, "feed growth post likers")
+	}
+	return likes, total, more, nil
+}
+
+// Share records a user's share of a post and bumps its share counter. Repeated shares by the
+// same user are ignored; it reports whether this was the user's first share of the post.
+func (r *GrowthPostRepository) Share(postID, userID uint, channel string) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.GrowthShare{PostID: postID, UserID: userID, Channel: channel})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+		return tx.Model(&model.GrowthPost{}).Where("id = ?", postID).
+			UpdateColumn("share_count", gorm.Expr("share_count + 1")).Error
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "share growth post")
+	}
+	return created, nil
+}
+
+// CreateComment inserts a comment on a post.
+func (r *GrowthPostRepository) CreateComment(comment *model.GrowthComment) error {
+	if err := r.db.Omit("User").Create(comment).Error; err != nil {
+		return errors.Wrap(err, "create growth comment")
+	}
+	return nil
+}
+
+// UpdateComment saves a comment.
+func (r *GrowthPostRepository) UpdateComment(comment *model.GrowthComment) error {
+	if err := r.db.Omit("User").Save(comment).Error; err != nil {
+		return errors.Wrap(err, "update growth comment")
+	}
+	return nil
+}
+
+// DeleteComment soft-deletes a comment.
+func (r *GrowthPostRepository) DeleteComment(comment *model.GrowthComment) error {
+	if err := r.db.Delete(comment).Error; err != nil {
+		return errors.Wrap(err, "delete growth comment")
+	}
+	return nil
+}
+
+// FindComment finds a comment by id.
+func (r *GrowthPostRepository) FindComment(id uint) (*model.GrowthComment, error) {
+	var comment model.GrowthComment
+	if err := r.db.Preload("User").First(&comment, id).Error; err != nil {
+		return nil, errors.Wrap(err, "find growth comment")
+	}
+	return &comment, nil
+}
+
+// FeedApprovedComments reads the approved top-level comments of a post, most recent first.
+func (r *GrowthPostRepository) FeedApprovedComments(postID uint, cursor CursorRequest) ([]model.GrowthComment, int64, bool, error) {
+	query := r.db.Model(&model.GrowthComment{}).
+		Where("post_id = ? AND parent_id IS NULL AND status = ?", postID, "approved")
+	comments, total, more, err := findFeed[model.GrowthComment](query, "growth_comments", cursor, preload("User"))
+	if err != nil {
+		return nil, 0, false, errors.Wrap(err, "feed approved growth comments")
+	}
+	return comments, total, more, nil
+}
+
+// ListApprovedReplies returns the approved direct replies to the given comments in chronological
+// order.
+func (r *GrowthPostRepository) ListApprovedReplies(parentIDs []uint) ([]model.GrowthComment, error) {
+	comments := []model.GrowthComment{}
+	if len(parentIDs) == 0 {
+		return comments, nil
+	}
+	if err := r.db.Preload("User").
+		Where("parent_id IN ? AND status = ?", parentIDs, "approved").
+		Order("created_at asc, id asc").
+		Find(&comments).Error; err != nil {
+		return nil, errors.Wrap(err, "list approved growth comment replies")
+	}
+	return comments, nil
+}
+
+// ListApprovedComments returns all the approved comments of a post in chronological order, for
+// counting the visible thread.
+func (r *GrowthPostRepository) ListApprovedComments(postID uint) ([]model.GrowthComment, error) {
+	var comments []model.GrowthComment
+	if err := r.db.Preload("User").
+		Where("post_id = ? AND status = ?", postID, "approved").
+		Order("created_at asc, id asc").
+		Find(&comments).Error; err != nil {
+		return nil, errors.Wrap(err, "list approved growth comments")
+	}
+	return comments, nil
+}
+
+// SetCommentCount stores the number of publicly visible comments of a post.
+func (r *GrowthPostRepository) SetCommentCount(postID uint, count int64) error {
+	if err := r.db.Model(&model.GrowthPost{}).Where("id = ?", postID).
+		UpdateColumn("comment_count", count).Error; err != nil {
+		return errors.Wrap(err, "set growth comment count")
+	}
+	return nil
+}
+
+// GrowthCommentSortFields lists the fields admin growth comment lists can be sorted by.
+var GrowthCommentSortFields = SortFields{
+	"id":         "id",
+	"created_at": "created_at",
+	"status":     "status",
+}
+
+// SearchComments returns a page of comments for admin with optional post, keyword and status filters.
+func (r *GrowthPostRepository) SearchComments(postID uint, keyword, status string, page PageRequest) ([]model.GrowthComment, int64, error) {
+	query := r.db.Model(&model.GrowthComment{})
+	if postID > 0 {
+		query = query.Where("post_id = ?", postID)
+	}
+	if keyword != "" {
+		query = query.Where("body LIKE ?", "%"+keyword+"%")
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	comments, total, err := findPage[model.GrowthComment](query, page, preload("User"))
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search growth comments")
+	}
+	return comments, total, nil
 }
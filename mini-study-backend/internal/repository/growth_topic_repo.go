@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// GrowthViewer describes who is reading growth posts. Admins see every post; other users see
+// company-wide posts, posts targeting their role, team posts by members of TeamIDs and their
+// own posts.
+type GrowthViewer struct {
+	UserID  uint
+	Role    string
+	TeamIDs []uint
+	Admin   bool
+}
+
+func (v GrowthViewer) scope(db *gorm.DB) *gorm.DB {
+	if v.Admin {
+		return db
+	}
+	teamIDs := v.TeamIDs
+	if len(teamIDs) == 0 {
+		teamIDs = []uint{0}
+	}
+	return db.Where("(growth_posts.creator_id = ? OR growth_posts.scope = ? OR "+
+		"(growth_posts.scope = ? AND growth_posts.visible_roles LIKE ?) OR "+
+		"(growth_posts.scope = ? AND growth_posts.creator_id IN ?))",
+		v.UserID, model.GrowthScopeCompany,
+		model.GrowthScopeRoles, "%,"+v.Role+",%",
+		model.GrowthScopeTeam, teamIDs)
+}
+
+// GrowthFeedFilter narrows the approved posts feed. Pinned posts are left out of the feed when
+// ExcludePinned is set, since they are returned separately above it.
+type GrowthFeedFilter struct {
+	Keyword       string
+	Topic         string
+	Featured      bool
+	ExcludePinned bool
+}
+
+func (r *GrowthPostRepository) approvedFor(viewer GrowthViewer, topic string) *gorm.DB {
+	query := r.db.Model(&model.GrowthPost{}).Where("growth_posts.status = ?", "approved").Scopes(viewer.scope)
+	if topic != "" {
+		query = query.Where("growth_posts.id IN (?)", r.db.Model(&model.GrowthPostTopic{}).
+			Select("growth_post_topics.post_id").
+			Joins("JOIN growth_topics ON growth_topics.id = growth_post_topics.topic_id").
+			Where("growth_topics.name = ?", topic))
+	}
+	return query
+}
+
+// FeedVisible reads the approved posts the viewer may see, newest first.
+func (r *GrowthPostRepository) FeedVisible(viewer GrowthViewer, filter GrowthFeedFilter, cursor CursorRequest) ([]model.GrowthPost, int64, bool, error) {
+	query := r.approvedFor(viewer, filter.Topic)
+	if filter.Keyword != "" {
+		query = query.Where("growth_posts.content LIKE ?", "%"+filter.Keyword+"%")
+	}
+	if filter.Featured {
+		query = query.Where("growth_posts.featured_at IS NOT NULL")
+	}
+	if filter.ExcludePinned {
+		query = query.Where("growth_posts.pinned_at IS NULL")
+	}
+
+	posts, total, more, err := findFeed[model.GrowthPost](query, "growth_posts", cursor, preload("Creator"))
+	if err != nil {
+		return nil, 0, false, errors.Wrap(err, "feed visible growth posts")
+	}
+	return posts, total, more, nil
+}
+
+// ListPinned returns the pinned approved posts the viewer may see, most recently pinned first.
+func (r *GrowthPostRepository) ListPinned(viewer GrowthViewer, topic string) ([]model.GrowthPost, error) {
+	var posts []model.GrowthPost
+	if err := r.approvedFor(viewer, topic).
+		Where("growth_posts.pinned_at IS NOT NULL").
+		Preload("Creator").
+		Order("growth_posts.pinned_at DESC").
+		Find(&posts).Error; err != nil {
+		return nil, errors.Wrap(err, "list pinned growth posts")
+	}
+	return posts, nil
+}
+
+// CountPinned returns the number of pinned approved posts.
+func (r *GrowthPostRepository) CountPinned() (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.GrowthPost{}).
+		Where("status = ? AND pinned_at IS NOT NULL", "approved").
+		Count(&count).Error; err != nil {
+		return 0, errors.Wrap(err, "count pinned growth posts")
+	}
+	return count, nil
+}
+
+// GrowthTopicCount is a topic with the number of posts tagged with it.
+type GrowthTopicCount struct {
+	Name      string
+	PostCount int64
+}
+
+// TrendingTopics ranks the topics of the approved posts the viewer may see by the number of
+// posts approved since the given time.
+func (r *GrowthPostRepository) TrendingTopics(viewer GrowthViewer, since time.Time, limit int) ([]GrowthTopicCount, error) {
+	var topics []GrowthTopicCount
+	if err := r.db.Model(&model.GrowthPostTopic{}).
+		Select("growth_topics.name AS name, COUNT(*) AS post_count").
+		Joins("JOIN growth_posts ON growth_posts.id = growth_post_topics.post_id AND growth_posts.deleted_at IS NULL").
+		Joins("JOIN growth_topics ON growth_topics.id = growth_post_topics.topic_id").
+		Where("growth_posts.status = ? AND growth_posts.approved_at >= ?", "approved", since).
+		Scopes(viewer.scope).
+		Group("growth_topics.id, growth_topics.name").
+		Order("post_count DESC, growth_topics.name ASC").
+		Limit(limit).
+		Scan(&topics).Error; err != nil {
+		return nil, errors.Wrap(err, "list trending growth topics")
+	}
+	return topics, nil
+}
+
+// syncGrowthTopics replaces the topic links of a post with the topics listed in post.Topics,
+// creating topics seen for the first time.
+func syncGrowthTopics(tx *gorm.DB, post *model.GrowthPost) error {
+	if err := tx.Unscoped().Where("post_id = ?", post.ID).Delete(&model.GrowthPostTopic{}).Error; err != nil {
+		return err
+	}
+	if post.Topics == "" {
+		return nil
+	}
+	for _, name := range strings.Split(post.Topics, ",") {
+		var topic model.GrowthTopic
+		if err := tx.Where(model.GrowthTopic{Name: name}).FirstOrCreate(&topic).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Topic").Create(&model.GrowthPostTopic{PostID: post.ID, TopicID: topic.ID}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/javapub/mini-study/mini-study-backend/internal/model"
 )
@@ -53,6 +56,44 @@ func (r *LearningRecordRepository) FirstOrCreate(userID, contentID uint) (*model
 	return record, nil
 }
 
+// RecordActivity 累加用户当天学习某内容的上报次数与视频推进秒数，当天首次上报时创建。
+func (r *LearningRecordRepository) RecordActivity(userID, contentID uint, day time.Time, watchSeconds int64) error {
+	activity := &model.LearningActivity{UserID: userID, ContentID: contentID, ActivityDate: day, Updates: 1, WatchSeconds: watchSeconds}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "content_id"}, {Name: "activity_date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"updates":       gorm.Expr("updates + 1"),
+			"watch_seconds": gorm.Expr("watch_seconds + ?", watchSeconds),
+			"updated_at":    time.Now(),
+		}),
+	}).Create(activity).Error
+	if err != nil {
+		return errors.Wrap(err, "record learning activity")
+	}
+	return nil
+}
+
+// LearningRecordSortFields 学习记录列表可排序字段。
+var LearningRecordSortFields = SortFields{
+	"id":         "id",
+	"progress":   "progress",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// SearchByUser 分页查询用户的学习记录，可按状态筛选。
+func (r *LearningRecordRepository) SearchByUser(userID uint, status string, page PageRequest) ([]model.LearningRecord, int64, error) {
+	query := r.db.Model(&model.LearningRecord{}).Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	records, total, err := findPage[model.LearningRecord](query, page)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search learning records by user")
+	}
+	return records, total, nil
+}
+
 // ListByUser 列出用户的全部学习记录。
 func (r *LearningRecordRepository) ListByUser(userID uint) ([]model.LearningRecord, error) {
 	var records []model.LearningRecord
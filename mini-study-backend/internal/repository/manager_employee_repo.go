@@ -17,6 +17,11 @@ func NewManagerEmployeeRepository(db *gorm.DB) *ManagerEmployeeRepository {
 	return &ManagerEmployeeRepository{db: db}
 }
 
+// WithTx returns a repository bound to transaction tx.
+func (r *ManagerEmployeeRepository) WithTx(tx *gorm.DB) *ManagerEmployeeRepository {
+	return &ManagerEmployeeRepository{db: tx}
+}
+
 // CreateRelations creates manager-employee relations in batch.
 func (r *ManagerEmployeeRepository) CreateRelations(employeeID uint, managerIDs []uint) error {
 	if len(managerIDs) == 0 {
@@ -70,3 +75,41 @@ func (r *ManagerEmployeeRepository) ListEmployeeIDsByManager(managerID uint) ([]
 	}
 	return ids, nil
 }
+
+// ListEmployeeIDsByManagers returns the distinct employee IDs managed by any of the managers.
+func (r *ManagerEmployeeRepository) ListEmployeeIDsByManagers(managerIDs []uint) ([]uint, error) {
+	if len(managerIDs) == 0 {
+		return nil, nil
+	}
+	var ids []uint
+	if err := r.db.
+		Model(&model.ManagerEmployee{}).
+		Distinct("employee_id").
+		Where("manager_id IN ?", managerIDs).
+		Pluck("employee_id", &ids).Error; err != nil {
+		return nil, errors.Wrap(err, "list employees by managers")
+	}
+	return ids, nil
+}
+
+// ListManagerIDsByEmployee returns manager IDs bound to an employee.
+func (r *ManagerEmployeeRepository) ListManagerIDsByEmployee(employeeID uint) ([]uint, error) {
+	var ids []uint
+	if err := r.db.
+		Model(&model.ManagerEmployee{}).
+		Where("employee_id = ?", employeeID).
+		Order("id ASC").
+		Pluck("manager_id", &ids).Error; err != nil {
+		return nil, errors.Wrap(err, "list managers by employee")
+	}
+	return ids, nil
+}
+
+// DeleteByUser removes every relation the user participates in, either as employee or as manager.
+func (r *ManagerEmployeeRepository) DeleteByUser(userID uint) (int64, error) {
+	result := r.db.Where("employee_id = ? OR manager_id = ?", userID, userID).Delete(&model.ManagerEmployee{})
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "delete manager_employee relations by user")
+	}
+	return result.RowsAffected, nil
+}
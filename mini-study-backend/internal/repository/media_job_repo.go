@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// MediaJobFilter narrows admin media job queries.
+type MediaJobFilter struct {
+	ContentID uint
+	Status    string
+}
+
+// MediaJobRepository 视频处理任务仓储。
+type MediaJobRepository struct {
+	db *gorm.DB
+}
+
+// NewMediaJobRepository 创建视频处理任务仓库实例。
+func NewMediaJobRepository(db *gorm.DB) *MediaJobRepository {
+	return &MediaJobRepository{db: db}
+}
+
+// Create 新建任务。
+func (r *MediaJobRepository) Create(job *model.MediaJob) error {
+	if err := r.db.Create(job).Error; err != nil {
+		return errors.Wrap(err, "create media job")
+	}
+	return nil
+}
+
+// Update 保存任务。
+func (r *MediaJobRepository) Update(job *model.MediaJob) error {
+	if err := r.db.Save(job).Error; err != nil {
+		return errors.Wrap(err, "update media job")
+	}
+	return nil
+}
+
+// FindByID 通过 ID 查询任务。
+func (r *MediaJobRepository) FindByID(id uint) (*model.MediaJob, error) {
+	var job model.MediaJob
+	if err := r.db.First(&job, id).Error; err != nil {
+		return nil, errors.Wrap(err, "find media job")
+	}
+	return &job, nil
+}
+
+// CancelPendingByContent 取消内容尚未执行的任务（源文件已被替换）。
+func (r *MediaJobRepository) CancelPendingByContent(contentID uint) error {
+	if err := r.db.Model(&model.MediaJob{}).
+		Where("content_id = ? AND status = ?", contentID, model.MediaJobPending).
+		Update("status", model.MediaJobCanceled).Error; err != nil {
+		return errors.Wrap(err, "cancel pending media jobs")
+	}
+	return nil
+}
+
+// ListClaimable 返回可执行的任务：到期的待处理任务，以及租约已过期（进程中断）的处理中任务。
+func (r *MediaJobRepository) ListClaimable(now time.Time, limit int) ([]model.MediaJob, error) {
+	var jobs []model.MediaJob
+	if err := r.db.Where("(status = ? AND next_run_at <= ?) OR (status = ? AND lease_until < ?)",
+		model.MediaJobPending, now, model.MediaJobRunning, now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&jobs).Error; err != nil {
+		return nil, errors.Wrap(err, "list claimable media jobs")
+	}
+	return jobs, nil
+}
+
+// Claim 以条件更新抢占任务，多实例部署时只有一个实例会成功。
+func (r *MediaJobRepository) Claim(job *model.MediaJob, now, leaseUntil time.Time) (bool, error) {
+	res := r.db.Model(&model.MediaJob{}).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, job.Status, job.Attempts).
+		Updates(map[string]interface{}{
+			"status":      model.MediaJobRunning,
+			"attempts":    job.Attempts + 1,
+			"lease_until": leaseUntil,
+			"started_at":  now,
+		})
+	if res.Error != nil {
+		return false, errors.Wrap(res.Error, "claim media job")
+	}
+	if res.RowsAffected != 1 {
+		return false, nil
+	}
+	job.Status = model.MediaJobRunning
+	job.Attempts++
+	job.LeaseUntil = &leaseUntil
+	job.StartedAt = &now
+	return true, nil
+}
+
+// Search 分页查询任务，按 ID 倒序。
+func (r *MediaJobRepository) Search(filter MediaJobFilter, page, pageSize int) ([]model.MediaJob, int64, error) {
+	query := r.db.Model(&model.MediaJob{})
+	if filter.ContentID > 0 {
+		query = query.Where("content_id = ?", filter.ContentID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "count media jobs")
+	}
+
+	var jobs []model.MediaJob
+	if err := query.Order("id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&jobs).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "search media jobs")
+	}
+	return jobs, total, nil
+}
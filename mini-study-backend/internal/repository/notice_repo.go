@@ -44,17 +44,26 @@ func (r *NoticeRepository) FindByID(id uint) (*model.Notice, error) {
 	return &notice, nil
 }
 
-// ListAdmin returns all notices optionally filtered by status.
-func (r *NoticeRepository) ListAdmin(status *bool) ([]model.Notice, error) {
-	query := r.db.Order("start_at desc, id desc")
+// NoticeSortFields lists the fields admin notice lists can be sorted by.
+var NoticeSortFields = SortFields{
+	"id":         "id",
+	"title":      "title",
+	"start_at":   "start_at",
+	"end_at":     "end_at",
+	"created_at": "created_at",
+}
+
+// SearchAdmin returns a page of notices optionally filtered by status.
+func (r *NoticeRepository) SearchAdmin(status *bool, page PageRequest) ([]model.Notice, int64, error) {
+	query := r.db.Model(&model.Notice{})
 	if status != nil {
 		query = query.Where("status = ?", *status)
 	}
-	var notices []model.Notice
-	if err := query.Find(&notices).Error; err != nil {
-		return nil, errors.Wrap(err, "list notices")
+	notices, total, err := findPage[model.Notice](query, page)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search notices")
 	}
-	return notices, nil
+	return notices, total, nil
 }
 
 // FindLatestActive returns the latest active notice by time window.
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// NotificationRepository handles in-app notification persistence.
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates notification repo.
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create inserts a notification.
+func (r *NotificationRepository) Create(notification *model.Notification) error {
+	if err := r.db.Omit("Actor").Create(notification).Error; err != nil {
+		return errors.Wrap(err, "create notification")
+	}
+	return nil
+}
+
+// FeedByUser reads a user's notifications, newest first, optionally only the unread ones.
+func (r *NotificationRepository) FeedByUser(userID uint, unreadOnly bool, cursor CursorRequest) ([]model.Notification, int64, bool, error) {
+	query := r.db.Model(&model.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	notifications, total, more, err := findFeed[model.Notification](query, "notifications", cursor, preload("Actor"))
+	if err != nil {
+		return nil, 0, false, errors.Wrap(err, "feed notifications")
+	}
+	return notifications, total, more, nil
+}
+
+// CountUnread counts a user's unread notifications.
+func (r *NotificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return 0, errors.Wrap(err, "count unread notifications")
+	}
+	return count, nil
+}
+
+// MarkRead marks one of a user's notifications as read, returning gorm.ErrRecordNotFound when the
+// user has no such notification.
+func (r *NotificationRepository) MarkRead(userID, id uint, at time.Time) error {
+	var notification model.Notification
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		return errors.Wrap(err, "find notification")
+	}
+	if notification.ReadAt != nil {
+		return nil
+	}
+	if err := r.db.Model(&notification).UpdateColumn("read_at", at).Error; err != nil {
+		return errors.Wrap(err, "mark notification read")
+	}
+	return nil
+}
+
+// MarkAllRead marks all of a user's unread notifications as read and returns how many changed.
+func (r *NotificationRepository) MarkAllRead(userID uint, at time.Time) (int64, error) {
+	res := r.db.Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		UpdateColumn("read_at", at)
+	if res.Error != nil {
+		return 0, errors.Wrap(res.Error, "mark all notifications read")
+	}
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SortFields whitelists the fields a list can be sorted by, mapping the API field name to its
+// column. Every whitelist has an "id" entry, used to break ties so that pages stay stable.
+type SortFields map[string]string
+
+// OrderBy builds the ORDER BY clause for a whitelisted field, or reports false for any other.
+func (f SortFields) OrderBy(field string, desc bool) (string, bool) {
+	column, ok := f[field]
+	if !ok {
+		return "", false
+	}
+	dir := " ASC"
+	if desc {
+		dir = " DESC"
+	}
+	order := column + dir
+	if field != "id" {
+		order += ", " + f["id"] + dir
+	}
+	return order, true
+}
+
+// PageRequest is an offset page with a validated ORDER BY clause.
+type PageRequest struct {
+	Page     int
+	PageSize int
+	Order    string
+}
+
+// CursorRequest reads a feed newest first. When After is set, only rows older than the last row
+// already returned, identified by its creation time and ID, are read.
+type CursorRequest struct {
+	AfterTime time.Time
+	AfterID   uint
+	After     bool
+	Limit     int
+}
+
+// findPage counts the rows matched by query and loads the requested page of them. Scopes such as
+// preloads apply to the page query only.
+func findPage[T any](query *gorm.DB, page PageRequest, scopes ...func(*gorm.DB) *gorm.DB) ([]T, int64, error) {
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	items := []T{}
+	if total == 0 {
+		return items, 0, nil
+	}
+	err := query.Scopes(scopes...).
+		Order(page.Order).
+		Offset((page.Page - 1) * page.PageSize).
+		Limit(page.PageSize).
+		Find(&items).Error
+	return items, total, err
+}
+
+// findFeed counts the rows matched by query and loads the next feed page, ordered by created_at
+// and id descending. One extra row is fetched to tell whether more rows follow.
+func findFeed[T any](query *gorm.DB, table string, cursor CursorRequest, scopes ...func(*gorm.DB) *gorm.DB) ([]T, int64, bool, error) {
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, false, err
+	}
+	items := []T{}
+	if total == 0 {
+		return items, 0, false, nil
+	}
+	if cursor.After {
+		query = query.Where("("+table+".created_at < ? OR ("+table+".created_at = ? AND "+table+".id < ?))",
+			cursor.AfterTime, cursor.AfterTime, cursor.AfterID)
+	}
+	if err := query.Scopes(scopes...).
+		Order(table + ".created_at DESC, " + table + ".id DESC").
+		Limit(cursor.Limit + 1).
+		Find(&items).Error; err != nil {
+		return nil, 0, false, err
+	}
+	if len(items) > cursor.Limit {
+		return items[:cursor.Limit], total, true, nil
+	}
+	return items, total, false, nil
+}
+
+// preload returns a scope that preloads the named association.
+func preload(name string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Preload(name)
+	}
+}
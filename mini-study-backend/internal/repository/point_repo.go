@@ -33,8 +33,10 @@ type UserPointWithUser struct {
 }
 
 // AddTransaction increments user points and records a transaction atomically.
-func (r *PointRepository) AddTransaction(txn *model.PointTransaction) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+// It returns the balance after the change.
+func (r *PointRepository) AddTransaction(txn *model.PointTransaction) (int64, error) {
+	var total int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
 		var balance model.UserPoint
 		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
 			Where("user_id = ?", txn.UserID).
@@ -58,8 +60,10 @@ func (r *PointRepository) AddTransaction(txn *model.PointTransaction) error {
 		if err := tx.Create(txn).Error; err != nil {
 			return errors.Wrap(err, "create point transaction")
 		}
+		total = balance.Total
 		return nil
 	})
+	return total, err
 }
 
 // ExistsByReference checks whether a transaction with the same reference already exists.
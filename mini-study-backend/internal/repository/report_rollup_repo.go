@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// ReportRollupRepository reads the raw rows behind the reporting rollups and replaces the
+// rollup tables. Rollup rows are rebuilt wholesale per day or per user, so every write deletes
+// the old rows and inserts the new ones in one transaction.
+type ReportRollupRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRollupRepository creates a ReportRollupRepository.
+func NewReportRollupRepository(db *gorm.DB) *ReportRollupRepository {
+	return &ReportRollupRepository{db: db}
+}
+
+// RollupLearningRow is a learning record touched or completed within a range.
+type RollupLearningRow struct {
+	UserID      uint
+	ContentID   uint
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// RollupExamRow is a submitted exam attempt, with the title of its exam when loaded for the
+// user summaries.
+type RollupExamRow struct {
+	UserID      uint
+	ExamID      uint
+	ExamTitle   string
+	Score       int
+	Pass        bool
+	CreatedAt   time.Time
+	SubmittedAt *time.Time
+}
+
+// ExamRollupRow holds the summed results of one exam over a group of users.
+type ExamRollupRow struct {
+	ExamID    uint
+	ExamTitle string
+	Attempts  int64
+	Passed    int64
+	ScoreSum  int64
+}
+
+// LearningActivity returns the learning records last updated or completed within [from, to).
+func (r *ReportRollupRepository) LearningActivity(from, to time.Time) ([]RollupLearningRow, error) {
+	var rows []RollupLearningRow
+	if err := r.db.Model(&model.LearningRecord{}).
+		Select("user_id, content_id, updated_at, completed_at").
+		Where("(updated_at >= ? AND updated_at < ?) OR (completed_at >= ? AND completed_at < ?)", from, to, from, to).
+		Scan(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, "list learning activity")
+	}
+	return rows, nil
+}
+
+// ExamSubmissions returns the exam attempts submitted within [from, to).
+func (r *ReportRollupRepository) ExamSubmissions(from, to time.Time) ([]RollupExamRow, error) {
+	var rows []RollupExamRow
+	if err := r.db.Model(&model.ExamAttempt{}).
+		Select("user_id, exam_id, score, pass, created_at, submitted_at").
+		Where("submitted_at >= ? AND submitted_at < ?", from, to).
+		Scan(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, "list exam submissions")
+	}
+	return rows, nil
+}
+
+// ExamAttemptsByUsers returns the attempts of the given users on exams that still exist,
+// newest first.
+func (r *ReportRollupRepository) ExamAttemptsByUsers(userIDs []uint) ([]RollupExamRow, error) {
+	if len(userIDs) == 0 {
+		return []RollupExamRow{}, nil
+	}
+	var rows []RollupExamRow
+	if err := r.db.Model(&model.ExamAttempt{}).
+		Select("exam_attempts.user_id, exam_attempts.exam_id, exam_papers.title AS exam_title, exam_attempts.score, exam_attempts.pass, exam_attempts.created_at, exam_attempts.submitted_at").
+		Joins("JOIN exam_papers ON exam_papers.id = exam_attempts.exam_id AND exam_papers.deleted_at IS NULL").
+		Where("exam_attempts.user_id IN ?", userIDs).
+		Order("exam_attempts.created_at DESC, exam_attempts.id DESC").
+		Scan(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, "list exam attempts for summaries")
+	}
+	return rows, nil
+}
+
+// ChangedUserIDs returns the users whose learning records or exam attempts changed since the
+// given time, including users who attempted an exam edited or deleted since then.
+func (r *ReportRollupRepository) ChangedUserIDs(since time.Time) ([]uint, error) {
+	seen := make(map[uint]struct{})
+	var ids []uint
+	collect := func(query *gorm.DB, what string) error {
+		var batch []uint
+		if err := query.Distinct().Pluck("user_id", &batch).Error; err != nil {
+			return errors.Wrap(err, what)
+		}
+		for _, id := range batch {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+		return nil
+	}
+
+	if err := collect(r.db.Unscoped().Model(&model.LearningRecord{}).
+		Where("updated_at >= ? OR deleted_at >= ?", since, since), "list changed learners"); err != nil {
+		return nil, err
+	}
+	if err := collect(r.db.Unscoped().Model(&model.ExamAttempt{}).
+		Where("updated_at >= ? OR deleted_at >= ?", since, since), "list changed examinees"); err != nil {
+		return nil, err
+	}
+	if err := collect(r.db.Model(&model.ExamAttempt{}).
+		Where("exam_id IN (?)", r.db.Unscoped().Model(&model.ExamPaper{}).Select("id").
+			Where("updated_at >= ? OR deleted_at >= ?", since, since)), "list examinees of changed exams"); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ListUserIDs returns the IDs of every user after afterID in ascending order, up to limit.
+func (r *ReportRollupRepository) ListUserIDs(afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	if err := r.db.Model(&model.User{}).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, errors.Wrap(err, "list user ids")
+	}
+	return ids, nil
+}
+
+// ReplaceDays swaps the daily rollups of [from, to) for the given rows.
+func (r *ReportRollupRepository) ReplaceDays(from, to time.Time, users []model.UserDailyStat, contents []model.ContentDailyStat, exams []model.ExamDailyStat) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range []interface{}{&model.UserDailyStat{}, &model.ContentDailyStat{}, &model.ExamDailyStat{}} {
+			if err := tx.Unscoped().Where("stat_date >= ? AND stat_date < ?", from, to).Delete(table).Error; err != nil {
+				return errors.Wrap(err, "delete daily rollups")
+			}
+		}
+		if len(users) > 0 {
+			if err := tx.CreateInBatches(users, 500).Error; err != nil {
+				return errors.Wrap(err, "create user daily rollups")
+			}
+		}
+		if len(contents) > 0 {
+			if err := tx.CreateInBatches(contents, 500).Error; err != nil {
+				return errors.Wrap(err, "create content daily rollups")
+			}
+		}
+		if len(exams) > 0 {
+			if err := tx.CreateInBatches(exams, 500).Error; err != nil {
+				return errors.Wrap(err, "create exam daily rollups")
+			}
+		}
+		return nil
+	})
+}
+
+// ReplaceSummaries swaps the summaries of the given users for the given rows.
+func (r *ReportRollupRepository) ReplaceSummaries(userIDs []uint, summaries []model.UserReportSummary, exams []model.UserExamSummary) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id IN ?", userIDs).Delete(&model.UserReportSummary{}).Error; err != nil {
+			return errors.Wrap(err, "delete user summaries")
+		}
+		if err := tx.Unscoped().Where("user_id IN ?", userIDs).Delete(&model.UserExamSummary{}).Error; err != nil {
+			return errors.Wrap(err, "delete user exam summaries")
+		}
+		if len(summaries) > 0 {
+			if err := tx.CreateInBatches(summaries, 500).Error; err != nil {
+				return errors.Wrap(err, "create user summaries")
+			}
+		}
+		if len(exams) > 0 {
+			if err := tx.CreateInBatches(exams, 500).Error; err != nil {
+				return errors.Wrap(err, "create user exam summaries")
+			}
+		}
+		return nil
+	})
+}
+
+// SummariesByUsers returns the summaries of the given users keyed by user ID. Users without
+// any learning or exam activity have no summary.
+func (r *ReportRollupRepository) SummariesByUsers(userIDs []uint) (map[uint]model.UserReportSummary, error) {
+	result := make(map[uint]model.UserReportSummary, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+	var summaries []model.UserReportSummary
+	if err := r.db.Where("user_id IN ?", userIDs).Find(&summaries).Error; err != nil {
+		return nil, errors.Wrap(err, "list user summaries")
+	}
+	for _, summary := range summaries {
+		result[summary.UserID] = summary
+	}
+	return result, nil
+}
+
+// ExamStatsForUsers sums the exam summaries of the given users per exam.
+func (r *ReportRollupRepository) ExamStatsForUsers(userIDs []uint) ([]ExamRollupRow, error) {
+	if len(userIDs) == 0 {
+		return []ExamRollupRow{}, nil
+	}
+	var rows []ExamRollupRow
+	if err := r.db.Model(&model.UserExamSummary{}).
+		Select("exam_id, MAX(exam_title) AS exam_title, SUM(attempts) AS attempts, SUM(passed) AS passed, SUM(score_sum) AS score_sum").
+		Where("user_id IN ?", userIDs).
+		Group("exam_id").
+		Scan(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, "aggregate exam summaries")
+	}
+	return rows, nil
+}
+
+// State returns the watermark of the named rollup task, or the zero time if it never ran.
+func (r *ReportRollupRepository) State(name string) (time.Time, error) {
+	var state model.ReportRollupState
+	if err := r.db.Where("name = ?", name).First(&state).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, errors.Wrap(err, "find rollup state")
+	}
+	return state.RefreshedUntil, nil
+}
+
+// SaveState moves the watermark of the named rollup task.
+func (r *ReportRollupRepository) SaveState(name string, until time.Time) error {
+	state := model.ReportRollupState{Name: name}
+	if err := r.db.Where("name = ?", name).FirstOrCreate(&state).Error; err != nil {
+		return errors.Wrap(err, "find rollup state")
+	}
+	if err := r.db.Model(&state).Update("refreshed_until", until).Error; err != nil {
+		return errors.Wrap(err, "update rollup state")
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// ReportScheduleRepository persists report delivery schedules.
+type ReportScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewReportScheduleRepository creates a ReportScheduleRepository.
+func NewReportScheduleRepository(db *gorm.DB) *ReportScheduleRepository {
+	return &ReportScheduleRepository{db: db}
+}
+
+// Create inserts a schedule.
+func (r *ReportScheduleRepository) Create(schedule *model.ReportSchedule) error {
+	if err := r.db.Create(schedule).Error; err != nil {
+		return errors.Wrap(err, "create report schedule")
+	}
+	return nil
+}
+
+// Update saves every field of a schedule.
+func (r *ReportScheduleRepository) Update(schedule *model.ReportSchedule) error {
+	if err := r.db.Save(schedule).Error; err != nil {
+		return errors.Wrap(err, "update report schedule")
+	}
+	return nil
+}
+
+// Delete soft-deletes a schedule.
+func (r *ReportScheduleRepository) Delete(id uint) error {
+	if err := r.db.Delete(&model.ReportSchedule{}, id).Error; err != nil {
+		return errors.Wrap(err, "delete report schedule")
+	}
+	return nil
+}
+
+// FindByID loads a schedule.
+func (r *ReportScheduleRepository) FindByID(id uint) (*model.ReportSchedule, error) {
+	var schedule model.ReportSchedule
+	if err := r.db.First(&schedule, id).Error; err != nil {
+		return nil, errors.Wrap(err, "find report schedule")
+	}
+	return &schedule, nil
+}
+
+// List returns the schedules created by ownerID, or every schedule when ownerID is 0.
+func (r *ReportScheduleRepository) List(ownerID uint) ([]model.ReportSchedule, error) {
+	query := r.db.Order("id asc")
+	if ownerID > 0 {
+		query = query.Where("owner_id = ?", ownerID)
+	}
+	var schedules []model.ReportSchedule
+	if err := query.Find(&schedules).Error; err != nil {
+		return nil, errors.Wrap(err, "list report schedules")
+	}
+	return schedules, nil
+}
+
+// ListDue returns enabled schedules whose next run is at or before now.
+func (r *ReportScheduleRepository) ListDue(now time.Time, limit int) ([]model.ReportSchedule, error) {
+	var schedules []model.ReportSchedule
+	if err := r.db.Where("enabled = ? AND next_run_at <= ?", true, now).
+		Order("next_run_at asc").Limit(limit).Find(&schedules).Error; err != nil {
+		return nil, errors.Wrap(err, "list due report schedules")
+	}
+	return schedules, nil
+}
+
+// Claim moves a due schedule to its next run, only if it is still due at dueAt. It returns false
+// when another replica claimed it first or the schedule was edited meanwhile.
+func (r *ReportScheduleRepository) Claim(id uint, dueAt, next time.Time) (bool, error) {
+	res := r.db.Model(&model.ReportSchedule{}).
+		Where("id = ? AND enabled = ? AND next_run_at = ?", id, true, dueAt).
+		Update("next_run_at", next)
+	if res.Error != nil {
+		return false, errors.Wrap(res.Error, "claim report schedule")
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// Reschedule moves a claimed schedule from claimedNext to runAt and records its consecutive
+// failed deliveries, unless the schedule was edited meanwhile.
+func (r *ReportScheduleRepository) Reschedule(id uint, claimedNext, runAt time.Time, failures int) error {
+	if err := r.db.Model(&model.ReportSchedule{}).
+		Where("id = ? AND next_run_at = ?", id, claimedNext).
+		Updates(map[string]interface{}{
+			"next_run_at":     runAt,
+			"failed_attempts": failures,
+		}).Error; err != nil {
+		return errors.Wrap(err, "reschedule report schedule")
+	}
+	return nil
+}
+
+// SaveResult records the outcome of a delivery.
+func (r *ReportScheduleRepository) SaveResult(id uint, at time.Time, status, message string) error {
+	if err := r.db.Model(&model.ReportSchedule{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_run_at": at,
+		"last_status": status,
+		"last_error":  message,
+	}).Error; err != nil {
+		return errors.Wrap(err, "save report delivery result")
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// ReviewEventRepository 审核流转记录仓储。
+type ReviewEventRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewEventRepository 创建审核流转记录仓库实例。
+func NewReviewEventRepository(db *gorm.DB) *ReviewEventRepository {
+	return &ReviewEventRepository{db: db}
+}
+
+// Create 新增一条流转记录。
+func (r *ReviewEventRepository) Create(event *model.ReviewEvent) error {
+	if err := r.db.Create(event).Error; err != nil {
+		return errors.Wrap(err, "create review event")
+	}
+	return nil
+}
+
+// ListByEntity 查询实体的全部流转记录，按时间先后排序。
+func (r *ReviewEventRepository) ListByEntity(entityType string, entityID uint) ([]model.ReviewEvent, error) {
+	var events []model.ReviewEvent
+	if err := r.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("id asc").Find(&events).Error; err != nil {
+		return nil, errors.Wrap(err, "list review events")
+	}
+	return events, nil
+}
+
+// ReviewQueueEntry 审核列表中的一项，EntityType 为 content 或 exam。
+type ReviewQueueEntry struct {
+	EntityType  string
+	EntityID    uint
+	SubmittedAt *time.Time
+}
+
+// ReviewQueueSortFields 审核列表可排序字段。
+var ReviewQueueSortFields = SortFields{
+	"id":           "entity_id",
+	"submitted_at": "submitted_at",
+	"updated_at":   "updated_at",
+	"title":        "title",
+}
+
+// SearchQueue 按审核状态分页查询内容与试卷，entityType 为空时两者合并排序；reviewerID 大于 0 时只返回指定给该审核人的条目。
+func (r *ReviewEventRepository) SearchQueue(entityType, status string, reviewerID uint, page PageRequest) ([]ReviewQueueEntry, int64, error) {
+	queue := func(model interface{}, label string) *gorm.DB {
+		query := r.db.Model(model).
+			Select("? AS entity_type, id AS entity_id, title, submitted_at, updated_at", label).
+			Where("status = ?", status)
+		if reviewerID > 0 {
+			query = query.Where("reviewer_id = ?", reviewerID)
+		}
+		return query
+	}
+
+	var source *gorm.DB
+	switch entityType {
+	case "content":
+		source = queue(&model.Content{}, "content")
+	case "exam":
+		source = queue(&model.ExamPaper{}, "exam")
+	default:
+		source = r.db.Raw("? UNION ALL ?", queue(&model.Content{}, "content"), queue(&model.ExamPaper{}, "exam"))
+	}
+
+	entries, total, err := findPage[ReviewQueueEntry](r.db.Table("(?) AS review_queue", source), page)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search review queue")
+	}
+	return entries, total, nil
+}
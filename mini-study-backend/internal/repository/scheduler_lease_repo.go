@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// SchedulerLeaseRepository stores leases that elect one replica to run a background task.
+type SchedulerLeaseRepository struct {
+	db *gorm.DB
+}
+
+// NewSchedulerLeaseRepository creates a SchedulerLeaseRepository.
+func NewSchedulerLeaseRepository(db *gorm.DB) *SchedulerLeaseRepository {
+	return &SchedulerLeaseRepository{db: db}
+}
+
+// Acquire takes or renews the named lease for holder until leaseUntil. It succeeds when the
+// lease is held by holder already, has expired, or does not exist yet.
+func (r *SchedulerLeaseRepository) Acquire(name, holder string, now, leaseUntil time.Time) (bool, error) {
+	res := r.db.Model(&model.SchedulerLease{}).
+		Where("name = ? AND (holder = ? OR lease_until < ?)", name, holder, now).
+		Updates(map[string]interface{}{"holder": holder, "lease_until": leaseUntil})
+	if res.Error != nil {
+		return false, errors.Wrap(res.Error, "renew scheduler lease")
+	}
+	if res.RowsAffected == 1 {
+		return true, nil
+	}
+
+	var count int64
+	if err := r.db.Model(&model.SchedulerLease{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return false, errors.Wrap(err, "find scheduler lease")
+	}
+	if count > 0 {
+		return false, nil
+	}
+	// 首次运行时创建租约；多个实例同时创建时由唯一索引保证只有一个成功
+	if err := r.db.Create(&model.SchedulerLease{Name: name, Holder: holder, LeaseUntil: leaseUntil}).Error; err != nil {
+		if r.db.Model(&model.SchedulerLease{}).Where("name = ?", name).Count(&count).Error == nil && count > 0 {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "create scheduler lease")
+	}
+	return true, nil
+}
+
+// Release gives up the lease if holder still owns it, so another replica can take over at once.
+func (r *SchedulerLeaseRepository) Release(name, holder string) error {
+	if err := r.db.Model(&model.SchedulerLease{}).
+		Where("name = ? AND holder = ?", name, holder).
+		Update("lease_until", time.Unix(0, 0)).Error; err != nil {
+		return errors.Wrap(err, "release scheduler lease")
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// SensitiveWordRepository handles the moderation dictionary.
+type SensitiveWordRepository struct {
+	db *gorm.DB
+}
+
+// NewSensitiveWordRepository creates sensitive word repo.
+func NewSensitiveWordRepository(db *gorm.DB) *SensitiveWordRepository {
+	return &SensitiveWordRepository{db: db}
+}
+
+// Create inserts a word.
+func (r *SensitiveWordRepository) Create(word *model.SensitiveWord) error {
+	if err := r.db.Create(word).Error; err != nil {
+		return errors.Wrap(err, "create sensitive word")
+	}
+	return nil
+}
+
+// Update saves a word.
+func (r *SensitiveWordRepository) Update(word *model.SensitiveWord) error {
+	if err := r.db.Save(word).Error; err != nil {
+		return errors.Wrap(err, "update sensitive word")
+	}
+	return nil
+}
+
+// Delete removes a word for good, so that it can be added again later despite the unique index.
+func (r *SensitiveWordRepository) Delete(word *model.SensitiveWord) error {
+	if err := r.db.Unscoped().Delete(word).Error; err != nil {
+		return errors.Wrap(err, "delete sensitive word")
+	}
+	return nil
+}
+
+// FindByID finds a word by id.
+func (r *SensitiveWordRepository) FindByID(id uint) (*model.SensitiveWord, error) {
+	var word model.SensitiveWord
+	if err := r.db.First(&word, id).Error; err != nil {
+		return nil, errors.Wrap(err, "find sensitive word")
+	}
+	return &word, nil
+}
+
+// ExistsWord reports whether another word than excludeID has the given text.
+func (r *SensitiveWordRepository) ExistsWord(text string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&model.SensitiveWord{}).Where("word = ?", text)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, errors.Wrap(err, "count sensitive word")
+	}
+	return count > 0, nil
+}
+
+// ListAll loads the whole dictionary, for building the matcher.
+func (r *SensitiveWordRepository) ListAll() ([]model.SensitiveWord, error) {
+	var words []model.SensitiveWord
+	if err := r.db.Order("id asc").Find(&words).Error; err != nil {
+		return nil, errors.Wrap(err, "list sensitive words")
+	}
+	return words, nil
+}
+
+// SensitiveWordSortFields lists the fields the dictionary can be sorted by.
+var SensitiveWordSortFields = SortFields{
+	"id":         "id",
+	"word":       "word",
+	"category":   "category",
+	"level":      "level",
+	"created_at": "created_at",
+}
+
+// Search returns a page of words filtered by keyword, category and level.
+func (r *SensitiveWordRepository) Search(keyword, category, level string, page PageRequest) ([]model.SensitiveWord, int64, error) {
+	query := r.db.Model(&model.SensitiveWord{})
+	if keyword != "" {
+		query = query.Where("word LIKE ?", "%"+keyword+"%")
+	}
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if level != "" {
+		query = query.Where("level = ?", level)
+	}
+	words, total, err := findPage[model.SensitiveWord](query, page)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search sensitive words")
+	}
+	return words, total, nil
+}
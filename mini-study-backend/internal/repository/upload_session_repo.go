@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// UploadSessionRepository 分片上传会话仓储。
+type UploadSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewUploadSessionRepository 创建上传会话仓库实例。
+func NewUploadSessionRepository(db *gorm.DB) *UploadSessionRepository {
+	return &UploadSessionRepository{db: db}
+}
+
+// Create 新建上传会话。
+func (r *UploadSessionRepository) Create(session *model.UploadSession) error {
+	if err := r.db.Create(session).Error; err != nil {
+		return errors.Wrap(err, "create upload session")
+	}
+	return nil
+}
+
+// FindByUploadID 按会话ID查询。
+func (r *UploadSessionRepository) FindByUploadID(uploadID string) (*model.UploadSession, error) {
+	var session model.UploadSession
+	if err := r.db.Where("upload_id = ?", uploadID).First(&session).Error; err != nil {
+		return nil, errors.Wrap(err, "find upload session")
+	}
+	return &session, nil
+}
+
+// Claim 认领会话：仅当会话未被认领或认领已过期时写入 token，返回是否认领成功。
+// 认领者在处理完后调用 ReleaseClaim，进程异常退出时认领在 until 后失效。
+func (r *UploadSessionRepository) Claim(id uint, token string, now, until time.Time) (bool, error) {
+	res := r.db.Model(&model.UploadSession{}).
+		Where("id = ? AND (claimed_until IS NULL OR claimed_until < ?)", id, now).
+		Updates(map[string]interface{}{"claim_token": token, "claimed_until": until})
+	if res.Error != nil {
+		return false, errors.Wrap(res.Error, "claim upload session")
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// ReleaseClaim 释放仍由 token 持有的认领。
+func (r *UploadSessionRepository) ReleaseClaim(id uint, token string) error {
+	if err := r.db.Model(&model.UploadSession{}).
+		Where("id = ? AND claim_token = ?", id, token).
+		Updates(map[string]interface{}{"claim_token": "", "claimed_until": nil}).Error; err != nil {
+		return errors.Wrap(err, "release upload session claim")
+	}
+	return nil
+}
+
+// AdvanceOffset 仅当认领仍由 token 持有且偏移量仍为 from 时推进到 to，防止并发分片重复写入。
+func (r *UploadSessionRepository) AdvanceOffset(id uint, token string, from, to int64, expiresAt time.Time) (bool, error) {
+	res := r.db.Model(&model.UploadSession{}).
+		Where("id = ? AND claim_token = ? AND status = ? AND received_bytes = ?", id, token, model.UploadSessionUploading, from).
+		Updates(map[string]interface{}{"received_bytes": to, "expires_at": expiresAt})
+	if res.Error != nil {
+		return false, errors.Wrap(res.Error, "advance upload session offset")
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// MarkAborted 仅当认领仍由 token 持有且会话仍在上传中时标记为已取消，返回是否实际更新。
+func (r *UploadSessionRepository) MarkAborted(id uint, token string) (bool, error) {
+	res := r.db.Model(&model.UploadSession{}).
+		Where("id = ? AND claim_token = ? AND status = ?", id, token, model.UploadSessionUploading).
+		Update("status", model.UploadSessionAborted)
+	if res.Error != nil {
+		return false, errors.Wrap(res.Error, "mark upload session aborted")
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// ListExpired 返回已过期但仍处于上传中的会话。
+func (r *UploadSessionRepository) ListExpired(now time.Time, limit int) ([]model.UploadSession, error) {
+	var sessions []model.UploadSession
+	if err := r.db.Where("status = ? AND expires_at < ?", model.UploadSessionUploading, now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&sessions).Error; err != nil {
+		return nil, errors.Wrap(err, "list expired upload sessions")
+	}
+	return sessions, nil
+}
+
+// MarkExpired 仅当认领仍由 token 持有、会话仍在上传中且已过期时标记为已过期，返回是否实际更新；
+// 期间被续传延长或已完成的会话不受影响。
+func (r *UploadSessionRepository) MarkExpired(id uint, token string, now time.Time) (bool, error) {
+	res := r.db.Model(&model.UploadSession{}).
+		Where("id = ? AND claim_token = ? AND status = ? AND expires_at < ?", id, token, model.UploadSessionUploading, now).
+		Update("status", model.UploadSessionExpired)
+	if res.Error != nil {
+		return false, errors.Wrap(res.Error, "mark upload session expired")
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// MarkCompleted 仅当认领仍由 token 持有且会话仍在上传中时标记为已完成，返回是否实际更新。
+func (r *UploadSessionRepository) MarkCompleted(id uint, token, key string, completedAt time.Time) (bool, error) {
+	res := r.db.Model(&model.UploadSession{}).
+		Where("id = ? AND claim_token = ? AND status = ?", id, token, model.UploadSessionUploading).
+		Updates(map[string]interface{}{"status": model.UploadSessionCompleted, "key": key, "completed_at": completedAt})
+	if res.Error != nil {
+		return false, errors.Wrap(res.Error, "mark upload session completed")
+	}
+	return res.RowsAffected == 1, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/javapub/mini-study/mini-study-backend/internal/model"
 )
@@ -17,6 +18,17 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+// WithTx 返回在事务 tx 中执行的仓库。
+func (r *UserRepository) WithTx(tx *gorm.DB) *UserRepository {
+	return &UserRepository{db: tx}
+}
+
+// Transaction 在同一个数据库事务中执行 fn，fn 返回错误时回滚。
+// 各仓库通过 WithTx(tx) 加入该事务。
+func (r *UserRepository) Transaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
 // Create 新增用户记录。
 func (r *UserRepository) Create(user *model.User) error {
 	if err := r.db.Create(user).Error; err != nil {
@@ -42,13 +54,14 @@ func (r *UserRepository) FindByWorkNo(workNo string) (*model.User, error) {
 	return &user, nil
 }
 
-// ListManagers 获取所有启用中的店长。
-func (r *UserRepository) ListManagers() ([]model.User, error) {
-	var users []model.User
-	if err := r.db.Where("role = ?", model.RoleManager).Where("status = ?", true).Find(&users).Error; err != nil {
-		return nil, errors.Wrap(err, "list managers")
+// SearchManagers 分页查询启用中的店长。
+func (r *UserRepository) SearchManagers(page PageRequest) ([]model.User, int64, error) {
+	query := r.db.Model(&model.User{}).Where("role = ? AND status = ?", model.RoleManager, true)
+	users, total, err := findPage[model.User](query, page)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search managers")
 	}
-	return users, nil
+	return users, total, nil
 }
 
 // FindByID 通过主键 ID 查询用户。
@@ -60,7 +73,43 @@ func (r *UserRepository) FindByID(id uint) (*model.User, error) {
 	return &user, nil
 }
 
-// ListUsers 按条件查询用户列表。
+// LockByID 在事务中查询并锁定用户行，避免与禁用等状态变更并发执行。
+func (r *UserRepository) LockByID(id uint) (*model.User, error) {
+	var user model.User
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, id).Error; err != nil {
+		return nil, errors.Wrap(err, "lock user by id")
+	}
+	return &user, nil
+}
+
+// UserSortFields 用户列表可排序字段。
+var UserSortFields = SortFields{
+	"id":         "id",
+	"work_no":    "work_no",
+	"name":       "name",
+	"role":       "role",
+	"created_at": "created_at",
+}
+
+// SearchUsers 按条件分页查询用户。
+func (r *UserRepository) SearchUsers(role, keyword string, page PageRequest) ([]model.User, int64, error) {
+	query := r.db.Model(&model.User{})
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		query = query.Where("work_no LIKE ? OR name LIKE ? OR phone LIKE ?", like, like, like)
+	}
+
+	users, total, err := findPage[model.User](query, page)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search users")
+	}
+	return users, total, nil
+}
+
+// ListUsers 按条件查询全部用户，用于统计等需要完整名单的场景。
 func (r *UserRepository) ListUsers(role, keyword string) ([]model.User, error) {
 	var users []model.User
 	query := r.db.Model(&model.User{})
@@ -21,6 +21,19 @@ func RegisterRoutes(
 	systemHandler *handler.SystemHandler,
 	pointHandler *handler.PointHandler,
 	growthHandler *handler.GrowthHandler,
+	auditHandler *handler.AuditHandler,
+	mediaHandler *handler.MediaHandler,
+	searchHandler *handler.SearchHandler,
+	scheduleHandler *handler.ScheduleHandler,
+	reviewHandler *handler.ReviewHandler,
+	checkpointHandler *handler.CheckpointHandler,
+	feedbackHandler *handler.ContentFeedbackHandler,
+	notificationHandler *handler.NotificationHandler,
+	moderationHandler *handler.ModerationHandler,
+	analyticsHandler *handler.AnalyticsHandler,
+	reportHandler *handler.ReportHandler,
+	certificateHandler *handler.CertificateHandler,
+	certificationHandler *handler.CertificationHandler,
 ) {
 	api := engine.Group("/api/v1")
 
@@ -47,6 +60,16 @@ func RegisterRoutes(
 		content.GET("/categories", contentHandler.ListCategories)
 		content.GET("/", contentHandler.ListPublishedContents)
 		content.GET("/:id", contentHandler.GetContentDetail)
+		content.GET("/:id/file", contentHandler.GetContentFile)
+		content.GET("/:id/pages", contentHandler.ListContentPages)
+		content.POST("/:id/blocks/:index/answer", contentHandler.AnswerArticleQuiz)
+		content.GET("/:id/checkpoints", checkpointHandler.ListCheckpoints)
+		content.POST("/:id/checkpoints/:checkpoint_id/answer", checkpointHandler.AnswerCheckpoint)
+		content.PUT("/:id/rating", feedbackHandler.RateContent)
+		content.GET("/:id/reviews", feedbackHandler.ListReviews)
+		content.GET("/:id/comments", feedbackHandler.ListComments)
+		content.POST("/:id/comments", feedbackHandler.CreateComment)
+		content.DELETE("/:id/comments/:comment_id", feedbackHandler.DeleteComment)
 	}
 
 	// Growth circle routes (need auth)
@@ -55,8 +78,34 @@ func RegisterRoutes(
 	{
 		growth.GET("/", growthHandler.ListPublicPosts)
 		growth.GET("/mine", growthHandler.ListMyPosts)
+		growth.GET("/topics/trending", growthHandler.TrendingTopics)
 		growth.POST("/", growthHandler.CreatePost)
+		growth.PUT("/:id", growthHandler.ResubmitPost)
 		growth.DELETE("/:id", growthHandler.DeletePost)
+		growth.GET("/:id/history", growthHandler.PostHistory)
+		growth.POST("/:id/like", growthHandler.LikePost)
+		growth.DELETE("/:id/like", growthHandler.UnlikePost)
+		growth.GET("/:id/likes", growthHandler.ListLikers)
+		growth.POST("/:id/share", growthHandler.SharePost)
+		growth.GET("/:id/comments", growthHandler.ListComments)
+		growth.POST("/:id/comments", growthHandler.CreateComment)
+		growth.DELETE("/:id/comments/:comment_id", growthHandler.DeleteComment)
+	}
+
+	// Notification routes
+	notifications := api.Group("/notifications")
+	notifications.Use(authMiddleware)
+	{
+		notifications.GET("/", notificationHandler.ListNotifications)
+		notifications.POST("/read-all", notificationHandler.MarkAllRead)
+		notifications.POST("/:id/read", notificationHandler.MarkRead)
+	}
+
+	// Search routes
+	searchGroup := api.Group("/search")
+	searchGroup.Use(authMiddleware)
+	{
+		searchGroup.GET("/", searchHandler.Search)
 	}
 
 	// Learning routes
@@ -84,6 +133,33 @@ func RegisterRoutes(
 	manager.Use(authMiddleware)
 	{
 		manager.GET("/exams/overview", examHandler.ManagerOverview)
+		manager.GET("/certifications/expiring", certificationHandler.ManagerExpiring)
+	}
+
+	// Report routes (managers and admins)
+	reports := api.Group("/reports")
+	reports.Use(authMiddleware)
+	{
+		reports.GET("/definitions", reportHandler.Definitions)
+		reports.GET("/schedules", reportHandler.ListSchedules)
+		reports.POST("/schedules", reportHandler.CreateSchedule)
+		reports.PUT("/schedules/:id", reportHandler.UpdateSchedule)
+		reports.DELETE("/schedules/:id", reportHandler.DeleteSchedule)
+		reports.POST("/schedules/:id/send", reportHandler.SendSchedule)
+		reports.GET("/:report/export", reportHandler.Export)
+	}
+
+	certificates := api.Group("/certificates")
+	// 证书验证面向持证人以外的第三方，无需登录
+	certificates.GET("/verify", certificateHandler.Verify)
+	certificates.Use(authMiddleware)
+	certificates.GET("/my", certificateHandler.ListMine)
+	certificates.GET("/attempts/:attempt_id/download", certificateHandler.Download)
+
+	certifications := api.Group("/certifications")
+	certifications.Use(authMiddleware)
+	{
+		certifications.GET("/my", certificationHandler.ListMine)
 	}
 
 	// Banner routes
@@ -111,6 +187,11 @@ func RegisterRoutes(
 		admin.POST("/employees", userHandler.AdminCreateEmployee)
 		admin.POST("/users/:id/promote-manager", userHandler.AdminPromoteToManager)
 		admin.PUT("/users/:id/managers", userHandler.AdminUpdateEmployeeManagers)
+		admin.POST("/users/:id/deactivate", userHandler.AdminDeactivateUser)
+		admin.POST("/users/:id/reactivate", userHandler.AdminReactivateUser)
+		admin.POST("/users/:id/offboard", userHandler.AdminOffboardUser)
+		admin.POST("/users/:id/transfer", userHandler.AdminTransferEmployee)
+		admin.GET("/users/:id/transfers", userHandler.AdminListEmployeeTransfers)
 		admin.GET("/users/:id/points", pointHandler.AdminGetUserPoints)
 		admin.GET("/points", pointHandler.AdminListAllPoints)
 
@@ -132,6 +213,16 @@ func RegisterRoutes(
 			adminContents.GET("/", contentHandler.AdminListContents)
 			adminContents.POST("/", contentHandler.AdminCreateContent)
 			adminContents.PUT("/:id", contentHandler.AdminUpdateContent)
+			adminContents.GET("/:id/revisions", contentHandler.AdminListRevisions)
+			adminContents.GET("/:id/revisions/diff", contentHandler.AdminDiffRevisions)
+			adminContents.GET("/:id/revisions/:version", contentHandler.AdminGetRevision)
+			adminContents.POST("/:id/revisions/:version/rollback", contentHandler.AdminRollbackContent)
+			adminContents.GET("/:id/checkpoints", checkpointHandler.AdminListCheckpoints)
+			adminContents.POST("/:id/checkpoints", checkpointHandler.AdminCreateCheckpoint)
+			adminContents.PUT("/:id/checkpoints/:checkpoint_id", checkpointHandler.AdminUpdateCheckpoint)
+			adminContents.DELETE("/:id/checkpoints/:checkpoint_id", checkpointHandler.AdminDeleteCheckpoint)
+			adminContents.POST("/:id/media-jobs", mediaHandler.AdminReprocessContent)
+			adminContents.PUT("/:id/schedule", scheduleHandler.AdminScheduleContent)
 		}
 
 		adminBanners := admin.Group("/banners")
@@ -148,19 +239,106 @@ func RegisterRoutes(
 			adminExams.GET("/:id", examHandler.AdminGetExam)
 			adminExams.POST("/", examHandler.AdminCreateExam)
 			adminExams.PUT("/:id", examHandler.AdminUpdateExam)
+			adminExams.PUT("/:id/schedule", scheduleHandler.AdminScheduleExam)
+		}
+
+		adminCertificates := admin.Group("/certificate-templates")
+		{
+			adminCertificates.GET("/", certificateHandler.AdminListTemplates)
+			adminCertificates.POST("/", certificateHandler.AdminCreateTemplate)
+			adminCertificates.PUT("/:id", certificateHandler.AdminUpdateTemplate)
+			adminCertificates.DELETE("/:id", certificateHandler.AdminDeleteTemplate)
+			adminCertificates.GET("/:id/preview", certificateHandler.AdminPreviewTemplate)
+		}
+
+		adminCertifications := admin.Group("/certifications")
+		{
+			adminCertifications.GET("/", certificationHandler.AdminList)
+			adminCertifications.POST("/", certificationHandler.AdminCreate)
+			adminCertifications.GET("/expiring", certificationHandler.AdminExpiring)
+			adminCertifications.PUT("/:id", certificationHandler.AdminUpdate)
+			adminCertifications.DELETE("/:id", certificationHandler.AdminDelete)
+		}
+
+		adminAudit := admin.Group("/audit-logs")
+		{
+			adminAudit.GET("/", auditHandler.AdminListAuditLogs)
+			adminAudit.GET("/export", auditHandler.AdminExportAuditLogs)
+			adminAudit.GET("/entities/:entity_type/:entity_id", auditHandler.AdminEntityHistory)
+		}
+
+		admin.GET("/media-jobs", mediaHandler.AdminListMediaJobs)
+		admin.POST("/search/reindex", searchHandler.AdminReindex)
+		admin.GET("/schedule", scheduleHandler.AdminCalendar)
+
+		adminReviews := admin.Group("/reviews")
+		{
+			adminReviews.GET("/", reviewHandler.AdminQueue)
+			adminReviews.GET("/:entity_type/:entity_id", reviewHandler.AdminDetail)
+			adminReviews.POST("/:entity_type/:entity_id/submit", reviewHandler.AdminSubmit)
+			adminReviews.POST("/:entity_type/:entity_id/withdraw", reviewHandler.AdminWithdraw)
+			adminReviews.PUT("/:entity_type/:entity_id/reviewer", reviewHandler.AdminAssignReviewer)
+			adminReviews.POST("/:entity_type/:entity_id/approve", reviewHandler.AdminApprove)
+			adminReviews.POST("/:entity_type/:entity_id/reject", reviewHandler.AdminReject)
+			adminReviews.POST("/:entity_type/:entity_id/comments", reviewHandler.AdminComment)
 		}
 
 		adminGrowth := admin.Group("/growth")
 		{
 			adminGrowth.GET("/", growthHandler.AdminListPosts)
+			adminGrowth.GET("/reject-reasons", growthHandler.AdminRejectReasons)
+			adminGrowth.GET("/rejections/report", growthHandler.AdminRejectionReport)
 			adminGrowth.POST("/:id/approve", growthHandler.AdminApprovePost)
 			adminGrowth.POST("/:id/reject", growthHandler.AdminRejectPost)
+			adminGrowth.POST("/:id/pin", growthHandler.AdminPinPost)
+			adminGrowth.DELETE("/:id/pin", growthHandler.AdminUnpinPost)
+			adminGrowth.POST("/:id/feature", growthHandler.AdminFeaturePost)
+			adminGrowth.DELETE("/:id/feature", growthHandler.AdminUnfeaturePost)
+			adminGrowth.GET("/comments", growthHandler.AdminListComments)
+			adminGrowth.POST("/comments/:id/approve", growthHandler.AdminApproveComment)
+			adminGrowth.POST("/comments/:id/reject", growthHandler.AdminRejectComment)
+		}
+
+		adminModeration := admin.Group("/moderation")
+		{
+			adminModeration.GET("/words", moderationHandler.AdminListWords)
+			adminModeration.POST("/words", moderationHandler.AdminCreateWord)
+			adminModeration.PUT("/words/:id", moderationHandler.AdminUpdateWord)
+			adminModeration.DELETE("/words/:id", moderationHandler.AdminDeleteWord)
+			adminModeration.POST("/check", moderationHandler.AdminCheck)
+		}
+
+		adminFeedback := admin.Group("/feedback")
+		{
+			adminFeedback.GET("/contents", feedbackHandler.AdminLowestRated)
+			adminFeedback.GET("/ratings", feedbackHandler.AdminListRatings)
+			adminFeedback.POST("/ratings/:id/approve", feedbackHandler.AdminApproveRating)
+			adminFeedback.POST("/ratings/:id/reject", feedbackHandler.AdminRejectRating)
+			adminFeedback.GET("/comments", feedbackHandler.AdminListComments)
+			adminFeedback.POST("/comments/:id/approve", feedbackHandler.AdminApproveComment)
+			adminFeedback.POST("/comments/:id/reject", feedbackHandler.AdminRejectComment)
+		}
+
+		adminAnalytics := admin.Group("/analytics")
+		{
+			adminAnalytics.GET("/learning", analyticsHandler.AdminLearningAnalytics)
 		}
 	}
 
 	files := api.Group("/files")
+	// 签名链接本身即访问凭证，下载接口不经过 JWT 鉴权
+	files.GET("/download/*key", uploadHandler.Download)
 	files.Use(authMiddleware)
 	files.POST("/upload", uploadHandler.Upload)
+	files.POST("/uploads", uploadHandler.InitUploadSession)
+	files.GET("/uploads/:upload_id", uploadHandler.GetUploadSession)
+	files.PUT("/uploads/:upload_id/chunks", uploadHandler.UploadChunk)
+	files.POST("/uploads/:upload_id/complete", uploadHandler.CompleteUploadSession)
+	files.DELETE("/uploads/:upload_id", uploadHandler.AbortUploadSession)
+
+	// HLS 播放地址同样由签名授权，播放器无法为每个切片附带 JWT
+	media := api.Group("/media")
+	media.GET("/hls/*path", mediaHandler.ServeHLS)
 
 	RegisterSystemRoutes(engine, systemHandler)
 
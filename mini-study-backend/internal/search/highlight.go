@@ -0,0 +1,89 @@
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	snippetRunes = 120 // length of body excerpts
+	snippetLead  = 20  // context kept before the first match of an excerpt
+)
+
+// highlight escapes text for HTML and wraps the spans matching terms in <em> tags. With a
+// positive limit, longer texts are cut to an excerpt of about limit runes around the first
+// match.
+func highlight(text string, terms map[string]struct{}, limit int) string {
+	spans := matchSpans(text, terms)
+	start, end := 0, len(text)
+	if limit > 0 && utf8.RuneCountInString(text) > limit {
+		if len(spans) > 0 {
+			start = backRunes(text, spans[0][0], snippetLead)
+		}
+		end = forwardRunes(text, start, limit)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, span := range spans {
+		s, e := max(span[0], start), min(span[1], end)
+		if e <= pos {
+			continue
+		}
+		if s >= end {
+			break
+		}
+		b.WriteString(html.EscapeString(text[pos:s]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(text[s:e]))
+		b.WriteString("</em>")
+		pos = e
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// matchSpans returns the sorted, merged byte spans of the tokens matching terms.
+func matchSpans(text string, terms map[string]struct{}) [][2]int {
+	var spans [][2]int
+	for _, token := range Tokenize(text) {
+		if _, ok := terms[token.Term]; ok {
+			spans = append(spans, [2]int{token.Start, token.End})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	merged := spans[:0]
+	for _, span := range spans {
+		if n := len(merged); n > 0 && span[0] <= merged[n-1][1] {
+			merged[n-1][1] = max(merged[n-1][1], span[1])
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
+
+func backRunes(text string, i, n int) int {
+	for ; n > 0 && i > 0; n-- {
+		_, size := utf8.DecodeLastRuneInString(text[:i])
+		i -= size
+	}
+	return i
+}
+
+func forwardRunes(text string, i, n int) int {
+	for ; n > 0 && i < len(text); n-- {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	return i
+}
@@ -0,0 +1,289 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// BM25 parameters and per-field weights; a title match weighs as much as three body matches.
+const (
+	bm25K1      = 1.2
+	bm25B       = 0.75
+	titleWeight = 3.0
+	bodyWeight  = 1.0
+)
+
+type memoryEntry struct {
+	doc      Document
+	title    map[string]int
+	body     map[string]int
+	titleLen int
+	bodyLen  int
+}
+
+// MemoryIndex is an embedded inverted index held in process memory. It is rebuilt from the
+// database at startup and kept in sync by the services that change searchable entities.
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	entries  map[string]*memoryEntry
+	postings map[string]map[string]struct{}
+	titleLen int
+	bodyLen  int
+}
+
+// NewMemoryIndex creates an empty in-memory index.
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		entries:  make(map[string]*memoryEntry),
+		postings: make(map[string]map[string]struct{}),
+	}
+}
+
+// Index adds or replaces documents.
+func (m *MemoryIndex) Index(ctx context.Context, docs ...Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, doc := range docs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		m.remove(doc.Key())
+		m.add(doc)
+	}
+	return nil
+}
+
+// Delete removes a document.
+func (m *MemoryIndex) Delete(_ context.Context, kind string, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remove(DocumentKey(kind, id))
+	return nil
+}
+
+// Rebuild replaces the index contents. The new index is built aside so searches keep being
+// served from the old one meanwhile.
+func (m *MemoryIndex) Rebuild(ctx context.Context, docs []Document) error {
+	fresh := NewMemoryIndex()
+	for _, doc := range docs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fresh.remove(doc.Key())
+		fresh.add(doc)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries, m.postings = fresh.entries, fresh.postings
+	m.titleLen, m.bodyLen = fresh.titleLen, fresh.bodyLen
+	return nil
+}
+
+// Search ranks the documents containing every query term with BM25.
+func (m *MemoryIndex) Search(_ context.Context, query Query) (*Result, error) {
+	result := &Result{Hits: []Hit{}, Facets: map[string][]FacetCount{}}
+	terms := QueryTerms(query.Text)
+	if len(terms) == 0 {
+		return result, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	candidates := m.candidates(terms)
+	if len(candidates) == 0 {
+		return result, nil
+	}
+
+	n := float64(len(m.entries))
+	avgTitle := math.Max(float64(m.titleLen)/n, 1)
+	avgBody := math.Max(float64(m.bodyLen)/n, 1)
+	idf := make(map[string]float64, len(terms))
+	for _, term := range terms {
+		df := float64(len(m.postings[term]))
+		idf[term] = math.Log(1 + (n-df+0.5)/(df+0.5))
+	}
+
+	facets := newFacetCounter()
+	hits := make([]Hit, 0, len(candidates))
+	for _, key := range candidates {
+		entry := m.entries[key]
+		doc := entry.doc
+		if !doc.VisibleTo(query.Role) || !matchesFilters(doc, query) {
+			continue
+		}
+		facets.add(doc)
+
+		titleNorm := 1 - bm25B + bm25B*float64(entry.titleLen)/avgTitle
+		bodyNorm := 1 - bm25B + bm25B*float64(entry.bodyLen)/avgBody
+		score := 0.0
+		for _, term := range terms {
+			tf := titleWeight*float64(entry.title[term])/titleNorm + bodyWeight*float64(entry.body[term])/bodyNorm
+			score += idf[term] * tf * (bm25K1 + 1) / (tf + bm25K1)
+		}
+		hits = append(hits, Hit{Document: doc, Score: score})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if !hits[i].CreatedAt.Equal(hits[j].CreatedAt) {
+			return hits[i].CreatedAt.After(hits[j].CreatedAt)
+		}
+		return hits[i].Key() < hits[j].Key()
+	})
+
+	result.Total = len(hits)
+	result.Facets = facets.result()
+	start := min(max(query.Offset, 0), len(hits))
+	end := len(hits)
+	if query.Limit > 0 {
+		end = min(start+query.Limit, len(hits))
+	}
+	termSet := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		termSet[term] = struct{}{}
+	}
+	for _, hit := range hits[start:end] {
+		hit.TitleHighlight = highlight(hit.Title, termSet, 0)
+		hit.BodyHighlight = highlight(hit.Body, termSet, snippetRunes)
+		result.Hits = append(result.Hits, hit)
+	}
+	return result, nil
+}
+
+// candidates intersects the posting lists of all terms, starting from the shortest.
+func (m *MemoryIndex) candidates(terms []string) []string {
+	lists := make([]map[string]struct{}, 0, len(terms))
+	for _, term := range terms {
+		posting, ok := m.postings[term]
+		if !ok {
+			return nil
+		}
+		lists = append(lists, posting)
+	}
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i]) < len(lists[j]) })
+
+	var keys []string
+next:
+	for key := range lists[0] {
+		for _, posting := range lists[1:] {
+			if _, ok := posting[key]; !ok {
+				continue next
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (m *MemoryIndex) add(doc Document) {
+	entry := &memoryEntry{doc: doc, title: make(map[string]int), body: make(map[string]int)}
+	for _, token := range Tokenize(doc.Title) {
+		entry.title[token.Term]++
+		entry.titleLen++
+	}
+	for _, token := range Tokenize(doc.Body) {
+		entry.body[token.Term]++
+		entry.bodyLen++
+	}
+
+	key := doc.Key()
+	m.entries[key] = entry
+	m.titleLen += entry.titleLen
+	m.bodyLen += entry.bodyLen
+	for _, terms := range []map[string]int{entry.title, entry.body} {
+		for term := range terms {
+			posting, ok := m.postings[term]
+			if !ok {
+				posting = make(map[string]struct{})
+				m.postings[term] = posting
+			}
+			posting[key] = struct{}{}
+		}
+	}
+}
+
+func (m *MemoryIndex) remove(key string) {
+	entry, ok := m.entries[key]
+	if !ok {
+		return
+	}
+	delete(m.entries, key)
+	m.titleLen -= entry.titleLen
+	m.bodyLen -= entry.bodyLen
+	for _, terms := range []map[string]int{entry.title, entry.body} {
+		for term := range terms {
+			posting := m.postings[term]
+			delete(posting, key)
+			if len(posting) == 0 {
+				delete(m.postings, term)
+			}
+		}
+	}
+}
+
+func matchesFilters(doc Document, query Query) bool {
+	if query.Kind != "" && doc.Kind != query.Kind {
+		return false
+	}
+	if query.Type != "" && doc.Type != query.Type {
+		return false
+	}
+	if query.CategoryID > 0 && doc.CategoryID != query.CategoryID {
+		return false
+	}
+	if query.VisibleRole != "" && !doc.VisibleTo(query.VisibleRole) {
+		return false
+	}
+	return true
+}
+
+// facetRoles are the roles reported by the role facet; administrators see everything and are
+// not a facet value.
+var facetRoles = []string{"employee", "manager"}
+
+type facetCounter map[string]map[string]int
+
+func newFacetCounter() facetCounter {
+	return facetCounter{FacetKind: {}, FacetType: {}, FacetCategory: {}, FacetRole: {}}
+}
+
+func (f facetCounter) add(doc Document) {
+	f[FacetKind][doc.Kind]++
+	if doc.Type != "" {
+		f[FacetType][doc.Type]++
+	}
+	if doc.CategoryID > 0 {
+		f[FacetCategory][strconv.FormatUint(uint64(doc.CategoryID), 10)]++
+	}
+	for _, role := range facetRoles {
+		if doc.VisibleTo(role) {
+			f[FacetRole][role]++
+		}
+	}
+}
+
+// result orders every facet by count, then value.
+func (f facetCounter) result() map[string][]FacetCount {
+	out := make(map[string][]FacetCount, len(f))
+	for name, values := range f {
+		counts := make([]FacetCount, 0, len(values))
+		for value, count := range values {
+			counts = append(counts, FacetCount{Value: value, Count: count})
+		}
+		sort.Slice(counts, func(i, j int) bool {
+			if counts[i].Count != counts[j].Count {
+				return counts[i].Count > counts[j].Count
+			}
+			return counts[i].Value < counts[j].Value
+		})
+		out[name] = counts
+	}
+	return out
+}
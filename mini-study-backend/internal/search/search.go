@@ -0,0 +1,106 @@
+package search
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Document kinds.
+const (
+	KindContent = "content"
+	KindExam    = "exam"
+	KindGrowth  = "growth"
+)
+
+// Facet names returned with every result.
+const (
+	FacetKind     = "kind"
+	FacetType     = "type"
+	FacetCategory = "category_id"
+	FacetRole     = "role"
+)
+
+// Document is one searchable entity. Roles lists the roles allowed to see it; an empty
+// list means every signed-in user may.
+type Document struct {
+	Kind       string
+	ID         uint
+	Title      string
+	Body       string
+	Type       string
+	CategoryID uint
+	Roles      []string
+	CreatedAt  time.Time
+}
+
+// Key identifies a document across kinds.
+func (d Document) Key() string {
+	return DocumentKey(d.Kind, d.ID)
+}
+
+// DocumentKey builds the key of the document with the given kind and ID.
+func DocumentKey(kind string, id uint) string {
+	return kind + ":" + strconv.FormatUint(uint64(id), 10)
+}
+
+// VisibleTo reports whether a user with role may see the document. The empty role stands
+// for administrators, who see everything.
+func (d Document) VisibleTo(role string) bool {
+	if role == "" || len(d.Roles) == 0 {
+		return true
+	}
+	for _, r := range d.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Query describes a search. Role is the caller's role and is always enforced; Kind, Type,
+// CategoryID and VisibleRole are optional facet filters.
+type Query struct {
+	Text        string
+	Role        string
+	Kind        string
+	Type        string
+	CategoryID  uint
+	VisibleRole string
+	Offset      int
+	Limit       int
+}
+
+// Hit is a matching document with its relevance score. The highlights are HTML-escaped
+// with matched terms wrapped in <em> tags.
+type Hit struct {
+	Document
+	Score          float64
+	TitleHighlight string
+	BodyHighlight  string
+}
+
+// FacetCount is the number of matching documents sharing a facet value.
+type FacetCount struct {
+	Value string
+	Count int
+}
+
+// Result is one page of hits together with the total and facet counts of all matches.
+type Result struct {
+	Total  int
+	Hits   []Hit
+	Facets map[string][]FacetCount
+}
+
+// Index is implemented by every search backend.
+type Index interface {
+	// Index adds documents or replaces those with the same key.
+	Index(ctx context.Context, docs ...Document) error
+	// Delete removes a document; deleting a missing document is not an error.
+	Delete(ctx context.Context, kind string, id uint) error
+	// Rebuild replaces the whole index with docs.
+	Rebuild(ctx context.Context, docs []Document) error
+	// Search returns the documents matching every term of the query text.
+	Search(ctx context.Context, query Query) (*Result, error)
+}
@@ -0,0 +1,101 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token is a normalized term and the byte span it was read from.
+type Token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// Tokenize splits text into index terms. Runs of letters and digits become lowercase words.
+// CJK text has no word delimiters, so every character and every pair of adjacent characters
+// becomes a term; this matches any query substring without a dictionary.
+func Tokenize(text string) []Token {
+	return tokenize(text, true)
+}
+
+// QueryTerms returns the distinct terms a query must match. CJK runs are reduced to their
+// bigrams, or to the single character for one-character runs.
+func QueryTerms(text string) []string {
+	seen := make(map[string]struct{})
+	var terms []string
+	for _, token := range tokenize(text, false) {
+		if _, ok := seen[token.Term]; ok {
+			continue
+		}
+		seen[token.Term] = struct{}{}
+		terms = append(terms, token.Term)
+	}
+	return terms
+}
+
+func tokenize(text string, unigrams bool) []Token {
+	var tokens []Token
+	var cjk []Token // characters of the current CJK run
+
+	flushCJK := func() {
+		switch {
+		case len(cjk) == 1:
+			tokens = append(tokens, cjk[0])
+		case len(cjk) > 1:
+			for i := range cjk {
+				if unigrams {
+					tokens = append(tokens, cjk[i])
+				}
+				if i+1 < len(cjk) {
+					tokens = append(tokens, Token{Term: cjk[i].Term + cjk[i+1].Term, Start: cjk[i].Start, End: cjk[i+1].End})
+				}
+			}
+		}
+		cjk = cjk[:0]
+	}
+
+	wordStart := -1
+	var word strings.Builder
+	flushWord := func(end int) {
+		if wordStart >= 0 {
+			tokens = append(tokens, Token{Term: word.String(), Start: wordStart, End: end})
+			word.Reset()
+			wordStart = -1
+		}
+	}
+
+	for i, orig := range text {
+		r := normalizeRune(orig)
+		switch {
+		case isCJK(r):
+			flushWord(i)
+			cjk = append(cjk, Token{Term: string(r), Start: i, End: i + utf8.RuneLen(orig)})
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			if wordStart < 0 {
+				wordStart = i
+			}
+			word.WriteRune(unicode.ToLower(r))
+		default:
+			flushCJK()
+			flushWord(i)
+		}
+	}
+	flushCJK()
+	flushWord(len(text))
+	return tokens
+}
+
+// normalizeRune folds full-width ASCII, common in Chinese input, to its half-width form.
+func normalizeRune(r rune) rune {
+	if r >= 0xFF01 && r <= 0xFF5E {
+		return r - 0xFEE0
+	}
+	return r
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+)
+
+const (
+	analyticsCohortAll       = "all"
+	analyticsCohortUnmanaged = "0"
+)
+
+// AnalyticsService 计算管理后台的学习数据看板。指标由每日学习活动、考试记录和积分明细按周期汇总，
+// 管理员账号不计入。
+type AnalyticsService struct {
+	analytics *repository.AnalyticsRepository
+	users     *repository.UserRepository
+	relations *repository.ManagerEmployeeRepository
+}
+
+// NewAnalyticsService 创建学习数据看板服务。
+func NewAnalyticsService(analytics *repository.AnalyticsRepository, users *repository.UserRepository, relations *repository.ManagerEmployeeRepository) *AnalyticsService {
+	return &AnalyticsService{analytics: analytics, users: users, relations: relations}
+}
+
+func (s *AnalyticsService) ensureAdmin(userID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("管理员不存在")
+		}
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return errors.New("仅管理员可操作")
+	}
+	return nil
+}
+
+// learningBucket accumulates the metrics of one cohort over one period.
+type learningBucket struct {
+	active       map[uint]bool
+	videoUsers   map[uint]bool
+	watchSeconds int64
+	point        dto.LearningAnalyticsPoint
+}
+
+func (b *learningBucket) result(period string) dto.LearningAnalyticsPoint {
+	p := b.point
+	p.Period = period
+	p.ActiveLearners = int64(len(b.active))
+	if p.ExamAttempts > 0 {
+		p.ExamPassRate = float64(p.ExamPassed) / float64(p.ExamAttempts) * 100
+	}
+	p.VideoLearners = int64(len(b.videoUsers))
+	if p.VideoLearners > 0 {
+		p.AvgWatchSeconds = float64(b.watchSeconds) / float64(p.VideoLearners)
+	}
+	return p
+}
+
+// learningCohort holds the buckets of one cohort: one per period plus the whole range.
+type learningCohort struct {
+	key     string
+	label   string
+	periods []learningBucket
+	total   learningBucket
+}
+
+// AdminLearningAnalytics 按日、周或月汇总活跃学员、完成数、考试通过率、人均观看时长和积分，
+// 可按角色或店长团队分组对比。
+func (s *AnalyticsService) AdminLearningAnalytics(adminID uint, query dto.LearningAnalyticsQuery) (*dto.LearningAnalyticsResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	window, err := newReportWindow(query.From, query.To, query.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := window.Bounds()
+	activity, err := s.analytics.Activity(bounds)
+	if err != nil {
+		return nil, err
+	}
+	completions, err := s.analytics.Completions(bounds)
+	if err != nil {
+		return nil, err
+	}
+	exams, err := s.analytics.ExamResults(bounds)
+	if err != nil {
+		return nil, err
+	}
+	points, err := s.analytics.PointTotals(bounds)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[uint]bool)
+	for _, list := range [][]repository.PeriodTotal{activity, completions, exams, points} {
+		for _, t := range list {
+			ids[t.UserID] = true
+		}
+	}
+	members, err := s.cohortMembers(ids, query)
+	if err != nil {
+		return nil, err
+	}
+
+	cohorts := make(map[string]*learningCohort)
+	bucketsOf := func(t repository.PeriodTotal) []*learningBucket {
+		keys, ok := members[t.UserID]
+		if !ok || t.Period < 0 || t.Period >= len(window.Periods) {
+			return nil
+		}
+		var buckets []*learningBucket
+		for _, key := range keys {
+			c := cohorts[key]
+			if c == nil {
+				c = &learningCohort{key: key, periods: make([]learningBucket, len(window.Periods))}
+				cohorts[key] = c
+			}
+			buckets = append(buckets, &c.periods[t.Period], &c.total)
+		}
+		return buckets
+	}
+	mark := func(set *map[uint]bool, userID uint) {
+		if *set == nil {
+			*set = make(map[uint]bool)
+		}
+		(*set)[userID] = true
+	}
+
+	for _, t := range activity {
+		for _, b := range bucketsOf(t) {
+			mark(&b.active, t.UserID)
+			if t.Amount > 0 {
+				mark(&b.videoUsers, t.UserID)
+				b.watchSeconds += t.Amount
+			}
+		}
+	}
+	for _, t := range completions {
+		for _, b := range bucketsOf(t) {
+			b.point.Completions += t.Events
+		}
+	}
+	for _, t := range points {
+		for _, b := range bucketsOf(t) {
+			mark(&b.active, t.UserID)
+			b.point.PointsEarned += t.Amount
+		}
+	}
+	for _, t := range exams {
+		for _, b := range bucketsOf(t) {
+			mark(&b.active, t.UserID)
+			b.point.ExamAttempts += t.Events
+			b.point.ExamPassed += t.Amount
+		}
+	}
+
+	ordered, err := s.orderCohorts(cohorts, query, len(window.Periods))
+	if err != nil {
+		return nil, err
+	}
+	labels := window.Labels()
+	resp := &dto.LearningAnalyticsResponse{
+		From:     window.From.Format(reportDateLayout),
+		To:       window.To.Format(reportDateLayout),
+		Interval: window.Interval,
+		GroupBy:  query.GroupBy,
+		Periods:  labels,
+		Cohorts:  make([]dto.LearningCohortSeries, 0, len(ordered)),
+	}
+	for _, c := range ordered {
+		series := make([]dto.LearningAnalyticsPoint, len(c.periods))
+		for i := range c.periods {
+			series[i] = c.periods[i].result(labels[i])
+		}
+		resp.Cohorts = append(resp.Cohorts, dto.LearningCohortSeries{
+			Key:    c.key,
+			Label:  c.label,
+			Total:  c.total.result(""),
+			Series: series,
+		})
+	}
+	return resp, nil
+}
+
+// cohortMembers maps each counted user to the cohorts it belongs to. Admins, unknown users and
+// users outside the role or manager filter are left out. An employee bound to several managers
+// counts in each of their teams; a manager leads their own team.
+func (s *AnalyticsService) cohortMembers(ids map[uint]bool, query dto.LearningAnalyticsQuery) (map[uint][]string, error) {
+	list := make([]uint, 0, len(ids))
+	for id := range ids {
+		list = append(list, id)
+	}
+	users, err := s.users.FindByIDs(list)
+	if err != nil {
+		return nil, err
+	}
+
+	managersOf := make(map[uint][]uint)
+	if query.GroupBy == "manager" || query.ManagerID > 0 {
+		relations, err := s.relations.ListByEmployeeIDs(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range relations {
+			managersOf[r.EmployeeID] = append(managersOf[r.EmployeeID], r.ManagerID)
+		}
+	}
+	teamsOf := func(u model.User) []uint {
+		if u.Role == model.RoleManager {
+			return []uint{u.ID}
+		}
+		return managersOf[u.ID]
+	}
+
+	members := make(map[uint][]string, len(users))
+	for _, u := range users {
+		if u.Role == model.RoleAdmin || (query.Role != "" && u.Role != query.Role) {
+			continue
+		}
+		teams := teamsOf(u)
+		if query.ManagerID > 0 && !containsID(teams, query.ManagerID) {
+			continue
+		}
+		switch query.GroupBy {
+		case "role":
+			members[u.ID] = []string{string(u.Role)}
+		case "manager":
+			if len(teams) == 0 {
+				members[u.ID] = []string{analyticsCohortUnmanaged}
+				continue
+			}
+			keys := make([]string, 0, len(teams))
+			for _, id := range teams {
+				keys = append(keys, strconv.FormatUint(uint64(id), 10))
+			}
+			members[u.ID] = keys
+		default:
+			members[u.ID] = []string{analyticsCohortAll}
+		}
+	}
+	return members, nil
+}
+
+// orderCohorts labels the cohorts and puts them in display order. Without grouping, and for
+// each role when grouping by role, the cohort is returned even if it has no data; manager
+// cohorts are ordered by name with unbound employees last.
+func (s *AnalyticsService) orderCohorts(cohorts map[string]*learningCohort, query dto.LearningAnalyticsQuery, periods int) ([]*learningCohort, error) {
+	ensure := func(key, label string) *learningCohort {
+		c := cohorts[key]
+		if c == nil {
+			c = &learningCohort{key: key, periods: make([]learningBucket, periods)}
+		}
+		c.label = label
+		return c
+	}
+
+	switch query.GroupBy {
+	case "role":
+		var ordered []*learningCohort
+		for _, role := range []string{model.RoleEmployee, model.RoleManager} {
+			if query.Role == "" || query.Role == role {
+				ordered = append(ordered, ensure(role, searchRoleLabels[role]))
+			}
+		}
+		return ordered, nil
+	case "manager":
+		var ids []uint
+		for key := range cohorts {
+			if id, err := strconv.ParseUint(key, 10, 64); err == nil && id > 0 {
+				ids = append(ids, uint(id))
+			}
+		}
+		managers, err := s.users.FindByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		ordered := make([]*learningCohort, 0, len(cohorts))
+		for _, m := range managers {
+			key := strconv.FormatUint(uint64(m.ID), 10)
+			ordered = append(ordered, ensure(key, m.Name))
+		}
+		sort.Slice(ordered, func(i, j int) bool {
+			if ordered[i].label != ordered[j].label {
+				return ordered[i].label < ordered[j].label
+			}
+			return ordered[i].key < ordered[j].key
+		})
+		if _, ok := cohorts[analyticsCohortUnmanaged]; ok {
+			ordered = append(ordered, ensure(analyticsCohortUnmanaged, "未绑定店长"))
+		}
+		return ordered, nil
+	default:
+		return []*learningCohort{ensure(analyticsCohortAll, "全部学员")}, nil
+	}
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+)
+
+// Article block types.
+const (
+	ArticleBlockText       = "text"
+	ArticleBlockHeading    = "heading"
+	ArticleBlockImage      = "image"
+	ArticleBlockList       = "list"
+	ArticleBlockQuote      = "quote"
+	ArticleBlockVideo      = "video"
+	ArticleBlockAttachment = "attachment"
+	ArticleBlockCallout    = "callout"
+	ArticleBlockQuiz       = "quiz"
+)
+
+const (
+	maxArticleBlocks      = 200
+	maxArticleTextRunes   = 5000
+	maxArticleLabelRunes  = 200
+	maxArticleListItems   = 50
+	maxArticleQuizOptions = 10
+	// articleSummaryRunes 是未填写摘要时从正文截取的长度
+	articleSummaryRunes = 120
+)
+
+var calloutStyles = map[string]struct{}{"info": {}, "tip": {}, "warning": {}, "danger": {}}
+
+// htmlTagPattern matches markup such as <script> or </p>, leaving comparisons like "a < b" alone.
+var htmlTagPattern = regexp.MustCompile(`</?[A-Za-z!][^<>]*>`)
+
+// NormalizeArticleBlocks validates article blocks against the schema of their type and returns
+// sanitized copies: markup and control characters are stripped from text, fields that do not
+// belong to the type are dropped and defaults are filled in.
+func NormalizeArticleBlocks(blocks []dto.ArticleBlock) ([]dto.ArticleBlock, error) {
+	if len(blocks) > maxArticleBlocks {
+		return nil, fmt.Errorf("图文内容块不能超过 %d 个", maxArticleBlocks)
+	}
+	out := make([]dto.ArticleBlock, 0, len(blocks))
+	for i, block := range blocks {
+		normalized, err := normalizeArticleBlock(block)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 个内容块：%s", i+1, err.Error())
+		}
+		out = append(out, normalized)
+	}
+	return out, nil
+}
+
+func normalizeArticleBlock(block dto.ArticleBlock) (dto.ArticleBlock, error) {
+	out := dto.ArticleBlock{Type: strings.TrimSpace(block.Type)}
+	var err error
+	switch out.Type {
+	case ArticleBlockText:
+		out.Text, err = blockText(block.Text, "正文", maxArticleTextRunes, true)
+	case ArticleBlockHeading:
+		if out.Text, err = blockText(block.Text, "标题", maxArticleLabelRunes, true); err != nil {
+			break
+		}
+		out.Level = block.Level
+		if out.Level == 0 {
+			out.Level = 2
+		}
+		if out.Level < 1 || out.Level > 3 {
+			err = fmt.Errorf("标题级别须为 1-3")
+		}
+	case ArticleBlockImage:
+		if out.ImagePath, err = blockPath(block.ImagePath, "图片"); err != nil {
+			break
+		}
+		out.Caption, err = blockText(block.Caption, "图片说明", maxArticleLabelRunes, false)
+	case ArticleBlockList:
+		out.Ordered = block.Ordered
+		for _, item := range block.Items {
+			text, itemErr := blockText(item, "列表项", maxArticleLabelRunes*2, false)
+			if itemErr != nil {
+				err = itemErr
+				break
+			}
+			if text != "" {
+				out.Items = append(out.Items, text)
+			}
+		}
+		if err == nil && len(out.Items) == 0 {
+			err = fmt.Errorf("列表不能为空")
+		}
+		if err == nil && len(out.Items) > maxArticleListItems {
+			err = fmt.Errorf("列表项不能超过 %d 个", maxArticleListItems)
+		}
+	case ArticleBlockQuote:
+		if out.Text, err = blockText(block.Text, "引用内容", maxArticleTextRunes, true); err != nil {
+			break
+		}
+		out.Cite, err = blockText(block.Cite, "引用出处", maxArticleLabelRunes, false)
+	case ArticleBlockVideo:
+		if out.VideoPath, err = blockPath(block.VideoPath, "视频"); err != nil {
+			break
+		}
+		if out.Caption, err = blockText(block.Caption, "视频说明", maxArticleLabelRunes, false); err != nil {
+			break
+		}
+		out.StartSeconds, out.EndSeconds = block.StartSeconds, block.EndSeconds
+		if out.StartSeconds < 0 || out.EndSeconds < 0 {
+			err = fmt.Errorf("视频片段时间不能为负数")
+		} else if out.EndSeconds > 0 && out.EndSeconds <= out.StartSeconds {
+			err = fmt.Errorf("视频片段结束时间须晚于起始时间")
+		}
+	case ArticleBlockAttachment:
+		if out.FilePath, err = blockPath(block.FilePath, "附件"); err != nil {
+			break
+		}
+		if out.FileName, err = blockText(block.FileName, "附件名称", maxArticleLabelRunes, false); err != nil {
+			break
+		}
+		if out.FileName == "" {
+			out.FileName = path.Base(out.FilePath)
+		}
+	case ArticleBlockCallout:
+		out.Style = strings.TrimSpace(block.Style)
+		if out.Style == "" {
+			out.Style = "info"
+		}
+		if _, ok := calloutStyles[out.Style]; !ok {
+			err = fmt.Errorf("提示框样式须为 info/tip/warning/danger")
+			break
+		}
+		if out.Title, err = blockText(block.Title, "提示框标题", maxArticleLabelRunes, false); err != nil {
+			break
+		}
+		out.Text, err = blockText(block.Text, "提示内容", maxArticleTextRunes, true)
+	case ArticleBlockQuiz:
+		out.Quiz, err = normalizeArticleQuiz(block.Quiz)
+	case "":
+		err = fmt.Errorf("缺少块类型")
+	default:
+		err = fmt.Errorf("不支持的块类型 %q", out.Type)
+	}
+	return out, err
+}
+
+func normalizeArticleQuiz(quiz *dto.ArticleQuiz) (*dto.ArticleQuiz, error) {
+	if quiz == nil {
+		return nil, fmt.Errorf("缺少测验题")
+	}
+	out := &dto.ArticleQuiz{Type: strings.TrimSpace(quiz.Type)}
+	if out.Type != "single" && out.Type != "multiple" {
+		return nil, fmt.Errorf("测验题型须为 single/multiple")
+	}
+	var err error
+	if out.Stem, err = blockText(quiz.Stem, "题干", maxArticleTextRunes, true); err != nil {
+		return nil, err
+	}
+	if out.Analysis, err = blockText(quiz.Analysis, "解析", maxArticleTextRunes, false); err != nil {
+		return nil, err
+	}
+	if len(quiz.Options) < 2 || len(quiz.Options) > maxArticleQuizOptions {
+		return nil, fmt.Errorf("测验选项须为 2-%d 个", maxArticleQuizOptions)
+	}
+	correct := 0
+	for _, option := range quiz.Options {
+		content, err := blockText(option.Content, "选项内容", maxArticleLabelRunes*2, true)
+		if err != nil {
+			return nil, err
+		}
+		if option.IsCorrect {
+			correct++
+		}
+		out.Options = append(out.Options, dto.ArticleQuizOption{Content: content, IsCorrect: option.IsCorrect})
+	}
+	if out.Type == "single" && correct != 1 {
+		return nil, fmt.Errorf("单选题须有且仅有一个正确选项")
+	}
+	if correct == 0 {
+		return nil, fmt.Errorf("多选题至少需要一个正确选项")
+	}
+	return out, nil
+}
+
+// gradeArticleQuiz checks the selected option indexes against the answer key of a quiz question.
+// Repeated indexes count once; the selection and the answer key are returned in ascending order.
+func gradeArticleQuiz(options []dto.ArticleQuizOption, indexes []int) (selected, correctIndexes []int, correct bool, err error) {
+	selected = make([]int, 0, len(indexes))
+	seen := make(map[int]bool, len(indexes))
+	for _, index := range indexes {
+		if index < 0 || index >= len(options) {
+			return nil, nil, false, fmt.Errorf("选项不存在")
+		}
+		if !seen[index] {
+			seen[index] = true
+			selected = append(selected, index)
+		}
+	}
+	sort.Ints(selected)
+	correctIndexes = make([]int, 0, 1)
+	for i, option := range options {
+		if option.IsCorrect {
+			correctIndexes = append(correctIndexes, i)
+		}
+	}
+	correct = len(selected) == len(correctIndexes)
+	for i := 0; correct && i < len(selected); i++ {
+		correct = selected[i] == correctIndexes[i]
+	}
+	return selected, correctIndexes, correct, nil
+}
+
+// blockText sanitizes a text field and checks its length.
+func blockText(raw, label string, maxRunes int, required bool) (string, error) {
+	text := sanitizeArticleText(raw)
+	if required && text == "" {
+		return "", fmt.Errorf("%s不能为空", label)
+	}
+	if utf8.RuneCountInString(text) > maxRunes {
+		return "", fmt.Errorf("%s不能超过 %d 个字符", label, maxRunes)
+	}
+	return text, nil
+}
+
+// blockPath accepts storage keys and http(s) URLs, rejecting path traversal and other schemes
+// such as javascript:.
+func blockPath(raw, label string) (string, error) {
+	p := strings.TrimSpace(raw)
+	if p == "" {
+		return "", fmt.Errorf("%s路径不能为空", label)
+	}
+	if len(p) > 512 {
+		return "", fmt.Errorf("%s路径过长", label)
+	}
+	for _, r := range p {
+		if unicode.IsControl(r) {
+			return "", fmt.Errorf("%s路径不合法", label)
+		}
+	}
+	lower := strings.ToLower(p)
+	if strings.Contains(p, ":") && !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		return "", fmt.Errorf("%s路径不合法", label)
+	}
+	for _, segment := range strings.Split(strings.ReplaceAll(p, "\\", "/"), "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("%s路径不合法", label)
+		}
+	}
+	return p, nil
+}
+
+// sanitizeArticleText strips markup and control characters (keeping line breaks and tabs) and
+// trims surrounding whitespace. Blocks hold plain text; clients render them as such.
+func sanitizeArticleText(raw string) string {
+	text := htmlTagPattern.ReplaceAllString(raw, "")
+	text = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if r == utf8.RuneError || unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+	return strings.TrimSpace(text)
+}
+
+// ArticlePlainText extracts the readable text of article blocks, one block per line, for search
+// and summaries. Quiz answers and analyses are left out.
+func ArticlePlainText(blocks []dto.ArticleBlock) string {
+	lines := make([]string, 0, len(blocks))
+	add := func(parts ...string) {
+		for _, part := range parts {
+			if part = strings.TrimSpace(part); part != "" {
+				lines = append(lines, part)
+			}
+		}
+	}
+	for _, block := range blocks {
+		switch block.Type {
+		case ArticleBlockList:
+			add(block.Items...)
+		case ArticleBlockQuote:
+			add(block.Text, block.Cite)
+		case ArticleBlockCallout:
+			add(block.Title, block.Text)
+		case ArticleBlockImage, ArticleBlockVideo:
+			add(block.Caption)
+		case ArticleBlockAttachment:
+			add(block.FileName)
+		case ArticleBlockQuiz:
+			if block.Quiz != nil {
+				add(block.Quiz.Stem)
+			}
+		default:
+			add(block.Text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LearnerArticleBlocks hides the answers and analyses of quiz blocks from learners.
+func LearnerArticleBlocks(blocks []dto.ArticleBlock) []dto.ArticleBlock {
+	out := make([]dto.ArticleBlock, len(blocks))
+	for i, block := range blocks {
+		out[i] = block
+		if block.Quiz == nil {
+			continue
+		}
+		quiz := *block.Quiz
+		quiz.Analysis = ""
+		quiz.Options = make([]dto.ArticleQuizOption, len(block.Quiz.Options))
+		for j, option := range block.Quiz.Options {
+			quiz.Options[j] = dto.ArticleQuizOption{Content: option.Content}
+		}
+		out[i].Quiz = &quiz
+	}
+	return out
+}
+
+// articleSummary derives a summary from the beginning of the article text.
+func articleSummary(blocks []dto.ArticleBlock) string {
+	text := strings.Join(strings.Fields(ArticlePlainText(blocks)), " ")
+	runes := []rune(text)
+	if len(runes) <= articleSummaryRunes {
+		return text
+	}
+	return string(runes[:articleSummaryRunes]) + "…"
+}
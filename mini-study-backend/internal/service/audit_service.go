@@ -1,28 +1,366 @@
 package service
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
 	"github.com/javapub/mini-study/mini-study-backend/internal/model"
 	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+const (
+	auditExportBatchSize   = 500
+	auditExportMaxRows     = 100000
+	auditArchiveBatchSize  = 1000
+	auditArchiveMaxBatches = 1000
+)
+
+// Entity types recorded on structured audit entries.
+const (
+	AuditEntityContent    = "content"
+	AuditEntityExam       = "exam"
+	AuditEntityBanner     = "banner"
+	AuditEntityNotice     = "notice"
+	AuditEntityUser       = "user"
+	AuditEntityUserPoint  = "user_point"
+	AuditEntityCheckpoint = "content_checkpoint"
+
+	AuditEntityCertificateTemplate = "certificate_template"
+	AuditEntityCertification       = "certification"
 )
 
+// auditEntityTypes lists the entity types accepted by the entity history endpoint.
+var auditEntityTypes = map[string]struct{}{
+	AuditEntityContent:             {},
+	AuditEntityExam:                {},
+	AuditEntityBanner:              {},
+	AuditEntityNotice:              {},
+	AuditEntityUser:                {},
+	AuditEntityUserPoint:           {},
+	AuditEntityCheckpoint:          {},
+	AuditEntityCertificateTemplate: {},
+	AuditEntityCertification:       {},
+}
+
+// AuditChange describes a mutation of a single entity. Before is nil for creations and
+// After is nil for deletions; both are snapshots serialised through their JSON tags.
+type AuditChange struct {
+	Action     string
+	Target     string
+	EntityType string
+	EntityID   uint
+	Before     interface{}
+	After      interface{}
+	Payload    interface{}
+}
+
 // AuditService encapsulates audit logging behavior.
 type AuditService struct {
-	repo *repository.AuditRepository
+	repo  *repository.AuditRepository
+	users *repository.UserRepository
 }
 
 // NewAuditService builds an audit service.
-func NewAuditService(repo *repository.AuditRepository) *AuditService {
-	return &AuditService{repo: repo}
+func NewAuditService(repo *repository.AuditRepository, userRepo *repository.UserRepository) *AuditService {
+	return &AuditService{repo: repo, users: userRepo}
 }
 
 // Record stores an audit entry, ignoring persistence errors for flow safety.
-func (s *AuditService) Record(actorID uint, action, target, payload, result string) error {
+// The request id is taken from ctx; when actorID is 0 the authenticated user in ctx is used.
+func (s *AuditService) Record(ctx context.Context, actorID uint, action, target, payload, result string) error {
+	if actorID == 0 {
+		actorID = utils.UserIDFromContext(ctx)
+	}
 	entry := &model.AuditLog{
-		ActorID: actorID,
-		Action:  action,
-		Target:  target,
-		Payload: payload,
-		Result:  result,
+		ActorID:   actorID,
+		Action:    action,
+		Target:    target,
+		Payload:   payload,
+		Result:    result,
+		RequestID: utils.RequestIDFromContext(ctx),
 	}
 	return s.repo.Create(entry)
 }
+
+// RecordChange stores a structured audit entry with field-level before/after diffs.
+// Secret fields are redacted in both the payload and the diff.
+func (s *AuditService) RecordChange(ctx context.Context, actorID uint, change AuditChange) error {
+	return s.repo.Create(changeEntry(ctx, actorID, change))
+}
+
+// RecordChangeTx stores a structured audit entry inside transaction tx, so the entry is rolled
+// back together with the change it describes.
+func (s *AuditService) RecordChangeTx(ctx context.Context, tx *gorm.DB, actorID uint, change AuditChange) error {
+	return s.repo.WithTx(tx).Create(changeEntry(ctx, actorID, change))
+}
+
+func changeEntry(ctx context.Context, actorID uint, change AuditChange) *model.AuditLog {
+	if actorID == 0 {
+		actorID = utils.UserIDFromContext(ctx)
+	}
+	entry := &model.AuditLog{
+		ActorID:    actorID,
+		Action:     change.Action,
+		Target:     change.Target,
+		Payload:    utils.ToRedactedJSONString(change.Payload),
+		Result:     "success",
+		RequestID:  utils.RequestIDFromContext(ctx),
+		EntityType: change.EntityType,
+		EntityID:   change.EntityID,
+	}
+	if changes := utils.DiffFields(change.Before, change.After); len(changes) > 0 {
+		entry.Changes = utils.ToJSONString(changes)
+	}
+	return entry
+}
+
+// AdminEntityHistory returns the audit trail of one entity, newest first.
+func (s *AuditService) AdminEntityHistory(adminID uint, entityType string, entityID uint, query dto.AuditEntityHistoryQuery) (*dto.AdminAuditLogListResponse, error) {
+	if entityType == "" || entityID == 0 {
+		return nil, errors.New("实体类型和实体ID不能为空")
+	}
+	// 拼写错误的实体类型会查不到任何记录，直接报错而不是返回空列表
+	if _, ok := auditEntityTypes[entityType]; !ok {
+		return nil, fmt.Errorf("未知的实体类型: %s", entityType)
+	}
+	return s.AdminSearch(adminID, dto.AdminAuditLogQuery{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+	})
+}
+
+// AdminSearch returns paginated audit logs matching the query.
+func (s *AuditService) AdminSearch(adminID uint, query dto.AdminAuditLogQuery) (*dto.AdminAuditLogListResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	filter, err := s.buildFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := query.Page
+	if page == 0 {
+		page = 1
+	}
+	size := query.PageSize
+	if size == 0 {
+		size = 20
+	}
+
+	logs, total, err := s.repo.Search(filter, page, size)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.toResponses(logs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AdminAuditLogListResponse{
+		Items:      items,
+		Pagination: dto.NewPagination(page, size, total),
+	}, nil
+}
+
+// AdminExportCSV writes all audit logs matching the query to w as CSV (UTF-8 with BOM for Excel),
+// newest first like AdminSearch. Nothing is written when the permission check or validation fails.
+func (s *AuditService) AdminExportCSV(adminID uint, query dto.AdminAuditLogQuery, w io.Writer) error {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return err
+	}
+	filter, err := s.buildFilter(query)
+	if err != nil {
+		return err
+	}
+
+	total, err := s.repo.Count(filter)
+	if err != nil {
+		return err
+	}
+	if total > auditExportMaxRows {
+		return fmt.Errorf("导出数据超过 %d 条，请缩小筛选范围", auditExportMaxRows)
+	}
+
+	if _, err := io.WriteString(w, "\xEF\xBB\xBF"); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"ID", "时间", "操作者ID", "操作者工号", "操作者姓名", "动作", "目标", "结果", "请求ID", "实体类型", "实体ID", "变更", "载荷"}); err != nil {
+		return err
+	}
+
+	err = s.repo.Iterate(filter, auditExportBatchSize, func(batch []model.AuditLog) error {
+		rows, err := s.toResponses(batch)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			changes := ""
+			if len(row.Changes) > 0 {
+				changes = utils.ToJSONString(row.Changes)
+			}
+			if err := writer.Write([]string{
+				strconv.FormatUint(uint64(row.ID), 10),
+				row.CreatedAt.Format(time.RFC3339),
+				strconv.FormatUint(uint64(row.ActorID), 10),
+				row.ActorWorkNo,
+				row.ActorName,
+				row.Action,
+				row.Target,
+				row.Result,
+				row.RequestID,
+				row.EntityType,
+				strconv.FormatUint(uint64(row.EntityID), 10),
+				changes,
+				row.Payload,
+			}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ArchiveExpired moves audit logs older than retention into audit_log_archives.
+func (s *AuditService) ArchiveExpired(retention time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-retention)
+	var total int64
+	for i := 0; i < auditArchiveMaxBatches; i++ {
+		moved, err := s.repo.ArchiveBefore(cutoff, auditArchiveBatchSize)
+		if err != nil {
+			return total, err
+		}
+		total += moved
+		if moved < auditArchiveBatchSize {
+			break
+		}
+	}
+	return total, nil
+}
+
+// RunRetention periodically archives expired audit logs until ctx is cancelled.
+func (s *AuditService) RunRetention(ctx context.Context, retention, interval time.Duration, logger *zap.Logger) {
+	archive := func() {
+		moved, err := s.ArchiveExpired(retention)
+		if err != nil {
+			logger.Error("archive audit logs failed", zap.Error(err))
+			return
+		}
+		if moved > 0 {
+			logger.Info("audit logs archived", zap.Int64("count", moved), zap.Duration("retention", retention))
+		}
+	}
+
+	archive()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			archive()
+		}
+	}
+}
+
+func (s *AuditService) buildFilter(query dto.AdminAuditLogQuery) (repository.AuditLogFilter, error) {
+	if query.StartAt != nil && query.EndAt != nil && !query.EndAt.After(*query.StartAt) {
+		return repository.AuditLogFilter{}, errors.New("结束时间必须晚于开始时间")
+	}
+	return repository.AuditLogFilter{
+		ActorID:    query.ActorID,
+		Action:     query.Action,
+		Target:     query.Target,
+		Result:     query.Result,
+		RequestID:  query.RequestID,
+		EntityType: query.EntityType,
+		EntityID:   query.EntityID,
+		StartAt:    query.StartAt,
+		EndAt:      query.EndAt,
+	}, nil
+}
+
+func (s *AuditService) toResponses(logs []model.AuditLog) ([]dto.AuditLogResponse, error) {
+	actorIDSet := make(map[uint]struct{})
+	for _, entry := range logs {
+		if entry.ActorID > 0 {
+			actorIDSet[entry.ActorID] = struct{}{}
+		}
+	}
+	actorIDs := make([]uint, 0, len(actorIDSet))
+	for id := range actorIDSet {
+		actorIDs = append(actorIDs, id)
+	}
+
+	actors, err := s.users.FindByIDs(actorIDs)
+	if err != nil {
+		return nil, err
+	}
+	actorMap := make(map[uint]model.User, len(actors))
+	for _, actor := range actors {
+		actorMap[actor.ID] = actor
+	}
+
+	resp := make([]dto.AuditLogResponse, 0, len(logs))
+	for _, entry := range logs {
+		actor := actorMap[entry.ActorID]
+		var changes []dto.AuditFieldChange
+		if entry.Changes != "" {
+			if err := json.Unmarshal([]byte(entry.Changes), &changes); err != nil {
+				return nil, err
+			}
+		}
+		resp = append(resp, dto.AuditLogResponse{
+			ID:          entry.ID,
+			ActorID:     entry.ActorID,
+			ActorWorkNo: actor.WorkNo,
+			ActorName:   actor.Name,
+			Action:      entry.Action,
+			Target:      entry.Target,
+			Payload:     entry.Payload,
+			Result:      entry.Result,
+			RequestID:   entry.RequestID,
+			EntityType:  entry.EntityType,
+			EntityID:    entry.EntityID,
+			Changes:     changes,
+			CreatedAt:   entry.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+func (s *AuditService) ensureAdmin(userID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("管理员不存在")
+		}
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return errors.New("仅管理员可操作")
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -23,25 +24,41 @@ func NewBannerService(repo *repository.BannerRepository, userRepo *repository.Us
 	return &BannerService{repo: repo, userRepo: userRepo, audit: audit}
 }
 
-// ListVisible returns banners available to current user role.
-func (s *BannerService) ListVisible(userID uint) ([]model.Banner, error) {
+// ListVisible returns a page of banners available to current user role.
+func (s *BannerService) ListVisible(userID uint, query dto.BannerQuery) ([]model.Banner, dto.Pagination, error) {
 	role, _, err := s.resolveUserRole(userID)
 	if err != nil {
-		return nil, err
+		return nil, dto.Pagination{}, err
+	}
+	page, err := pageRequest(query.PageQuery, repository.BannerSortFields, "sort_order", false)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	banners, total, err := s.repo.SearchVisible(role, time.Now(), page)
+	if err != nil {
+		return nil, dto.Pagination{}, err
 	}
-	return s.repo.ListVisible(role, time.Now())
+	return banners, pagination(page, total), nil
 }
 
 // AdminListBanners lists banners for admin.
-func (s *BannerService) AdminListBanners(adminID uint, status *bool) ([]model.Banner, error) {
+func (s *BannerService) AdminListBanners(adminID uint, query dto.AdminListBannerQuery) ([]model.Banner, dto.Pagination, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
-		return nil, err
+		return nil, dto.Pagination{}, err
+	}
+	page, err := pageRequest(query.PageQuery, repository.BannerSortFields, "sort_order", false)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	banners, total, err := s.repo.SearchAdmin(query.Status, page)
+	if err != nil {
+		return nil, dto.Pagination{}, err
 	}
-	return s.repo.ListAdmin(status)
+	return banners, pagination(page, total), nil
 }
 
 // AdminCreateBanner creates banner.
-func (s *BannerService) AdminCreateBanner(adminID uint, req dto.AdminCreateBannerRequest) (*model.Banner, error) {
+func (s *BannerService) AdminCreateBanner(ctx context.Context, adminID uint, req dto.AdminCreateBannerRequest) (*model.Banner, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
@@ -66,12 +83,18 @@ func (s *BannerService) AdminCreateBanner(adminID uint, req dto.AdminCreateBanne
 	if err := s.repo.Create(banner); err != nil {
 		return nil, err
 	}
-	_ = s.audit.Record(adminID, "create_banner", "banners", banner.Title, "success")
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "create_banner",
+		Target:     "banners",
+		EntityType: AuditEntityBanner,
+		EntityID:   banner.ID,
+		After:      banner,
+	})
 	return banner, nil
 }
 
 // AdminUpdateBanner updates banner fields.
-func (s *BannerService) AdminUpdateBanner(adminID, bannerID uint, req dto.AdminUpdateBannerRequest) (*model.Banner, error) {
+func (s *BannerService) AdminUpdateBanner(ctx context.Context, adminID, bannerID uint, req dto.AdminUpdateBannerRequest) (*model.Banner, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
@@ -80,6 +103,7 @@ func (s *BannerService) AdminUpdateBanner(adminID, bannerID uint, req dto.AdminU
 	if err != nil {
 		return nil, err
 	}
+	before := *banner
 
 	if req.Title != "" {
 		banner.Title = req.Title
@@ -107,7 +131,14 @@ func (s *BannerService) AdminUpdateBanner(adminID, bannerID uint, req dto.AdminU
 	if err := s.repo.Update(banner); err != nil {
 		return nil, err
 	}
-	_ = s.audit.Record(adminID, "update_banner", "banners", banner.Title, "success")
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "update_banner",
+		Target:     "banners",
+		EntityType: AuditEntityBanner,
+		EntityID:   banner.ID,
+		Before:     before,
+		After:      banner,
+	})
 	return banner, nil
 }
 
@@ -0,0 +1,513 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/certificate"
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
+)
+
+var (
+	// ErrNoCertificateTemplate is returned when neither the exam nor the default template can issue a certificate.
+	ErrNoCertificateTemplate = errors.New("该考试未配置证书模板")
+	// ErrCertificateAttemptNotFound is returned when the attempt of a certificate does not exist.
+	ErrCertificateAttemptNotFound = errors.New("考试记录不存在")
+	// ErrCertificateForbidden is returned when the user may not see another user's certificate.
+	ErrCertificateForbidden = errors.New("无权查看该证书")
+)
+
+// CertificateOptions configures certificate serial numbers and the printed verification link.
+type CertificateOptions struct {
+	SerialPrefix string
+	VerifyURL    string
+}
+
+// CertificateService issues PDF certificates for passed exam attempts from admin-designed
+// templates and verifies serial numbers. Each attempt gets at most one certificate; its PDF is
+// rendered once and kept in storage, so later template edits do not change issued certificates.
+type CertificateService struct {
+	certificates *repository.CertificateRepository
+	attempts     *repository.ExamAttemptRepository
+	exams        *repository.ExamRepository
+	users        *repository.UserRepository
+	relations    *repository.ManagerEmployeeRepository
+	store        storage.Storage
+	audit        *AuditService
+	opts         CertificateOptions
+}
+
+// NewCertificateService builds a CertificateService.
+func NewCertificateService(
+	certificateRepo *repository.CertificateRepository,
+	attemptRepo *repository.ExamAttemptRepository,
+	examRepo *repository.ExamRepository,
+	userRepo *repository.UserRepository,
+	relationRepo *repository.ManagerEmployeeRepository,
+	store storage.Storage,
+	audit *AuditService,
+	opts CertificateOptions,
+) *CertificateService {
+	return &CertificateService{
+		certificates: certificateRepo,
+		attempts:     attemptRepo,
+		exams:        examRepo,
+		users:        userRepo,
+		relations:    relationRepo,
+		store:        store,
+		audit:        audit,
+		opts:         opts,
+	}
+}
+
+// ListTemplates returns every certificate template.
+func (s *CertificateService) ListTemplates(adminID uint) (*dto.CertificateTemplateListResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	templates, err := s.certificates.ListTemplates()
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.CertificateTemplateResponse, 0, len(templates))
+	for i := range templates {
+		items = append(items, s.templateResponse(&templates[i]))
+	}
+	return &dto.CertificateTemplateListResponse{Items: items, Placeholders: certificate.Placeholders}, nil
+}
+
+// CreateTemplate adds a certificate template.
+func (s *CertificateService) CreateTemplate(ctx context.Context, adminID uint, req dto.CertificateTemplateRequest) (*dto.CertificateTemplateResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	template := &model.CertificateTemplate{CreatorID: adminID, Enabled: true}
+	if err := s.applyTemplate(template, req); err != nil {
+		return nil, err
+	}
+	if err := s.certificates.CreateTemplate(template); err != nil {
+		return nil, err
+	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "create_certificate_template",
+		Target:     "certificate_templates",
+		EntityType: AuditEntityCertificateTemplate,
+		EntityID:   template.ID,
+		After:      template,
+	})
+	resp := s.templateResponse(template)
+	return &resp, nil
+}
+
+// UpdateTemplate replaces a template. Certificates already issued keep their original layout.
+func (s *CertificateService) UpdateTemplate(ctx context.Context, adminID, templateID uint, req dto.CertificateTemplateRequest) (*dto.CertificateTemplateResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	template, err := s.findTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+	before := *template
+	if err := s.applyTemplate(template, req); err != nil {
+		return nil, err
+	}
+	if err := s.certificates.UpdateTemplate(template); err != nil {
+		return nil, err
+	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "update_certificate_template",
+		Target:     "certificate_templates",
+		EntityType: AuditEntityCertificateTemplate,
+		EntityID:   template.ID,
+		Before:     &before,
+		After:      template,
+	})
+	resp := s.templateResponse(template)
+	return &resp, nil
+}
+
+// DeleteTemplate removes a template.
+func (s *CertificateService) DeleteTemplate(ctx context.Context, adminID, templateID uint) error {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return err
+	}
+	template, err := s.findTemplate(templateID)
+	if err != nil {
+		return err
+	}
+	if err := s.certificates.DeleteTemplate(template.ID); err != nil {
+		return err
+	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "delete_certificate_template",
+		Target:     "certificate_templates",
+		EntityType: AuditEntityCertificateTemplate,
+		EntityID:   template.ID,
+		Before:     template,
+	})
+	return nil
+}
+
+// PreviewTemplate renders a template with sample data.
+func (s *CertificateService) PreviewTemplate(adminID, templateID uint, w io.Writer) error {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return err
+	}
+	template, err := s.findTemplate(templateID)
+	if err != nil {
+		return err
+	}
+	examTitle := "示例考试"
+	if template.ExamID > 0 {
+		if exam, err := s.exams.FindByID(template.ExamID); err == nil {
+			examTitle = exam.Title
+		}
+	}
+	now := time.Now()
+	serial := fmt.Sprintf("%s-%s-00000000", s.opts.SerialPrefix, now.Format("20060102"))
+	return certificate.Render(w, templateDesign(template), certificate.Data{
+		HolderName: "张三",
+		WorkNo:     "E0001",
+		ExamTitle:  examTitle,
+		Score:      95,
+		TotalScore: 100,
+		PassedAt:   now,
+		Serial:     serial,
+		VerifyURL:  certificate.VerifyLink(s.opts.VerifyURL, serial),
+	})
+}
+
+// Issue returns the certificate of a passed attempt, rendering and storing it on first use.
+func (s *CertificateService) Issue(ctx context.Context, attempt *model.ExamAttempt) (*model.Certificate, error) {
+	if !attempt.Pass {
+		return nil, errors.New("考试未通过，无法颁发证书")
+	}
+	if existing, err := s.certificates.FindByAttempt(attempt.ID); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	exam := attempt.Exam
+	if exam.ID == 0 {
+		loaded, err := s.exams.FindByID(attempt.ExamID)
+		if err != nil {
+			return nil, err
+		}
+		exam = *loaded
+	}
+	template, err := s.certificates.FindTemplateForExam(exam.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoCertificateTemplate
+		}
+		return nil, err
+	}
+	holder, err := s.users.FindByID(attempt.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	passedAt := attempt.CreatedAt
+	if attempt.SubmittedAt != nil {
+		passedAt = *attempt.SubmittedAt
+	}
+	serial, err := certificate.NewSerial(s.opts.SerialPrefix, passedAt)
+	if err != nil {
+		return nil, err
+	}
+	issued := &model.Certificate{
+		Serial:     serial,
+		AttemptID:  attempt.ID,
+		UserID:     holder.ID,
+		ExamID:     exam.ID,
+		TemplateID: template.ID,
+		HolderName: holder.Name,
+		WorkNo:     holder.WorkNo,
+		ExamTitle:  exam.Title,
+		Score:      attempt.Score,
+		TotalScore: exam.TotalScore,
+		IssuedAt:   passedAt,
+		FileKey:    fmt.Sprintf("%scertificates/%s/%s.pdf", storage.PrivatePrefix, passedAt.Format("2006"), serial),
+	}
+
+	var buf bytes.Buffer
+	if err := certificate.Render(&buf, templateDesign(template), certificateData(issued, s.opts.VerifyURL)); err != nil {
+		return nil, err
+	}
+	issued.FileSize = int64(buf.Len())
+	if err := s.store.Put(ctx, issued.FileKey, bytes.NewReader(buf.Bytes()), issued.FileSize, "application/pdf"); err != nil {
+		return nil, err
+	}
+	if err := s.certificates.Create(issued); err != nil {
+		// 并发颁发时以先写入的证书为准
+		_ = s.store.Delete(ctx, issued.FileKey)
+		if existing, findErr := s.certificates.FindByAttempt(attempt.ID); findErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return issued, nil
+}
+
+// ListMine returns the certificates held by the user.
+func (s *CertificateService) ListMine(userID uint) (*dto.CertificateListResponse, error) {
+	certificates, err := s.certificates.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.CertificateResponse, 0, len(certificates))
+	for i := range certificates {
+		items = append(items, s.certificateResponse(&certificates[i]))
+	}
+	return &dto.CertificateListResponse{Items: items}, nil
+}
+
+// Open returns the PDF of an attempt's certificate, issuing it first if needed. The holder, their
+// managers and admins may download it.
+func (s *CertificateService) Open(ctx context.Context, userID, attemptID uint) (io.ReadCloser, *storage.ObjectInfo, *model.Certificate, error) {
+	attempt, err := s.attempts.FindByID(attemptID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, ErrCertificateAttemptNotFound
+		}
+		return nil, nil, nil, err
+	}
+	if err := s.ensureCanView(userID, attempt.UserID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	issued, err := s.Issue(ctx, attempt)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	body, info, err := s.store.Get(ctx, issued.FileKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return body, info, issued, nil
+}
+
+// Verify looks up a serial number. Unknown serials are reported as invalid rather than as errors.
+func (s *CertificateService) Verify(serial string) (*dto.CertificateVerifyResponse, error) {
+	serial = certificate.NormalizeSerial(serial)
+	if serial == "" {
+		return nil, errors.New("请输入证书编号")
+	}
+	issued, err := s.certificates.FindBySerial(serial)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &dto.CertificateVerifyResponse{Valid: false, Serial: serial}, nil
+		}
+		return nil, err
+	}
+	issuedAt := issued.IssuedAt
+	return &dto.CertificateVerifyResponse{
+		Valid:      true,
+		Serial:     issued.Serial,
+		HolderName: maskName(issued.HolderName),
+		WorkNo:     maskWorkNo(issued.WorkNo),
+		ExamTitle:  issued.ExamTitle,
+		Score:      issued.Score,
+		TotalScore: issued.TotalScore,
+		IssuedAt:   &issuedAt,
+	}, nil
+}
+
+// serialsByAttempts maps attempts to the serials of their certificates.
+func (s *CertificateService) serialsByAttempts(attemptIDs []uint) map[uint]string {
+	serials, err := s.certificates.SerialsByAttempts(attemptIDs)
+	if err != nil {
+		return map[uint]string{}
+	}
+	return serials
+}
+
+func (s *CertificateService) applyTemplate(template *model.CertificateTemplate, req dto.CertificateTemplateRequest) error {
+	template.Name = strings.TrimSpace(req.Name)
+	template.ExamID = req.ExamID
+	template.Title = strings.TrimSpace(req.Title)
+	template.Body = strings.TrimSpace(req.Body)
+	template.Issuer = strings.TrimSpace(req.Issuer)
+	template.Signer = strings.TrimSpace(req.Signer)
+	template.Orientation = req.Orientation
+	if template.Orientation == "" {
+		template.Orientation = certificate.Landscape
+	}
+	template.AccentColor = strings.ToUpper(req.AccentColor)
+	if template.AccentColor == "" {
+		template.AccentColor = "#B8860B"
+	}
+	if req.Enabled != nil {
+		template.Enabled = *req.Enabled
+	}
+
+	if template.Name == "" {
+		return errors.New("模板名称不能为空")
+	}
+	if template.Title == "" {
+		template.Title = "合格证书"
+	}
+	if template.ExamID > 0 {
+		if _, err := s.exams.FindByID(template.ExamID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("试卷不存在")
+			}
+			return err
+		}
+	}
+	if template.Enabled {
+		exists, err := s.certificates.EnabledTemplateExists(template.ExamID, template.ID)
+		if err != nil {
+			return err
+		}
+		if exists && template.ExamID > 0 {
+			return errors.New("该试卷已有启用的证书模板")
+		}
+		if exists {
+			return errors.New("已有启用的默认证书模板")
+		}
+	}
+	return nil
+}
+
+func (s *CertificateService) findTemplate(templateID uint) (*model.CertificateTemplate, error) {
+	template, err := s.certificates.FindTemplate(templateID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("证书模板不存在")
+		}
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *CertificateService) templateResponse(template *model.CertificateTemplate) dto.CertificateTemplateResponse {
+	resp := dto.CertificateTemplateResponse{
+		ID:          template.ID,
+		Name:        template.Name,
+		ExamID:      template.ExamID,
+		Title:       template.Title,
+		Body:        template.Body,
+		Issuer:      template.Issuer,
+		Signer:      template.Signer,
+		Orientation: template.Orientation,
+		AccentColor: template.AccentColor,
+		Enabled:     template.Enabled,
+		CreatedAt:   template.CreatedAt,
+		UpdatedAt:   template.UpdatedAt,
+	}
+	if template.ExamID > 0 {
+		if exam, err := s.exams.FindByID(template.ExamID); err == nil {
+			resp.ExamTitle = exam.Title
+		}
+	}
+	return resp
+}
+
+func (s *CertificateService) certificateResponse(issued *model.Certificate) dto.CertificateResponse {
+	return dto.CertificateResponse{
+		Serial:     issued.Serial,
+		AttemptID:  issued.AttemptID,
+		ExamID:     issued.ExamID,
+		ExamTitle:  issued.ExamTitle,
+		HolderName: issued.HolderName,
+		Score:      issued.Score,
+		TotalScore: issued.TotalScore,
+		IssuedAt:   issued.IssuedAt,
+		FileSize:   issued.FileSize,
+		VerifyURL:  certificate.VerifyLink(s.opts.VerifyURL, issued.Serial),
+	}
+}
+
+// ensureCanView allows the holder, their managers and admins.
+func (s *CertificateService) ensureCanView(userID, holderID uint) error {
+	if userID == holderID {
+		return nil
+	}
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	switch user.Role {
+	case model.RoleAdmin:
+		return nil
+	case model.RoleManager:
+		managerIDs, err := s.relations.ListManagerIDsByEmployee(holderID)
+		if err != nil {
+			return err
+		}
+		for _, id := range managerIDs {
+			if id == userID {
+				return nil
+			}
+		}
+	}
+	return ErrCertificateForbidden
+}
+
+func (s *CertificateService) ensureAdmin(userID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return errors.New("仅管理员可操作")
+	}
+	return nil
+}
+
+func templateDesign(template *model.CertificateTemplate) certificate.Design {
+	return certificate.Design{
+		Title:       template.Title,
+		Body:        template.Body,
+		Issuer:      template.Issuer,
+		Signer:      template.Signer,
+		Orientation: template.Orientation,
+		AccentColor: template.AccentColor,
+	}
+}
+
+func certificateData(issued *model.Certificate, verifyURL string) certificate.Data {
+	return certificate.Data{
+		HolderName: issued.HolderName,
+		WorkNo:     issued.WorkNo,
+		ExamTitle:  issued.ExamTitle,
+		Score:      issued.Score,
+		TotalScore: issued.TotalScore,
+		PassedAt:   issued.IssuedAt,
+		Serial:     issued.Serial,
+		VerifyURL:  certificate.VerifyLink(verifyURL, issued.Serial),
+	}
+}
+
+// maskName keeps the first character of a name, e.g. 张三丰 -> 张**.
+func maskName(name string) string {
+	n := utf8.RuneCountInString(name)
+	if n <= 1 {
+		return name
+	}
+	first, _ := utf8.DecodeRuneInString(name)
+	return string(first) + strings.Repeat("*", n-1)
+}
+
+// maskWorkNo keeps the first two and the last character of a work number.
+func maskWorkNo(workNo string) string {
+	runes := []rune(workNo)
+	if len(runes) <= 3 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:2]) + strings.Repeat("*", len(runes)-3) + string(runes[len(runes)-1:])
+}
@@ -0,0 +1,715 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+)
+
+// Certification statuses of a user.
+const (
+	CertificationValid        = "valid"
+	CertificationExpiring     = "expiring"
+	CertificationExpired      = "expired"
+	CertificationNotCertified = "not_certified"
+)
+
+const (
+	certificationLeaseName     = "certification_reminders"
+	certificationReminderBatch = 200
+	defaultCertificationWindow = 30
+	defaultExpiringDays        = 30
+)
+
+// CertificationExpiry returns when a certification passed at passedAt expires.
+func CertificationExpiry(passedAt time.Time, validityMonths int) time.Time {
+	return passedAt.AddDate(0, validityMonths, 0)
+}
+
+// CertificationRenewalOpensAt returns when the exam reopens for recertification, windowDays
+// before expiry.
+func CertificationRenewalOpensAt(expiresAt time.Time, windowDays int) time.Time {
+	return expiresAt.AddDate(0, 0, -windowDays)
+}
+
+// CertificationStatus returns the status at now of a certification expiring at expiresAt:
+// expired from expiresAt on, expiring inside the renewal window and valid before it.
+func CertificationStatus(expiresAt time.Time, windowDays int, now time.Time) string {
+	switch {
+	case !now.Before(expiresAt):
+		return CertificationExpired
+	case !now.Before(CertificationRenewalOpensAt(expiresAt, windowDays)):
+		return CertificationExpiring
+	default:
+		return CertificationValid
+	}
+}
+
+// CertificationService manages certifications: an exam that must be passed again after a
+// validity period. It tracks when each user last passed, reopens the exam for the user in the
+// renewal window, reminds users before and at expiry, and lists expiring teams for managers.
+//
+// Every replica runs the reminder loop, but only the holder of a database lease sends
+// reminders, and each reminder is recorded with a conditional update before it is sent.
+type CertificationService struct {
+	certifications *repository.CertificationRepository
+	attempts       *repository.ExamAttemptRepository
+	exams          *repository.ExamRepository
+	users          *repository.UserRepository
+	relations      *repository.ManagerEmployeeRepository
+	notifications  *NotificationService
+	leases         *repository.SchedulerLeaseRepository
+	audit          *AuditService
+	holder         string
+	leaseTTL       time.Duration
+}
+
+// NewCertificationService builds a CertificationService.
+func NewCertificationService(
+	certificationRepo *repository.CertificationRepository,
+	attemptRepo *repository.ExamAttemptRepository,
+	examRepo *repository.ExamRepository,
+	userRepo *repository.UserRepository,
+	relationRepo *repository.ManagerEmployeeRepository,
+	notifications *NotificationService,
+	leaseRepo *repository.SchedulerLeaseRepository,
+	audit *AuditService,
+	leaseTTL time.Duration,
+) *CertificationService {
+	host, _ := os.Hostname()
+	return &CertificationService{
+		certifications: certificationRepo,
+		attempts:       attemptRepo,
+		exams:          examRepo,
+		users:          userRepo,
+		relations:      relationRepo,
+		notifications:  notifications,
+		leases:         leaseRepo,
+		audit:          audit,
+		holder:         fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8]),
+		leaseTTL:       leaseTTL,
+	}
+}
+
+// List returns every certification.
+func (s *CertificationService) List(adminID uint) (*dto.CertificationListResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	certifications, err := s.certifications.List()
+	if err != nil {
+		return nil, err
+	}
+	titles := s.examTitles(certifications)
+	items := make([]dto.CertificationResponse, 0, len(certifications))
+	for i := range certifications {
+		resp := certificationResponse(&certifications[i], titles)
+		if held, err := s.certifications.ListByCertification(certifications[i].ID); err == nil {
+			resp.HolderCount = len(held)
+		}
+		items = append(items, resp)
+	}
+	return &dto.CertificationListResponse{Items: items}, nil
+}
+
+// Create adds a certification and grants it to everyone who already passed its exam.
+func (s *CertificationService) Create(ctx context.Context, adminID uint, req dto.CertificationRequest) (*dto.CertificationResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	certification := &model.Certification{CreatorID: adminID, Enabled: true, RenewalWindowDays: defaultCertificationWindow}
+	if err := s.apply(certification, req); err != nil {
+		return nil, err
+	}
+	if err := s.certifications.Create(certification); err != nil {
+		return nil, err
+	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "create_certification",
+		Target:     "certifications",
+		EntityType: AuditEntityCertification,
+		EntityID:   certification.ID,
+		After:      certification,
+	})
+	held, err := s.sync(certification)
+	if err != nil {
+		return nil, err
+	}
+	resp := certificationResponse(certification, s.examTitles([]model.Certification{*certification}))
+	resp.HolderCount = held
+	return &resp, nil
+}
+
+// Update replaces a certification and recomputes expiry dates from past passes. Linking another
+// exam drops what users held of the old one.
+func (s *CertificationService) Update(ctx context.Context, adminID, certificationID uint, req dto.CertificationRequest) (*dto.CertificationResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	certification, err := s.find(certificationID)
+	if err != nil {
+		return nil, err
+	}
+	before := *certification
+	if err := s.apply(certification, req); err != nil {
+		return nil, err
+	}
+	if err := s.certifications.Update(certification); err != nil {
+		return nil, err
+	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "update_certification",
+		Target:     "certifications",
+		EntityType: AuditEntityCertification,
+		EntityID:   certification.ID,
+		Before:     &before,
+		After:      certification,
+	})
+	if before.ExamID != certification.ExamID {
+		if err := s.certifications.DeleteUserCertifications(certification.ID); err != nil {
+			return nil, err
+		}
+	}
+	held, err := s.sync(certification)
+	if err != nil {
+		return nil, err
+	}
+	resp := certificationResponse(certification, s.examTitles([]model.Certification{*certification}))
+	resp.HolderCount = held
+	return &resp, nil
+}
+
+// Delete removes a certification. Exam attempts are kept.
+func (s *CertificationService) Delete(ctx context.Context, adminID, certificationID uint) error {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return err
+	}
+	certification, err := s.find(certificationID)
+	if err != nil {
+		return err
+	}
+	if err := s.certifications.Delete(certification.ID); err != nil {
+		return err
+	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "delete_certification",
+		Target:     "certifications",
+		EntityType: AuditEntityCertification,
+		EntityID:   certification.ID,
+		Before:     certification,
+	})
+	return nil
+}
+
+// RecordPass renews the certification linked to a passed attempt's exam. It returns nil when
+// the exam backs no enabled certification.
+func (s *CertificationService) RecordPass(attempt *model.ExamAttempt) (*model.UserCertification, error) {
+	if !attempt.Pass {
+		return nil, nil
+	}
+	certification, err := s.certifications.FindEnabledByExam(attempt.ExamID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	held := userCertification(certification, attempt)
+	if err := s.certifications.SaveUserCertification(held); err != nil {
+		return nil, err
+	}
+	return held, nil
+}
+
+// CanRetake reports whether a user who already took an exam may take it again: the exam must
+// back an enabled certification the user holds, and its renewal window must have opened.
+func (s *CertificationService) CanRetake(userID, examID uint, now time.Time) (bool, error) {
+	certification, err := s.certifications.FindEnabledByExam(examID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	held, err := s.certifications.FindUserCertification(certification.ID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return !now.Before(CertificationRenewalOpensAt(held.ExpiresAt, certification.RenewalWindowDays)), nil
+}
+
+// ListMine returns the enabled certifications whose exam is meant for the user, plus any other
+// certification the user holds, with the user's status in each.
+func (s *CertificationService) ListMine(userID uint) (*dto.MyCertificationListResponse, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	certifications, err := s.certifications.ListEnabled()
+	if err != nil {
+		return nil, err
+	}
+	held, err := s.heldByCertification(userID)
+	if err != nil {
+		return nil, err
+	}
+	exams := s.examsByID(certifications)
+
+	now := time.Now()
+	items := make([]dto.MyCertificationItem, 0, len(certifications))
+	for i := range certifications {
+		certification := &certifications[i]
+		exam, ok := exams[certification.ExamID]
+		if !ok {
+			continue
+		}
+		record, holds := held[certification.ID]
+		if !holds && exam.TargetRole != "all" && user.Role != model.RoleAdmin && exam.TargetRole != user.Role {
+			continue
+		}
+		item := dto.MyCertificationItem{
+			CertificationID: certification.ID,
+			Name:            certification.Name,
+			Description:     certification.Description,
+			ExamID:          certification.ExamID,
+			ExamTitle:       exam.Title,
+			ValidityMonths:  certification.ValidityMonths,
+			Status:          CertificationNotCertified,
+		}
+		if holds {
+			passedAt, expiresAt := record.PassedAt, record.ExpiresAt
+			opensAt := CertificationRenewalOpensAt(expiresAt, certification.RenewalWindowDays)
+			days := daysRemaining(expiresAt, now)
+			item.PassedAt = &passedAt
+			item.ExpiresAt = &expiresAt
+			item.RenewalOpensAt = &opensAt
+			item.DaysRemaining = &days
+			item.Status = CertificationStatus(expiresAt, certification.RenewalWindowDays, now)
+			item.CanRetake = !now.Before(opensAt)
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return certificationStatusRank(items[i].Status) < certificationStatusRank(items[j].Status)
+	})
+	return &dto.MyCertificationListResponse{Items: items}, nil
+}
+
+// TeamExpiring lists the manager's team members whose certifications expire within the
+// requested days or have expired.
+func (s *CertificationService) TeamExpiring(managerID uint, query dto.CertificationExpiringQuery) (*dto.CertificationExpiringResponse, error) {
+	manager, err := s.users.FindByID(managerID)
+	if err != nil {
+		return nil, err
+	}
+	if manager.Role != model.RoleManager && manager.Role != model.RoleAdmin {
+		return nil, errors.New("仅店长可查看")
+	}
+	employeeIDs, err := s.relations.ListEmployeeIDsByManager(managerID)
+	if err != nil {
+		return nil, err
+	}
+	if employeeIDs == nil {
+		employeeIDs = []uint{}
+	}
+	return s.expiring(employeeIDs, query)
+}
+
+// AdminExpiring lists certifications expiring within the requested days across all users, or
+// across one manager's team when manager_id is given.
+func (s *CertificationService) AdminExpiring(adminID uint, query dto.CertificationExpiringQuery) (*dto.CertificationExpiringResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	var userIDs []uint
+	if query.ManagerID > 0 {
+		employeeIDs, err := s.relations.ListEmployeeIDsByManager(query.ManagerID)
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append([]uint{}, employeeIDs...)
+	}
+	return s.expiring(userIDs, query)
+}
+
+// RunReminders sends due recertification reminders every interval until ctx is cancelled.
+func (s *CertificationService) RunReminders(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	run := func() {
+		sent, err := s.SendReminders(time.Now())
+		if err != nil {
+			logger.Error("send certification reminders", zap.Error(err))
+		}
+		if sent > 0 {
+			logger.Info("certification reminders sent", zap.Int("count", sent))
+		}
+	}
+
+	run()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			_ = s.leases.Release(certificationLeaseName, s.holder)
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
+
+// SendReminders notifies holders whose certification entered its renewal window or expired, if
+// this replica holds the reminder lease, and returns how many reminders were sent. Each holder
+// is reminded once per pass for each of the two events.
+func (s *CertificationService) SendReminders(now time.Time) (int, error) {
+	held, err := s.leases.Acquire(certificationLeaseName, s.holder, now, now.Add(s.leaseTTL))
+	if err != nil || !held {
+		return 0, err
+	}
+
+	certifications, err := s.certifications.ListEnabled()
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	var errs []error
+	for i := range certifications {
+		certification := &certifications[i]
+		expired, err := s.certifications.ListExpiredDue(certification.ID, now, certificationReminderBatch)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for j := range expired {
+			ok, err := s.remind(certification, &expired[j], "expired_notified_at", NotificationCertificationExpired,
+				fmt.Sprintf("您的「%s」认证已过期，请尽快重新参加考试", certification.Name), now)
+			if err != nil {
+				errs = append(errs, err)
+			} else if ok {
+				sent++
+			}
+		}
+
+		until := now.AddDate(0, 0, certification.RenewalWindowDays)
+		expiring, err := s.certifications.ListRenewalDue(certification.ID, now, until, certificationReminderBatch)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for j := range expiring {
+			record := &expiring[j]
+			ok, err := s.remind(certification, record, "renewal_notified_at", NotificationCertificationExpiring,
+				fmt.Sprintf("您的「%s」认证将于%s到期，现已可重新参加考试", certification.Name, record.ExpiresAt.Format("2006年1月2日")), now)
+			if err != nil {
+				errs = append(errs, err)
+			} else if ok {
+				sent++
+			}
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// remind records a reminder on the holder's row and then notifies them. A reminder already
+// recorded by another run is skipped.
+func (s *CertificationService) remind(certification *model.Certification, record *model.UserCertification, column, kind, summary string, now time.Time) (bool, error) {
+	claimed, err := s.certifications.MarkNotified(record.ID, column, now)
+	if err != nil || !claimed {
+		return false, err
+	}
+	if err := s.notifications.Notify(record.UserID, certification.CreatorID, kind, "certification", certification.ID, summary); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// examStates returns the user's certification status for each exam backing an enabled
+// certification.
+func (s *CertificationService) examStates(userID uint, now time.Time) map[uint]certificationExamState {
+	states := map[uint]certificationExamState{}
+	certifications, err := s.certifications.ListEnabled()
+	if err != nil || len(certifications) == 0 {
+		return states
+	}
+	held, err := s.heldByCertification(userID)
+	if err != nil {
+		return states
+	}
+	for i := range certifications {
+		certification := &certifications[i]
+		state := certificationExamState{Status: CertificationNotCertified}
+		if record, ok := held[certification.ID]; ok {
+			expiresAt := record.ExpiresAt
+			state.Status = CertificationStatus(expiresAt, certification.RenewalWindowDays, now)
+			state.ExpiresAt = &expiresAt
+			state.CanRetake = !now.Before(CertificationRenewalOpensAt(expiresAt, certification.RenewalWindowDays))
+		}
+		states[certification.ExamID] = state
+	}
+	return states
+}
+
+// certificationExamState is a user's certification status on one exam.
+type certificationExamState struct {
+	Status    string
+	ExpiresAt *time.Time
+	CanRetake bool
+}
+
+func (s *CertificationService) expiring(userIDs []uint, query dto.CertificationExpiringQuery) (*dto.CertificationExpiringResponse, error) {
+	days := query.Days
+	if days <= 0 {
+		days = defaultExpiringDays
+	}
+	resp := &dto.CertificationExpiringResponse{Days: days, Items: []dto.CertificationExpiringItem{}}
+	if userIDs != nil && len(userIDs) == 0 {
+		return resp, nil
+	}
+
+	certifications, err := s.certifications.ListEnabled()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*model.Certification, len(certifications))
+	certificationIDs := make([]uint, 0, len(certifications))
+	for i := range certifications {
+		if query.CertificationID > 0 && certifications[i].ID != query.CertificationID {
+			continue
+		}
+		byID[certifications[i].ID] = &certifications[i]
+		certificationIDs = append(certificationIDs, certifications[i].ID)
+	}
+	if len(certificationIDs) == 0 {
+		return resp, nil
+	}
+
+	now := time.Now()
+	records, err := s.certifications.ListExpiringBefore(certificationIDs, userIDs, now.AddDate(0, 0, days))
+	if err != nil {
+		return nil, err
+	}
+	holderIDs := make([]uint, 0, len(records))
+	for _, record := range records {
+		holderIDs = append(holderIDs, record.UserID)
+	}
+	users, err := s.users.FindByIDs(holderIDs)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[uint]model.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	for _, record := range records {
+		certification := byID[record.CertificationID]
+		user, ok := usersByID[record.UserID]
+		if certification == nil || !ok {
+			continue
+		}
+		resp.Items = append(resp.Items, dto.CertificationExpiringItem{
+			UserID:            user.ID,
+			WorkNo:            user.WorkNo,
+			Name:              user.Name,
+			CertificationID:   certification.ID,
+			CertificationName: certification.Name,
+			ExamID:            certification.ExamID,
+			Status:            CertificationStatus(record.ExpiresAt, certification.RenewalWindowDays, now),
+			PassedAt:          record.PassedAt,
+			ExpiresAt:         record.ExpiresAt,
+			DaysRemaining:     daysRemaining(record.ExpiresAt, now),
+		})
+	}
+	return resp, nil
+}
+
+// sync grants the certification from each user's latest passed attempt of its exam and returns
+// the number of holders. Rows whose pass and expiry did not change keep their reminder times.
+func (s *CertificationService) sync(certification *model.Certification) (int, error) {
+	attempts, err := s.attempts.ListLatestPassedByExam(certification.ExamID)
+	if err != nil {
+		return 0, err
+	}
+	existing, err := s.certifications.ListByCertification(certification.ID)
+	if err != nil {
+		return 0, err
+	}
+	current := make(map[uint]model.UserCertification, len(existing))
+	for _, record := range existing {
+		current[record.UserID] = record
+	}
+
+	for i := range attempts {
+		held := userCertification(certification, &attempts[i])
+		if record, ok := current[held.UserID]; ok && record.AttemptID == held.AttemptID && record.ExpiresAt.Equal(held.ExpiresAt) {
+			continue
+		}
+		if err := s.certifications.SaveUserCertification(held); err != nil {
+			return 0, err
+		}
+	}
+	return len(attempts), nil
+}
+
+func (s *CertificationService) apply(certification *model.Certification, req dto.CertificationRequest) error {
+	certification.Name = strings.TrimSpace(req.Name)
+	certification.Description = strings.TrimSpace(req.Description)
+	certification.ExamID = req.ExamID
+	certification.ValidityMonths = req.ValidityMonths
+	if req.RenewalWindowDays != nil {
+		certification.RenewalWindowDays = *req.RenewalWindowDays
+	}
+	if req.Enabled != nil {
+		certification.Enabled = *req.Enabled
+	}
+
+	if certification.Name == "" {
+		return errors.New("认证名称不能为空")
+	}
+	if certification.ValidityMonths <= 0 {
+		return errors.New("有效期必须大于0")
+	}
+	if certification.RenewalWindowDays > certification.ValidityMonths*28 {
+		return errors.New("重新认证窗口不能超过有效期")
+	}
+	if _, err := s.exams.FindByID(certification.ExamID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("试卷不存在")
+		}
+		return err
+	}
+	if certification.Enabled {
+		exists, err := s.certifications.EnabledExists(certification.ExamID, certification.ID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.New("该试卷已关联启用的认证")
+		}
+	}
+	return nil
+}
+
+func (s *CertificationService) find(certificationID uint) (*model.Certification, error) {
+	certification, err := s.certifications.FindByID(certificationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("认证不存在")
+		}
+		return nil, err
+	}
+	return certification, nil
+}
+
+func (s *CertificationService) heldByCertification(userID uint) (map[uint]model.UserCertification, error) {
+	records, err := s.certifications.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	held := make(map[uint]model.UserCertification, len(records))
+	for _, record := range records {
+		held[record.CertificationID] = record
+	}
+	return held, nil
+}
+
+func (s *CertificationService) examsByID(certifications []model.Certification) map[uint]model.ExamPaper {
+	ids := make([]uint, 0, len(certifications))
+	for _, certification := range certifications {
+		ids = append(ids, certification.ExamID)
+	}
+	result := make(map[uint]model.ExamPaper, len(ids))
+	exams, err := s.exams.FindByIDs(ids)
+	if err != nil {
+		return result
+	}
+	for _, exam := range exams {
+		result[exam.ID] = exam
+	}
+	return result
+}
+
+func (s *CertificationService) examTitles(certifications []model.Certification) map[uint]string {
+	exams := s.examsByID(certifications)
+	titles := make(map[uint]string, len(exams))
+	for id, exam := range exams {
+		titles[id] = exam.Title
+	}
+	return titles
+}
+
+func (s *CertificationService) ensureAdmin(userID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return errors.New("仅管理员可操作")
+	}
+	return nil
+}
+
+func certificationResponse(certification *model.Certification, examTitles map[uint]string) dto.CertificationResponse {
+	return dto.CertificationResponse{
+		ID:                certification.ID,
+		Name:              certification.Name,
+		Description:       certification.Description,
+		ExamID:            certification.ExamID,
+		ExamTitle:         examTitles[certification.ExamID],
+		ValidityMonths:    certification.ValidityMonths,
+		RenewalWindowDays: certification.RenewalWindowDays,
+		Enabled:           certification.Enabled,
+		CreatedAt:         certification.CreatedAt,
+		UpdatedAt:         certification.UpdatedAt,
+	}
+}
+
+// userCertification builds the holder row granted by a passed attempt, with no reminders sent.
+func userCertification(certification *model.Certification, attempt *model.ExamAttempt) *model.UserCertification {
+	passedAt := attempt.CreatedAt
+	if attempt.SubmittedAt != nil {
+		passedAt = *attempt.SubmittedAt
+	}
+	return &model.UserCertification{
+		CertificationID: certification.ID,
+		UserID:          attempt.UserID,
+		AttemptID:       attempt.ID,
+		PassedAt:        passedAt,
+		ExpiresAt:       CertificationExpiry(passedAt, certification.ValidityMonths),
+	}
+}
+
+// daysRemaining counts whole days until expiresAt, rounding up; it is negative once expired.
+func daysRemaining(expiresAt, now time.Time) int {
+	return int(math.Ceil(expiresAt.Sub(now).Hours() / 24))
+}
+
+// certificationStatusRank puts the certifications that need action first.
+func certificationStatusRank(status string) int {
+	switch status {
+	case CertificationExpired:
+		return 0
+	case CertificationExpiring:
+		return 1
+	case CertificationNotCertified:
+		return 2
+	default:
+		return 3
+	}
+}
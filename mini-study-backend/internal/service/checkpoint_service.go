@@ -0,0 +1,420 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+)
+
+// CheckpointService manages the quiz checkpoints embedded in contents and grades learners'
+// answers. Passing a checkpoint unlocks the learning after it (see LearningService).
+//
+// Checkpoints share the question schema and validation of article quiz blocks
+// (normalizeArticleQuiz) and grading (gradeArticleQuiz) but are stored separately: quiz blocks are
+// self-checks within an article body, answered without a record and versioned and rolled back with
+// it, while checkpoints record answers per learner, gate progress, award points and also attach to
+// videos and documents, which have no body to hold them.
+type CheckpointService struct {
+	checkpoints *repository.ContentCheckpointRepository
+	answers     *repository.CheckpointAnswerRepository
+	contents    *repository.ContentRepository
+	users       *repository.UserRepository
+	learning    *LearningService
+	points      *PointService
+	audit       *AuditService
+}
+
+// NewCheckpointService builds a CheckpointService.
+func NewCheckpointService(
+	checkpointRepo *repository.ContentCheckpointRepository,
+	answerRepo *repository.CheckpointAnswerRepository,
+	contentRepo *repository.ContentRepository,
+	userRepo *repository.UserRepository,
+	learning *LearningService,
+	points *PointService,
+	audit *AuditService,
+) *CheckpointService {
+	return &CheckpointService{
+		checkpoints: checkpointRepo,
+		answers:     answerRepo,
+		contents:    contentRepo,
+		users:       userRepo,
+		learning:    learning,
+		points:      points,
+		audit:       audit,
+	}
+}
+
+// AdminList returns a page of the checkpoints of a content, including the answer keys.
+func (s *CheckpointService) AdminList(adminID, contentID uint, query dto.CheckpointListQuery) ([]dto.CheckpointResponse, dto.Pagination, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	if _, err := s.contents.FindByID(contentID); err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	checkpoints, page, err := s.searchCheckpoints(contentID, query)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	items := make([]dto.CheckpointResponse, 0, len(checkpoints))
+	for i := range checkpoints {
+		items = append(items, checkpointResponse(&checkpoints[i], true))
+	}
+	return items, page, nil
+}
+
+// AdminCreate adds a checkpoint to a content.
+func (s *CheckpointService) AdminCreate(ctx context.Context, adminID, contentID uint, req dto.AdminCheckpointUpsertRequest) (*dto.CheckpointResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	content, err := s.contents.FindByID(contentID)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := &model.ContentCheckpoint{ContentID: content.ID, CreatorID: adminID}
+	if err := applyCheckpoint(checkpoint, content, req); err != nil {
+		return nil, err
+	}
+	if err := s.checkpoints.Create(checkpoint); err != nil {
+		return nil, err
+	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "create_checkpoint",
+		Target:     "content_checkpoints",
+		EntityType: AuditEntityCheckpoint,
+		EntityID:   checkpoint.ID,
+		After:      checkpointAuditSnapshot(checkpoint),
+	})
+	resp := checkpointResponse(checkpoint, true)
+	return &resp, nil
+}
+
+// AdminUpdate replaces a checkpoint. Learners who already passed it stay passed.
+func (s *CheckpointService) AdminUpdate(ctx context.Context, adminID, contentID, checkpointID uint, req dto.AdminCheckpointUpsertRequest) (*dto.CheckpointResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	content, err := s.contents.FindByID(contentID)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, err := s.findCheckpoint(contentID, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	before := checkpointAuditSnapshot(checkpoint)
+	if err := applyCheckpoint(checkpoint, content, req); err != nil {
+		return nil, err
+	}
+	if err := s.checkpoints.Update(checkpoint); err != nil {
+		return nil, err
+	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "update_checkpoint",
+		Target:     "content_checkpoints",
+		EntityType: AuditEntityCheckpoint,
+		EntityID:   checkpoint.ID,
+		Before:     before,
+		After:      checkpointAuditSnapshot(checkpoint),
+	})
+	resp := checkpointResponse(checkpoint, true)
+	return &resp, nil
+}
+
+// AdminDelete removes a checkpoint, which no longer holds learners back.
+func (s *CheckpointService) AdminDelete(ctx context.Context, adminID, contentID, checkpointID uint) error {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return err
+	}
+	checkpoint, err := s.findCheckpoint(contentID, checkpointID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkpoints.Delete(checkpoint.ID); err != nil {
+		return err
+	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "delete_checkpoint",
+		Target:     "content_checkpoints",
+		EntityType: AuditEntityCheckpoint,
+		EntityID:   checkpoint.ID,
+		Before:     checkpointAuditSnapshot(checkpoint),
+	})
+	return nil
+}
+
+// List returns a page of the checkpoints of a content to a learner with their own answer state.
+// Correct answers and analyses are only included for checkpoints the learner has passed.
+func (s *CheckpointService) List(userID, contentID uint, query dto.CheckpointListQuery) ([]dto.CheckpointResponse, dto.Pagination, error) {
+	content, err := s.learnerContent(userID, contentID)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	checkpoints, page, err := s.searchCheckpoints(content.ID, query)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	answers, err := s.answers.ListByUserAndContent(userID, content.ID)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	byCheckpoint := make(map[uint]model.CheckpointAnswer, len(answers))
+	for _, answer := range answers {
+		byCheckpoint[answer.CheckpointID] = answer
+	}
+
+	items := make([]dto.CheckpointResponse, 0, len(checkpoints))
+	for i := range checkpoints {
+		answer := byCheckpoint[checkpoints[i].ID]
+		item := checkpointResponse(&checkpoints[i], answer.Passed)
+		item.Attempts = answer.Attempts
+		item.Passed = answer.Passed
+		item.PassedAt = answer.PassedAt
+		items = append(items, item)
+	}
+	return items, page, nil
+}
+
+// searchCheckpoints pages the checkpoints of a content, by position unless another sort is given.
+func (s *CheckpointService) searchCheckpoints(contentID uint, query dto.CheckpointListQuery) ([]model.ContentCheckpoint, dto.Pagination, error) {
+	page, err := pageRequest(query.PageQuery, repository.ContentCheckpointSortFields, "position", false)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	checkpoints, total, err := s.checkpoints.SearchByContent(contentID, page)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	return checkpoints, pagination(page, total), nil
+}
+
+// Answer grades a learner's answer. Wrong answers may be retried; the answer key and analysis
+// are revealed once the checkpoint is passed. Points are only awarded for a correct first
+// attempt.
+func (s *CheckpointService) Answer(ctx context.Context, userID, contentID, checkpointID uint, req dto.CheckpointAnswerRequest) (*dto.CheckpointAnswerResponse, error) {
+	content, err := s.learnerContent(userID, contentID)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, err := s.findCheckpoint(content.ID, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	options, err := checkpointOptions(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, correctIndexes, correct, err := gradeArticleQuiz(options, req.OptionIndexes)
+	if err != nil {
+		return nil, err
+	}
+
+	answer, newlyPassed, err := s.answers.RecordAttempt(userID, content.ID, checkpoint.ID, joinIndexes(selected), correct)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.CheckpointAnswerResponse{
+		CheckpointID: checkpoint.ID,
+		Correct:      correct,
+		Passed:       answer.Passed,
+		Attempts:     answer.Attempts,
+	}
+	if !answer.Passed {
+		return resp, nil
+	}
+	resp.CorrectIndexes = correctIndexes
+	resp.Analysis = checkpoint.Analysis
+	// 积分与进度只在首次通过的那次作答中处理，重复或并发的作答不会再次触发
+	if !newlyPassed {
+		return resp, nil
+	}
+
+	if correct && answer.Attempts == 1 {
+		awarded, err := s.points.AwardCheckpoint(ctx, userID, checkpoint, content)
+		if err != nil {
+			return nil, err
+		}
+		if awarded {
+			resp.PointsAwarded = checkpoint.Points
+		}
+	}
+	progress, err := s.learning.RefreshProgress(ctx, userID, content.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp.Progress = progress
+	return resp, nil
+}
+
+// learnerContent loads a published content the user may learn.
+func (s *CheckpointService) learnerContent(userID, contentID uint) (*model.Content, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	content, err := s.contents.FindByID(contentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.learning.ensureContentAccessible(user, content); err != nil {
+		return nil, err
+	}
+	if content.Status != model.EditorialPublished && user.Role != model.RoleAdmin {
+		return nil, errors.New("内容未发布")
+	}
+	return content, nil
+}
+
+func (s *CheckpointService) findCheckpoint(contentID, checkpointID uint) (*model.ContentCheckpoint, error) {
+	checkpoint, err := s.checkpoints.FindByID(checkpointID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("检查点不存在")
+		}
+		return nil, err
+	}
+	if checkpoint.ContentID != contentID {
+		return nil, errors.New("检查点不存在")
+	}
+	return checkpoint, nil
+}
+
+func (s *CheckpointService) ensureAdmin(userID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("管理员不存在")
+		}
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return errors.New("仅管理员可操作")
+	}
+	return nil
+}
+
+// applyCheckpoint validates the request against the content and copies it onto the checkpoint.
+// The question follows the schema of article quiz blocks.
+func applyCheckpoint(checkpoint *model.ContentCheckpoint, content *model.Content, req dto.AdminCheckpointUpsertRequest) error {
+	if err := validateCheckpointPosition(content, req.Position); err != nil {
+		return err
+	}
+	quiz, err := normalizeArticleQuiz(&dto.ArticleQuiz{
+		Type:     req.QuestionType,
+		Stem:     req.Stem,
+		Options:  req.Options,
+		Analysis: req.Analysis,
+	})
+	if err != nil {
+		return err
+	}
+	options, err := json.Marshal(quiz.Options)
+	if err != nil {
+		return err
+	}
+	checkpoint.Position = req.Position
+	checkpoint.QuestionType = quiz.Type
+	checkpoint.Stem = quiz.Stem
+	checkpoint.OptionsJSON = string(options)
+	checkpoint.Analysis = quiz.Analysis
+	checkpoint.Points = req.Points
+	return nil
+}
+
+// validateCheckpointPosition checks the position against the length of the content when it is
+// known: the duration of a video, the pages of a document or the blocks of an article.
+func validateCheckpointPosition(content *model.Content, position int64) error {
+	switch content.Type {
+	case "video":
+		if content.DurationSeconds > 0 && position > content.DurationSeconds {
+			return errors.New("检查点位置超出视频时长")
+		}
+	case "doc":
+		if position < 1 {
+			return errors.New("文档检查点的位置为页码，从 1 开始")
+		}
+		if content.PageCount > 0 && position > int64(content.PageCount) {
+			return errors.New("检查点位置超出文档页数")
+		}
+	case "article":
+		var blocks []dto.ArticleBlock
+		if content.BodyBlocksJSON != "" {
+			if err := json.Unmarshal([]byte(content.BodyBlocksJSON), &blocks); err != nil {
+				return err
+			}
+		}
+		if position > int64(len(blocks)) {
+			return fmt.Errorf("检查点位置超出图文内容块数量（%d）", len(blocks))
+		}
+	}
+	return nil
+}
+
+func checkpointOptions(checkpoint *model.ContentCheckpoint) ([]dto.ArticleQuizOption, error) {
+	var options []dto.ArticleQuizOption
+	if err := json.Unmarshal([]byte(checkpoint.OptionsJSON), &options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// checkpointResponse converts a checkpoint, hiding the answer key and analysis unless
+// withAnswers is set.
+func checkpointResponse(checkpoint *model.ContentCheckpoint, withAnswers bool) dto.CheckpointResponse {
+	options, _ := checkpointOptions(checkpoint)
+	resp := dto.CheckpointResponse{
+		ID:           checkpoint.ID,
+		ContentID:    checkpoint.ContentID,
+		Position:     checkpoint.Position,
+		QuestionType: checkpoint.QuestionType,
+		Stem:         checkpoint.Stem,
+		Options:      options,
+		Points:       checkpoint.Points,
+	}
+	if withAnswers {
+		resp.Analysis = checkpoint.Analysis
+		return resp
+	}
+	resp.Options = make([]dto.ArticleQuizOption, len(options))
+	for i, option := range options {
+		resp.Options[i] = dto.ArticleQuizOption{Content: option.Content}
+	}
+	return resp
+}
+
+func checkpointAuditSnapshot(checkpoint *model.ContentCheckpoint) map[string]interface{} {
+	var options json.RawMessage
+	if checkpoint.OptionsJSON != "" {
+		options = json.RawMessage(checkpoint.OptionsJSON)
+	}
+	return map[string]interface{}{
+		"content_id":    checkpoint.ContentID,
+		"position":      checkpoint.Position,
+		"question_type": checkpoint.QuestionType,
+		"stem":          checkpoint.Stem,
+		"options":       options,
+		"analysis":      checkpoint.Analysis,
+		"points":        checkpoint.Points,
+	}
+}
+
+func joinIndexes(indexes []int) string {
+	parts := make([]string, len(indexes))
+	for i, index := range indexes {
+		parts[i] = strconv.Itoa(index)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,34 @@
+package service
+
+// threadComments nests comments under their parents, keeping the input order within each level.
+// Replies whose parent is not among comments, such as replies to rejected or deleted comments,
+// are left out. node returns a comment's ID and parent ID; reply attaches a reply to its parent.
+func threadComments[C, R any](comments []C, node func(*C) (uint, *uint), respond func(*C) R, reply func(parent *R, child R)) []R {
+	children := make(map[uint][]*C)
+	var roots []*C
+	for i := range comments {
+		comment := &comments[i]
+		_, parentID := node(comment)
+		if parentID == nil {
+			roots = append(roots, comment)
+			continue
+		}
+		children[*parentID] = append(children[*parentID], comment)
+	}
+
+	var build func(comment *C) R
+	build = func(comment *C) R {
+		resp := respond(comment)
+		id, _ := node(comment)
+		for _, child := range children[id] {
+			reply(&resp, build(child))
+		}
+		return resp
+	}
+
+	resp := make([]R, 0, len(roots))
+	for _, root := range roots {
+		resp = append(resp, build(root))
+	}
+	return resp
+}
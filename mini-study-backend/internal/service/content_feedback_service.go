@@ -0,0 +1,535 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+)
+
+// Moderation states of review texts and comments, following the growth post workflow.
+const (
+	FeedbackStatusPending  = "pending"
+	FeedbackStatusApproved = "approved"
+	FeedbackStatusRejected = "rejected"
+)
+
+// ContentFeedbackService handles learners' ratings, reviews and comments on contents. Ratings
+// count towards the average at once; review texts and comments are shown only after an admin
+// approves them.
+type ContentFeedbackService struct {
+	ratings  *repository.ContentRatingRepository
+	comments *repository.ContentCommentRepository
+	contents *repository.ContentRepository
+	users    *repository.UserRepository
+	learning *LearningService
+	audit    *AuditService
+}
+
+// NewContentFeedbackService builds a ContentFeedbackService.
+func NewContentFeedbackService(
+	ratingRepo *repository.ContentRatingRepository,
+	commentRepo *repository.ContentCommentRepository,
+	contentRepo *repository.ContentRepository,
+	userRepo *repository.UserRepository,
+	learning *LearningService,
+	audit *AuditService,
+) *ContentFeedbackService {
+	return &ContentFeedbackService{
+		ratings:  ratingRepo,
+		comments: commentRepo,
+		contents: contentRepo,
+		users:    userRepo,
+		learning: learning,
+		audit:    audit,
+	}
+}
+
+// Rate creates or replaces the caller's rating of a content. A changed review text goes back to
+// moderation; a rating without text needs none.
+func (s *ContentFeedbackService) Rate(ctx context.Context, userID, contentID uint, req dto.ContentRatingRequest) (*dto.ContentRatingResponse, error) {
+	if _, err := s.learnerContent(userID, contentID); err != nil {
+		return nil, err
+	}
+	review := strings.TrimSpace(req.Review)
+
+	rating := &model.ContentRating{ContentID: contentID, UserID: userID}
+	existing, err := s.ratings.FindByUserAndContent(userID, contentID)
+	switch {
+	case err == nil:
+		rating = existing
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	if existing == nil || rating.Review != review {
+		rating.Review = review
+		rating.Status = FeedbackStatusPending
+		rating.ApprovedAt = nil
+		if review == "" {
+			now := time.Now()
+			rating.Status = FeedbackStatusApproved
+			rating.ApprovedAt = &now
+		}
+	}
+	rating.Rating = req.Rating
+	if err := s.ratings.Save(rating); err != nil {
+		return nil, err
+	}
+	if s.audit != nil {
+		_ = s.audit.Record(ctx, userID, "rate_content", "content_ratings", review, "success")
+	}
+
+	saved, err := s.ratings.FindByID(rating.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := ratingResponse(saved)
+	return &resp, nil
+}
+
+// ListReviews returns the rating summary of a content, the caller's own rating and the approved
+// reviews.
+func (s *ContentFeedbackService) ListReviews(userID, contentID uint) (*dto.ContentReviewListResponse, error) {
+	content, err := s.learnerContent(userID, contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregates, err := s.ratings.AggregateByContents([]uint{contentID})
+	if err != nil {
+		return nil, err
+	}
+	distribution, err := s.ratings.Distribution(contentID)
+	if err != nil {
+		return nil, err
+	}
+	summary := ratingSummary(aggregates[contentID])
+	summary.ContentID = content.ID
+	summary.ContentTitle = content.Title
+	summary.Distribution = distribution
+
+	reviews, err := s.ratings.ListApprovedReviews(contentID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.ContentReviewListResponse{Summary: summary, Reviews: make([]dto.ContentRatingResponse, 0, len(reviews))}
+	for i := range reviews {
+		resp.Reviews = append(resp.Reviews, ratingResponse(&reviews[i]))
+	}
+
+	mine, err := s.ratings.FindByUserAndContent(userID, contentID)
+	if err == nil {
+		item := ratingResponse(mine)
+		resp.Mine = &item
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ApplyRatings fills in the average rating and the number of ratings of each content.
+func (s *ContentFeedbackService) ApplyRatings(items []dto.ContentResponse) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	aggregates, err := s.ratings.AggregateByContents(ids)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		summary := ratingSummary(aggregates[items[i].ID])
+		items[i].RatingAverage = summary.Average
+		items[i].RatingCount = summary.Count
+	}
+	return nil
+}
+
+// AdminLowestRated pages through rated contents, by default from the lowest average rating up.
+func (s *ContentFeedbackService) AdminLowestRated(adminID uint, query dto.AdminRatedContentQuery) (*dto.ContentRatingSummaryListResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	minCount := query.MinCount
+	if minCount <= 0 {
+		minCount = 1
+	}
+	page, err := pageRequest(query.PageQuery, repository.RatedContentSortFields, "average", false)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, total, err := s.ratings.SearchRated(minCount, page)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]dto.ContentRatingSummary, 0, len(rows))
+	for _, row := range rows {
+		summary := ratingSummary(row)
+		summary.ContentID = row.ContentID
+		summary.ContentTitle = row.Title
+		resp = append(resp, summary)
+	}
+	return &dto.ContentRatingSummaryListResponse{Items: resp, Pagination: pagination(page, total)}, nil
+}
+
+// AdminListRatings pages through ratings for moderation, by default lowest ratings first.
+func (s *ContentFeedbackService) AdminListRatings(adminID uint, query dto.AdminContentRatingQuery) (*dto.ContentRatingListResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	page, err := pageRequest(query.PageQuery, repository.ContentRatingSortFields, "rating", false)
+	if err != nil {
+		return nil, err
+	}
+	ratings, total, err := s.ratings.AdminSearch(repository.ContentRatingFilter{
+		ContentID:  query.ContentID,
+		Status:     query.Status,
+		MaxRating:  query.MaxRating,
+		WithReview: query.WithReview,
+	}, page)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make(map[uint]string)
+	resp := make([]dto.ContentRatingResponse, 0, len(ratings))
+	for i := range ratings {
+		item := ratingResponse(&ratings[i])
+		item.ContentTitle = s.contentTitle(titles, ratings[i].ContentID)
+		resp = append(resp, item)
+	}
+	return &dto.ContentRatingListResponse{Items: resp, Pagination: pagination(page, total)}, nil
+}
+
+// AdminModerateRating approves or rejects the review text of a rating. The star rating itself
+// keeps counting either way.
+func (s *ContentFeedbackService) AdminModerateRating(ctx context.Context, adminID, ratingID uint, approve bool) (*dto.ContentRatingResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	rating, err := s.ratings.FindByID(ratingID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("评分不存在")
+		}
+		return nil, err
+	}
+	if rating.Review == "" {
+		return nil, errors.New("该评分未填写评价，无需审核")
+	}
+
+	status, action := FeedbackStatusRejected, "reject_content_rating"
+	if approve {
+		status, action = FeedbackStatusApproved, "approve_content_rating"
+	}
+	if rating.Status != status {
+		rating.Status = status
+		rating.ApprovedAt = nil
+		if approve {
+			now := time.Now()
+			rating.ApprovedAt = &now
+		}
+		if err := s.ratings.Update(rating); err != nil {
+			return nil, err
+		}
+		if s.audit != nil {
+			_ = s.audit.Record(ctx, adminID, action, "content_ratings", rating.Review, "success")
+		}
+	}
+	resp := ratingResponse(rating)
+	return &resp, nil
+}
+
+// ListComments returns a cursor page of the approved top-level comments of a content, newest
+// first, with their approved replies nested beneath them in posting order. Replies to comments
+// that are no longer visible are left out.
+func (s *ContentFeedbackService) ListComments(userID, contentID uint, query dto.ContentCommentQuery) (*dto.ContentCommentListResponse, error) {
+	if _, err := s.learnerContent(userID, contentID); err != nil {
+		return nil, err
+	}
+	cursor, err := cursorRequest(query.CursorQuery)
+	if err != nil {
+		return nil, err
+	}
+	roots, total, more, err := s.comments.FeedApprovedComments(contentID, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := roots
+	parentIDs := make([]uint, 0, len(roots))
+	for _, root := range roots {
+		parentIDs = append(parentIDs, root.ID)
+	}
+	for len(parentIDs) > 0 {
+		replies, err := s.comments.ListApprovedReplies(parentIDs)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, replies...)
+		parentIDs = parentIDs[:0]
+		for _, reply := range replies {
+			parentIDs = append(parentIDs, reply.ID)
+		}
+	}
+
+	var last model.ContentComment
+	if len(roots) > 0 {
+		last = roots[len(roots)-1]
+	}
+	return &dto.ContentCommentListResponse{
+		Items:      commentThreads(comments),
+		Pagination: feedPagination(cursor, total, more, last.CreatedAt, last.ID),
+	}, nil
+}
+
+// CreateComment posts a comment, or a reply when ParentID is set. It is visible to others once
+// an admin approves it.
+func (s *ContentFeedbackService) CreateComment(ctx context.Context, userID, contentID uint, req dto.ContentCommentRequest) (*dto.ContentCommentResponse, error) {
+	if _, err := s.learnerContent(userID, contentID); err != nil {
+		return nil, err
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, errors.New("评论内容不能为空")
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.comments.FindByID(*req.ParentID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if parent == nil || parent.ContentID != contentID || parent.Status != FeedbackStatusApproved {
+			return nil, errors.New("回复的评论不存在")
+		}
+	}
+
+	comment := &model.ContentComment{
+		ContentID: contentID,
+		ParentID:  req.ParentID,
+		UserID:    userID,
+		Body:      body,
+		Status:    FeedbackStatusPending,
+	}
+	if err := s.comments.Create(comment); err != nil {
+		return nil, err
+	}
+	if s.audit != nil {
+		_ = s.audit.Record(ctx, userID, "create_content_comment", "content_comments", comment.Body, "success")
+	}
+
+	saved, err := s.comments.FindByID(comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := commentResponse(saved)
+	return &resp, nil
+}
+
+// DeleteComment deletes a comment. Authors may delete their own comments and admins any comment;
+// the replies beneath it are hidden along with it.
+func (s *ContentFeedbackService) DeleteComment(ctx context.Context, userID, contentID, commentID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("用户不存在")
+		}
+		return err
+	}
+	comment, err := s.findComment(contentID, commentID)
+	if err != nil {
+		return err
+	}
+	if user.Role != model.RoleAdmin && comment.UserID != user.ID {
+		return errors.New("无权删除该评论")
+	}
+
+	if err := s.comments.Delete(comment); err != nil {
+		return err
+	}
+	if s.audit != nil {
+		action := "delete_own_content_comment"
+		if comment.UserID != user.ID {
+			action = "delete_content_comment"
+		}
+		_ = s.audit.Record(ctx, userID, action, "content_comments", comment.Body, "success")
+	}
+	return nil
+}
+
+// AdminListComments pages through comments for moderation, by default newest first.
+func (s *ContentFeedbackService) AdminListComments(adminID uint, query dto.AdminContentCommentQuery) (*dto.ContentCommentListResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	page, err := pageRequest(query.PageQuery, repository.ContentCommentSortFields, "created_at", true)
+	if err != nil {
+		return nil, err
+	}
+	comments, total, err := s.comments.AdminSearch(query.ContentID, query.Keyword, query.Status, page)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]dto.ContentCommentResponse, 0, len(comments))
+	for i := range comments {
+		resp = append(resp, commentResponse(&comments[i]))
+	}
+	return &dto.ContentCommentListResponse{Items: resp, Pagination: pagination(page, total)}, nil
+}
+
+// AdminModerateComment approves or rejects a comment.
+func (s *ContentFeedbackService) AdminModerateComment(ctx context.Context, adminID, commentID uint, approve bool) (*dto.ContentCommentResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	comment, err := s.comments.FindByID(commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("评论不存在")
+		}
+		return nil, err
+	}
+
+	status, action := FeedbackStatusRejected, "reject_content_comment"
+	if approve {
+		status, action = FeedbackStatusApproved, "approve_content_comment"
+	}
+	if comment.Status != status {
+		comment.Status = status
+		comment.ApprovedAt = nil
+		if approve {
+			now := time.Now()
+			comment.ApprovedAt = &now
+		}
+		if err := s.comments.Update(comment); err != nil {
+			return nil, err
+		}
+		if s.audit != nil {
+			_ = s.audit.Record(ctx, adminID, action, "content_comments", comment.Body, "success")
+		}
+	}
+	resp := commentResponse(comment)
+	return &resp, nil
+}
+
+func (s *ContentFeedbackService) learnerContent(userID, contentID uint) (*model.Content, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	content, err := s.contents.FindByID(contentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("内容不存在")
+		}
+		return nil, err
+	}
+	if err := s.learning.ensureContentAccessible(user, content); err != nil {
+		return nil, err
+	}
+	if content.Status != model.EditorialPublished && user.Role != model.RoleAdmin {
+		return nil, errors.New("内容未发布")
+	}
+	return content, nil
+}
+
+func (s *ContentFeedbackService) findComment(contentID, commentID uint) (*model.ContentComment, error) {
+	comment, err := s.comments.FindByID(commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("评论不存在")
+		}
+		return nil, err
+	}
+	if comment.ContentID != contentID {
+		return nil, errors.New("评论不存在")
+	}
+	return comment, nil
+}
+
+// contentTitle looks up content titles for admin lists, caching them per request.
+func (s *ContentFeedbackService) contentTitle(cache map[uint]string, contentID uint) string {
+	if title, ok := cache[contentID]; ok {
+		return title
+	}
+	title := ""
+	if content, err := s.contents.FindByID(contentID); err == nil {
+		title = content.Title
+	}
+	cache[contentID] = title
+	return title
+}
+
+func (s *ContentFeedbackService) ensureAdmin(userID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("管理员不存在")
+		}
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return errors.New("仅管理员可操作")
+	}
+	return nil
+}
+
+// ratingSummary rounds the average rating to one decimal place.
+func ratingSummary(aggregate repository.ContentRatingAggregate) dto.ContentRatingSummary {
+	return dto.ContentRatingSummary{
+		Average: math.Round(aggregate.Average*10) / 10,
+		Count:   aggregate.Count,
+	}
+}
+
+func ratingResponse(rating *model.ContentRating) dto.ContentRatingResponse {
+	return dto.ContentRatingResponse{
+		ID:         rating.ID,
+		ContentID:  rating.ContentID,
+		UserID:     rating.UserID,
+		UserName:   rating.User.Name,
+		Rating:     rating.Rating,
+		Review:     rating.Review,
+		Status:     rating.Status,
+		CreatedAt:  rating.CreatedAt,
+		UpdatedAt:  rating.UpdatedAt,
+		ApprovedAt: rating.ApprovedAt,
+	}
+}
+
+func commentResponse(comment *model.ContentComment) dto.ContentCommentResponse {
+	return dto.ContentCommentResponse{
+		ID:         comment.ID,
+		ContentID:  comment.ContentID,
+		ParentID:   comment.ParentID,
+		Body:       comment.Body,
+		Status:     comment.Status,
+		UserID:     comment.UserID,
+		UserName:   comment.User.Name,
+		UserRole:   comment.User.Role,
+		CreatedAt:  comment.CreatedAt,
+		ApprovedAt: comment.ApprovedAt,
+	}
+}
+
+// commentThreads nests content comments under their parents.
+func commentThreads(comments []model.ContentComment) []dto.ContentCommentResponse {
+	return threadComments(comments,
+		func(c *model.ContentComment) (uint, *uint) { return c.ID, c.ParentID },
+		commentResponse,
+		func(parent *dto.ContentCommentResponse, child dto.ContentCommentResponse) {
+			parent.Replies = append(parent.Replies, child)
+		})
+}
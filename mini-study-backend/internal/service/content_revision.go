@@ -0,0 +1,479 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// blockChanged marks a block edited in place in a revision diff.
+const blockChanged = "changed"
+
+// revisionSaveAttempts bounds the retries of an edit that lost the race for the next version.
+const revisionSaveAttempts = 3
+
+// AdminListRevisions lists a page of the revisions of a content, newest first by default, with
+// the fields each one changed compared to the version before it.
+func (s *ContentService) AdminListRevisions(adminID, contentID uint, query dto.ContentRevisionListQuery) ([]dto.ContentRevisionItem, dto.Pagination, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	content, err := s.contents.FindByID(contentID)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	page, err := pageRequest(query.PageQuery, repository.ContentRevisionSortFields, "version", true)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+
+	revisions, total, err := s.revisions.SearchByContent(content.ID, page)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	previous, err := s.previousRevisions(content.ID, revisions)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	names, err := s.authorNames(revisions)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+
+	items := make([]dto.ContentRevisionItem, 0, len(revisions))
+	for i := range revisions {
+		changed := make([]string, 0)
+		if prev, ok := previous[revisions[i].Version-1]; ok {
+			for _, change := range utils.DiffFields(revisionFields(prev), revisionFields(&revisions[i])) {
+				changed = append(changed, change.Field)
+			}
+		}
+		items = append(items, dto.ContentRevisionItem{
+			Version:       revisions[i].Version,
+			Action:        revisions[i].Action,
+			SourceVersion: revisions[i].SourceVersion,
+			AuthorID:      revisions[i].AuthorID,
+			AuthorName:    names[revisions[i].AuthorID],
+			Title:         revisions[i].Title,
+			ChangedFields: changed,
+			CreatedAt:     revisions[i].CreatedAt,
+		})
+	}
+	return items, pagination(page, total), nil
+}
+
+// previousRevisions maps each version preceding a revision of the page to that revision, loading
+// the ones that fall outside the page.
+func (s *ContentService) previousRevisions(contentID uint, revisions []model.ContentRevision) (map[int]*model.ContentRevision, error) {
+	byVersion := make(map[int]*model.ContentRevision, len(revisions))
+	for i := range revisions {
+		byVersion[revisions[i].Version] = &revisions[i]
+	}
+	missing := make([]int, 0)
+	for _, revision := range revisions {
+		if _, ok := byVersion[revision.Version-1]; !ok && revision.Version > 1 {
+			missing = append(missing, revision.Version-1)
+		}
+	}
+	loaded, err := s.revisions.FindByVersions(contentID, missing)
+	if err != nil {
+		return nil, err
+	}
+	for i := range loaded {
+		byVersion[loaded[i].Version] = &loaded[i]
+	}
+	return byVersion, nil
+}
+
+// AdminGetRevision returns the full content of one revision.
+func (s *ContentService) AdminGetRevision(adminID, contentID uint, version int) (*dto.ContentRevisionDetail, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	revision, err := s.findRevision(contentID, version)
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.authorNames([]model.ContentRevision{*revision})
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := revisionBlocks(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ContentRevisionDetail{
+		Version:         revision.Version,
+		Action:          revision.Action,
+		SourceVersion:   revision.SourceVersion,
+		AuthorID:        revision.AuthorID,
+		AuthorName:      names[revision.AuthorID],
+		Title:           revision.Title,
+		Type:            revision.Type,
+		CategoryID:      revision.CategoryID,
+		VisibleRoles:    revision.VisibleRoles,
+		FilePath:        revision.FilePath,
+		CoverURL:        revision.CoverURL,
+		Summary:         revision.Summary,
+		DurationSeconds: revision.DurationSeconds,
+		ArticleBlocks:   blocks,
+		CreatedAt:       revision.CreatedAt,
+	}, nil
+}
+
+// AdminDiffRevisions compares two revisions of a content. Without versions it compares the
+// latest revision with the one before it.
+func (s *ContentService) AdminDiffRevisions(adminID, contentID uint, query dto.ContentRevisionDiffQuery) (*dto.ContentRevisionDiffResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	if _, err := s.contents.FindByID(contentID); err != nil {
+		return nil, err
+	}
+
+	to := query.To
+	if to == 0 {
+		latest, err := s.revisions.LatestVersion(contentID)
+		if err != nil {
+			return nil, err
+		}
+		to = latest
+	}
+	from := query.From
+	if from == 0 {
+		from = to - 1
+	}
+	if from < 1 {
+		return nil, errors.New("暂无可比对的版本")
+	}
+	if from == to {
+		return nil, errors.New("请选择两个不同的版本")
+	}
+
+	older, err := s.findRevision(contentID, from)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := s.findRevision(contentID, to)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := diffRevisionBlocks(older, newer)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]dto.AuditFieldChange, 0)
+	for _, change := range utils.DiffFields(revisionFields(older), revisionFields(newer)) {
+		if change.Field == "article_blocks" {
+			continue
+		}
+		fields = append(fields, dto.AuditFieldChange{Field: change.Field, Before: change.Before, After: change.After})
+	}
+	return &dto.ContentRevisionDiffResponse{From: from, To: to, Fields: fields, Blocks: blocks}, nil
+}
+
+// AdminRollbackContent restores the fields of a revision onto the content. The rollback is an
+// ordinary edit: it follows the editorial rules and is itself recorded as a new revision.
+func (s *ContentService) AdminRollbackContent(ctx context.Context, adminID, contentID uint, version int) (*model.Content, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	content, err := s.contents.FindByID(contentID)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := s.findRevision(contentID, version)
+	if err != nil {
+		return nil, err
+	}
+	original := *content
+	if !revisionDiffers(contentRevisionOf(content), revision) {
+		return nil, errors.New("该版本与当前内容一致，无需回滚")
+	}
+
+	if revision.CategoryID != content.CategoryID {
+		category, err := s.categories.FindByID(revision.CategoryID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("该版本的分类已不存在")
+			}
+			return nil, err
+		}
+		if !category.Status {
+			return nil, errors.New("分类已禁用")
+		}
+	}
+	// 版本中保存的图文块按当前规则重新校验，旧规则下保存的无效内容不能恢复
+	blocksJSON, err := normalizedRevisionBlocks(revision)
+	if err != nil {
+		return nil, err
+	}
+	sourceChanged := revision.Type != content.Type || revision.FilePath != content.FilePath
+	if revision.FilePath != content.FilePath {
+		content.HLSPath = ""
+		content.PageCount = 0
+	}
+	content.Title = revision.Title
+	content.Type = revision.Type
+	content.CategoryID = revision.CategoryID
+	content.VisibleRoles = revision.VisibleRoles
+	content.FilePath = revision.FilePath
+	content.CoverURL = revision.CoverURL
+	content.Summary = revision.Summary
+	content.BodyBlocksJSON = blocksJSON
+	content.DurationSeconds = revision.DurationSeconds
+
+	return s.saveEdit(ctx, adminID, content, contentEdit{
+		original:       original,
+		sourceChanged:  sourceChanged,
+		auditAction:    "rollback_content",
+		revisionAction: model.RevisionActionRollback,
+		sourceVersion:  revision.Version,
+	})
+}
+
+// saveWithRevision updates the content and, when a versioned field changed, records the new
+// revision in the same transaction. Concurrent edits may pick the same next version; the loser
+// hits the unique (content_id, version) index and is retried against the committed versions.
+func (s *ContentService) saveWithRevision(content *model.Content, authorID uint, edit contentEdit) error {
+	for attempt := 1; ; attempt++ {
+		err := s.contents.Transaction(func(tx *gorm.DB) error {
+			if err := s.contents.WithTx(tx).Update(content); err != nil {
+				return err
+			}
+			if !revisionChanged(&edit.original, content) {
+				return nil
+			}
+			if err := s.ensureBaseline(tx, &edit.original); err != nil {
+				return err
+			}
+			return s.recordRevision(tx, content, authorID, edit.revisionAction, edit.sourceVersion)
+		})
+		if errors.Is(err, gorm.ErrDuplicatedKey) && attempt < revisionSaveAttempts {
+			continue
+		}
+		return err
+	}
+}
+
+// recordRevision stores the current fields of a content as its next version within tx.
+func (s *ContentService) recordRevision(tx *gorm.DB, content *model.Content, authorID uint, action string, sourceVersion int) error {
+	revisions := s.revisions.WithTx(tx)
+	latest, err := revisions.LatestVersion(content.ID)
+	if err != nil {
+		return err
+	}
+	revision := contentRevisionOf(content)
+	revision.Version = latest + 1
+	revision.Action = action
+	revision.SourceVersion = sourceVersion
+	revision.AuthorID = authorID
+	return revisions.Create(revision)
+}
+
+// ensureBaseline keeps the state of contents created before revisions existed, so that their
+// first edit can still be compared and rolled back.
+func (s *ContentService) ensureBaseline(tx *gorm.DB, original *model.Content) error {
+	revisions := s.revisions.WithTx(tx)
+	latest, err := revisions.LatestVersion(original.ID)
+	if err != nil || latest > 0 {
+		return err
+	}
+	revision := contentRevisionOf(original)
+	revision.Version = 1
+	revision.Action = model.RevisionActionBaseline
+	revision.AuthorID = original.CreatorID
+	revision.CreatedAt = original.UpdatedAt
+	return revisions.Create(revision)
+}
+
+func (s *ContentService) findRevision(contentID uint, version int) (*model.ContentRevision, error) {
+	revision, err := s.revisions.FindByVersion(contentID, version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("版本不存在")
+		}
+		return nil, err
+	}
+	return revision, nil
+}
+
+func (s *ContentService) authorNames(revisions []model.ContentRevision) (map[uint]string, error) {
+	names := make(map[uint]string)
+	ids := make([]uint, 0, len(revisions))
+	for _, revision := range revisions {
+		if _, ok := names[revision.AuthorID]; !ok {
+			names[revision.AuthorID] = ""
+			ids = append(ids, revision.AuthorID)
+		}
+	}
+	if len(ids) == 0 {
+		return names, nil
+	}
+	users, err := s.users.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		names[user.ID] = user.Name
+	}
+	return names, nil
+}
+
+// contentRevisionOf copies the versioned fields of a content. Status, schedules and fields
+// derived by media processing are not versioned.
+func contentRevisionOf(content *model.Content) *model.ContentRevision {
+	return &model.ContentRevision{
+		ContentID:       content.ID,
+		Title:           content.Title,
+		Type:            content.Type,
+		CategoryID:      content.CategoryID,
+		VisibleRoles:    content.VisibleRoles,
+		FilePath:        content.FilePath,
+		CoverURL:        content.CoverURL,
+		Summary:         content.Summary,
+		BodyBlocksJSON:  content.BodyBlocksJSON,
+		DurationSeconds: content.DurationSeconds,
+	}
+}
+
+// revisionChanged reports whether an edit touched any versioned field.
+func revisionChanged(before, after *model.Content) bool {
+	return revisionDiffers(contentRevisionOf(before), contentRevisionOf(after))
+}
+
+func revisionDiffers(a, b *model.ContentRevision) bool {
+	return len(utils.DiffFields(revisionFields(a), revisionFields(b))) > 0
+}
+
+// revisionFields snapshots the versioned fields by their JSON names for comparison.
+func revisionFields(revision *model.ContentRevision) map[string]interface{} {
+	var blocks json.RawMessage
+	if revision.BodyBlocksJSON != "" {
+		blocks = json.RawMessage(revision.BodyBlocksJSON)
+	}
+	return map[string]interface{}{
+		"title":            revision.Title,
+		"type":             revision.Type,
+		"category_id":      revision.CategoryID,
+		"visible_roles":    revision.VisibleRoles,
+		"file_path":        revision.FilePath,
+		"cover_url":        revision.CoverURL,
+		"summary":          revision.Summary,
+		"article_blocks":   blocks,
+		"duration_seconds": revision.DurationSeconds,
+	}
+}
+
+func revisionBlocks(revision *model.ContentRevision) ([]dto.ArticleBlock, error) {
+	blocks := make([]dto.ArticleBlock, 0)
+	if revision.BodyBlocksJSON == "" {
+		return blocks, nil
+	}
+	if err := json.Unmarshal([]byte(revision.BodyBlocksJSON), &blocks); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// normalizedRevisionBlocks runs the article blocks of a revision through NormalizeArticleBlocks
+// and encodes them for storage.
+func normalizedRevisionBlocks(revision *model.ContentRevision) (string, error) {
+	if revision.BodyBlocksJSON == "" {
+		return "", nil
+	}
+	blocks, err := revisionBlocks(revision)
+	if err != nil {
+		return "", errors.New("该版本的图文内容已损坏，无法回滚")
+	}
+	blocks, err = NormalizeArticleBlocks(blocks)
+	if err != nil {
+		return "", fmt.Errorf("该版本的图文内容无效，无法回滚：%s", err.Error())
+	}
+	b, err := json.Marshal(blocks)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// diffRevisionBlocks aligns the article blocks of two revisions. Blocks removed and added at
+// the same place are paired up as changed, so an edited paragraph reads as one change.
+func diffRevisionBlocks(older, newer *model.ContentRevision) ([]dto.ArticleBlockDiff, error) {
+	before, err := revisionBlocks(older)
+	if err != nil {
+		return nil, err
+	}
+	after, err := revisionBlocks(newer)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make([]dto.ArticleBlockDiff, 0, len(after))
+	var removed, added []int
+	flush := func() {
+		paired := len(removed)
+		if len(added) < paired {
+			paired = len(added)
+		}
+		for k := 0; k < paired; k++ {
+			diffs = append(diffs, blockDiff(utils.SequenceOp{Op: blockChanged, AIndex: removed[k], BIndex: added[k]}, before, after))
+		}
+		for _, i := range removed[paired:] {
+			diffs = append(diffs, blockDiff(utils.SequenceOp{Op: utils.SequenceRemoved, AIndex: i, BIndex: -1}, before, after))
+		}
+		for _, j := range added[paired:] {
+			diffs = append(diffs, blockDiff(utils.SequenceOp{Op: utils.SequenceAdded, AIndex: -1, BIndex: j}, before, after))
+		}
+		removed, added = removed[:0], added[:0]
+	}
+	for _, op := range utils.DiffSequences(canonicalBlocks(before), canonicalBlocks(after)) {
+		switch op.Op {
+		case utils.SequenceRemoved:
+			removed = append(removed, op.AIndex)
+		case utils.SequenceAdded:
+			added = append(added, op.BIndex)
+		default:
+			flush()
+			diffs = append(diffs, blockDiff(op, before, after))
+		}
+	}
+	flush()
+	return diffs, nil
+}
+
+func blockDiff(op utils.SequenceOp, before, after []dto.ArticleBlock) dto.ArticleBlockDiff {
+	diff := dto.ArticleBlockDiff{Op: op.Op}
+	if op.AIndex >= 0 {
+		index := op.AIndex
+		diff.FromIndex = &index
+		diff.Before = &before[index]
+	}
+	if op.BIndex >= 0 {
+		index := op.BIndex
+		diff.ToIndex = &index
+		diff.After = &after[index]
+	}
+	return diff
+}
+
+// canonicalBlocks encodes each block the same way regardless of how it was stored, so equal
+// blocks compare equal.
+func canonicalBlocks(blocks []dto.ArticleBlock) []string {
+	out := make([]string, len(blocks))
+	for i, block := range blocks {
+		b, _ := json.Marshal(block)
+		out[i] = string(b)
+	}
+	return out
+}
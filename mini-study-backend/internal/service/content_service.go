@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"time"
@@ -12,11 +13,26 @@ import (
 	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
 )
 
+// Errors returned by GetPublishedDetail, so handlers can tell a missing content from a denied one.
+var (
+	ErrContentNotFound     = errors.New("内容不存在")
+	ErrContentNotPublished = errors.New("内容未发布")
+	ErrContentForbidden    = errors.New("无权查看该内容")
+)
+
+// ErrArticleQuizNotFound is returned by AnswerArticleQuiz when the block is missing or not a quiz.
+var ErrArticleQuizNotFound = errors.New("测验不存在")
+
 // ContentService handles business logic around categories and contents.
 type ContentService struct {
 	categories *repository.ContentCategoryRepository
 	contents   *repository.ContentRepository
 	users      *repository.UserRepository
+	audit      *AuditService
+	media      *MediaService
+	search     *SearchService
+	reviews    *ReviewService
+	revisions  *repository.ContentRevisionRepository
 }
 
 // NewContentService builds a content service.
@@ -24,11 +40,21 @@ func NewContentService(
 	categoryRepo *repository.ContentCategoryRepository,
 	contentRepo *repository.ContentRepository,
 	userRepo *repository.UserRepository,
+	audit *AuditService,
+	media *MediaService,
+	search *SearchService,
+	reviews *ReviewService,
+	revisionRepo *repository.ContentRevisionRepository,
 ) *ContentService {
 	return &ContentService{
 		categories: categoryRepo,
 		contents:   contentRepo,
 		users:      userRepo,
+		audit:      audit,
+		media:      media,
+		search:     search,
+		reviews:    reviews,
+		revisions:  revisionRepo,
 	}
 }
 
@@ -41,51 +67,47 @@ func (s *ContentService) ListCategories(userID uint) ([]model.ContentCategory, e
 	return s.categories.ListByRole(roleFilter)
 }
 
-// ListCategoriesWithCount returns categories visible to current user role with content count.
-func (s *ContentService) ListCategoriesWithCount(userID uint) ([]model.ContentCategory, []int64, error) {
+// ListCategoriesWithCount returns a page of the categories visible to the user's role, with the
+// number of published contents the user can see in each.
+func (s *ContentService) ListCategoriesWithCount(userID uint, query dto.ContentCategoryQuery) ([]model.ContentCategory, []int64, dto.Pagination, error) {
 	roleFilter, _, err := s.resolveUserRole(userID)
 	if err != nil {
-		return nil, nil, err
-	}
-	categories, err := s.categories.ListByRole(roleFilter)
-	if err != nil {
-		return nil, nil, err
+		return nil, nil, dto.Pagination{}, err
 	}
+	return s.searchCategories(roleFilter, query)
+}
 
-	counts := make([]int64, len(categories))
-	for i, category := range categories {
-		count, err := s.contents.CountPublishedByCategoryAndRole(category.ID, roleFilter)
-		if err != nil {
-			return nil, nil, err
-		}
-		counts[i] = count
+// AdminListCategories returns a page of all enabled categories with their published content counts.
+func (s *ContentService) AdminListCategories(adminID uint, query dto.ContentCategoryQuery) ([]model.ContentCategory, []int64, dto.Pagination, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, nil, dto.Pagination{}, err
 	}
-
-	return categories, counts, nil
+	return s.searchCategories("", query)
 }
 
-func (s *ContentService) AdminListCategories(adminID uint) ([]model.ContentCategory, []int64, error) {
-	if err := s.ensureAdmin(adminID); err != nil {
-		return nil, nil, err
+func (s *ContentService) searchCategories(roleFilter string, query dto.ContentCategoryQuery) ([]model.ContentCategory, []int64, dto.Pagination, error) {
+	page, err := pageRequest(query.PageQuery, repository.ContentCategorySortFields, "sort_order", false)
+	if err != nil {
+		return nil, nil, dto.Pagination{}, err
 	}
-	categories, err := s.categories.ListByRole("")
+	categories, total, err := s.categories.SearchByRole(roleFilter, page)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, dto.Pagination{}, err
 	}
 
 	counts := make([]int64, len(categories))
 	for i, category := range categories {
-		count, err := s.contents.CountPublishedByCategoryAndRole(category.ID, "")
+		count, err := s.contents.CountPublishedByCategoryAndRole(category.ID, roleFilter)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, dto.Pagination{}, err
 		}
 		counts[i] = count
 	}
-	return categories, counts, nil
+	return categories, counts, pagination(page, total), nil
 }
 
 // AdminCreateContent creates a new content entry.
-func (s *ContentService) AdminCreateContent(adminID uint, req dto.AdminCreateContentRequest) (*model.Content, error) {
+func (s *ContentService) AdminCreateContent(ctx context.Context, adminID uint, req dto.AdminCreateContentRequest) (*model.Content, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
@@ -98,12 +120,16 @@ func (s *ContentService) AdminCreateContent(adminID uint, req dto.AdminCreateCon
 		return nil, errors.New("分类已禁用")
 	}
 
-	if req.Type == "video" && req.DurationSeconds <= 0 {
+	// 上传到存储的视频由处理任务自动识别时长
+	if req.Type == "video" && req.DurationSeconds <= 0 && !s.media.Processable(req.Type, req.FilePath) {
 		return nil, errors.New("视频必须提供 duration_seconds")
 	}
 	if (req.Type == "doc" || req.Type == "video") && req.FilePath == "" {
 		return nil, errors.New("文件路径不能为空")
 	}
+	if req.Status != "" && req.Status != model.EditorialDraft {
+		return nil, errors.New("新建内容为草稿，需提交审核通过后才能发布")
+	}
 	if req.Type == "article" {
 		if len(req.ArticleBlocks) == 0 {
 			return nil, errors.New("图文内容不能为空")
@@ -115,11 +141,18 @@ func (s *ContentService) AdminCreateContent(adminID uint, req dto.AdminCreateCon
 
 	var bodyBlocksJSON string
 	if len(req.ArticleBlocks) > 0 {
-		b, err := json.Marshal(req.ArticleBlocks)
+		blocks, err := NormalizeArticleBlocks(req.ArticleBlocks)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(blocks)
 		if err != nil {
 			return nil, err
 		}
 		bodyBlocksJSON = string(b)
+		if req.Summary == "" {
+			req.Summary = articleSummary(blocks)
+		}
 	}
 
 	content := &model.Content{
@@ -131,27 +164,36 @@ func (s *ContentService) AdminCreateContent(adminID uint, req dto.AdminCreateCon
 		CoverURL:        req.CoverURL,
 		Summary:         req.Summary,
 		BodyBlocksJSON:  bodyBlocksJSON,
-		Status:          req.Status,
+		Status:          model.EditorialDraft,
 		CreatorID:       adminID,
 		DurationSeconds: req.DurationSeconds,
 	}
 
-	if content.Status == "" {
-		content.Status = "draft"
-	}
-	if content.Status == "published" {
-		now := time.Now()
-		content.PublishAt = &now
-	}
-
-	if err := s.contents.Create(content); err != nil {
+	// 内容与首个版本同时写入，避免出现没有版本记录的内容
+	if err := s.contents.Transaction(func(tx *gorm.DB) error {
+		if err := s.contents.WithTx(tx).Create(content); err != nil {
+			return err
+		}
+		return s.recordRevision(tx, content, adminID, model.RevisionActionCreate, 0)
+	}); err != nil {
 		return nil, err
 	}
+	// 入队失败不影响内容保存，管理员可在任务列表中重新发起处理
+	_, _ = s.media.Enqueue(content)
+	// 索引失败同样不影响保存，可由管理员重建索引
+	_ = s.search.IndexContent(ctx, content)
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "create_content",
+		Target:     "contents",
+		EntityType: AuditEntityContent,
+		EntityID:   content.ID,
+		After:      contentAuditSnapshot(content),
+	})
 	return content, nil
 }
 
 // AdminUpdateContent updates content basic info/status.
-func (s *ContentService) AdminUpdateContent(adminID, contentID uint, req dto.AdminUpdateContentRequest) (*model.Content, error) {
+func (s *ContentService) AdminUpdateContent(ctx context.Context, adminID, contentID uint, req dto.AdminUpdateContentRequest) (*model.Content, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
@@ -160,11 +202,14 @@ func (s *ContentService) AdminUpdateContent(adminID, contentID uint, req dto.Adm
 	if err != nil {
 		return nil, err
 	}
+	original := *content
+	sourceChanged := false
 
 	if req.Title != "" {
 		content.Title = req.Title
 	}
 	if req.Type != "" {
+		sourceChanged = sourceChanged || req.Type != content.Type
 		content.Type = req.Type
 	}
 	if req.CategoryID > 0 {
@@ -180,8 +225,11 @@ func (s *ContentService) AdminUpdateContent(adminID, contentID uint, req dto.Adm
 			content.VisibleRoles = category.RoleScope
 		}
 	}
-	if req.FilePath != "" {
+	if req.FilePath != "" && req.FilePath != content.FilePath {
+		sourceChanged = true
 		content.FilePath = req.FilePath
+		content.HLSPath = ""
+		content.PageCount = 0
 	}
 	if req.CoverURL != "" {
 		content.CoverURL = req.CoverURL
@@ -193,29 +241,132 @@ func (s *ContentService) AdminUpdateContent(adminID, contentID uint, req dto.Adm
 		content.VisibleRoles = req.VisibleRoles
 	}
 	if len(req.ArticleBlocks) > 0 {
-		b, err := json.Marshal(req.ArticleBlocks)
+		blocks, err := NormalizeArticleBlocks(req.ArticleBlocks)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(blocks)
 		if err != nil {
 			return nil, err
 		}
 		content.BodyBlocksJSON = string(b)
+		if content.Summary == "" {
+			content.Summary = articleSummary(blocks)
+		}
 	}
 	if req.DurationSeconds > 0 {
 		content.DurationSeconds = req.DurationSeconds
 	}
-	if req.Status != "" {
-		content.Status = req.Status
-		if req.Status == "published" && content.PublishAt == nil {
+
+	return s.saveEdit(ctx, adminID, content, contentEdit{
+		original:       original,
+		status:         req.Status,
+		sourceChanged:  sourceChanged,
+		auditAction:    "update_content",
+		revisionAction: model.RevisionActionUpdate,
+	})
+}
+
+// contentEdit describes a manual change to an existing content for saveEdit.
+type contentEdit struct {
+	original       model.Content // 修改前的内容
+	status         string        // 请求的状态，为空表示不变
+	sourceChanged  bool          // 文件或类型变化，需要重新处理媒体
+	auditAction    string
+	revisionAction string
+	sourceVersion  int // 回滚来源版本号
+}
+
+// saveEdit applies the editorial rules to an edited content and persists it, recording the
+// status transition, the new revision and the audit log.
+func (s *ContentService) saveEdit(ctx context.Context, adminID uint, content *model.Content, edit contentEdit) (*model.Content, error) {
+	before := contentAuditSnapshot(&edit.original)
+	fromStatus := content.Status
+	edited := editorialEdited(before, contentAuditSnapshot(content))
+	status, err := NextEditorialStatus(fromStatus, edit.status, edited)
+	if err != nil {
+		return nil, err
+	}
+	if edit.status != "" || status != fromStatus {
+		content.Status = status
+		if status == model.EditorialPublished && content.PublishAt == nil {
 			now := time.Now()
 			content.PublishAt = &now
 		}
+		clearSupersededSchedule(content.Status, &content.ScheduledPublishAt, &content.ScheduledOfflineAt)
+	}
+	if err := ensureVideoDuration(content); err != nil {
+		return nil, err
 	}
 
-	if err := s.contents.Update(content); err != nil {
+	if err := s.saveWithRevision(content, adminID, edit); err != nil {
 		return nil, err
 	}
+	if status != fromStatus {
+		_ = s.reviews.recordTransition(AuditEntityContent, content.ID, editorialAction(status), fromStatus, status, adminID, content.ReviewerID, editorialNote(fromStatus, status, edited))
+	}
+	if edit.sourceChanged {
+		_, _ = s.media.Enqueue(content)
+	}
+	_ = s.search.IndexContent(ctx, content)
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     edit.auditAction,
+		Target:     "contents",
+		EntityType: AuditEntityContent,
+		EntityID:   content.ID,
+		Before:     before,
+		After:      contentAuditSnapshot(content),
+	})
 	return content, nil
 }
 
+// ensureVideoDuration keeps videos without a known duration out of publication, since learning
+// progress is computed against it.
+func ensureVideoDuration(content *model.Content) error {
+	if content.Type == "video" && content.Status == "published" && content.DurationSeconds <= 0 {
+		return errors.New("视频时长尚未识别，请等待视频处理完成或填写 duration_seconds")
+	}
+	return nil
+}
+
+// clearSupersededSchedule drops schedules made obsolete by a manual status change: publishing
+// replaces the scheduled publish, losing the approval cancels it, and taking offline an entity
+// that is not going to be published again replaces the scheduled offline.
+func clearSupersededSchedule(status string, publishAt, offlineAt **time.Time) {
+	if status == model.EditorialPublished || !editorialPublishable(status) {
+		*publishAt = nil
+	}
+	if status != model.EditorialPublished && *publishAt == nil {
+		*offlineAt = nil
+	}
+}
+
+// contentAuditSnapshot captures the editable fields of a content, including the article blocks
+// that are hidden from the model's JSON, without the preloaded category.
+func contentAuditSnapshot(content *model.Content) map[string]interface{} {
+	var blocks json.RawMessage
+	if content.BodyBlocksJSON != "" {
+		blocks = json.RawMessage(content.BodyBlocksJSON)
+	}
+	return map[string]interface{}{
+		"title":            content.Title,
+		"type":             content.Type,
+		"category_id":      content.CategoryID,
+		"visible_roles":    content.VisibleRoles,
+		"file_path":        content.FilePath,
+		"cover_url":        content.CoverURL,
+		"summary":          content.Summary,
+		"article_blocks":   blocks,
+		"status":           content.Status,
+		"publish_at":       content.PublishAt,
+		"duration_seconds": content.DurationSeconds,
+		"hls_path":         content.HLSPath,
+
+		"scheduled_publish_at": content.ScheduledPublishAt,
+		"scheduled_offline_at": content.ScheduledOfflineAt,
+	}
+}
+
 func (s *ContentService) AdminUpdateCategory(adminID, categoryID uint, req dto.AdminUpdateCategoryRequest) (*model.ContentCategory, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
@@ -237,20 +388,36 @@ func (s *ContentService) AdminUpdateCategory(adminID, categoryID uint, req dto.A
 }
 
 // AdminListContents lists contents for admin.
-func (s *ContentService) AdminListContents(adminID uint, filter dto.AdminListContentRequest) ([]model.Content, error) {
+func (s *ContentService) AdminListContents(adminID uint, filter dto.AdminListContentRequest) ([]model.Content, dto.Pagination, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
-		return nil, err
+		return nil, dto.Pagination{}, err
 	}
-	return s.contents.ListAdmin(filter.CategoryID, filter.Type, filter.Status)
+	page, err := pageRequest(filter.PageQuery, repository.ContentSortFields, "id", true)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	contents, total, err := s.contents.SearchAdmin(filter.CategoryID, filter.Type, filter.Status, page)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	return contents, pagination(page, total), nil
 }
 
-// ListPublished returns contents for user.
-func (s *ContentService) ListPublished(userID uint, categoryID uint, contentType string) ([]model.Content, error) {
+// ListPublished returns a page of contents visible to user.
+func (s *ContentService) ListPublished(userID uint, query dto.PublishedContentQuery) ([]model.Content, dto.Pagination, error) {
 	roleFilter, _, err := s.resolveUserRole(userID)
 	if err != nil {
-		return nil, err
+		return nil, dto.Pagination{}, err
 	}
-	return s.contents.ListPublishedByRole(roleFilter, categoryID, contentType)
+	page, err := pageRequest(query.PageQuery, repository.ContentSortFields, "publish_at", true)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	contents, total, err := s.contents.SearchPublished(roleFilter, query.CategoryID, query.Type, page)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	return contents, pagination(page, total), nil
 }
 
 // GetPublishedDetail returns published content if visible to user.
@@ -262,19 +429,53 @@ func (s *ContentService) GetPublishedDetail(userID, id uint) (*model.Content, er
 
 	content, err := s.contents.FindByID(id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContentNotFound
+		}
 		return nil, err
 	}
 	if user.Role != model.RoleAdmin {
 		if content.Status != "published" {
-			return nil, errors.New("内容未发布")
+			return nil, ErrContentNotPublished
 		}
 		if roleFilter != "" && content.VisibleRoles != "both" && content.VisibleRoles != roleFilter {
-			return nil, errors.New("无权查看该内容")
+			return nil, ErrContentForbidden
 		}
 	}
 	return content, nil
 }
 
+// AnswerArticleQuiz grades an answer to the quiz block at blockIndex of an article the user may
+// read. Quiz blocks are self-checks: nothing is recorded, and the answer key and analysis come
+// back with every answer. Graded questions that gate progress are checkpoints (CheckpointService).
+func (s *ContentService) AnswerArticleQuiz(userID, contentID uint, blockIndex int, req dto.ArticleQuizAnswerRequest) (*dto.ArticleQuizAnswerResponse, error) {
+	content, err := s.GetPublishedDetail(userID, contentID)
+	if err != nil {
+		return nil, err
+	}
+	var blocks []dto.ArticleBlock
+	if content.BodyBlocksJSON != "" {
+		if err := json.Unmarshal([]byte(content.BodyBlocksJSON), &blocks); err != nil {
+			return nil, err
+		}
+	}
+	if blockIndex < 0 || blockIndex >= len(blocks) || blocks[blockIndex].Type != ArticleBlockQuiz || blocks[blockIndex].Quiz == nil {
+		return nil, ErrArticleQuizNotFound
+	}
+	quiz := blocks[blockIndex].Quiz
+
+	_, correctIndexes, correct, err := gradeArticleQuiz(quiz.Options, req.OptionIndexes)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ArticleQuizAnswerResponse{
+		BlockIndex:     blockIndex,
+		Correct:        correct,
+		CorrectIndexes: correctIndexes,
+		Analysis:       quiz.Analysis,
+	}, nil
+}
+
 func (s *ContentService) ensureAdmin(userID uint) error {
 	user, err := s.users.FindByID(userID)
 	if err != nil {
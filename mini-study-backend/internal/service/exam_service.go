@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"math"
@@ -15,14 +16,23 @@ import (
 	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
 )
 
+// ErrExamAlreadyTaken is returned when the user already submitted the exam and may not retake it yet.
+var ErrExamAlreadyTaken = errors.New("您已经参加过该考试，不能重复参加")
+
 // ExamService handles exam workflows.
 type ExamService struct {
-	exams     *repository.ExamRepository
-	attempts  *repository.ExamAttemptRepository
-	users     *repository.UserRepository
-	relations *repository.ManagerEmployeeRepository
-	learning  *repository.LearningRecordRepository
-	contents  *repository.ContentRepository
+	exams          *repository.ExamRepository
+	attempts       *repository.ExamAttemptRepository
+	users          *repository.UserRepository
+	relations      *repository.ManagerEmployeeRepository
+	learning       *repository.LearningRecordRepository
+	contents       *repository.ContentRepository
+	rollups        *repository.ReportRollupRepository
+	audit          *AuditService
+	search         *SearchService
+	reviews        *ReviewService
+	certificates   *CertificateService
+	certifications *CertificationService
 }
 
 // NewExamService builds ExamService.
@@ -33,23 +43,39 @@ func NewExamService(
 	relationRepo *repository.ManagerEmployeeRepository,
 	learningRepo *repository.LearningRecordRepository,
 	contentRepo *repository.ContentRepository,
+	rollupRepo *repository.ReportRollupRepository,
+	audit *AuditService,
+	search *SearchService,
+	reviews *ReviewService,
+	certificates *CertificateService,
+	certifications *CertificationService,
 ) *ExamService {
 	return &ExamService{
-		exams:     examRepo,
-		attempts:  attemptRepo,
-		users:     userRepo,
-		relations: relationRepo,
-		learning:  learningRepo,
-		contents:  contentRepo,
+		exams:          examRepo,
+		attempts:       attemptRepo,
+		users:          userRepo,
+		relations:      relationRepo,
+		learning:       learningRepo,
+		contents:       contentRepo,
+		rollups:        rollupRepo,
+		audit:          audit,
+		search:         search,
+		reviews:        reviews,
+		certificates:   certificates,
+		certifications: certifications,
 	}
 }
 
 // AdminCreateExam allows admin to create an exam paper.
-func (s *ExamService) AdminCreateExam(adminID uint, req dto.AdminExamUpsertRequest) (*dto.ExamDetailResponse, error) {
+func (s *ExamService) AdminCreateExam(ctx context.Context, adminID uint, req dto.AdminExamUpsertRequest) (*dto.ExamDetailResponse, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
 
+	if req.Status != "" && req.Status != model.EditorialDraft {
+		return nil, errors.New("新建考试为草稿，需提交审核通过后才能发布")
+	}
+
 	questions, totalScore, err := s.buildQuestionModels(req.Questions)
 	if err != nil {
 		return nil, err
@@ -58,7 +84,7 @@ func (s *ExamService) AdminCreateExam(adminID uint, req dto.AdminExamUpsertReque
 	exam := &model.ExamPaper{
 		Title:            req.Title,
 		Description:      req.Description,
-		Status:           s.normalizeExamStatus(req.Status),
+		Status:           model.EditorialDraft,
 		TargetRole:       model.Role(s.normalizeTargetRole(req.TargetRole)),
 		TimeLimitMinutes: req.TimeLimitMinutes,
 		PassScore:        req.PassScore,
@@ -74,17 +100,29 @@ func (s *ExamService) AdminCreateExam(adminID uint, req dto.AdminExamUpsertReque
 	if err := s.exams.Create(exam); err != nil {
 		return nil, err
 	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "create_exam",
+		Target:     "exam_papers",
+		EntityType: AuditEntityExam,
+		EntityID:   exam.ID,
+		After:      examAuditSnapshot(exam),
+	})
+	_ = s.search.IndexExam(ctx, exam)
 
 	return s.buildExamDetailDTO(exam), nil
 }
 
-// AdminListExams returns all exams for admin.
-func (s *ExamService) AdminListExams(adminID uint) ([]dto.ExamDetailResponse, error) {
+// AdminListExams returns a page of exams for admin.
+func (s *ExamService) AdminListExams(adminID uint, query dto.AdminExamListQuery) (*dto.AdminExamListResponse, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
 
-	exams, err := s.exams.ListAll()
+	page, err := pageRequest(query.PageQuery, repository.ExamSortFields, "id", true)
+	if err != nil {
+		return nil, err
+	}
+	exams, total, err := s.exams.SearchAdmin(query.Status, page)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +137,7 @@ func (s *ExamService) AdminListExams(adminID uint) ([]dto.ExamDetailResponse, er
 		resp = append(resp, *s.buildExamDetailDTOWithAnswers(examWithQuestions, true))
 	}
 
-	return resp, nil
+	return &dto.AdminExamListResponse{Items: resp, Pagination: pagination(page, total)}, nil
 }
 
 // AdminGetExam returns exam detail for admin editing.
@@ -118,7 +156,7 @@ func (s *ExamService) AdminGetExam(adminID, examID uint) (*dto.ExamDetailRespons
 }
 
 // AdminUpdateExam allows admin to update exam and questions.
-func (s *ExamService) AdminUpdateExam(adminID, examID uint, req dto.AdminExamUpsertRequest) (*dto.ExamDetailResponse, error) {
+func (s *ExamService) AdminUpdateExam(ctx context.Context, adminID, examID uint, req dto.AdminExamUpsertRequest) (*dto.ExamDetailResponse, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
@@ -127,6 +165,7 @@ func (s *ExamService) AdminUpdateExam(adminID, examID uint, req dto.AdminExamUps
 	if err != nil {
 		return nil, err
 	}
+	before := examAuditSnapshot(exam)
 
 	questions, totalScore, err := s.buildQuestionModels(req.Questions)
 	if err != nil {
@@ -135,9 +174,6 @@ func (s *ExamService) AdminUpdateExam(adminID, examID uint, req dto.AdminExamUps
 
 	exam.Title = req.Title
 	exam.Description = req.Description
-	if req.Status != "" {
-		exam.Status = s.normalizeExamStatus(req.Status)
-	}
 	if req.TargetRole != "" {
 		exam.TargetRole = model.Role(s.normalizeTargetRole(req.TargetRole))
 	}
@@ -151,29 +187,94 @@ func (s *ExamService) AdminUpdateExam(adminID, examID uint, req dto.AdminExamUps
 		return nil, errors.New("及格分不能高于总分")
 	}
 
+	// 题目整体替换，以替换后的题目判断试卷是否被修改
+	fromStatus := exam.Status
+	exam.Questions = questions
+	edited := editorialEdited(before, examAuditSnapshot(exam))
+	status, err := NextEditorialStatus(fromStatus, req.Status, edited)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != "" || status != fromStatus {
+		exam.Status = status
+		clearSupersededSchedule(exam.Status, &exam.ScheduledPublishAt, &exam.ScheduledOfflineAt)
+	}
+
 	if err := s.exams.UpdateExam(exam); err != nil {
 		return nil, err
 	}
 	if err := s.exams.ReplaceQuestions(exam.ID, questions); err != nil {
 		return nil, err
 	}
+	if status != fromStatus {
+		_ = s.reviews.recordTransition(AuditEntityExam, exam.ID, editorialAction(status), fromStatus, status, adminID, exam.ReviewerID, editorialNote(fromStatus, status, edited))
+	}
 
 	updated, err := s.exams.FindWithQuestions(exam.ID)
 	if err != nil {
 		return nil, err
 	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "update_exam",
+		Target:     "exam_papers",
+		EntityType: AuditEntityExam,
+		EntityID:   exam.ID,
+		Before:     before,
+		After:      examAuditSnapshot(updated),
+	})
+	_ = s.search.IndexExam(ctx, updated)
 
 	return s.buildExamDetailDTO(updated), nil
 }
 
-// ListAvailableExams returns published exams for current user.
-func (s *ExamService) ListAvailableExams(userID uint) ([]dto.ExamListItem, error) {
+// examAuditSnapshot captures an exam with its questions. Question and option IDs are left out
+// because updates replace them, which would otherwise show every question as changed.
+func examAuditSnapshot(exam *model.ExamPaper) map[string]interface{} {
+	questions := make([]map[string]interface{}, 0, len(exam.Questions))
+	for _, q := range exam.Questions {
+		options := make([]map[string]interface{}, 0, len(q.Options))
+		for _, opt := range q.Options {
+			options = append(options, map[string]interface{}{
+				"label":      opt.Label,
+				"content":    opt.Content,
+				"is_correct": opt.IsCorrect,
+			})
+		}
+		questions = append(questions, map[string]interface{}{
+			"type":     q.Type,
+			"stem":     q.Stem,
+			"score":    q.Score,
+			"analysis": q.Analysis,
+			"options":  options,
+		})
+	}
+	return map[string]interface{}{
+		"title":              exam.Title,
+		"description":        exam.Description,
+		"status":             exam.Status,
+		"target_role":        exam.TargetRole,
+		"time_limit_minutes": exam.TimeLimitMinutes,
+		"pass_score":         exam.PassScore,
+		"total_score":        exam.TotalScore,
+		"questions":          questions,
+
+		"scheduled_publish_at": exam.ScheduledPublishAt,
+		"scheduled_offline_at": exam.ScheduledOfflineAt,
+	}
+}
+
+// ListAvailableExams returns a page of published exams for current user.
+func (s *ExamService) ListAvailableExams(userID uint, query dto.PageQuery) (*dto.ExamListResponse, error) {
 	user, err := s.users.FindByID(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	exams, err := s.exams.ListPublishedByRole(user.Role)
+	page, err := pageRequest(query, repository.ExamSortFields, "id", true)
+	if err != nil {
+		return nil, err
+	}
+	exams, total, err := s.exams.SearchPublishedByRole(user.Role, page)
 	if err != nil {
 		return nil, err
 	}
@@ -183,6 +284,8 @@ func (s *ExamService) ListAvailableExams(userID uint) ([]dto.ExamListItem, error
 		return nil, err
 	}
 
+	certificationStates := s.certifications.examStates(userID, time.Now())
+
 	latestByExam := make(map[uint]model.ExamAttempt)
 	for _, attempt := range attempts {
 		if _, exists := latestByExam[attempt.ExamID]; exists {
@@ -222,11 +325,16 @@ func (s *ExamService) ListAvailableExams(userID uint) ([]dto.ExamListItem, error
 				item.AttemptStatus = "attempted"
 			}
 		}
+		if state, ok := certificationStates[exam.ID]; ok {
+			item.CertificationStatus = state.Status
+			item.CertificationExpiresAt = state.ExpiresAt
+			item.CanRetake = state.CanRetake
+		}
 
 		resp = append(resp, item)
 	}
 
-	return resp, nil
+	return &dto.ExamListResponse{Items: resp, Pagination: pagination(page, total)}, nil
 }
 
 // GetExamDetail returns exam detail for answering.
@@ -248,8 +356,10 @@ func (s *ExamService) GetExamDetail(userID, examID uint) (*dto.ExamDetailRespons
 	return s.buildExamDetailDTO(exam), nil
 }
 
-// SubmitExam evaluates answers and stores attempt.
-func (s *ExamService) SubmitExam(userID, examID uint, req dto.ExamSubmitRequest) (*dto.ExamSubmitResponse, error) {
+// SubmitExam evaluates answers and stores attempt. A passed attempt is issued a certificate when
+// the exam has a certificate template, and renews the certification the exam backs. An exam can
+// only be taken once, unless it backs a certification whose renewal window has opened.
+func (s *ExamService) SubmitExam(ctx context.Context, userID, examID uint, req dto.ExamSubmitRequest) (*dto.ExamSubmitResponse, error) {
 	user, err := s.users.FindByID(userID)
 	if err != nil {
 		return nil, err
@@ -264,9 +374,18 @@ func (s *ExamService) SubmitExam(userID, examID uint, req dto.ExamSubmitRequest)
 	}
 
 	// 检查用户是否已经参加过这个考试
+	cycle := 1
 	existingAttempt, err := s.attempts.FindLatestByUserAndExam(userID, examID)
 	if err == nil && existingAttempt != nil {
-		return nil, errors.New("您已经参加过该考试，不能重复参加")
+		// 认证进入重新认证窗口或已过期时允许再次参加，并进入下一轮次
+		retake, retakeErr := s.certifications.CanRetake(userID, examID, time.Now())
+		if retakeErr != nil {
+			return nil, retakeErr
+		}
+		if !retake {
+			return nil, ErrExamAlreadyTaken
+		}
+		cycle = existingAttempt.Cycle + 1
 	}
 	// 如果错误是记录不存在，可以继续；其他错误需要返回
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -338,6 +457,7 @@ func (s *ExamService) SubmitExam(userID, examID uint, req dto.ExamSubmitRequest)
 	attempt := &model.ExamAttempt{
 		ExamID:          exam.ID,
 		UserID:          userID,
+		Cycle:           cycle,
 		Status:          "submitted",
 		Score:           totalScore,
 		CorrectCount:    correctCount,
@@ -348,11 +468,15 @@ func (s *ExamService) SubmitExam(userID, examID uint, req dto.ExamSubmitRequest)
 		SubmittedAt:     &now,
 	}
 
+	// 同一轮次只能提交一次，并发提交时由唯一索引拦下后到的一份
 	if err := s.attempts.Create(attempt); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrExamAlreadyTaken
+		}
 		return nil, err
 	}
 
-	return &dto.ExamSubmitResponse{
+	resp := &dto.ExamSubmitResponse{
 		AttemptID:       attempt.ID,
 		ExamID:          exam.ID,
 		Score:           totalScore,
@@ -362,15 +486,37 @@ func (s *ExamService) SubmitExam(userID, examID uint, req dto.ExamSubmitRequest)
 		TotalCount:      len(exam.Questions),
 		DurationSeconds: req.DurationSeconds,
 		Answers:         reviews,
-	}, nil
+	}
+	if pass {
+		// 证书生成失败不影响交卷，下载证书时会重新生成
+		attempt.Exam = *exam
+		if issued, err := s.certificates.Issue(ctx, attempt); err == nil {
+			resp.CertificateSerial = issued.Serial
+		}
+		// 认证记录失败不影响交卷，修改认证时会按考试记录重新同步
+		if held, err := s.certifications.RecordPass(attempt); err == nil && held != nil {
+			resp.CertificationExpiresAt = &held.ExpiresAt
+		}
+	}
+	return resp, nil
 }
 
-// ListMyResults returns attempt summaries for user.
-func (s *ExamService) ListMyResults(userID uint) ([]dto.ExamResultSummary, error) {
-	attempts, err := s.attempts.ListByUser(userID)
+// ListMyResults returns a page of attempt summaries for user.
+func (s *ExamService) ListMyResults(userID uint, query dto.PageQuery) (*dto.ExamResultListResponse, error) {
+	page, err := pageRequest(query, repository.ExamAttemptSortFields, "created_at", true)
 	if err != nil {
 		return nil, err
 	}
+	attempts, total, err := s.attempts.SearchByUser(userID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptIDs := make([]uint, 0, len(attempts))
+	for _, attempt := range attempts {
+		attemptIDs = append(attemptIDs, attempt.ID)
+	}
+	serials := s.certificates.serialsByAttempts(attemptIDs)
 
 	results := make([]dto.ExamResultSummary, 0, len(attempts))
 	for _, attempt := range attempts {
@@ -390,10 +536,12 @@ func (s *ExamService) ListMyResults(userID uint) ([]dto.ExamResultSummary, error
 			PassScore:   attempt.Exam.PassScore,
 			Pass:        attempt.Pass,
 			SubmittedAt: submittedAt,
+
+			CertificateSerial: serials[attempt.ID],
 		})
 	}
 
-	return results, nil
+	return &dto.ExamResultListResponse{Items: results, Pagination: pagination(page, total)}, nil
 }
 
 // GetManagerOverview returns learning/exam summary for manager employees.
@@ -422,17 +570,7 @@ func (s *ExamService) GetManagerOverview(managerID uint) (*dto.ManagerExamOvervi
 		return nil, err
 	}
 
-	learningAgg, err := s.learning.AggregateByUsers(employeeIDs)
-	if err != nil {
-		return nil, err
-	}
-
-	latestAttempts, err := s.attempts.ListLatestByUsers(employeeIDs)
-	if err != nil {
-		return nil, err
-	}
-
-	examStats, err := s.attempts.AggregateByExamForUsers(employeeIDs)
+	summaries, progressList, refreshedAt, err := s.overviewStats(employeeIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -442,49 +580,6 @@ func (s *ExamService) GetManagerOverview(managerID uint) (*dto.ManagerExamOvervi
 		return nil, err
 	}
 
-	examIDSet := make(map[uint]struct{}, len(examStats))
-	examIDs := make([]uint, 0, len(examStats))
-	for _, row := range examStats {
-		if _, exists := examIDSet[row.ExamID]; exists {
-			continue
-		}
-		examIDSet[row.ExamID] = struct{}{}
-		examIDs = append(examIDs, row.ExamID)
-	}
-
-	examInfos := make(map[uint]model.ExamPaper)
-	if len(examIDs) > 0 {
-		exams, err := s.exams.FindByIDs(examIDs)
-		if err != nil {
-			return nil, err
-		}
-		for _, exam := range exams {
-			examInfos[exam.ID] = exam
-		}
-	}
-
-	progressList := make([]dto.ManagerExamProgressItem, 0, len(examStats))
-	for _, row := range examStats {
-		info, ok := examInfos[row.ExamID]
-		if !ok {
-			continue
-		}
-		passRate := 0.0
-		if row.AttemptCount > 0 {
-			passRate = float64(row.PassCount) / float64(row.AttemptCount)
-		}
-		progressList = append(progressList, dto.ManagerExamProgressItem{
-			ExamID:       row.ExamID,
-			Title:        info.Title,
-			AttemptCount: row.AttemptCount,
-			PassRate:     math.Round(passRate*1000) / 1000,
-			AvgScore:     math.Round(row.AvgScore*10) / 10,
-		})
-	}
-	sort.Slice(progressList, func(i, j int) bool {
-		return progressList[i].AttemptCount > progressList[j].AttemptCount
-	})
-
 	sort.Slice(employees, func(i, j int) bool {
 		return employees[i].Name < employees[j].Name
 	})
@@ -493,40 +588,9 @@ func (s *ExamService) GetManagerOverview(managerID uint) (*dto.ManagerExamOvervi
 	total := int(totalContents)
 
 	for _, emp := range employees {
-		agg := learningAgg[emp.ID]
-		completed := int(agg.Completed)
-		pending := total - completed
-		if pending < 0 {
-			pending = 0
-		}
-		percent := 0
-		if total > 0 {
-			percent = int(math.Round(float64(completed) * 100 / float64(total)))
-		}
-
-		progress := dto.EmployeeLearningProgress{
-			Completed: completed,
-			Total:     total,
-			Pending:   pending,
-			Percent:   percent,
-		}
-
-		var latest *dto.EmployeeLatestExamResult
-		if att, ok := latestAttempts[emp.ID]; ok {
-			if att.Exam.ID > 0 {
-				submitted := att.CreatedAt
-				if att.SubmittedAt != nil {
-					submitted = *att.SubmittedAt
-				}
-				latest = &dto.EmployeeLatestExamResult{
-					ExamID:      att.ExamID,
-					ExamTitle:   att.Exam.Title,
-					Score:       att.Score,
-					Pass:        att.Pass,
-					SubmittedAt: submitted,
-				}
-			}
-		}
+		summary := summaries[emp.ID]
+		progress := overviewLearningProgress(summary, total)
+		latest := overviewLatestExam(summary)
 
 		employeeRecords = append(employeeRecords, dto.ManagerEmployeeExamRecord{
 			EmployeeID:       emp.ID,
@@ -540,6 +604,7 @@ func (s *ExamService) GetManagerOverview(managerID uint) (*dto.ManagerExamOvervi
 	return &dto.ManagerExamOverviewResponse{
 		ExamProgress: progressList,
 		Employees:    employeeRecords,
+		RefreshedAt:  refreshedAt,
 	}, nil
 }
 
@@ -638,11 +703,7 @@ func (s *ExamService) GetAdminOverview(adminID uint, query dto.AdminExamOverview
 		return &dto.AdminExamOverviewResponse{
 			ExamProgress: []dto.ManagerExamProgressItem{},
 			Users:        []dto.AdminUserExamRecord{},
-			Pagination: dto.Pagination{
-				Page:     page,
-				PageSize: size,
-				Total:    0,
-			},
+			Pagination:   dto.NewPagination(page, size, 0),
 		}, nil
 	}
 
@@ -652,17 +713,7 @@ func (s *ExamService) GetAdminOverview(adminID uint, query dto.AdminExamOverview
 		userIDs = append(userIDs, u.ID)
 	}
 
-	learningAgg, err := s.learning.AggregateByUsers(userIDs)
-	if err != nil {
-		return nil, err
-	}
-
-	latestAttempts, err := s.attempts.ListLatestByUsers(userIDs)
-	if err != nil {
-		return nil, err
-	}
-
-	examStats, err := s.attempts.AggregateByExamForUsers(userIDs)
+	summaries, progressList, refreshedAt, err := s.overviewStats(userIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -673,49 +724,6 @@ func (s *ExamService) GetAdminOverview(adminID uint, query dto.AdminExamOverview
 		return nil, err
 	}
 
-	examIDSet := make(map[uint]struct{}, len(examStats))
-	examIDs := make([]uint, 0, len(examStats))
-	for _, row := range examStats {
-		if _, exists := examIDSet[row.ExamID]; exists {
-			continue
-		}
-		examIDSet[row.ExamID] = struct{}{}
-		examIDs = append(examIDs, row.ExamID)
-	}
-
-	examInfos := make(map[uint]model.ExamPaper)
-	if len(examIDs) > 0 {
-		exams, err := s.exams.FindByIDs(examIDs)
-		if err != nil {
-			return nil, err
-		}
-		for _, exam := range exams {
-			examInfos[exam.ID] = exam
-		}
-	}
-
-	progressList := make([]dto.ManagerExamProgressItem, 0, len(examStats))
-	for _, row := range examStats {
-		info, ok := examInfos[row.ExamID]
-		if !ok {
-			continue
-		}
-		passRate := 0.0
-		if row.AttemptCount > 0 {
-			passRate = float64(row.PassCount) / float64(row.AttemptCount)
-		}
-		progressList = append(progressList, dto.ManagerExamProgressItem{
-			ExamID:       row.ExamID,
-			Title:        info.Title,
-			AttemptCount: row.AttemptCount,
-			PassRate:     math.Round(passRate*1000) / 1000,
-			AvgScore:     math.Round(row.AvgScore*10) / 10,
-		})
-	}
-	sort.Slice(progressList, func(i, j int) bool {
-		return progressList[i].AttemptCount > progressList[j].AttemptCount
-	})
-
 	// Sort users by name for stable ordering.
 	sort.Slice(users, func(i, j int) bool {
 		return users[i].Name < users[j].Name
@@ -739,40 +747,9 @@ func (s *ExamService) GetAdminOverview(adminID uint, query dto.AdminExamOverview
 
 	userRecords := make([]dto.AdminUserExamRecord, 0, len(pagedUsers))
 	for _, u := range pagedUsers {
-		agg := learningAgg[u.ID]
-		completed := int(agg.Completed)
-		pending := totalContentsInt - completed
-		if pending < 0 {
-			pending = 0
-		}
-		percent := 0
-		if totalContentsInt > 0 {
-			percent = int(math.Round(float64(completed) * 100 / float64(totalContentsInt)))
-		}
-
-		progress := dto.EmployeeLearningProgress{
-			Completed: completed,
-			Total:     totalContentsInt,
-			Pending:   pending,
-			Percent:   percent,
-		}
-
-		var latest *dto.EmployeeLatestExamResult
-		if att, ok := latestAttempts[u.ID]; ok {
-			if att.Exam.ID > 0 {
-				submitted := att.CreatedAt
-				if att.SubmittedAt != nil {
-					submitted = *att.SubmittedAt
-				}
-				latest = &dto.EmployeeLatestExamResult{
-					ExamID:      att.ExamID,
-					ExamTitle:   att.Exam.Title,
-					Score:       att.Score,
-					Pass:        att.Pass,
-					SubmittedAt: submitted,
-				}
-			}
-		}
+		summary := summaries[u.ID]
+		progress := overviewLearningProgress(summary, totalContentsInt)
+		latest := overviewLatestExam(summary)
 
 		userRecords = append(userRecords, dto.AdminUserExamRecord{
 			UserID:           u.ID,
@@ -787,14 +764,81 @@ func (s *ExamService) GetAdminOverview(adminID uint, query dto.AdminExamOverview
 	return &dto.AdminExamOverviewResponse{
 		ExamProgress: progressList,
 		Users:        userRecords,
-		Pagination: dto.Pagination{
-			Page:     page,
-			PageSize: size,
-			Total:    int64(totalUsers),
-		},
+		Pagination:   dto.NewPagination(page, size, int64(totalUsers)),
+		RefreshedAt:  refreshedAt,
 	}, nil
 }
 
+// overviewStats reads the rollup summaries of the given users and their results per exam,
+// ordered by attempt count, along with when the rollups were last refreshed. The overviews
+// take no date range: the summaries cover every attempt, independent of how many days the
+// daily rollups hold.
+func (s *ExamService) overviewStats(userIDs []uint) (map[uint]model.UserReportSummary, []dto.ManagerExamProgressItem, *time.Time, error) {
+	summaries, err := s.rollups.SummariesByUsers(userIDs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	examStats, err := s.rollups.ExamStatsForUsers(userIDs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	refreshedAt, err := rollupRefreshedAt(s.rollups)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	progressList := make([]dto.ManagerExamProgressItem, 0, len(examStats))
+	for _, row := range examStats {
+		passRate, avgScore := 0.0, 0.0
+		if row.Attempts > 0 {
+			passRate = float64(row.Passed) / float64(row.Attempts)
+			avgScore = float64(row.ScoreSum) / float64(row.Attempts)
+		}
+		progressList = append(progressList, dto.ManagerExamProgressItem{
+			ExamID:       row.ExamID,
+			Title:        row.ExamTitle,
+			AttemptCount: row.Attempts,
+			PassRate:     math.Round(passRate*1000) / 1000,
+			AvgScore:     math.Round(avgScore*10) / 10,
+		})
+	}
+	sort.Slice(progressList, func(i, j int) bool {
+		return progressList[i].AttemptCount > progressList[j].AttemptCount
+	})
+	return summaries, progressList, refreshedAt, nil
+}
+
+func overviewLearningProgress(summary model.UserReportSummary, total int) dto.EmployeeLearningProgress {
+	completed := int(summary.Completed)
+	pending := total - completed
+	if pending < 0 {
+		pending = 0
+	}
+	percent := 0
+	if total > 0 {
+		percent = int(math.Round(float64(completed) * 100 / float64(total)))
+	}
+	return dto.EmployeeLearningProgress{
+		Completed: completed,
+		Total:     total,
+		Pending:   pending,
+		Percent:   percent,
+	}
+}
+
+func overviewLatestExam(summary model.UserReportSummary) *dto.EmployeeLatestExamResult {
+	if summary.LatestExamID == 0 || summary.LatestSubmittedAt == nil {
+		return nil
+	}
+	return &dto.EmployeeLatestExamResult{
+		ExamID:      summary.LatestExamID,
+		ExamTitle:   summary.LatestExamTitle,
+		Score:       summary.LatestScore,
+		Pass:        summary.LatestPass,
+		SubmittedAt: *summary.LatestSubmittedAt,
+	}
+}
+
 func (s *ExamService) ensureAdmin(userID uint) error {
 	user, err := s.users.FindByID(userID)
 	if err != nil {
@@ -819,15 +863,6 @@ func (s *ExamService) ensureExamAccessible(role model.Role, exam *model.ExamPape
 	return nil
 }
 
-func (s *ExamService) normalizeExamStatus(status string) string {
-	switch status {
-	case "published", "archived":
-		return status
-	default:
-		return "draft"
-	}
-}
-
 func (s *ExamService) normalizeTargetRole(role string) string {
 	switch role {
 	case "manager", "all":
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// Upload purposes accepted by the file upload endpoint.
+const (
+	UploadPurposeAvatar      = "avatar"
+	UploadPurposeCover       = "cover"
+	UploadPurposeVideo       = "video"
+	UploadPurposeGrowthImage = "growth_image"
+	UploadPurposeDocument    = "document"
+)
+
+// uploadPolicy lists the extensions allowed for a purpose and the MIME types their
+// content must sniff as. Private purposes hold learning materials served via signed URLs.
+type uploadPolicy struct {
+	private bool
+	types   map[string][]string
+}
+
+var (
+	imageTypes = map[string][]string{
+		".jpg":  {"image/jpeg"},
+		".jpeg": {"image/jpeg"},
+		".png":  {"image/png"},
+		".webp": {"image/webp"},
+	}
+	// OOXML files are zip archives and OLE2 files share one container format; the sniffer
+	// may only see the container within its read limit, so the container type is accepted too.
+	documentTypes = map[string][]string{
+		".pdf":  {"application/pdf"},
+		".doc":  {"application/msword", "application/x-ole-storage"},
+		".xls":  {"application/vnd.ms-excel", "application/x-ole-storage"},
+		".ppt":  {"application/vnd.ms-powerpoint", "application/x-ole-storage"},
+		".docx": {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", "application/zip"},
+		".xlsx": {"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "application/zip"},
+		".pptx": {"application/vnd.openxmlformats-officedocument.presentationml.presentation", "application/zip"},
+		".txt":  {"text/plain"},
+	}
+	videoTypes = map[string][]string{
+		".mp4": {"video/mp4"},
+		".m4v": {"video/x-m4v", "video/mp4"},
+		".mov": {"video/quicktime"},
+	}
+)
+
+var uploadPolicies = map[string]uploadPolicy{
+	UploadPurposeAvatar:      {types: imageTypes},
+	UploadPurposeCover:       {types: withTypes(imageTypes, map[string][]string{".gif": {"image/gif"}})},
+	UploadPurposeGrowthImage: {types: withTypes(imageTypes, map[string][]string{".gif": {"image/gif"}})},
+	UploadPurposeVideo:       {private: true, types: videoTypes},
+	UploadPurposeDocument:    {private: true, types: documentTypes},
+}
+
+// FileService validates uploads and stores them through the configured storage backend.
+type FileService struct {
+	store     storage.Storage
+	signer    *storage.URLSigner
+	audit     *AuditService
+	maxSize   int64
+	signedTTL time.Duration
+}
+
+// NewFileService builds a FileService. signer verifies links issued by the local backend.
+func NewFileService(store storage.Storage, signer *storage.URLSigner, audit *AuditService, maxSizeMB int, signedTTL time.Duration) *FileService {
+	return &FileService{
+		store:     store,
+		signer:    signer,
+		audit:     audit,
+		maxSize:   int64(maxSizeMB) * 1024 * 1024,
+		signedTTL: signedTTL,
+	}
+}
+
+// Upload sniffs and validates the file against the purpose policy, then stores it under a
+// content-addressed key so identical files are stored once.
+func (s *FileService) Upload(ctx context.Context, userID uint, purpose string, file *multipart.FileHeader) (*dto.UploadResponse, error) {
+	if file.Size > s.maxSize {
+		return nil, fmt.Errorf("文件大小不能超过 %dMB，大文件请使用分片上传", s.maxSize/1024/1024)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open upload: %w", err)
+	}
+	defer src.Close()
+
+	return s.save(ctx, userID, purpose, file.Filename, src, file.Size, "")
+}
+
+// ResolvePurpose validates filename against the purpose policy, inferring the purpose from
+// the extension when it is empty, and returns the effective purpose.
+func (s *FileService) ResolvePurpose(purpose, filename string) (string, error) {
+	purpose, _, _, err := resolveUploadPolicy(purpose, filename)
+	return purpose, err
+}
+
+// save validates and persists src. When expectedSHA256 is set the content must match it.
+func (s *FileService) save(ctx context.Context, userID uint, purpose, filename string, src io.ReadSeeker, size int64, expectedSHA256 string) (*dto.UploadResponse, error) {
+	purpose, ext, policy, err := resolveUploadPolicy(purpose, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType, err := sniffContentType(src, policy.types[ext])
+	if err != nil {
+		return nil, err
+	}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, src); err != nil {
+		return nil, fmt.Errorf("hash upload: %w", err)
+	}
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	if expectedSHA256 != "" && !strings.EqualFold(expectedSHA256, sum) {
+		return nil, errors.New("文件校验和不一致")
+	}
+
+	prefix := storage.PublicPrefix
+	if policy.private {
+		prefix = storage.PrivatePrefix
+	}
+	key := fmt.Sprintf("%s%s/%s/%s%s", prefix, purpose, sum[:2], sum, ext)
+
+	if _, err := s.store.Stat(ctx, key); err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			return nil, err
+		}
+		if _, err := src.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("rewind upload: %w", err)
+		}
+		if err := s.store.Put(ctx, key, src, size, contentType); err != nil {
+			return nil, err
+		}
+	}
+
+	_ = s.audit.Record(ctx, userID, "upload", "files", utils.ToJSONString(map[string]interface{}{
+		"filename":     filename,
+		"size":         size,
+		"purpose":      purpose,
+		"content_type": contentType,
+		"key":          key,
+	}), "success")
+	return s.Describe(ctx, key, purpose, contentType, size)
+}
+
+// Describe builds the upload response for a stored object.
+func (s *FileService) Describe(ctx context.Context, key, purpose, contentType string, size int64) (*dto.UploadResponse, error) {
+	resp := &dto.UploadResponse{
+		Key:         key,
+		Purpose:     purpose,
+		ContentType: contentType,
+		Size:        size,
+		Private:     storage.IsPrivateKey(key),
+	}
+	if resp.Private {
+		signed, err := s.store.SignedURL(ctx, key, s.signedTTL)
+		if err != nil {
+			return nil, err
+		}
+		resp.Path = key
+		resp.URL = signed
+	} else {
+		resp.Path = s.store.PublicURL(key)
+		resp.URL = resp.Path
+	}
+	return resp, nil
+}
+
+// ResolveURL turns a stored path into a URL the client can open: private storage keys get a
+// short-lived signed URL, anything else (public URLs, legacy /uploads paths) is returned as is.
+func (s *FileService) ResolveURL(ctx context.Context, path string) string {
+	if !storage.IsPrivateKey(path) {
+		return path
+	}
+	signed, err := s.store.SignedURL(ctx, path, s.signedTTL)
+	if err != nil {
+		return ""
+	}
+	return signed
+}
+
+// OpenSigned verifies a signed download link issued by the local backend and opens the object.
+func (s *FileService) OpenSigned(ctx context.Context, key string, query dto.SignedDownloadQuery) (io.ReadCloser, *storage.ObjectInfo, error) {
+	if _, err := storage.CleanKey(key); err != nil {
+		return nil, nil, err
+	}
+	if err := s.signer.Verify(key, query.Expires, query.Signature, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	return s.store.Get(ctx, key)
+}
+
+// ContentFile is a learning file opened for a reader who may see the owning content.
+type ContentFile struct {
+	Key       string
+	Body      io.ReadCloser // nil when Redirect is set
+	Info      *storage.ObjectInfo
+	Redirect  string // presigned URL for backends that serve byte ranges themselves
+	Watermark string // identifies the reader and time of this download in access logs
+}
+
+// OpenContentFile opens the file of a content the caller has already been authorised to read.
+// Legacy files referenced by their public /uploads URL are mapped back to their storage key.
+func (s *FileService) OpenContentFile(ctx context.Context, userID uint, content *model.Content) (*ContentFile, error) {
+	key := s.storageKey(content.FilePath)
+	if key == "" {
+		return nil, storage.ErrNotFound
+	}
+	body, info, redirect, err := s.openSeekable(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	issuedAt := time.Now().Unix()
+	subject := "watermark:" + strconv.FormatUint(uint64(userID), 10) + ":" + strconv.FormatUint(uint64(content.ID), 10)
+	return &ContentFile{
+		Key:       key,
+		Body:      body,
+		Info:      info,
+		Redirect:  redirect,
+		Watermark: strconv.FormatInt(issuedAt, 36) + "-" + s.signer.Sign(subject, issuedAt)[:16],
+	}, nil
+}
+
+// openSeekable opens key for byte-range serving. Backends whose objects cannot be seeked
+// (object storage) return a presigned URL instead; the object store answers Range itself.
+func (s *FileService) openSeekable(ctx context.Context, key string) (io.ReadCloser, *storage.ObjectInfo, string, error) {
+	if _, local := s.store.(*storage.LocalStorage); local {
+		body, info, err := s.store.Get(ctx, key)
+		return body, info, "", err
+	}
+	info, err := s.store.Stat(ctx, key)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	redirect, err := s.store.SignedURL(ctx, key, s.signedTTL)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return nil, info, redirect, nil
+}
+
+// storageKey maps a stored file path to its storage key, or "" for external URLs.
+func (s *FileService) storageKey(path string) string {
+	if strings.HasPrefix(path, storage.PrivatePrefix) || strings.HasPrefix(path, storage.PublicPrefix) {
+		return path
+	}
+	if base := s.store.PublicURL(storage.PublicPrefix); strings.HasPrefix(path, base) {
+		return storage.PublicPrefix + strings.TrimPrefix(path, base)
+	}
+	return ""
+}
+
+// sniffContentType detects the MIME type from the file header and checks it against allowed.
+// The reader is left positioned at the start.
+func sniffContentType(src io.ReadSeeker, allowed []string) (string, error) {
+	header := make([]byte, 3072)
+	n, err := io.ReadFull(src, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read upload: %w", err)
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("rewind upload: %w", err)
+	}
+
+	detected := mimetype.Detect(header[:n])
+	for m := detected; m != nil; m = m.Parent() {
+		for _, candidate := range allowed {
+			if m.Is(candidate) {
+				return allowed[0], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("文件内容(%s)与扩展名不符", detected.String())
+}
+
+// resolveUploadPolicy returns the effective purpose, lower-cased extension and policy for filename.
+func resolveUploadPolicy(purpose, filename string) (string, string, uploadPolicy, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if purpose == "" {
+		purpose = inferUploadPurpose(ext)
+	}
+	policy, ok := uploadPolicies[purpose]
+	if !ok {
+		return "", "", uploadPolicy{}, errors.New("不支持的文件类型")
+	}
+	if _, ok := policy.types[ext]; !ok {
+		return "", "", uploadPolicy{}, fmt.Errorf("用途 %s 不允许上传 %s 文件", purpose, ext)
+	}
+	return purpose, ext, policy, nil
+}
+
+func inferUploadPurpose(ext string) string {
+	switch {
+	case uploadPolicies[UploadPurposeCover].types[ext] != nil:
+		return UploadPurposeCover
+	case videoTypes[ext] != nil:
+		return UploadPurposeVideo
+	case documentTypes[ext] != nil:
+		return UploadPurposeDocument
+	default:
+		return ""
+	}
+}
+
+func withTypes(base, extra map[string][]string) map[string][]string {
+	merged := make(map[string][]string, len(base)+len(extra))
+	for ext, types := range base {
+		merged[ext] = types
+	}
+	for ext, types := range extra {
+		merged[ext] = types
+	}
+	return merged
+}
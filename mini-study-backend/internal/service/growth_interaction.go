@@ -0,0 +1,420 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+)
+
+// growthNotifySnippet 通知摘要中引用评论内容的最大字数。
+const growthNotifySnippet = 40
+
+// Like 点赞已公开的动态，重复点赞不会重复计数，首次点赞时通知作者。
+func (s *GrowthService) Like(userID, postID uint) (*dto.GrowthLikeResponse, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := s.approvedPost(user.ID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.posts.Like(post.ID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		_ = s.notifications.Notify(post.CreatorID, user.ID, NotificationGrowthLike, "growth_posts", post.ID,
+			user.Name+" 赞了你的动态")
+	}
+	return s.likeResponse(post.ID, true)
+}
+
+// Unlike 取消点赞，未点赞时直接返回当前状态。
+func (s *GrowthService) Unlike(userID, postID uint) (*dto.GrowthLikeResponse, error) {
+	post, err := s.approvedPost(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.posts.Unlike(post.ID, userID); err != nil {
+		return nil, err
+	}
+	return s.likeResponse(post.ID, false)
+}
+
+// ListLikers 按游标分页返回点赞用户，最近点赞的在前。
+func (s *GrowthService) ListLikers(userID, postID uint, query dto.CursorQuery) (*dto.GrowthLikerListResponse, error) {
+	post, err := s.approvedPost(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := cursorRequest(query)
+	if err != nil {
+		return nil, err
+	}
+	likes, total, more, err := s.posts.FeedLikers(post.ID, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.GrowthLikerResponse, 0, len(likes))
+	for _, like := range likes {
+		items = append(items, dto.GrowthLikerResponse{
+			UserID:  like.UserID,
+			Name:    like.User.Name,
+			Role:    string(like.User.Role),
+			LikedAt: like.CreatedAt,
+		})
+	}
+	var last model.GrowthLike
+	if len(likes) > 0 {
+		last = likes[len(likes)-1]
+	}
+	return &dto.GrowthLikerListResponse{
+		Items:      items,
+		Pagination: feedPagination(cursor, total, more, last.CreatedAt, last.ID),
+	}, nil
+}
+
+// Share 记录分享，同一用户重复分享不会重复计数，首次分享时通知作者。
+func (s *GrowthService) Share(userID, postID uint, req dto.GrowthShareRequest) (*dto.GrowthShareResponse, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := s.approvedPost(user.ID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.posts.Share(post.ID, user.ID, req.Channel)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		_ = s.notifications.Notify(post.CreatorID, user.ID, NotificationGrowthShare, "growth_posts", post.ID,
+			user.Name+" 分享了你的动态")
+	}
+
+	post, err = s.posts.FindByID(post.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.GrowthShareResponse{PostID: post.ID, ShareCount: post.ShareCount}, nil
+}
+
+// ListComments 按游标分页返回动态下已审核通过的顶层评论，最新的在前；每条评论下按时间顺序嵌套
+// 其全部已通过的回复，被隐藏评论下的回复不返回。
+func (s *GrowthService) ListComments(userID, postID uint, query dto.CursorQuery) (*dto.GrowthCommentListResponse, error) {
+	post, err := s.approvedPost(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := cursorRequest(query)
+	if err != nil {
+		return nil, err
+	}
+	roots, total, more, err := s.posts.FeedApprovedComments(post.ID, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := roots
+	parentIDs := make([]uint, 0, len(roots))
+	for _, root := range roots {
+		parentIDs = append(parentIDs, root.ID)
+	}
+	for len(parentIDs) > 0 {
+		replies, err := s.posts.ListApprovedReplies(parentIDs)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, replies...)
+		parentIDs = parentIDs[:0]
+		for _, reply := range replies {
+			parentIDs = append(parentIDs, reply.ID)
+		}
+	}
+
+	var last model.GrowthComment
+	if len(roots) > 0 {
+		last = roots[len(roots)-1]
+	}
+	return &dto.GrowthCommentListResponse{
+		Items:      growthCommentThreads(comments),
+		Pagination: feedPagination(cursor, total, more, last.CreatedAt, last.ID),
+	}, nil
+}
+
+// CreateComment 发表评论或回复，与动态一样需管理员审核通过后才公开。
+func (s *GrowthService) CreateComment(ctx context.Context, userID, postID uint, req dto.GrowthCommentRequest) (*dto.GrowthCommentResponse, error) {
+	if _, err := s.users.FindByID(userID); err != nil {
+		return nil, err
+	}
+	post, err := s.approvedPost(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, errors.New("评论内容不能为空")
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.posts.FindComment(*req.ParentID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if parent == nil || parent.PostID != post.ID || parent.Status != GrowthStatusApproved {
+			return nil, errors.New("回复的评论不存在")
+		}
+	}
+
+	comment := &model.GrowthComment{
+		PostID:   post.ID,
+		ParentID: req.ParentID,
+		UserID:   userID,
+		Body:     body,
+		Status:   GrowthStatusPending,
+	}
+	if err := s.posts.CreateComment(comment); err != nil {
+		return nil, err
+	}
+	if s.audit != nil {
+		_ = s.audit.Record(ctx, userID, "create_growth_comment", "growth_comments", comment.Body, "success")
+	}
+
+	saved, err := s.posts.FindComment(comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := growthCommentResponse(saved)
+	return &resp, nil
+}
+
+// DeleteComment 删除评论：作者可删除自己的评论，管理员可删除任意评论，其下的回复随之隐藏。
+func (s *GrowthService) DeleteComment(ctx context.Context, userID, postID, commentID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("用户不存在")
+		}
+		return err
+	}
+	comment, err := s.findComment(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.PostID != postID {
+		return errors.New("评论不存在")
+	}
+	if user.Role != model.RoleAdmin && comment.UserID != user.ID {
+		return errors.New("无权删除该评论")
+	}
+
+	if err := s.posts.DeleteComment(comment); err != nil {
+		return err
+	}
+	if err := s.refreshCommentCount(comment.PostID); err != nil {
+		return err
+	}
+	if s.audit != nil {
+		action := "delete_own_growth_comment"
+		if comment.UserID != user.ID {
+			action = "delete_growth_comment"
+		}
+		_ = s.audit.Record(ctx, userID, action, "growth_comments", comment.Body, "success")
+	}
+	return nil
+}
+
+// AdminListComments 分页返回管理员视角的成长圈评论，默认最新的在前。
+func (s *GrowthService) AdminListComments(adminID uint, query dto.AdminGrowthCommentQuery) (*dto.GrowthCommentListResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	page, err := pageRequest(query.PageQuery, repository.GrowthCommentSortFields, "created_at", true)
+	if err != nil {
+		return nil, err
+	}
+	comments, total, err := s.posts.SearchComments(query.PostID, query.Keyword, query.Status, page)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.GrowthCommentResponse, 0, len(comments))
+	for i := range comments {
+		items = append(items, growthCommentResponse(&comments[i]))
+	}
+	return &dto.GrowthCommentListResponse{Items: items, Pagination: pagination(page, total)}, nil
+}
+
+// AdminModerateComment 审核通过或拒绝评论。通过时通知动态作者，回复还会通知被回复的评论作者。
+func (s *GrowthService) AdminModerateComment(ctx context.Context, adminID, commentID uint, approve bool) (*dto.GrowthCommentResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	comment, err := s.findComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	status, action := GrowthStatusRejected, "reject_growth_comment"
+	if approve {
+		status, action = GrowthStatusApproved, "approve_growth_comment"
+	}
+	if comment.Status != status {
+		comment.Status = status
+		comment.ApprovedAt = nil
+		if approve {
+			now := time.Now()
+			comment.ApprovedAt = &now
+		}
+		if err := s.posts.UpdateComment(comment); err != nil {
+			return nil, err
+		}
+		if err := s.refreshCommentCount(comment.PostID); err != nil {
+			return nil, err
+		}
+		if s.audit != nil {
+			_ = s.audit.Record(ctx, adminID, action, "growth_comments", comment.Body, "success")
+		}
+		if approve {
+			s.notifyComment(comment)
+		}
+	}
+	resp := growthCommentResponse(comment)
+	return &resp, nil
+}
+
+// notifyComment 通知动态作者有新评论；回复同时通知被回复的评论作者（与动态作者相同时只通知一次）。
+func (s *GrowthService) notifyComment(comment *model.GrowthComment) {
+	post, err := s.posts.FindByID(comment.PostID)
+	if err != nil {
+		return
+	}
+	snippet := []rune(comment.Body)
+	if len(snippet) > growthNotifySnippet {
+		snippet = append(snippet[:growthNotifySnippet], '…')
+	}
+	name := comment.User.Name
+
+	if comment.ParentID != nil {
+		if parent, err := s.posts.FindComment(*comment.ParentID); err == nil && parent.UserID != post.CreatorID {
+			_ = s.notifications.Notify(parent.UserID, comment.UserID, NotificationGrowthReply, "growth_posts", post.ID,
+				name+" 回复了你的评论："+string(snippet))
+		}
+	}
+	_ = s.notifications.Notify(post.CreatorID, comment.UserID, NotificationGrowthComment, "growth_posts", post.ID,
+		name+" 评论了你的动态："+string(snippet))
+}
+
+// refreshCommentCount 按公开评论树重新统计动态的评论数，被删除或拒绝的评论下的回复不计入。
+func (s *GrowthService) refreshCommentCount(postID uint) error {
+	comments, err := s.posts.ListApprovedComments(postID)
+	if err != nil {
+		return err
+	}
+	var count func(items []dto.GrowthCommentResponse) int64
+	count = func(items []dto.GrowthCommentResponse) int64 {
+		n := int64(len(items))
+		for _, item := range items {
+			n += count(item.Replies)
+		}
+		return n
+	}
+	return s.posts.SetCommentCount(postID, count(growthCommentThreads(comments)))
+}
+
+// applyLiked 标记当前用户已点赞的动态。
+func (s *GrowthService) applyLiked(userID uint, items []dto.GrowthPostResponse) error {
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	liked, err := s.posts.LikedPostIDs(userID, ids)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].Liked = liked[items[i].ID]
+	}
+	return nil
+}
+
+func (s *GrowthService) likeResponse(postID uint, liked bool) (*dto.GrowthLikeResponse, error) {
+	post, err := s.posts.FindByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.GrowthLikeResponse{PostID: post.ID, Liked: liked, LikeCount: post.LikeCount}, nil
+}
+
+// approvedPost 查询用户可见的已审核通过的动态，其他动态不能互动。
+func (s *GrowthService) approvedPost(userID, postID uint) (*model.GrowthPost, error) {
+	post, err := s.posts.FindByID(postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("动态不存在")
+		}
+		return nil, err
+	}
+	if post.Status != GrowthStatusApproved {
+		return nil, errors.New("动态不存在")
+	}
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	visible, err := s.canView(user, post)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, errors.New("动态不存在")
+	}
+	return post, nil
+}
+
+func (s *GrowthService) findComment(commentID uint) (*model.GrowthComment, error) {
+	comment, err := s.posts.FindComment(commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("评论不存在")
+		}
+		return nil, err
+	}
+	return comment, nil
+}
+
+func growthCommentResponse(comment *model.GrowthComment) dto.GrowthCommentResponse {
+	return dto.GrowthCommentResponse{
+		ID:         comment.ID,
+		PostID:     comment.PostID,
+		ParentID:   comment.ParentID,
+		Body:       comment.Body,
+		Status:     comment.Status,
+		UserID:     comment.UserID,
+		UserName:   comment.User.Name,
+		UserRole:   string(comment.User.Role),
+		CreatedAt:  comment.CreatedAt,
+		ApprovedAt: comment.ApprovedAt,
+	}
+}
+
+// growthCommentThreads 将评论组织为楼中楼，父评论不可见的回复不展示。
+func growthCommentThreads(comments []model.GrowthComment) []dto.GrowthCommentResponse {
+	return threadComments(comments,
+		func(c *model.GrowthComment) (uint, *uint) { return c.ID, c.ParentID },
+		growthCommentResponse,
+		func(parent *dto.GrowthCommentResponse, child dto.GrowthCommentResponse) {
+			parent.Replies = append(parent.Replies, child)
+		})
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/moderation"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+const (
+	// GrowthRejectAutoModeration is the reason code of posts rejected by automated moderation.
+	GrowthRejectAutoModeration = "auto_moderation"
+	growthRejectOther          = "other"
+)
+
+// growthRejectReasons are the preset rejection reasons, in the order offered to reviewers.
+var growthRejectReasons = []dto.GrowthRejectReasonResponse{
+	{Code: "ad", Label: "广告或引流"},
+	{Code: "sensitive", Label: "含敏感或不当内容"},
+	{Code: "privacy", Label: "泄露客户或内部信息"},
+	{Code: "irrelevant", Label: "与工作无关"},
+	{Code: "low_quality", Label: "内容质量不足"},
+	{Code: "duplicate", Label: "重复发布"},
+	{Code: growthRejectOther, Label: "其他"},
+}
+
+func rejectReasonLabel(code string) string {
+	if code == GrowthRejectAutoModeration {
+		return "自动审核拒绝"
+	}
+	for _, r := range growthRejectReasons {
+		if r.Code == code {
+			return r.Label
+		}
+	}
+	return code
+}
+
+// RejectReasons 返回预设的拒绝原因，供审核界面选择。
+func (s *GrowthService) RejectReasons(adminID uint) ([]dto.GrowthRejectReasonResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	return growthRejectReasons, nil
+}
+
+// rejectionReason validates a rejection request. Without a code the reason counts as "other",
+// which needs an explanation.
+func rejectionReason(req dto.GrowthRejectRequest) (string, string, error) {
+	code := req.ReasonCode
+	reason := strings.TrimSpace(req.Reason)
+	if code == "" && reason == "" {
+		return "", "", errors.New("请选择或填写拒绝原因")
+	}
+	if code == "" {
+		code = growthRejectOther
+	}
+	if code == growthRejectOther && reason == "" {
+		return "", "", errors.New("选择其他原因时请填写原因说明")
+	}
+	return code, reason, nil
+}
+
+func rejectionSummary(code, reason string) string {
+	if reason == "" {
+		return rejectReasonLabel(code)
+	}
+	if code == growthRejectOther {
+		return reason
+	}
+	return rejectReasonLabel(code) + "，" + reason
+}
+
+func clearRejection(post *model.GrowthPost) {
+	post.RejectReasonCode = ""
+	post.RejectReason = ""
+	post.RejectedAt = nil
+}
+
+// submit puts a new or edited post into the queue and, when automated moderation is enabled,
+// applies its decision. It returns the history entries to store with the post.
+func (s *GrowthService) submit(ctx context.Context, post *model.GrowthPost, action string, imagePaths []string) []model.GrowthModerationEvent {
+	from := post.Status
+	post.Status = GrowthStatusPending
+	post.ApprovedAt = nil
+	post.ModerationVerdict = ""
+	post.ModerationMatches = ""
+	post.ModerationLabels = ""
+	post.ModerationNote = ""
+	clearRejection(post)
+
+	events := []model.GrowthModerationEvent{{
+		Action:     action,
+		FromStatus: from,
+		ToStatus:   GrowthStatusPending,
+		ActorID:    post.CreatorID,
+		Content:    post.Content,
+		ImagePaths: post.ImagePaths,
+	}}
+	if !s.moderation.Enabled() {
+		return events
+	}
+
+	outcome := s.moderation.Moderate(ctx, post.Content, imagePaths)
+	post.ModerationVerdict = outcome.Verdict
+	post.ModerationMatches = encodeMatches(outcome.Matches)
+	post.ModerationLabels = truncateUTF8(strings.Join(outcome.Labels, ","), 255)
+	post.ModerationNote = outcome.Note
+
+	event := model.GrowthModerationEvent{FromStatus: GrowthStatusPending, Reason: outcome.Note}
+	switch outcome.Verdict {
+	case moderation.VerdictPass:
+		now := time.Now()
+		post.Status = GrowthStatusApproved
+		post.ApprovedAt = &now
+		event.Action = model.GrowthActionAutoApprove
+	case moderation.VerdictBlock:
+		now := time.Now()
+		post.Status = GrowthStatusRejected
+		post.RejectReasonCode = GrowthRejectAutoModeration
+		post.RejectReason = outcome.Note
+		post.RejectedAt = &now
+		event.Action = model.GrowthActionAutoReject
+		event.ReasonCode = GrowthRejectAutoModeration
+	default:
+		event.Action = model.GrowthActionAutoReview
+	}
+	event.ToStatus = post.Status
+	return append(events, event)
+}
+
+// afterSubmit records a stored submission and publishes posts approved automatically.
+func (s *GrowthService) afterSubmit(ctx context.Context, userID uint, post *model.GrowthPost, action string) {
+	if s.audit != nil {
+		_ = s.audit.Record(ctx, userID, action, "growth_posts", post.Content, "success")
+		switch post.Status {
+		case GrowthStatusApproved:
+			_ = s.audit.Record(ctx, userID, "auto_approve_growth_post", "growth_posts", post.Content, "success")
+		case GrowthStatusRejected:
+			_ = s.audit.Record(ctx, userID, "auto_reject_growth_post", "growth_posts", post.ModerationNote, "success")
+		}
+	}
+	if post.Status == GrowthStatusApproved {
+		_ = s.search.IndexGrowthPost(ctx, post)
+	}
+}
+
+// Resubmit 发布者修改自己被拒绝的动态并重新提交，动态回到待审核状态并重新经过自动审核。
+// 未指定可见范围时沿用原来的范围。
+func (s *GrowthService) Resubmit(ctx context.Context, userID, postID uint, req dto.ResubmitGrowthPostRequest) (*dto.GrowthPostResponse, error) {
+	user, err := s.ensurePoster(userID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := s.posts.FindByID(postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("动态不存在")
+		}
+		return nil, err
+	}
+	if post.CreatorID != userID {
+		return nil, errors.New("只能修改自己发布的动态")
+	}
+	if post.Status != GrowthStatusRejected {
+		return nil, errors.New("只有被拒绝的动态可以修改后重新提交")
+	}
+
+	imgJSON, err := json.Marshal(req.ImagePaths)
+	if err != nil {
+		return nil, err
+	}
+	if req.Scope != "" {
+		if err := s.applyScope(user, post, req.Scope, req.VisibleRoles); err != nil {
+			return nil, err
+		}
+	}
+	post.Content = req.Content
+	post.ImagePaths = string(imgJSON)
+	post.Topics = strings.Join(utils.ExtractHashtags(req.Content), ",")
+	post.ResubmitCount++
+	events := s.submit(ctx, post, model.GrowthActionResubmit, req.ImagePaths)
+
+	if err := s.posts.UpdateWithEvents(post, events...); err != nil {
+		return nil, err
+	}
+	s.afterSubmit(ctx, userID, post, "resubmit_growth_post")
+	return s.toResponse(post), nil
+}
+
+// History 返回动态的审核历史，发布者本人和管理员可查看。
+func (s *GrowthService) History(userID, postID uint) (*dto.GrowthModerationHistoryResponse, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("用户不存在")
+		}
+		return nil, err
+	}
+	post, err := s.posts.FindByID(postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("动态不存在")
+		}
+		return nil, err
+	}
+	if user.Role != model.RoleAdmin && post.CreatorID != user.ID {
+		return nil, errors.New("无权查看该动态的审核记录")
+	}
+
+	events, err := s.posts.ListEvents(post.ID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(events))
+	for _, e := range events {
+		if e.ActorID != 0 {
+			ids = append(ids, e.ActorID)
+		}
+	}
+	names := make(map[uint]string, len(ids))
+	if len(ids) > 0 {
+		users, err := s.users.FindByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			names[u.ID] = u.Name
+		}
+	}
+
+	items := make([]dto.GrowthModerationEventResponse, 0, len(events))
+	for _, e := range events {
+		item := dto.GrowthModerationEventResponse{
+			ID:         e.ID,
+			Action:     e.Action,
+			FromStatus: e.FromStatus,
+			ToStatus:   e.ToStatus,
+			ActorID:    e.ActorID,
+			ActorName:  names[e.ActorID],
+			ReasonCode: e.ReasonCode,
+			Reason:     e.Reason,
+			Content:    e.Content,
+			CreatedAt:  e.CreatedAt,
+		}
+		if e.ActorID == 0 {
+			item.ActorName = "自动审核"
+		}
+		if e.ReasonCode != "" {
+			item.ReasonLabel = rejectReasonLabel(e.ReasonCode)
+		}
+		if e.ImagePaths != "" {
+			_ = json.Unmarshal([]byte(e.ImagePaths), &item.ImagePaths)
+		}
+		items = append(items, item)
+	}
+	return &dto.GrowthModerationHistoryResponse{PostID: post.ID, Status: post.Status, Events: items}, nil
+}
+
+// AdminRejectionReport 统计一段时间内人工和自动拒绝的原因分布，并按日、周或月给出趋势。
+func (s *GrowthService) AdminRejectionReport(adminID uint, query dto.GrowthRejectionReportQuery) (*dto.GrowthRejectionReportResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	window, err := newReportWindow(query.From, query.To, query.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.posts.ListRejections(window.From, window.End)
+	if err != nil {
+		return nil, err
+	}
+
+	periods := make([]dto.GrowthRejectionPeriod, len(window.Periods))
+	for i, label := range window.Labels() {
+		periods[i].Period = label
+	}
+	totals := make(map[string]int64)
+	byPeriod := make([]map[string]int64, len(periods))
+	for _, e := range events {
+		i, ok := window.Period(e.CreatedAt)
+		if !ok {
+			continue
+		}
+		code := e.ReasonCode
+		if code == "" {
+			code = growthRejectOther
+		}
+		if byPeriod[i] == nil {
+			byPeriod[i] = make(map[string]int64)
+		}
+		byPeriod[i][code]++
+		periods[i].Total++
+		totals[code]++
+	}
+	for i := range periods {
+		periods[i].Reasons = reasonCounts(byPeriod[i])
+	}
+
+	return &dto.GrowthRejectionReportResponse{
+		From:     window.From.Format(reportDateLayout),
+		To:       window.To.Format(reportDateLayout),
+		Interval: window.Interval,
+		Total:    int64(len(events)),
+		Reasons:  reasonCounts(totals),
+		Periods:  periods,
+	}, nil
+}
+
+// reasonCounts orders reason counts by count, most frequent first, then by code.
+func reasonCounts(counts map[string]int64) []dto.GrowthRejectionReasonCount {
+	items := make([]dto.GrowthRejectionReasonCount, 0, len(counts))
+	for code, count := range counts {
+		items = append(items, dto.GrowthRejectionReasonCount{Code: code, Label: rejectReasonLabel(code), Count: count})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Code < items[j].Code
+	})
+	return items
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+)
+
+const (
+	// maxPinnedGrowthPosts 同时置顶的动态数量上限。
+	maxPinnedGrowthPosts = 5
+	// defaultTrendingTopics 热门话题默认返回数量。
+	defaultTrendingTopics = 10
+	// growthScopeRegion 按区域发布。用户没有区域信息，"我的区域/团队"由 team（本店团队）承担，
+	// 收到 region 时明确拒绝，而不是按全公司发布。
+	growthScopeRegion = "region"
+)
+
+// applyScope 设置动态的可见范围。发布到本店团队要求发布者属于某个店长的团队，
+// 指定角色时至少选择一个角色；不支持按区域发布。
+func (s *GrowthService) applyScope(user *model.User, post *model.GrowthPost, scope string, roles []string) error {
+	if scope == "" {
+		scope = model.GrowthScopeCompany
+	}
+	if scope == growthScopeRegion {
+		return errors.New("暂不支持按区域发布，请使用 team（本店团队）")
+	}
+	post.Scope = scope
+	post.VisibleRoles = ""
+	switch scope {
+	case model.GrowthScopeTeam:
+		if user.Role == model.RoleEmployee {
+			managers, err := s.relations.ListManagerIDsByEmployee(user.ID)
+			if err != nil {
+				return err
+			}
+			if len(managers) == 0 {
+				return errors.New("未绑定店长，无法发布到本店团队")
+			}
+		}
+	case model.GrowthScopeRoles:
+		seen := make(map[string]bool, len(roles))
+		var distinct []string
+		for _, r := range roles {
+			if !seen[r] {
+				seen[r] = true
+				distinct = append(distinct, r)
+			}
+		}
+		if len(distinct) == 0 {
+			return errors.New("请选择可见的角色")
+		}
+		post.VisibleRoles = "," + strings.Join(distinct, ",") + ","
+	}
+	return nil
+}
+
+// teamMemberIDs 返回用户所在团队的成员：店长本人及其名下员工；员工所属的每个店长及其名下员工。
+func (s *GrowthService) teamMemberIDs(user *model.User) ([]uint, error) {
+	var managers []uint
+	switch user.Role {
+	case model.RoleManager:
+		managers = []uint{user.ID}
+	case model.RoleEmployee:
+		ids, err := s.relations.ListManagerIDsByEmployee(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		managers = ids
+	}
+	if len(managers) == 0 {
+		return nil, nil
+	}
+	employees, err := s.relations.ListEmployeeIDsByManagers(managers)
+	if err != nil {
+		return nil, err
+	}
+	return append(managers, employees...), nil
+}
+
+func (s *GrowthService) viewer(user *model.User) (repository.GrowthViewer, error) {
+	if user.Role == model.RoleAdmin {
+		return repository.GrowthViewer{UserID: user.ID, Role: string(user.Role), Admin: true}, nil
+	}
+	team, err := s.teamMemberIDs(user)
+	if err != nil {
+		return repository.GrowthViewer{}, err
+	}
+	return repository.GrowthViewer{UserID: user.ID, Role: string(user.Role), TeamIDs: team}, nil
+}
+
+// canView 判断用户能否看到某条动态，规则与 repository.GrowthViewer 的查询条件一致。
+func (s *GrowthService) canView(user *model.User, post *model.GrowthPost) (bool, error) {
+	if user.Role == model.RoleAdmin || post.CreatorID == user.ID {
+		return true, nil
+	}
+	switch post.Scope {
+	case model.GrowthScopeRoles:
+		return strings.Contains(post.VisibleRoles, ","+string(user.Role)+","), nil
+	case model.GrowthScopeTeam:
+		team, err := s.teamMemberIDs(user)
+		if err != nil {
+			return false, err
+		}
+		for _, id := range team {
+			if id == post.CreatorID {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+// TrendingTopics 返回最近一段时间内当前用户可见动态中最热门的话题。
+func (s *GrowthService) TrendingTopics(userID uint, query dto.GrowthTrendingQuery) ([]dto.GrowthTopicResponse, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	viewer, err := s.viewer(user)
+	if err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultTrendingTopics
+	}
+	since := time.Now().AddDate(0, 0, -s.opts.TrendingDays)
+	topics, err := s.posts.TrendingTopics(viewer, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.GrowthTopicResponse, 0, len(topics))
+	for _, t := range topics {
+		items = append(items, dto.GrowthTopicResponse{Name: t.Name, PostCount: t.PostCount})
+	}
+	return items, nil
+}
+
+// SetPinned 置顶或取消置顶已通过审核的动态，同时置顶的动态不超过 5 条。
+func (s *GrowthService) SetPinned(ctx context.Context, adminID, postID uint, pinned bool) (*dto.GrowthPostResponse, error) {
+	post, err := s.curatedPost(adminID, postID, pinned)
+	if err != nil {
+		return nil, err
+	}
+	if pinned == (post.PinnedAt != nil) {
+		return s.toResponse(post), nil
+	}
+	action := "unpin_growth_post"
+	if pinned {
+		count, err := s.posts.CountPinned()
+		if err != nil {
+			return nil, err
+		}
+		if count >= maxPinnedGrowthPosts {
+			return nil, errors.New("最多同时置顶 5 条动态，请先取消其他置顶")
+		}
+		now := time.Now()
+		post.PinnedAt = &now
+		action = "pin_growth_post"
+	} else {
+		post.PinnedAt = nil
+	}
+	if err := s.posts.Update(post); err != nil {
+		return nil, err
+	}
+	if s.audit != nil {
+		_ = s.audit.Record(ctx, adminID, action, "growth_posts", post.Content, "success")
+	}
+	return s.toResponse(post), nil
+}
+
+// SetFeatured 将已通过审核的动态设为精选或取消精选，设为精选时通知发布者。
+func (s *GrowthService) SetFeatured(ctx context.Context, adminID, postID uint, featured bool) (*dto.GrowthPostResponse, error) {
+	post, err := s.curatedPost(adminID, postID, featured)
+	if err != nil {
+		return nil, err
+	}
+	if featured == (post.FeaturedAt != nil) {
+		return s.toResponse(post), nil
+	}
+	action := "unfeature_growth_post"
+	if featured {
+		now := time.Now()
+		post.FeaturedAt = &now
+		action = "feature_growth_post"
+	} else {
+		post.FeaturedAt = nil
+	}
+	if err := s.posts.Update(post); err != nil {
+		return nil, err
+	}
+	if s.audit != nil {
+		_ = s.audit.Record(ctx, adminID, action, "growth_posts", post.Content, "success")
+	}
+	if featured {
+		_ = s.notifications.Notify(post.CreatorID, adminID, NotificationGrowthFeatured, "growth_posts", post.ID,
+			"你的动态被设为精选")
+	}
+	return s.toResponse(post), nil
+}
+
+// curatedPost 查询要置顶或设为精选的动态，只有已通过审核的动态可以设置，取消不受限制。
+func (s *GrowthService) curatedPost(adminID, postID uint, set bool) (*model.GrowthPost, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	post, err := s.posts.FindByID(postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("动态不存在")
+		}
+		return nil, err
+	}
+	if set && post.Status != GrowthStatusApproved {
+		return nil, errors.New("只有已通过审核的动态可以置顶或设为精选")
+	}
+	return post, nil
+}
+
+// splitList 拆分逗号分隔的列表，忽略空项。
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,6 +12,7 @@ import (
 	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
 	"github.com/javapub/mini-study/mini-study-backend/internal/model"
 	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
 )
 
 const (
@@ -18,16 +21,30 @@ const (
 	GrowthStatusRejected = "rejected"
 )
 
+// GrowthOptions 成长圈发布与话题统计配置。
+type GrowthOptions struct {
+	EmployeePostEnabled bool // 是否允许员工发布动态
+	TrendingDays        int  // 热门话题统计最近多少天内通过审核的动态
+}
+
 // GrowthService 处理成长圈业务逻辑。
 type GrowthService struct {
-	posts *repository.GrowthPostRepository
-	users *repository.UserRepository
-	audit *AuditService
+	posts         *repository.GrowthPostRepository
+	users         *repository.UserRepository
+	relations     *repository.ManagerEmployeeRepository
+	audit         *AuditService
+	search        *SearchService
+	notifications *NotificationService
+	moderation    *ModerationService
+	opts          GrowthOptions
 }
 
 // NewGrowthService 创建成长圈服务。
-func NewGrowthService(posts *repository.GrowthPostRepository, users *repository.UserRepository, audit *AuditService) *GrowthService {
-	return &GrowthService{posts: posts, users: users, audit: audit}
+func NewGrowthService(posts *repository.GrowthPostRepository, users *repository.UserRepository, relations *repository.ManagerEmployeeRepository, audit *AuditService, search *SearchService, notifications *NotificationService, moderation *ModerationService, opts GrowthOptions) *GrowthService {
+	if opts.TrendingDays <= 0 {
+		opts.TrendingDays = 7
+	}
+	return &GrowthService{posts: posts, users: users, relations: relations, audit: audit, search: search, notifications: notifications, moderation: moderation, opts: opts}
 }
 
 func (s *GrowthService) ensureAdmin(userID uint) error {
@@ -44,20 +61,27 @@ func (s *GrowthService) ensureAdmin(userID uint) error {
 	return nil
 }
 
-func (s *GrowthService) ensureManager(userID uint) (*model.User, error) {
+// ensurePoster 校验用户能否发布成长圈：店长始终可以，员工需配置开启。
+func (s *GrowthService) ensurePoster(userID uint) (*model.User, error) {
 	user, err := s.users.FindByID(userID)
 	if err != nil {
 		return nil, err
 	}
-	if user.Role != model.RoleManager {
+	switch {
+	case user.Role == model.RoleManager:
+	case user.Role == model.RoleEmployee && s.opts.EmployeePostEnabled:
+	case user.Role == model.RoleEmployee:
+		return nil, errors.New("暂未开放员工发布成长圈")
+	default:
 		return nil, errors.New("仅店长可发布成长圈")
 	}
 	return user, nil
 }
 
-// CreatePost 店长创建成长圈动态。
-func (s *GrowthService) CreatePost(creatorID uint, req dto.CreateGrowthPostRequest) (*dto.GrowthPostResponse, error) {
-	user, err := s.ensureManager(creatorID)
+// CreatePost 店长（以及开启后的员工）创建成长圈动态，可限定可见范围，正文中的 #话题 自动关联。
+// 启用自动审核时，无异常的动态直接通过，明显违规的直接拒绝，其余留在待审核队列等待人工处理。
+func (s *GrowthService) CreatePost(ctx context.Context, creatorID uint, req dto.CreateGrowthPostRequest) (*dto.GrowthPostResponse, error) {
+	user, err := s.ensurePoster(creatorID)
 	if err != nil {
 		return nil, err
 	}
@@ -75,50 +99,127 @@ func (s *GrowthService) CreatePost(creatorID uint, req dto.CreateGrowthPostReque
 		CreatorID:  user.ID,
 		Content:    req.Content,
 		ImagePaths: string(imgJSON),
-		Status:     GrowthStatusPending,
+		Topics:     strings.Join(utils.ExtractHashtags(req.Content), ","),
 	}
-
-	if err := s.posts.Create(post); err != nil {
+	if err := s.applyScope(user, post, req.Scope, req.VisibleRoles); err != nil {
 		return nil, err
 	}
-	if s.audit != nil {
-		_ = s.audit.Record(creatorID, "create_growth_post", "growth_posts", post.Content, "success")
+	events := s.submit(ctx, post, model.GrowthActionSubmit, req.ImagePaths)
+
+	if err := s.posts.CreateWithEvents(post, events...); err != nil {
+		return nil, err
 	}
+	s.afterSubmit(ctx, creatorID, post, "create_growth_post")
 	return s.toResponse(post), nil
 }
 
-// ListPublic 返回所有已审核通过的成长圈动态。
-func (s *GrowthService) ListPublic(keyword string) ([]dto.GrowthPostResponse, error) {
-	posts, err := s.posts.ListPublic(keyword)
+// ListPublic 按游标分页返回当前用户可见的已审核通过的成长圈动态，最新的在前，可按话题或精选筛选，
+// 并标记当前用户是否已点赞。不带关键词和精选筛选时，第一页另外返回置顶动态。
+func (s *GrowthService) ListPublic(userID uint, query dto.GrowthListQuery) (*dto.GrowthPostListResponse, error) {
+	cursor, err := cursorRequest(query.CursorQuery)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	viewer, err := s.viewer(user)
+	if err != nil {
+		return nil, err
+	}
+	filter := repository.GrowthFeedFilter{
+		Keyword:       query.Keyword,
+		Topic:         utils.NormalizeHashtag(query.Topic),
+		Featured:      query.Featured,
+		ExcludePinned: query.Keyword == "" && !query.Featured,
+	}
+	posts, total, more, err := s.posts.FeedVisible(viewer, filter, cursor)
 	if err != nil {
 		return nil, err
 	}
-	return s.toResponses(posts), nil
+	resp := s.feedResponse(posts, cursor, total, more)
+	if filter.ExcludePinned && query.Cursor == "" {
+		pinned, err := s.posts.ListPinned(viewer, filter.Topic)
+		if err != nil {
+			return nil, err
+		}
+		resp.Pinned = s.toResponses(pinned)
+		if err := s.applyLiked(userID, resp.Pinned); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.applyLiked(userID, resp.Items); err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
-// ListMine 返回当前用户自己的成长圈动态列表。
-func (s *GrowthService) ListMine(userID uint, query dto.GrowthMyListQuery) ([]dto.GrowthPostResponse, error) {
-	posts, err := s.posts.ListByCreator(userID, query.Keyword, query.Status)
+// ListMine 按游标分页返回当前用户自己的成长圈动态。
+func (s *GrowthService) ListMine(userID uint, query dto.GrowthMyListQuery) (*dto.GrowthPostListResponse, error) {
+	cursor, err := cursorRequest(query.CursorQuery)
 	if err != nil {
 		return nil, err
 	}
-	return s.toResponses(posts), nil
+	posts, total, more, err := s.posts.FeedByCreator(userID, query.Keyword, query.Status, cursor)
+	if err != nil {
+		return nil, err
+	}
+	resp := s.feedResponse(posts, cursor, total, more)
+	if err := s.applyLiked(userID, resp.Items); err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
-// AdminList 返回管理员视角的成长圈动态列表。
-func (s *GrowthService) AdminList(adminID uint, query dto.AdminGrowthListQuery) ([]dto.GrowthPostResponse, error) {
+// AdminList 分页返回管理员视角的成长圈动态列表。
+func (s *GrowthService) AdminList(adminID uint, query dto.AdminGrowthListQuery) (*dto.GrowthPostListResponse, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
-	posts, err := s.posts.AdminList(query.Keyword, query.Status)
+	page, err := pageRequest(query.PageQuery, repository.GrowthPostSortFields, "created_at", true)
 	if err != nil {
 		return nil, err
 	}
-	return s.toResponses(posts), nil
+	posts, total, err := s.posts.SearchAdmin(query.Keyword, query.Status, query.Moderation, page)
+	if err != nil {
+		return nil, err
+	}
+	items := s.toResponses(posts)
+	for i := range posts {
+		items[i].Moderation = moderationResponse(&posts[i])
+	}
+	return &dto.GrowthPostListResponse{Items: items, Pagination: pagination(page, total)}, nil
+}
+
+// moderationResponse rebuilds the automated decision stored on a post, or returns nil for posts
+// that were not moderated automatically.
+func moderationResponse(post *model.GrowthPost) *dto.ModerationResultResponse {
+	if post.ModerationVerdict == "" {
+		return nil
+	}
+	outcome := &ModerationOutcome{
+		Verdict: post.ModerationVerdict,
+		Matches: decodeMatches(post.ModerationMatches, post.Content),
+		Note:    post.ModerationNote,
+	}
+	if post.ModerationLabels != "" {
+		outcome.Labels = strings.Split(post.ModerationLabels, ",")
+	}
+	return outcome.response(post.Content)
+}
+
+func (s *GrowthService) feedResponse(posts []model.GrowthPost, cursor repository.CursorRequest, total int64, more bool) *dto.GrowthPostListResponse {
+	var last model.GrowthPost
+	if len(posts) > 0 {
+		last = posts[len(posts)-1]
+	}
+	page := feedPagination(cursor, total, more, last.CreatedAt, last.ID)
+	return &dto.GrowthPostListResponse{Items: s.toResponses(posts), Pagination: page}
 }
 
 // Approve 审核通过某条成长圈动态。
-func (s *GrowthService) Approve(adminID, postID uint) (*dto.GrowthPostResponse, error) {
+func (s *GrowthService) Approve(ctx context.Context, adminID, postID uint) (*dto.GrowthPostResponse, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
@@ -127,43 +228,71 @@ func (s *GrowthService) Approve(adminID, postID uint) (*dto.GrowthPostResponse,
 		return nil, err
 	}
 	if post.Status != GrowthStatusApproved {
+		event := model.GrowthModerationEvent{Action: model.GrowthActionApprove, FromStatus: post.Status, ToStatus: GrowthStatusApproved, ActorID: adminID}
 		post.Status = GrowthStatusApproved
 		now := time.Now()
 		post.ApprovedAt = &now
-		if err := s.posts.Update(post); err != nil {
+		clearRejection(post)
+		if err := s.posts.UpdateWithEvents(post, event); err != nil {
 			return nil, err
 		}
 		if s.audit != nil {
-			_ = s.audit.Record(adminID, "approve_growth_post", "growth_posts", post.Content, "success")
+			_ = s.audit.Record(ctx, adminID, "approve_growth_post", "growth_posts", post.Content, "success")
 		}
+		_ = s.search.IndexGrowthPost(ctx, post)
+		_ = s.notifications.Notify(post.CreatorID, adminID, NotificationGrowthApproved, "growth_posts", post.ID,
+			"你的动态已通过审核")
 	}
 	return s.toResponse(post), nil
 }
 
-// Reject 将某条成长圈动态标记为拒绝。
-func (s *GrowthService) Reject(adminID, postID uint) (*dto.GrowthPostResponse, error) {
+// Reject 拒绝某条成长圈动态，必须给出预设原因代码或原因说明，结果通知发布者。
+func (s *GrowthService) Reject(ctx context.Context, adminID, postID uint, req dto.GrowthRejectRequest) (*dto.GrowthPostResponse, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
+	code, reason, err := rejectionReason(req)
+	if err != nil {
+		return nil, err
+	}
 	post, err := s.posts.FindByID(postID)
 	if err != nil {
 		return nil, err
 	}
-	if post.Status != GrowthStatusRejected {
-		post.Status = GrowthStatusRejected
-		post.ApprovedAt = nil
-		if err := s.posts.Update(post); err != nil {
-			return nil, err
-		}
-		if s.audit != nil {
-			_ = s.audit.Record(adminID, "reject_growth_post", "growth_posts", post.Content, "success")
-		}
+	if post.Status == GrowthStatusRejected {
+		return nil, errors.New("动态已被拒绝")
+	}
+
+	event := model.GrowthModerationEvent{
+		Action:     model.GrowthActionReject,
+		FromStatus: post.Status,
+		ToStatus:   GrowthStatusRejected,
+		ActorID:    adminID,
+		ReasonCode: code,
+		Reason:     reason,
+	}
+	now := time.Now()
+	post.Status = GrowthStatusRejected
+	post.ApprovedAt = nil
+	post.PinnedAt = nil
+	post.FeaturedAt = nil
+	post.RejectReasonCode = code
+	post.RejectReason = reason
+	post.RejectedAt = &now
+	if err := s.posts.UpdateWithEvents(post, event); err != nil {
+		return nil, err
 	}
+	if s.audit != nil {
+		_ = s.audit.Record(ctx, adminID, "reject_growth_post", "growth_posts", post.Content, "success")
+	}
+	_ = s.search.RemoveGrowthPost(ctx, post.ID)
+	_ = s.notifications.Notify(post.CreatorID, adminID, NotificationGrowthRejected, "growth_posts", post.ID,
+		"你的动态未通过审核："+rejectionSummary(code, reason))
 	return s.toResponse(post), nil
 }
 
-// Delete 删除成长圈动态（店长删除未通过的自己的，管理员可删除所有）。
-func (s *GrowthService) Delete(userID, postID uint) error {
+// Delete 删除成长圈动态（发布者删除自己未通过的，管理员可删除所有）。
+func (s *GrowthService) Delete(ctx context.Context, userID, postID uint) error {
 	user, err := s.users.FindByID(userID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -182,13 +311,14 @@ func (s *GrowthService) Delete(userID, postID uint) error {
 			return err
 		}
 		if s.audit != nil {
-			_ = s.audit.Record(userID, "delete_growth_post", "growth_posts", post.Content, "success")
+			_ = s.audit.Record(ctx, userID, "delete_growth_post", "growth_posts", post.Content, "success")
 		}
+		_ = s.search.RemoveGrowthPost(ctx, post.ID)
 		return nil
 	}
 
-	// 店长只能删除自己发布且未通过审核的动态
-	if user.Role == model.RoleManager && post.CreatorID == user.ID {
+	// 发布者只能删除自己发布且未通过审核的动态
+	if post.CreatorID == user.ID {
 		if post.Status == GrowthStatusApproved {
 			return errors.New("已通过审核的动态仅管理员可删除")
 		}
@@ -196,7 +326,7 @@ func (s *GrowthService) Delete(userID, postID uint) error {
 			return err
 		}
 		if s.audit != nil {
-			_ = s.audit.Record(userID, "delete_own_growth_post", "growth_posts", post.Content, "success")
+			_ = s.audit.Record(ctx, userID, "delete_own_growth_post", "growth_posts", post.Content, "success")
 		}
 		return nil
 	}
@@ -235,5 +365,20 @@ func (s *GrowthService) toResponse(post *model.GrowthPost) *dto.GrowthPostRespon
 		PublisherRole: publisherRole,
 		CreatedAt:     post.CreatedAt,
 		ApprovedAt:    post.ApprovedAt,
+		LikeCount:     post.LikeCount,
+		CommentCount:  post.CommentCount,
+		ShareCount:    post.ShareCount,
+
+		RejectReasonCode:  post.RejectReasonCode,
+		RejectReasonLabel: rejectReasonLabel(post.RejectReasonCode),
+		RejectReason:      post.RejectReason,
+		RejectedAt:        post.RejectedAt,
+		ResubmitCount:     post.ResubmitCount,
+
+		Scope:        post.Scope,
+		VisibleRoles: splitList(post.VisibleRoles),
+		Topics:       splitList(post.Topics),
+		Pinned:       post.PinnedAt != nil,
+		Featured:     post.FeaturedAt != nil,
 	}
 }
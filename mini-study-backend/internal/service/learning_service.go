@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -11,31 +12,49 @@ import (
 	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
 )
 
+// LearningOptions configures how document reading counts towards completion.
+type LearningOptions struct {
+	DocCompletionPercent int   // share of pages that must be read to complete a document
+	MinPageSeconds       int64 // time on a page before it counts as read
+}
+
 // LearningService handles learning progress.
 type LearningService struct {
-	records  *repository.LearningRecordRepository
-	contents *repository.ContentRepository
-	users    *repository.UserRepository
-	points   *PointService
+	records     *repository.LearningRecordRepository
+	pageViews   *repository.LearningPageViewRepository
+	checkpoints *repository.ContentCheckpointRepository
+	answers     *repository.CheckpointAnswerRepository
+	contents    *repository.ContentRepository
+	users       *repository.UserRepository
+	points      *PointService
+	opts        LearningOptions
 }
 
 // NewLearningService builds learning service.
 func NewLearningService(
 	recordRepo *repository.LearningRecordRepository,
+	pageViewRepo *repository.LearningPageViewRepository,
+	checkpointRepo *repository.ContentCheckpointRepository,
+	answerRepo *repository.CheckpointAnswerRepository,
 	contentRepo *repository.ContentRepository,
 	userRepo *repository.UserRepository,
 	pointSvc *PointService,
+	opts LearningOptions,
 ) *LearningService {
 	return &LearningService{
-		records:  recordRepo,
-		contents: contentRepo,
-		users:    userRepo,
-		points:   pointSvc,
+		records:     recordRepo,
+		pageViews:   pageViewRepo,
+		checkpoints: checkpointRepo,
+		answers:     answerRepo,
+		contents:    contentRepo,
+		users:       userRepo,
+		points:      pointSvc,
+		opts:        opts,
 	}
 }
 
 // UpdateProgress updates the learning record for a user/content.
-func (s *LearningService) UpdateProgress(userID uint, req dto.LearningProgressRequest) (*dto.LearningProgressResponse, error) {
+func (s *LearningService) UpdateProgress(ctx context.Context, userID uint, req dto.LearningProgressRequest) (*dto.LearningProgressResponse, error) {
 	user, err := s.users.FindByID(userID)
 	if err != nil {
 		return nil, err
@@ -53,6 +72,11 @@ func (s *LearningService) UpdateProgress(userID uint, req dto.LearningProgressRe
 		return nil, errors.New("内容未发布")
 	}
 
+	gate, err := s.checkpointGate(user.ID, content.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	record, err := s.records.FirstOrCreate(user.ID, content.ID)
 	if err != nil {
 		return nil, err
@@ -60,17 +84,29 @@ func (s *LearningService) UpdateProgress(userID uint, req dto.LearningProgressRe
 	prevRecord := *record
 	wasCompleted := record.Status == "completed"
 
-	// 如果是文档或图文类型，打开即视为完成
-	if content.Type == "doc" || content.Type == "article" {
+	if content.Type == "doc" && content.PageCount > 0 {
+		// 已生成分页预览的文档：按已读页数占比判断完成
+		if err := s.updatePageProgress(user.ID, content, record, req, gate); err != nil {
+			return nil, err
+		}
+	} else if content.Type == "doc" || content.Type == "article" {
+		// 图文及尚无分页预览的文档，打开即视为完成；有随堂测验时需全部答对
 		if record.Status != "completed" {
-			record.Status = "completed"
-			record.Progress = 100
-			now := time.Now()
-			record.CompletedAt = &now
+			if gate.pending == nil {
+				record.Status = "completed"
+				record.Progress = 100
+				now := time.Now()
+				record.CompletedAt = &now
+			} else {
+				record.Progress = gate.passed * 100 / gate.total
+			}
 		}
 	} else {
-		// 视频类型：记录播放位置和进度
+		// 视频类型：记录播放位置和进度，未答对的检查点之后的播放不计入
 		newPos := req.VideoPosition
+		if gate.pending != nil && newPos > gate.pending.Position {
+			newPos = gate.pending.Position
+		}
 		if newPos < record.VideoPosition {
 			newPos = record.VideoPosition
 		}
@@ -101,6 +137,13 @@ func (s *LearningService) UpdateProgress(userID uint, req dto.LearningProgressRe
 			if duration > 0 && record.VideoPosition >= duration*95/100 {
 				isCompleted = true
 			}
+			// 有未答对的检查点时不能完成（检查点可能位于结尾附近）
+			if gate.pending != nil {
+				isCompleted = false
+				if record.Progress > 99 {
+					record.Progress = 99
+				}
+			}
 		}
 
 		if isCompleted {
@@ -118,16 +161,36 @@ func (s *LearningService) UpdateProgress(userID uint, req dto.LearningProgressRe
 	if err := s.records.Upsert(record); err != nil {
 		return nil, err
 	}
+	// 按天留存学习活动，学习数据看板据此统计活跃学员与观看时长
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if err := s.records.RecordActivity(user.ID, content.ID, today, record.VideoPosition-prevRecord.VideoPosition); err != nil {
+		return nil, err
+	}
 
 	if !wasCompleted && nowCompleted && s.points != nil {
-		if err := s.points.AwardContentCompletion(user.ID, content); err != nil {
+		if err := s.points.AwardContentCompletion(ctx, user.ID, content); err != nil {
 			rollback := prevRecord
 			// best-effort rollback to previous state
 			_ = s.records.Upsert(&rollback)
 			return nil, err
 		}
 	}
-	return s.buildProgressResponse(record, content), nil
+	resp := s.buildProgressResponse(record, content)
+	gate.apply(resp)
+	return resp, nil
+}
+
+// RefreshProgress recomputes the progress of a content the user has started, after a checkpoint
+// was passed. It returns nil when the user has not started the content.
+func (s *LearningService) RefreshProgress(ctx context.Context, userID, contentID uint) (*dto.LearningProgressResponse, error) {
+	if _, err := s.records.FindByUserAndContent(userID, contentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return s.UpdateProgress(ctx, userID, dto.LearningProgressRequest{ContentID: contentID})
 }
 
 // GetProgress returns progress for user/content.
@@ -144,32 +207,55 @@ func (s *LearningService) GetProgress(userID uint, contentID uint) (*dto.Learnin
 	if err := s.ensureContentAccessible(user, content); err != nil {
 		return nil, err
 	}
+	gate, err := s.checkpointGate(userID, contentID)
+	if err != nil {
+		return nil, err
+	}
 
 	record, err := s.records.FindByUserAndContent(userID, contentID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// 返回默认进度
-			return &dto.LearningProgressResponse{
+			resp := &dto.LearningProgressResponse{
 				ContentID:       contentID,
 				VideoPosition:   0,
 				DurationSeconds: content.DurationSeconds,
+				PageCount:       content.PageCount,
 				Progress:        0,
 				Status:          "not_started",
-			}, nil
+			}
+			gate.apply(resp)
+			return resp, nil
 		}
 		return nil, err
 	}
-	return s.buildProgressResponse(record, content), nil
+	resp := s.buildProgressResponse(record, content)
+	gate.apply(resp)
+	if content.Type == "doc" && content.PageCount > 0 {
+		views, err := s.pageViews.ListByUserAndContent(userID, contentID)
+		if err != nil {
+			return nil, err
+		}
+		resp.PageViews = make([]dto.LearningPageViewResponse, 0, len(views))
+		for _, view := range views {
+			resp.PageViews = append(resp.PageViews, dto.LearningPageViewResponse{
+				PageNo:          view.PageNo,
+				DurationSeconds: view.DurationSeconds,
+			})
+		}
+	}
+	return resp, nil
 }
 
-// ListProgress returns all learning progress for user.
-func (s *LearningService) ListProgress(userID uint) ([]dto.LearningProgressResponse, error) {
-	records, err := s.records.ListByUser(userID)
+// ListProgress returns a page of learning progress for user, by default most recently studied first.
+func (s *LearningService) ListProgress(userID uint, query dto.LearningProgressQuery) (*dto.LearningProgressListResponse, error) {
+	page, err := pageRequest(query.PageQuery, repository.LearningRecordSortFields, "updated_at", true)
 	if err != nil {
 		return nil, err
 	}
-	if len(records) == 0 {
-		return []dto.LearningProgressResponse{}, nil
+	records, total, err := s.records.SearchByUser(userID, query.Status, page)
+	if err != nil {
+		return nil, err
 	}
 
 	responses := make([]dto.LearningProgressResponse, 0, len(records))
@@ -181,7 +267,7 @@ func (s *LearningService) ListProgress(userID uint) ([]dto.LearningProgressRespo
 		resp := s.buildProgressResponse(&records[idx], content)
 		responses = append(responses, *resp)
 	}
-	return responses, nil
+	return &dto.LearningProgressListResponse{Items: responses, Pagination: pagination(page, total)}, nil
 }
 
 func (s *LearningService) buildProgressResponse(record *model.LearningRecord, content *model.Content) *dto.LearningProgressResponse {
@@ -189,11 +275,95 @@ func (s *LearningService) buildProgressResponse(record *model.LearningRecord, co
 		ContentID:       record.ContentID,
 		VideoPosition:   record.VideoPosition,
 		DurationSeconds: content.DurationSeconds,
+		PageCount:       content.PageCount,
+		PagesRead:       record.PagesRead,
+		LastPage:        record.LastPage,
 		Progress:        record.Progress,
 		Status:          record.Status,
 	}
 }
 
+// updatePageProgress accumulates time on the reported page and recomputes the share of pages
+// read. A page counts as read once the reader has spent MinPageSeconds on it in total.
+func (s *LearningService) updatePageProgress(userID uint, content *model.Content, record *model.LearningRecord, req dto.LearningProgressRequest, gate checkpointGate) error {
+	if req.Page > content.PageCount {
+		return errors.New("页码超出文档页数")
+	}
+	// 未答对的检查点之后的页面不计阅读时长
+	if req.Page > 0 && (gate.pending == nil || int64(req.Page) <= gate.pending.Position) {
+		if err := s.pageViews.AddDuration(userID, content.ID, req.Page, req.PageSeconds); err != nil {
+			return err
+		}
+		record.LastPage = req.Page
+	}
+
+	read, err := s.pageViews.CountRead(userID, content.ID, content.PageCount, s.opts.MinPageSeconds)
+	if err != nil {
+		return err
+	}
+	record.PagesRead = int(read)
+	if record.Status == "completed" {
+		return nil
+	}
+
+	record.Progress = record.PagesRead * 100 / content.PageCount
+	if gate.pending != nil {
+		if record.Progress > 99 {
+			record.Progress = 99
+		}
+	} else if record.Progress >= s.opts.DocCompletionPercent {
+		record.Status = "completed"
+		record.Progress = 100
+		now := time.Now()
+		record.CompletedAt = &now
+	} else if record.Status == "" {
+		record.Status = "in_progress"
+	}
+	return nil
+}
+
+// checkpointGate summarises the checkpoints of a content for one user. Learning past the first
+// checkpoint not yet answered correctly does not count, and the content cannot be completed
+// until every checkpoint is passed.
+type checkpointGate struct {
+	total   int
+	passed  int
+	pending *model.ContentCheckpoint
+}
+
+func (s *LearningService) checkpointGate(userID, contentID uint) (checkpointGate, error) {
+	checkpoints, err := s.checkpoints.ListByContent(contentID)
+	if err != nil || len(checkpoints) == 0 {
+		return checkpointGate{}, err
+	}
+	answers, err := s.answers.ListByUserAndContent(userID, contentID)
+	if err != nil {
+		return checkpointGate{}, err
+	}
+	passed := make(map[uint]bool, len(answers))
+	for _, answer := range answers {
+		passed[answer.CheckpointID] = answer.Passed
+	}
+
+	gate := checkpointGate{total: len(checkpoints)}
+	for i := range checkpoints {
+		if passed[checkpoints[i].ID] {
+			gate.passed++
+		} else if gate.pending == nil {
+			gate.pending = &checkpoints[i]
+		}
+	}
+	return gate, nil
+}
+
+func (g checkpointGate) apply(resp *dto.LearningProgressResponse) {
+	resp.CheckpointTotal = g.total
+	resp.CheckpointPassed = g.passed
+	if g.pending != nil {
+		resp.PendingCheckpointID = g.pending.ID
+	}
+}
+
 func (s *LearningService) ensureContentAccessible(user *model.User, content *model.Content) error {
 	if user.Role == model.RoleAdmin {
 		return nil
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+const (
+	docPagesPrefix   = "private/doc-pages/"
+	maxPageTextBytes = 60000 // TEXT column limit on MySQL is 64KB
+)
+
+// documentResult holds the outputs of one document conversion.
+type documentResult struct {
+	pages    []model.ContentPage
+	coverURL string
+}
+
+// convert renders the document into one PNG per page plus its text. Office files are first
+// converted to PDF with LibreOffice; PDFs are rendered directly with poppler.
+func (s *MediaService) convert(ctx context.Context, job *model.MediaJob) (*documentResult, error) {
+	workDir, source, err := s.prepare(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+
+	pdf := source
+	if filepath.Ext(source) != ".pdf" {
+		profile, err := filepath.Abs(filepath.Join(workDir, "profile"))
+		if err != nil {
+			return nil, err
+		}
+		// 独立的配置目录避免与其他 LibreOffice 进程争用同一用户配置
+		if _, err := s.run(ctx, s.opts.SofficePath, "-env:UserInstallation=file://"+filepath.ToSlash(profile),
+			"--headless", "--convert-to", "pdf", "--outdir", workDir, source); err != nil {
+			return nil, fmt.Errorf("convert to pdf: %w", err)
+		}
+		pdf = strings.TrimSuffix(source, filepath.Ext(source)) + ".pdf"
+		if _, err := os.Stat(pdf); err != nil {
+			return nil, errors.New("文档转换失败，未生成 PDF")
+		}
+	}
+
+	pagesDir := filepath.Join(workDir, "pages")
+	if err := os.MkdirAll(pagesDir, 0o755); err != nil {
+		return nil, fmt.Errorf("make pages dir: %w", err)
+	}
+	if _, err := s.run(ctx, s.opts.PdftoppmPath, "-png",
+		"-scale-to-x", strconv.Itoa(s.opts.PageWidth), "-scale-to-y", "-1",
+		pdf, filepath.Join(pagesDir, "page")); err != nil {
+		return nil, fmt.Errorf("render pages: %w", err)
+	}
+	images, err := pageImages(pagesDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) == 0 {
+		return nil, errors.New("文档没有可预览的页面")
+	}
+
+	textFile := filepath.Join(workDir, "text.txt")
+	if _, err := s.run(ctx, s.opts.PdftotextPath, "-layout", "-enc", "UTF-8", pdf, textFile); err != nil {
+		return nil, fmt.Errorf("extract text: %w", err)
+	}
+	text, err := os.ReadFile(textFile)
+	if err != nil {
+		return nil, fmt.Errorf("read text: %w", err)
+	}
+	// pdftotext 以换页符分隔各页
+	texts := strings.Split(string(text), "\f")
+
+	sum := sha256.Sum256([]byte(job.SourceKey))
+	dir := docPagesPrefix + hex.EncodeToString(sum[:16]) + "/"
+	result := &documentResult{pages: make([]model.ContentPage, 0, len(images))}
+	for i, image := range images {
+		key := fmt.Sprintf("%s%04d.png", dir, i+1)
+		if err := s.putFile(ctx, image, key, "image/png"); err != nil {
+			return nil, err
+		}
+		page := model.ContentPage{ContentID: job.ContentID, PageNo: i + 1, ImageKey: key}
+		if i < len(texts) {
+			page.Text = truncateUTF8(strings.TrimSpace(texts[i]), maxPageTextBytes)
+		}
+		result.pages = append(result.pages, page)
+	}
+
+	result.coverURL, err = s.saveFile(ctx, images[0])
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applyPages stores the page previews unless the content's document was replaced meanwhile.
+func (s *MediaService) applyPages(ctx context.Context, job *model.MediaJob, result *documentResult) error {
+	content, err := s.contents.FindByID(job.ContentID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	job.PageCount = len(result.pages)
+	job.ThumbnailURL = result.coverURL
+	job.LeaseUntil = nil
+	job.FinishedAt = &now
+	job.Error = ""
+
+	if content.FilePath != job.SourceKey {
+		job.Status = model.MediaJobCanceled
+		job.Error = "内容的文档已更换，处理结果未应用"
+		return s.jobs.Update(job)
+	}
+
+	before := documentAuditSnapshot(content)
+	fields := map[string]interface{}{"page_count": len(result.pages)}
+	content.PageCount = len(result.pages)
+	if content.CoverURL == "" {
+		fields["cover_url"] = result.coverURL
+		content.CoverURL = result.coverURL
+	}
+	if err := s.pages.ReplaceForContent(content.ID, result.pages, fields); err != nil {
+		return err
+	}
+
+	job.Status = model.MediaJobSucceeded
+	if err := s.jobs.Update(job); err != nil {
+		return err
+	}
+	_ = s.audit.RecordChange(ctx, 0, AuditChange{
+		Action:     "process_document",
+		Target:     "contents",
+		EntityType: AuditEntityContent,
+		EntityID:   content.ID,
+		Before:     before,
+		After:      documentAuditSnapshot(content),
+		Payload:    map[string]interface{}{"job_id": job.ID},
+	})
+	// 页面文字随文档一起可被搜索
+	_ = s.search.IndexContent(ctx, content)
+	return nil
+}
+
+// ListPages returns the page previews of a document the caller may read. Page images are
+// private and returned as short-lived signed URLs.
+func (s *MediaService) ListPages(ctx context.Context, content *model.Content, query dto.ContentPageQuery) (*dto.ContentPageListResponse, error) {
+	if content.Type != "doc" {
+		return nil, errors.New("仅文档内容支持分页预览")
+	}
+	page := query.Page
+	if page == 0 {
+		page = 1
+	}
+	size := query.PageSize
+	if size == 0 {
+		size = 10
+	}
+
+	items := []dto.ContentPageResponse{}
+	if content.PageCount > 0 {
+		pages, err := s.pages.ListByContent(content.ID, content.PageCount, page, size)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range pages {
+			items = append(items, dto.ContentPageResponse{
+				PageNo:   p.PageNo,
+				ImageURL: s.files.ResolveURL(ctx, p.ImageKey),
+				Text:     p.Text,
+			})
+		}
+	}
+	return &dto.ContentPageListResponse{
+		Items:      items,
+		Pagination: dto.NewPagination(page, size, int64(content.PageCount)),
+	}, nil
+}
+
+// pageImages lists the images written by pdftoppm in page order. pdftoppm zero-pads the page
+// number to the width of the page count, so names are ordered numerically, not lexically.
+func pageImages(dir string) ([]string, error) {
+	images, err := filepath.Glob(filepath.Join(dir, "page-*.png"))
+	if err != nil {
+		return nil, err
+	}
+	number := func(p string) int {
+		n, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(p), "page-"), ".png"))
+		return n
+	}
+	sort.Slice(images, func(i, j int) bool { return number(images[i]) < number(images[j]) })
+	return images, nil
+}
+
+func truncateUTF8(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	s = s[:max]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+func documentAuditSnapshot(content *model.Content) map[string]interface{} {
+	return map[string]interface{}{
+		"page_count": content.PageCount,
+		"cover_url":  content.CoverURL,
+	}
+}
@@ -0,0 +1,769 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
+)
+
+const (
+	hlsPrefix          = storage.PrivatePrefix + "hls/"
+	hlsMasterPlaylist  = "master.m3u8"
+	hlsPlaylistType    = "application/vnd.apple.mpegurl"
+	hlsSegmentType     = "video/mp2t"
+	mediaClaimBatch    = 10
+	mediaErrorTailSize = 2048
+)
+
+// hlsRendition is one rung of the adaptive bitrate ladder.
+type hlsRendition struct {
+	name         string
+	height       int
+	videoBitrate int // kbps
+	audioBitrate int // kbps
+}
+
+// Renditions taller than the source are skipped so small videos are never upscaled.
+var hlsLadder = []hlsRendition{
+	{name: "360p", height: 360, videoBitrate: 800, audioBitrate: 96},
+	{name: "720p", height: 720, videoBitrate: 2800, audioBitrate: 128},
+	{name: "1080p", height: 1080, videoBitrate: 5000, audioBitrate: 128},
+}
+
+// MediaOptions configures the video processing pipeline.
+type MediaOptions struct {
+	Enabled        bool
+	FFmpegPath     string
+	FFprobePath    string
+	WorkDir        string
+	SegmentSeconds int
+	MaxAttempts    int
+	JobTimeout     time.Duration
+	PlaybackTTL    time.Duration
+	PlaybackURL    string // route serving HLS playlists and segments, e.g. /api/v1/media/hls
+	SofficePath    string // LibreOffice, converts Office documents to PDF
+	PdftoppmPath   string // poppler, renders PDF pages to images
+	PdftotextPath  string // poppler, extracts page text
+	PageWidth      int    // rendered page image width in pixels
+}
+
+// MediaService runs the media pipeline: it probes uploaded videos for duration and resolution,
+// extracts a thumbnail and packages HLS renditions, renders documents into page images and text,
+// then writes the results back to the content.
+type MediaService struct {
+	jobs     *repository.MediaJobRepository
+	contents *repository.ContentRepository
+	pages    *repository.ContentPageRepository
+	users    *repository.UserRepository
+	files    *FileService
+	audit    *AuditService
+	search   *SearchService
+	opts     MediaOptions
+}
+
+// NewMediaService builds a MediaService.
+func NewMediaService(
+	jobRepo *repository.MediaJobRepository,
+	contentRepo *repository.ContentRepository,
+	pageRepo *repository.ContentPageRepository,
+	userRepo *repository.UserRepository,
+	files *FileService,
+	audit *AuditService,
+	search *SearchService,
+	opts MediaOptions,
+) *MediaService {
+	return &MediaService{
+		jobs:     jobRepo,
+		contents: contentRepo,
+		pages:    pageRepo,
+		users:    userRepo,
+		files:    files,
+		audit:    audit,
+		search:   search,
+		opts:     opts,
+	}
+}
+
+// Processable reports whether a video or document at filePath will be handled by the pipeline.
+// Only files in storage are processed; external URLs and legacy /uploads paths keep manual
+// durations and have no page previews.
+func (s *MediaService) Processable(contentType, filePath string) bool {
+	if !s.opts.Enabled {
+		return false
+	}
+	if !strings.HasPrefix(filePath, storage.PrivatePrefix) && !strings.HasPrefix(filePath, storage.PublicPrefix) {
+		return false
+	}
+	switch contentType {
+	case "video":
+		return true
+	case "doc":
+		return documentTypes[strings.ToLower(path.Ext(filePath))] != nil
+	default:
+		return false
+	}
+}
+
+// Enqueue schedules processing of the content's current file, superseding any job still
+// waiting for an older file. It is a no-op for contents the pipeline does not handle.
+func (s *MediaService) Enqueue(content *model.Content) (*model.MediaJob, error) {
+	if !s.Processable(content.Type, content.FilePath) {
+		return nil, nil
+	}
+	if err := s.jobs.CancelPendingByContent(content.ID); err != nil {
+		return nil, err
+	}
+	kind := model.MediaJobVideo
+	if content.Type == "doc" {
+		kind = model.MediaJobDocument
+	}
+	job := &model.MediaJob{
+		ContentID: content.ID,
+		Kind:      kind,
+		SourceKey: content.FilePath,
+		Status:    model.MediaJobPending,
+		NextRunAt: time.Now(),
+	}
+	if err := s.jobs.Create(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// AdminReprocess re-enqueues the content's video or document, e.g. after a failed job.
+func (s *MediaService) AdminReprocess(ctx context.Context, adminID, contentID uint) (*dto.MediaJobResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	content, err := s.contents.FindByID(contentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("内容不存在")
+		}
+		return nil, err
+	}
+	if !s.opts.Enabled {
+		return nil, errors.New("媒体处理未启用")
+	}
+	if !s.Processable(content.Type, content.FilePath) {
+		return nil, errors.New("仅支持处理已上传到存储的视频与文档内容")
+	}
+	job, err := s.Enqueue(content)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "reprocess_media",
+		Target:     "media_jobs",
+		EntityType: AuditEntityContent,
+		EntityID:   content.ID,
+		Payload:    map[string]interface{}{"job_id": job.ID, "source_key": job.SourceKey},
+	})
+	resp := toMediaJobResponse(job)
+	return &resp, nil
+}
+
+// AdminListJobs returns processing jobs, newest first.
+func (s *MediaService) AdminListJobs(adminID uint, query dto.AdminMediaJobQuery) (*dto.AdminMediaJobListResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	page := query.Page
+	if page == 0 {
+		page = 1
+	}
+	size := query.PageSize
+	if size == 0 {
+		size = 20
+	}
+
+	jobs, total, err := s.jobs.Search(repository.MediaJobFilter{
+		ContentID: query.ContentID,
+		Status:    query.Status,
+	}, page, size)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.MediaJobResponse, 0, len(jobs))
+	for i := range jobs {
+		items = append(items, toMediaJobResponse(&jobs[i]))
+	}
+	return &dto.AdminMediaJobListResponse{
+		Items:      items,
+		Pagination: dto.NewPagination(page, size, total),
+	}, nil
+}
+
+// PlaybackURL returns a signed HLS master playlist URL for the content, or "" when the video
+// has not been packaged. The signature covers the whole rendition directory, so the same
+// query string authorises every playlist and segment below it.
+func (s *MediaService) PlaybackURL(content *model.Content) string {
+	if content.HLSPath == "" {
+		return ""
+	}
+	expires := time.Now().Add(s.opts.PlaybackTTL).Unix()
+	return s.opts.PlaybackURL + "/" + content.HLSPath + "?" + s.playbackQuery(hlsDir(content.HLSPath), expires)
+}
+
+// OpenHLS verifies a signed playback request. Playlists are returned with the signature
+// appended to every URI so players can follow them. Segments are opened for byte-range
+// serving on local storage; other backends answer with a presigned redirect URL instead.
+func (s *MediaService) OpenHLS(ctx context.Context, key string, query dto.SignedDownloadQuery) (*HLSObject, error) {
+	cleaned, err := storage.CleanKey(key)
+	if err != nil || cleaned != key || !strings.HasPrefix(key, hlsPrefix) {
+		return nil, storage.ErrInvalidKey
+	}
+	if err := s.files.signer.Verify(hlsDir(key), query.Expires, query.Signature, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if strings.HasSuffix(key, ".m3u8") {
+		body, info, err := s.files.store.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		playlist, err := io.ReadAll(body)
+		if err != nil {
+			return nil, fmt.Errorf("read playlist: %w", err)
+		}
+		return &HLSObject{
+			Playlist:    signPlaylist(playlist, s.playbackQuery(hlsDir(key), query.Expires)),
+			ContentType: hlsPlaylistType,
+			ModTime:     info.ModTime,
+		}, nil
+	}
+
+	body, info, redirect, err := s.files.openSeekable(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &HLSObject{Body: body, Redirect: redirect, ContentType: hlsSegmentType, ETag: info.ETag, ModTime: info.ModTime}, nil
+}
+
+// HLSObject is a resolved HLS request: a signed playlist, a segment body or a redirect.
+type HLSObject struct {
+	Playlist    []byte
+	Body        io.ReadCloser
+	Redirect    string
+	ContentType string
+	ETag        string
+	ModTime     time.Time
+}
+
+// RunWorker processes queued jobs until ctx is cancelled, polling for new ones every interval.
+func (s *MediaService) RunWorker(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	drain := func() {
+		for ctx.Err() == nil {
+			job, err := s.ProcessNext(ctx)
+			if err != nil {
+				logger.Error("media job failed", zap.Error(err))
+			}
+			if job == nil {
+				return
+			}
+			logger.Info("media job finished",
+				zap.Uint("job_id", job.ID),
+				zap.Uint("content_id", job.ContentID),
+				zap.String("status", job.Status))
+		}
+	}
+
+	drain()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			drain()
+		}
+	}
+}
+
+// ProcessNext claims and runs one due job. It returns nil when there is nothing to do; a job
+// that failed is returned together with the processing error.
+func (s *MediaService) ProcessNext(ctx context.Context) (*model.MediaJob, error) {
+	now := time.Now()
+	candidates, err := s.jobs.ListClaimable(now, mediaClaimBatch)
+	if err != nil {
+		return nil, err
+	}
+	for i := range candidates {
+		job := &candidates[i]
+		if job.Status == model.MediaJobRunning && job.Attempts >= s.opts.MaxAttempts {
+			// 进程在最后一次尝试中退出，租约过期后直接判定失败
+			job.Status = model.MediaJobFailed
+			job.Error = "任务执行超时，已达到最大尝试次数"
+			job.LeaseUntil = nil
+			job.FinishedAt = &now
+			if err := s.jobs.Update(job); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		claimed, err := s.jobs.Claim(job, now, now.Add(s.opts.JobTimeout))
+		if err != nil {
+			return nil, err
+		}
+		if !claimed {
+			continue
+		}
+		return job, s.process(ctx, job)
+	}
+	return nil, nil
+}
+
+func (s *MediaService) process(ctx context.Context, job *model.MediaJob) error {
+	runCtx, cancel := context.WithTimeout(ctx, s.opts.JobTimeout)
+	defer cancel()
+
+	var err error
+	if job.Kind == model.MediaJobDocument {
+		var pages *documentResult
+		if pages, err = s.convert(runCtx, job); err == nil {
+			err = s.applyPages(ctx, job, pages)
+		}
+	} else {
+		var result *mediaResult
+		if result, err = s.transcode(runCtx, job); err == nil {
+			err = s.apply(ctx, job, result)
+		}
+	}
+	if err != nil {
+		return s.fail(job, err)
+	}
+	return nil
+}
+
+// mediaResult holds the outputs of one pipeline run.
+type mediaResult struct {
+	durationSeconds int64
+	width, height   int
+	thumbnailURL    string
+	hlsKey          string
+}
+
+func (s *MediaService) transcode(ctx context.Context, job *model.MediaJob) (*mediaResult, error) {
+	workDir, source, err := s.prepare(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+
+	result, err := s.probe(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnail := filepath.Join(workDir, "thumbnail.jpg")
+	offset := math.Min(float64(result.durationSeconds)/10, 5)
+	if _, err := s.run(ctx, s.opts.FFmpegPath, "-y", "-v", "error",
+		"-ss", strconv.FormatFloat(offset, 'f', 2, 64), "-i", source,
+		"-frames:v", "1", "-vf", "scale=-2:'min(720,ih)'", "-q:v", "3", thumbnail); err != nil {
+		return nil, fmt.Errorf("extract thumbnail: %w", err)
+	}
+	thumb, err := s.saveFile(ctx, thumbnail)
+	if err != nil {
+		return nil, err
+	}
+	result.thumbnailURL = thumb
+
+	result.hlsKey, err = s.packageHLS(ctx, job, source, workDir, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// prepare creates a scratch directory for the job and copies the source file into it.
+func (s *MediaService) prepare(ctx context.Context, job *model.MediaJob) (string, string, error) {
+	if err := os.MkdirAll(s.opts.WorkDir, 0o755); err != nil {
+		return "", "", fmt.Errorf("make media work dir: %w", err)
+	}
+	workDir, err := os.MkdirTemp(s.opts.WorkDir, fmt.Sprintf("job-%d-", job.ID))
+	if err != nil {
+		return "", "", fmt.Errorf("make media work dir: %w", err)
+	}
+	source := filepath.Join(workDir, "source"+strings.ToLower(path.Ext(job.SourceKey)))
+	if err := s.download(ctx, job.SourceKey, source); err != nil {
+		os.RemoveAll(workDir)
+		return "", "", err
+	}
+	return workDir, source, nil
+}
+
+func (s *MediaService) download(ctx context.Context, key, dst string) error {
+	body, _, err := s.files.store.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("open source video: %w", err)
+	}
+	defer body.Close()
+
+	file, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("create source copy: %w", err)
+	}
+	_, err = io.Copy(file, body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("copy source video: %w", err)
+	}
+	return nil
+}
+
+// ffprobeOutput is the subset of `ffprobe -print_format json` the pipeline reads.
+type ffprobeOutput struct {
+	Format struct {
+		Duration string `json:"duration"`
+	} `json:"format"`
+	Streams []struct {
+		CodecType string `json:"codec_type"`
+		Width     int    `json:"width"`
+		Height    int    `json:"height"`
+	} `json:"streams"`
+}
+
+func (s *MediaService) probe(ctx context.Context, source string) (*mediaResult, error) {
+	out, err := s.run(ctx, s.opts.FFprobePath, "-v", "error", "-print_format", "json",
+		"-show_format", "-show_streams", source)
+	if err != nil {
+		return nil, fmt.Errorf("probe video: %w", err)
+	}
+	var probed ffprobeOutput
+	if err := json.Unmarshal(out, &probed); err != nil {
+		return nil, fmt.Errorf("parse probe output: %w", err)
+	}
+
+	result := &mediaResult{}
+	for _, stream := range probed.Streams {
+		if stream.CodecType == "video" && stream.Height > 0 {
+			result.width, result.height = stream.Width, stream.Height
+			break
+		}
+	}
+	if result.height == 0 {
+		return nil, errors.New("文件中没有可识别的视频流")
+	}
+	duration, err := strconv.ParseFloat(probed.Format.Duration, 64)
+	if err != nil || duration <= 0 {
+		return nil, errors.New("无法识别视频时长")
+	}
+	result.durationSeconds = int64(math.Ceil(duration))
+	return result, nil
+}
+
+// packageHLS encodes the ladder into byte-range HLS (one .ts file per rendition) and uploads it
+// under a directory derived from the source key. Identical sources share the packaged output,
+// so a master playlist that already exists is reused as is.
+func (s *MediaService) packageHLS(ctx context.Context, job *model.MediaJob, source, workDir string, probed *mediaResult) (string, error) {
+	sum := sha256.Sum256([]byte(job.SourceKey))
+	dir := hlsPrefix + hex.EncodeToString(sum[:16]) + "/"
+	masterKey := dir + hlsMasterPlaylist
+	if _, err := s.files.store.Stat(ctx, masterKey); err == nil {
+		return masterKey, nil
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return "", err
+	}
+
+	outDir := filepath.Join(workDir, "hls")
+	var master strings.Builder
+	master.WriteString("#EXTM3U\n#EXT-X-VERSION:4\n#EXT-X-INDEPENDENT-SEGMENTS\n")
+	for _, rendition := range renditionsFor(probed.height) {
+		width := evenRound(float64(probed.width) * float64(rendition.height) / float64(probed.height))
+		renditionDir := filepath.Join(outDir, rendition.name)
+		if err := os.MkdirAll(renditionDir, 0o755); err != nil {
+			return "", fmt.Errorf("make rendition dir: %w", err)
+		}
+		segment := strconv.Itoa(s.opts.SegmentSeconds)
+		if _, err := s.run(ctx, s.opts.FFmpegPath, "-y", "-v", "error", "-i", source,
+			"-map", "0:v:0", "-map", "0:a:0?",
+			"-vf", fmt.Sprintf("scale=%d:%d", width, rendition.height),
+			"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main",
+			"-b:v", fmt.Sprintf("%dk", rendition.videoBitrate),
+			"-maxrate", fmt.Sprintf("%dk", rendition.videoBitrate*107/100),
+			"-bufsize", fmt.Sprintf("%dk", rendition.videoBitrate*3/2),
+			"-force_key_frames", "expr:gte(t,n_forced*"+segment+")",
+			"-c:a", "aac", "-b:a", fmt.Sprintf("%dk", rendition.audioBitrate), "-ac", "2",
+			"-f", "hls", "-hls_time", segment, "-hls_playlist_type", "vod",
+			"-hls_flags", "single_file+independent_segments",
+			"-hls_segment_filename", filepath.Join(renditionDir, "stream.ts"),
+			filepath.Join(renditionDir, "index.m3u8")); err != nil {
+			return "", fmt.Errorf("package %s: %w", rendition.name, err)
+		}
+		bandwidth := (rendition.videoBitrate + rendition.audioBitrate) * 1000 * 11 / 10
+		fmt.Fprintf(&master, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d\n%s/index.m3u8\n",
+			bandwidth, width, rendition.height, rendition.name)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, hlsMasterPlaylist), []byte(master.String()), 0o644); err != nil {
+		return "", fmt.Errorf("write master playlist: %w", err)
+	}
+
+	// 主播放列表最后上传，它存在即表示整个目录已完整
+	var files []string
+	err := filepath.Walk(outDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || (info.Name() == hlsMasterPlaylist && filepath.Dir(p) == outDir) {
+			return err
+		}
+		files = append(files, p)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("collect hls output: %w", err)
+	}
+	files = append(files, filepath.Join(outDir, hlsMasterPlaylist))
+	for _, p := range files {
+		rel, _ := filepath.Rel(outDir, p)
+		contentType := hlsSegmentType
+		if strings.HasSuffix(p, ".m3u8") {
+			contentType = hlsPlaylistType
+		}
+		if err := s.putFile(ctx, p, dir+filepath.ToSlash(rel), contentType); err != nil {
+			return "", err
+		}
+	}
+	return masterKey, nil
+}
+
+// putFile uploads a generated file to storage under key.
+func (s *MediaService) putFile(ctx context.Context, src, key, contentType string) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open media output: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("stat media output: %w", err)
+	}
+	return s.files.store.Put(ctx, key, file, info.Size(), contentType)
+}
+
+// saveFile stores a generated thumbnail as a public cover image.
+func (s *MediaService) saveFile(ctx context.Context, src string) (string, error) {
+	file, err := os.Open(src)
+	if err != nil {
+		return "", fmt.Errorf("open thumbnail: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("stat thumbnail: %w", err)
+	}
+	resp, err := s.files.save(ctx, 0, UploadPurposeCover, filepath.Base(src), file, info.Size(), "")
+	if err != nil {
+		return "", fmt.Errorf("store thumbnail: %w", err)
+	}
+	return resp.Path, nil
+}
+
+// apply writes the results to the content unless its video was replaced while processing.
+func (s *MediaService) apply(ctx context.Context, job *model.MediaJob, result *mediaResult) error {
+	content, err := s.contents.FindByID(job.ContentID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	job.DurationSeconds = result.durationSeconds
+	job.Width, job.Height = result.width, result.height
+	job.ThumbnailURL = result.thumbnailURL
+	job.HLSKey = result.hlsKey
+	job.LeaseUntil = nil
+	job.FinishedAt = &now
+	job.Error = ""
+
+	if content.FilePath != job.SourceKey {
+		job.Status = model.MediaJobCanceled
+		job.Error = "内容的视频文件已更换，处理结果未应用"
+		return s.jobs.Update(job)
+	}
+
+	before := mediaAuditSnapshot(content)
+	fields := map[string]interface{}{
+		"duration_seconds": result.durationSeconds,
+		"hls_path":         result.hlsKey,
+	}
+	content.DurationSeconds = result.durationSeconds
+	content.HLSPath = result.hlsKey
+	if content.CoverURL == "" {
+		fields["cover_url"] = result.thumbnailURL
+		content.CoverURL = result.thumbnailURL
+	}
+	if err := s.contents.UpdateFields(content.ID, fields); err != nil {
+		return err
+	}
+
+	job.Status = model.MediaJobSucceeded
+	if err := s.jobs.Update(job); err != nil {
+		return err
+	}
+	_ = s.audit.RecordChange(ctx, 0, AuditChange{
+		Action:     "process_media",
+		Target:     "contents",
+		EntityType: AuditEntityContent,
+		EntityID:   content.ID,
+		Before:     before,
+		After:      mediaAuditSnapshot(content),
+		Payload:    map[string]interface{}{"job_id": job.ID, "width": result.width, "height": result.height},
+	})
+	return nil
+}
+
+// fail records the error and schedules a retry with quadratic backoff until MaxAttempts.
+func (s *MediaService) fail(job *model.MediaJob, cause error) error {
+	now := time.Now()
+	job.Error = cause.Error()
+	job.LeaseUntil = nil
+	if job.Attempts >= s.opts.MaxAttempts {
+		job.Status = model.MediaJobFailed
+		job.FinishedAt = &now
+	} else {
+		job.Status = model.MediaJobPending
+		job.NextRunAt = now.Add(time.Duration(job.Attempts*job.Attempts) * time.Minute)
+	}
+	if err := s.jobs.Update(job); err != nil {
+		return err
+	}
+	return cause
+}
+
+// run executes an external tool and returns its stdout; failures carry the tail of stderr.
+func (s *MediaService) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		detail := strings.TrimSpace(stderr.String())
+		if len(detail) > mediaErrorTailSize {
+			detail = detail[len(detail)-mediaErrorTailSize:]
+		}
+		if detail != "" {
+			return nil, fmt.Errorf("%s: %w: %s", filepath.Base(name), err, detail)
+		}
+		return nil, fmt.Errorf("%s: %w", filepath.Base(name), err)
+	}
+	return stdout.Bytes(), nil
+}
+
+func (s *MediaService) playbackQuery(dir string, expires int64) string {
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.files.signer.Sign(dir, expires))
+	return query.Encode()
+}
+
+func (s *MediaService) ensureAdmin(userID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("管理员不存在")
+		}
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return errors.New("仅管理员可操作")
+	}
+	return nil
+}
+
+// renditionsFor picks the ladder rungs not taller than the source, keeping at least one.
+func renditionsFor(sourceHeight int) []hlsRendition {
+	var picked []hlsRendition
+	for _, rendition := range hlsLadder {
+		if rendition.height <= sourceHeight {
+			picked = append(picked, rendition)
+		}
+	}
+	if len(picked) == 0 {
+		lowest := hlsLadder[0]
+		lowest.name = strconv.Itoa(sourceHeight) + "p"
+		lowest.height = sourceHeight - sourceHeight%2
+		picked = append(picked, lowest)
+	}
+	return picked
+}
+
+// hlsDir returns the packaged directory ("private/hls/<id>/") a key belongs to.
+func hlsDir(key string) string {
+	parts := strings.SplitN(strings.TrimPrefix(key, hlsPrefix), "/", 2)
+	return hlsPrefix + parts[0] + "/"
+}
+
+// signPlaylist appends query to every URI line so relative playlist and segment requests
+// carry the playback signature.
+func signPlaylist(playlist []byte, query string) []byte {
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(playlist))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			line += "?" + query
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
+
+func evenRound(v float64) int {
+	n := int(math.Round(v))
+	return n + n%2
+}
+
+func mediaAuditSnapshot(content *model.Content) map[string]interface{} {
+	return map[string]interface{}{
+		"duration_seconds": content.DurationSeconds,
+		"cover_url":        content.CoverURL,
+		"hls_path":         content.HLSPath,
+	}
+}
+
+func toMediaJobResponse(job *model.MediaJob) dto.MediaJobResponse {
+	return dto.MediaJobResponse{
+		ID:              job.ID,
+		ContentID:       job.ContentID,
+		Kind:            job.Kind,
+		SourceKey:       job.SourceKey,
+		Status:          job.Status,
+		Attempts:        job.Attempts,
+		NextRunAt:       job.NextRunAt,
+		Error:           job.Error,
+		DurationSeconds: job.DurationSeconds,
+		Width:           job.Width,
+		Height:          job.Height,
+		ThumbnailURL:    job.ThumbnailURL,
+		HLSKey:          job.HLSKey,
+		PageCount:       job.PageCount,
+		StartedAt:       job.StartedAt,
+		FinishedAt:      job.FinishedAt,
+		CreatedAt:       job.CreatedAt,
+	}
+}
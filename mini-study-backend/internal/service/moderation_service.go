@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/moderation"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+)
+
+// ModerationOptions configures automated moderation.
+type ModerationOptions struct {
+	Enabled         bool
+	RefreshInterval time.Duration // how long a loaded dictionary is used before it is read again
+	ImageBaseURL    string        // prefix turning stored image paths into URLs for the external checker
+}
+
+// ModerationOutcome is the automated decision on a piece of content.
+type ModerationOutcome struct {
+	Verdict string
+	Matches []moderation.Match
+	Labels  []string
+	Note    string
+}
+
+// ModerationService 负责成长圈自动审核：内置敏感词词典匹配，并可叠加外部图文审核服务。
+type ModerationService struct {
+	words   *repository.SensitiveWordRepository
+	users   *repository.UserRepository
+	audit   *AuditService
+	checker moderation.Checker
+	opts    ModerationOptions
+
+	mu       sync.Mutex
+	matcher  *moderation.Matcher
+	loadedAt time.Time
+}
+
+// NewModerationService 创建自动审核服务，checker 为空时只使用敏感词词典。
+func NewModerationService(words *repository.SensitiveWordRepository, users *repository.UserRepository, audit *AuditService, checker moderation.Checker, opts ModerationOptions) *ModerationService {
+	return &ModerationService{words: words, users: users, audit: audit, checker: checker, opts: opts}
+}
+
+func (s *ModerationService) ensureAdmin(userID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("管理员不存在")
+		}
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return errors.New("仅管理员可操作")
+	}
+	return nil
+}
+
+// Enabled reports whether posts are moderated automatically.
+func (s *ModerationService) Enabled() bool {
+	return s != nil && s.opts.Enabled
+}
+
+// Moderate 检查一条内容：命中 block 级敏感词或外部服务判定违规的拒绝，命中 review 级敏感词、
+// 外部服务存疑或不可用的转人工，其余通过。
+func (s *ModerationService) Moderate(ctx context.Context, text string, imagePaths []string) *ModerationOutcome {
+	outcome := &ModerationOutcome{Verdict: moderation.VerdictPass}
+	var notes []string
+
+	matcher, err := s.currentMatcher()
+	if err != nil {
+		outcome.Verdict = moderation.VerdictReview
+		notes = append(notes, "敏感词词典加载失败")
+	}
+	outcome.Matches = matcher.Find(text)
+	if words := matchedWords(outcome.Matches); len(words) > 0 {
+		for _, m := range outcome.Matches {
+			outcome.Verdict = moderation.Stricter(outcome.Verdict, matchVerdict(m.Level))
+		}
+		notes = append(notes, "命中敏感词："+strings.Join(words, "、"))
+	}
+
+	// 已被词典拒绝的内容无需再调用外部服务
+	if s.checker != nil && outcome.Verdict != moderation.VerdictBlock {
+		result, err := s.checker.Check(ctx, moderation.Input{Text: text, ImageURLs: s.imageURLs(imagePaths)})
+		if err != nil {
+			outcome.Verdict = moderation.Stricter(outcome.Verdict, moderation.VerdictReview)
+			notes = append(notes, "外部审核服务不可用")
+		} else {
+			outcome.Verdict = moderation.Stricter(outcome.Verdict, result.Verdict)
+			outcome.Labels = result.Labels
+			if result.Reason != "" {
+				notes = append(notes, "外部审核："+result.Reason)
+			}
+		}
+	}
+
+	outcome.Note = truncateUTF8(strings.Join(notes, "；"), 255)
+	return outcome
+}
+
+// Check 试运行自动审核，便于管理员调整词典后验证效果。
+func (s *ModerationService) Check(ctx context.Context, adminID uint, req dto.ModerationCheckRequest) (*dto.ModerationResultResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	outcome := s.Moderate(ctx, req.Content, req.ImagePaths)
+	return outcome.response(req.Content), nil
+}
+
+// ListWords 分页查询敏感词。
+func (s *ModerationService) ListWords(adminID uint, query dto.SensitiveWordQuery) (*dto.SensitiveWordListResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	page, err := pageRequest(query.PageQuery, repository.SensitiveWordSortFields, "id", true)
+	if err != nil {
+		return nil, err
+	}
+	words, total, err := s.words.Search(query.Keyword, query.Category, query.Level, page)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.SensitiveWordResponse, 0, len(words))
+	for i := range words {
+		items = append(items, toSensitiveWordResponse(&words[i]))
+	}
+	return &dto.SensitiveWordListResponse{Items: items, Pagination: pagination(page, total)}, nil
+}
+
+// CreateWord 新增敏感词，立即生效。
+func (s *ModerationService) CreateWord(ctx context.Context, adminID uint, req dto.SensitiveWordRequest) (*dto.SensitiveWordResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	word := &model.SensitiveWord{}
+	if err := s.fillWord(word, req); err != nil {
+		return nil, err
+	}
+	if err := s.words.Create(word); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	if s.audit != nil {
+		_ = s.audit.Record(ctx, adminID, "create_sensitive_word", "sensitive_words", word.Word, "success")
+	}
+	resp := toSensitiveWordResponse(word)
+	return &resp, nil
+}
+
+// UpdateWord 修改敏感词，立即生效。
+func (s *ModerationService) UpdateWord(ctx context.Context, adminID, id uint, req dto.SensitiveWordRequest) (*dto.SensitiveWordResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	word, err := s.findWord(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillWord(word, req); err != nil {
+		return nil, err
+	}
+	if err := s.words.Update(word); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	if s.audit != nil {
+		_ = s.audit.Record(ctx, adminID, "update_sensitive_word", "sensitive_words", word.Word, "success")
+	}
+	resp := toSensitiveWordResponse(word)
+	return &resp, nil
+}
+
+// DeleteWord 删除敏感词，立即生效。
+func (s *ModerationService) DeleteWord(ctx context.Context, adminID, id uint) error {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return err
+	}
+	word, err := s.findWord(id)
+	if err != nil {
+		return err
+	}
+	if err := s.words.Delete(word); err != nil {
+		return err
+	}
+	s.invalidate()
+	if s.audit != nil {
+		_ = s.audit.Record(ctx, adminID, "delete_sensitive_word", "sensitive_words", word.Word, "success")
+	}
+	return nil
+}
+
+func (s *ModerationService) findWord(id uint) (*model.SensitiveWord, error) {
+	word, err := s.words.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("敏感词不存在")
+		}
+		return nil, err
+	}
+	return word, nil
+}
+
+func (s *ModerationService) fillWord(word *model.SensitiveWord, req dto.SensitiveWordRequest) error {
+	text := strings.TrimSpace(req.Word)
+	if moderation.Normalize(text) == "" {
+		return errors.New("敏感词须包含文字或数字")
+	}
+	exists, err := s.words.ExistsWord(text, word.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("敏感词已存在")
+	}
+	word.Word = text
+	word.Category = strings.TrimSpace(req.Category)
+	word.Level = req.Level
+	return nil
+}
+
+// currentMatcher returns the dictionary matcher, reloading it once RefreshInterval has passed so
+// that edits made on other instances are picked up. On a load failure the previous matcher, which
+// may be nil, is returned with the error.
+func (s *ModerationService) currentMatcher() (*moderation.Matcher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.matcher != nil && time.Since(s.loadedAt) < s.opts.RefreshInterval {
+		return s.matcher, nil
+	}
+	words, err := s.words.ListAll()
+	if err != nil {
+		return s.matcher, err
+	}
+	entries := make([]moderation.Word, 0, len(words))
+	for _, w := range words {
+		entries = append(entries, moderation.Word{Text: w.Word, Category: w.Category, Level: w.Level})
+	}
+	s.matcher = moderation.NewMatcher(entries)
+	s.loadedAt = time.Now()
+	return s.matcher, nil
+}
+
+// invalidate makes the next check reload the dictionary.
+func (s *ModerationService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *ModerationService) imageURLs(paths []string) []string {
+	urls := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://") || s.opts.ImageBaseURL == "" {
+			urls = append(urls, p)
+			continue
+		}
+		urls = append(urls, strings.TrimSuffix(s.opts.ImageBaseURL, "/")+"/"+strings.TrimPrefix(p, "/"))
+	}
+	return urls
+}
+
+// storedMatch is how matches are kept on the moderated record.
+type storedMatch struct {
+	Word     string `json:"word"`
+	Category string `json:"category,omitempty"`
+	Level    string `json:"level"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+}
+
+// encodeMatches serializes matches for storage.
+func encodeMatches(matches []moderation.Match) string {
+	if len(matches) == 0 {
+		return ""
+	}
+	stored := make([]storedMatch, 0, len(matches))
+	for _, m := range matches {
+		stored = append(stored, storedMatch{Word: m.Word, Category: m.Category, Level: m.Level, Start: m.Start, End: m.End})
+	}
+	data, _ := json.Marshal(stored)
+	return string(data)
+}
+
+// decodeMatches reads stored matches, dropping any that no longer fit text.
+func decodeMatches(data, text string) []moderation.Match {
+	if data == "" {
+		return nil
+	}
+	var stored []storedMatch
+	if err := json.Unmarshal([]byte(data), &stored); err != nil {
+		return nil
+	}
+	matches := make([]moderation.Match, 0, len(stored))
+	for _, m := range stored {
+		if m.Start < 0 || m.Start >= m.End || m.End > len(text) {
+			continue
+		}
+		matches = append(matches, moderation.Match{Word: m.Word, Category: m.Category, Level: m.Level, Start: m.Start, End: m.End})
+	}
+	return matches
+}
+
+func (o *ModerationOutcome) response(text string) *dto.ModerationResultResponse {
+	matches := make([]dto.ModerationMatchResponse, 0, len(o.Matches))
+	for _, m := range o.Matches {
+		matches = append(matches, dto.ModerationMatchResponse{
+			Word:     m.Word,
+			Text:     text[m.Start:m.End],
+			Category: m.Category,
+			Level:    m.Level,
+		})
+	}
+	return &dto.ModerationResultResponse{
+		Verdict:   o.Verdict,
+		Matches:   matches,
+		Labels:    o.Labels,
+		Note:      o.Note,
+		Highlight: moderation.Highlight(text, o.Matches),
+	}
+}
+
+// matchedWords lists the distinct dictionary words among matches.
+func matchedWords(matches []moderation.Match) []string {
+	seen := make(map[string]bool, len(matches))
+	var words []string
+	for _, m := range matches {
+		if !seen[m.Word] {
+			seen[m.Word] = true
+			words = append(words, m.Word)
+		}
+	}
+	return words
+}
+
+func matchVerdict(level string) string {
+	if level == moderation.LevelBlock {
+		return moderation.VerdictBlock
+	}
+	return moderation.VerdictReview
+}
+
+func toSensitiveWordResponse(word *model.SensitiveWord) dto.SensitiveWordResponse {
+	return dto.SensitiveWordResponse{
+		ID:        word.ID,
+		Word:      word.Word,
+		Category:  word.Category,
+		Level:     word.Level,
+		CreatedAt: word.CreatedAt,
+		UpdatedAt: word.UpdatedAt,
+	}
+}
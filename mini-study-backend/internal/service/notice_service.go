@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -39,15 +40,23 @@ func (s *NoticeService) GetLatestNotice(userID uint) (*model.Notice, error) {
 }
 
 // AdminListNotices lists notices for admin.
-func (s *NoticeService) AdminListNotices(adminID uint, status *bool) ([]model.Notice, error) {
+func (s *NoticeService) AdminListNotices(adminID uint, query dto.AdminListNoticeQuery) ([]model.Notice, dto.Pagination, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
-		return nil, err
+		return nil, dto.Pagination{}, err
+	}
+	page, err := pageRequest(query.PageQuery, repository.NoticeSortFields, "start_at", true)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	notices, total, err := s.repo.SearchAdmin(query.Status, page)
+	if err != nil {
+		return nil, dto.Pagination{}, err
 	}
-	return s.repo.ListAdmin(status)
+	return notices, pagination(page, total), nil
 }
 
 // AdminCreateNotice creates notice.
-func (s *NoticeService) AdminCreateNotice(adminID uint, req dto.AdminCreateNoticeRequest) (*model.Notice, error) {
+func (s *NoticeService) AdminCreateNotice(ctx context.Context, adminID uint, req dto.AdminCreateNoticeRequest) (*model.Notice, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
@@ -67,12 +76,18 @@ func (s *NoticeService) AdminCreateNotice(adminID uint, req dto.AdminCreateNotic
 	if err := s.repo.Create(notice); err != nil {
 		return nil, err
 	}
-	_ = s.audit.Record(adminID, "create_notice", "notices", notice.Title, "success")
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "create_notice",
+		Target:     "notices",
+		EntityType: AuditEntityNotice,
+		EntityID:   notice.ID,
+		After:      notice,
+	})
 	return notice, nil
 }
 
 // AdminUpdateNotice updates notice fields.
-func (s *NoticeService) AdminUpdateNotice(adminID, noticeID uint, req dto.AdminUpdateNoticeRequest) (*model.Notice, error) {
+func (s *NoticeService) AdminUpdateNotice(ctx context.Context, adminID, noticeID uint, req dto.AdminUpdateNoticeRequest) (*model.Notice, error) {
 	if err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
@@ -81,6 +96,7 @@ func (s *NoticeService) AdminUpdateNotice(adminID, noticeID uint, req dto.AdminU
 	if err != nil {
 		return nil, err
 	}
+	before := *notice
 
 	if req.Title != "" {
 		notice.Title = req.Title
@@ -102,7 +118,14 @@ func (s *NoticeService) AdminUpdateNotice(adminID, noticeID uint, req dto.AdminU
 	if err := s.repo.Update(notice); err != nil {
 		return nil, err
 	}
-	_ = s.audit.Record(adminID, "update_notice", "notices", notice.Title, "success")
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "update_notice",
+		Target:     "notices",
+		EntityType: AuditEntityNotice,
+		EntityID:   notice.ID,
+		Before:     before,
+		After:      notice,
+	})
 	return notice, nil
 }
 
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+)
+
+// Notification types.
+const (
+	NotificationGrowthLike    = "growth_like"
+	NotificationGrowthComment = "growth_comment"
+	NotificationGrowthReply   = "growth_reply"
+	NotificationGrowthShare   = "growth_share"
+
+	NotificationGrowthApproved = "growth_approved"
+	NotificationGrowthRejected = "growth_rejected"
+	NotificationGrowthFeatured = "growth_featured"
+
+	NotificationCertificationExpiring = "certification_expiring"
+	NotificationCertificationExpired  = "certification_expired"
+)
+
+// maxNotificationSummary is the byte size of the notification summary column.
+const maxNotificationSummary = 255
+
+// NotificationService delivers and reads in-app notifications.
+type NotificationService struct {
+	repo *repository.NotificationRepository
+}
+
+// NewNotificationService creates notification service.
+func NewNotificationService(repo *repository.NotificationRepository) *NotificationService {
+	return &NotificationService{repo: repo}
+}
+
+// Notify sends a notification to a user. Users are not notified of their own actions.
+func (s *NotificationService) Notify(recipientID, actorID uint, kind, entityType string, entityID uint, summary string) error {
+	if recipientID == 0 || recipientID == actorID {
+		return nil
+	}
+	return s.repo.Create(&model.Notification{
+		UserID:     recipientID,
+		ActorID:    actorID,
+		Type:       kind,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Summary:    truncateUTF8(summary, maxNotificationSummary),
+	})
+}
+
+// List returns the user's notifications, newest first, together with the unread count.
+func (s *NotificationService) List(userID uint, query dto.NotificationListQuery) (*dto.NotificationListResponse, error) {
+	cursor, err := cursorRequest(query.CursorQuery)
+	if err != nil {
+		return nil, err
+	}
+	notifications, total, more, err := s.repo.FeedByUser(userID, query.UnreadOnly, cursor)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.NotificationResponse, 0, len(notifications))
+	for i := range notifications {
+		items = append(items, notificationResponse(&notifications[i]))
+	}
+	var last model.Notification
+	if len(notifications) > 0 {
+		last = notifications[len(notifications)-1]
+	}
+	return &dto.NotificationListResponse{
+		Items:       items,
+		UnreadCount: unread,
+		Pagination:  feedPagination(cursor, total, more, last.CreatedAt, last.ID),
+	}, nil
+}
+
+// MarkRead marks one of the user's notifications as read.
+func (s *NotificationService) MarkRead(userID, id uint) error {
+	if err := s.repo.MarkRead(userID, id, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("通知不存在")
+		}
+		return err
+	}
+	return nil
+}
+
+// MarkAllRead marks all of the user's notifications as read.
+func (s *NotificationService) MarkAllRead(userID uint) (*dto.NotificationReadAllResponse, error) {
+	updated, err := s.repo.MarkAllRead(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &dto.NotificationReadAllResponse{Updated: updated}, nil
+}
+
+func notificationResponse(notification *model.Notification) dto.NotificationResponse {
+	return dto.NotificationResponse{
+		ID:         notification.ID,
+		Type:       notification.Type,
+		ActorID:    notification.ActorID,
+		ActorName:  notification.Actor.Name,
+		EntityType: notification.EntityType,
+		EntityID:   notification.EntityID,
+		Summary:    notification.Summary,
+		Read:       notification.ReadAt != nil,
+		ReadAt:     notification.ReadAt,
+		CreatedAt:  notification.CreatedAt,
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+const defaultPageSize = 20
+
+// pageRequest resolves an offset page against the sort whitelist of a list. Without a sort
+// field the list uses its default order; a sort field without a direction sorts ascending.
+func pageRequest(query dto.PageQuery, fields repository.SortFields, defaultSort string, defaultDesc bool) (repository.PageRequest, error) {
+	req := repository.PageRequest{Page: query.Page, PageSize: query.PageSize}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = defaultPageSize
+	}
+
+	field, desc := defaultSort, defaultDesc
+	if query.Sort != "" {
+		field, desc = query.Sort, false
+	}
+	if query.Order != "" {
+		desc = query.Order == "desc"
+	}
+	order, ok := fields.OrderBy(field, desc)
+	if !ok {
+		return req, fmt.Errorf("不支持的排序字段 %s，可选：%s", field, sortFieldNames(fields))
+	}
+	req.Order = order
+	return req, nil
+}
+
+func sortFieldNames(fields repository.SortFields) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, "/")
+}
+
+func pagination(req repository.PageRequest, total int64) dto.Pagination {
+	return dto.NewPagination(req.Page, req.PageSize, total)
+}
+
+// cursorRequest decodes the cursor of a feed page.
+func cursorRequest(query dto.CursorQuery) (repository.CursorRequest, error) {
+	req := repository.CursorRequest{Limit: query.PageSize}
+	if req.Limit <= 0 {
+		req.Limit = defaultPageSize
+	}
+	if query.Cursor == "" {
+		return req, nil
+	}
+	after, id, err := utils.DecodeCursor(query.Cursor)
+	if err != nil {
+		return req, err
+	}
+	req.After, req.AfterTime, req.AfterID = true, after, id
+	return req, nil
+}
+
+// feedPagination describes a feed page. When more rows follow, next_cursor points after the last
+// row of the page, identified by its creation time and ID.
+func feedPagination(req repository.CursorRequest, total int64, more bool, lastCreatedAt time.Time, lastID uint) dto.Pagination {
+	resp := dto.Pagination{PageSize: req.Limit, Total: total, HasMore: more}
+	if more {
+		resp.NextCursor = utils.EncodeCursor(lastCreatedAt, lastID)
+	}
+	return resp
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -12,6 +13,7 @@ import (
 
 const (
 	pointSourceContentCompletion = "content_completion"
+	pointSourceCheckpoint        = "checkpoint"
 	contentCompletionPoints      = 1
 )
 
@@ -19,36 +21,75 @@ const (
 type PointService struct {
 	repo  *repository.PointRepository
 	users *repository.UserRepository
+	audit *AuditService
 }
 
 // NewPointService creates a PointService.
-func NewPointService(pointRepo *repository.PointRepository, userRepo *repository.UserRepository) *PointService {
+func NewPointService(pointRepo *repository.PointRepository, userRepo *repository.UserRepository, audit *AuditService) *PointService {
 	return &PointService{
 		repo:  pointRepo,
 		users: userRepo,
+		audit: audit,
 	}
 }
 
 // AwardContentCompletion gives points when a user completes a content.
-func (s *PointService) AwardContentCompletion(userID uint, content *model.Content) error {
-	referenceID := fmt.Sprintf("content:%d", content.ID)
-	exists, err := s.repo.ExistsByReference(userID, referenceID, pointSourceContentCompletion)
-	if err != nil {
-		return err
-	}
-	if exists {
-		return nil
-	}
-
-	txn := &model.PointTransaction{
+func (s *PointService) AwardContentCompletion(ctx context.Context, userID uint, content *model.Content) error {
+	_, err := s.award(ctx, &model.PointTransaction{
 		UserID:      userID,
 		Change:      contentCompletionPoints,
 		Source:      pointSourceContentCompletion,
-		ReferenceID: referenceID,
+		ReferenceID: fmt.Sprintf("content:%d", content.ID),
 		ContentID:   &content.ID,
 		Description: fmt.Sprintf("完成学习内容《%s》", content.Title),
+	})
+	return err
+}
+
+// AwardCheckpoint gives the points of a checkpoint answered correctly at the first attempt. It
+// reports whether points were awarded, which is false when they already were.
+func (s *PointService) AwardCheckpoint(ctx context.Context, userID uint, checkpoint *model.ContentCheckpoint, content *model.Content) (bool, error) {
+	if checkpoint.Points <= 0 {
+		return false, nil
+	}
+	return s.award(ctx, &model.PointTransaction{
+		UserID:      userID,
+		Change:      int64(checkpoint.Points),
+		Source:      pointSourceCheckpoint,
+		ReferenceID: fmt.Sprintf("checkpoint:%d", checkpoint.ID),
+		ContentID:   &content.ID,
+		Description: fmt.Sprintf("答对《%s》的随堂测验", content.Title),
+	})
+}
+
+// award records a point transaction once per user, reference and source.
+func (s *PointService) award(ctx context.Context, txn *model.PointTransaction) (bool, error) {
+	exists, err := s.repo.ExistsByReference(txn.UserID, txn.ReferenceID, txn.Source)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
 	}
-	return s.repo.AddTransaction(txn)
+
+	total, err := s.repo.AddTransaction(txn)
+	if err != nil {
+		return false, err
+	}
+	_ = s.audit.RecordChange(ctx, txn.UserID, AuditChange{
+		Action:     "award_points",
+		Target:     "user_points",
+		EntityType: AuditEntityUserPoint,
+		EntityID:   txn.UserID,
+		Before:     map[string]interface{}{"total": total - txn.Change},
+		After:      map[string]interface{}{"total": total},
+		Payload: map[string]interface{}{
+			"source":       txn.Source,
+			"reference_id": txn.ReferenceID,
+			"change":       txn.Change,
+		},
+	})
+	return true, nil
 }
 
 // GetTotalsMap returns point totals for users.
@@ -97,11 +138,7 @@ func (s *PointService) AdminUserPointDetails(adminID, targetUserID uint, query d
 		},
 		TotalPoints:  total,
 		Transactions: make([]dto.PointTransactionResponse, 0, len(transactions)),
-		Pagination: dto.Pagination{
-			Page:     page,
-			PageSize: size,
-			Total:    totalCount,
-		},
+		Pagination:   dto.NewPagination(page, size, totalCount),
 	}
 
 	for _, txn := range transactions {
@@ -142,12 +179,8 @@ func (s *PointService) AdminListAllPoints(adminID uint, query dto.AdminListPoint
 	}
 
 	result := &dto.AdminListPointsResponse{
-		Items: make([]dto.UserPointListItem, 0, len(items)),
-		Pagination: dto.Pagination{
-			Page:     page,
-			PageSize: size,
-			Total:    total,
-		},
+		Items:      make([]dto.UserPointListItem, 0, len(items)),
+		Pagination: dto.NewPagination(page, size, total),
 	}
 
 	for _, item := range items {
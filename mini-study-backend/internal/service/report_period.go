@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	reportDefaultDays = 30
+	reportMaxRange    = 366 * 24 * time.Hour
+	reportDateLayout  = "2006-01-02"
+)
+
+// reportWindow is the inclusive date range of a report split into consecutive day, week
+// (Monday first) or month periods, in the server time zone.
+type reportWindow struct {
+	From     time.Time
+	To       time.Time // 包含的最后一天
+	End      time.Time // To 的次日零点，查询时作为开区间上界
+	Interval string
+	Periods  []time.Time
+	index    map[string]int
+}
+
+// newReportWindow resolves optional query dates: the range defaults to the last 30 days
+// ending today and may not exceed 366 days; the interval defaults to day.
+func newReportWindow(fromDate, toDate *time.Time, interval string) (*reportWindow, error) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if toDate != nil {
+		to = *toDate
+	}
+	from := to.AddDate(0, 0, -reportDefaultDays+1)
+	if fromDate != nil {
+		from = *fromDate
+	}
+	end := to.AddDate(0, 0, 1)
+	if !end.After(from) {
+		return nil, errors.New("结束日期不能早于开始日期")
+	}
+	if end.Sub(from) > reportMaxRange {
+		return nil, errors.New("查询范围不能超过 366 天")
+	}
+	if interval == "" {
+		interval = "day"
+	}
+
+	w := &reportWindow{From: from, To: to, End: end, Interval: interval, index: make(map[string]int)}
+	// 周期连续排列，没有数据的周期也保留，便于直接绘制趋势图
+	for start := periodStart(from, interval); start.Before(end); start = nextPeriod(start, interval) {
+		w.index[start.Format(reportDateLayout)] = len(w.Periods)
+		w.Periods = append(w.Periods, start)
+	}
+	return w, nil
+}
+
+// Period returns the index of the period containing t, or false when t is outside the window.
+func (w *reportWindow) Period(t time.Time) (int, bool) {
+	i, ok := w.index[periodStart(t.In(time.Local), w.Interval).Format(reportDateLayout)]
+	return i, ok
+}
+
+// Bounds returns the period boundaries clipped to the window: period i spans
+// [bounds[i], bounds[i+1]).
+func (w *reportWindow) Bounds() []time.Time {
+	bounds := make([]time.Time, 0, len(w.Periods)+1)
+	bounds = append(bounds, w.From)
+	bounds = append(bounds, w.Periods[1:]...)
+	return append(bounds, w.End)
+}
+
+// Labels formats the period starts as dates.
+func (w *reportWindow) Labels() []string {
+	labels := make([]string, len(w.Periods))
+	for i, p := range w.Periods {
+		labels[i] = p.Format(reportDateLayout)
+	}
+	return labels
+}
+
+// periodStart returns the local midnight starting the day, ISO week (Monday) or month of t.
+func periodStart(t time.Time, interval string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	switch interval {
+	case "week":
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case "month":
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+func nextPeriod(start time.Time, interval string) time.Time {
+	switch interval {
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "month":
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+)
+
+const (
+	rollupLeaseName        = "report_rollup"
+	rollupStateIncremental = "incremental"
+	rollupStateNightly     = "nightly"
+	rollupUserBatch        = 500
+	// rollupSlack 回看水位线之前的一小段时间，避免漏掉汇总开始时尚未提交的写入
+	rollupSlack = time.Minute
+)
+
+// RollupOptions tunes the reporting rollups.
+type RollupOptions struct {
+	// NightlyHour is the local hour after which the nightly rebuild runs once a day.
+	NightlyHour int
+	// InitialDays is how many days the first run rolls up when no watermark exists yet.
+	InitialDays int
+	LeaseTTL    time.Duration
+}
+
+// ReportRollupService maintains the reporting rollups read by the learning overviews: daily
+// per-user, per-content and per-exam stats, and the current per-user summaries.
+//
+// Every interval the holder of the rollup lease recomputes the days since the last watermark
+// and the summaries of the users whose records changed. Once a night it closes the previous
+// day again and rebuilds every summary, which also picks up deleted rows. Daily rows are
+// always recomputed for whole days, so refreshing a day twice is harmless.
+type ReportRollupService struct {
+	rollups  *repository.ReportRollupRepository
+	learning *repository.LearningRecordRepository
+	points   *repository.AnalyticsRepository
+	leases   *repository.SchedulerLeaseRepository
+	holder   string
+	opts     RollupOptions
+}
+
+// NewReportRollupService builds a ReportRollupService.
+func NewReportRollupService(
+	rollupRepo *repository.ReportRollupRepository,
+	learningRepo *repository.LearningRecordRepository,
+	analyticsRepo *repository.AnalyticsRepository,
+	leaseRepo *repository.SchedulerLeaseRepository,
+	opts RollupOptions,
+) *ReportRollupService {
+	host, _ := os.Hostname()
+	return &ReportRollupService{
+		rollups:  rollupRepo,
+		learning: learningRepo,
+		points:   analyticsRepo,
+		leases:   leaseRepo,
+		holder:   fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8]),
+		opts:     opts,
+	}
+}
+
+// RunRollups refreshes the rollups every interval until ctx is cancelled.
+func (s *ReportRollupService) RunRollups(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	run := func() {
+		start := time.Now()
+		ran, err := s.RunDue(start)
+		if err != nil {
+			logger.Error("refresh report rollups", zap.Error(err))
+			return
+		}
+		if ran {
+			logger.Debug("report rollups refreshed", zap.Duration("took", time.Since(start)))
+		}
+	}
+
+	run()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			_ = s.leases.Release(rollupLeaseName, s.holder)
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
+
+// RunDue runs the incremental refresh, and the nightly rebuild when it is due, if this
+// replica holds the rollup lease. It reports whether anything ran.
+func (s *ReportRollupService) RunDue(now time.Time) (bool, error) {
+	held, err := s.leases.Acquire(rollupLeaseName, s.holder, now, now.Add(s.opts.LeaseTTL))
+	if err != nil || !held {
+		return false, err
+	}
+	if err := s.Refresh(now); err != nil {
+		return true, err
+	}
+
+	today := dayStart(now)
+	last, err := s.rollups.State(rollupStateNightly)
+	if err != nil {
+		return true, err
+	}
+	if now.Hour() < s.opts.NightlyHour || !last.Before(today) {
+		return true, nil
+	}
+	if err := s.RollupDays(today.AddDate(0, 0, -1), today); err != nil {
+		return true, err
+	}
+	if err := s.RebuildSummaries(); err != nil {
+		return true, err
+	}
+	return true, s.rollups.SaveState(rollupStateNightly, today)
+}
+
+// Refresh recomputes the days since the last watermark up to now and the summaries of the
+// users changed since then. Without a watermark it rolls up the configured initial days and
+// builds every summary.
+func (s *ReportRollupService) Refresh(now time.Time) error {
+	watermark, err := s.rollups.State(rollupStateIncremental)
+	if err != nil {
+		return err
+	}
+
+	from := dayStart(now).AddDate(0, 0, -s.opts.InitialDays+1)
+	if watermark.IsZero() {
+		if err := s.RebuildSummaries(); err != nil {
+			return err
+		}
+	} else {
+		from = dayStart(watermark)
+		userIDs, err := s.rollups.ChangedUserIDs(watermark.Add(-rollupSlack))
+		if err != nil {
+			return err
+		}
+		for start := 0; start < len(userIDs); start += rollupUserBatch {
+			end := start + rollupUserBatch
+			if end > len(userIDs) {
+				end = len(userIDs)
+			}
+			if err := s.RefreshSummaries(userIDs[start:end]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.RollupDays(from, now); err != nil {
+		return err
+	}
+	return s.rollups.SaveState(rollupStateIncremental, now)
+}
+
+// Backfill recomputes the daily rollups of every day from from through to and rebuilds all
+// summaries. Learning updates are counted on the day a record was last touched, so days
+// rebuilt long after the fact only keep the records that were not touched again since.
+func (s *ReportRollupService) Backfill(from, to time.Time) (int, error) {
+	from, end := dayStart(from), dayStart(to).AddDate(0, 0, 1)
+	if !end.After(from) {
+		return 0, errors.New("结束日期不能早于开始日期")
+	}
+	days := 0
+	// 逐月汇总，避免一次性读取过多原始记录
+	for start := from; start.Before(end); start = start.AddDate(0, 1, 0) {
+		stop := start.AddDate(0, 1, 0)
+		if stop.After(end) {
+			stop = end
+		}
+		if err := s.RollupDays(start, stop); err != nil {
+			return days, err
+		}
+		days += int(stop.Sub(start).Hours()+12) / 24
+	}
+	return days, s.RebuildSummaries()
+}
+
+// RollupDays recomputes the daily rollups of every day touched by [from, to).
+func (s *ReportRollupService) RollupDays(from, to time.Time) error {
+	from, end := dayStart(from), dayStart(to)
+	if to.After(end) {
+		end = end.AddDate(0, 0, 1)
+	}
+	if !end.After(from) {
+		return nil
+	}
+
+	type userDay struct {
+		day  time.Time
+		user uint
+	}
+	type itemDay struct {
+		day time.Time
+		id  uint
+	}
+	users := make(map[userDay]*model.UserDailyStat)
+	contents := make(map[itemDay]*model.ContentDailyStat)
+	exams := make(map[itemDay]*model.ExamDailyStat)
+	var userOrder []userDay
+	var contentOrder, examOrder []itemDay
+
+	userStat := func(at time.Time, userID uint) *model.UserDailyStat {
+		key := userDay{dayStart(at), userID}
+		if stat, ok := users[key]; ok {
+			return stat
+		}
+		stat := &model.UserDailyStat{StatDate: key.day, UserID: userID}
+		users[key] = stat
+		userOrder = append(userOrder, key)
+		return stat
+	}
+	contentStat := func(at time.Time, contentID uint) *model.ContentDailyStat {
+		key := itemDay{dayStart(at), contentID}
+		if stat, ok := contents[key]; ok {
+			return stat
+		}
+		stat := &model.ContentDailyStat{StatDate: key.day, ContentID: contentID}
+		contents[key] = stat
+		contentOrder = append(contentOrder, key)
+		return stat
+	}
+	inRange := func(at time.Time) bool {
+		return !at.Before(from) && at.Before(end)
+	}
+
+	records, err := s.rollups.LearningActivity(from, end)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if inRange(record.UpdatedAt) {
+			userStat(record.UpdatedAt, record.UserID).LearningUpdates++
+			contentStat(record.UpdatedAt, record.ContentID).Learners++
+		}
+		if record.CompletedAt != nil && inRange(*record.CompletedAt) {
+			userStat(*record.CompletedAt, record.UserID).Completions++
+			contentStat(*record.CompletedAt, record.ContentID).Completions++
+		}
+	}
+
+	attempts, err := s.rollups.ExamSubmissions(from, end)
+	if err != nil {
+		return err
+	}
+	for _, attempt := range attempts {
+		at := *attempt.SubmittedAt
+		stat := userStat(at, attempt.UserID)
+		key := itemDay{dayStart(at), attempt.ExamID}
+		exam, ok := exams[key]
+		if !ok {
+			exam = &model.ExamDailyStat{StatDate: key.day, ExamID: attempt.ExamID}
+			exams[key] = exam
+			examOrder = append(examOrder, key)
+		}
+		stat.ExamAttempts++
+		stat.ExamScoreSum += int64(attempt.Score)
+		exam.Attempts++
+		exam.ScoreSum += int64(attempt.Score)
+		if attempt.Pass {
+			stat.ExamPassed++
+			exam.Passed++
+		}
+	}
+
+	points, err := s.points.PointsEarned(from, end)
+	if err != nil {
+		return err
+	}
+	for _, point := range points {
+		userStat(point.At, point.UserID).PointsEarned += point.Value
+	}
+
+	userRows := make([]model.UserDailyStat, 0, len(userOrder))
+	for _, key := range userOrder {
+		userRows = append(userRows, *users[key])
+	}
+	contentRows := make([]model.ContentDailyStat, 0, len(contentOrder))
+	for _, key := range contentOrder {
+		contentRows = append(contentRows, *contents[key])
+	}
+	examRows := make([]model.ExamDailyStat, 0, len(examOrder))
+	for _, key := range examOrder {
+		examRows = append(examRows, *exams[key])
+	}
+	return s.rollups.ReplaceDays(from, end, userRows, contentRows, examRows)
+}
+
+// RefreshSummaries rebuilds the summaries of the given users from their learning records and
+// exam attempts.
+func (s *ReportRollupService) RefreshSummaries(userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	learning, err := s.learning.AggregateByUsers(userIDs)
+	if err != nil {
+		return err
+	}
+	attempts, err := s.rollups.ExamAttemptsByUsers(userIDs)
+	if err != nil {
+		return err
+	}
+
+	summaries := make(map[uint]*model.UserReportSummary, len(userIDs))
+	summary := func(userID uint) *model.UserReportSummary {
+		if item, ok := summaries[userID]; ok {
+			return item
+		}
+		item := &model.UserReportSummary{UserID: userID}
+		summaries[userID] = item
+		return item
+	}
+	for userID, agg := range learning {
+		item := summary(userID)
+		item.Completed = agg.Completed
+		item.Learning = agg.Total
+	}
+
+	type userExam struct {
+		user uint
+		exam uint
+	}
+	exams := make(map[userExam]*model.UserExamSummary)
+	var examOrder []userExam
+	for _, attempt := range attempts {
+		// 记录按时间倒序返回，第一条即为最近一次考试
+		item := summary(attempt.UserID)
+		if item.LatestExamID == 0 {
+			submitted := attempt.CreatedAt
+			if attempt.SubmittedAt != nil {
+				submitted = *attempt.SubmittedAt
+			}
+			item.LatestExamID = attempt.ExamID
+			item.LatestExamTitle = attempt.ExamTitle
+			item.LatestScore = attempt.Score
+			item.LatestPass = attempt.Pass
+			item.LatestSubmittedAt = &submitted
+		}
+
+		key := userExam{attempt.UserID, attempt.ExamID}
+		exam, ok := exams[key]
+		if !ok {
+			exam = &model.UserExamSummary{UserID: attempt.UserID, ExamID: attempt.ExamID, ExamTitle: attempt.ExamTitle}
+			exams[key] = exam
+			examOrder = append(examOrder, key)
+		}
+		exam.Attempts++
+		exam.ScoreSum += int64(attempt.Score)
+		if attempt.Pass {
+			exam.Passed++
+		}
+	}
+
+	summaryRows := make([]model.UserReportSummary, 0, len(summaries))
+	for _, userID := range userIDs {
+		if item, ok := summaries[userID]; ok {
+			summaryRows = append(summaryRows, *item)
+		}
+	}
+	examRows := make([]model.UserExamSummary, 0, len(examOrder))
+	for _, key := range examOrder {
+		examRows = append(examRows, *exams[key])
+	}
+	return s.rollups.ReplaceSummaries(userIDs, summaryRows, examRows)
+}
+
+// RebuildSummaries rebuilds the summaries of every user in batches.
+func (s *ReportRollupService) RebuildSummaries() error {
+	var after uint
+	for {
+		userIDs, err := s.rollups.ListUserIDs(after, rollupUserBatch)
+		if err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+		if err := s.RefreshSummaries(userIDs); err != nil {
+			return err
+		}
+		after = userIDs[len(userIDs)-1]
+	}
+}
+
+// rollupRefreshedAt returns when the rollups were last refreshed, or nil before the first refresh.
+func rollupRefreshedAt(rollups *repository.ReportRollupRepository) (*time.Time, error) {
+	at, err := rollups.State(rollupStateIncremental)
+	if err != nil || at.IsZero() {
+		return nil, err
+	}
+	return &at, nil
+}
+
+func dayStart(t time.Time) time.Time {
+	return periodStart(t.In(time.Local), "day")
+}
@@ -0,0 +1,601 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/mailer"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/report"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+)
+
+// Report kinds.
+const (
+	ReportTeamLearning  = "team_learning"
+	ReportExamResults   = "exam_results"
+	ReportPointsRanking = "points_ranking"
+)
+
+// Delivery results recorded on a schedule.
+const (
+	ReportDeliverySent   = "sent"
+	ReportDeliveryFailed = "failed"
+)
+
+const (
+	reportLeaseName     = "report_delivery"
+	reportDeliveryBatch = 50
+	reportMaxRows       = 20000
+	// 定时发送失败后依次在 5、10、20、40、80 分钟后重试
+	reportRetryDelay = 5 * time.Minute
+	reportMaxRetries = 5
+)
+
+var reportDefinitions = []dto.ReportDefinition{
+	{
+		Key:         ReportTeamLearning,
+		Name:        "团队学习汇总",
+		Description: "每位成员的学习完成情况与最近一次考试成绩",
+		Columns:     []string{"工号", "姓名", "角色", "已完成内容", "内容总数", "完成率(%)", "最近考试", "最近成绩", "是否通过", "最近考试时间"},
+	},
+	{
+		Key:         ReportExamResults,
+		Name:        "考试成绩汇总",
+		Description: "团队在每份试卷上的提交次数、通过率与平均分",
+		Columns:     []string{"试卷ID", "试卷", "提交次数", "通过率(%)", "平均分"},
+	},
+	{
+		Key:         ReportPointsRanking,
+		Name:        "积分排行",
+		Description: "按积分从高到低排列的团队成员",
+		Columns:     []string{"排名", "工号", "姓名", "角色", "积分"},
+	},
+}
+
+var reportRoleLabels = map[model.Role]string{
+	model.RoleEmployee: "员工",
+	model.RoleManager:  "店长",
+	model.RoleAdmin:    "管理员",
+}
+
+// ReportService exports team reports as XLSX or CSV and emails them on schedule. Reports are
+// built from the learning overviews, so they show the same figures as the dashboards.
+//
+// Every replica runs the delivery loop, but only the holder of a database lease sends mail, and
+// each schedule is claimed with a conditional update before sending so it is never sent twice.
+type ReportService struct {
+	schedules *repository.ReportScheduleRepository
+	users     *repository.UserRepository
+	exams     *ExamService
+	points    *PointService
+	leases    *repository.SchedulerLeaseRepository
+	mailer    mailer.Mailer
+	holder    string
+	leaseTTL  time.Duration
+}
+
+// NewReportService builds a ReportService. mail may be nil when no mail server is configured;
+// exports still work but schedules cannot be created or sent.
+func NewReportService(
+	scheduleRepo *repository.ReportScheduleRepository,
+	userRepo *repository.UserRepository,
+	exams *ExamService,
+	points *PointService,
+	leaseRepo *repository.SchedulerLeaseRepository,
+	mail mailer.Mailer,
+	leaseTTL time.Duration,
+) *ReportService {
+	host, _ := os.Hostname()
+	return &ReportService{
+		schedules: scheduleRepo,
+		users:     userRepo,
+		exams:     exams,
+		points:    points,
+		leases:    leaseRepo,
+		mailer:    mail,
+		holder:    fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8]),
+		leaseTTL:  leaseTTL,
+	}
+}
+
+// Definitions lists the available reports.
+func (s *ReportService) Definitions() []dto.ReportDefinition {
+	return reportDefinitions
+}
+
+// ExportFilename returns the download name of a report, or an error for an unknown report.
+func (s *ReportService) ExportFilename(kind, format string, now time.Time) (string, error) {
+	if _, ok := reportDefinition(kind); !ok {
+		return "", errors.New("报表不存在")
+	}
+	return fmt.Sprintf("%s-%s.%s", kind, now.Format("20060102"), reportFormat(format)), nil
+}
+
+// Export writes a report for the user's team to w. Nothing is written when the user may not
+// export reports or the report is unknown.
+func (s *ReportService) Export(userID uint, kind string, query dto.ReportExportQuery, w io.Writer) error {
+	user, err := s.reportUser(userID)
+	if err != nil {
+		return err
+	}
+	table, _, err := s.build(user, kind, query.ManagerID)
+	if err != nil {
+		return err
+	}
+	return report.Write(w, reportFormat(query.Format), table)
+}
+
+// ListSchedules returns the user's schedules; admins see every schedule.
+func (s *ReportService) ListSchedules(userID uint) (*dto.ReportScheduleListResponse, error) {
+	user, err := s.reportUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	ownerID := user.ID
+	if user.Role == model.RoleAdmin {
+		ownerID = 0
+	}
+	schedules, err := s.schedules.List(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.toResponses(schedules)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ReportScheduleListResponse{Items: items}, nil
+}
+
+// CreateSchedule adds a delivery schedule owned by the user.
+func (s *ReportService) CreateSchedule(userID uint, req dto.ReportScheduleRequest) (*dto.ReportScheduleResponse, error) {
+	user, err := s.reportUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if s.mailer == nil {
+		return nil, errors.New("未配置邮件服务，无法定时发送报表")
+	}
+	schedule := &model.ReportSchedule{OwnerID: user.ID}
+	if err := s.applySchedule(user, schedule, req, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.schedules.Create(schedule); err != nil {
+		return nil, err
+	}
+	return s.toResponse(schedule)
+}
+
+// UpdateSchedule replaces a schedule of the user, or any schedule for admins.
+func (s *ReportService) UpdateSchedule(userID, scheduleID uint, req dto.ReportScheduleRequest) (*dto.ReportScheduleResponse, error) {
+	user, schedule, err := s.ownedSchedule(userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	// 管理员修改他人的计划时，报表范围仍按创建人的角色确定
+	owner := user
+	if schedule.OwnerID != user.ID {
+		if owner, err = s.users.FindByID(schedule.OwnerID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.applySchedule(owner, schedule, req, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.schedules.Update(schedule); err != nil {
+		return nil, err
+	}
+	return s.toResponse(schedule)
+}
+
+// DeleteSchedule removes a schedule of the user, or any schedule for admins.
+func (s *ReportService) DeleteSchedule(userID, scheduleID uint) error {
+	if _, _, err := s.ownedSchedule(userID, scheduleID); err != nil {
+		return err
+	}
+	return s.schedules.Delete(scheduleID)
+}
+
+// SendNow delivers a schedule immediately, e.g. to check the recipient address, without moving
+// its next run.
+func (s *ReportService) SendNow(ctx context.Context, userID, scheduleID uint) (*dto.ReportScheduleResponse, error) {
+	_, schedule, err := s.ownedSchedule(userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if s.mailer == nil {
+		return nil, errors.New("未配置邮件服务，无法发送报表")
+	}
+	now := time.Now()
+	if err := s.deliver(ctx, schedule, now); err != nil {
+		return nil, err
+	}
+	if schedule, err = s.schedules.FindByID(scheduleID); err != nil {
+		return nil, err
+	}
+	return s.toResponse(schedule)
+}
+
+// RunDeliveries sends due reports every interval until ctx is cancelled.
+func (s *ReportService) RunDeliveries(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	run := func() {
+		sent, err := s.DeliverDue(ctx, time.Now())
+		if err != nil {
+			logger.Error("deliver scheduled reports", zap.Error(err))
+		}
+		if sent > 0 {
+			logger.Info("scheduled reports delivered", zap.Int("count", sent))
+		}
+	}
+
+	run()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			_ = s.leases.Release(reportLeaseName, s.holder)
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
+
+// DeliverDue sends every report due at now if this replica holds the delivery lease, and
+// returns how many were sent. A schedule that was missed, e.g. while the service was down,
+// is sent once and then resumes its regular times. A failed delivery is retried with backoff.
+func (s *ReportService) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	held, err := s.leases.Acquire(reportLeaseName, s.holder, now, now.Add(s.leaseTTL))
+	if err != nil || !held {
+		return 0, err
+	}
+
+	schedules, err := s.schedules.ListDue(now, reportDeliveryBatch)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	var errs []error
+	for i := range schedules {
+		schedule := &schedules[i]
+		next := report.NextRun(reportSchedule(schedule), now)
+		claimed, err := s.schedules.Claim(schedule.ID, *schedule.NextRunAt, next)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if err := s.deliver(ctx, schedule, now); err != nil {
+			errs = append(errs, fmt.Errorf("report schedule %d: %w", schedule.ID, err))
+			if err := s.retryLater(schedule, now, next); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if schedule.FailedAttempts > 0 {
+			if err := s.schedules.Reschedule(schedule.ID, next, next, 0); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+// retryLater brings a failed delivery forward from the regular next run, waiting twice as long
+// after each consecutive failure. Once the retries are used up, or the regular run comes first,
+// the schedule waits for its regular run with a fresh retry budget.
+func (s *ReportService) retryLater(schedule *model.ReportSchedule, now, next time.Time) error {
+	failures := schedule.FailedAttempts + 1
+	retryAt := now.Add(reportRetryDelay << (failures - 1))
+	if failures > reportMaxRetries || !retryAt.Before(next) {
+		return s.schedules.Reschedule(schedule.ID, next, next, 0)
+	}
+	return s.schedules.Reschedule(schedule.ID, next, retryAt, failures)
+}
+
+// deliver builds the report as its owner and mails it, recording the result on the schedule.
+func (s *ReportService) deliver(ctx context.Context, schedule *model.ReportSchedule, now time.Time) error {
+	err := s.send(ctx, schedule, now)
+	status, message := ReportDeliverySent, ""
+	if err != nil {
+		status, message = ReportDeliveryFailed, truncateUTF8(err.Error(), 500)
+	}
+	if saveErr := s.schedules.SaveResult(schedule.ID, now, status, message); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	return err
+}
+
+func (s *ReportService) send(ctx context.Context, schedule *model.ReportSchedule, now time.Time) error {
+	owner, err := s.reportUser(schedule.OwnerID)
+	if err != nil {
+		return err
+	}
+	table, refreshedAt, err := s.build(owner, schedule.Report, schedule.ManagerID)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := report.Write(&buf, schedule.Format, table); err != nil {
+		return err
+	}
+
+	definition, _ := reportDefinition(schedule.Report)
+	date := now.Format(reportDateLayout)
+	body := fmt.Sprintf("您好：\n\n附件为%s（%s），共 %d 行。\n", definition.Name, date, len(table.Rows))
+	if refreshedAt != nil {
+		body += fmt.Sprintf("数据统计截至 %s。\n", refreshedAt.In(time.Local).Format("2006-01-02 15:04"))
+	}
+	body += "\n此邮件由系统根据报表发送计划自动发送，如需停止请在报表计划中关闭。\n"
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      []string{schedule.Recipient},
+		Subject: fmt.Sprintf("%s %s", definition.Name, date),
+		Body:    body,
+		Attachments: []mailer.Attachment{{
+			Filename:    fmt.Sprintf("%s-%s.%s", definition.Name, now.Format("20060102"), schedule.Format),
+			ContentType: report.ContentType(schedule.Format),
+			Data:        buf.Bytes(),
+		}},
+	})
+}
+
+// reportMember is a row of the team behind a report.
+type reportMember struct {
+	ID       uint
+	WorkNo   string
+	Name     string
+	Role     model.Role
+	Progress dto.EmployeeLearningProgress
+	Latest   *dto.EmployeeLatestExamResult
+}
+
+// build renders a report for the user's team: a manager's employees, or for admins the team of
+// managerID (the manager included) or every user.
+func (s *ReportService) build(user *model.User, kind string, managerID uint) (*report.Table, *time.Time, error) {
+	definition, ok := reportDefinition(kind)
+	if !ok {
+		return nil, nil, errors.New("报表不存在")
+	}
+
+	var members []reportMember
+	var exams []dto.ManagerExamProgressItem
+	var refreshedAt *time.Time
+	if user.Role == model.RoleAdmin {
+		overview, err := s.exams.GetAdminOverview(user.ID, dto.AdminExamOverviewQuery{ManagerID: managerID, Page: 1, PageSize: reportMaxRows})
+		if err != nil {
+			return nil, nil, err
+		}
+		if overview.Pagination.Total > reportMaxRows {
+			return nil, nil, fmt.Errorf("报表超过 %d 行，请按店长筛选", reportMaxRows)
+		}
+		for _, u := range overview.Users {
+			members = append(members, reportMember{ID: u.UserID, WorkNo: u.WorkNo, Name: u.Name, Role: model.Role(u.Role), Progress: u.LearningProgress, Latest: u.LatestExam})
+		}
+		exams, refreshedAt = overview.ExamProgress, overview.RefreshedAt
+	} else {
+		overview, err := s.exams.GetManagerOverview(user.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, e := range overview.Employees {
+			members = append(members, reportMember{ID: e.EmployeeID, WorkNo: e.WorkNo, Name: e.Name, Role: model.RoleEmployee, Progress: e.LearningProgress, Latest: e.LatestExam})
+		}
+		exams, refreshedAt = overview.ExamProgress, overview.RefreshedAt
+	}
+
+	table := &report.Table{Title: definition.Name, Columns: definition.Columns}
+	switch kind {
+	case ReportTeamLearning:
+		for _, m := range members {
+			var examTitle, pass, submittedAt interface{}
+			var score interface{}
+			if m.Latest != nil {
+				examTitle, score, submittedAt = m.Latest.ExamTitle, m.Latest.Score, m.Latest.SubmittedAt
+				pass = "未通过"
+				if m.Latest.Pass {
+					pass = "通过"
+				}
+			}
+			table.AddRow(m.WorkNo, m.Name, reportRoleLabels[m.Role], m.Progress.Completed, m.Progress.Total, m.Progress.Percent, examTitle, score, pass, submittedAt)
+		}
+	case ReportExamResults:
+		for _, exam := range exams {
+			table.AddRow(exam.ExamID, exam.Title, exam.AttemptCount, math.Round(exam.PassRate*1000)/10, exam.AvgScore)
+		}
+	case ReportPointsRanking:
+		ids := make([]uint, 0, len(members))
+		for _, m := range members {
+			ids = append(ids, m.ID)
+		}
+		totals, err := s.points.GetTotalsMap(ids)
+		if err != nil {
+			return nil, nil, err
+		}
+		sort.SliceStable(members, func(i, j int) bool {
+			return totals[members[i].ID] > totals[members[j].ID]
+		})
+		rank := 0
+		for i, m := range members {
+			// 积分相同的成员名次相同，下一名次顺延
+			if i == 0 || totals[m.ID] != totals[members[i-1].ID] {
+				rank = i + 1
+			}
+			table.AddRow(rank, m.WorkNo, m.Name, reportRoleLabels[m.Role], totals[m.ID])
+		}
+	}
+	return table, refreshedAt, nil
+}
+
+// applySchedule validates req and copies it onto schedule, computing the next run after now.
+// Managers always report on their own team, so their manager_id is ignored.
+func (s *ReportService) applySchedule(owner *model.User, schedule *model.ReportSchedule, req dto.ReportScheduleRequest, now time.Time) error {
+	managerID := req.ManagerID
+	if owner.Role != model.RoleAdmin {
+		managerID = 0
+	} else if managerID > 0 {
+		manager, err := s.users.FindByID(managerID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("店长不存在")
+			}
+			return err
+		}
+		if manager.Role != model.RoleManager {
+			return errors.New("manager_id 必须是店长")
+		}
+	}
+
+	schedule.Report = req.Report
+	schedule.Format = reportFormat(req.Format)
+	schedule.Recipient = req.Recipient
+	schedule.Frequency = req.Frequency
+	schedule.Weekday = req.Weekday
+	if schedule.Weekday == 0 {
+		schedule.Weekday = 1
+	}
+	schedule.MonthDay = req.MonthDay
+	if schedule.MonthDay == 0 {
+		schedule.MonthDay = 1
+	}
+	schedule.Hour = req.Hour
+	schedule.ManagerID = managerID
+	schedule.Enabled = req.Enabled == nil || *req.Enabled
+
+	next := report.NextRun(reportSchedule(schedule), now)
+	schedule.NextRunAt = &next
+	schedule.FailedAttempts = 0
+	return nil
+}
+
+// ownedSchedule loads a schedule the user may manage: their own, or any for admins.
+func (s *ReportService) ownedSchedule(userID, scheduleID uint) (*model.User, *model.ReportSchedule, error) {
+	user, err := s.reportUser(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	schedule, err := s.schedules.FindByID(scheduleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("报表计划不存在")
+		}
+		return nil, nil, err
+	}
+	if schedule.OwnerID != user.ID && user.Role != model.RoleAdmin {
+		return nil, nil, errors.New("报表计划不存在")
+	}
+	return user, schedule, nil
+}
+
+// reportUser loads a user allowed to export reports: managers and admins.
+func (s *ReportService) reportUser(userID uint) (*model.User, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("用户不存在")
+		}
+		return nil, err
+	}
+	if user.Role != model.RoleManager && user.Role != model.RoleAdmin {
+		return nil, errors.New("仅店长或管理员可使用报表")
+	}
+	if !user.Status {
+		return nil, errors.New("账号已停用")
+	}
+	return user, nil
+}
+
+func (s *ReportService) toResponses(schedules []model.ReportSchedule) ([]dto.ReportScheduleResponse, error) {
+	managerIDs := make([]uint, 0)
+	for _, schedule := range schedules {
+		if schedule.ManagerID > 0 {
+			managerIDs = append(managerIDs, schedule.ManagerID)
+		}
+	}
+	names := make(map[uint]string)
+	if len(managerIDs) > 0 {
+		managers, err := s.users.FindByIDs(managerIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, manager := range managers {
+			names[manager.ID] = manager.Name
+		}
+	}
+
+	items := make([]dto.ReportScheduleResponse, 0, len(schedules))
+	for _, schedule := range schedules {
+		definition, _ := reportDefinition(schedule.Report)
+		items = append(items, dto.ReportScheduleResponse{
+			ID:             schedule.ID,
+			OwnerID:        schedule.OwnerID,
+			Report:         schedule.Report,
+			ReportName:     definition.Name,
+			Format:         schedule.Format,
+			Recipient:      schedule.Recipient,
+			Frequency:      schedule.Frequency,
+			Weekday:        schedule.Weekday,
+			MonthDay:       schedule.MonthDay,
+			Hour:           schedule.Hour,
+			ManagerID:      schedule.ManagerID,
+			ManagerName:    names[schedule.ManagerID],
+			Enabled:        schedule.Enabled,
+			NextRunAt:      schedule.NextRunAt,
+			LastRunAt:      schedule.LastRunAt,
+			LastStatus:     schedule.LastStatus,
+			LastError:      schedule.LastError,
+			FailedAttempts: schedule.FailedAttempts,
+			CreatedAt:      schedule.CreatedAt,
+		})
+	}
+	return items, nil
+}
+
+func (s *ReportService) toResponse(schedule *model.ReportSchedule) (*dto.ReportScheduleResponse, error) {
+	items, err := s.toResponses([]model.ReportSchedule{*schedule})
+	if err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+func reportDefinition(kind string) (dto.ReportDefinition, bool) {
+	for _, definition := range reportDefinitions {
+		if definition.Key == kind {
+			return definition, true
+		}
+	}
+	return dto.ReportDefinition{}, false
+}
+
+func reportFormat(format string) string {
+	if format == report.FormatCSV {
+		return report.FormatCSV
+	}
+	return report.FormatXLSX
+}
+
+func reportSchedule(schedule *model.ReportSchedule) report.Schedule {
+	return report.Schedule{
+		Frequency: schedule.Frequency,
+		Weekday:   schedule.Weekday,
+		MonthDay:  schedule.MonthDay,
+		Hour:      schedule.Hour,
+	}
+}
@@ -0,0 +1,584 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+// editorialBookkeepingFields change with publication rather than with the material itself, so
+// they do not invalidate an approval.
+var editorialBookkeepingFields = map[string]struct{}{
+	"status":               {},
+	"publish_at":           {},
+	"scheduled_publish_at": {},
+	"scheduled_offline_at": {},
+}
+
+// ReviewService runs the editorial workflow of contents and exams:
+// draft → in_review → approved → published, with rejection back to draft. It follows the
+// pending/approved/rejected moderation of growth posts, adding reviewer assignment, comments
+// and a transition history.
+type ReviewService struct {
+	events   *repository.ReviewEventRepository
+	contents *repository.ContentRepository
+	exams    *repository.ExamRepository
+	users    *repository.UserRepository
+	audit    *AuditService
+}
+
+// NewReviewService builds a ReviewService.
+func NewReviewService(
+	events *repository.ReviewEventRepository,
+	contents *repository.ContentRepository,
+	exams *repository.ExamRepository,
+	users *repository.UserRepository,
+	audit *AuditService,
+) *ReviewService {
+	return &ReviewService{events: events, contents: contents, exams: exams, users: users, audit: audit}
+}
+
+// reviewTarget is the reviewable part of a content or an exam.
+type reviewTarget struct {
+	entityType string
+	id         uint
+	title      string
+	status     string
+	state      model.ReviewState
+	updatedAt  time.Time
+}
+
+// Submit sends a draft for review, optionally assigning a reviewer.
+func (s *ReviewService) Submit(ctx context.Context, adminID uint, entityType string, entityID uint, req dto.ReviewSubmitRequest) (*dto.ReviewDetailResponse, error) {
+	target, err := s.prepare(adminID, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if target.status != model.EditorialDraft {
+		return nil, errors.New("仅草稿可以提交审核")
+	}
+	reviewerID := target.state.ReviewerID
+	if req.ReviewerID > 0 {
+		if err := s.ensureReviewer(req.ReviewerID); err != nil {
+			return nil, err
+		}
+		reviewerID = req.ReviewerID
+	}
+	if reviewerID == adminID {
+		return nil, errors.New("不能指定自己为审核人")
+	}
+
+	now := time.Now()
+	return s.transition(ctx, adminID, target, model.ReviewActionSubmit, model.EditorialInReview, req.Comment, map[string]interface{}{
+		"reviewer_id":   reviewerID,
+		"submitted_by":  adminID,
+		"submitted_at":  now,
+		"reviewed_by":   0,
+		"reviewed_at":   nil,
+		"reject_reason": "",
+	})
+}
+
+// Withdraw takes a submission back to draft. Only the submitter may withdraw.
+func (s *ReviewService) Withdraw(ctx context.Context, adminID uint, entityType string, entityID uint) (*dto.ReviewDetailResponse, error) {
+	target, err := s.prepare(adminID, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if target.status != model.EditorialInReview {
+		return nil, errors.New("当前不在审核中")
+	}
+	if target.state.SubmittedBy != adminID {
+		return nil, errors.New("仅提交人可以撤回")
+	}
+	return s.transition(ctx, adminID, target, model.ReviewActionWithdraw, model.EditorialDraft, "", map[string]interface{}{})
+}
+
+// AssignReviewer assigns the reviewer of a draft or a pending submission.
+func (s *ReviewService) AssignReviewer(ctx context.Context, adminID uint, entityType string, entityID uint, req dto.ReviewAssignRequest) (*dto.ReviewDetailResponse, error) {
+	target, err := s.prepare(adminID, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if target.status != model.EditorialDraft && target.status != model.EditorialInReview {
+		return nil, errors.New("仅草稿或审核中的条目可以指派审核人")
+	}
+	if err := s.ensureReviewer(req.ReviewerID); err != nil {
+		return nil, err
+	}
+	if target.status == model.EditorialInReview && req.ReviewerID == target.state.SubmittedBy {
+		return nil, errors.New("审核人不能是提交人")
+	}
+	return s.transition(ctx, adminID, target, model.ReviewActionAssign, target.status, "", map[string]interface{}{
+		"reviewer_id": req.ReviewerID,
+	})
+}
+
+// Approve accepts a submission. When a reviewer is assigned only that reviewer may decide.
+func (s *ReviewService) Approve(ctx context.Context, adminID uint, entityType string, entityID uint, req dto.ReviewApproveRequest) (*dto.ReviewDetailResponse, error) {
+	target, err := s.prepareDecision(adminID, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, adminID, target, model.ReviewActionApprove, model.EditorialApproved, req.Comment, map[string]interface{}{
+		"reviewed_by":   adminID,
+		"reviewed_at":   time.Now(),
+		"reject_reason": "",
+	})
+}
+
+// Reject sends a submission back to draft with a reason.
+func (s *ReviewService) Reject(ctx context.Context, adminID uint, entityType string, entityID uint, req dto.ReviewRejectRequest) (*dto.ReviewDetailResponse, error) {
+	target, err := s.prepareDecision(adminID, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, adminID, target, model.ReviewActionReject, model.EditorialDraft, req.Reason, map[string]interface{}{
+		"reviewed_by":   adminID,
+		"reviewed_at":   time.Now(),
+		"reject_reason": req.Reason,
+	})
+}
+
+// Comment adds a review comment without changing the status.
+func (s *ReviewService) Comment(ctx context.Context, adminID uint, entityType string, entityID uint, req dto.ReviewCommentRequest) (*dto.ReviewDetailResponse, error) {
+	target, err := s.prepare(adminID, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordTransition(entityType, entityID, model.ReviewActionComment, target.status, target.status, adminID, target.state.ReviewerID, req.Comment); err != nil {
+		return nil, err
+	}
+	return s.detail(target)
+}
+
+// Detail returns the review state of an entity with its full history.
+func (s *ReviewService) Detail(adminID uint, entityType string, entityID uint) (*dto.ReviewDetailResponse, error) {
+	target, err := s.prepare(adminID, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	return s.detail(target)
+}
+
+// Queue lists a page of contents and exams in a review status, oldest submission first by
+// default.
+func (s *ReviewService) Queue(adminID uint, query dto.ReviewQueueQuery) ([]dto.ReviewItemResponse, dto.Pagination, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	page, err := pageRequest(query.PageQuery, repository.ReviewQueueSortFields, "submitted_at", false)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	status := query.Status
+	if status == "" {
+		status = model.EditorialInReview
+	}
+	reviewerID := query.ReviewerID
+	if query.Mine {
+		reviewerID = adminID
+	}
+
+	entries, total, err := s.events.SearchQueue(query.EntityType, status, reviewerID, page)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	targets, err := s.queueTargets(entries)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+
+	userIDs := make([]uint, 0, len(targets)*2)
+	for _, target := range targets {
+		userIDs = append(userIDs, target.state.ReviewerID, target.state.SubmittedBy)
+	}
+	names, err := s.userNames(userIDs)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	items := make([]dto.ReviewItemResponse, 0, len(targets))
+	for i := range targets {
+		items = append(items, reviewItem(&targets[i], names))
+	}
+	return items, pagination(page, total), nil
+}
+
+// queueTargets loads the contents and exams of a queue page, keeping the order of the page.
+func (s *ReviewService) queueTargets(entries []repository.ReviewQueueEntry) ([]reviewTarget, error) {
+	var contentIDs, examIDs []uint
+	for _, entry := range entries {
+		if entry.EntityType == AuditEntityContent {
+			contentIDs = append(contentIDs, entry.EntityID)
+		} else {
+			examIDs = append(examIDs, entry.EntityID)
+		}
+	}
+	byKey := make(map[string]reviewTarget, len(entries))
+	contents, err := s.contents.FindByIDs(contentIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range contents {
+		byKey[AuditEntityContent+":"+strconv.FormatUint(uint64(contents[i].ID), 10)] = contentReviewTarget(&contents[i])
+	}
+	exams, err := s.exams.FindByIDs(examIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range exams {
+		byKey[AuditEntityExam+":"+strconv.FormatUint(uint64(exams[i].ID), 10)] = examReviewTarget(&exams[i])
+	}
+
+	targets := make([]reviewTarget, 0, len(entries))
+	for _, entry := range entries {
+		if target, ok := byKey[entry.EntityType+":"+strconv.FormatUint(uint64(entry.EntityID), 10)]; ok {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+// recordTransition appends a history entry. Other services call it for status changes made
+// outside the review actions, such as manual or scheduled publishing.
+func (s *ReviewService) recordTransition(entityType string, entityID uint, action, from, to string, actorID, reviewerID uint, comment string) error {
+	return s.events.Create(&model.ReviewEvent{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorID:    actorID,
+		ReviewerID: reviewerID,
+		Comment:    comment,
+	})
+}
+
+// transition moves target to status with the given review fields, guarded by its current
+// status so concurrent decisions cannot both succeed.
+func (s *ReviewService) transition(ctx context.Context, adminID uint, target *reviewTarget, action, status, comment string, fields map[string]interface{}) (*dto.ReviewDetailResponse, error) {
+	before := reviewAuditSnapshot(target)
+	fields["status"] = status
+
+	var ok bool
+	var err error
+	switch target.entityType {
+	case AuditEntityContent:
+		ok, err = s.contents.UpdateIfStatus(target.id, target.status, fields)
+	default:
+		ok, err = s.exams.UpdateIfStatus(target.id, target.status, fields)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("状态已变更，请刷新后重试")
+	}
+
+	updated, err := s.load(target.entityType, target.id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordTransition(target.entityType, target.id, action, target.status, updated.status, adminID, updated.state.ReviewerID, comment); err != nil {
+		return nil, err
+	}
+	auditTarget := "contents"
+	if target.entityType == AuditEntityExam {
+		auditTarget = "exam_papers"
+	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     action + "_review",
+		Target:     auditTarget,
+		EntityType: target.entityType,
+		EntityID:   target.id,
+		Before:     before,
+		After:      reviewAuditSnapshot(updated),
+	})
+	return s.detail(updated)
+}
+
+// prepare checks the admin and loads the target.
+func (s *ReviewService) prepare(adminID uint, entityType string, entityID uint) (*reviewTarget, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	return s.load(entityType, entityID)
+}
+
+// prepareDecision loads a submission that adminID may approve or reject. Submitters never
+// decide on their own submissions.
+func (s *ReviewService) prepareDecision(adminID uint, entityType string, entityID uint) (*reviewTarget, error) {
+	target, err := s.prepare(adminID, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if target.status != model.EditorialInReview {
+		return nil, errors.New("当前不在审核中")
+	}
+	if target.state.SubmittedBy == adminID {
+		return nil, errors.New("不能审核自己提交的条目")
+	}
+	if target.state.ReviewerID > 0 && target.state.ReviewerID != adminID {
+		return nil, errors.New("仅指定的审核人可以审核")
+	}
+	return target, nil
+}
+
+func (s *ReviewService) load(entityType string, entityID uint) (*reviewTarget, error) {
+	switch entityType {
+	case AuditEntityContent:
+		content, err := s.contents.FindByID(entityID)
+		if err != nil {
+			return nil, err
+		}
+		target := contentReviewTarget(content)
+		return &target, nil
+	case AuditEntityExam:
+		exam, err := s.exams.FindByID(entityID)
+		if err != nil {
+			return nil, err
+		}
+		target := examReviewTarget(exam)
+		return &target, nil
+	default:
+		return nil, errors.New("不支持的审核类型")
+	}
+}
+
+func (s *ReviewService) detail(target *reviewTarget) (*dto.ReviewDetailResponse, error) {
+	events, err := s.events.ListByEntity(target.entityType, target.id)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := []uint{target.state.ReviewerID, target.state.SubmittedBy}
+	for _, event := range events {
+		userIDs = append(userIDs, event.ActorID)
+	}
+	names, err := s.userNames(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ReviewDetailResponse{
+		ReviewItemResponse: reviewItem(target, names),
+		History:            make([]dto.ReviewEventResponse, 0, len(events)),
+	}
+	for _, event := range events {
+		resp.History = append(resp.History, dto.ReviewEventResponse{
+			ID:         event.ID,
+			Action:     event.Action,
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			ActorID:    event.ActorID,
+			ActorName:  names[event.ActorID],
+			ReviewerID: event.ReviewerID,
+			Comment:    event.Comment,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+func (s *ReviewService) userNames(ids []uint) (map[uint]string, error) {
+	unique := make([]uint, 0, len(ids))
+	seen := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; id == 0 || ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	names := make(map[uint]string, len(unique))
+	if len(unique) == 0 {
+		return names, nil
+	}
+	users, err := s.users.FindByIDs(unique)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		names[user.ID] = user.Name
+	}
+	return names, nil
+}
+
+func (s *ReviewService) ensureReviewer(userID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("审核人不存在")
+		}
+		return err
+	}
+	if user.Role != model.RoleAdmin || !user.Status {
+		return errors.New("审核人必须是已启用的管理员")
+	}
+	return nil
+}
+
+func (s *ReviewService) ensureAdmin(userID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("管理员不存在")
+		}
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return errors.New("仅管理员可操作")
+	}
+	return nil
+}
+
+// NextEditorialStatus resolves the status of a content or exam after an admin edit, given the
+// requested status ("" keeps the current one) and whether the material itself changed.
+// Publishing requires an approval; editing approved, published or retired material invalidates
+// the approval and returns it to draft, so edits to live material go through review before
+// learners see them; submissions under review cannot be edited at all.
+func NextEditorialStatus(current, requested string, edited bool) (string, error) {
+	if requested == "" {
+		requested = current
+	}
+	if current == model.EditorialInReview {
+		if edited || requested != current {
+			return "", errors.New("审核中不能修改，请等待审核完成或撤回提交")
+		}
+		return current, nil
+	}
+
+	base := current
+	if edited && editorialReviewed(current) {
+		base = model.EditorialDraft
+		if requested == current {
+			// 客户端回传原状态时按未指定处理
+			requested = base
+		}
+	}
+	if requested == base {
+		return base, nil
+	}
+
+	switch requested {
+	case model.EditorialPublished:
+		if editorialPublishable(base) {
+			return requested, nil
+		}
+		if base != current {
+			return "", errors.New("修改后需重新提交审核，审核通过后才能发布")
+		}
+		return "", errors.New("审核通过后才能发布")
+	case model.EditorialOffline, model.EditorialArchived:
+		if base == model.EditorialPublished {
+			return requested, nil
+		}
+		return "", errors.New("仅已发布的条目可以下线")
+	case model.EditorialDraft:
+		return requested, nil
+	default:
+		return "", errors.New("请通过审核接口提交或审核")
+	}
+}
+
+// editorialPublishable reports whether an entity may be published without another review: it
+// was approved, or was published before and retired unchanged since.
+func editorialPublishable(status string) bool {
+	return status == model.EditorialApproved || editorialRetired(status)
+}
+
+// editorialReviewed reports whether the status carries an approval that an edit invalidates.
+func editorialReviewed(status string) bool {
+	return status == model.EditorialApproved || status == model.EditorialPublished || editorialRetired(status)
+}
+
+func editorialRetired(status string) bool {
+	return status == model.EditorialOffline || status == model.EditorialArchived
+}
+
+// editorialEdited reports whether two audit snapshots differ in anything but publication state.
+func editorialEdited(before, after map[string]interface{}) bool {
+	for _, change := range utils.DiffFields(before, after) {
+		if _, ok := editorialBookkeepingFields[change.Field]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// editorialAction names the history action of a status change made outside the review actions.
+func editorialAction(to string) string {
+	switch to {
+	case model.EditorialPublished:
+		return model.ReviewActionPublish
+	case model.EditorialOffline, model.EditorialArchived:
+		return model.ReviewActionOffline
+	default:
+		return model.ReviewActionRevert
+	}
+}
+
+// editorialNote explains a status change caused by editing reviewed material.
+func editorialNote(from, to string, edited bool) string {
+	if edited && to == model.EditorialDraft && editorialReviewed(from) {
+		return "内容已修改，需重新提交审核"
+	}
+	return ""
+}
+
+func contentReviewTarget(content *model.Content) reviewTarget {
+	return reviewTarget{
+		entityType: AuditEntityContent,
+		id:         content.ID,
+		title:      content.Title,
+		status:     content.Status,
+		state:      content.ReviewState,
+		updatedAt:  content.UpdatedAt,
+	}
+}
+
+func examReviewTarget(exam *model.ExamPaper) reviewTarget {
+	return reviewTarget{
+		entityType: AuditEntityExam,
+		id:         exam.ID,
+		title:      exam.Title,
+		status:     exam.Status,
+		state:      exam.ReviewState,
+		updatedAt:  exam.UpdatedAt,
+	}
+}
+
+func reviewItem(target *reviewTarget, names map[uint]string) dto.ReviewItemResponse {
+	return dto.ReviewItemResponse{
+		EntityType:    target.entityType,
+		EntityID:      target.id,
+		Title:         target.title,
+		Status:        target.status,
+		ReviewerID:    target.state.ReviewerID,
+		ReviewerName:  names[target.state.ReviewerID],
+		SubmittedBy:   target.state.SubmittedBy,
+		SubmitterName: names[target.state.SubmittedBy],
+		SubmittedAt:   target.state.SubmittedAt,
+		ReviewedBy:    target.state.ReviewedBy,
+		ReviewedAt:    target.state.ReviewedAt,
+		RejectReason:  target.state.RejectReason,
+		UpdatedAt:     target.updatedAt,
+	}
+}
+
+func reviewAuditSnapshot(target *reviewTarget) map[string]interface{} {
+	return map[string]interface{}{
+		"status":        target.status,
+		"reviewer_id":   target.state.ReviewerID,
+		"submitted_by":  target.state.SubmittedBy,
+		"reviewed_by":   target.state.ReviewedBy,
+		"reject_reason": target.state.RejectReason,
+	}
+}
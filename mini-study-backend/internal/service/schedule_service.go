@@ -0,0 +1,489 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+)
+
+const (
+	scheduleLeaseName   = "content_schedule"
+	scheduleBatch       = 100
+	scheduleMaxRange    = 92 * 24 * time.Hour
+	scheduleDefaultDays = 30
+)
+
+// Scheduled actions shown in the calendar.
+const (
+	ScheduleActionPublish = "publish"
+	ScheduleActionOffline = "offline"
+)
+
+// ScheduleService publishes and takes offline contents and exams at their scheduled times.
+// Every replica runs the loop, but only the holder of a database lease applies changes, and
+// each change is a conditional update so a schedule is never applied twice.
+type ScheduleService struct {
+	leases   *repository.SchedulerLeaseRepository
+	contents *repository.ContentRepository
+	exams    *repository.ExamRepository
+	users    *repository.UserRepository
+	audit    *AuditService
+	search   *SearchService
+	reviews  *ReviewService
+	holder   string
+	leaseTTL time.Duration
+}
+
+// NewScheduleService builds a ScheduleService. leaseTTL must exceed the loop interval so the
+// holder renews its lease before it expires.
+func NewScheduleService(
+	leaseRepo *repository.SchedulerLeaseRepository,
+	contentRepo *repository.ContentRepository,
+	examRepo *repository.ExamRepository,
+	userRepo *repository.UserRepository,
+	audit *AuditService,
+	search *SearchService,
+	reviews *ReviewService,
+	leaseTTL time.Duration,
+) *ScheduleService {
+	host, _ := os.Hostname()
+	return &ScheduleService{
+		leases:   leaseRepo,
+		contents: contentRepo,
+		exams:    examRepo,
+		users:    userRepo,
+		audit:    audit,
+		search:   search,
+		reviews:  reviews,
+		holder:   fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8]),
+		leaseTTL: leaseTTL,
+	}
+}
+
+// AdminScheduleContent replaces the publish/offline schedule of a content.
+func (s *ScheduleService) AdminScheduleContent(ctx context.Context, adminID, contentID uint, req dto.AdminScheduleRequest) (*dto.ScheduleStateResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	content, err := s.contents.FindByID(contentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSchedule(content.Status, req, time.Now()); err != nil {
+		return nil, err
+	}
+	if req.PublishAt != nil {
+		candidate := *content
+		candidate.Status = "published"
+		if err := ensureVideoDuration(&candidate); err != nil {
+			return nil, err
+		}
+	}
+
+	before := scheduleSnapshot(content.Status, content.ScheduledPublishAt, content.ScheduledOfflineAt)
+	if err := s.contents.UpdateFields(content.ID, map[string]interface{}{
+		"scheduled_publish_at": req.PublishAt,
+		"scheduled_offline_at": req.OfflineAt,
+	}); err != nil {
+		return nil, err
+	}
+	content.ScheduledPublishAt, content.ScheduledOfflineAt = req.PublishAt, req.OfflineAt
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "schedule_content",
+		Target:     "contents",
+		EntityType: AuditEntityContent,
+		EntityID:   content.ID,
+		Before:     before,
+		After:      scheduleSnapshot(content.Status, content.ScheduledPublishAt, content.ScheduledOfflineAt),
+	})
+	return contentScheduleState(content), nil
+}
+
+// AdminScheduleExam replaces the publish/archive schedule of an exam.
+func (s *ScheduleService) AdminScheduleExam(ctx context.Context, adminID, examID uint, req dto.AdminScheduleRequest) (*dto.ScheduleStateResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	exam, err := s.exams.FindByID(examID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSchedule(exam.Status, req, time.Now()); err != nil {
+		return nil, err
+	}
+
+	before := scheduleSnapshot(exam.Status, exam.ScheduledPublishAt, exam.ScheduledOfflineAt)
+	if err := s.exams.UpdateFields(exam.ID, map[string]interface{}{
+		"scheduled_publish_at": req.PublishAt,
+		"scheduled_offline_at": req.OfflineAt,
+	}); err != nil {
+		return nil, err
+	}
+	exam.ScheduledPublishAt, exam.ScheduledOfflineAt = req.PublishAt, req.OfflineAt
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "schedule_exam",
+		Target:     "exam_papers",
+		EntityType: AuditEntityExam,
+		EntityID:   exam.ID,
+		Before:     before,
+		After:      scheduleSnapshot(exam.Status, exam.ScheduledPublishAt, exam.ScheduledOfflineAt),
+	})
+	return examScheduleState(exam), nil
+}
+
+// AdminCalendar lists the scheduled changes within the requested days, grouped by date.
+func (s *ScheduleService) AdminCalendar(adminID uint, query dto.AdminScheduleCalendarQuery) (*dto.AdminScheduleCalendarResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if query.From != nil {
+		from = *query.From
+	}
+	to := from.AddDate(0, 0, scheduleDefaultDays)
+	if query.To != nil {
+		to = *query.To
+	}
+	end := to.AddDate(0, 0, 1) // to 为包含的日期
+	if !end.After(from) {
+		return nil, errors.New("结束日期不能早于开始日期")
+	}
+	if end.Sub(from) > scheduleMaxRange {
+		return nil, errors.New("查询范围不能超过 92 天")
+	}
+
+	inRange := func(t *time.Time) bool {
+		return t != nil && !t.Before(from) && t.Before(end)
+	}
+	var events []dto.ScheduleEvent
+	if query.EntityType == "" || query.EntityType == AuditEntityContent {
+		contents, err := s.contents.ListScheduledBetween(from, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, content := range contents {
+			if inRange(content.ScheduledPublishAt) {
+				events = append(events, scheduleEvent(*content.ScheduledPublishAt, ScheduleActionPublish, AuditEntityContent, content.ID, content.Title, content.Status))
+			}
+			if inRange(content.ScheduledOfflineAt) {
+				events = append(events, scheduleEvent(*content.ScheduledOfflineAt, ScheduleActionOffline, AuditEntityContent, content.ID, content.Title, content.Status))
+			}
+		}
+	}
+	if query.EntityType == "" || query.EntityType == AuditEntityExam {
+		exams, err := s.exams.ListScheduledBetween(from, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, exam := range exams {
+			if inRange(exam.ScheduledPublishAt) {
+				events = append(events, scheduleEvent(*exam.ScheduledPublishAt, ScheduleActionPublish, AuditEntityExam, exam.ID, exam.Title, exam.Status))
+			}
+			if inRange(exam.ScheduledOfflineAt) {
+				events = append(events, scheduleEvent(*exam.ScheduledOfflineAt, ScheduleActionOffline, AuditEntityExam, exam.ID, exam.Title, exam.Status))
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+
+	resp := &dto.AdminScheduleCalendarResponse{
+		From: from.Format(time.DateOnly),
+		To:   to.Format(time.DateOnly),
+		Days: []dto.ScheduleCalendarDay{},
+	}
+	for _, event := range events {
+		date := event.At.In(time.Local).Format(time.DateOnly)
+		if n := len(resp.Days); n == 0 || resp.Days[n-1].Date != date {
+			resp.Days = append(resp.Days, dto.ScheduleCalendarDay{Date: date})
+		}
+		day := &resp.Days[len(resp.Days)-1]
+		day.Events = append(day.Events, event)
+	}
+	return resp, nil
+}
+
+// RunScheduler applies due schedules every interval until ctx is cancelled.
+func (s *ScheduleService) RunScheduler(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	run := func() {
+		applied, err := s.RunDue(ctx, time.Now())
+		if err != nil {
+			logger.Error("apply content schedules", zap.Error(err))
+		}
+		if applied > 0 {
+			logger.Info("content schedules applied", zap.Int("count", applied))
+		}
+	}
+
+	run()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// 主动释放租约，其他实例无需等待租约过期即可接管
+			_ = s.leases.Release(scheduleLeaseName, s.holder)
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
+
+// RunDue applies every schedule due at now if this replica holds the scheduler lease, and
+// returns how many changes were applied.
+func (s *ScheduleService) RunDue(ctx context.Context, now time.Time) (int, error) {
+	held, err := s.leases.Acquire(scheduleLeaseName, s.holder, now, now.Add(s.leaseTTL))
+	if err != nil || !held {
+		return 0, err
+	}
+
+	applied := 0
+	var errs []error
+	contents, err := s.contents.ListDueSchedules(now, scheduleBatch)
+	if err != nil {
+		return 0, err
+	}
+	for i := range contents {
+		n, err := s.applyContent(ctx, &contents[i], now)
+		applied += n
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	exams, err := s.exams.ListDueSchedules(now, scheduleBatch)
+	if err != nil {
+		return applied, err
+	}
+	for i := range exams {
+		n, err := s.applyExam(ctx, &exams[i], now)
+		applied += n
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return applied, errors.Join(errs...)
+}
+
+// applyContent publishes and/or takes a content offline. When both times have passed, e.g.
+// after downtime, the content is published first so it ends up offline.
+func (s *ScheduleService) applyContent(ctx context.Context, content *model.Content, now time.Time) (int, error) {
+	before := scheduleSnapshot(content.Status, content.ScheduledPublishAt, content.ScheduledOfflineAt)
+	applied := 0
+
+	if scheduleDue(content.ScheduledPublishAt, now) {
+		fields := map[string]interface{}{"scheduled_publish_at": nil}
+		// 计划后失去审核通过状态的内容只清除计划，不发布
+		if editorialPublishable(content.Status) {
+			candidate := *content
+			candidate.Status = "published"
+			// 视频源文件在计划后被替换且时长尚未识别时保留计划，待处理完成后的下个周期再发布
+			if err := ensureVideoDuration(&candidate); err != nil {
+				return applied, fmt.Errorf("content %d: %w", content.ID, err)
+			}
+			fields["status"] = "published"
+			if content.PublishAt == nil {
+				fields["publish_at"] = now
+				content.PublishAt = &now
+			}
+			content.Status = "published"
+		}
+		ok, err := s.contents.ApplySchedule(content.ID, "scheduled_publish_at", now, fields)
+		if err != nil || !ok {
+			return applied, err
+		}
+		content.ScheduledPublishAt = nil
+		applied++
+		s.recordContent(ctx, "scheduled_publish_content", content, before)
+	}
+
+	if scheduleDue(content.ScheduledOfflineAt, now) {
+		before = scheduleSnapshot(content.Status, content.ScheduledPublishAt, content.ScheduledOfflineAt)
+		fields := map[string]interface{}{"scheduled_offline_at": nil}
+		if content.Status == "published" {
+			fields["status"] = "offline"
+			content.Status = "offline"
+		}
+		ok, err := s.contents.ApplySchedule(content.ID, "scheduled_offline_at", now, fields)
+		if err != nil || !ok {
+			return applied, err
+		}
+		content.ScheduledOfflineAt = nil
+		applied++
+		s.recordContent(ctx, "scheduled_offline_content", content, before)
+	}
+	return applied, nil
+}
+
+func (s *ScheduleService) recordContent(ctx context.Context, action string, content *model.Content, before map[string]interface{}) {
+	if from, _ := before["status"].(string); from != content.Status {
+		_ = s.reviews.recordTransition(AuditEntityContent, content.ID, editorialAction(content.Status), from, content.Status, 0, content.ReviewerID, "定时任务执行")
+	}
+	_ = s.audit.RecordChange(ctx, 0, AuditChange{
+		Action:     action,
+		Target:     "contents",
+		EntityType: AuditEntityContent,
+		EntityID:   content.ID,
+		Before:     before,
+		After:      scheduleSnapshot(content.Status, content.ScheduledPublishAt, content.ScheduledOfflineAt),
+	})
+	_ = s.search.IndexContent(ctx, content)
+}
+
+// applyExam publishes and/or archives an exam, mirroring applyContent.
+func (s *ScheduleService) applyExam(ctx context.Context, exam *model.ExamPaper, now time.Time) (int, error) {
+	before := scheduleSnapshot(exam.Status, exam.ScheduledPublishAt, exam.ScheduledOfflineAt)
+	applied := 0
+
+	if scheduleDue(exam.ScheduledPublishAt, now) {
+		fields := map[string]interface{}{"scheduled_publish_at": nil}
+		if editorialPublishable(exam.Status) {
+			fields["status"] = "published"
+			exam.Status = "published"
+		}
+		ok, err := s.exams.ApplySchedule(exam.ID, "scheduled_publish_at", now, fields)
+		if err != nil || !ok {
+			return applied, err
+		}
+		exam.ScheduledPublishAt = nil
+		applied++
+		if err := s.recordExam(ctx, "scheduled_publish_exam", exam, before); err != nil {
+			return applied, err
+		}
+	}
+
+	if scheduleDue(exam.ScheduledOfflineAt, now) {
+		before = scheduleSnapshot(exam.Status, exam.ScheduledPublishAt, exam.ScheduledOfflineAt)
+		fields := map[string]interface{}{"scheduled_offline_at": nil}
+		if exam.Status == "published" {
+			fields["status"] = "archived"
+			exam.Status = "archived"
+		}
+		ok, err := s.exams.ApplySchedule(exam.ID, "scheduled_offline_at", now, fields)
+		if err != nil || !ok {
+			return applied, err
+		}
+		exam.ScheduledOfflineAt = nil
+		applied++
+		if err := s.recordExam(ctx, "scheduled_archive_exam", exam, before); err != nil {
+			return applied, err
+		}
+	}
+	return applied, nil
+}
+
+// recordExam audits an applied exam schedule and reindexes the exam with its questions.
+func (s *ScheduleService) recordExam(ctx context.Context, action string, exam *model.ExamPaper, before map[string]interface{}) error {
+	if from, _ := before["status"].(string); from != exam.Status {
+		_ = s.reviews.recordTransition(AuditEntityExam, exam.ID, editorialAction(exam.Status), from, exam.Status, 0, exam.ReviewerID, "定时任务执行")
+	}
+	_ = s.audit.RecordChange(ctx, 0, AuditChange{
+		Action:     action,
+		Target:     "exam_papers",
+		EntityType: AuditEntityExam,
+		EntityID:   exam.ID,
+		Before:     before,
+		After:      scheduleSnapshot(exam.Status, exam.ScheduledPublishAt, exam.ScheduledOfflineAt),
+	})
+	full, err := s.exams.FindWithQuestions(exam.ID)
+	if err != nil {
+		return err
+	}
+	_ = s.search.IndexExam(ctx, full)
+	return nil
+}
+
+// validateSchedule checks a schedule against the entity's status: a published entity can only
+// be scheduled offline, only an approved one can be scheduled to be published, and an
+// unpublished one only offline after it is scheduled to be published.
+func validateSchedule(status string, req dto.AdminScheduleRequest, now time.Time) error {
+	published := status == model.EditorialPublished
+	if req.PublishAt != nil {
+		if published {
+			return errors.New("已发布，无需定时发布")
+		}
+		if !editorialPublishable(status) {
+			return errors.New("审核通过后才能定时发布")
+		}
+		if !req.PublishAt.After(now) {
+			return errors.New("定时发布时间必须晚于当前时间")
+		}
+	}
+	if req.OfflineAt != nil {
+		if !req.OfflineAt.After(now) {
+			return errors.New("定时下线时间必须晚于当前时间")
+		}
+		if !published && req.PublishAt == nil {
+			return errors.New("未发布且未设置定时发布，无法定时下线")
+		}
+		if req.PublishAt != nil && !req.OfflineAt.After(*req.PublishAt) {
+			return errors.New("定时下线时间必须晚于定时发布时间")
+		}
+	}
+	return nil
+}
+
+func scheduleDue(at *time.Time, now time.Time) bool {
+	return at != nil && !at.After(now)
+}
+
+func scheduleSnapshot(status string, publishAt, offlineAt *time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"status":               status,
+		"scheduled_publish_at": publishAt,
+		"scheduled_offline_at": offlineAt,
+	}
+}
+
+func scheduleEvent(at time.Time, action, entityType string, id uint, title, status string) dto.ScheduleEvent {
+	return dto.ScheduleEvent{At: at, Action: action, EntityType: entityType, EntityID: id, Title: title, Status: status}
+}
+
+func contentScheduleState(content *model.Content) *dto.ScheduleStateResponse {
+	return &dto.ScheduleStateResponse{
+		EntityType:         AuditEntityContent,
+		EntityID:           content.ID,
+		Title:              content.Title,
+		Status:             content.Status,
+		ScheduledPublishAt: content.ScheduledPublishAt,
+		ScheduledOfflineAt: content.ScheduledOfflineAt,
+	}
+}
+
+func examScheduleState(exam *model.ExamPaper) *dto.ScheduleStateResponse {
+	return &dto.ScheduleStateResponse{
+		EntityType:         AuditEntityExam,
+		EntityID:           exam.ID,
+		Title:              exam.Title,
+		Status:             exam.Status,
+		ScheduledPublishAt: exam.ScheduledPublishAt,
+		ScheduledOfflineAt: exam.ScheduledOfflineAt,
+	}
+}
+
+func (s *ScheduleService) ensureAdmin(userID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("管理员不存在")
+		}
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return errors.New("仅管理员可操作")
+	}
+	return nil
+}
@@ -0,0 +1,446 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/search"
+)
+
+var searchKindLabels = map[string]string{
+	search.KindContent: "学习内容",
+	search.KindExam:    "考试",
+	search.KindGrowth:  "成长圈",
+}
+
+var searchTypeLabels = map[string]string{
+	"doc":     "文档",
+	"video":   "视频",
+	"article": "图文",
+}
+
+var searchRoleLabels = map[string]string{
+	string(model.RoleEmployee): "员工",
+	string(model.RoleManager):  "店长",
+}
+
+// SearchService keeps the search index in sync with published contents, exams and approved
+// growth posts, and answers searches with the caller's role visibility applied.
+//
+// Each replica keeps its own index and only applies the writes it handles, so an index may
+// briefly hold documents changed on another replica. Search re-checks every hit against the
+// database before returning it, and RunRefresh rebuilds the index periodically to pick up
+// documents published elsewhere.
+type SearchService struct {
+	index      search.Index
+	contents   *repository.ContentRepository
+	categories *repository.ContentCategoryRepository
+	pages      *repository.ContentPageRepository
+	exams      *repository.ExamRepository
+	posts      *repository.GrowthPostRepository
+	users      *repository.UserRepository
+}
+
+// NewSearchService builds a SearchService on top of the configured index.
+func NewSearchService(
+	index search.Index,
+	contentRepo *repository.ContentRepository,
+	categoryRepo *repository.ContentCategoryRepository,
+	pageRepo *repository.ContentPageRepository,
+	examRepo *repository.ExamRepository,
+	postRepo *repository.GrowthPostRepository,
+	userRepo *repository.UserRepository,
+) *SearchService {
+	return &SearchService{
+		index:      index,
+		contents:   contentRepo,
+		categories: categoryRepo,
+		pages:      pageRepo,
+		exams:      examRepo,
+		posts:      postRepo,
+		users:      userRepo,
+	}
+}
+
+// Search runs a full-text query across everything the user may see.
+func (s *SearchService) Search(ctx context.Context, userID uint, query dto.SearchQuery) (*dto.SearchResponse, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	role := string(user.Role)
+	if user.Role == model.RoleAdmin {
+		role = ""
+	}
+
+	page := query.Page
+	if page == 0 {
+		page = 1
+	}
+	size := query.PageSize
+	if size == 0 {
+		size = 20
+	}
+
+	result, err := s.index.Search(ctx, search.Query{
+		Text:        query.Q,
+		Role:        role,
+		Kind:        query.Kind,
+		Type:        query.Type,
+		CategoryID:  query.CategoryID,
+		VisibleRole: query.Role,
+		Offset:      (page - 1) * size,
+		Limit:       size,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	categoryNames, err := s.categoryNames()
+	if err != nil {
+		return nil, err
+	}
+
+	hits, err := s.visibleHits(ctx, result.Hits, role)
+	if err != nil {
+		return nil, err
+	}
+	total := result.Total - (len(result.Hits) - len(hits))
+
+	items := make([]dto.SearchHit, 0, len(hits))
+	for _, hit := range hits {
+		items = append(items, dto.SearchHit{
+			Kind:           hit.Kind,
+			ID:             hit.ID,
+			Title:          hit.Title,
+			TitleHighlight: hit.TitleHighlight,
+			Snippet:        hit.BodyHighlight,
+			Type:           hit.Type,
+			CategoryID:     hit.CategoryID,
+			CategoryName:   categoryNames[strconv.FormatUint(uint64(hit.CategoryID), 10)],
+			Score:          hit.Score,
+			CreatedAt:      hit.CreatedAt,
+		})
+	}
+
+	labels := map[string]map[string]string{
+		search.FacetKind:     searchKindLabels,
+		search.FacetType:     searchTypeLabels,
+		search.FacetCategory: categoryNames,
+		search.FacetRole:     searchRoleLabels,
+	}
+	facets := make(map[string][]dto.SearchFacet, len(result.Facets))
+	for name, counts := range result.Facets {
+		values := make([]dto.SearchFacet, 0, len(counts))
+		for _, count := range counts {
+			values = append(values, dto.SearchFacet{Value: count.Value, Label: labels[name][count.Value], Count: count.Count})
+		}
+		facets[name] = values
+	}
+
+	return &dto.SearchResponse{
+		Items:      items,
+		Facets:     facets,
+		Pagination: dto.NewPagination(page, size, int64(total)),
+	}, nil
+}
+
+// visibleHits keeps the hits whose record is still published (or approved) and visible to
+// role, which is empty for admins. Hits whose record was taken offline or deleted through
+// another replica are dropped from this replica's index as well.
+func (s *SearchService) visibleHits(ctx context.Context, hits []search.Hit, role string) ([]search.Hit, error) {
+	ids := make(map[string][]uint, len(searchKindLabels))
+	for _, hit := range hits {
+		ids[hit.Kind] = append(ids[hit.Kind], hit.ID)
+	}
+	live := make(map[string]bool, len(hits))
+	visible := make(map[string]bool, len(hits))
+
+	contents, err := s.contents.FindByIDs(ids[search.KindContent])
+	if err != nil {
+		return nil, err
+	}
+	for _, content := range contents {
+		if content.Status != "published" {
+			continue
+		}
+		key := search.DocumentKey(search.KindContent, content.ID)
+		live[key] = true
+		visible[key] = role == "" || content.VisibleRoles == "both" || content.VisibleRoles == "" || content.VisibleRoles == role
+	}
+	exams, err := s.exams.FindByIDs(ids[search.KindExam])
+	if err != nil {
+		return nil, err
+	}
+	for _, exam := range exams {
+		if exam.Status != "published" {
+			continue
+		}
+		key := search.DocumentKey(search.KindExam, exam.ID)
+		live[key] = true
+		visible[key] = role == "" || exam.TargetRole == "all" || string(exam.TargetRole) == role
+	}
+	posts, err := s.posts.FindByIDs(ids[search.KindGrowth])
+	if err != nil {
+		return nil, err
+	}
+	for _, post := range posts {
+		if post.Status != GrowthStatusApproved || post.Scope == model.GrowthScopeTeam {
+			continue
+		}
+		key := search.DocumentKey(search.KindGrowth, post.ID)
+		live[key] = true
+		visible[key] = role == "" || post.Scope != model.GrowthScopeRoles || strings.Contains(post.VisibleRoles, ","+role+",")
+	}
+
+	kept := make([]search.Hit, 0, len(hits))
+	for _, hit := range hits {
+		key := hit.Key()
+		if !live[key] {
+			_ = s.index.Delete(ctx, hit.Kind, hit.ID)
+			continue
+		}
+		if visible[key] {
+			kept = append(kept, hit)
+		}
+	}
+	return kept, nil
+}
+
+// IndexContent adds a published content to the index, or removes it otherwise.
+func (s *SearchService) IndexContent(ctx context.Context, content *model.Content) error {
+	if content.Status != "published" {
+		return s.index.Delete(ctx, search.KindContent, content.ID)
+	}
+	doc, err := s.contentDocument(content)
+	if err != nil {
+		return err
+	}
+	return s.index.Index(ctx, doc)
+}
+
+// IndexExam adds a published exam to the index, or removes it otherwise. The exam must be
+// loaded with its questions.
+func (s *SearchService) IndexExam(ctx context.Context, exam *model.ExamPaper) error {
+	if exam.Status != "published" {
+		return s.index.Delete(ctx, search.KindExam, exam.ID)
+	}
+	return s.index.Index(ctx, examDocument(exam))
+}
+
+// IndexGrowthPost adds an approved growth post to the index, or removes it otherwise. Team
+// posts are never indexed since the index only knows about role visibility.
+func (s *SearchService) IndexGrowthPost(ctx context.Context, post *model.GrowthPost) error {
+	if post.Status != GrowthStatusApproved || post.Scope == model.GrowthScopeTeam {
+		return s.RemoveGrowthPost(ctx, post.ID)
+	}
+	return s.index.Index(ctx, growthDocument(post))
+}
+
+// RemoveGrowthPost drops a deleted growth post from the index.
+func (s *SearchService) RemoveGrowthPost(ctx context.Context, postID uint) error {
+	return s.index.Delete(ctx, search.KindGrowth, postID)
+}
+
+// Reindex rebuilds the whole index from the database and returns the number of documents.
+func (s *SearchService) Reindex(ctx context.Context) (int, error) {
+	var docs []search.Document
+
+	contents, err := s.contents.ListPublishedByRole("", 0, "")
+	if err != nil {
+		return 0, err
+	}
+	for i := range contents {
+		doc, err := s.contentDocument(&contents[i])
+		if err != nil {
+			return 0, err
+		}
+		docs = append(docs, doc)
+	}
+
+	exams, err := s.exams.ListAll()
+	if err != nil {
+		return 0, err
+	}
+	for _, exam := range exams {
+		if exam.Status != "published" {
+			continue
+		}
+		full, err := s.exams.FindWithQuestions(exam.ID)
+		if err != nil {
+			return 0, err
+		}
+		docs = append(docs, examDocument(full))
+	}
+
+	posts, err := s.posts.ListPublic("")
+	if err != nil {
+		return 0, err
+	}
+	for i := range posts {
+		if posts[i].Scope == model.GrowthScopeTeam {
+			continue
+		}
+		docs = append(docs, growthDocument(&posts[i]))
+	}
+
+	if err := s.index.Rebuild(ctx, docs); err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// RunRefresh rebuilds the index every interval until ctx is cancelled, starting at once.
+func (s *SearchService) RunRefresh(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	refresh := func() {
+		start := time.Now()
+		count, err := s.Reindex(ctx)
+		if err != nil {
+			logger.Error("build search index", zap.Error(err))
+			return
+		}
+		logger.Debug("search index built", zap.Int("documents", count), zap.Duration("took", time.Since(start)))
+	}
+
+	refresh()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
+
+// AdminReindex lets an admin rebuild the index, e.g. after editing data directly in the database.
+func (s *SearchService) AdminReindex(ctx context.Context, adminID uint) (*dto.SearchReindexResponse, error) {
+	if err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	count, err := s.Reindex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.SearchReindexResponse{Documents: count, DurationMS: time.Since(start).Milliseconds()}, nil
+}
+
+// contentDocument indexes the summary, the text of article blocks and the extracted text of
+// document pages.
+func (s *SearchService) contentDocument(content *model.Content) (search.Document, error) {
+	parts := []string{content.Summary}
+	if content.BodyBlocksJSON != "" {
+		var blocks []dto.ArticleBlock
+		if err := json.Unmarshal([]byte(content.BodyBlocksJSON), &blocks); err == nil {
+			parts = append(parts, ArticlePlainText(blocks))
+		}
+	}
+	if content.Type == "doc" && content.PageCount > 0 {
+		pages, err := s.pages.ListByContent(content.ID, content.PageCount, 1, content.PageCount)
+		if err != nil {
+			return search.Document{}, err
+		}
+		for _, page := range pages {
+			parts = append(parts, page.Text)
+		}
+	}
+
+	var roles []string
+	if content.VisibleRoles != "both" && content.VisibleRoles != "" {
+		roles = []string{content.VisibleRoles}
+	}
+	return search.Document{
+		Kind:       search.KindContent,
+		ID:         content.ID,
+		Title:      content.Title,
+		Body:       compactText(parts...),
+		Type:       content.Type,
+		CategoryID: content.CategoryID,
+		Roles:      roles,
+		CreatedAt:  content.CreatedAt,
+	}, nil
+}
+
+func examDocument(exam *model.ExamPaper) search.Document {
+	parts := []string{exam.Description}
+	for _, question := range exam.Questions {
+		parts = append(parts, question.Stem)
+	}
+	var roles []string
+	if exam.TargetRole != "all" {
+		roles = []string{string(exam.TargetRole)}
+	}
+	return search.Document{
+		Kind:      search.KindExam,
+		ID:        exam.ID,
+		Title:     exam.Title,
+		Body:      compactText(parts...),
+		Roles:     roles,
+		CreatedAt: exam.CreatedAt,
+	}
+}
+
+// growthDocument titles a post with its author's name so posts can be found by author.
+func growthDocument(post *model.GrowthPost) search.Document {
+	var roles []string
+	if post.Scope == model.GrowthScopeRoles {
+		roles = splitList(post.VisibleRoles)
+	}
+	return search.Document{
+		Kind:      search.KindGrowth,
+		ID:        post.ID,
+		Title:     post.Creator.Name,
+		Body:      compactText(post.Content),
+		Roles:     roles,
+		CreatedAt: post.CreatedAt,
+	}
+}
+
+// compactText joins the parts and collapses runs of whitespace, which are frequent in text
+// extracted from documents and would otherwise bloat snippets.
+func compactText(parts ...string) string {
+	var fields []string
+	for _, part := range parts {
+		fields = append(fields, strings.Fields(part)...)
+	}
+	return strings.Join(fields, " ")
+}
+
+func (s *SearchService) categoryNames() (map[string]string, error) {
+	categories, err := s.categories.ListByRole("")
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(categories))
+	for _, category := range categories {
+		names[strconv.FormatUint(uint64(category.ID), 10)] = category.Name
+	}
+	return names, nil
+}
+
+func (s *SearchService) ensureAdmin(userID uint) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("管理员不存在")
+		}
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return errors.New("仅管理员可操作")
+	}
+	return nil
+}
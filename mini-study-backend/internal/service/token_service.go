@@ -26,11 +26,11 @@ func NewTokenService(secret, issuer string, ttl, refreshTTL time.Duration, userR
 
 // GeneratePair issues an access and refresh token pair.
 func (s *TokenService) GeneratePair(user *model.User) (*dto.TokenResponse, error) {
-	access, err := utils.GenerateToken(s.secret, s.issuer, s.ttl, user.ID, user.WorkNo)
+	access, err := utils.GenerateToken(s.secret, s.issuer, s.ttl, user.ID, user.WorkNo, user.TokenVersion)
 	if err != nil {
 		return nil, err
 	}
-	refresh, err := utils.GenerateToken(s.secret, s.issuer, s.refreshTTL, user.ID, user.WorkNo)
+	refresh, err := utils.GenerateToken(s.secret, s.issuer, s.refreshTTL, user.ID, user.WorkNo, user.TokenVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -45,14 +45,34 @@ func (s *TokenService) Refresh(refreshToken string) (*dto.TokenResponse, error)
 		return nil, err
 	}
 
-	user, err := s.userRepo.FindByID(claims.UserID)
+	user, err := s.checkSession(claims.UserID, claims.TokenVersion)
 	if err != nil {
 		return nil, err
 	}
 
+	return s.GeneratePair(user)
+}
+
+// ValidateSession rejects tokens whose owner has been disabled or whose version was revoked.
+// It is used by the JWT middleware on every authenticated request.
+func (s *TokenService) ValidateSession(userID, tokenVersion uint) error {
+	_, err := s.checkSession(userID, tokenVersion)
+	return err
+}
+
+func (s *TokenService) checkSession(userID, tokenVersion uint) (*model.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("用户不存在")
+	}
+
 	if !user.Status {
 		return nil, errors.New("用户已禁用")
 	}
 
-	return s.GeneratePair(user)
+	if user.TokenVersion != tokenVersion {
+		return nil, errors.New("登录已失效，请重新登录")
+	}
+
+	return user, nil
 }
@@ -0,0 +1,416 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+)
+
+const (
+	uploadCleanupBatchSize = 100
+	// uploadClaimTTL bounds how long a request may hold a session; a claim left by a crashed
+	// replica is taken over after it.
+	uploadClaimTTL = 10 * time.Minute
+)
+
+var (
+	// ErrUploadOffsetMismatch is returned when a chunk does not start at the session's received offset;
+	// the client should query the session and resume from its received_bytes.
+	ErrUploadOffsetMismatch = errors.New("分片偏移与已接收字节数不一致")
+	// ErrUploadSessionBusy is returned while another request, possibly on another replica, is
+	// processing the session; the client should retry shortly.
+	ErrUploadSessionBusy = errors.New("上传会话正在处理其他请求，请稍后重试")
+)
+
+// UploadSessionService implements resumable chunked uploads. Chunks are appended to a
+// staging file in chunkDir; on completion the assembled file goes through FileService.
+//
+// Every request that writes the staging file or changes the session status first claims the
+// session row with a conditional update, and its status changes only apply while it still holds
+// the claim. Replicas therefore never write one session at once, provided chunkDir is shared.
+type UploadSessionService struct {
+	sessions  *repository.UploadSessionRepository
+	files     *FileService
+	chunkDir  string
+	chunkSize int64
+	maxSize   int64
+	ttl       time.Duration
+}
+
+// NewUploadSessionService builds an UploadSessionService.
+func NewUploadSessionService(
+	sessionRepo *repository.UploadSessionRepository,
+	files *FileService,
+	chunkDir string,
+	chunkSizeMB, maxSizeMB int,
+	ttl time.Duration,
+) *UploadSessionService {
+	return &UploadSessionService{
+		sessions:  sessionRepo,
+		files:     files,
+		chunkDir:  chunkDir,
+		chunkSize: int64(chunkSizeMB) * 1024 * 1024,
+		maxSize:   int64(maxSizeMB) * 1024 * 1024,
+		ttl:       ttl,
+	}
+}
+
+// Init validates the file metadata against the purpose policy and opens a session.
+func (s *UploadSessionService) Init(userID uint, req dto.InitUploadSessionRequest) (*dto.UploadSessionResponse, error) {
+	if req.Size > s.maxSize {
+		return nil, fmt.Errorf("文件大小不能超过 %dMB", s.maxSize/1024/1024)
+	}
+	purpose, err := s.files.ResolvePurpose(req.Purpose, req.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &model.UploadSession{
+		UploadID:  uuid.NewString(),
+		UserID:    userID,
+		Purpose:   purpose,
+		Filename:  filepath.Base(req.Filename),
+		TotalSize: req.Size,
+		SHA256:    strings.ToLower(req.SHA256),
+		Status:    model.UploadSessionUploading,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if err := os.MkdirAll(s.chunkDir, 0o755); err != nil {
+		return nil, fmt.Errorf("make chunk dir: %w", err)
+	}
+	if err := s.sessions.Create(session); err != nil {
+		return nil, err
+	}
+	return s.toResponse(session), nil
+}
+
+// Get returns the session state so a client can resume from received_bytes.
+func (s *UploadSessionService) Get(userID uint, uploadID string) (*dto.UploadSessionResponse, error) {
+	session, err := s.load(userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(session), nil
+}
+
+// UploadChunk appends a chunk starting at offset. A chunk whose checksum does not match is
+// discarded and the offset stays unchanged, so the client can simply retry it.
+func (s *UploadSessionService) UploadChunk(userID uint, uploadID string, req dto.UploadChunkRequest, chunk *multipart.FileHeader) (*dto.UploadSessionResponse, error) {
+	session, token, err := s.claim(userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	defer s.release(session, token)
+
+	if err := ensureActive(session, time.Now()); err != nil {
+		return nil, err
+	}
+	if req.Offset != session.ReceivedBytes {
+		return nil, ErrUploadOffsetMismatch
+	}
+	if chunk.Size == 0 || chunk.Size > s.chunkSize {
+		return nil, fmt.Errorf("分片大小必须在 1 到 %d 字节之间", s.chunkSize)
+	}
+	if req.Offset+chunk.Size > session.TotalSize {
+		return nil, errors.New("分片超出文件总大小")
+	}
+
+	src, err := chunk.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open chunk: %w", err)
+	}
+	defer src.Close()
+
+	staging, err := os.OpenFile(s.stagingPath(session), os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open staging file: %w", err)
+	}
+	defer staging.Close()
+
+	// 截断到已确认的偏移，丢弃上次中断时可能残留的未确认数据
+	if err := staging.Truncate(req.Offset); err != nil {
+		return nil, fmt.Errorf("truncate staging file: %w", err)
+	}
+	if _, err := staging.Seek(req.Offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("seek staging file: %w", err)
+	}
+
+	hasher := sha256.New()
+	written, err := io.Copy(io.MultiWriter(staging, hasher), src)
+	if err != nil {
+		return nil, fmt.Errorf("write chunk: %w", err)
+	}
+	if written != chunk.Size {
+		return nil, errors.New("分片数据不完整")
+	}
+	if req.Checksum != "" && !strings.EqualFold(req.Checksum, hex.EncodeToString(hasher.Sum(nil))) {
+		_ = staging.Truncate(req.Offset)
+		return nil, errors.New("分片校验和不一致")
+	}
+	if err := staging.Sync(); err != nil {
+		return nil, fmt.Errorf("sync staging file: %w", err)
+	}
+
+	expiresAt := time.Now().Add(s.ttl)
+	advanced, err := s.sessions.AdvanceOffset(session.ID, token, req.Offset, req.Offset+written, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if !advanced {
+		return nil, ErrUploadOffsetMismatch
+	}
+	session.ReceivedBytes = req.Offset + written
+	session.ExpiresAt = expiresAt
+	return s.toResponse(session), nil
+}
+
+// Complete assembles the received chunks into the final stored file. Completing an already
+// completed session returns the same result, so a client may retry after a lost response.
+func (s *UploadSessionService) Complete(ctx context.Context, userID uint, uploadID string) (*dto.UploadResponse, error) {
+	session, err := s.load(userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == model.UploadSessionCompleted {
+		return s.describeCompleted(ctx, session)
+	}
+	session, token, err := s.claim(userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	defer s.release(session, token)
+	// 认领前可能已由其他请求完成
+	if session.Status == model.UploadSessionCompleted {
+		return s.describeCompleted(ctx, session)
+	}
+	if err := ensureActive(session, time.Now()); err != nil {
+		return nil, err
+	}
+	if session.ReceivedBytes != session.TotalSize {
+		return nil, fmt.Errorf("文件尚未上传完整：已接收 %d / %d 字节", session.ReceivedBytes, session.TotalSize)
+	}
+
+	staging, err := os.Open(s.stagingPath(session))
+	if err != nil {
+		return nil, fmt.Errorf("open staging file: %w", err)
+	}
+	defer staging.Close()
+
+	resp, err := s.files.save(ctx, userID, session.Purpose, session.Filename, io.NewSectionReader(staging, 0, session.TotalSize), session.TotalSize, session.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	completed, err := s.sessions.MarkCompleted(session.ID, token, resp.Key, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !completed {
+		return nil, errors.New("上传会话已过期，请重新上传")
+	}
+	s.removeStaging(session)
+	return resp, nil
+}
+
+func (s *UploadSessionService) describeCompleted(ctx context.Context, session *model.UploadSession) (*dto.UploadResponse, error) {
+	info, err := s.files.store.Stat(ctx, session.Key)
+	if err != nil {
+		return nil, err
+	}
+	return s.files.Describe(ctx, session.Key, session.Purpose, info.ContentType, session.TotalSize)
+}
+
+// Abort cancels a session and discards received chunks.
+func (s *UploadSessionService) Abort(userID uint, uploadID string) error {
+	session, token, err := s.claim(userID, uploadID)
+	if err != nil {
+		return err
+	}
+	defer s.release(session, token)
+
+	if err := ensureActive(session, time.Now()); err != nil {
+		return err
+	}
+	aborted, err := s.sessions.MarkAborted(session.ID, token)
+	if err != nil {
+		return err
+	}
+	if !aborted {
+		return ErrUploadSessionBusy
+	}
+	s.removeStaging(session)
+	return nil
+}
+
+// CleanupExpired marks abandoned sessions as expired and deletes their staging files.
+// Each session is expired under its claim and only if it is still uploading and past its
+// deadline, so a chunk or Complete racing with the cleanup never loses its staging file; a
+// session claimed by a request is left for the next run.
+func (s *UploadSessionService) CleanupExpired(now time.Time) (int, error) {
+	cleaned := 0
+	for {
+		sessions, err := s.sessions.ListExpired(now, uploadCleanupBatchSize)
+		if err != nil {
+			return cleaned, err
+		}
+		for i := range sessions {
+			expired, err := s.expire(&sessions[i], now)
+			if err != nil {
+				return cleaned, err
+			}
+			if expired {
+				cleaned++
+			}
+		}
+		if len(sessions) < uploadCleanupBatchSize {
+			return cleaned, nil
+		}
+	}
+}
+
+func (s *UploadSessionService) expire(session *model.UploadSession, now time.Time) (bool, error) {
+	token := uuid.NewString()
+	claimed, err := s.sessions.Claim(session.ID, token, now, now.Add(uploadClaimTTL))
+	if err != nil || !claimed {
+		return false, err
+	}
+	defer s.release(session, token)
+
+	expired, err := s.sessions.MarkExpired(session.ID, token, now)
+	if err != nil || !expired {
+		return false, err
+	}
+	s.removeStaging(session)
+	return true, nil
+}
+
+// RunCleanup periodically expires abandoned sessions until ctx is cancelled.
+func (s *UploadSessionService) RunCleanup(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	cleanup := func() {
+		cleaned, err := s.CleanupExpired(time.Now())
+		if err != nil {
+			logger.Error("cleanup upload sessions failed", zap.Error(err))
+			return
+		}
+		if cleaned > 0 {
+			logger.Info("expired upload sessions cleaned", zap.Int("count", cleaned))
+		}
+	}
+
+	cleanup()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cleanup()
+		}
+	}
+}
+
+func (s *UploadSessionService) load(userID uint, uploadID string) (*model.UploadSession, error) {
+	session, err := s.sessions.FindByUploadID(uploadID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("上传会话不存在")
+		}
+		return nil, err
+	}
+	if session.UserID != userID {
+		return nil, errors.New("上传会话不存在")
+	}
+	return session, nil
+}
+
+// claim takes the session for one request and returns it as stored after the claim, with the
+// claim token. It fails with ErrUploadSessionBusy while another request holds the session.
+func (s *UploadSessionService) claim(userID uint, uploadID string) (*model.UploadSession, string, error) {
+	session, err := s.load(userID, uploadID)
+	if err != nil {
+		return nil, "", err
+	}
+	token := uuid.NewString()
+	now := time.Now()
+	claimed, err := s.sessions.Claim(session.ID, token, now, now.Add(uploadClaimTTL))
+	if err != nil {
+		return nil, "", err
+	}
+	if !claimed {
+		return nil, "", ErrUploadSessionBusy
+	}
+	// 认领前读取的状态可能已被上一个请求改变，重新读取
+	current, err := s.load(userID, uploadID)
+	if err != nil {
+		s.release(session, token)
+		return nil, "", err
+	}
+	return current, token, nil
+}
+
+func (s *UploadSessionService) release(session *model.UploadSession, token string) {
+	_ = s.sessions.ReleaseClaim(session.ID, token)
+}
+
+// ensureActive rejects sessions that are no longer uploading or whose deadline has passed,
+// even if the cleanup job has not marked them expired yet.
+func ensureActive(session *model.UploadSession, now time.Time) error {
+	if err := ensureUploading(session); err != nil {
+		return err
+	}
+	if now.After(session.ExpiresAt) {
+		return errors.New("上传会话已过期，请重新上传")
+	}
+	return nil
+}
+
+func ensureUploading(session *model.UploadSession) error {
+	switch session.Status {
+	case model.UploadSessionUploading:
+		return nil
+	case model.UploadSessionCompleted:
+		return errors.New("上传会话已完成")
+	case model.UploadSessionAborted:
+		return errors.New("上传会话已取消")
+	default:
+		return errors.New("上传会话已过期，请重新上传")
+	}
+}
+
+func (s *UploadSessionService) stagingPath(session *model.UploadSession) string {
+	return filepath.Join(s.chunkDir, session.UploadID+".part")
+}
+
+func (s *UploadSessionService) removeStaging(session *model.UploadSession) {
+	_ = os.Remove(s.stagingPath(session))
+}
+
+func (s *UploadSessionService) toResponse(session *model.UploadSession) *dto.UploadSessionResponse {
+	return &dto.UploadSessionResponse{
+		UploadID:      session.UploadID,
+		Filename:      session.Filename,
+		Purpose:       session.Purpose,
+		TotalSize:     session.TotalSize,
+		ReceivedBytes: session.ReceivedBytes,
+		ChunkSize:     s.chunkSize,
+		Status:        session.Status,
+		ExpiresAt:     session.ExpiresAt,
+	}
+}
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
-	"net/http"
+	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -16,17 +19,18 @@ import (
 type UserService struct {
 	repo         *repository.UserRepository
 	relationRepo *repository.ManagerEmployeeRepository
+	transfers    *repository.EmployeeTransferRepository
 	audit        *AuditService
 	points       *PointService
 }
 
 // NewUserService builds a user service.
-func NewUserService(userRepo *repository.UserRepository, relationRepo *repository.ManagerEmployeeRepository, audit *AuditService, pointSvc *PointService) *UserService {
-	return &UserService{repo: userRepo, relationRepo: relationRepo, audit: audit, points: pointSvc}
+func NewUserService(userRepo *repository.UserRepository, relationRepo *repository.ManagerEmployeeRepository, transferRepo *repository.EmployeeTransferRepository, audit *AuditService, pointSvc *PointService) *UserService {
+	return &UserService{repo: userRepo, relationRepo: relationRepo, transfers: transferRepo, audit: audit, points: pointSvc}
 }
 
 // Register creates a new user.
-func (s *UserService) Register(req dto.RegisterRequest) (*model.User, error) {
+func (s *UserService) Register(ctx context.Context, req dto.RegisterRequest) (*model.User, error) {
 	if _, err := s.repo.FindByWorkNo(req.WorkNo); err == nil {
 		return nil, errors.New("工号已存在")
 	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -60,12 +64,12 @@ func (s *UserService) Register(req dto.RegisterRequest) (*model.User, error) {
 		return nil, err
 	}
 
-	_ = s.audit.Record(user.ID, "register", "users", utils.ToJSONString(req), http.StatusText(http.StatusCreated))
+	_ = s.audit.Record(ctx, user.ID, "register", "users", utils.ToRedactedJSONString(req), "success")
 	return user, nil
 }
 
 // Login validates user credentials and returns the user.
-func (s *UserService) Login(req dto.LoginRequest) (*model.User, error) {
+func (s *UserService) Login(ctx context.Context, req dto.LoginRequest) (*model.User, error) {
 	user, err := s.repo.FindByWorkNo(req.WorkNo)
 	if err != nil {
 		return nil, errors.New("工号或密码错误")
@@ -75,27 +79,47 @@ func (s *UserService) Login(req dto.LoginRequest) (*model.User, error) {
 		return nil, errors.New("工号或密码错误")
 	}
 
-	_ = s.audit.Record(user.ID, "login", "users", "{}", http.StatusText(http.StatusOK))
+	if !user.Status {
+		return nil, errors.New("用户已禁用")
+	}
+
+	_ = s.audit.Record(ctx, user.ID, "login", "users", "{}", "success")
 	return user, nil
 }
 
-// ListManagers returns all active managers.
-func (s *UserService) ListManagers() ([]model.User, error) {
-	return s.repo.ListManagers()
+// ListManagers returns a page of active managers.
+func (s *UserService) ListManagers(query dto.ManagerListQuery) ([]model.User, dto.Pagination, error) {
+	page, err := pageRequest(query.PageQuery, repository.UserSortFields, "id", false)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	users, total, err := s.repo.SearchManagers(page)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	return users, pagination(page, total), nil
 }
 
-// AdminListUsers returns users for admin panel.
-func (s *UserService) AdminListUsers(adminID uint, filter dto.AdminListUsersQuery) ([]dto.AdminUserResponse, error) {
+// AdminListUsers returns a page of users for admin panel.
+func (s *UserService) AdminListUsers(adminID uint, filter dto.AdminListUsersQuery) (*dto.AdminUserListResponse, error) {
 	if _, err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
 
-	users, err := s.repo.ListUsers(filter.Role, filter.Keyword)
+	page, err := pageRequest(filter.PageQuery, repository.UserSortFields, "id", true)
+	if err != nil {
+		return nil, err
+	}
+	users, total, err := s.repo.SearchUsers(filter.Role, filter.Keyword, page)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.buildAdminUserResponses(users)
+	items, err := s.buildAdminUserResponses(users)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AdminUserListResponse{Items: items, Pagination: pagination(page, total)}, nil
 }
 
 // AdminGetUser returns a single user for admin panel.
@@ -122,7 +146,7 @@ func (s *UserService) AdminGetUser(adminID, targetID uint) (*dto.AdminUserRespon
 }
 
 // AdminUpdateUserRole updates role for a specific user.
-func (s *UserService) AdminUpdateUserRole(adminID, targetID uint, role model.Role) (*model.User, error) {
+func (s *UserService) AdminUpdateUserRole(ctx context.Context, adminID, targetID uint, role model.Role) (*model.User, error) {
 	if _, err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
@@ -135,6 +159,7 @@ func (s *UserService) AdminUpdateUserRole(adminID, targetID uint, role model.Rol
 	if err != nil {
 		return nil, err
 	}
+	before := userAuditSnapshot(user)
 
 	user.Role = role
 	if err := s.repo.Update(user); err != nil {
@@ -147,6 +172,14 @@ func (s *UserService) AdminUpdateUserRole(adminID, targetID uint, role model.Rol
 		}
 	}
 
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "update_user_role",
+		Target:     "users",
+		EntityType: AuditEntityUser,
+		EntityID:   user.ID,
+		Before:     before,
+		After:      userAuditSnapshot(user),
+	})
 	return user, nil
 }
 
@@ -241,9 +274,10 @@ func (s *UserService) buildAdminUserResponses(users []model.User) ([]dto.AdminUs
 				Role:   user.Role,
 				Status: user.Status,
 			},
-			ManagerIDs: copiedIDs,
-			Managers:   managerBriefs,
-			Points:     points,
+			ManagerIDs:   copiedIDs,
+			Managers:     managerBriefs,
+			Points:       points,
+			OffboardedAt: user.OffboardedAt,
 		})
 	}
 
@@ -357,7 +391,7 @@ func (s *UserService) CreateManager(adminID uint, req dto.AdminCreateManagerRequ
 }
 
 // CreateEmployee creates a new employee user; only admin can call this.
-func (s *UserService) CreateEmployee(adminID uint, req dto.AdminCreateEmployeeRequest) (*model.User, error) {
+func (s *UserService) CreateEmployee(ctx context.Context, adminID uint, req dto.AdminCreateEmployeeRequest) (*model.User, error) {
 	if _, err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
@@ -397,12 +431,19 @@ func (s *UserService) CreateEmployee(adminID uint, req dto.AdminCreateEmployeeRe
 		}
 	}
 
-	_ = s.audit.Record(adminID, "create_employee", "users", utils.ToJSONString(req), http.StatusText(http.StatusCreated))
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "create_employee",
+		Target:     "users",
+		EntityType: AuditEntityUser,
+		EntityID:   user.ID,
+		After:      userAuditSnapshot(user),
+		Payload:    req,
+	})
 	return user, nil
 }
 
 // PromoteToManager changes an existing employee to manager; only admin can call.
-func (s *UserService) PromoteToManager(adminID, targetUserID uint) (*model.User, error) {
+func (s *UserService) PromoteToManager(ctx context.Context, adminID, targetUserID uint) (*model.User, error) {
 	if _, err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
@@ -415,17 +456,27 @@ func (s *UserService) PromoteToManager(adminID, targetUserID uint) (*model.User,
 	if user.Role == model.RoleAdmin {
 		return nil, errors.New("不能修改管理员角色")
 	}
+	before := userAuditSnapshot(user)
 
 	user.Role = model.RoleManager
 
 	if err := s.repo.Update(user); err != nil {
 		return nil, err
 	}
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "promote_to_manager",
+		Target:     "users",
+		EntityType: AuditEntityUser,
+		EntityID:   user.ID,
+		Before:     before,
+		After:      userAuditSnapshot(user),
+	})
 	return user, nil
 }
 
-// UpdateEmployeeManagers updates the manager bindings for an employee or manager (non-admin).
-func (s *UserService) UpdateEmployeeManagers(adminID, targetUserID uint, managerWorkNos []string) (*model.User, error) {
+// UpdateEmployeeManagers replaces the manager bindings of an employee or manager (non-admin)
+// and keeps a transfer history entry, like TransferEmployee.
+func (s *UserService) UpdateEmployeeManagers(ctx context.Context, adminID, targetUserID uint, managerWorkNos []string) (*model.User, error) {
 	if _, err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
@@ -444,9 +495,306 @@ func (s *UserService) UpdateEmployeeManagers(adminID, targetUserID uint, manager
 		return nil, err
 	}
 
-	if err := s.relationRepo.ReplaceRelations(user.ID, managerIDs); err != nil {
+	if _, err := s.rebindManagers(ctx, adminID, user.ID, managerIDs, "", "update_employee_managers"); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// DeactivateUser disables an account, revokes its issued tokens and removes its manager-employee bindings.
+func (s *UserService) DeactivateUser(ctx context.Context, adminID, targetUserID uint, reason string) (*model.User, error) {
+	user, err := s.loadLifecycleTarget(adminID, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Status {
+		return nil, errors.New("用户已处于禁用状态")
+	}
+	before := userAuditSnapshot(user)
+
+	user.Status = false
+	user.TokenVersion++
+	// 状态、店长关系与审计日志在同一事务中写入，避免只完成一部分
+	err = s.repo.Transaction(func(tx *gorm.DB) error {
+		if err := s.repo.WithTx(tx).Update(user); err != nil {
+			return err
+		}
+		removed, err := s.relationRepo.WithTx(tx).DeleteByUser(user.ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.RecordChangeTx(ctx, tx, adminID, AuditChange{
+			Action:     "deactivate_user",
+			Target:     "users",
+			EntityType: AuditEntityUser,
+			EntityID:   user.ID,
+			Before:     before,
+			After:      userAuditSnapshot(user),
+			Payload:    map[string]interface{}{"reason": reason, "removed_relations": removed},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ReactivateUser re-enables a deactivated account. Offboarded accounts cannot be reactivated.
+func (s *UserService) ReactivateUser(ctx context.Context, adminID, targetUserID uint, reason string) (*model.User, error) {
+	user, err := s.loadLifecycleTarget(adminID, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.OffboardedAt != nil {
+		return nil, errors.New("已离职用户不能重新启用")
+	}
+	if user.Status {
+		return nil, errors.New("用户已处于启用状态")
+	}
+	before := userAuditSnapshot(user)
+
+	user.Status = true
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+
+	_ = s.audit.RecordChange(ctx, adminID, AuditChange{
+		Action:     "reactivate_user",
+		Target:     "users",
+		EntityType: AuditEntityUser,
+		EntityID:   user.ID,
+		Before:     before,
+		After:      userAuditSnapshot(user),
+		Payload:    map[string]interface{}{"reason": reason},
+	})
+	return user, nil
+}
+
+// TransferEmployee replaces an employee's manager bindings and keeps a transfer history entry.
+func (s *UserService) TransferEmployee(ctx context.Context, adminID, targetUserID uint, req dto.AdminTransferEmployeeRequest) (*dto.EmployeeTransferResponse, error) {
+	user, err := s.loadLifecycleTarget(adminID, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != model.RoleEmployee {
+		return nil, errors.New("仅员工可调动")
+	}
+	if !user.Status {
+		return nil, errors.New("用户已禁用，不能调动")
+	}
+
+	toIDs, err := s.resolveManagerWorkNos(req.ManagerWorkNos)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer, err := s.rebindManagers(ctx, adminID, user.ID, toIDs, req.Reason, "transfer_employee")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.buildTransferResponses([]model.EmployeeTransfer{*transfer})
+	if err != nil {
+		return nil, err
+	}
+	return &resp[0], nil
+}
+
+// rebindManagers replaces a user's manager bindings and records the transfer and its audit entry
+// in one transaction. The user row is locked and re-checked there, so a user deactivated
+// concurrently is not bound again.
+func (s *UserService) rebindManagers(ctx context.Context, adminID, userID uint, toIDs []uint, reason, action string) (*model.EmployeeTransfer, error) {
+	for _, mid := range toIDs {
+		if mid == userID {
+			return nil, errors.New("不能调动到自己名下")
+		}
+	}
+
+	var transfer *model.EmployeeTransfer
+	err := s.repo.Transaction(func(tx *gorm.DB) error {
+		user, err := s.repo.WithTx(tx).LockByID(userID)
+		if err != nil {
+			return err
+		}
+		if !user.Status {
+			return errors.New("用户已禁用，不能调动")
+		}
+		fromIDs, err := s.relationRepo.WithTx(tx).ListManagerIDsByEmployee(userID)
+		if err != nil {
+			return err
+		}
+		transfer = &model.EmployeeTransfer{
+			EmployeeID:     userID,
+			FromManagerIDs: utils.ToJSONString(fromIDs),
+			ToManagerIDs:   utils.ToJSONString(toIDs),
+			OperatorID:     adminID,
+			Reason:         reason,
+		}
+		if err := s.relationRepo.WithTx(tx).ReplaceRelations(userID, toIDs); err != nil {
+			return err
+		}
+		if err := s.transfers.WithTx(tx).Create(transfer); err != nil {
+			return err
+		}
+		return s.audit.RecordChangeTx(ctx, tx, adminID, AuditChange{
+			Action:     action,
+			Target:     "manager_employees",
+			EntityType: AuditEntityUser,
+			EntityID:   userID,
+			Before:     map[string]interface{}{"manager_ids": fromIDs},
+			After:      map[string]interface{}{"manager_ids": toIDs},
+			Payload:    map[string]interface{}{"reason": reason},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// ListEmployeeTransfers returns the transfer history of an employee, newest first.
+func (s *UserService) ListEmployeeTransfers(adminID, targetUserID uint) ([]dto.EmployeeTransferResponse, error) {
+	if _, err := s.ensureAdmin(adminID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FindByID(targetUserID); err != nil {
+		return nil, err
+	}
+
+	transfers, err := s.transfers.ListByEmployee(targetUserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransferResponses(transfers)
+}
+
+// OffboardUser disables an account permanently and anonymizes its personal data.
+// Learning records, exam attempts and point transactions stay linked to the user ID for reporting.
+func (s *UserService) OffboardUser(ctx context.Context, adminID, targetUserID uint, reason string) (*model.User, error) {
+	user, err := s.loadLifecycleTarget(adminID, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.OffboardedAt != nil {
+		return nil, errors.New("用户已离职")
+	}
+	before := userAuditSnapshot(user)
+
+	now := time.Now()
+	user.Status = false
+	user.TokenVersion++
+	user.OffboardedAt = &now
+	user.WorkNo = fmt.Sprintf("offboarded-%d", user.ID)
+	user.Name = "已离职员工"
+	user.Phone = ""
+	user.PasswordHash = ""
+	err = s.repo.Transaction(func(tx *gorm.DB) error {
+		if err := s.repo.WithTx(tx).Update(user); err != nil {
+			return err
+		}
+		removed, err := s.relationRepo.WithTx(tx).DeleteByUser(user.ID)
+		if err != nil {
+			return err
+		}
+		// 快照不含工号、姓名、手机号，避免把已匿名化的个人信息写回日志
+		return s.audit.RecordChangeTx(ctx, tx, adminID, AuditChange{
+			Action:     "offboard_user",
+			Target:     "users",
+			EntityType: AuditEntityUser,
+			EntityID:   user.ID,
+			Before:     before,
+			After:      userAuditSnapshot(user),
+			Payload:    map[string]interface{}{"reason": reason, "removed_relations": removed},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// userAuditSnapshot keeps only the account fields tracked in the audit trail; personal data is left out.
+func userAuditSnapshot(user *model.User) map[string]interface{} {
+	return map[string]interface{}{
+		"role":          user.Role,
+		"status":        user.Status,
+		"offboarded_at": user.OffboardedAt,
+	}
+}
+
+// loadLifecycleTarget checks admin permission and returns a user whose account status may be changed.
+func (s *UserService) loadLifecycleTarget(adminID, targetUserID uint) (*model.User, error) {
+	if _, err := s.ensureAdmin(adminID); err != nil {
 		return nil, err
 	}
+	if adminID == targetUserID {
+		return nil, errors.New("不能对自己执行该操作")
+	}
 
+	user, err := s.repo.FindByID(targetUserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == model.RoleAdmin {
+		return nil, errors.New("不能修改管理员的账号状态")
+	}
 	return user, nil
 }
+
+func (s *UserService) buildTransferResponses(transfers []model.EmployeeTransfer) ([]dto.EmployeeTransferResponse, error) {
+	resp := make([]dto.EmployeeTransferResponse, 0, len(transfers))
+	if len(transfers) == 0 {
+		return resp, nil
+	}
+
+	fromMap := make(map[uint][]uint, len(transfers))
+	toMap := make(map[uint][]uint, len(transfers))
+	managerIDSet := make(map[uint]struct{})
+	for _, t := range transfers {
+		var from, to []uint
+		_ = json.Unmarshal([]byte(t.FromManagerIDs), &from)
+		_ = json.Unmarshal([]byte(t.ToManagerIDs), &to)
+		fromMap[t.ID] = from
+		toMap[t.ID] = to
+		for _, id := range append(append([]uint{}, from...), to...) {
+			managerIDSet[id] = struct{}{}
+		}
+	}
+
+	managerIDs := make([]uint, 0, len(managerIDSet))
+	for id := range managerIDSet {
+		managerIDs = append(managerIDs, id)
+	}
+	managers, err := s.repo.FindByIDs(managerIDs)
+	if err != nil {
+		return nil, err
+	}
+	managerMap := make(map[uint]dto.ManagerBrief, len(managers))
+	for _, m := range managers {
+		managerMap[m.ID] = dto.ManagerBrief{ID: m.ID, WorkNo: m.WorkNo, Name: m.Name, Phone: m.Phone}
+	}
+
+	briefs := func(ids []uint) []dto.ManagerBrief {
+		out := make([]dto.ManagerBrief, 0, len(ids))
+		for _, id := range ids {
+			if brief, ok := managerMap[id]; ok {
+				out = append(out, brief)
+			} else {
+				out = append(out, dto.ManagerBrief{ID: id})
+			}
+		}
+		return out
+	}
+
+	for _, t := range transfers {
+		resp = append(resp, dto.EmployeeTransferResponse{
+			ID:           t.ID,
+			EmployeeID:   t.EmployeeID,
+			FromManagers: briefs(fromMap[t.ID]),
+			ToManagers:   briefs(toMap[t.ID]),
+			OperatorID:   t.OperatorID,
+			Reason:       t.Reason,
+			CreatedAt:    t.CreatedAt,
+		})
+	}
+	return resp, nil
+}
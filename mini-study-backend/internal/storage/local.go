@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage keeps objects on disk. Public objects live under publicDir, which is served
+// statically at publicBaseURL; private objects live under privateDir and are only reachable
+// through signed links handled by downloadURL.
+type LocalStorage struct {
+	publicDir     string
+	privateDir    string
+	publicBaseURL string
+	downloadURL   string
+	signer        *URLSigner
+}
+
+// NewLocalStorage builds a disk-backed storage.
+func NewLocalStorage(publicDir, privateDir, publicBaseURL, downloadURL string, signer *URLSigner) *LocalStorage {
+	return &LocalStorage{
+		publicDir:     publicDir,
+		privateDir:    privateDir,
+		publicBaseURL: strings.TrimSuffix(publicBaseURL, "/"),
+		downloadURL:   strings.TrimSuffix(downloadURL, "/"),
+		signer:        signer,
+	}
+}
+
+// Put writes the object to a temporary file first so readers never see partial content.
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	dst, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("make storage dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write object: %w", err)
+	}
+	if size >= 0 && written != size {
+		return fmt.Errorf("write object: expected %d bytes, got %d", size, written)
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return fmt.Errorf("commit object: %w", err)
+	}
+	return nil
+}
+
+// Get opens a stored object.
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	info, err := s.Stat(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	dst, _ := s.resolve(info.Key)
+	file, err := os.Open(dst)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, fmt.Errorf("open object: %w", err)
+	}
+	return file, info, nil
+}
+
+// Stat returns object metadata.
+func (s *LocalStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	dst, err := s.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(dst)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("stat object: %w", err)
+	}
+	if fi.IsDir() {
+		return nil, ErrNotFound
+	}
+	return &ObjectInfo{
+		Key:         key,
+		Size:        fi.Size(),
+		ContentType: contentTypeByKey(key),
+		ETag:        fmt.Sprintf(`"%x-%x"`, fi.ModTime().UnixNano(), fi.Size()),
+		ModTime:     fi.ModTime(),
+	}, nil
+}
+
+// Delete removes a stored object.
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	dst, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(dst); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete object: %w", err)
+	}
+	return nil
+}
+
+// PublicURL maps a public key to its static URL.
+func (s *LocalStorage) PublicURL(key string) string {
+	return s.publicBaseURL + "/" + strings.TrimPrefix(key, PublicPrefix)
+}
+
+// SignedURL returns a download link verified by the file download endpoint.
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if _, err := CleanKey(key); err != nil {
+		return "", err
+	}
+	expires := time.Now().Add(ttl).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.signer.Sign(key, expires))
+	return s.downloadURL + "/" + key + "?" + query.Encode(), nil
+}
+
+func (s *LocalStorage) resolve(key string) (string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(cleaned, PublicPrefix) {
+		return filepath.Join(s.publicDir, filepath.FromSlash(strings.TrimPrefix(cleaned, PublicPrefix))), nil
+	}
+	return filepath.Join(s.privateDir, filepath.FromSlash(strings.TrimPrefix(cleaned, PrivatePrefix))), nil
+}
+
+func contentTypeByKey(key string) string {
+	if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+		return ct
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// URLSigner issues and verifies HMAC signatures for expiring download links.
+type URLSigner struct {
+	secret []byte
+}
+
+// NewURLSigner builds a signer from a shared secret.
+func NewURLSigner(secret string) *URLSigner {
+	return &URLSigner{secret: []byte(secret)}
+}
+
+// Sign returns the signature of key valid until expires (unix seconds).
+func (s *URLSigner) Sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks signature and expiry for key.
+func (s *URLSigner) Verify(key string, expires int64, signature string, now time.Time) error {
+	if now.Unix() > expires {
+		return errors.New("链接已过期")
+	}
+	expected := s.Sign(key, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("签名无效")
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// Key prefixes decide who may read an object: public objects are served directly,
+// private objects (learning materials) only through signed, expiring URLs.
+const (
+	PublicPrefix  = "public/"
+	PrivatePrefix = "private/"
+)
+
+// ErrNotFound is returned when an object does not exist.
+var ErrNotFound = errors.New("对象不存在")
+
+// ErrInvalidKey is returned for keys that are empty, escape the storage root or lack a visibility prefix.
+var ErrInvalidKey = errors.New("无效的存储键")
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ETag        string
+	ModTime     time.Time
+}
+
+// Storage is implemented by every file backend (local disk, S3-compatible object storage).
+type Storage interface {
+	// Put writes size bytes from body under key, replacing any existing object.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get opens an object for reading; callers must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	// Stat returns object metadata or ErrNotFound.
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Delete removes an object; deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// PublicURL returns the direct URL of a public object.
+	PublicURL(key string) string
+	// SignedURL returns a URL granting read access to key until ttl elapses.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// IsPrivateKey reports whether key refers to a non-public object.
+func IsPrivateKey(key string) bool {
+	return strings.HasPrefix(key, PrivatePrefix)
+}
+
+// CleanKey validates a key and returns it in canonical form.
+func CleanKey(key string) (string, error) {
+	if key == "" || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned != key || strings.HasPrefix(cleaned, "..") {
+		return "", ErrInvalidKey
+	}
+	if !strings.HasPrefix(cleaned, PublicPrefix) && !strings.HasPrefix(cleaned, PrivatePrefix) {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
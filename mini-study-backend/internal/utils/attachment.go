@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttachmentWriter streams a file download to the client.
+// Headers are only written with the first byte, so a handler can still
+// reply with a JSON error if generation fails before any output.
+type AttachmentWriter struct {
+	c           *gin.Context
+	filename    string
+	contentType string
+	started     bool
+}
+
+// NewAttachmentWriter builds a writer that downloads as filename.
+func NewAttachmentWriter(c *gin.Context, filename, contentType string) *AttachmentWriter {
+	return &AttachmentWriter{c: c, filename: filename, contentType: contentType}
+}
+
+// Write implements io.Writer.
+func (w *AttachmentWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", w.contentType)
+		w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.filename))
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
+}
+
+// Started reports whether any bytes have been sent.
+func (w *AttachmentWriter) Started() bool {
+	return w.started
+}
@@ -0,0 +1,42 @@
+package utils
+
+import "context"
+
+type contextKey int
+
+const (
+	requestIDContextKey contextKey = iota
+	userIDContextKey
+)
+
+// WithRequestID stores the request id in ctx so that lower layers (e.g. audit logging) can read it.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+// RequestIDFromContext returns the request id stored by WithRequestID, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if rid, ok := ctx.Value(requestIDContextKey).(string); ok {
+		return rid
+	}
+	return ""
+}
+
+// WithUserID stores the authenticated user id in ctx.
+func WithUserID(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, userIDContextKey, userID)
+}
+
+// UserIDFromContext returns the authenticated user id stored by WithUserID, or 0.
+func UserIDFromContext(ctx context.Context) uint {
+	if ctx == nil {
+		return 0
+	}
+	if id, ok := ctx.Value(userIDContextKey).(uint); ok {
+		return id
+	}
+	return 0
+}
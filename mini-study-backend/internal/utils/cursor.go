@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned for cursors that were not produced by EncodeCursor.
+var ErrInvalidCursor = errors.New("非法的分页游标")
+
+// EncodeCursor encodes the sort key of the last row of a feed page, its creation time and ID,
+// into an opaque URL-safe token.
+func EncodeCursor(createdAt time.Time, id uint) string {
+	raw := strconv.FormatInt(createdAt.UnixNano(), 10) + ":" + strconv.FormatUint(uint64(id), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor reverses EncodeCursor. The time is returned in the local time zone, the zone rows
+// are written in.
+func DecodeCursor(cursor string) (time.Time, uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	v, err := strconv.ParseUint(id, 10, 64)
+	if err != nil || v == 0 {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	return time.Unix(0, n), uint(v), nil
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// RedactedValue replaces secret values in audit payloads and diffs.
+const RedactedValue = "***"
+
+// FieldChange is a single field-level difference between two snapshots.
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// diffIgnoredFields are bookkeeping columns that change on every save and carry no business meaning.
+var diffIgnoredFields = map[string]struct{}{
+	"id":         {},
+	"created_at": {},
+	"updated_at": {},
+	"deleted_at": {},
+}
+
+// secretFieldMarkers identify keys whose values must never reach the audit trail.
+var secretFieldMarkers = []string{"password", "secret", "token"}
+
+// IsSecretField reports whether a JSON key holds a credential.
+func IsSecretField(key string) bool {
+	lower := strings.ToLower(key)
+	for _, marker := range secretFieldMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToRedactedJSONString converts payload to JSON like ToJSONString but masks secret fields at any depth.
+func ToRedactedJSONString(payload interface{}) string {
+	if payload == nil {
+		return ""
+	}
+	normalized, ok := normalizeJSON(payload)
+	if !ok {
+		return ""
+	}
+	return ToJSONString(redact(normalized))
+}
+
+// DiffFields compares two snapshots (structs or maps, serialised through their JSON tags)
+// and returns the changed top-level fields sorted by name. A nil snapshot stands for
+// "did not exist", so creations and deletions list every field. Secret fields are masked.
+func DiffFields(before, after interface{}) []FieldChange {
+	beforeMap := toFieldMap(before)
+	afterMap := toFieldMap(after)
+
+	keys := make(map[string]struct{}, len(beforeMap)+len(afterMap))
+	for k := range beforeMap {
+		keys[k] = struct{}{}
+	}
+	for k := range afterMap {
+		keys[k] = struct{}{}
+	}
+
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		if _, ignored := diffIgnoredFields[k]; ignored {
+			continue
+		}
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	changes := make([]FieldChange, 0)
+	for _, name := range names {
+		oldVal, newVal := beforeMap[name], afterMap[name]
+		if reflect.DeepEqual(oldVal, newVal) {
+			continue
+		}
+		if IsSecretField(name) {
+			oldVal, newVal = maskPresent(oldVal), maskPresent(newVal)
+		} else {
+			oldVal, newVal = redact(oldVal), redact(newVal)
+		}
+		changes = append(changes, FieldChange{Field: name, Before: oldVal, After: newVal})
+	}
+	return changes
+}
+
+func toFieldMap(snapshot interface{}) map[string]interface{} {
+	if snapshot == nil {
+		return map[string]interface{}{}
+	}
+	rv := reflect.ValueOf(snapshot)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return map[string]interface{}{}
+	}
+	normalized, ok := normalizeJSON(snapshot)
+	if !ok {
+		return map[string]interface{}{}
+	}
+	fields, ok := normalized.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return fields
+}
+
+// normalizeJSON round-trips v through encoding/json so that structs, maps and
+// primitives compare uniformly (numbers become float64, structs become maps).
+func normalizeJSON(v interface{}) (interface{}, bool) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+func redact(v interface{}) interface{} {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(typed))
+		for k, val := range typed {
+			if IsSecretField(k) {
+				out[k] = maskPresent(val)
+				continue
+			}
+			out[k] = redact(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(typed))
+		for i, val := range typed {
+			out[i] = redact(val)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func maskPresent(v interface{}) interface{} {
+	if v == nil || v == "" {
+		return v
+	}
+	return RedactedValue
+}
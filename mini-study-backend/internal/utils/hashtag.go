@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxHashtagRunes is the longest topic name recognised in a hashtag.
+	MaxHashtagRunes = 20
+	// MaxHashtags caps the number of topics taken from one text.
+	MaxHashtags = 5
+)
+
+// ExtractHashtags returns the distinct topics tagged in text, in order of first appearance.
+// A tag is "#" followed by letters, digits or underscores and may be closed by another "#",
+// so both "#新品上市 今天..." and "#新品上市#今天..." tag 新品上市. ASCII letters are lowercased;
+// tags longer than MaxHashtagRunes are ignored and at most MaxHashtags topics are returned.
+func ExtractHashtags(text string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for i := 0; i < len(text) && len(tags) < MaxHashtags; {
+		if text[i] != '#' {
+			i++
+			continue
+		}
+		start := i + 1
+		end := start
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if !isHashtagRune(r) {
+				break
+			}
+			end += size
+		}
+		i = end
+		if end < len(text) && text[end] == '#' && end > start {
+			i = end + 1 // 跳过闭合的 #，避免被当作下一个话题的开头
+		}
+		tag := NormalizeHashtag(text[start:end])
+		if tag == "" || utf8.RuneCountInString(tag) > MaxHashtagRunes || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// NormalizeHashtag trims a topic name and lowercases its ASCII letters so that "#KPI" and
+// "#kpi" land in the same topic.
+func NormalizeHashtag(name string) string {
+	name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+	name = strings.TrimSuffix(name, "#")
+	return strings.Map(func(r rune) rune {
+		if r < utf8.RuneSelf {
+			return unicode.ToLower(r)
+		}
+		return r
+	}, name)
+}
+
+func isHashtagRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -9,16 +9,19 @@ import (
 
 // Claims represents the JWT payload we use across the app.
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	WorkNo string `json:"work_no"`
+	UserID       uint   `json:"user_id"`
+	WorkNo       string `json:"work_no"`
+	TokenVersion uint   `json:"ver"`
 	jwt.RegisteredClaims
 }
 
 // GenerateToken creates a signed JWT with the provided claims and TTL.
-func GenerateToken(secret string, issuer string, ttl time.Duration, userID uint, workNo string) (string, error) {
+// tokenVersion must match the user's current version, otherwise the token is treated as revoked.
+func GenerateToken(secret string, issuer string, ttl time.Duration, userID uint, workNo string, tokenVersion uint) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		WorkNo: workNo,
+		UserID:       userID,
+		WorkNo:       workNo,
+		TokenVersion: tokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    issuer,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
@@ -0,0 +1,63 @@
+package utils
+
+// Sequence diff operations.
+const (
+	SequenceEqual   = "equal"
+	SequenceRemoved = "removed"
+	SequenceAdded   = "added"
+)
+
+// SequenceOp is one step of the edit script turning sequence a into sequence b. BIndex is -1
+// for removals and AIndex is -1 for additions.
+type SequenceOp struct {
+	Op     string
+	AIndex int
+	BIndex int
+}
+
+// DiffSequences returns a minimal edit script between a and b based on their longest common
+// subsequence. Elements are compared as strings, so callers pass a canonical encoding. Within
+// a hunk removals come before additions.
+func DiffSequences(a, b []string) []SequenceOp {
+	n, m := len(a), len(b)
+	// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]SequenceOp, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, SequenceOp{Op: SequenceEqual, AIndex: i, BIndex: j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, SequenceOp{Op: SequenceRemoved, AIndex: i, BIndex: -1})
+			i++
+		default:
+			ops = append(ops, SequenceOp{Op: SequenceAdded, AIndex: -1, BIndex: j})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, SequenceOp{Op: SequenceRemoved, AIndex: i, BIndex: -1})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, SequenceOp{Op: SequenceAdded, AIndex: -1, BIndex: j})
+	}
+	return ops
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/javapub/mini-study/mini-study-backend/internal/bootstrap"
 	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/moderation"
 	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
 )
 
@@ -34,6 +35,7 @@ func main() {
 	ensureDefaultEmployee(db, logger)
 	ensureDefaultCategories(db, logger)
 	ensureDefaultBanners(db, logger)
+	ensureDefaultSensitiveWords(db, logger)
 
 	logger.Info("database migrated")
 }
@@ -222,3 +224,27 @@ func ensureDefaultBanners(db *gorm.DB, logger *zap.Logger) {
 	}
 	logger.Info("default banner created", zap.String("title", title))
 }
+
+// ensureDefaultSensitiveWords seeds the built-in moderation dictionary into an empty word table.
+// Once administrators maintain the list, their edits and deletions are left alone.
+func ensureDefaultSensitiveWords(db *gorm.DB, logger *zap.Logger) {
+	var count int64
+	if err := db.Unscoped().Model(&model.SensitiveWord{}).Count(&count).Error; err != nil {
+		logger.Error("count sensitive words failed", zap.Error(err))
+		return
+	}
+	if count > 0 {
+		logger.Info("sensitive words already exist, skip seeding")
+		return
+	}
+
+	words := make([]model.SensitiveWord, 0, len(moderation.DefaultWords))
+	for _, w := range moderation.DefaultWords {
+		words = append(words, model.SensitiveWord{Word: w.Text, Category: w.Category, Level: w.Level})
+	}
+	if err := db.Create(&words).Error; err != nil {
+		logger.Error("create default sensitive words failed", zap.Error(err))
+		return
+	}
+	logger.Info("default sensitive words created", zap.Int("count", len(words)))
+}
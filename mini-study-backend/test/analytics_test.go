@@ -0,0 +1,137 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+func newAnalyticsService(db *gorm.DB) *service.AnalyticsService {
+	return service.NewAnalyticsService(repository.NewAnalyticsRepository(db), repository.NewUserRepository(db), repository.NewManagerEmployeeRepository(db))
+}
+
+func analyticsDay(day int) time.Time {
+	return time.Date(2024, 6, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestLearningProgressRecordsDailyActivity(t *testing.T) {
+	db := newTestDB(t)
+	svc := newLearningService(db)
+	ctx := context.Background()
+	user := createUser(t, db, model.RoleEmployee, "E1")
+	video := createContent(t, db, "video", "")
+	if err := db.Model(video).Update("duration_seconds", 600).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 回退播放位置不计入观看时长
+	for _, position := range []int64{100, 250, 200} {
+		if _, err := svc.UpdateProgress(ctx, user.ID, dto.LearningProgressRequest{ContentID: video.ID, VideoPosition: position}); err != nil {
+			t.Fatalf("progress %d: %v", position, err)
+		}
+	}
+	var activities []model.LearningActivity
+	if err := db.Where("user_id = ? AND content_id = ?", user.ID, video.ID).Find(&activities).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(activities) != 1 || activities[0].Updates != 3 || activities[0].WatchSeconds != 250 {
+		t.Fatalf("activities: %+v", activities)
+	}
+}
+
+func TestAdminLearningAnalytics(t *testing.T) {
+	db := newTestDB(t)
+	svc := newAnalyticsService(db)
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	manager := createUser(t, db, model.RoleManager, "M1")
+	watcher := createUser(t, db, model.RoleEmployee, "E1")
+	reader := createUser(t, db, model.RoleEmployee, "E2")
+	bindManager(t, db, manager.ID, watcher.ID)
+	video := createContent(t, db, "video", "")
+	doc := createContent(t, db, "doc", "")
+	exam := &model.ExamPaper{Title: "试卷", Status: "published"}
+	if err := db.Create(exam).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	insert := func(value interface{}) {
+		t.Helper()
+		if err := db.Omit("Exam").Create(value).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 同一内容在两周各学一次，两周都算活跃；管理员的学习不计入
+	insert(&model.LearningActivity{UserID: watcher.ID, ContentID: video.ID, ActivityDate: analyticsDay(3), Updates: 4, WatchSeconds: 120})
+	insert(&model.LearningActivity{UserID: watcher.ID, ContentID: video.ID, ActivityDate: analyticsDay(11), Updates: 2, WatchSeconds: 60})
+	insert(&model.LearningActivity{UserID: reader.ID, ContentID: doc.ID, ActivityDate: analyticsDay(4), Updates: 1})
+	insert(&model.LearningActivity{UserID: admin.ID, ContentID: doc.ID, ActivityDate: analyticsDay(4), Updates: 1})
+	insert(&model.LearningActivity{UserID: reader.ID, ContentID: doc.ID, ActivityDate: analyticsDay(17), Updates: 1})
+	completedAt := analyticsDay(4).Add(10 * time.Hour)
+	insert(&model.LearningRecord{UserID: reader.ID, ContentID: doc.ID, Status: "completed", Progress: 100, CompletedAt: &completedAt})
+	for _, a := range []struct {
+		user  *model.User
+		cycle int
+		at    time.Time
+		pass  bool
+	}{
+		{manager, 1, analyticsDay(5).Add(9 * time.Hour), true},
+		{reader, 1, analyticsDay(12).Add(9 * time.Hour), false},
+		{reader, 2, analyticsDay(12).Add(15 * time.Hour), true},
+	} {
+		at := a.at
+		insert(&model.ExamAttempt{ExamID: exam.ID, UserID: a.user.ID, Cycle: a.cycle, Pass: a.pass, SubmittedAt: &at})
+	}
+	insert(&model.PointTransaction{UserID: watcher.ID, Change: 10, Source: "content", ReferenceID: "1"})
+	insert(&model.PointTransaction{UserID: watcher.ID, Change: -5, Source: "redeem", ReferenceID: "2"})
+	if err := db.Model(&model.PointTransaction{}).Where("user_id = ?", watcher.ID).Update("created_at", analyticsDay(11).Add(8*time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	from, to := analyticsDay(3), analyticsDay(16)
+	query := dto.LearningAnalyticsQuery{From: &from, To: &to, Interval: "week"}
+	resp, err := svc.AdminLearningAnalytics(admin.ID, query)
+	if err != nil {
+		t.Fatalf("analytics: %v", err)
+	}
+	if len(resp.Periods) != 2 || resp.Periods[1] != "2024-06-10" || len(resp.Cohorts) != 1 {
+		t.Fatalf("response: %+v", resp)
+	}
+	all := resp.Cohorts[0]
+	first, second := all.Series[0], all.Series[1]
+	if first.ActiveLearners != 3 || first.Completions != 1 || first.ExamAttempts != 1 || first.VideoLearners != 1 || first.AvgWatchSeconds != 120 {
+		t.Fatalf("first week: %+v", first)
+	}
+	if second.ActiveLearners != 2 || second.ExamAttempts != 2 || second.ExamPassRate != 50 || second.AvgWatchSeconds != 60 || second.PointsEarned != 10 {
+		t.Fatalf("second week: %+v", second)
+	}
+	// 汇总按用户去重：观看视频的只有一人，两周共推进 180 秒
+	if all.Total.ActiveLearners != 3 || all.Total.VideoLearners != 1 || all.Total.AvgWatchSeconds != 180 || all.Total.ExamPassed != 2 {
+		t.Fatalf("total: %+v", all.Total)
+	}
+
+	query.GroupBy = "manager"
+	resp, err = svc.AdminLearningAnalytics(admin.ID, query)
+	if err != nil {
+		t.Fatalf("by manager: %v", err)
+	}
+	if len(resp.Cohorts) != 2 || resp.Cohorts[0].Label != manager.Name || resp.Cohorts[1].Key != "0" {
+		t.Fatalf("manager cohorts: %+v", resp.Cohorts)
+	}
+	if team := resp.Cohorts[0].Total; team.ActiveLearners != 2 || team.PointsEarned != 10 {
+		t.Fatalf("team total: %+v", team)
+	}
+	if unmanaged := resp.Cohorts[1].Total; unmanaged.ActiveLearners != 1 || unmanaged.Completions != 1 {
+		t.Fatalf("unmanaged total: %+v", unmanaged)
+	}
+
+	if _, err := svc.AdminLearningAnalytics(manager.ID, query); err == nil {
+		t.Fatal("manager read the analytics")
+	}
+}
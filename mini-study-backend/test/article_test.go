@@ -0,0 +1,186 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/handler"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
+)
+
+func TestNormalizeArticleBlocks(t *testing.T) {
+	blocks, err := service.NormalizeArticleBlocks([]dto.ArticleBlock{
+		{Type: "heading", Text: " <b>开业检查</b> ", ImagePath: "ignored.png"},
+		{Type: "list", Items: []string{"灯光", " ", "消防<script>alert(1)</script>通道"}},
+		{Type: "attachment", FilePath: "contents/2024/checklist.pdf"},
+		{Type: "callout", Text: "a < b 时注意"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if blocks[0].Text != "开业检查" || blocks[0].Level != 2 || blocks[0].ImagePath != "" {
+		t.Fatalf("unexpected heading: %+v", blocks[0])
+	}
+	if len(blocks[1].Items) != 2 || blocks[1].Items[1] != "消防alert(1)通道" {
+		t.Fatalf("unexpected list: %+v", blocks[1])
+	}
+	if blocks[2].FileName != "checklist.pdf" {
+		t.Fatalf("unexpected attachment: %+v", blocks[2])
+	}
+	if blocks[3].Style != "info" || blocks[3].Text != "a < b 时注意" {
+		t.Fatalf("unexpected callout: %+v", blocks[3])
+	}
+
+	invalid := []dto.ArticleBlock{
+		{Type: "unknown", Text: "x"},
+		{Type: "heading", Text: "x", Level: 4},
+		{Type: "image", ImagePath: "../secret.png"},
+		{Type: "video", VideoPath: "javascript:alert(1)"},
+		{Type: "video", VideoPath: "a.mp4", StartSeconds: 30, EndSeconds: 10},
+		{Type: "quiz", Quiz: &dto.ArticleQuiz{Type: "single", Stem: "q", Options: []dto.ArticleQuizOption{
+			{Content: "a", IsCorrect: true}, {Content: "b", IsCorrect: true},
+		}}},
+	}
+	for _, block := range invalid {
+		if _, err := service.NormalizeArticleBlocks([]dto.ArticleBlock{block}); err == nil {
+			t.Errorf("expected error for %+v", block)
+		}
+	}
+}
+
+func TestArticlePlainTextHidesAnswers(t *testing.T) {
+	blocks := []dto.ArticleBlock{
+		{Type: "text", Text: "第一段"},
+		{Type: "quiz", Quiz: &dto.ArticleQuiz{Type: "single", Stem: "题干", Analysis: "解析", Options: []dto.ArticleQuizOption{
+			{Content: "甲", IsCorrect: true}, {Content: "乙"},
+		}}},
+	}
+	if got := service.ArticlePlainText(blocks); got != "第一段\n题干" {
+		t.Fatalf("unexpected plain text %q", got)
+	}
+	learner := service.LearnerArticleBlocks(blocks)
+	if learner[1].Quiz.Analysis != "" || learner[1].Quiz.Options[0].IsCorrect {
+		t.Fatalf("answers leaked: %+v", learner[1].Quiz)
+	}
+	if blocks[1].Quiz.Analysis != "解析" || !blocks[1].Quiz.Options[0].IsCorrect {
+		t.Fatal("original blocks were modified")
+	}
+}
+
+// createArticle stores a published article with the given blocks.
+func createArticle(t *testing.T, db *gorm.DB, blocks []dto.ArticleBlock) *model.Content {
+	t.Helper()
+	content := createContent(t, db, "article", "")
+	b, err := json.Marshal(blocks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(content).Update("body_blocks_json", string(b)).Error; err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func TestAnswerArticleQuiz(t *testing.T) {
+	db := newTestDB(t)
+	svc := newContentService(db)
+	employee := createUser(t, db, model.RoleEmployee, "E1")
+	content := createArticle(t, db, []dto.ArticleBlock{
+		{Type: "text", Text: "开业检查"},
+		{Type: "quiz", Quiz: &dto.ArticleQuiz{Type: "multiple", Stem: "开业前检查哪些？", Analysis: "通道与灯光都要检查", Options: []dto.ArticleQuizOption{
+			{Content: "消防通道", IsCorrect: true}, {Content: "员工发型"}, {Content: "照明", IsCorrect: true},
+		}}},
+	})
+
+	resp, err := svc.AnswerArticleQuiz(employee.ID, content.ID, 1, dto.ArticleQuizAnswerRequest{OptionIndexes: []int{2, 0, 0}})
+	if err != nil {
+		t.Fatalf("answer: %v", err)
+	}
+	if !resp.Correct || len(resp.CorrectIndexes) != 2 || resp.CorrectIndexes[0] != 0 || resp.CorrectIndexes[1] != 2 || resp.Analysis == "" {
+		t.Fatalf("correct answer: %+v", resp)
+	}
+	// 答错同样返回正确选项与解析，且不留下作答记录
+	if resp, err = svc.AnswerArticleQuiz(employee.ID, content.ID, 1, dto.ArticleQuizAnswerRequest{OptionIndexes: []int{0}}); err != nil || resp.Correct || len(resp.CorrectIndexes) != 2 {
+		t.Fatalf("partial answer: %+v %v", resp, err)
+	}
+	if got := countRows(t, db, &model.CheckpointAnswer{}, "user_id = ?", employee.ID); got != 0 {
+		t.Fatalf("recorded %d answers", got)
+	}
+
+	for _, index := range []int{0, 2} {
+		if _, err := svc.AnswerArticleQuiz(employee.ID, content.ID, index, dto.ArticleQuizAnswerRequest{OptionIndexes: []int{0}}); !errors.Is(err, service.ErrArticleQuizNotFound) {
+			t.Fatalf("block %d: %v", index, err)
+		}
+	}
+	if _, err := svc.AnswerArticleQuiz(employee.ID, content.ID, 1, dto.ArticleQuizAnswerRequest{OptionIndexes: []int{3}}); err == nil {
+		t.Fatal("answered with a missing option")
+	}
+	if err := db.Model(content).Update("status", model.EditorialDraft).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AnswerArticleQuiz(employee.ID, content.ID, 1, dto.ArticleQuizAnswerRequest{OptionIndexes: []int{0}}); !errors.Is(err, service.ErrContentNotPublished) {
+		t.Fatalf("draft: %v", err)
+	}
+}
+
+func TestArticleBlockURLs(t *testing.T) {
+	db := newTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	signer := storage.NewURLSigner("test-secret")
+	store := storage.NewLocalStorage(t.TempDir(), t.TempDir(), "/uploads", "/api/v1/files/download", signer)
+	files := service.NewFileService(store, signer, service.NewAuditService(repository.NewAuditRepository(db), userRepo), 10, time.Minute)
+	h := handler.NewContentHandler(newContentService(db), files, nil, newContentFeedbackService(db))
+
+	employee := createUser(t, db, model.RoleEmployee, "E1")
+	image := storage.PrivatePrefix + "articles/layout.png"
+	content := createArticle(t, db, []dto.ArticleBlock{
+		{Type: "image", ImagePath: image},
+		{Type: "attachment", FilePath: "https://cdn.example.com/checklist.pdf", FileName: "清单"},
+		{Type: "quiz", Quiz: &dto.ArticleQuiz{Type: "single", Stem: "题干", Analysis: "解析", Options: []dto.ArticleQuizOption{
+			{Content: "甲", IsCorrect: true}, {Content: "乙"},
+		}}},
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/contents/:id", func(c *gin.Context) {
+		c.Set("userID", employee.ID)
+	}, h.GetContentDetail)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/contents/"+strconv.FormatUint(uint64(content.ID), 10), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("detail: %d %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Data dto.ContentResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	blocks := body.Data.ArticleBlocks
+	if len(blocks) != 3 {
+		t.Fatalf("blocks = %+v", blocks)
+	}
+	if url := blocks[0].ImageURL; url == "" || url == image || !strings.Contains(url, "signature=") {
+		t.Fatalf("image_url = %q", url)
+	}
+	if blocks[1].FileURL != "https://cdn.example.com/checklist.pdf" {
+		t.Fatalf("file_url = %q", blocks[1].FileURL)
+	}
+	if quiz := blocks[2].Quiz; quiz == nil || quiz.Analysis != "" || quiz.Options[0].IsCorrect {
+		t.Fatalf("quiz answers leaked: %+v", quiz)
+	}
+}
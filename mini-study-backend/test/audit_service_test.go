@@ -0,0 +1,102 @@
+package test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+func TestAuditExportMatchesSearchOrder(t *testing.T) {
+	db := newTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	svc := service.NewAuditService(repository.NewAuditRepository(db), userRepo)
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+
+	// 超过一个导出批次（500 条），确认跨批次时顺序仍一致
+	const total = 1201
+	entries := make([]model.AuditLog, 0, total)
+	for i := 0; i < total; i++ {
+		action := "update_content"
+		if i%3 == 0 {
+			action = "create_content"
+		}
+		entries = append(entries, model.AuditLog{ActorID: admin.ID, Action: action, Target: "contents", Result: "success"})
+	}
+	if err := db.CreateInBatches(entries, 200).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, query := range []dto.AdminAuditLogQuery{{}, {Action: "create_content"}} {
+		var buf bytes.Buffer
+		if err := svc.AdminExportCSV(admin.ID, query, &buf); err != nil {
+			t.Fatalf("export: %v", err)
+		}
+		records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\xEF\xBB\xBF"))).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		exported := make([]uint64, 0, len(records)-1)
+		for _, record := range records[1:] {
+			id, err := strconv.ParseUint(record[0], 10, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			exported = append(exported, id)
+		}
+
+		query.PageSize = 100
+		var searched []uint64
+		for page := 1; ; page++ {
+			query.Page = page
+			resp, err := svc.AdminSearch(admin.ID, query)
+			if err != nil {
+				t.Fatalf("search: %v", err)
+			}
+			for _, item := range resp.Items {
+				searched = append(searched, uint64(item.ID))
+			}
+			if !resp.Pagination.HasMore {
+				break
+			}
+		}
+
+		if len(exported) != len(searched) || len(exported) == 0 {
+			t.Fatalf("action %q: exported %d rows, searched %d", query.Action, len(exported), len(searched))
+		}
+		for i := range exported {
+			if exported[i] != searched[i] {
+				t.Fatalf("action %q: row %d exported id %d, searched id %d", query.Action, i, exported[i], searched[i])
+			}
+			if i > 0 && exported[i] >= exported[i-1] {
+				t.Fatalf("action %q: export not newest first at row %d", query.Action, i)
+			}
+		}
+	}
+}
+
+func TestAuditEntityHistoryRejectsUnknownType(t *testing.T) {
+	db := newTestDB(t)
+	svc := service.NewAuditService(repository.NewAuditRepository(db), repository.NewUserRepository(db))
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	if err := db.Create(&model.AuditLog{ActorID: admin.ID, Action: "update_content", Target: "contents", Result: "success", EntityType: service.AuditEntityContent, EntityID: 7}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := svc.AdminEntityHistory(admin.ID, service.AuditEntityContent, 7, dto.AuditEntityHistoryQuery{})
+	if err != nil {
+		t.Fatalf("known type: %v", err)
+	}
+	if len(resp.Items) != 1 {
+		t.Fatalf("known type returned %d items", len(resp.Items))
+	}
+	if _, err := svc.AdminEntityHistory(admin.ID, "contents", 7, dto.AuditEntityHistoryQuery{}); err == nil {
+		t.Fatal("expected error for unknown entity type")
+	}
+}
@@ -0,0 +1,240 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/certificate"
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
+)
+
+// newCertificateService returns the service and the directory its PDFs are stored in.
+func newCertificateService(t *testing.T, db *gorm.DB) (*service.CertificateService, string) {
+	t.Helper()
+	userRepo := repository.NewUserRepository(db)
+	dir := t.TempDir()
+	store := storage.NewLocalStorage(t.TempDir(), dir, "/uploads", "/api/v1/files/download", storage.NewURLSigner("test-secret"))
+	audit := service.NewAuditService(repository.NewAuditRepository(db), userRepo)
+	return service.NewCertificateService(repository.NewCertificateRepository(db), repository.NewExamAttemptRepository(db), repository.NewExamRepository(db),
+		userRepo, repository.NewManagerEmployeeRepository(db), store, audit, service.CertificateOptions{SerialPrefix: "MS"}), dir
+}
+
+// storedPDFs counts the certificate files written under dir.
+func storedPDFs(t *testing.T, dir string) int {
+	t.Helper()
+	count := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(path, ".pdf") {
+			count++
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestCertificateFillBody(t *testing.T) {
+	data := certificate.Data{
+		HolderName: "李雷",
+		WorkNo:     "E0012",
+		ExamTitle:  "消防安全",
+		Score:      88,
+		TotalScore: 100,
+		PassedAt:   time.Date(2026, 3, 5, 10, 0, 0, 0, time.Local),
+		Serial:     "MS-20260305-ABCDEFGH",
+	}
+	got := certificate.FillBody("{name}/{work_no}/{exam_title}/{score}/{total_score}/{date}/{serial}/{unknown}", data)
+	if got != "李雷/E0012/消防安全/88/100/2026年3月5日/MS-20260305-ABCDEFGH/{unknown}" {
+		t.Fatalf("FillBody = %s", got)
+	}
+	if got := certificate.FillBody("  ", data); !strings.HasPrefix(got, "李雷（工号 E0012）于 2026年3月5日 参加《消防安全》") {
+		t.Fatalf("default body = %s", got)
+	}
+}
+
+func TestCertificateSerial(t *testing.T) {
+	pattern := regexp.MustCompile(`^MS-20261019-[0-9A-HJKMNP-TV-Z]{8}$`)
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		serial, err := certificate.NewSerial("MS", time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pattern.MatchString(serial) {
+			t.Fatalf("unexpected serial %s", serial)
+		}
+		if seen[serial] {
+			t.Fatalf("duplicate serial %s", serial)
+		}
+		seen[serial] = true
+	}
+
+	if got := certificate.NormalizeSerial(" ms-20261019-o1lx9pab \n"); got != "MS-20261019-011X9PAB" {
+		t.Fatalf("NormalizeSerial = %s", got)
+	}
+}
+
+func TestCertificateVerifyLink(t *testing.T) {
+	if got := certificate.VerifyLink("", "MS-1"); got != "" {
+		t.Fatalf("empty base = %s", got)
+	}
+	if got := certificate.VerifyLink("https://a.example.com/verify", "MS-1"); got != "https://a.example.com/verify?serial=MS-1" {
+		t.Fatalf("VerifyLink = %s", got)
+	}
+	if got := certificate.VerifyLink("https://a.example.com/#/verify?from=pdf", "MS-1"); got != "https://a.example.com/#/verify?from=pdf&serial=MS-1" {
+		t.Fatalf("VerifyLink = %s", got)
+	}
+}
+
+func TestCertificateRender(t *testing.T) {
+	for _, orientation := range []string{certificate.Landscape, certificate.Portrait} {
+		var buf bytes.Buffer
+		err := certificate.Render(&buf, certificate.Design{
+			Title:       "合格证书",
+			Issuer:      "培训中心",
+			Orientation: orientation,
+			AccentColor: "not-a-color",
+		}, certificate.Data{HolderName: "李雷", ExamTitle: "消防安全", Score: 90, TotalScore: 100, PassedAt: time.Now(), Serial: "MS-1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		mediaBox := "/MediaBox [0 0 841.89 595.28]"
+		if orientation == certificate.Portrait {
+			mediaBox = "/MediaBox [0 0 595.28 841.89]"
+		}
+		if !bytes.Contains(buf.Bytes(), []byte(mediaBox)) {
+			t.Fatalf("%s certificate missing %s", orientation, mediaBox)
+		}
+	}
+}
+
+func TestCertificateIssue(t *testing.T) {
+	db := newTestDB(t)
+	svc, dir := newCertificateService(t, db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	holder := createUser(t, db, model.RoleEmployee, "E1")
+	bound, plain := createExam(t, db, "消防安全"), createExam(t, db, "食品安全")
+	passedAt := time.Now().Add(-time.Hour)
+
+	attempt := createAttempt(t, db, plain.ID, holder.ID, passedAt, 90, true)
+	if _, err := svc.Issue(ctx, attempt); !errors.Is(err, service.ErrNoCertificateTemplate) {
+		t.Fatalf("issue without templates: %v", err)
+	}
+	failed := createAttempt(t, db, plain.ID, holder.ID, passedAt, 30, false)
+	if _, err := svc.Issue(ctx, failed); err == nil {
+		t.Fatal("issued a certificate for a failed attempt")
+	}
+
+	fallback, err := svc.CreateTemplate(ctx, admin.ID, dto.CertificateTemplateRequest{Name: "默认", Title: "合格证书"})
+	if err != nil {
+		t.Fatalf("create default template: %v", err)
+	}
+	own, err := svc.CreateTemplate(ctx, admin.ID, dto.CertificateTemplateRequest{Name: "消防", ExamID: bound.ID, Title: "消防合格证"})
+	if err != nil {
+		t.Fatalf("create exam template: %v", err)
+	}
+
+	// 试卷没有专属模板时使用默认模板，再次颁发返回同一张证书
+	issued, err := svc.Issue(ctx, attempt)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if issued.TemplateID != fallback.ID || issued.HolderName != holder.Name || issued.ExamTitle != plain.Title || !strings.HasPrefix(issued.Serial, "MS-") {
+		t.Fatalf("issued: %+v", issued)
+	}
+	again, err := svc.Issue(ctx, attempt)
+	if err != nil || again.ID != issued.ID || again.Serial != issued.Serial {
+		t.Fatalf("reissue: %+v %v", again, err)
+	}
+	if got := countRows(t, db, &model.Certificate{}, "attempt_id = ?", attempt.ID); got != 1 {
+		t.Fatalf("certificates = %d, want 1", got)
+	}
+	boundAttempt := createAttempt(t, db, bound.ID, holder.ID, passedAt, 95, true)
+	if issued, err := svc.Issue(ctx, boundAttempt); err != nil || issued.TemplateID != own.ID {
+		t.Fatalf("issue with exam template: %+v %v", issued, err)
+	}
+	if got := storedPDFs(t, dir); got != 2 {
+		t.Fatalf("stored PDFs = %d, want 2", got)
+	}
+
+	// 并发颁发：写入前另一请求已保存证书，以先写入的为准并删除多余的文件
+	raced := createAttempt(t, db, bound.ID, holder.ID, passedAt, 85, true)
+	rival := &model.Certificate{Serial: "MS-RIVAL", AttemptID: raced.ID, UserID: holder.ID, ExamID: bound.ID, IssuedAt: passedAt}
+	inserted := false
+	if err := db.Callback().Create().Before("gorm:begin_transaction").Register("test:certificate_race", func(tx *gorm.DB) {
+		if inserted || tx.Statement.Table != "certificates" {
+			return
+		}
+		inserted = true
+		if err := db.Create(rival).Error; err != nil {
+			t.Errorf("insert rival certificate: %v", err)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	winner, err := svc.Issue(ctx, raced)
+	if err != nil {
+		t.Fatalf("issue during race: %v", err)
+	}
+	if winner.ID != rival.ID || winner.Serial != "MS-RIVAL" {
+		t.Fatalf("race winner: %+v", winner)
+	}
+	if got := storedPDFs(t, dir); got != 2 {
+		t.Fatalf("stored PDFs after race = %d, want 2", got)
+	}
+}
+
+func TestCertificateOpenPermissions(t *testing.T) {
+	db := newTestDB(t)
+	svc, _ := newCertificateService(t, db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	holder := createUser(t, db, model.RoleEmployee, "E1")
+	colleague := createUser(t, db, model.RoleEmployee, "E2")
+	manager := createUser(t, db, model.RoleManager, "M1")
+	otherManager := createUser(t, db, model.RoleManager, "M2")
+	bindManager(t, db, manager.ID, holder.ID)
+	bindManager(t, db, otherManager.ID, colleague.ID)
+	exam := createExam(t, db, "消防安全")
+	if _, err := svc.CreateTemplate(ctx, admin.ID, dto.CertificateTemplateRequest{Name: "默认"}); err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	attempt := createAttempt(t, db, exam.ID, holder.ID, time.Now(), 90, true)
+
+	for _, viewer := range []*model.User{holder, manager, admin} {
+		body, info, issued, err := svc.Open(ctx, viewer.ID, attempt.ID)
+		if err != nil {
+			t.Fatalf("open as %s: %v", viewer.WorkNo, err)
+		}
+		data, _ := io.ReadAll(body)
+		body.Close()
+		if !bytes.HasPrefix(data, []byte("%PDF")) || info.Size != int64(len(data)) || issued.AttemptID != attempt.ID {
+			t.Fatalf("open as %s: %d bytes, %+v", viewer.WorkNo, len(data), issued)
+		}
+	}
+	for _, viewer := range []*model.User{colleague, otherManager} {
+		if _, _, _, err := svc.Open(ctx, viewer.ID, attempt.ID); !errors.Is(err, service.ErrCertificateForbidden) {
+			t.Fatalf("open as %s: %v", viewer.WorkNo, err)
+		}
+	}
+	if _, _, _, err := svc.Open(ctx, holder.ID, attempt.ID+100); !errors.Is(err, service.ErrCertificateAttemptNotFound) {
+		t.Fatalf("open missing attempt: %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+func newCertificationService(db *gorm.DB) *service.CertificationService {
+	userRepo := repository.NewUserRepository(db)
+	return service.NewCertificationService(repository.NewCertificationRepository(db), repository.NewExamAttemptRepository(db),
+		repository.NewExamRepository(db), userRepo, repository.NewManagerEmployeeRepository(db),
+		service.NewNotificationService(repository.NewNotificationRepository(db)), repository.NewSchedulerLeaseRepository(db),
+		service.NewAuditService(repository.NewAuditRepository(db), userRepo), time.Minute)
+}
+
+// createSingleChoice adds a single choice question worth the exam's total score and returns the
+// answer that passes it.
+func createSingleChoice(t *testing.T, db *gorm.DB, exam *model.ExamPaper) dto.ExamSubmitRequest {
+	t.Helper()
+	question := &model.ExamQuestion{ExamID: exam.ID, Type: "single", Stem: "题目", Score: exam.TotalScore, Options: []model.ExamOption{
+		{Label: "A", Content: "正确", IsCorrect: true},
+		{Label: "B", Content: "错误"},
+	}}
+	if err := db.Create(question).Error; err != nil {
+		t.Fatalf("create question: %v", err)
+	}
+	return dto.ExamSubmitRequest{Answers: []dto.ExamSubmitAnswer{{QuestionID: question.ID, OptionIDs: []uint{question.Options[0].ID}}}}
+}
+
+func userCertification(t *testing.T, db *gorm.DB, certificationID, userID uint) *model.UserCertification {
+	t.Helper()
+	var held model.UserCertification
+	if err := db.Where("certification_id = ? AND user_id = ?", certificationID, userID).Limit(1).Find(&held).Error; err != nil {
+		t.Fatal(err)
+	}
+	if held.ID == 0 {
+		return nil
+	}
+	return &held
+}
+
+func TestCertificationExpiry(t *testing.T) {
+	passedAt := time.Date(2026, 3, 15, 10, 30, 0, 0, time.Local)
+	if got, want := service.CertificationExpiry(passedAt, 12), time.Date(2027, 3, 15, 10, 30, 0, 0, time.Local); !got.Equal(want) {
+		t.Errorf("12 months: got %v, want %v", got, want)
+	}
+	if got, want := service.CertificationExpiry(passedAt, 6), time.Date(2026, 9, 15, 10, 30, 0, 0, time.Local); !got.Equal(want) {
+		t.Errorf("6 months: got %v, want %v", got, want)
+	}
+
+	expiresAt := service.CertificationExpiry(passedAt, 12)
+	if got, want := service.CertificationRenewalOpensAt(expiresAt, 30), time.Date(2027, 2, 13, 10, 30, 0, 0, time.Local); !got.Equal(want) {
+		t.Errorf("renewal opens: got %v, want %v", got, want)
+	}
+	if got := service.CertificationRenewalOpensAt(expiresAt, 0); !got.Equal(expiresAt) {
+		t.Errorf("no window: got %v, want %v", got, expiresAt)
+	}
+}
+
+func TestCertificationStatus(t *testing.T) {
+	expiresAt := time.Date(2027, 3, 15, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name   string
+		now    time.Time
+		window int
+		want   string
+	}{
+		{name: "well before window", now: expiresAt.AddDate(0, -6, 0), window: 30, want: service.CertificationValid},
+		{name: "just before window", now: expiresAt.AddDate(0, 0, -30).Add(-time.Second), window: 30, want: service.CertificationValid},
+		{name: "window opens", now: expiresAt.AddDate(0, 0, -30), window: 30, want: service.CertificationExpiring},
+		{name: "inside window", now: expiresAt.Add(-time.Hour), window: 30, want: service.CertificationExpiring},
+		{name: "expiry moment", now: expiresAt, window: 30, want: service.CertificationExpired},
+		{name: "after expiry", now: expiresAt.AddDate(0, 1, 0), window: 30, want: service.CertificationExpired},
+		{name: "no window stays valid", now: expiresAt.Add(-time.Second), window: 0, want: service.CertificationValid},
+	}
+	for _, tc := range cases {
+		if got := service.CertificationStatus(expiresAt, tc.window, tc.now); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestExamSubmitRetakeCycles(t *testing.T) {
+	db := newTestDB(t)
+	exams := newExamService(t, db)
+	certs := newCertificationService(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	holder := createUser(t, db, model.RoleEmployee, "E1")
+	racer := createUser(t, db, model.RoleEmployee, "E2")
+	exam := createExam(t, db, "食品安全考试")
+	answers := createSingleChoice(t, db, exam)
+
+	// 未关联认证的试卷只能参加一次
+	if _, err := exams.SubmitExam(ctx, holder.ID, exam.ID, answers); err != nil {
+		t.Fatalf("first submit: %v", err)
+	}
+	if _, err := exams.SubmitExam(ctx, holder.ID, exam.ID, answers); !errors.Is(err, service.ErrExamAlreadyTaken) {
+		t.Fatalf("second submit: %v", err)
+	}
+
+	// 并发提交：检查之后另一请求已写入同一轮次，由唯一索引拦下
+	inserted := false
+	if err := db.Callback().Create().Before("gorm:begin_transaction").Register("test:attempt_race", func(tx *gorm.DB) {
+		if inserted || tx.Statement.Table != "exam_attempts" {
+			return
+		}
+		inserted = true
+		createAttempt(t, db, exam.ID, racer.ID, time.Now(), 0, false)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := exams.SubmitExam(ctx, racer.ID, exam.ID, answers); !errors.Is(err, service.ErrExamAlreadyTaken) {
+		t.Fatalf("racing submit: %v", err)
+	}
+	if got := countRows(t, db, &model.ExamAttempt{}, "user_id = ?", racer.ID); got != 1 {
+		t.Fatalf("racer attempts = %d, want 1", got)
+	}
+
+	// 关联认证后，进入重新认证窗口即可重考，重考记为下一轮次并续期认证
+	window := 30
+	created, err := certs.Create(ctx, admin.ID, dto.CertificationRequest{Name: "食品安全认证", ExamID: exam.ID, ValidityMonths: 12, RenewalWindowDays: &window})
+	if err != nil {
+		t.Fatalf("create certification: %v", err)
+	}
+	if _, err := exams.SubmitExam(ctx, holder.ID, exam.ID, answers); !errors.Is(err, service.ErrExamAlreadyTaken) {
+		t.Fatalf("submit before the window: %v", err)
+	}
+	if err := db.Model(&model.UserCertification{}).Where("user_id = ?", holder.ID).Update("expires_at", time.Now().AddDate(0, 0, 10)).Error; err != nil {
+		t.Fatal(err)
+	}
+	resp, err := exams.SubmitExam(ctx, holder.ID, exam.ID, answers)
+	if err != nil {
+		t.Fatalf("retake: %v", err)
+	}
+	if resp.CertificationExpiresAt == nil || resp.CertificationExpiresAt.Before(time.Now().AddDate(1, 0, -1)) {
+		t.Fatalf("renewed expiry: %v", resp.CertificationExpiresAt)
+	}
+	var retake model.ExamAttempt
+	if err := db.First(&retake, resp.AttemptID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if retake.Cycle != 2 {
+		t.Fatalf("retake cycle = %d, want 2", retake.Cycle)
+	}
+	if held := userCertification(t, db, created.ID, holder.ID); held == nil || held.AttemptID != retake.ID {
+		t.Fatalf("renewed certification: %+v", held)
+	}
+	if _, err := exams.SubmitExam(ctx, holder.ID, exam.ID, answers); !errors.Is(err, service.ErrExamAlreadyTaken) {
+		t.Fatalf("submit after renewal: %v", err)
+	}
+}
+
+func TestCertificationSyncAndRetake(t *testing.T) {
+	db := newTestDB(t)
+	svc := newCertificationService(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	passed := createUser(t, db, model.RoleEmployee, "E1")
+	failed := createUser(t, db, model.RoleEmployee, "E2")
+	exam := createExam(t, db, "安全考试")
+	other := createExam(t, db, "普通考试")
+	passedAt := time.Date(2026, 3, 15, 10, 0, 0, 0, time.Local)
+	first := createAttempt(t, db, exam.ID, passed.ID, passedAt.AddDate(-1, 0, 0), 80, true)
+	latest := createAttempt(t, db, exam.ID, passed.ID, passedAt, 90, true)
+	createAttempt(t, db, exam.ID, failed.ID, passedAt, 30, false)
+
+	// 创建认证时按每人最近一次通过记录授予
+	window := 30
+	req := dto.CertificationRequest{Name: "安全认证", ExamID: exam.ID, ValidityMonths: 12, RenewalWindowDays: &window}
+	created, err := svc.Create(ctx, admin.ID, req)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.HolderCount != 1 {
+		t.Fatalf("holders = %d, want 1", created.HolderCount)
+	}
+	held := userCertification(t, db, created.ID, passed.ID)
+	if held == nil || held.AttemptID != latest.ID || !held.ExpiresAt.Equal(passedAt.AddDate(1, 0, 0)) {
+		t.Fatalf("granted certification: %+v (first attempt %d)", held, first.ID)
+	}
+	if userCertification(t, db, created.ID, failed.ID) != nil {
+		t.Fatal("failed user certified")
+	}
+
+	// 修改有效期后按原通过时间重新计算到期时间
+	req.ValidityMonths = 6
+	if _, err := svc.Update(ctx, admin.ID, created.ID, req); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if held := userCertification(t, db, created.ID, passed.ID); held == nil || !held.ExpiresAt.Equal(passedAt.AddDate(0, 6, 0)) {
+		t.Fatalf("recomputed certification: %+v", held)
+	}
+
+	expiresAt := passedAt.AddDate(0, 6, 0)
+	cases := []struct {
+		name   string
+		userID uint
+		examID uint
+		now    time.Time
+		want   bool
+	}{
+		{name: "exam without certification", userID: passed.ID, examID: other.ID, now: expiresAt, want: false},
+		{name: "user not certified", userID: failed.ID, examID: exam.ID, now: expiresAt, want: false},
+		{name: "before the window", userID: passed.ID, examID: exam.ID, now: expiresAt.AddDate(0, 0, -31), want: false},
+		{name: "window opens", userID: passed.ID, examID: exam.ID, now: expiresAt.AddDate(0, 0, -30), want: true},
+		{name: "after expiry", userID: passed.ID, examID: exam.ID, now: expiresAt.AddDate(0, 1, 0), want: true},
+	}
+	for _, tc := range cases {
+		got, err := svc.CanRetake(tc.userID, tc.examID, tc.now)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	// 只有通过关联试卷的记录才续期认证
+	if held, err := svc.RecordPass(&model.ExamAttempt{ExamID: exam.ID, UserID: failed.ID}); err != nil || held != nil {
+		t.Fatalf("failed attempt: %+v, %v", held, err)
+	}
+	if held, err := svc.RecordPass(&model.ExamAttempt{ExamID: other.ID, UserID: failed.ID, Pass: true}); err != nil || held != nil {
+		t.Fatalf("exam without certification: %+v, %v", held, err)
+	}
+	renewedAt := expiresAt.AddDate(0, 0, -10)
+	renewal := createAttempt(t, db, exam.ID, passed.ID, renewedAt, 95, true)
+	renewed, err := svc.RecordPass(renewal)
+	if err != nil {
+		t.Fatalf("record pass: %v", err)
+	}
+	if renewed == nil || !renewed.ExpiresAt.Equal(renewedAt.AddDate(0, 6, 0)) {
+		t.Fatalf("renewed: %+v", renewed)
+	}
+	if held := userCertification(t, db, created.ID, passed.ID); held == nil || held.AttemptID != renewal.ID {
+		t.Fatalf("stored renewal: %+v", held)
+	}
+	if got := countRows(t, db, &model.UserCertification{}, "certification_id = ?", created.ID); got != 1 {
+		t.Fatalf("holder rows = %d, want 1", got)
+	}
+
+	// 改为关联其他试卷时清除原试卷的持有记录
+	req.ExamID = other.ID
+	updated, err := svc.Update(ctx, admin.ID, created.ID, req)
+	if err != nil {
+		t.Fatalf("relink: %v", err)
+	}
+	if updated.HolderCount != 0 || countRows(t, db, &model.UserCertification{}, "certification_id = ?", created.ID) != 0 {
+		t.Fatalf("holders after relinking: %d", updated.HolderCount)
+	}
+}
+
+func TestCertificationSendReminders(t *testing.T) {
+	db := newTestDB(t)
+	svc := newCertificationService(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	expiring := createUser(t, db, model.RoleEmployee, "E1")
+	expired := createUser(t, db, model.RoleEmployee, "E2")
+	valid := createUser(t, db, model.RoleEmployee, "E3")
+	exam := createExam(t, db, "安全考试")
+	now := time.Now()
+	createAttempt(t, db, exam.ID, expiring.ID, now.AddDate(0, -12, 10), 90, true)
+	createAttempt(t, db, exam.ID, expired.ID, now.AddDate(0, -12, -1), 90, true)
+	createAttempt(t, db, exam.ID, valid.ID, now.AddDate(0, -1, 0), 90, true)
+
+	window := 30
+	if _, err := svc.Create(ctx, admin.ID, dto.CertificationRequest{Name: "安全认证", ExamID: exam.ID, ValidityMonths: 12, RenewalWindowDays: &window}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	sent, err := svc.SendReminders(now)
+	if err != nil {
+		t.Fatalf("send reminders: %v", err)
+	}
+	if sent != 2 {
+		t.Fatalf("sent %d reminders, want 2", sent)
+	}
+	if got := countRows(t, db, &model.Notification{}, "user_id = ? AND type = ?", expiring.ID, service.NotificationCertificationExpiring); got != 1 {
+		t.Fatalf("renewal reminders = %d, want 1", got)
+	}
+	if got := countRows(t, db, &model.Notification{}, "user_id = ? AND type = ?", expired.ID, service.NotificationCertificationExpired); got != 1 {
+		t.Fatalf("expiry reminders = %d, want 1", got)
+	}
+	if got := countRows(t, db, &model.Notification{}, "user_id = ?", valid.ID); got != 0 {
+		t.Fatalf("valid holder reminded %d times", got)
+	}
+
+	// 每次通过只提醒一次
+	if sent, err := svc.SendReminders(now.Add(time.Hour)); err != nil || sent != 0 {
+		t.Fatalf("second run sent %d: %v", sent, err)
+	}
+}
@@ -0,0 +1,196 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+func newCheckpointServices(db *gorm.DB) (*service.CheckpointService, *service.LearningService, *service.PointService) {
+	userRepo := repository.NewUserRepository(db)
+	contentRepo := repository.NewContentRepository(db)
+	checkpointRepo := repository.NewContentCheckpointRepository(db)
+	answerRepo := repository.NewCheckpointAnswerRepository(db)
+	audit := service.NewAuditService(repository.NewAuditRepository(db), userRepo)
+	points := service.NewPointService(repository.NewPointRepository(db), userRepo, audit)
+	learning := service.NewLearningService(repository.NewLearningRecordRepository(db), repository.NewLearningPageViewRepository(db),
+		checkpointRepo, answerRepo, contentRepo, userRepo, points, service.LearningOptions{DocCompletionPercent: 80})
+	return service.NewCheckpointService(checkpointRepo, answerRepo, contentRepo, userRepo, learning, points, audit), learning, points
+}
+
+func singleChoice(position int64, correct, points int) dto.AdminCheckpointUpsertRequest {
+	options := []dto.ArticleQuizOption{{Content: "选项A"}, {Content: "选项B"}, {Content: "选项C"}}
+	options[correct].IsCorrect = true
+	return dto.AdminCheckpointUpsertRequest{Position: position, QuestionType: "single", Stem: "题干", Options: options, Analysis: "解析", Points: points}
+}
+
+func userPoints(t *testing.T, points *service.PointService, userID uint) int64 {
+	t.Helper()
+	totals, err := points.GetTotalsMap([]uint{userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return totals[userID]
+}
+
+func TestCheckpointGrading(t *testing.T) {
+	db := newTestDB(t)
+	svc, _, points := newCheckpointServices(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	learner := createUser(t, db, model.RoleEmployee, "E1")
+	content := createContent(t, db, "article", "")
+
+	single, err := svc.AdminCreate(ctx, admin.ID, content.ID, singleChoice(0, 1, 5))
+	if err != nil {
+		t.Fatalf("create single: %v", err)
+	}
+	multiple, err := svc.AdminCreate(ctx, admin.ID, content.ID, dto.AdminCheckpointUpsertRequest{
+		QuestionType: "multiple", Stem: "多选", Points: 3,
+		Options: []dto.ArticleQuizOption{{Content: "A", IsCorrect: true}, {Content: "B"}, {Content: "C", IsCorrect: true}},
+	})
+	if err != nil {
+		t.Fatalf("create multiple: %v", err)
+	}
+
+	// 答错不返回答案；之后答对通过，但不是首次作答，不奖励积分
+	wrong, err := svc.Answer(ctx, learner.ID, content.ID, single.ID, dto.CheckpointAnswerRequest{OptionIndexes: []int{0}})
+	if err != nil {
+		t.Fatalf("wrong answer: %v", err)
+	}
+	if wrong.Correct || wrong.Passed || wrong.CorrectIndexes != nil || wrong.Analysis != "" {
+		t.Fatalf("wrong answer revealed or passed: %+v", wrong)
+	}
+	right, err := svc.Answer(ctx, learner.ID, content.ID, single.ID, dto.CheckpointAnswerRequest{OptionIndexes: []int{1}})
+	if err != nil {
+		t.Fatalf("right answer: %v", err)
+	}
+	if !right.Passed || right.Attempts != 2 || right.PointsAwarded != 0 || len(right.CorrectIndexes) != 1 || right.Analysis != "解析" {
+		t.Fatalf("right answer after retry: %+v", right)
+	}
+
+	// 已通过后再答错不会变回未通过
+	again, err := svc.Answer(ctx, learner.ID, content.ID, single.ID, dto.CheckpointAnswerRequest{OptionIndexes: []int{2}})
+	if err != nil {
+		t.Fatalf("answer after pass: %v", err)
+	}
+	if again.Correct || !again.Passed {
+		t.Fatalf("answer after pass: %+v", again)
+	}
+
+	// 多选题顺序无关、重复选项去重，漏选算错
+	if resp, err := svc.Answer(ctx, learner.ID, content.ID, multiple.ID, dto.CheckpointAnswerRequest{OptionIndexes: []int{0}}); err != nil || resp.Correct {
+		t.Fatalf("partial multiple answer: %+v %v", resp, err)
+	}
+	other := createUser(t, db, model.RoleEmployee, "E2")
+	first, err := svc.Answer(ctx, other.ID, content.ID, multiple.ID, dto.CheckpointAnswerRequest{OptionIndexes: []int{2, 0, 2}})
+	if err != nil {
+		t.Fatalf("multiple answer: %v", err)
+	}
+	if !first.Correct || first.PointsAwarded != 3 {
+		t.Fatalf("first correct answer: %+v", first)
+	}
+	if got := userPoints(t, points, other.ID); got != 3 {
+		t.Fatalf("points after first correct answer = %d", got)
+	}
+	if got := userPoints(t, points, learner.ID); got != 0 {
+		t.Fatalf("points after retries = %d", got)
+	}
+
+	if _, err := svc.Answer(ctx, learner.ID, content.ID, single.ID, dto.CheckpointAnswerRequest{OptionIndexes: []int{3}}); err == nil {
+		t.Fatal("accepted an option that does not exist")
+	}
+}
+
+func TestCheckpointPassIsRecordedOnce(t *testing.T) {
+	db := newTestDB(t)
+	answers := repository.NewCheckpointAnswerRepository(db)
+
+	_, newlyPassed, err := answers.RecordAttempt(1, 2, 3, "0", false)
+	if err != nil || newlyPassed {
+		t.Fatalf("wrong attempt: newly=%v err=%v", newlyPassed, err)
+	}
+	answer, newlyPassed, err := answers.RecordAttempt(1, 2, 3, "1", true)
+	if err != nil || !newlyPassed || !answer.Passed || answer.PassedAt == nil || answer.Attempts != 2 {
+		t.Fatalf("passing attempt: %+v newly=%v err=%v", answer, newlyPassed, err)
+	}
+	answer, newlyPassed, err = answers.RecordAttempt(1, 2, 3, "1", true)
+	if err != nil || newlyPassed || !answer.Passed || answer.Attempts != 3 {
+		t.Fatalf("repeated pass: %+v newly=%v err=%v", answer, newlyPassed, err)
+	}
+}
+
+func TestCheckpointGatesVideoProgress(t *testing.T) {
+	db := newTestDB(t)
+	svc, learning, _ := newCheckpointServices(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	learner := createUser(t, db, model.RoleEmployee, "E1")
+	video := createContent(t, db, "video", "/uploads/a.mp4")
+	if err := db.Model(video).Update("duration_seconds", 100).Error; err != nil {
+		t.Fatal(err)
+	}
+	checkpoint, err := svc.AdminCreate(ctx, admin.ID, video.ID, singleChoice(30, 0, 0))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// 未答对检查点时，上报位置不会越过检查点，也不能完成
+	progress, err := learning.UpdateProgress(ctx, learner.ID, dto.LearningProgressRequest{ContentID: video.ID, VideoPosition: 100})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if progress.VideoPosition != 30 || progress.Status == "completed" || progress.PendingCheckpointID != checkpoint.ID {
+		t.Fatalf("progress past a pending checkpoint: %+v", progress)
+	}
+
+	resp, err := svc.Answer(ctx, learner.ID, video.ID, checkpoint.ID, dto.CheckpointAnswerRequest{OptionIndexes: []int{0}})
+	if err != nil {
+		t.Fatalf("answer: %v", err)
+	}
+	if resp.Progress == nil || resp.Progress.CheckpointPassed != 1 || resp.Progress.PendingCheckpointID != 0 {
+		t.Fatalf("progress not refreshed after passing: %+v", resp.Progress)
+	}
+
+	progress, err = learning.UpdateProgress(ctx, learner.ID, dto.LearningProgressRequest{ContentID: video.ID, VideoPosition: 100})
+	if err != nil {
+		t.Fatalf("update after pass: %v", err)
+	}
+	if progress.VideoPosition != 100 || progress.Status != "completed" {
+		t.Fatalf("progress after passing: %+v", progress)
+	}
+}
+
+func TestCheckpointGatesArticleCompletion(t *testing.T) {
+	db := newTestDB(t)
+	svc, learning, _ := newCheckpointServices(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	learner := createUser(t, db, model.RoleEmployee, "E1")
+	article := createContent(t, db, "article", "")
+	checkpoint, err := svc.AdminCreate(ctx, admin.ID, article.ID, singleChoice(0, 2, 0))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	progress, err := learning.UpdateProgress(ctx, learner.ID, dto.LearningProgressRequest{ContentID: article.ID})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if progress.Status == "completed" || progress.CheckpointTotal != 1 {
+		t.Fatalf("article completed with a pending checkpoint: %+v", progress)
+	}
+	resp, err := svc.Answer(ctx, learner.ID, article.ID, checkpoint.ID, dto.CheckpointAnswerRequest{OptionIndexes: []int{2}})
+	if err != nil {
+		t.Fatalf("answer: %v", err)
+	}
+	if resp.Progress == nil || resp.Progress.Status != "completed" {
+		t.Fatalf("article not completed after passing: %+v", resp.Progress)
+	}
+}
@@ -0,0 +1,232 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+func newContentFeedbackService(db *gorm.DB) *service.ContentFeedbackService {
+	userRepo := repository.NewUserRepository(db)
+	audit := service.NewAuditService(repository.NewAuditRepository(db), userRepo)
+	return service.NewContentFeedbackService(repository.NewContentRatingRepository(db), repository.NewContentCommentRepository(db),
+		repository.NewContentRepository(db), userRepo, newLearningService(db), audit)
+}
+
+func TestContentRatingModeration(t *testing.T) {
+	db := newTestDB(t)
+	svc := newContentFeedbackService(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	learner := createUser(t, db, model.RoleEmployee, "E1")
+	other := createUser(t, db, model.RoleEmployee, "E2")
+	content := createContent(t, db, "doc", "")
+
+	// 只评星不写评价无需审核
+	rated, err := svc.Rate(ctx, other.ID, content.ID, dto.ContentRatingRequest{Rating: 2})
+	if err != nil {
+		t.Fatalf("rate without review: %v", err)
+	}
+	if rated.Status != service.FeedbackStatusApproved {
+		t.Fatalf("rating without review status = %s", rated.Status)
+	}
+	if _, err := svc.AdminModerateRating(ctx, admin.ID, rated.ID, true); err == nil {
+		t.Fatal("moderated a rating without review")
+	}
+
+	rating, err := svc.Rate(ctx, learner.ID, content.ID, dto.ContentRatingRequest{Rating: 4, Review: "  讲解清晰  "})
+	if err != nil {
+		t.Fatalf("rate: %v", err)
+	}
+	if rating.Status != service.FeedbackStatusPending || rating.Review != "讲解清晰" {
+		t.Fatalf("new review: %+v", rating)
+	}
+	reviews, err := svc.ListReviews(learner.ID, content.ID)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(reviews.Reviews) != 0 || reviews.Mine == nil || reviews.Mine.Status != service.FeedbackStatusPending {
+		t.Fatalf("pending review visible or mine missing: %+v", reviews)
+	}
+	// 待审核的评价不公开，但星级立即计入汇总
+	if reviews.Summary.Count != 2 || reviews.Summary.Average != 3 {
+		t.Fatalf("summary: %+v", reviews.Summary)
+	}
+
+	if _, err := svc.AdminModerateRating(ctx, learner.ID, rating.ID, true); err == nil {
+		t.Fatal("learner moderated a rating")
+	}
+	if _, err := svc.AdminModerateRating(ctx, admin.ID, rating.ID, true); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if reviews, _ := svc.ListReviews(other.ID, content.ID); len(reviews.Reviews) != 1 {
+		t.Fatalf("approved review not listed: %+v", reviews.Reviews)
+	}
+
+	// 只改星级保留审核结果，改评价文字重新进入审核
+	updated, err := svc.Rate(ctx, learner.ID, content.ID, dto.ContentRatingRequest{Rating: 5, Review: "讲解清晰"})
+	if err != nil {
+		t.Fatalf("change stars: %v", err)
+	}
+	if updated.ID != rating.ID || updated.Rating != 5 || updated.Status != service.FeedbackStatusApproved {
+		t.Fatalf("stars changed: %+v", updated)
+	}
+	updated, err = svc.Rate(ctx, learner.ID, content.ID, dto.ContentRatingRequest{Rating: 5, Review: "改了评价"})
+	if err != nil {
+		t.Fatalf("change review: %v", err)
+	}
+	if updated.ID != rating.ID || updated.Status != service.FeedbackStatusPending {
+		t.Fatalf("review changed: %+v", updated)
+	}
+
+	rejected, err := svc.AdminModerateRating(ctx, admin.ID, rating.ID, false)
+	if err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	if rejected.Status != service.FeedbackStatusRejected {
+		t.Fatalf("rejected status = %s", rejected.Status)
+	}
+	reviews, err = svc.ListReviews(other.ID, content.ID)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(reviews.Reviews) != 0 || reviews.Summary.Count != 2 || reviews.Summary.Average != 3.5 {
+		t.Fatalf("after rejection: %+v", reviews)
+	}
+}
+
+func TestContentRatingAggregates(t *testing.T) {
+	db := newTestDB(t)
+	svc := newContentFeedbackService(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	users := []*model.User{createUser(t, db, model.RoleEmployee, "E1"), createUser(t, db, model.RoleEmployee, "E2"), createUser(t, db, model.RoleEmployee, "E3")}
+	good := createContent(t, db, "doc", "")
+	poor := createContent(t, db, "doc", "")
+	single := createContent(t, db, "doc", "")
+	unrated := createContent(t, db, "doc", "")
+
+	rate := func(user *model.User, content *model.Content, stars int) {
+		t.Helper()
+		if _, err := svc.Rate(ctx, user.ID, content.ID, dto.ContentRatingRequest{Rating: stars}); err != nil {
+			t.Fatalf("rate: %v", err)
+		}
+	}
+	rate(users[0], good, 5)
+	rate(users[1], good, 4)
+	rate(users[2], good, 4)
+	rate(users[0], poor, 2)
+	rate(users[1], poor, 1)
+	rate(users[0], single, 3)
+
+	items := []dto.ContentResponse{{ID: good.ID}, {ID: poor.ID}, {ID: unrated.ID}}
+	if err := svc.ApplyRatings(items); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if items[0].RatingAverage != 4.3 || items[0].RatingCount != 3 {
+		t.Fatalf("good: %.2f/%d", items[0].RatingAverage, items[0].RatingCount)
+	}
+	if items[1].RatingAverage != 1.5 || items[1].RatingCount != 2 {
+		t.Fatalf("poor: %.2f/%d", items[1].RatingAverage, items[1].RatingCount)
+	}
+	if items[2].RatingAverage != 0 || items[2].RatingCount != 0 {
+		t.Fatalf("unrated: %.2f/%d", items[2].RatingAverage, items[2].RatingCount)
+	}
+
+	order := func(query dto.AdminRatedContentQuery) []uint {
+		t.Helper()
+		resp, err := svc.AdminLowestRated(admin.ID, query)
+		if err != nil {
+			t.Fatalf("lowest rated: %v", err)
+		}
+		ids := make([]uint, 0, len(resp.Items))
+		for _, item := range resp.Items {
+			ids = append(ids, item.ContentID)
+		}
+		return ids
+	}
+	if got := order(dto.AdminRatedContentQuery{}); !equalIDs(got, []uint{poor.ID, single.ID, good.ID}) {
+		t.Fatalf("default order: %v", got)
+	}
+	if got := order(dto.AdminRatedContentQuery{MinCount: 2}); !equalIDs(got, []uint{poor.ID, good.ID}) {
+		t.Fatalf("min_count=2: %v", got)
+	}
+	if got := order(dto.AdminRatedContentQuery{PageQuery: dto.PageQuery{Order: "desc"}}); !equalIDs(got, []uint{good.ID, single.ID, poor.ID}) {
+		t.Fatalf("highest first: %v", got)
+	}
+	if got := order(dto.AdminRatedContentQuery{PageQuery: dto.PageQuery{Sort: "count", Order: "desc", PageSize: 1}}); !equalIDs(got, []uint{good.ID}) {
+		t.Fatalf("by count: %v", got)
+	}
+	if _, err := svc.AdminLowestRated(users[0].ID, dto.AdminRatedContentQuery{}); err == nil {
+		t.Fatal("learner read the rating report")
+	}
+}
+
+func TestContentCommentThreadsArePaged(t *testing.T) {
+	db := newTestDB(t)
+	svc := newContentFeedbackService(db)
+	learner := createUser(t, db, model.RoleEmployee, "E1")
+	content := createContent(t, db, "doc", "")
+	now := time.Now()
+	comment := func(parent *model.ContentComment, status string, ago time.Duration) *model.ContentComment {
+		t.Helper()
+		c := &model.ContentComment{ContentID: content.ID, UserID: learner.ID, Body: "评论", Status: status}
+		if parent != nil {
+			c.ParentID = &parent.ID
+		}
+		c.CreatedAt = now.Add(-ago)
+		if err := db.Create(c).Error; err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	oldest := comment(nil, service.FeedbackStatusApproved, 3*time.Hour)
+	middle := comment(nil, service.FeedbackStatusApproved, 2*time.Hour)
+	newest := comment(nil, service.FeedbackStatusApproved, time.Hour)
+	comment(nil, service.FeedbackStatusPending, time.Minute)
+	reply := comment(oldest, service.FeedbackStatusApproved, 30*time.Minute)
+	nested := comment(reply, service.FeedbackStatusApproved, 20*time.Minute)
+	comment(reply, service.FeedbackStatusPending, 10*time.Minute)
+
+	// 按顶层评论分页，新的在前
+	first, err := svc.ListComments(learner.ID, content.ID, dto.ContentCommentQuery{CursorQuery: dto.CursorQuery{PageSize: 2}})
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	if len(first.Items) != 2 || first.Items[0].ID != newest.ID || first.Items[1].ID != middle.ID || first.Pagination.Total != 3 || first.Pagination.NextCursor == "" {
+		t.Fatalf("first page: %+v", first)
+	}
+	// 期间发表的评论不影响后续页面
+	comment(nil, service.FeedbackStatusApproved, 0)
+	second, err := svc.ListComments(learner.ID, content.ID, dto.ContentCommentQuery{CursorQuery: dto.CursorQuery{Cursor: first.Pagination.NextCursor, PageSize: 2}})
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if len(second.Items) != 1 || second.Items[0].ID != oldest.ID || second.Pagination.HasMore {
+		t.Fatalf("second page: %+v", second)
+	}
+	// 每条顶层评论连同全部已通过的回复一起返回
+	replies := second.Items[0].Replies
+	if len(replies) != 1 || replies[0].ID != reply.ID || len(replies[0].Replies) != 1 || replies[0].Replies[0].ID != nested.ID {
+		t.Fatalf("replies: %+v", replies)
+	}
+}
+
+func equalIDs(got, want []uint) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
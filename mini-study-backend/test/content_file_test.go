@@ -0,0 +1,91 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/handler"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
+)
+
+func TestGetContentFile(t *testing.T) {
+	db := newTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	signer := storage.NewURLSigner("test-secret")
+	store := storage.NewLocalStorage(t.TempDir(), t.TempDir(), "/uploads", "/api/v1/files/download", signer)
+	files := service.NewFileService(store, signer, service.NewAuditService(repository.NewAuditRepository(db), userRepo), 10, time.Minute)
+	h := handler.NewContentHandler(newContentService(db), files, nil, newContentFeedbackService(db))
+
+	employee := createUser(t, db, model.RoleEmployee, "E1")
+	data := []byte("0123456789abcdef")
+	key := storage.PrivatePrefix + "documents/guide.pdf"
+	if err := store.Put(context.Background(), key, bytes.NewReader(data), int64(len(data)), "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+	published := createContent(t, db, "doc", key)
+	draft := createContent(t, db, "doc", key)
+	managersOnly := createContent(t, db, "doc", key)
+	if err := db.Model(draft).Update("status", "draft").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(managersOnly).Update("visible_roles", "manager").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/contents/:id/file", func(c *gin.Context) {
+		c.Set("userID", employee.ID)
+	}, h.GetContentFile)
+	get := func(contentID uint, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/contents/"+strconv.FormatUint(uint64(contentID), 10)+"/file", nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	full := get(published.ID, nil)
+	if full.Code != http.StatusOK || full.Body.String() != string(data) || full.Header().Get("X-Watermark-ID") == "" {
+		t.Fatalf("full: %d %q %v", full.Code, full.Body.String(), full.Header())
+	}
+	etag := full.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag")
+	}
+
+	partial := get(published.ID, http.Header{"Range": {"bytes=2-5"}})
+	if partial.Code != http.StatusPartialContent || partial.Body.String() != "2345" {
+		t.Fatalf("range: %d %q", partial.Code, partial.Body.String())
+	}
+	if got := partial.Header().Get("Content-Range"); got != "bytes 2-5/16" {
+		t.Fatalf("Content-Range = %q", got)
+	}
+
+	if cached := get(published.ID, http.Header{"If-None-Match": {etag}}); cached.Code != http.StatusNotModified || cached.Body.Len() != 0 {
+		t.Fatalf("If-None-Match: %d %q", cached.Code, cached.Body.String())
+	}
+
+	// 未发布与不存在的内容都返回 404，角色不可见返回 403
+	if rec := get(draft.ID, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("draft: %d", rec.Code)
+	}
+	if rec := get(managersOnly.ID+100, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("missing: %d", rec.Code)
+	}
+	if rec := get(managersOnly.ID, nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("other role: %d", rec.Code)
+	}
+}
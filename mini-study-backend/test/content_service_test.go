@@ -0,0 +1,220 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/search"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+// newContentService wires a ContentService with an in-memory search index and media processing disabled.
+func newContentService(db *gorm.DB) *service.ContentService {
+	userRepo := repository.NewUserRepository(db)
+	contentRepo := repository.NewContentRepository(db)
+	categoryRepo := repository.NewContentCategoryRepository(db)
+	pageRepo := repository.NewContentPageRepository(db)
+	audit := service.NewAuditService(repository.NewAuditRepository(db), userRepo)
+	searchSvc := service.NewSearchService(search.NewMemoryIndex(), contentRepo, categoryRepo, pageRepo, repository.NewExamRepository(db), repository.NewGrowthPostRepository(db), userRepo)
+	media := service.NewMediaService(repository.NewMediaJobRepository(db), contentRepo, pageRepo, userRepo, nil, audit, searchSvc, service.MediaOptions{})
+	reviews := service.NewReviewService(repository.NewReviewEventRepository(db), contentRepo, repository.NewExamRepository(db), userRepo, audit)
+	return service.NewContentService(categoryRepo, contentRepo, userRepo, audit, media, searchSvc, reviews, repository.NewContentRevisionRepository(db))
+}
+
+func revisionVersions(t *testing.T, db *gorm.DB, contentID uint) []int {
+	t.Helper()
+	var versions []int
+	if err := db.Model(&model.ContentRevision{}).Where("content_id = ?", contentID).Order("version").Pluck("version", &versions).Error; err != nil {
+		t.Fatal(err)
+	}
+	return versions
+}
+
+func TestGetPublishedDetailErrors(t *testing.T) {
+	db := newTestDB(t)
+	svc := newContentService(db)
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	employee := createUser(t, db, model.RoleEmployee, "E1")
+
+	published := createContent(t, db, "doc", "private/document/ab/a.pdf")
+	draft := createContent(t, db, "doc", "private/document/ab/b.pdf")
+	managersOnly := createContent(t, db, "doc", "private/document/ab/c.pdf")
+	if err := db.Model(draft).Update("status", "draft").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(managersOnly).Update("visible_roles", model.RoleManager).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.GetPublishedDetail(employee.ID, published.ID); err != nil {
+		t.Fatalf("published: %v", err)
+	}
+	cases := []struct {
+		name string
+		id   uint
+		want error
+	}{
+		{"missing", published.ID + 100, service.ErrContentNotFound},
+		{"draft", draft.ID, service.ErrContentNotPublished},
+		{"other role", managersOnly.ID, service.ErrContentForbidden},
+	}
+	for _, tc := range cases {
+		if _, err := svc.GetPublishedDetail(employee.ID, tc.id); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.want)
+		}
+	}
+	if _, err := svc.GetPublishedDetail(admin.ID, draft.ID); err != nil {
+		t.Fatalf("admin reading a draft: %v", err)
+	}
+}
+
+func TestContentRevisionsAreRecordedWithEdits(t *testing.T) {
+	db := newTestDB(t)
+	svc := newContentService(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+
+	created, err := svc.AdminCreateContent(ctx, admin.ID, dto.AdminCreateContentRequest{Title: "v1", Type: "doc", CategoryID: createContent(t, db, "doc", "").CategoryID, FilePath: "/uploads/a.pdf"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if got := revisionVersions(t, db, created.ID); len(got) != 1 {
+		t.Fatalf("versions after create: %v", got)
+	}
+
+	// 早于版本功能创建的内容，首次修改时补记基线版本
+	legacy := createContent(t, db, "doc", "/uploads/b.pdf")
+	if _, err := svc.AdminUpdateContent(ctx, admin.ID, legacy.ID, dto.AdminUpdateContentRequest{Title: "改名"}); err != nil {
+		t.Fatalf("update legacy: %v", err)
+	}
+	var revisions []model.ContentRevision
+	if err := db.Where("content_id = ?", legacy.ID).Order("version").Find(&revisions).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 || revisions[0].Action != model.RevisionActionBaseline || revisions[0].Title != "内容" || revisions[1].Title != "改名" {
+		t.Fatalf("legacy revisions: %+v", revisions)
+	}
+
+	// 同一版本号重复写入会被唯一索引拒绝并识别为唯一键冲突
+	duplicate := revisions[1]
+	duplicate.ID = 0
+	if err := repository.NewContentRevisionRepository(db).Create(&duplicate); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("duplicate version: %v", err)
+	}
+}
+
+func TestContentRevisionConflictIsRetried(t *testing.T) {
+	db := newTestDB(t)
+	svc := newContentService(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	content := createContent(t, db, "doc", "/uploads/a.pdf")
+
+	// 模拟并发修改抢先写入了同一版本号：前 conflicts 次写版本时返回唯一键冲突
+	conflicts := 1
+	if err := db.Callback().Create().Before("gorm:create").Register("test:revision_conflict", func(tx *gorm.DB) {
+		if tx.Statement.Table == "content_revisions" && conflicts > 0 {
+			conflicts--
+			_ = tx.AddError(gorm.ErrDuplicatedKey)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.AdminUpdateContent(ctx, admin.ID, content.ID, dto.AdminUpdateContentRequest{Title: "重试后成功"}); err != nil {
+		t.Fatalf("update with one conflict: %v", err)
+	}
+	if got := revisionVersions(t, db, content.ID); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fatalf("versions: %v", got)
+	}
+
+	// 持续冲突时返回错误，内容修改随版本一起回滚
+	conflicts = 100
+	if _, err := svc.AdminUpdateContent(ctx, admin.ID, content.ID, dto.AdminUpdateContentRequest{Title: "不会保存"}); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("update with persistent conflicts: %v", err)
+	}
+	var stored model.Content
+	if err := db.First(&stored, content.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Title != "重试后成功" {
+		t.Fatalf("content saved without its revision: %q", stored.Title)
+	}
+	if got := revisionVersions(t, db, content.ID); len(got) != 2 {
+		t.Fatalf("versions after failed update: %v", got)
+	}
+}
+
+func TestContentRevisionsArePaged(t *testing.T) {
+	db := newTestDB(t)
+	svc := newContentService(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	content := createContent(t, db, "doc", "")
+	for _, title := range []string{"第二版", "第三版", "第四版"} {
+		if _, err := svc.AdminUpdateContent(ctx, admin.ID, content.ID, dto.AdminUpdateContentRequest{Title: title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 每页的最后一个版本也与页外的上一版本比对
+	for _, tc := range []struct {
+		query    dto.PageQuery
+		versions []int
+		changed  []int
+	}{
+		{dto.PageQuery{PageSize: 1}, []int{4}, []int{1}},
+		{dto.PageQuery{Page: 2, PageSize: 2}, []int{2, 1}, []int{1, 0}},
+		{dto.PageQuery{PageSize: 2, Sort: "version", Order: "asc"}, []int{1, 2}, []int{0, 1}},
+	} {
+		items, page, err := svc.AdminListRevisions(admin.ID, content.ID, dto.ContentRevisionListQuery{PageQuery: tc.query})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != 4 || len(items) != len(tc.versions) {
+			t.Fatalf("%+v: total=%d items=%+v", tc.query, page.Total, items)
+		}
+		for i, item := range items {
+			if item.Version != tc.versions[i] || len(item.ChangedFields) != tc.changed[i] {
+				t.Fatalf("%+v: item %d = %+v", tc.query, i, item)
+			}
+		}
+	}
+	if _, _, err := svc.AdminListRevisions(admin.ID, content.ID, dto.ContentRevisionListQuery{PageQuery: dto.PageQuery{Sort: "title"}}); err == nil {
+		t.Fatal("sorted by a field outside the whitelist")
+	}
+}
+
+func TestContentRollbackNormalizesBlocks(t *testing.T) {
+	db := newTestDB(t)
+	svc := newContentService(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	content := createContent(t, db, "article", "")
+	for version, blocks := range map[int]string{
+		1: `[{"type":"heading","text":" 小节 "}]`,
+		2: `[{"type":"heading","text":"小节","level":9}]`,
+	} {
+		revision := &model.ContentRevision{ContentID: content.ID, Version: version, Action: model.RevisionActionUpdate, Title: "旧版", Type: "article", CategoryID: content.CategoryID, VisibleRoles: "both", BodyBlocksJSON: blocks}
+		if err := db.Create(revision).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := svc.AdminRollbackContent(ctx, admin.ID, content.ID, 2); err == nil {
+		t.Fatal("rolled back to invalid blocks")
+	}
+	restored, err := svc.AdminRollbackContent(ctx, admin.ID, content.ID, 1)
+	if err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if want := `[{"type":"heading","text":"小节","level":2}]`; restored.BodyBlocksJSON != want {
+		t.Fatalf("restored blocks = %s, want %s", restored.BodyBlocksJSON, want)
+	}
+}
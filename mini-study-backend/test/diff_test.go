@@ -0,0 +1,67 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+func TestDiffFieldsRedactsSecrets(t *testing.T) {
+	type snapshot struct {
+		Title    string `json:"title"`
+		Status   string `json:"status"`
+		Password string `json:"password"`
+	}
+
+	before := snapshot{Title: "old", Status: "draft", Password: "a"}
+	after := snapshot{Title: "new", Status: "draft", Password: "b"}
+
+	changes := utils.DiffFields(before, after)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Field != "password" || changes[0].Before != utils.RedactedValue || changes[0].After != utils.RedactedValue {
+		t.Fatalf("password change not redacted: %+v", changes[0])
+	}
+	if changes[1].Field != "title" || changes[1].Before != "old" || changes[1].After != "new" {
+		t.Fatalf("unexpected title change: %+v", changes[1])
+	}
+}
+
+func TestDiffFieldsCreation(t *testing.T) {
+	changes := utils.DiffFields(nil, map[string]interface{}{"title": "t", "id": 3})
+	if len(changes) != 1 || changes[0].Field != "title" || changes[0].Before != nil {
+		t.Fatalf("unexpected creation diff: %+v", changes)
+	}
+}
+
+func TestToRedactedJSONString(t *testing.T) {
+	payload := map[string]interface{}{
+		"work_no":  "E001",
+		"nested":   map[string]string{"refresh_token": "abc"},
+		"Password": "123456",
+	}
+	out := utils.ToRedactedJSONString(payload)
+	if strings.Contains(out, "123456") || strings.Contains(out, "abc") {
+		t.Fatalf("secret leaked: %s", out)
+	}
+	if !strings.Contains(out, "E001") {
+		t.Fatalf("non-secret field missing: %s", out)
+	}
+}
+
+func TestDiffSequences(t *testing.T) {
+	ops := utils.DiffSequences([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+	var got []string
+	for _, op := range ops {
+		got = append(got, op.Op)
+	}
+	want := "equal,removed,added,equal,added"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("got %v, want %s", got, want)
+	}
+	if ops[1].AIndex != 1 || ops[1].BIndex != -1 || ops[2].AIndex != -1 || ops[2].BIndex != 1 {
+		t.Fatalf("unexpected indexes: %+v", ops)
+	}
+}
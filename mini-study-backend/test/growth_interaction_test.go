@@ -0,0 +1,247 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/search"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+func newGrowthService(db *gorm.DB) *service.GrowthService {
+	userRepo := repository.NewUserRepository(db)
+	postRepo := repository.NewGrowthPostRepository(db)
+	audit := service.NewAuditService(repository.NewAuditRepository(db), userRepo)
+	searchSvc := service.NewSearchService(search.NewMemoryIndex(), repository.NewContentRepository(db), repository.NewContentCategoryRepository(db),
+		repository.NewContentPageRepository(db), repository.NewExamRepository(db), postRepo, userRepo)
+	notifications := service.NewNotificationService(repository.NewNotificationRepository(db))
+	moderation := service.NewModerationService(repository.NewSensitiveWordRepository(db), userRepo, audit, nil, service.ModerationOptions{})
+	return service.NewGrowthService(postRepo, userRepo, repository.NewManagerEmployeeRepository(db), audit, searchSvc, notifications, moderation, service.GrowthOptions{})
+}
+
+// createGrowthPost inserts a post visible to the whole company with the given status.
+func createGrowthPost(t *testing.T, db *gorm.DB, creatorID uint, status string) *model.GrowthPost {
+	t.Helper()
+	post := &model.GrowthPost{CreatorID: creatorID, Content: "今天的成长", Status: status, Scope: model.GrowthScopeCompany}
+	if status == service.GrowthStatusApproved {
+		now := time.Now()
+		post.ApprovedAt = &now
+	}
+	if err := db.Omit("Creator").Create(post).Error; err != nil {
+		t.Fatal(err)
+	}
+	return post
+}
+
+func growthNotifications(t *testing.T, db *gorm.DB, userID uint, kind string) int64 {
+	t.Helper()
+	return countRows(t, db, &model.Notification{}, "user_id = ? AND type = ?", userID, kind)
+}
+
+func TestGrowthLikeIsCountedOncePerUser(t *testing.T) {
+	db := newTestDB(t)
+	svc := newGrowthService(db)
+	author := createUser(t, db, model.RoleManager, "M1")
+	fan := createUser(t, db, model.RoleEmployee, "E1")
+	other := createUser(t, db, model.RoleEmployee, "E2")
+	post := createGrowthPost(t, db, author.ID, service.GrowthStatusApproved)
+
+	for i := 0; i < 2; i++ {
+		resp, err := svc.Like(fan.ID, post.ID)
+		if err != nil {
+			t.Fatalf("like: %v", err)
+		}
+		if !resp.Liked || resp.LikeCount != 1 {
+			t.Fatalf("like %d: %+v", i+1, resp)
+		}
+	}
+	if resp, err := svc.Like(other.ID, post.ID); err != nil || resp.LikeCount != 2 {
+		t.Fatalf("second user like: %+v %v", resp, err)
+	}
+	if got := growthNotifications(t, db, author.ID, service.NotificationGrowthLike); got != 2 {
+		t.Fatalf("like notifications = %d, want 2", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := svc.Unlike(fan.ID, post.ID)
+		if err != nil {
+			t.Fatalf("unlike: %v", err)
+		}
+		if resp.Liked || resp.LikeCount != 1 {
+			t.Fatalf("unlike %d: %+v", i+1, resp)
+		}
+	}
+
+	pending := createGrowthPost(t, db, author.ID, service.GrowthStatusPending)
+	if _, err := svc.Like(fan.ID, pending.ID); err == nil {
+		t.Fatal("liked a pending post")
+	}
+}
+
+func TestGrowthShareIsCountedOncePerUser(t *testing.T) {
+	db := newTestDB(t)
+	svc := newGrowthService(db)
+	author := createUser(t, db, model.RoleManager, "M1")
+	fan := createUser(t, db, model.RoleEmployee, "E1")
+	other := createUser(t, db, model.RoleEmployee, "E2")
+	post := createGrowthPost(t, db, author.ID, service.GrowthStatusApproved)
+
+	// 同一用户换渠道重复上报只计一次，也只通知一次
+	for _, channel := range []string{"wechat_friend", "wechat_moments", "wechat_friend"} {
+		resp, err := svc.Share(fan.ID, post.ID, dto.GrowthShareRequest{Channel: channel})
+		if err != nil {
+			t.Fatalf("share via %s: %v", channel, err)
+		}
+		if resp.ShareCount != 1 {
+			t.Fatalf("share via %s: count = %d", channel, resp.ShareCount)
+		}
+	}
+	resp, err := svc.Share(other.ID, post.ID, dto.GrowthShareRequest{Channel: "link"})
+	if err != nil {
+		t.Fatalf("second user share: %v", err)
+	}
+	if resp.ShareCount != 2 {
+		t.Fatalf("share count = %d, want 2", resp.ShareCount)
+	}
+	if got := countRows(t, db, &model.GrowthShare{}, "post_id = ?", post.ID); got != 2 {
+		t.Fatalf("share rows = %d, want 2", got)
+	}
+	if got := growthNotifications(t, db, author.ID, service.NotificationGrowthShare); got != 2 {
+		t.Fatalf("share notifications = %d, want 2", got)
+	}
+	var first model.GrowthShare
+	if err := db.Where("post_id = ? AND user_id = ?", post.ID, fan.ID).First(&first).Error; err != nil {
+		t.Fatal(err)
+	}
+	if first.Channel != "wechat_friend" {
+		t.Fatalf("channel = %s, want the first one", first.Channel)
+	}
+}
+
+func TestGrowthCommentThreads(t *testing.T) {
+	db := newTestDB(t)
+	svc := newGrowthService(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	author := createUser(t, db, model.RoleManager, "M1")
+	commenter := createUser(t, db, model.RoleEmployee, "E1")
+	replier := createUser(t, db, model.RoleEmployee, "E2")
+	post := createGrowthPost(t, db, author.ID, service.GrowthStatusApproved)
+
+	comment, err := svc.CreateComment(ctx, commenter.ID, post.ID, dto.GrowthCommentRequest{Body: "  恭喜  "})
+	if err != nil {
+		t.Fatalf("comment: %v", err)
+	}
+	if comment.Body != "恭喜" || comment.Status != service.GrowthStatusPending {
+		t.Fatalf("new comment: %+v", comment)
+	}
+	// 待审核的评论不公开，也不能被回复
+	if list, err := svc.ListComments(replier.ID, post.ID, dto.CursorQuery{}); err != nil || len(list.Items) != 0 {
+		t.Fatalf("pending comment listed: %+v %v", list, err)
+	}
+	if _, err := svc.CreateComment(ctx, replier.ID, post.ID, dto.GrowthCommentRequest{Body: "同喜", ParentID: &comment.ID}); err == nil {
+		t.Fatal("replied to a pending comment")
+	}
+
+	if _, err := svc.AdminModerateComment(ctx, admin.ID, comment.ID, true); err != nil {
+		t.Fatalf("approve comment: %v", err)
+	}
+	reply, err := svc.CreateComment(ctx, replier.ID, post.ID, dto.GrowthCommentRequest{Body: "同喜", ParentID: &comment.ID})
+	if err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+	if _, err := svc.AdminModerateComment(ctx, admin.ID, reply.ID, true); err != nil {
+		t.Fatalf("approve reply: %v", err)
+	}
+
+	list, err := svc.ListComments(author.ID, post.ID, dto.CursorQuery{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if threads := list.Items; len(threads) != 1 || threads[0].ID != comment.ID || len(threads[0].Replies) != 1 || threads[0].Replies[0].ID != reply.ID {
+		t.Fatalf("threads: %+v", list)
+	}
+	var saved model.GrowthPost
+	if err := db.First(&saved, post.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if saved.CommentCount != 2 {
+		t.Fatalf("comment count = %d, want 2", saved.CommentCount)
+	}
+	// 动态作者收到两条评论通知，被回复的评论作者收到回复通知
+	if got := growthNotifications(t, db, author.ID, service.NotificationGrowthComment); got != 2 {
+		t.Fatalf("comment notifications = %d, want 2", got)
+	}
+	if got := growthNotifications(t, db, commenter.ID, service.NotificationGrowthReply); got != 1 {
+		t.Fatalf("reply notifications = %d, want 1", got)
+	}
+
+	if err := svc.DeleteComment(ctx, replier.ID, post.ID, comment.ID); err == nil {
+		t.Fatal("deleted another user's comment")
+	}
+	// 删除父评论后其下的回复随之隐藏，评论数一并更新
+	if err := svc.DeleteComment(ctx, commenter.ID, post.ID, comment.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if list, err := svc.ListComments(author.ID, post.ID, dto.CursorQuery{}); err != nil || len(list.Items) != 0 {
+		t.Fatalf("orphaned reply listed: %+v %v", list, err)
+	}
+	if err := db.First(&saved, post.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if saved.CommentCount != 0 {
+		t.Fatalf("comment count after delete = %d", saved.CommentCount)
+	}
+}
+
+func TestGrowthCommentThreadsArePaged(t *testing.T) {
+	db := newTestDB(t)
+	svc := newGrowthService(db)
+	author := createUser(t, db, model.RoleManager, "M1")
+	commenter := createUser(t, db, model.RoleEmployee, "E1")
+	post := createGrowthPost(t, db, author.ID, service.GrowthStatusApproved)
+	now := time.Now()
+	comment := func(parent *model.GrowthComment, ago time.Duration) *model.GrowthComment {
+		t.Helper()
+		c := &model.GrowthComment{PostID: post.ID, UserID: commenter.ID, Body: "恭喜", Status: service.GrowthStatusApproved}
+		if parent != nil {
+			c.ParentID = &parent.ID
+		}
+		c.CreatedAt = now.Add(-ago)
+		if err := db.Omit("User").Create(c).Error; err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	older := comment(nil, 2*time.Hour)
+	newer := comment(nil, time.Hour)
+	reply := comment(older, 30*time.Minute)
+	nested := comment(reply, 10*time.Minute)
+
+	// 按顶层评论游标分页，最新的在前
+	first, err := svc.ListComments(author.ID, post.ID, dto.CursorQuery{PageSize: 1})
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	if len(first.Items) != 1 || first.Items[0].ID != newer.ID || first.Pagination.Total != 2 || !first.Pagination.HasMore {
+		t.Fatalf("first page: %+v", first)
+	}
+	second, err := svc.ListComments(author.ID, post.ID, dto.CursorQuery{Cursor: first.Pagination.NextCursor, PageSize: 1})
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if len(second.Items) != 1 || second.Items[0].ID != older.ID || second.Pagination.HasMore {
+		t.Fatalf("second page: %+v", second)
+	}
+	// 回复不单独占用分页，按层级嵌套在顶层评论下
+	replies := second.Items[0].Replies
+	if len(replies) != 1 || replies[0].ID != reply.ID || len(replies[0].Replies) != 1 || replies[0].Replies[0].ID != nested.ID {
+		t.Fatalf("replies: %+v", replies)
+	}
+}
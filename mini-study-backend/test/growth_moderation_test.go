@@ -0,0 +1,154 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+func TestGrowthRejectRequiresReason(t *testing.T) {
+	db := newTestDB(t)
+	svc := newGrowthService(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	author := createUser(t, db, model.RoleManager, "M1")
+	post, err := svc.CreatePost(ctx, author.ID, dto.CreateGrowthPostRequest{Content: "门店喜报"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	for _, req := range []dto.GrowthRejectRequest{{}, {Reason: "   "}, {ReasonCode: "other"}, {ReasonCode: "other", Reason: " "}} {
+		if _, err := svc.Reject(ctx, admin.ID, post.ID, req); err == nil {
+			t.Fatalf("rejected without a reason: %+v", req)
+		}
+	}
+
+	// 只填说明时按“其他”记录，通知里带上说明
+	rejected, err := svc.Reject(ctx, admin.ID, post.ID, dto.GrowthRejectRequest{Reason: " 请补充数据 "})
+	if err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	if rejected.Status != service.GrowthStatusRejected || rejected.RejectReasonCode != "other" || rejected.RejectReason != "请补充数据" || rejected.RejectedAt == nil {
+		t.Fatalf("rejected post: %+v", rejected)
+	}
+	var note model.Notification
+	if err := db.Where("user_id = ? AND type = ?", author.ID, service.NotificationGrowthRejected).First(&note).Error; err != nil {
+		t.Fatal(err)
+	}
+	if note.Summary != "你的动态未通过审核：请补充数据" {
+		t.Fatalf("summary = %q", note.Summary)
+	}
+	if _, err := svc.Reject(ctx, admin.ID, post.ID, dto.GrowthRejectRequest{ReasonCode: "ad"}); err == nil {
+		t.Fatal("rejected a post twice")
+	}
+
+	// 预设原因可以不填说明
+	other, err := svc.CreatePost(ctx, author.ID, dto.CreateGrowthPostRequest{Content: "另一条"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	rejected, err = svc.Reject(ctx, admin.ID, other.ID, dto.GrowthRejectRequest{ReasonCode: "irrelevant"})
+	if err != nil {
+		t.Fatalf("reject with code: %v", err)
+	}
+	if rejected.RejectReasonCode != "irrelevant" || rejected.RejectReasonLabel != "与工作无关" || rejected.RejectReason != "" {
+		t.Fatalf("rejected with code: %+v", rejected)
+	}
+	if _, err := svc.Reject(ctx, author.ID, other.ID, dto.GrowthRejectRequest{ReasonCode: "ad"}); err == nil {
+		t.Fatal("non-admin rejected a post")
+	}
+}
+
+func TestGrowthResubmitAndHistory(t *testing.T) {
+	db := newTestDB(t)
+	svc := newGrowthService(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	author := createUser(t, db, model.RoleManager, "M1")
+	stranger := createUser(t, db, model.RoleManager, "M2")
+	post, err := svc.CreatePost(ctx, author.ID, dto.CreateGrowthPostRequest{Content: "初稿"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// 待审核的动态不能重新提交
+	if _, err := svc.Resubmit(ctx, author.ID, post.ID, dto.ResubmitGrowthPostRequest{Content: "改稿"}); err == nil {
+		t.Fatal("resubmitted a pending post")
+	}
+	if _, err := svc.Reject(ctx, admin.ID, post.ID, dto.GrowthRejectRequest{ReasonCode: "low_quality", Reason: "请补充数据"}); err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	if _, err := svc.Resubmit(ctx, stranger.ID, post.ID, dto.ResubmitGrowthPostRequest{Content: "改稿"}); err == nil {
+		t.Fatal("resubmitted another user's post")
+	}
+
+	resubmitted, err := svc.Resubmit(ctx, author.ID, post.ID, dto.ResubmitGrowthPostRequest{Content: "改稿 #门店喜报"})
+	if err != nil {
+		t.Fatalf("resubmit: %v", err)
+	}
+	if resubmitted.Status != service.GrowthStatusPending || resubmitted.Content != "改稿 #门店喜报" || resubmitted.ResubmitCount != 1 {
+		t.Fatalf("resubmitted post: %+v", resubmitted)
+	}
+	if resubmitted.RejectReasonCode != "" || resubmitted.RejectReason != "" || resubmitted.RejectedAt != nil {
+		t.Fatalf("rejection kept after resubmit: %+v", resubmitted)
+	}
+	if len(resubmitted.Topics) != 1 || resubmitted.Topics[0] != "门店喜报" {
+		t.Fatalf("topics = %v", resubmitted.Topics)
+	}
+	if _, err := svc.Approve(ctx, admin.ID, post.ID); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+
+	history, err := svc.History(author.ID, post.ID)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	want := []string{model.GrowthActionSubmit, model.GrowthActionReject, model.GrowthActionResubmit, model.GrowthActionApprove}
+	if history.Status != service.GrowthStatusApproved || len(history.Events) != len(want) {
+		t.Fatalf("history: %+v", history)
+	}
+	for i, action := range want {
+		if history.Events[i].Action != action {
+			t.Fatalf("event %d action = %s, want %s", i, history.Events[i].Action, action)
+		}
+	}
+	reject := history.Events[1]
+	if reject.ActorName != admin.Name || reject.ReasonCode != "low_quality" || reject.ReasonLabel != "内容质量不足" || reject.Reason != "请补充数据" {
+		t.Fatalf("reject event: %+v", reject)
+	}
+	if resubmit := history.Events[2]; resubmit.Content != "改稿 #门店喜报" || resubmit.FromStatus != service.GrowthStatusRejected {
+		t.Fatalf("resubmit event: %+v", resubmit)
+	}
+
+	if _, err := svc.History(stranger.ID, post.ID); err == nil {
+		t.Fatal("another user read the history")
+	}
+	if _, err := svc.History(admin.ID, post.ID); err != nil {
+		t.Fatalf("admin history: %v", err)
+	}
+}
+
+func TestGrowthPostScope(t *testing.T) {
+	db := newTestDB(t)
+	svc := newGrowthService(db)
+	ctx := context.Background()
+	author := createUser(t, db, model.RoleManager, "M1")
+
+	// 用户没有区域信息，按区域发布被明确拒绝，而不是退回全公司可见
+	if _, err := svc.CreatePost(ctx, author.ID, dto.CreateGrowthPostRequest{Content: "门店喜报", Scope: "region"}); err == nil || err.Error() != "暂不支持按区域发布，请使用 team（本店团队）" {
+		t.Fatalf("region scope: %v", err)
+	}
+	if _, err := svc.CreatePost(ctx, author.ID, dto.CreateGrowthPostRequest{Content: "门店喜报", Scope: model.GrowthScopeRoles}); err == nil {
+		t.Fatal("roles scope without roles")
+	}
+	post, err := svc.CreatePost(ctx, author.ID, dto.CreateGrowthPostRequest{Content: "门店喜报", Scope: model.GrowthScopeTeam})
+	if err != nil {
+		t.Fatalf("team scope: %v", err)
+	}
+	if post.Scope != model.GrowthScopeTeam {
+		t.Fatalf("scope = %s", post.Scope)
+	}
+}
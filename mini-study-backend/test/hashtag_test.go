@@ -0,0 +1,24 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	text := "#新品上市#本周门店 #KPI 冲刺，再说一次 #kpi！#一二三四五六七八九十一二三四五六七八九十一 # 空标签"
+	got := utils.ExtractHashtags(text)
+	want := []string{"新品上市", "kpi"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ExtractHashtags = %v, want %v", got, want)
+	}
+}
+
+func TestExtractHashtagsLimit(t *testing.T) {
+	got := utils.ExtractHashtags("#a #b #c #d #e #f")
+	if len(got) != utils.MaxHashtags || got[utils.MaxHashtags-1] != "e" {
+		t.Fatalf("unexpected tags %v", got)
+	}
+}
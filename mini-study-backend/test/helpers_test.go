@@ -0,0 +1,65 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/bootstrap"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+)
+
+// newTestDB opens a migrated in-memory SQLite database private to the test.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	cfg := &bootstrap.Config{}
+	cfg.App.Env = "test"
+	cfg.Database.Driver = "sqlite"
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	cfg.Database.DSN = fmt.Sprintf("file:%s?mode=memory&cache=shared&_busy_timeout=5000", name)
+	db, err := bootstrap.InitDatabase(cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("init database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("database handle: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return db
+}
+
+// createUser inserts an active user with the given role.
+func createUser(t *testing.T, db *gorm.DB, role model.Role, workNo string) *model.User {
+	t.Helper()
+	user := &model.User{WorkNo: workNo, Name: "用户" + workNo, Role: role, PasswordHash: "x", Status: true}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user %s: %v", workNo, err)
+	}
+	return user
+}
+
+// bindManager makes employee report to manager.
+func bindManager(t *testing.T, db *gorm.DB, managerID, employeeID uint) {
+	t.Helper()
+	if err := db.Create(&model.ManagerEmployee{ManagerID: managerID, EmployeeID: employeeID}).Error; err != nil {
+		t.Fatalf("bind manager: %v", err)
+	}
+}
+
+// createContent inserts a published content of the given type visible to everyone.
+func createContent(t *testing.T, db *gorm.DB, contentType, filePath string) *model.Content {
+	t.Helper()
+	category := &model.ContentCategory{Name: "分类" + t.Name(), RoleScope: "employee", Status: true}
+	if err := db.FirstOrCreate(category, model.ContentCategory{Name: category.Name}).Error; err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	content := &model.Content{Title: "内容", Type: contentType, CategoryID: category.ID, VisibleRoles: "both", FilePath: filePath, Status: "published"}
+	if err := db.Create(content).Error; err != nil {
+		t.Fatalf("create content: %v", err)
+	}
+	return content
+}
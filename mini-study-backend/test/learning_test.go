@@ -0,0 +1,89 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+// newLearningService requires half of the pages, each read for at least 10 seconds.
+func newLearningService(db *gorm.DB) *service.LearningService {
+	return service.NewLearningService(repository.NewLearningRecordRepository(db), repository.NewLearningPageViewRepository(db),
+		repository.NewContentCheckpointRepository(db), repository.NewCheckpointAnswerRepository(db),
+		repository.NewContentRepository(db), repository.NewUserRepository(db), nil,
+		service.LearningOptions{DocCompletionPercent: 50, MinPageSeconds: 10})
+}
+
+func TestDocumentPageProgress(t *testing.T) {
+	db := newTestDB(t)
+	svc := newLearningService(db)
+	ctx := context.Background()
+	user := createUser(t, db, model.RoleEmployee, "E1")
+	doc := createContent(t, db, "doc", "private/document/ab/abc.pdf")
+	if err := db.Model(doc).Update("page_count", 4).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	report := func(page int, seconds int64) *dto.LearningProgressResponse {
+		t.Helper()
+		resp, err := svc.UpdateProgress(ctx, user.ID, dto.LearningProgressRequest{ContentID: doc.ID, Page: page, PageSeconds: seconds})
+		if err != nil {
+			t.Fatalf("page %d: %v", page, err)
+		}
+		return resp
+	}
+
+	// 停留时间累计达到 MinPageSeconds 才算已读
+	if resp := report(1, 6); resp.PagesRead != 0 || resp.Progress != 0 || resp.Status != "in_progress" {
+		t.Fatalf("after 6s: %+v", resp)
+	}
+	if resp := report(1, 6); resp.PagesRead != 1 || resp.Progress != 25 || resp.LastPage != 1 {
+		t.Fatalf("after 12s: %+v", resp)
+	}
+	if _, err := svc.UpdateProgress(ctx, user.ID, dto.LearningProgressRequest{ContentID: doc.ID, Page: 5, PageSeconds: 10}); err == nil {
+		t.Fatal("accepted a page beyond the document")
+	}
+	if resp := report(3, 30); resp.PagesRead != 2 || resp.Progress != 100 || resp.Status != "completed" {
+		t.Fatalf("after half the pages: %+v", resp)
+	}
+
+	// 完成后继续阅读只累计页数，不改变完成状态
+	if resp := report(2, 10); resp.PagesRead != 3 || resp.Status != "completed" {
+		t.Fatalf("after completion: %+v", resp)
+	}
+	progress, err := svc.GetProgress(user.ID, doc.ID)
+	if err != nil {
+		t.Fatalf("get progress: %v", err)
+	}
+	if progress.PageCount != 4 || len(progress.PageViews) != 3 {
+		t.Fatalf("progress: %+v", progress)
+	}
+	seconds := map[int]int64{}
+	for _, view := range progress.PageViews {
+		seconds[view.PageNo] = view.DurationSeconds
+	}
+	if seconds[1] != 12 || seconds[3] != 30 || seconds[2] != 10 {
+		t.Fatalf("page durations: %v", seconds)
+	}
+}
+
+func TestDocumentWithoutPagesCompletesOnOpen(t *testing.T) {
+	db := newTestDB(t)
+	svc := newLearningService(db)
+	user := createUser(t, db, model.RoleEmployee, "E1")
+	doc := createContent(t, db, "doc", "/uploads/legacy.pdf")
+
+	resp, err := svc.UpdateProgress(context.Background(), user.ID, dto.LearningProgressRequest{ContentID: doc.ID})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if resp.Status != "completed" || resp.Progress != 100 {
+		t.Fatalf("document without previews not completed on open: %+v", resp)
+	}
+}
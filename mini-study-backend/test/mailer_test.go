@@ -0,0 +1,181 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/mailer"
+)
+
+// fakeSMTP accepts one message without TLS or authentication and reports the envelope and data.
+type fakeSMTP struct {
+	listener net.Listener
+	from     string
+	to       []string
+	data     chan string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeSMTP{listener: listener, data: make(chan string, 1)}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *fakeSMTP) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTP) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		upper := strings.ToUpper(cmd)
+		switch {
+		case strings.HasPrefix(upper, "EHLO"):
+			reply("250-fake")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.from = smtpPath(cmd[len("MAIL FROM:"):])
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			s.to = append(s.to, smtpPath(cmd[len("RCPT TO:"):]))
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 go ahead")
+			var body strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				body.WriteString(strings.TrimPrefix(line, "."))
+			}
+			s.data <- body.String()
+			reply("250 queued")
+		case upper == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// smtpPath extracts the address from "<addr> PARAM=..." arguments.
+func smtpPath(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if end := strings.Index(arg, ">"); end >= 0 {
+		arg = arg[:end]
+	}
+	return strings.TrimPrefix(arg, "<")
+}
+
+func TestSMTPMailerSendsAttachment(t *testing.T) {
+	server := newFakeSMTP(t)
+	m := mailer.NewSMTPMailer(mailer.SMTPConfig{
+		Host:    "127.0.0.1",
+		Port:    server.port(),
+		From:    "reports@example.com",
+		Timeout: 5 * time.Second,
+	})
+
+	attachment := []byte(strings.Repeat("工号,姓名\n", 20))
+	err := m.Send(context.Background(), mailer.Message{
+		To:      []string{"manager@example.com"},
+		Subject: "团队学习汇总 2026-10-19",
+		Body:    "附件为本周报表。",
+		Attachments: []mailer.Attachment{{
+			Filename:    "团队学习汇总-20261019.csv",
+			ContentType: "text/csv; charset=utf-8",
+			Data:        attachment,
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var data string
+	select {
+	case data = <-server.data:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+	if server.from != "reports@example.com" || len(server.to) != 1 || server.to[0] != "manager@example.com" {
+		t.Fatalf("unexpected envelope from=%s to=%v", server.from, server.to)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "团队学习汇总 2026-10-19" {
+		t.Fatalf("subject = %q (%v)", subject, err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("content type = %s (%v)", mediaType, err)
+	}
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []*multipart.Part
+	var bodies [][]byte
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, _ := io.ReadAll(part)
+		decoded, err := base64.StdEncoding.DecodeString(strings.NewReplacer("\r", "", "\n", "").Replace(string(raw)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, part)
+		bodies = append(bodies, decoded)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("expected 2 parts, got %d", len(parts))
+	}
+	if string(bodies[0]) != "附件为本周报表。" {
+		t.Fatalf("body = %q", bodies[0])
+	}
+	if parts[1].FileName() != "团队学习汇总-20261019.csv" {
+		t.Fatalf("filename = %q", parts[1].FileName())
+	}
+	if string(bodies[1]) != string(attachment) {
+		t.Fatal("attachment corrupted")
+	}
+}
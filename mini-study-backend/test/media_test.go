@@ -0,0 +1,184 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
+)
+
+// newMediaService builds a MediaService whose external tools do not exist, so every job fails.
+func newMediaService(t *testing.T, db *gorm.DB) (*service.MediaService, storage.Storage) {
+	t.Helper()
+	userRepo := repository.NewUserRepository(db)
+	audit := service.NewAuditService(repository.NewAuditRepository(db), userRepo)
+	signer := storage.NewURLSigner("test-secret")
+	store := storage.NewLocalStorage(t.TempDir(), t.TempDir(), "/uploads", "/api/v1/files/download", signer)
+	files := service.NewFileService(store, signer, audit, 10, time.Minute)
+	missing := t.TempDir() + "/missing"
+	svc := service.NewMediaService(repository.NewMediaJobRepository(db), repository.NewContentRepository(db), repository.NewContentPageRepository(db),
+		userRepo, files, audit, nil, service.MediaOptions{
+			Enabled:        true,
+			FFmpegPath:     missing,
+			FFprobePath:    missing,
+			WorkDir:        t.TempDir(),
+			SegmentSeconds: 6,
+			MaxAttempts:    2,
+			JobTimeout:     time.Minute,
+			PlaybackTTL:    time.Hour,
+			PlaybackURL:    "/api/v1/media/hls",
+		})
+	return svc, store
+}
+
+func TestMediaProcessable(t *testing.T) {
+	db := newTestDB(t)
+	svc, _ := newMediaService(t, db)
+	cases := []struct {
+		contentType, path string
+		want              bool
+	}{
+		{"video", "private/video/ab/abc.mp4", true},
+		{"doc", "private/document/ab/abc.pdf", true},
+		{"doc", "private/document/ab/abc.docx", true},
+		{"doc", "private/cover/ab/abc.png", false},
+		{"video", "/uploads/legacy.mp4", false},
+		{"video", "https://cdn.example.com/a.mp4", false},
+		{"article", "private/document/ab/abc.pdf", false},
+	}
+	for _, tc := range cases {
+		if got := svc.Processable(tc.contentType, tc.path); got != tc.want {
+			t.Errorf("Processable(%s, %s) = %v, want %v", tc.contentType, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestMediaJobRetriesThenFails(t *testing.T) {
+	db := newTestDB(t)
+	svc, store := newMediaService(t, db)
+	ctx := context.Background()
+	key := "private/video/ab/abc.mp4"
+	if err := store.Put(ctx, key, strings.NewReader("video"), 5, "video/mp4"); err != nil {
+		t.Fatal(err)
+	}
+	content := createContent(t, db, "video", key)
+
+	stale, err := svc.Enqueue(content)
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	job, err := svc.Enqueue(content)
+	if err != nil {
+		t.Fatalf("enqueue again: %v", err)
+	}
+	var superseded model.MediaJob
+	if err := db.First(&superseded, stale.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if superseded.Status != model.MediaJobCanceled {
+		t.Fatalf("superseded job status = %s", superseded.Status)
+	}
+
+	processed, err := svc.ProcessNext(ctx)
+	if err == nil || processed == nil || processed.ID != job.ID {
+		t.Fatalf("first attempt: job=%v err=%v", processed, err)
+	}
+	if processed.Status != model.MediaJobPending || processed.Attempts != 1 || processed.NextRunAt.Before(time.Now().Add(50*time.Second)) {
+		t.Fatalf("retry not scheduled with backoff: %+v", processed)
+	}
+	if next, err := svc.ProcessNext(ctx); next != nil || err != nil {
+		t.Fatalf("job ran before its backoff: job=%v err=%v", next, err)
+	}
+
+	if err := db.Model(&model.MediaJob{}).Where("id = ?", job.ID).Update("next_run_at", time.Now().Add(-time.Second)).Error; err != nil {
+		t.Fatal(err)
+	}
+	processed, err = svc.ProcessNext(ctx)
+	if err == nil || processed == nil {
+		t.Fatalf("second attempt: job=%v err=%v", processed, err)
+	}
+	if processed.Status != model.MediaJobFailed || processed.Attempts != 2 || processed.FinishedAt == nil || processed.Error == "" {
+		t.Fatalf("job not failed after max attempts: %+v", processed)
+	}
+}
+
+func TestMediaJobLeaseExpiredOnLastAttempt(t *testing.T) {
+	db := newTestDB(t)
+	svc, _ := newMediaService(t, db)
+	content := createContent(t, db, "video", "private/video/ab/abc.mp4")
+
+	// 最后一次尝试中进程退出、租约过期的任务直接判定失败，不再执行
+	expired := time.Now().Add(-time.Minute)
+	job := &model.MediaJob{ContentID: content.ID, Kind: model.MediaJobVideo, SourceKey: content.FilePath, Status: model.MediaJobRunning, Attempts: 2, NextRunAt: expired, LeaseUntil: &expired}
+	if err := db.Create(job).Error; err != nil {
+		t.Fatal(err)
+	}
+	if processed, err := svc.ProcessNext(context.Background()); processed != nil || err != nil {
+		t.Fatalf("job=%v err=%v", processed, err)
+	}
+	var stored model.MediaJob
+	if err := db.First(&stored, job.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != model.MediaJobFailed || stored.Attempts != 2 {
+		t.Fatalf("status=%s attempts=%d", stored.Status, stored.Attempts)
+	}
+}
+
+func TestMediaSignedPlayback(t *testing.T) {
+	db := newTestDB(t)
+	svc, store := newMediaService(t, db)
+	ctx := context.Background()
+	master := "private/hls/abc/master.m3u8"
+	playlist := "#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=928000\n360p/index.m3u8\n"
+	if err := store.Put(ctx, master, strings.NewReader(playlist), int64(len(playlist)), "application/vnd.apple.mpegurl"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := svc.PlaybackURL(&model.Content{}); got != "" {
+		t.Fatalf("unpackaged video got playback url %q", got)
+	}
+	playback, err := url.Parse(svc.PlaybackURL(&model.Content{HLSPath: master}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if playback.Path != "/api/v1/media/hls/"+master {
+		t.Fatalf("playback path = %s", playback.Path)
+	}
+	expires, _ := strconv.ParseInt(playback.Query().Get("expires"), 10, 64)
+	query := dto.SignedDownloadQuery{Expires: expires, Signature: playback.Query().Get("signature")}
+
+	obj, err := svc.OpenHLS(ctx, master, query)
+	if err != nil {
+		t.Fatalf("open playlist: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(obj.Playlist)), "\n")
+	if last := lines[len(lines)-1]; last != "360p/index.m3u8?"+playback.RawQuery {
+		t.Fatalf("variant uri not signed: %s", last)
+	}
+	if lines[0] != "#EXTM3U" {
+		t.Fatalf("tag line changed: %s", lines[0])
+	}
+
+	// 同一签名覆盖整个渲染目录，但不能用于其他目录或篡改后的签名
+	if _, err := svc.OpenHLS(ctx, "private/hls/other/master.m3u8", query); err == nil {
+		t.Fatal("signature accepted for another directory")
+	}
+	if _, err := svc.OpenHLS(ctx, master, dto.SignedDownloadQuery{Expires: expires, Signature: "bad"}); err == nil {
+		t.Fatal("tampered signature accepted")
+	}
+	if _, err := svc.OpenHLS(ctx, "private/video/ab/abc.mp4", query); !errors.Is(err, storage.ErrInvalidKey) {
+		t.Fatalf("non-hls key: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/moderation"
+)
+
+func TestMatcherFindsDisguisedWords(t *testing.T) {
+	matcher := moderation.NewMatcher([]moderation.Word{
+		{Text: "加微信", Category: "ad", Level: moderation.LevelReview},
+		{Text: "微信群", Category: "ad", Level: moderation.LevelReview},
+		{Text: "ＶＩＰ", Category: "ad", Level: moderation.LevelBlock},
+	})
+
+	text := "详情请加 微-信群，vip 名额有限"
+	matches := matcher.Find(text)
+	if len(matches) != 3 {
+		t.Fatalf("expected 3 matches, got %+v", matches)
+	}
+	if got := text[matches[0].Start:matches[0].End]; got != "加 微-信" {
+		t.Fatalf("unexpected first span %q", got)
+	}
+	if got := text[matches[1].Start:matches[1].End]; got != "微-信群" {
+		t.Fatalf("unexpected second span %q", got)
+	}
+	if matches[2].Level != moderation.LevelBlock {
+		t.Fatalf("full-width word not folded: %+v", matches[2])
+	}
+
+	want := "详情请<mark>加 微-信群</mark>，<mark>vip</mark> 名额有限"
+	if got := moderation.Highlight(text, matches); got != want {
+		t.Fatalf("highlight = %q", got)
+	}
+}
+
+func TestStricterVerdict(t *testing.T) {
+	if v := moderation.Stricter(moderation.VerdictReview, moderation.VerdictPass); v != moderation.VerdictReview {
+		t.Fatalf("got %s", v)
+	}
+	if v := moderation.Stricter(moderation.VerdictReview, moderation.VerdictBlock); v != moderation.VerdictBlock {
+		t.Fatalf("got %s", v)
+	}
+}
@@ -0,0 +1,51 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/utils"
+)
+
+func TestSortFieldsOrderBy(t *testing.T) {
+	order, ok := repository.RatedContentSortFields.OrderBy("average", true)
+	if !ok || order != "average DESC, content_id DESC" {
+		t.Fatalf("unexpected order %q (%v)", order, ok)
+	}
+	order, ok = repository.ContentSortFields.OrderBy("id", false)
+	if !ok || order != "id ASC" {
+		t.Fatalf("unexpected id order %q (%v)", order, ok)
+	}
+	if _, ok := repository.ContentSortFields.OrderBy("title; DROP TABLE contents", false); ok {
+		t.Fatal("field outside the whitelist accepted")
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 8, 30, 0, 123456789, time.UTC)
+	cursor := utils.EncodeCursor(createdAt, 42)
+
+	gotAt, gotID, err := utils.DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !gotAt.Equal(createdAt) || gotID != 42 {
+		t.Fatalf("round trip mismatch: %v %d", gotAt, gotID)
+	}
+	for _, bad := range []string{"not-base64!", utils.EncodeCursor(createdAt, 0), "MTIz"} {
+		if _, _, err := utils.DecodeCursor(bad); err == nil {
+			t.Fatalf("cursor %q accepted", bad)
+		}
+	}
+}
+
+func TestNewPaginationHasMore(t *testing.T) {
+	if p := dto.NewPagination(2, 20, 41); !p.HasMore {
+		t.Fatalf("page 2 of 41 rows should have more: %+v", p)
+	}
+	if p := dto.NewPagination(3, 20, 41); p.HasMore {
+		t.Fatalf("last page should not have more: %+v", p)
+	}
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/pdf"
+)
+
+func TestPDFEncodeText(t *testing.T) {
+	if got := pdf.EncodeText("证书 A1"); got != "8BC14E66002000410031" {
+		t.Fatalf("EncodeText = %s", got)
+	}
+	// 超出基本多文种平面的字符与控制字符替换为 ?
+	if got := pdf.EncodeText("😀\t"); got != "003F003F" {
+		t.Fatalf("EncodeText = %s", got)
+	}
+}
+
+func TestPDFTextWidth(t *testing.T) {
+	if got := pdf.TextWidth("证书", 10); got != 20 {
+		t.Fatalf("cjk width = %v", got)
+	}
+	if got := pdf.TextWidth("0", 10); got != 4.62 {
+		t.Fatalf("digit width = %v", got)
+	}
+}
+
+func TestPDFWrap(t *testing.T) {
+	lines := pdf.Wrap("一二三四五六七八九十", 10, 40)
+	if strings.Join(lines, "|") != "一二三四|五六七八|九十" {
+		t.Fatalf("cjk wrap = %q", lines)
+	}
+
+	// 英文按单词换行，保留显式换行
+	lines = pdf.Wrap("hello world\n\nfoo", 10, 35)
+	if strings.Join(lines, "|") != "hello|world||foo" {
+		t.Fatalf("latin wrap = %q", lines)
+	}
+	for _, line := range pdf.Wrap("This certificate is awarded in recognition of outstanding performance", 16, 200) {
+		if pdf.TextWidth(line, 16) > 200 {
+			t.Fatalf("line too wide: %q", line)
+		}
+	}
+}
+
+func TestPDFWriteStructure(t *testing.T) {
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	doc.Title = "合格证书"
+	for i := 0; i < 2; i++ {
+		page := doc.AddPage()
+		page.Rect(10, 10, 100, 50, nil, &pdf.Black, 1)
+		page.TextCentered(page.Width()/2, 400, 20, pdf.Black, i == 0, "考试证书")
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("missing header or trailer")
+	}
+
+	// startxref 指向 xref 表，表中每个偏移量都指向对应对象
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	if startxref == nil {
+		t.Fatal("missing startxref")
+	}
+	offset, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(data[offset:], []byte("xref\n0 11\n")) {
+		t.Fatalf("startxref does not point at xref: %q", data[offset:offset+10])
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[offset:], -1)
+	if len(entries) != 10 {
+		t.Fatalf("expected 10 objects, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		pos, _ := strconv.Atoi(string(entry[1]))
+		if want := strconv.Itoa(i+1) + " 0 obj\n"; !bytes.HasPrefix(data[pos:], []byte(want)) {
+			t.Fatalf("xref entry %d points at %q", i+1, data[pos:pos+10])
+		}
+	}
+
+	// 页面内容流可解压，且包含编码后的文字
+	stream := regexp.MustCompile(`(?s)8 0 obj\n<< /Filter /FlateDecode /Length (\d+) >>\nstream\n`).FindSubmatchIndex(data)
+	if stream == nil {
+		t.Fatal("missing content stream")
+	}
+	length, _ := strconv.Atoi(string(data[stream[2]:stream[3]]))
+	zr, err := zlib.NewReader(bytes.NewReader(data[stream[1] : stream[1]+length]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(zr)
+	if !strings.Contains(string(content), "<"+pdf.EncodeText("考试证书")+"> Tj") || !strings.Contains(string(content), "2 Tr") {
+		t.Fatalf("unexpected content stream: %s", content)
+	}
+}
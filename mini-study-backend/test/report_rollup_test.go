@@ -0,0 +1,164 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/search"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+func newRollupService(db *gorm.DB) *service.ReportRollupService {
+	return service.NewReportRollupService(repository.NewReportRollupRepository(db), repository.NewLearningRecordRepository(db),
+		repository.NewAnalyticsRepository(db), repository.NewSchedulerLeaseRepository(db),
+		service.RollupOptions{NightlyHour: 2, InitialDays: 7, LeaseTTL: time.Minute})
+}
+
+func newExamService(t *testing.T, db *gorm.DB) *service.ExamService {
+	t.Helper()
+	userRepo := repository.NewUserRepository(db)
+	relationRepo := repository.NewManagerEmployeeRepository(db)
+	examRepo := repository.NewExamRepository(db)
+	attemptRepo := repository.NewExamAttemptRepository(db)
+	contentRepo := repository.NewContentRepository(db)
+	audit := service.NewAuditService(repository.NewAuditRepository(db), userRepo)
+	searchSvc := service.NewSearchService(search.NewMemoryIndex(), contentRepo, repository.NewContentCategoryRepository(db),
+		repository.NewContentPageRepository(db), examRepo, repository.NewGrowthPostRepository(db), userRepo)
+	reviews := service.NewReviewService(repository.NewReviewEventRepository(db), contentRepo, examRepo, userRepo, audit)
+	certificates, _ := newCertificateService(t, db)
+	return service.NewExamService(examRepo, attemptRepo, userRepo, relationRepo, repository.NewLearningRecordRepository(db), contentRepo,
+		repository.NewReportRollupRepository(db), audit, searchSvc, reviews, certificates, newCertificationService(db))
+}
+
+func createExam(t *testing.T, db *gorm.DB, title string) *model.ExamPaper {
+	t.Helper()
+	exam := &model.ExamPaper{Title: title, Status: "published", PassScore: 60, TotalScore: 100}
+	if err := db.Create(exam).Error; err != nil {
+		t.Fatalf("create exam: %v", err)
+	}
+	return exam
+}
+
+// createAttempt inserts an attempt submitted at the given time, in the user's next cycle of the exam.
+func createAttempt(t *testing.T, db *gorm.DB, examID, userID uint, at time.Time, score int, pass bool) *model.ExamAttempt {
+	t.Helper()
+	cycle := int(countRows(t, db, &model.ExamAttempt{}, "user_id = ? AND exam_id = ?", userID, examID)) + 1
+	attempt := &model.ExamAttempt{ExamID: examID, UserID: userID, Cycle: cycle, Status: "completed", Score: score, Pass: pass, SubmittedAt: &at}
+	attempt.CreatedAt = at
+	if err := db.Omit("Exam").Create(attempt).Error; err != nil {
+		t.Fatalf("create attempt: %v", err)
+	}
+	return attempt
+}
+
+func dailyStat(t *testing.T, db *gorm.DB, userID uint, day time.Time) *model.UserDailyStat {
+	t.Helper()
+	var stat model.UserDailyStat
+	if err := db.Where("user_id = ? AND stat_date = ?", userID, day).Limit(1).Find(&stat).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stat.ID == 0 {
+		return nil
+	}
+	return &stat
+}
+
+func TestReportRollupRefreshAndBackfill(t *testing.T) {
+	db := newTestDB(t)
+	rollups := newRollupService(db)
+	exams := newExamService(t, db)
+	manager := createUser(t, db, model.RoleManager, "M1")
+	early := createUser(t, db, model.RoleEmployee, "E1")
+	late := createUser(t, db, model.RoleEmployee, "E2")
+	bindManager(t, db, manager.ID, early.ID)
+	bindManager(t, db, manager.ID, late.ID)
+	exam := createExam(t, db, "安全考试")
+	content := createContent(t, db, "doc", "")
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	oldDay, recentDay := today.AddDate(0, 0, -20), today.AddDate(0, 0, -2)
+	createAttempt(t, db, exam.ID, early.ID, oldDay.Add(10*time.Hour), 90, true)
+	createAttempt(t, db, exam.ID, early.ID, recentDay.Add(10*time.Hour), 40, false)
+	completedAt := recentDay.Add(9 * time.Hour)
+	if err := db.Create(&model.LearningRecord{UserID: early.ID, ContentID: content.ID, Status: "completed", Progress: 100, CompletedAt: &completedAt}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 首次运行只汇总最近几天的日数据，但用户汇总覆盖全部历史
+	if err := rollups.Refresh(now); err != nil {
+		t.Fatalf("first refresh: %v", err)
+	}
+	if stat := dailyStat(t, db, early.ID, recentDay); stat == nil || stat.ExamAttempts != 1 || stat.Completions != 1 {
+		t.Fatalf("recent day: %+v", stat)
+	}
+	if stat := dailyStat(t, db, early.ID, oldDay); stat != nil {
+		t.Fatalf("day before the initial window rolled up: %+v", stat)
+	}
+	overview, err := exams.GetManagerOverview(manager.ID)
+	if err != nil {
+		t.Fatalf("overview: %v", err)
+	}
+	if overview.RefreshedAt == nil || len(overview.ExamProgress) != 1 || len(overview.Employees) != 2 {
+		t.Fatalf("overview: %+v", overview)
+	}
+	if progress := overview.ExamProgress[0]; progress.AttemptCount != 2 || progress.PassRate != 0.5 || progress.AvgScore != 65 {
+		t.Fatalf("exam progress: %+v", progress)
+	}
+	first := overview.Employees[0]
+	if first.EmployeeID != early.ID || first.LatestExam == nil || first.LatestExam.Score != 40 || first.LearningProgress.Completed != 1 {
+		t.Fatalf("early employee: %+v", first)
+	}
+	if overview.Employees[1].LatestExam != nil {
+		t.Fatalf("late employee has an exam: %+v", overview.Employees[1])
+	}
+
+	// 增量汇总只重算水位线当天起的日数据，但会重建有变动的用户汇总
+	backdated := today.AddDate(0, 0, -3)
+	createAttempt(t, db, exam.ID, late.ID, backdated.Add(11*time.Hour), 80, true)
+	createAttempt(t, db, exam.ID, late.ID, time.Now(), 70, true)
+	if err := rollups.Refresh(time.Now()); err != nil {
+		t.Fatalf("incremental refresh: %v", err)
+	}
+	if stat := dailyStat(t, db, late.ID, today); stat == nil || stat.ExamAttempts != 1 || stat.ExamPassed != 1 {
+		t.Fatalf("today: %+v", stat)
+	}
+	if stat := dailyStat(t, db, late.ID, backdated); stat != nil {
+		t.Fatalf("day before the watermark recomputed: %+v", stat)
+	}
+	overview, err = exams.GetManagerOverview(manager.ID)
+	if err != nil {
+		t.Fatalf("overview: %v", err)
+	}
+	if progress := overview.ExamProgress[0]; progress.AttemptCount != 4 || progress.PassRate != 0.75 {
+		t.Fatalf("exam progress after refresh: %+v", progress)
+	}
+	if latest := overview.Employees[1].LatestExam; latest == nil || latest.Score != 70 {
+		t.Fatalf("late employee latest exam: %+v", latest)
+	}
+
+	days, err := rollups.Backfill(oldDay, today)
+	if err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	if days != 21 {
+		t.Fatalf("backfilled %d days, want 21", days)
+	}
+	if stat := dailyStat(t, db, early.ID, oldDay); stat == nil || stat.ExamAttempts != 1 || stat.ExamScoreSum != 90 {
+		t.Fatalf("backfilled old day: %+v", stat)
+	}
+	if stat := dailyStat(t, db, late.ID, backdated); stat == nil || stat.ExamPassed != 1 {
+		t.Fatalf("backfilled backdated day: %+v", stat)
+	}
+	// 回填可重复执行，不会重复计数
+	if _, err := rollups.Backfill(oldDay, today); err != nil {
+		t.Fatalf("second backfill: %v", err)
+	}
+	if got := countRows(t, db, &model.UserDailyStat{}, "user_id = ?", early.ID); got != 3 {
+		t.Fatalf("early employee daily rows = %d, want 3", got)
+	}
+}
@@ -0,0 +1,220 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/mailer"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/report"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+func TestNextRun(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	// 2026-10-19 是周一
+	after := time.Date(2026, 10, 19, 9, 30, 0, 0, loc)
+	cases := []struct {
+		schedule report.Schedule
+		want     time.Time
+	}{
+		{report.Schedule{Frequency: report.FrequencyDaily, Hour: 8}, time.Date(2026, 10, 20, 8, 0, 0, 0, loc)},
+		{report.Schedule{Frequency: report.FrequencyDaily, Hour: 18}, time.Date(2026, 10, 19, 18, 0, 0, 0, loc)},
+		{report.Schedule{Frequency: report.FrequencyWeekly, Weekday: 1, Hour: 8}, time.Date(2026, 10, 26, 8, 0, 0, 0, loc)},
+		{report.Schedule{Frequency: report.FrequencyWeekly, Weekday: 1, Hour: 10}, time.Date(2026, 10, 19, 10, 0, 0, 0, loc)},
+		{report.Schedule{Frequency: report.FrequencyWeekly, Weekday: 7, Hour: 8}, time.Date(2026, 10, 25, 8, 0, 0, 0, loc)},
+		{report.Schedule{Frequency: report.FrequencyMonthly, MonthDay: 1, Hour: 8}, time.Date(2026, 11, 1, 8, 0, 0, 0, loc)},
+		{report.Schedule{Frequency: report.FrequencyMonthly, MonthDay: 28, Hour: 8}, time.Date(2026, 10, 28, 8, 0, 0, 0, loc)},
+	}
+	for _, c := range cases {
+		if got := report.NextRun(c.schedule, after); !got.Equal(c.want) {
+			t.Errorf("NextRun(%+v) = %v, want %v", c.schedule, got, c.want)
+		}
+	}
+
+	// 12 月之后跨年
+	dec := time.Date(2026, 12, 15, 0, 0, 0, 0, loc)
+	if got := report.NextRun(report.Schedule{Frequency: report.FrequencyMonthly, MonthDay: 1}, dec); !got.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, loc)) {
+		t.Errorf("monthly across year = %v", got)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for index, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := report.ColumnName(index); got != want {
+			t.Errorf("ColumnName(%d) = %s, want %s", index, got, want)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	table := &report.Table{Columns: []string{"姓名", "积分", "通过"}}
+	table.AddRow("张三, Jr.", int64(120), true)
+	table.AddRow("李四", 0, nil)
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf, table); err != nil {
+		t.Fatal(err)
+	}
+	want := "\xEF\xBB\xBF姓名,积分,通过\n\"张三, Jr.\",120,是\n李四,0,\n"
+	if buf.String() != want {
+		t.Fatalf("csv = %q", buf.String())
+	}
+}
+
+func TestFormatCellEscapesFormulas(t *testing.T) {
+	cases := map[interface{}]string{
+		"=HYPERLINK(\"http://x\")": "'=HYPERLINK(\"http://x\")",
+		"+86 138":                  "'+86 138",
+		"-1+1":                     "'-1+1",
+		"@SUM(A1)":                 "'@SUM(A1)",
+		"\t=1":                     "'\t=1",
+		"张三":                       "张三",
+		"a=b":                      "a=b",
+		"":                         "",
+		int64(-5):                  "-5",
+		-2.5:                       "-2.5",
+	}
+	for cell, want := range cases {
+		if got := report.FormatCell(cell); got != want {
+			t.Errorf("FormatCell(%q) = %q, want %q", cell, got, want)
+		}
+	}
+
+	table := &report.Table{Columns: []string{"姓名"}}
+	table.AddRow("=1+1")
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf, table); err != nil {
+		t.Fatal(err)
+	}
+	if want := "\xEF\xBB\xBF姓名\n'=1+1\n"; buf.String() != want {
+		t.Fatalf("csv = %q", buf.String())
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	table := &report.Table{Title: "团队学习汇总[本周]", Columns: []string{"姓名", "完成率(%)"}}
+	table.AddRow("<王五> & 赵六", 87.5)
+
+	var buf bytes.Buffer
+	if err := report.WriteXLSX(&buf, table); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(data)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Fatalf("missing part %s", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="团队学习汇总本周"`) {
+		t.Fatalf("sheet name not sanitized: %s", parts["xl/workbook.xml"])
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">姓名</t></is></c>`,
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">&lt;王五&gt; &amp; 赵六</t></is></c>`,
+		`<c r="B2"><v>87.5</v></c>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Fatalf("sheet missing %s:\n%s", want, sheet)
+		}
+	}
+}
+
+// flakyMailer fails the first failures sends and records the rest.
+type flakyMailer struct {
+	failures int
+	sent     []mailer.Message
+}
+
+func (m *flakyMailer) Send(_ context.Context, msg mailer.Message) error {
+	if m.failures > 0 {
+		m.failures--
+		return errors.New("smtp unavailable")
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestReportDeliveryRetriesWithBackoff(t *testing.T) {
+	db := newTestDB(t)
+	mail := &flakyMailer{failures: 2}
+	userRepo := repository.NewUserRepository(db)
+	points := service.NewPointService(repository.NewPointRepository(db), userRepo, service.NewAuditService(repository.NewAuditRepository(db), userRepo))
+	svc := service.NewReportService(repository.NewReportScheduleRepository(db), userRepo, newExamService(t, db), points,
+		repository.NewSchedulerLeaseRepository(db), mail, time.Minute)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+
+	created, err := svc.CreateSchedule(admin.ID, dto.ReportScheduleRequest{Report: service.ReportTeamLearning, Recipient: "boss@example.com", Frequency: "daily", Hour: 8})
+	if err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+	due := time.Date(2030, 1, 1, 8, 0, 0, 0, time.Local)
+	regular := due.AddDate(0, 0, 1)
+	if err := db.Model(&model.ReportSchedule{}).Where("id = ?", created.ID).Update("next_run_at", due).Error; err != nil {
+		t.Fatal(err)
+	}
+	load := func() model.ReportSchedule {
+		t.Helper()
+		var schedule model.ReportSchedule
+		if err := db.First(&schedule, created.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return schedule
+	}
+
+	// 失败后依次等待 5、10 分钟重试，成功后恢复按计划发送
+	now := due
+	for i, wait := range []time.Duration{5 * time.Minute, 10 * time.Minute} {
+		if sent, err := svc.DeliverDue(ctx, now); err == nil || sent != 0 {
+			t.Fatalf("attempt %d: sent %d, err %v", i+1, sent, err)
+		}
+		schedule := load()
+		if schedule.LastStatus != service.ReportDeliveryFailed || schedule.FailedAttempts != i+1 || !schedule.NextRunAt.Equal(now.Add(wait)) {
+			t.Fatalf("after failure %d: %+v", i+1, schedule)
+		}
+		now = now.Add(wait)
+	}
+	if sent, err := svc.DeliverDue(ctx, now); err != nil || sent != 1 {
+		t.Fatalf("retry: sent %d, err %v", sent, err)
+	}
+	schedule := load()
+	if schedule.LastStatus != service.ReportDeliverySent || schedule.FailedAttempts != 0 || !schedule.NextRunAt.Equal(regular) || len(mail.sent) != 1 {
+		t.Fatalf("after retry: %+v", schedule)
+	}
+
+	// 重试用尽后等到下一次计划时间，并重新计算重试次数
+	mail.failures = 1
+	if err := db.Model(&model.ReportSchedule{}).Where("id = ?", created.ID).Update("failed_attempts", 5).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.DeliverDue(ctx, regular); err == nil {
+		t.Fatal("delivery did not fail")
+	}
+	schedule = load()
+	if schedule.FailedAttempts != 0 || !schedule.NextRunAt.Equal(regular.AddDate(0, 0, 1)) {
+		t.Fatalf("after exhausted retries: %+v", schedule)
+	}
+}
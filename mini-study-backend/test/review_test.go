@@ -0,0 +1,272 @@
+package test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+func newReviewService(db *gorm.DB) *service.ReviewService {
+	userRepo := repository.NewUserRepository(db)
+	audit := service.NewAuditService(repository.NewAuditRepository(db), userRepo)
+	return service.NewReviewService(repository.NewReviewEventRepository(db), repository.NewContentRepository(db), repository.NewExamRepository(db), userRepo, audit)
+}
+
+func TestNextEditorialStatus(t *testing.T) {
+	cases := []struct {
+		name      string
+		current   string
+		requested string
+		edited    bool
+		want      string
+		wantErr   bool
+	}{
+		{name: "draft cannot be published", current: "draft", requested: "published", wantErr: true},
+		{name: "approved can be published", current: "approved", requested: "published", want: "published"},
+		{name: "editing approved returns to draft", current: "approved", edited: true, want: "draft"},
+		{name: "echoed status on edit is ignored", current: "approved", requested: "approved", edited: true, want: "draft"},
+		{name: "edited approved cannot be published", current: "approved", requested: "published", edited: true, wantErr: true},
+		{name: "in review is locked", current: "in_review", edited: true, wantErr: true},
+		{name: "in review untouched", current: "in_review", requested: "in_review", want: "in_review"},
+		{name: "editing published returns to draft", current: "published", edited: true, want: "draft"},
+		{name: "echoed published on edit is ignored", current: "published", requested: "published", edited: true, want: "draft"},
+		{name: "unedited published stays live", current: "published", requested: "published", want: "published"},
+		{name: "edited published cannot be taken offline", current: "published", requested: "offline", edited: true, wantErr: true},
+		{name: "published can go offline", current: "published", requested: "offline", want: "offline"},
+		{name: "unchanged offline can be republished", current: "offline", requested: "published", want: "published"},
+		{name: "edited archive needs review", current: "archived", requested: "published", edited: true, wantErr: true},
+		{name: "draft cannot be archived", current: "draft", requested: "archived", wantErr: true},
+		{name: "approval only through review", current: "draft", requested: "approved", wantErr: true},
+	}
+	for _, tc := range cases {
+		got, err := service.NextEditorialStatus(tc.current, tc.requested, tc.edited)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got %q", tc.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("%s: got %q, %v, want %q", tc.name, got, err, tc.want)
+		}
+	}
+}
+
+func TestReviewWorkflow(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	reviews := newReviewService(db)
+	contents := newContentService(db)
+	submitter := createUser(t, db, model.RoleAdmin, "A1")
+	reviewer := createUser(t, db, model.RoleAdmin, "A2")
+	other := createUser(t, db, model.RoleAdmin, "A3")
+	employee := createUser(t, db, model.RoleEmployee, "E1")
+	content := createContent(t, db, "doc", "")
+	if err := db.Model(content).Update("status", model.EditorialDraft).Error; err != nil {
+		t.Fatal(err)
+	}
+	entity := service.AuditEntityContent
+
+	if _, err := reviews.Submit(ctx, employee.ID, entity, content.ID, dto.ReviewSubmitRequest{}); err == nil {
+		t.Fatal("employee submitted a review")
+	}
+	if _, err := reviews.Submit(ctx, submitter.ID, entity, content.ID, dto.ReviewSubmitRequest{ReviewerID: submitter.ID}); err == nil || err.Error() != "不能指定自己为审核人" {
+		t.Fatalf("self-assigned reviewer: %v", err)
+	}
+	// 未审核通过的条目不能发布
+	if _, err := contents.AdminUpdateContent(ctx, submitter.ID, content.ID, dto.AdminUpdateContentRequest{Status: model.EditorialPublished}); err == nil {
+		t.Fatal("published a draft")
+	}
+
+	detail, err := reviews.Submit(ctx, submitter.ID, entity, content.ID, dto.ReviewSubmitRequest{ReviewerID: reviewer.ID, Comment: "请审核"})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if detail.Status != model.EditorialInReview || detail.SubmittedBy != submitter.ID || detail.ReviewerID != reviewer.ID {
+		t.Fatalf("submitted: %+v", detail.ReviewItemResponse)
+	}
+	if _, err := contents.AdminUpdateContent(ctx, submitter.ID, content.ID, dto.AdminUpdateContentRequest{Title: "审核中修改"}); err == nil {
+		t.Fatal("edited a submission under review")
+	}
+	if _, err := reviews.AssignReviewer(ctx, reviewer.ID, entity, content.ID, dto.ReviewAssignRequest{ReviewerID: submitter.ID}); err == nil {
+		t.Fatal("assigned the submitter as reviewer")
+	}
+	if _, err := reviews.Approve(ctx, other.ID, entity, content.ID, dto.ReviewApproveRequest{}); err == nil {
+		t.Fatal("approved by an admin who is not the assigned reviewer")
+	}
+
+	detail, err = reviews.Reject(ctx, reviewer.ID, entity, content.ID, dto.ReviewRejectRequest{Reason: "数据需要更新"})
+	if err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	if detail.Status != model.EditorialDraft || detail.RejectReason != "数据需要更新" || detail.ReviewedBy != reviewer.ID {
+		t.Fatalf("rejected: %+v", detail.ReviewItemResponse)
+	}
+	if _, err := reviews.Approve(ctx, reviewer.ID, entity, content.ID, dto.ReviewApproveRequest{}); err == nil {
+		t.Fatal("approved a draft")
+	}
+
+	// 仅提交人可以撤回，撤回后回到草稿
+	if _, err := reviews.Submit(ctx, submitter.ID, entity, content.ID, dto.ReviewSubmitRequest{}); err != nil {
+		t.Fatalf("resubmit: %v", err)
+	}
+	if _, err := reviews.Withdraw(ctx, reviewer.ID, entity, content.ID); err == nil {
+		t.Fatal("withdrawn by someone other than the submitter")
+	}
+	if detail, err = reviews.Withdraw(ctx, submitter.ID, entity, content.ID); err != nil || detail.Status != model.EditorialDraft {
+		t.Fatalf("withdraw: %v", err)
+	}
+
+	if _, err := reviews.Submit(ctx, submitter.ID, entity, content.ID, dto.ReviewSubmitRequest{}); err != nil {
+		t.Fatalf("submit again: %v", err)
+	}
+	detail, err = reviews.Approve(ctx, reviewer.ID, entity, content.ID, dto.ReviewApproveRequest{Comment: "内容准确"})
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if detail.Status != model.EditorialApproved || detail.ReviewedBy != reviewer.ID || detail.RejectReason != "" {
+		t.Fatalf("approved: %+v", detail.ReviewItemResponse)
+	}
+
+	published, err := contents.AdminUpdateContent(ctx, submitter.ID, content.ID, dto.AdminUpdateContentRequest{Status: model.EditorialPublished})
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if published.Status != model.EditorialPublished || published.PublishAt == nil {
+		t.Fatalf("published: %+v", published)
+	}
+
+	// 发布后修改回到草稿，需重新审核才能再次发布
+	edited, err := contents.AdminUpdateContent(ctx, submitter.ID, content.ID, dto.AdminUpdateContentRequest{Title: "发布后修改"})
+	if err != nil {
+		t.Fatalf("edit after publish: %v", err)
+	}
+	if edited.Status != model.EditorialDraft {
+		t.Fatalf("status after editing published content = %s", edited.Status)
+	}
+	if _, err := contents.AdminUpdateContent(ctx, submitter.ID, content.ID, dto.AdminUpdateContentRequest{Status: model.EditorialPublished}); err == nil {
+		t.Fatal("republished edited content without review")
+	}
+
+	detail, err = reviews.Detail(submitter.ID, entity, content.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		model.ReviewActionSubmit, model.ReviewActionReject,
+		model.ReviewActionSubmit, model.ReviewActionWithdraw,
+		model.ReviewActionSubmit, model.ReviewActionApprove,
+		model.ReviewActionPublish, model.ReviewActionRevert,
+	}
+	if len(detail.History) != len(want) {
+		t.Fatalf("history = %+v", detail.History)
+	}
+	for i, event := range detail.History {
+		if event.Action != want[i] {
+			t.Fatalf("history[%d] = %s, want %s", i, event.Action, want[i])
+		}
+	}
+}
+
+func TestReviewSubmitterCannotDecide(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	reviews := newReviewService(db)
+	submitter := createUser(t, db, model.RoleAdmin, "A1")
+	other := createUser(t, db, model.RoleAdmin, "A2")
+	exam := createExam(t, db, "安全考试")
+	if err := db.Model(exam).Update("status", model.EditorialDraft).Error; err != nil {
+		t.Fatal(err)
+	}
+	entity := service.AuditEntityExam
+
+	// 未指定审核人时，除提交人外的任意管理员可以审核
+	if _, err := reviews.Submit(ctx, submitter.ID, entity, exam.ID, dto.ReviewSubmitRequest{}); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if _, err := reviews.Approve(ctx, submitter.ID, entity, exam.ID, dto.ReviewApproveRequest{}); err == nil || err.Error() != "不能审核自己提交的条目" {
+		t.Fatalf("self approval: %v", err)
+	}
+	if _, err := reviews.Reject(ctx, submitter.ID, entity, exam.ID, dto.ReviewRejectRequest{Reason: "自行驳回"}); err == nil {
+		t.Fatal("submitter rejected their own submission")
+	}
+	detail, err := reviews.Approve(ctx, other.ID, entity, exam.ID, dto.ReviewApproveRequest{})
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if detail.Status != model.EditorialApproved || detail.ReviewedBy != other.ID {
+		t.Fatalf("approved: %+v", detail.ReviewItemResponse)
+	}
+}
+
+func TestReviewQueueIsPaged(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	reviews := newReviewService(db)
+	submitter := createUser(t, db, model.RoleAdmin, "A1")
+	reviewer := createUser(t, db, model.RoleAdmin, "A2")
+	first, last := createContent(t, db, "doc", ""), createContent(t, db, "doc", "")
+	exam := createExam(t, db, "安全考试")
+	for _, target := range []interface{}{first, last, exam} {
+		if err := db.Model(target).Update("status", model.EditorialDraft).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := reviews.Submit(ctx, submitter.ID, service.AuditEntityContent, first.ID, dto.ReviewSubmitRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reviews.Submit(ctx, submitter.ID, service.AuditEntityContent, last.ID, dto.ReviewSubmitRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reviews.Submit(ctx, submitter.ID, service.AuditEntityExam, exam.ID, dto.ReviewSubmitRequest{ReviewerID: reviewer.ID}); err != nil {
+		t.Fatal(err)
+	}
+	// 内容与考试合并后按提交时间从早到晚分页
+	now := time.Now()
+	for i, target := range []interface{}{first, exam, last} {
+		if err := db.Model(target).Update("submitted_at", now.Add(time.Duration(i-3)*time.Hour)).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pageOf := func(query dto.ReviewQueueQuery) ([]string, dto.Pagination) {
+		t.Helper()
+		items, page, err := reviews.Queue(reviewer.ID, query)
+		if err != nil {
+			t.Fatalf("queue: %v", err)
+		}
+		keys := make([]string, 0, len(items))
+		for _, item := range items {
+			keys = append(keys, item.EntityType+":"+strconv.FormatUint(uint64(item.EntityID), 10))
+		}
+		return keys, page
+	}
+	key := func(entityType string, id uint) string {
+		return entityType + ":" + strconv.FormatUint(uint64(id), 10)
+	}
+	keys, page := pageOf(dto.ReviewQueueQuery{PageQuery: dto.PageQuery{PageSize: 2}})
+	if len(keys) != 2 || keys[0] != key(service.AuditEntityContent, first.ID) || keys[1] != key(service.AuditEntityExam, exam.ID) || page.Total != 3 || !page.HasMore {
+		t.Fatalf("first page: %v %+v", keys, page)
+	}
+	keys, page = pageOf(dto.ReviewQueueQuery{PageQuery: dto.PageQuery{Page: 2, PageSize: 2}})
+	if len(keys) != 1 || keys[0] != key(service.AuditEntityContent, last.ID) || page.HasMore {
+		t.Fatalf("second page: %v %+v", keys, page)
+	}
+	if keys, page = pageOf(dto.ReviewQueueQuery{EntityType: service.AuditEntityExam}); len(keys) != 1 || page.Total != 1 {
+		t.Fatalf("exams: %v %+v", keys, page)
+	}
+	if keys, _ = pageOf(dto.ReviewQueueQuery{Mine: true}); len(keys) != 1 || keys[0] != key(service.AuditEntityExam, exam.ID) {
+		t.Fatalf("mine: %v", keys)
+	}
+	if _, _, err := reviews.Queue(reviewer.ID, dto.ReviewQueueQuery{PageQuery: dto.PageQuery{Sort: "reviewer_id"}}); err == nil {
+		t.Fatal("sorted by a field that is not whitelisted")
+	}
+}
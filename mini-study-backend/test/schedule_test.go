@@ -0,0 +1,229 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+// newScheduleService builds one replica's ScheduleService on top of its own search index.
+func newScheduleService(db *gorm.DB, searchSvc *service.SearchService) *service.ScheduleService {
+	userRepo := repository.NewUserRepository(db)
+	contentRepo := repository.NewContentRepository(db)
+	examRepo := repository.NewExamRepository(db)
+	audit := service.NewAuditService(repository.NewAuditRepository(db), userRepo)
+	reviews := service.NewReviewService(repository.NewReviewEventRepository(db), contentRepo, examRepo, userRepo, audit)
+	return service.NewScheduleService(repository.NewSchedulerLeaseRepository(db), contentRepo, examRepo, userRepo, audit, searchSvc, reviews, time.Minute)
+}
+
+func TestSchedulerLease(t *testing.T) {
+	db := newTestDB(t)
+	leases := repository.NewSchedulerLeaseRepository(db)
+	now := time.Now()
+
+	if held, err := leases.Acquire("job", "a", now, now.Add(time.Minute)); err != nil || !held {
+		t.Fatalf("first acquire: held=%v err=%v", held, err)
+	}
+	if held, err := leases.Acquire("job", "b", now.Add(30*time.Second), now.Add(90*time.Second)); err != nil || held {
+		t.Fatalf("second holder while the lease is live: held=%v err=%v", held, err)
+	}
+	if held, err := leases.Acquire("job", "a", now.Add(30*time.Second), now.Add(90*time.Second)); err != nil || !held {
+		t.Fatalf("renew: held=%v err=%v", held, err)
+	}
+
+	// 持有者停止续约后，其他实例在租约到期后接管
+	later := now.Add(2 * time.Minute)
+	if held, err := leases.Acquire("job", "b", later, later.Add(time.Minute)); err != nil || !held {
+		t.Fatalf("takeover after expiry: held=%v err=%v", held, err)
+	}
+	if held, err := leases.Acquire("job", "a", later, later.Add(time.Minute)); err != nil || held {
+		t.Fatalf("old holder after takeover: held=%v err=%v", held, err)
+	}
+
+	// 只有当前持有者的释放生效，释放后其他实例可立即接管
+	if err := leases.Release("job", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if held, _ := leases.Acquire("job", "a", later, later.Add(time.Minute)); held {
+		t.Fatal("release by a former holder freed the lease")
+	}
+	if err := leases.Release("job", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if held, err := leases.Acquire("job", "a", later, later.Add(time.Minute)); err != nil || !held {
+		t.Fatalf("acquire after release: held=%v err=%v", held, err)
+	}
+	if got := countRows(t, db, &model.SchedulerLease{}, "name = ?", "job"); got != 1 {
+		t.Fatalf("lease rows = %d, want 1", got)
+	}
+}
+
+func TestScheduleRunDue(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	primarySearch, replicaSearch := newSearchService(db), newSearchService(db)
+	primary, replica := newScheduleService(db, primarySearch), newScheduleService(db, replicaSearch)
+	employee := createUser(t, db, model.RoleEmployee, "E1")
+
+	now := time.Now()
+	publishAt, offlineAt := now.Add(-2*time.Hour), now.Add(-time.Hour)
+	// 停机期间发布与下线都已到期：先发布再下线
+	both := createContent(t, db, "doc", "")
+	// 只到期发布：发布后可被搜索到
+	published := createContent(t, db, "doc", "")
+	// 计划后被改回草稿：只清除计划，不发布
+	draft := createContent(t, db, "doc", "")
+	for _, c := range []struct {
+		content *model.Content
+		fields  map[string]interface{}
+	}{
+		{both, map[string]interface{}{"status": model.EditorialApproved, "summary": "门店陈列", "scheduled_publish_at": publishAt, "scheduled_offline_at": offlineAt}},
+		{published, map[string]interface{}{"status": model.EditorialApproved, "summary": "门店陈列", "scheduled_publish_at": publishAt}},
+		{draft, map[string]interface{}{"status": model.EditorialDraft, "scheduled_publish_at": publishAt}},
+	} {
+		if err := db.Model(c.content).Updates(c.fields).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	exam := &model.ExamPaper{Title: "安全考试", Status: model.EditorialApproved, ScheduledPublishAt: &publishAt}
+	if err := db.Create(exam).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := primary.RunDue(ctx, now)
+	if err != nil {
+		t.Fatalf("run due: %v", err)
+	}
+	if applied != 5 {
+		t.Fatalf("applied %d changes, want 5", applied)
+	}
+	var got model.Content
+	if err := db.First(&got, both.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != model.EditorialOffline || got.PublishAt == nil || got.ScheduledPublishAt != nil || got.ScheduledOfflineAt != nil {
+		t.Fatalf("published then offline: %+v", got)
+	}
+	var publishedNow model.Content
+	if err := db.First(&publishedNow, published.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if publishedNow.Status != model.EditorialPublished || publishedNow.PublishAt == nil {
+		t.Fatalf("published: %+v", publishedNow)
+	}
+	var draftNow model.Content
+	if err := db.First(&draftNow, draft.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if draftNow.Status != model.EditorialDraft || draftNow.ScheduledPublishAt != nil {
+		t.Fatalf("draft: %+v", draftNow)
+	}
+	var examNow model.ExamPaper
+	if err := db.First(&examNow, exam.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if examNow.Status != model.EditorialPublished || examNow.ScheduledPublishAt != nil {
+		t.Fatalf("exam: %+v", examNow)
+	}
+	// 审计日志按执行顺序记录发布与下线，各一次
+	var actions []string
+	if err := db.Model(&model.AuditLog{}).Where("entity_type = ? AND entity_id = ?", service.AuditEntityContent, both.ID).Order("id").Pluck("action", &actions).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 2 || actions[0] != "scheduled_publish_content" || actions[1] != "scheduled_offline_content" {
+		t.Fatalf("audit actions = %v", actions)
+	}
+
+	// 租约有效期内其他实例不执行
+	if err := db.Model(draft).Update("scheduled_offline_at", now.Add(-time.Minute)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if applied, err := replica.RunDue(ctx, now.Add(30*time.Second)); err != nil || applied != 0 {
+		t.Fatalf("replica while the lease is live: applied=%d err=%v", applied, err)
+	}
+	// 租约过期后其他实例接管并执行剩余的计划
+	if applied, err := replica.RunDue(ctx, now.Add(2*time.Minute)); err != nil || applied != 1 {
+		t.Fatalf("replica after takeover: applied=%d err=%v", applied, err)
+	}
+
+	// 条件更新：计划已被执行后，持有旧数据的实例不会再次执行
+	contents := repository.NewContentRepository(db)
+	if ok, err := contents.ApplySchedule(both.ID, "scheduled_publish_at", now, map[string]interface{}{"status": model.EditorialPublished}); err != nil || ok {
+		t.Fatalf("double apply: ok=%v err=%v", ok, err)
+	}
+	if err := db.First(&got, both.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != model.EditorialOffline {
+		t.Fatalf("status after the stale apply = %s", got.Status)
+	}
+
+	// 执行计划的实例同步更新了自己的索引：已发布的可被搜索到，先发布后下线的不在结果中
+	resp, err := primarySearch.Search(ctx, employee.ID, dto.SearchQuery{Q: "门店陈列"})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].ID != published.ID {
+		t.Fatalf("search hits after the schedule: %+v", resp.Items)
+	}
+}
+
+func TestScheduleValidation(t *testing.T) {
+	db := newTestDB(t)
+	svc := newScheduleService(db, newSearchService(db))
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	employee := createUser(t, db, model.RoleEmployee, "E1")
+	published := createContent(t, db, "doc", "")
+	approved := createContent(t, db, "doc", "")
+	draft := createContent(t, db, "doc", "")
+	if err := db.Model(approved).Update("status", model.EditorialApproved).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(draft).Update("status", model.EditorialDraft).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	at := func(d time.Duration) *time.Time {
+		when := now.Add(d)
+		return &when
+	}
+	cases := []struct {
+		name      string
+		contentID uint
+		req       dto.AdminScheduleRequest
+		want      string
+	}{
+		{"publish a published content", published.ID, dto.AdminScheduleRequest{PublishAt: at(time.Hour)}, "已发布，无需定时发布"},
+		{"publish a draft", draft.ID, dto.AdminScheduleRequest{PublishAt: at(time.Hour)}, "审核通过后才能定时发布"},
+		{"publish in the past", approved.ID, dto.AdminScheduleRequest{PublishAt: at(-time.Hour)}, "定时发布时间必须晚于当前时间"},
+		{"offline in the past", published.ID, dto.AdminScheduleRequest{OfflineAt: at(-time.Hour)}, "定时下线时间必须晚于当前时间"},
+		{"offline without publishing", approved.ID, dto.AdminScheduleRequest{OfflineAt: at(time.Hour)}, "未发布且未设置定时发布，无法定时下线"},
+		{"offline before publishing", approved.ID, dto.AdminScheduleRequest{PublishAt: at(2 * time.Hour), OfflineAt: at(time.Hour)}, "定时下线时间必须晚于定时发布时间"},
+	}
+	for _, tc := range cases {
+		_, err := svc.AdminScheduleContent(ctx, admin.ID, tc.contentID, tc.req)
+		if err == nil || err.Error() != tc.want {
+			t.Errorf("%s: got %v, want %q", tc.name, err, tc.want)
+		}
+	}
+
+	state, err := svc.AdminScheduleContent(ctx, admin.ID, approved.ID, dto.AdminScheduleRequest{PublishAt: at(time.Hour), OfflineAt: at(2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("valid schedule: %v", err)
+	}
+	if state.ScheduledPublishAt == nil || state.ScheduledOfflineAt == nil {
+		t.Fatalf("state: %+v", state)
+	}
+	if _, err := svc.AdminScheduleContent(ctx, employee.ID, approved.ID, dto.AdminScheduleRequest{}); err == nil {
+		t.Fatal("employee scheduled a content")
+	}
+}
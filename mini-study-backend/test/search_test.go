@@ -0,0 +1,152 @@
+package test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/search"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+// newSearchService builds a SearchService with its own memory index, like one replica.
+func newSearchService(db *gorm.DB) *service.SearchService {
+	return service.NewSearchService(search.NewMemoryIndex(), repository.NewContentRepository(db), repository.NewContentCategoryRepository(db),
+		repository.NewContentPageRepository(db), repository.NewExamRepository(db), repository.NewGrowthPostRepository(db), repository.NewUserRepository(db))
+}
+
+func TestQueryTermsSplitsChineseIntoBigrams(t *testing.T) {
+	got := search.QueryTerms("门店陈列 SOP，ＡＢＣ")
+	want := []string{"门店", "店陈", "陈列", "sop", "abc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("query terms = %v, want %v", got, want)
+	}
+}
+
+func TestMemoryIndexRanksAndRespectsVisibility(t *testing.T) {
+	ctx := context.Background()
+	index := search.NewMemoryIndex()
+	err := index.Rebuild(ctx, []search.Document{
+		{Kind: search.KindContent, ID: 1, Title: "门店陈列规范", Body: "新品上市时的陈列要求", Type: "doc", CategoryID: 1, Roles: []string{"employee"}},
+		{Kind: search.KindContent, ID: 2, Title: "收银流程", Body: "收银台附近的门店陈列需要保持整洁", Type: "video", CategoryID: 2, Roles: []string{"manager"}},
+		{Kind: search.KindGrowth, ID: 3, Title: "张店长", Body: "今天调整了门店陈列，销量提升明显"},
+		{Kind: search.KindExam, ID: 4, Title: "安全考试", Body: "消防通道不得堆放杂物"},
+	})
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+
+	result, err := index.Search(ctx, search.Query{Text: "门店陈列", Role: "employee", Limit: 10})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if result.Total != 2 {
+		t.Fatalf("employee should see 2 hits, got %d", result.Total)
+	}
+	if result.Hits[0].Key() != "content:1" {
+		t.Fatalf("title match should rank first, got %s", result.Hits[0].Key())
+	}
+	if result.Hits[0].TitleHighlight != "<em>门店陈列</em>规范" {
+		t.Fatalf("unexpected title highlight %q", result.Hits[0].TitleHighlight)
+	}
+	if result.Hits[1].BodyHighlight != "今天调整了<em>门店陈列</em>，销量提升明显" {
+		t.Fatalf("unexpected body highlight %q", result.Hits[1].BodyHighlight)
+	}
+
+	result, err = index.Search(ctx, search.Query{Text: "门店陈列", Limit: 10})
+	if err != nil {
+		t.Fatalf("admin search: %v", err)
+	}
+	if result.Total != 3 {
+		t.Fatalf("admin should see 3 hits, got %d", result.Total)
+	}
+	want := []search.FacetCount{{Value: "content", Count: 2}, {Value: "growth", Count: 1}}
+	if !reflect.DeepEqual(result.Facets[search.FacetKind], want) {
+		t.Fatalf("kind facet = %v, want %v", result.Facets[search.FacetKind], want)
+	}
+
+	result, err = index.Search(ctx, search.Query{Text: "门店陈列", Type: "video", Limit: 10})
+	if err != nil {
+		t.Fatalf("filtered search: %v", err)
+	}
+	if result.Total != 1 || result.Hits[0].ID != 2 {
+		t.Fatalf("type filter should keep only content 2, got %+v", result.Hits)
+	}
+
+	if err := index.Delete(ctx, search.KindGrowth, 3); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	result, _ = index.Search(ctx, search.Query{Text: "销量", Limit: 10})
+	if result.Total != 0 {
+		t.Fatalf("deleted document still found")
+	}
+}
+
+func TestSearchRechecksHitsChangedOnOtherReplicas(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	writer, reader := newSearchService(db), newSearchService(db)
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	employee := createUser(t, db, model.RoleEmployee, "E1")
+	offline := createContent(t, db, "doc", "")
+	restricted := createContent(t, db, "doc", "")
+	kept := createContent(t, db, "doc", "")
+	for _, content := range []*model.Content{offline, restricted, kept} {
+		if err := db.Model(content).Update("summary", "门店陈列规范").Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	post := &model.GrowthPost{CreatorID: employee.ID, Content: "今天调整了门店陈列", Status: service.GrowthStatusApproved, Scope: model.GrowthScopeCompany}
+	if err := db.Omit("Creator").Create(post).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, svc := range []*service.SearchService{writer, reader} {
+		if _, err := svc.Reindex(ctx); err != nil {
+			t.Fatalf("reindex: %v", err)
+		}
+	}
+
+	// 下线、改为店长可见与驳回都只在写入的实例上更新了索引
+	if err := db.Model(offline).Update("status", "offline").Error; err != nil {
+		t.Fatal(err)
+	}
+	offline.Status = "offline"
+	if err := writer.IndexContent(ctx, offline); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(restricted).Update("visible_roles", "manager").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(post).Update("status", service.GrowthStatusRejected).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := reader.Search(ctx, employee.ID, dto.SearchQuery{Q: "门店陈列"})
+	if err != nil {
+		t.Fatalf("employee search: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].ID != kept.ID || resp.Pagination.Total != 1 {
+		t.Fatalf("employee hits: %+v (%+v)", resp.Items, resp.Pagination)
+	}
+	resp, err = reader.Search(ctx, admin.ID, dto.SearchQuery{Q: "门店陈列"})
+	if err != nil {
+		t.Fatalf("admin search: %v", err)
+	}
+	if len(resp.Items) != 2 {
+		t.Fatalf("admin hits: %+v", resp.Items)
+	}
+
+	// 失效条目已从本实例索引中移除，后续分页与统计不再计入
+	resp, err = reader.Search(ctx, admin.ID, dto.SearchQuery{Q: "门店陈列"})
+	if err != nil {
+		t.Fatalf("second admin search: %v", err)
+	}
+	if resp.Pagination.Total != 2 || len(resp.Facets["kind"]) != 1 {
+		t.Fatalf("stale documents left in the index: %+v %+v", resp.Pagination, resp.Facets)
+	}
+}
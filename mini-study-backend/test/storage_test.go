@@ -0,0 +1,102 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/bootstrap"
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
+)
+
+func TestLocalStorageSignedURL(t *testing.T) {
+	ctx := context.Background()
+	signer := storage.NewURLSigner("test-secret")
+	store := storage.NewLocalStorage(t.TempDir(), t.TempDir(), "/uploads", "/api/v1/files/download", signer)
+
+	key := "private/document/ab/abcdef.pdf"
+	if err := store.Put(ctx, key, strings.NewReader("%PDF-1.4"), 8, "application/pdf"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	signed, err := store.SignedURL(ctx, key, time.Minute)
+	if err != nil {
+		t.Fatalf("signed url: %v", err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("parse signed url: %v", err)
+	}
+	if u.Path != "/api/v1/files/download/"+key {
+		t.Fatalf("unexpected path %s", u.Path)
+	}
+	expires, _ := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	signature := u.Query().Get("signature")
+
+	if err := signer.Verify(key, expires, signature, time.Now()); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := signer.Verify("private/document/ab/other.pdf", expires, signature, time.Now()); err == nil {
+		t.Fatalf("signature must be bound to the key")
+	}
+	if err := signer.Verify(key, expires, signature, time.Now().Add(2*time.Minute)); err == nil {
+		t.Fatalf("expired link must be rejected")
+	}
+
+	body, info, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	defer body.Close()
+	data, _ := io.ReadAll(body)
+	if string(data) != "%PDF-1.4" || info.ContentType != "application/pdf" {
+		t.Fatalf("unexpected object %q %s", data, info.ContentType)
+	}
+
+	if got := store.PublicURL("public/cover/ab/abcdef.png"); got != "/uploads/cover/ab/abcdef.png" {
+		t.Fatalf("unexpected public url %s", got)
+	}
+}
+
+func TestStorageRejectsInvalidKeys(t *testing.T) {
+	store := storage.NewLocalStorage(t.TempDir(), t.TempDir(), "/uploads", "/api/v1/files/download", storage.NewURLSigner("s"))
+	for _, key := range []string{"", "cover/a.png", "private/../../etc/passwd", "public/./a.png", "/private/a.pdf"} {
+		if _, err := store.Stat(context.Background(), key); !errors.Is(err, storage.ErrInvalidKey) {
+			t.Fatalf("key %q: expected ErrInvalidKey, got %v", key, err)
+		}
+	}
+}
+
+func TestDeriveSignSecret(t *testing.T) {
+	derived, err := bootstrap.DeriveSignSecret("jwt-secret")
+	if err != nil {
+		t.Fatalf("derive: %v", err)
+	}
+	again, _ := bootstrap.DeriveSignSecret("jwt-secret")
+	if derived != again {
+		t.Fatal("derivation must be deterministic across restarts")
+	}
+	if derived == "jwt-secret" || len(derived) != 64 {
+		t.Fatalf("unexpected derived secret %q", derived)
+	}
+	other, _ := bootstrap.DeriveSignSecret("other-secret")
+	if derived == other {
+		t.Fatal("different jwt secrets must derive different keys")
+	}
+
+	// JWT 密钥签出的链接不能通过派生密钥的校验
+	expires := time.Now().Add(time.Minute).Unix()
+	signature := storage.NewURLSigner("jwt-secret").Sign("private/a.pdf", expires)
+	if err := storage.NewURLSigner(derived).Verify("private/a.pdf", expires, signature, time.Now()); err == nil {
+		t.Fatal("jwt secret signature accepted by derived signer")
+	}
+
+	if _, err := bootstrap.DeriveSignSecret(""); err == nil {
+		t.Fatal("expected error without any secret")
+	}
+}
@@ -0,0 +1,253 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+	"github.com/javapub/mini-study/mini-study-backend/internal/storage"
+)
+
+// newUploadSessionService builds a service with 1MB chunks backed by temporary directories.
+func newUploadSessionService(t *testing.T, db *gorm.DB) (*service.UploadSessionService, string) {
+	t.Helper()
+	userRepo := repository.NewUserRepository(db)
+	audit := service.NewAuditService(repository.NewAuditRepository(db), userRepo)
+	signer := storage.NewURLSigner("test-secret")
+	store := storage.NewLocalStorage(t.TempDir(), t.TempDir(), "/uploads", "/api/v1/files/download", signer)
+	files := service.NewFileService(store, signer, audit, 10, time.Minute)
+	chunkDir := t.TempDir()
+	return service.NewUploadSessionService(repository.NewUploadSessionRepository(db), files, chunkDir, 1, 10, time.Hour), chunkDir
+}
+
+// chunkHeader wraps data in a multipart file header as the handler would receive it.
+func chunkHeader(t *testing.T, data []byte) *multipart.FileHeader {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("chunk", "chunk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(int64(len(data)) + 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = form.RemoveAll() })
+	return form.File["chunk"][0]
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func uploadSessionDocument() []byte {
+	return append([]byte("%PDF-1.4\n"), bytes.Repeat([]byte("x"), 4000)...)
+}
+
+func TestUploadSessionResumeAndComplete(t *testing.T) {
+	db := newTestDB(t)
+	svc, _ := newUploadSessionService(t, db)
+	user := createUser(t, db, model.RoleAdmin, "A1")
+	data := uploadSessionDocument()
+
+	session, err := svc.Init(user.ID, dto.InitUploadSessionRequest{Filename: "manual.pdf", Size: int64(len(data)), SHA256: checksum(data), Purpose: service.UploadPurposeDocument})
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	first, rest := data[:1000], data[1000:]
+	if _, err := svc.UploadChunk(user.ID, session.UploadID, dto.UploadChunkRequest{Offset: 0, Checksum: checksum(first)}, chunkHeader(t, first)); err != nil {
+		t.Fatalf("first chunk: %v", err)
+	}
+
+	// 重传已确认的分片会因偏移不一致被拒绝，客户端应从 received_bytes 续传
+	if _, err := svc.UploadChunk(user.ID, session.UploadID, dto.UploadChunkRequest{Offset: 0}, chunkHeader(t, first)); !errors.Is(err, service.ErrUploadOffsetMismatch) {
+		t.Fatalf("replayed chunk: %v", err)
+	}
+	// 校验和错误的分片被丢弃，偏移不变
+	if _, err := svc.UploadChunk(user.ID, session.UploadID, dto.UploadChunkRequest{Offset: 1000, Checksum: checksum(first)}, chunkHeader(t, rest)); err == nil {
+		t.Fatal("expected checksum mismatch")
+	}
+	state, err := svc.Get(user.ID, session.UploadID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if state.ReceivedBytes != 1000 {
+		t.Fatalf("received_bytes = %d, want 1000", state.ReceivedBytes)
+	}
+
+	if _, err := svc.Complete(context.Background(), user.ID, session.UploadID); err == nil {
+		t.Fatal("completed an incomplete upload")
+	}
+	if _, err := svc.UploadChunk(user.ID, session.UploadID, dto.UploadChunkRequest{Offset: state.ReceivedBytes, Checksum: checksum(rest)}, chunkHeader(t, rest)); err != nil {
+		t.Fatalf("resumed chunk: %v", err)
+	}
+
+	resp, err := svc.Complete(context.Background(), user.ID, session.UploadID)
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	again, err := svc.Complete(context.Background(), user.ID, session.UploadID)
+	if err != nil {
+		t.Fatalf("complete retry: %v", err)
+	}
+	if again.Key != resp.Key {
+		t.Fatalf("retry returned %s, want %s", again.Key, resp.Key)
+	}
+	if _, err := svc.Get(user.ID+1, session.UploadID); err == nil {
+		t.Fatal("another user can see the session")
+	}
+}
+
+func TestUploadSessionExpiry(t *testing.T) {
+	db := newTestDB(t)
+	svc, chunkDir := newUploadSessionService(t, db)
+	user := createUser(t, db, model.RoleAdmin, "A1")
+	data := uploadSessionDocument()
+
+	expired, err := svc.Init(user.ID, dto.InitUploadSessionRequest{Filename: "old.pdf", Size: int64(len(data))})
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if _, err := svc.UploadChunk(user.ID, expired.UploadID, dto.UploadChunkRequest{}, chunkHeader(t, data)); err != nil {
+		t.Fatalf("chunk: %v", err)
+	}
+	active, err := svc.Init(user.ID, dto.InitUploadSessionRequest{Filename: "new.pdf", Size: int64(len(data))})
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := db.Model(&model.UploadSession{}).Where("upload_id = ?", expired.UploadID).Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 清理任务尚未运行时，过期会话也不能完成
+	if _, err := svc.Complete(context.Background(), user.ID, expired.UploadID); err == nil {
+		t.Fatal("completed an expired session")
+	}
+
+	cleaned, err := svc.CleanupExpired(time.Now())
+	if err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	if cleaned != 1 {
+		t.Fatalf("cleaned %d sessions, want 1", cleaned)
+	}
+	if _, err := os.Stat(filepath.Join(chunkDir, expired.UploadID+".part")); !os.IsNotExist(err) {
+		t.Fatalf("staging file not removed: %v", err)
+	}
+	state, err := svc.Get(user.ID, expired.UploadID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if state.Status != model.UploadSessionExpired {
+		t.Fatalf("status = %s", state.Status)
+	}
+	if state, _ := svc.Get(user.ID, active.UploadID); state.Status != model.UploadSessionUploading {
+		t.Fatalf("active session status = %s", state.Status)
+	}
+
+	// 已清理的会话不会重复计数，未过期的会话即使被选中也不会被标记
+	if cleaned, err := svc.CleanupExpired(time.Now()); err != nil || cleaned != 0 {
+		t.Fatalf("second cleanup: cleaned=%d err=%v", cleaned, err)
+	}
+	var activeRow model.UploadSession
+	if err := db.Where("upload_id = ?", active.UploadID).First(&activeRow).Error; err != nil {
+		t.Fatal(err)
+	}
+	repo := repository.NewUploadSessionRepository(db)
+	if claimed, err := repo.Claim(activeRow.ID, "cleanup", time.Now(), time.Now().Add(time.Minute)); err != nil || !claimed {
+		t.Fatalf("claim active session: claimed=%v err=%v", claimed, err)
+	}
+	if marked, err := repo.MarkExpired(activeRow.ID, "cleanup", time.Now()); err != nil || marked {
+		t.Fatalf("renewed session marked expired: marked=%v err=%v", marked, err)
+	}
+	if _, err := svc.Complete(context.Background(), user.ID, expired.UploadID); err == nil {
+		t.Fatal("completed a cleaned up session")
+	}
+}
+
+func TestUploadSessionClaims(t *testing.T) {
+	db := newTestDB(t)
+	svc, _ := newUploadSessionService(t, db)
+	repo := repository.NewUploadSessionRepository(db)
+	user := createUser(t, db, model.RoleAdmin, "A1")
+	data := uploadSessionDocument()
+
+	session, err := svc.Init(user.ID, dto.InitUploadSessionRequest{Filename: "doc.pdf", Size: int64(len(data))})
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	var row model.UploadSession
+	if err := db.Where("upload_id = ?", session.UploadID).First(&row).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 其他实例持有认领时，分片、取消与过期清理都不会处理该会话
+	if claimed, err := repo.Claim(row.ID, "other-replica", time.Now(), time.Now().Add(time.Minute)); err != nil || !claimed {
+		t.Fatalf("claim: claimed=%v err=%v", claimed, err)
+	}
+	if _, err := svc.UploadChunk(user.ID, session.UploadID, dto.UploadChunkRequest{}, chunkHeader(t, data)); !errors.Is(err, service.ErrUploadSessionBusy) {
+		t.Fatalf("chunk while claimed: %v", err)
+	}
+	if err := svc.Abort(user.ID, session.UploadID); !errors.Is(err, service.ErrUploadSessionBusy) {
+		t.Fatalf("abort while claimed: %v", err)
+	}
+	if err := db.Model(&row).Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if cleaned, err := svc.CleanupExpired(time.Now()); err != nil || cleaned != 0 {
+		t.Fatalf("cleanup while claimed: cleaned=%d err=%v", cleaned, err)
+	}
+
+	// 认领过期后可被接管；接管后原持有者的状态变更不再生效
+	if err := db.Model(&row).Updates(map[string]interface{}{"expires_at": time.Now().Add(time.Hour), "claimed_until": time.Now().Add(-time.Second)}).Error; err != nil {
+		t.Fatal(err)
+	}
+	resp, err := svc.UploadChunk(user.ID, session.UploadID, dto.UploadChunkRequest{}, chunkHeader(t, data))
+	if err != nil {
+		t.Fatalf("chunk after the claim expired: %v", err)
+	}
+	if resp.ReceivedBytes != int64(len(data)) {
+		t.Fatalf("received %d bytes", resp.ReceivedBytes)
+	}
+	if advanced, err := repo.AdvanceOffset(row.ID, "other-replica", resp.ReceivedBytes, 0, time.Now().Add(time.Hour)); err != nil || advanced {
+		t.Fatalf("stale holder advanced the offset: advanced=%v err=%v", advanced, err)
+	}
+	if completed, err := repo.MarkCompleted(row.ID, "other-replica", "stale", time.Now()); err != nil || completed {
+		t.Fatalf("stale holder completed the session: completed=%v err=%v", completed, err)
+	}
+	var released model.UploadSession
+	if err := db.First(&released, row.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if released.ClaimToken != "" || released.ClaimedUntil != nil {
+		t.Fatalf("claim not released: %q %v", released.ClaimToken, released.ClaimedUntil)
+	}
+
+	if _, err := svc.Complete(context.Background(), user.ID, session.UploadID); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if _, err := svc.Complete(context.Background(), user.ID, session.UploadID); err != nil {
+		t.Fatalf("repeat complete: %v", err)
+	}
+}
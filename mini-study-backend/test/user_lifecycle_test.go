@@ -0,0 +1,189 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/javapub/mini-study/mini-study-backend/internal/dto"
+	"github.com/javapub/mini-study/mini-study-backend/internal/model"
+	"github.com/javapub/mini-study/mini-study-backend/internal/repository"
+	"github.com/javapub/mini-study/mini-study-backend/internal/service"
+)
+
+func newUserService(db *gorm.DB) *service.UserService {
+	userRepo := repository.NewUserRepository(db)
+	audit := service.NewAuditService(repository.NewAuditRepository(db), userRepo)
+	points := service.NewPointService(repository.NewPointRepository(db), userRepo, audit)
+	return service.NewUserService(userRepo, repository.NewManagerEmployeeRepository(db), repository.NewEmployeeTransferRepository(db), audit, points)
+}
+
+func countRows(t *testing.T, db *gorm.DB, value interface{}, query string, args ...interface{}) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Model(value).Where(query, args...).Count(&n).Error; err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	return n
+}
+
+func TestDeactivateUser(t *testing.T) {
+	db := newTestDB(t)
+	svc := newUserService(db)
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	manager := createUser(t, db, model.RoleManager, "M1")
+	employee := createUser(t, db, model.RoleEmployee, "E1")
+	bindManager(t, db, manager.ID, employee.ID)
+
+	user, err := svc.DeactivateUser(context.Background(), admin.ID, employee.ID, "休假")
+	if err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if user.Status || user.TokenVersion != 1 {
+		t.Fatalf("status=%v token_version=%d", user.Status, user.TokenVersion)
+	}
+	if n := countRows(t, db, &model.ManagerEmployee{}, "employee_id = ?", employee.ID); n != 0 {
+		t.Fatalf("relations left: %d", n)
+	}
+	if n := countRows(t, db, &model.AuditLog{}, "action = ? AND entity_id = ?", "deactivate_user", employee.ID); n != 1 {
+		t.Fatalf("audit entries: %d", n)
+	}
+	if _, err := svc.DeactivateUser(context.Background(), admin.ID, employee.ID, ""); err == nil {
+		t.Fatal("expected error deactivating twice")
+	}
+}
+
+func TestDeactivateUserRollsBack(t *testing.T) {
+	db := newTestDB(t)
+	svc := newUserService(db)
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	manager := createUser(t, db, model.RoleManager, "M1")
+	employee := createUser(t, db, model.RoleEmployee, "E1")
+	bindManager(t, db, manager.ID, employee.ID)
+
+	// 审计日志写入失败时，状态与店长关系都不应改变
+	if err := db.Migrator().DropTable(&model.AuditLog{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.DeactivateUser(context.Background(), admin.ID, employee.ID, ""); err == nil {
+		t.Fatal("expected error when the audit log cannot be written")
+	}
+
+	var stored model.User
+	if err := db.First(&stored, employee.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !stored.Status || stored.TokenVersion != 0 {
+		t.Fatalf("user changed: status=%v token_version=%d", stored.Status, stored.TokenVersion)
+	}
+	if n := countRows(t, db, &model.ManagerEmployee{}, "employee_id = ?", employee.ID); n != 1 {
+		t.Fatalf("relations changed: %d", n)
+	}
+}
+
+func TestTransferEmployee(t *testing.T) {
+	db := newTestDB(t)
+	svc := newUserService(db)
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	from := createUser(t, db, model.RoleManager, "M1")
+	to := createUser(t, db, model.RoleManager, "M2")
+	employee := createUser(t, db, model.RoleEmployee, "E1")
+	bindManager(t, db, from.ID, employee.ID)
+
+	resp, err := svc.TransferEmployee(context.Background(), admin.ID, employee.ID, dto.AdminTransferEmployeeRequest{ManagerWorkNos: []string{"M2"}, Reason: "门店调整"})
+	if err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	if resp.Reason != "门店调整" {
+		t.Fatalf("reason = %q", resp.Reason)
+	}
+	if n := countRows(t, db, &model.ManagerEmployee{}, "employee_id = ? AND manager_id = ?", employee.ID, to.ID); n != 1 {
+		t.Fatal("not bound to the new manager")
+	}
+	if n := countRows(t, db, &model.ManagerEmployee{}, "employee_id = ? AND manager_id = ?", employee.ID, from.ID); n != 0 {
+		t.Fatal("still bound to the old manager")
+	}
+	if n := countRows(t, db, &model.EmployeeTransfer{}, "employee_id = ?", employee.ID); n != 1 {
+		t.Fatalf("transfer records: %d", n)
+	}
+
+	if err := db.Migrator().DropTable(&model.AuditLog{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.TransferEmployee(context.Background(), admin.ID, employee.ID, dto.AdminTransferEmployeeRequest{ManagerWorkNos: []string{"M1"}}); err == nil {
+		t.Fatal("expected error when the audit log cannot be written")
+	}
+	if n := countRows(t, db, &model.ManagerEmployee{}, "employee_id = ? AND manager_id = ?", employee.ID, to.ID); n != 1 {
+		t.Fatal("failed transfer changed relations")
+	}
+	if n := countRows(t, db, &model.EmployeeTransfer{}, "employee_id = ?", employee.ID); n != 1 {
+		t.Fatal("failed transfer left a record")
+	}
+}
+
+func TestUpdateEmployeeManagersRecordsTransfer(t *testing.T) {
+	db := newTestDB(t)
+	svc := newUserService(db)
+	ctx := context.Background()
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	from := createUser(t, db, model.RoleManager, "M1")
+	to := createUser(t, db, model.RoleManager, "M2")
+	employee := createUser(t, db, model.RoleEmployee, "E1")
+	bindManager(t, db, from.ID, employee.ID)
+
+	if _, err := svc.UpdateEmployeeManagers(ctx, admin.ID, employee.ID, []string{"M2"}); err != nil {
+		t.Fatalf("update managers: %v", err)
+	}
+	if n := countRows(t, db, &model.ManagerEmployee{}, "employee_id = ? AND manager_id = ?", employee.ID, to.ID); n != 1 {
+		t.Fatal("not bound to the new manager")
+	}
+	var transfer model.EmployeeTransfer
+	if err := db.Where("employee_id = ?", employee.ID).First(&transfer).Error; err != nil {
+		t.Fatalf("transfer record: %v", err)
+	}
+	if transfer.OperatorID != admin.ID || transfer.FromManagerIDs != fmt.Sprintf("[%d]", from.ID) || transfer.ToManagerIDs != fmt.Sprintf("[%d]", to.ID) {
+		t.Fatalf("transfer: %+v", transfer)
+	}
+
+	// 已禁用的用户不能重新绑定店长
+	if _, err := svc.DeactivateUser(ctx, admin.ID, employee.ID, "停用"); err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if _, err := svc.UpdateEmployeeManagers(ctx, admin.ID, employee.ID, []string{"M1"}); err == nil {
+		t.Fatal("rebound a deactivated user")
+	}
+	if n := countRows(t, db, &model.ManagerEmployee{}, "employee_id = ?", employee.ID); n != 0 {
+		t.Fatalf("deactivated user has %d relations", n)
+	}
+	if n := countRows(t, db, &model.EmployeeTransfer{}, "employee_id = ?", employee.ID); n != 1 {
+		t.Fatalf("transfer records: %d", n)
+	}
+}
+
+func TestOffboardUser(t *testing.T) {
+	db := newTestDB(t)
+	svc := newUserService(db)
+	admin := createUser(t, db, model.RoleAdmin, "A1")
+	manager := createUser(t, db, model.RoleManager, "M1")
+	employee := createUser(t, db, model.RoleEmployee, "E1")
+	bindManager(t, db, manager.ID, employee.ID)
+
+	user, err := svc.OffboardUser(context.Background(), admin.ID, employee.ID, "离职")
+	if err != nil {
+		t.Fatalf("offboard: %v", err)
+	}
+	if user.OffboardedAt == nil || user.Status || user.Name != "已离职员工" || user.Phone != "" {
+		t.Fatalf("user not anonymized: %+v", user)
+	}
+	if n := countRows(t, db, &model.ManagerEmployee{}, "employee_id = ?", employee.ID); n != 0 {
+		t.Fatalf("relations left: %d", n)
+	}
+	if _, err := svc.ReactivateUser(context.Background(), admin.ID, employee.ID, ""); err == nil {
+		t.Fatal("offboarded user reactivated")
+	}
+	if _, err := svc.OffboardUser(context.Background(), admin.ID, admin.ID, ""); err == nil {
+		t.Fatal("admin offboarded themselves")
+	}
+}